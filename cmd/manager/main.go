@@ -38,7 +38,10 @@ import (
 	"github.com/planetscale/operator-sdk-libs/pkg/k8sutil"
 	"github.com/planetscale/operator-sdk-libs/pkg/leader"
 
+	"planetscale.dev/vitess-operator/pkg/operator/backupreplicator"
+	"planetscale.dev/vitess-operator/pkg/operator/backupverifier"
 	"planetscale.dev/vitess-operator/pkg/operator/controllermanager"
+	"planetscale.dev/vitess-operator/pkg/operator/etcdbackup"
 	"planetscale.dev/vitess-operator/pkg/operator/fork"
 	"planetscale.dev/vitess-operator/version"
 )
@@ -69,6 +72,35 @@ func main() {
 
 	printVersion()
 
+	// The backup verifier, backup replicator and etcd snapshot tools aren't
+	// controllers, so they don't need a manager.
+	switch forkPath {
+	case backupverifier.ForkPath:
+		if err := backupverifier.Run(context.TODO()); err != nil {
+			log.Error(err, "Backup verification failed")
+			os.Exit(1)
+		}
+		return
+	case backupreplicator.ForkPath:
+		if err := backupreplicator.Run(context.TODO()); err != nil {
+			log.Error(err, "Backup replication failed")
+			os.Exit(1)
+		}
+		return
+	case etcdbackup.SnapshotForkPath:
+		if err := etcdbackup.RunSnapshot(context.TODO()); err != nil {
+			log.Error(err, "Etcd snapshot failed")
+			os.Exit(1)
+		}
+		return
+	case etcdbackup.RestoreForkPath:
+		if err := etcdbackup.RunRestore(context.TODO()); err != nil {
+			log.Error(err, "Etcd restore failed")
+			os.Exit(1)
+		}
+		return
+	}
+
 	namespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		log.Error(err, "Failed to get watch namespace")
@@ -16,6 +16,10 @@ limitations under the License.
 
 package v2
 
+import (
+	"time"
+)
+
 /*
 All hard-coded default values for configurable aspects of objects in our API should go here.
 However, hard-coded values that are not (yet) configurable in the API can live with the code.
@@ -62,6 +66,8 @@ const (
 	defaultEtcdCreatePDB           = true
 	defaultEtcdCreateClientService = true
 	defaultEtcdCreatePeerService   = true
+	defaultEtcdSnapshotSchedule    = "0 * * * *"
+	defaultEtcdSnapshotsToKeep     = 24
 
 	defaultVtctldReplicas    = 1
 	defaultVtctldCPUMillis   = 100
@@ -71,7 +77,7 @@ const (
 	defaultVtadminCPUMillis   = 100
 	defaultVtadminMemoryBytes = 128 * Mi
 
-	defaultVtorcReplicas    = 0
+	defaultVtorcReplicas    = 1
 	defaultVtorcCPUMillis   = 100
 	defaultVtorcMemoryBytes = 128 * Mi
 
@@ -79,11 +85,99 @@ const (
 	defaultVtgateCPUMillis   = 500
 	defaultVtgateMemoryBytes = 1 * Gi
 
+	defaultVtgateBlueGreenStepPercent     = 25
+	defaultVtgateBlueGreenStepInterval    = 2 * time.Minute
+	defaultVtgateBlueGreenMaxErrorPercent = 5
+	defaultVtgateBlueGreenMinQueries      = 100
+
+	defaultVtgateAutoscalerTargetCPUUtilization = 80
+	defaultVtgateAutoscalerStabilizationWindow  = 5 * time.Minute
+	defaultVtgateAutoscalerDrainTimeout         = time.Minute
+	defaultVtgateQueriesPerSecondMetricName     = "vtgate_queries_per_second"
+	defaultVtgateConnectionsMetricName          = "vtgate_mysql_server_conn_count"
+
+	defaultVtgateLDAPRefreshInterval = time.Minute
+
+	defaultVtgateServingGateMinHealthyShardPercent = 100
+	defaultVtgateServingGateMaxWait                = 10 * time.Minute
+
 	defaultBackupIntervalHours     = 24
 	defaultBackupMinRetentionHours = 72
 	defaultBackupMinRetentionCount = 1
 	defaultBackupEngine            = VitessBackupEngineBuiltIn
 
+	defaultDrainMaxUnavailable = 1
+
+	defaultRdonlyPoolDrainOrder = 0
+	defaultPoolDrainOrder       = 10
+
+	defaultPlannedReparentTimeout  = 30 * time.Second
+	defaultReconcileDrainTimeout   = 60 * time.Second
+	defaultTolerableReplicationLag = 15 * time.Second
+
+	defaultAllowEmergencyFailover       = false
+	defaultEmergencyFailoverGracePeriod = 5 * time.Minute
+
+	defaultDrainAbortGracePeriod = 10 * time.Minute
+	defaultDrainTimeout          = time.Hour
+
+	defaultPrimaryAffinityStableFor = 5 * time.Minute
+
+	defaultCanaryReplicas   = 1
+	defaultCanarySoakPeriod = 10 * time.Minute
+
+	defaultUpgradeCheckMaxBackupAge       = 24 * time.Hour
+	defaultUpgradeCheckMaxReplicationLag  = 30 * time.Second
+	defaultUpgradeCheckMinHealthyReplicas = 1
+
+	defaultRevisionHistoryLimit = 10
+
+	defaultStandbyRefreshInterval = 24 * time.Hour
+
+	defaultZoneSpreadMaxSkew = 1
+
+	defaultExternalFailoverCheckInterval = 30 * time.Second
+	defaultDemotePrimaryToSpare          = true
+
+	defaultQueryServingPoolSizePerCPU       = 16
+	defaultQueryServingMinPoolSize          = 16
+	defaultQueryServingMaxPoolSize          = 96
+	defaultQueryServingTransactionCapPerCPU = 50
+	defaultQueryServingMinTransactionCap    = 50
+	defaultQueryServingMaxTransactionCap    = 300
+	defaultQueryServingQueryTimeout         = 15 * time.Minute
+	defaultQueryServingMaxResultSize        = 100000
+	defaultHotRowProtectionMode             = "enable"
+
+	defaultSlowQueryLogLongQueryTime = time.Second
+	defaultQueryLogMaxSize           = "100Mi"
+	defaultQueryLogMaxFiles          = 5
+
+	defaultAuditLogFormat       = "JSON"
+	defaultAuditLogPolicy       = "ALL"
+	defaultAuditLogRotateOnSize = "100Mi"
+	defaultAuditLogRotations    = 5
+
+	defaultMysqlUserHost      = "%"
+	defaultMysqlGrantDatabase = "*"
+	defaultMysqlGrantTable    = "*"
+
+	defaultTopoConsistencyCheckInterval = 10 * time.Minute
+
+	defaultCrashLoopRestartThreshold = 3
+	defaultCrashLoopInitialDelay     = time.Minute
+	defaultCrashLoopMaxDelay         = 30 * time.Minute
+
+	defaultVReplicationMaxReplicationLag = 30 * time.Second
+	defaultMoveTablesMaxRetries          = 5
+
+	defaultAutoReshardSustainedFor = time.Hour
+
+	defaultDecommissionHoldPeriod = 24 * time.Hour
+
+	defaultSequenceStart = 1
+	defaultSequenceCache = 1000
+
 	// DefaultWebPort is the port for debug status pages and dashboard UIs.
 	DefaultWebPort = 15000
 	// DefaultAPIPort is the port for API endpoint.
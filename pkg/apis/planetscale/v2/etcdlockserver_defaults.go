@@ -67,4 +67,17 @@ func DefaultEtcdLockserverTemplate(ls *EtcdLockserverTemplate) {
 	}
 	DefaultServiceOverrides(&ls.ClientService)
 	DefaultServiceOverrides(&ls.PeerService)
+	DefaultEtcdLockserverBackup(ls.Backup)
+}
+
+func DefaultEtcdLockserverBackup(backup *EtcdLockserverBackup) {
+	if backup == nil {
+		return
+	}
+	if backup.Schedule == "" {
+		backup.Schedule = defaultEtcdSnapshotSchedule
+	}
+	if backup.SnapshotsToKeep == nil {
+		backup.SnapshotsToKeep = pointer.Int32Ptr(defaultEtcdSnapshotsToKeep)
+	}
 }
@@ -197,6 +197,54 @@ type EtcdLockserverTemplate struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Backup configures scheduled snapshots of the etcd data to object storage.
+	// Default: Don't take snapshots.
+	Backup *EtcdLockserverBackup `json:"backup,omitempty"`
+
+	// Restore seeds new etcd members from a snapshot taken by Backup.
+	//
+	// The snapshot is only restored when the data volumes of all members are
+	// created together, such as for a new EtcdLockserver, or after deleting
+	// the PVCs of all members. A member that's replaced on its own rejoins the
+	// existing members as usual instead.
+	//
+	// WARNING: Restoring a snapshot resets the topology to the time it was
+	// taken. Tablets register themselves again when they restart, but
+	// reparents, resharding and VSchema changes since then are lost and may
+	// need to be repeated by hand.
+	Restore *EtcdLockserverRestore `json:"restore,omitempty"`
+}
+
+// EtcdLockserverBackup configures scheduled snapshots of an etcd cluster.
+type EtcdLockserverBackup struct {
+	// Location is where to store snapshots. It's configured the same way as
+	// a Vitess backup location, and can point at the same place.
+	Location VitessBackupLocation `json:"location"`
+
+	// Schedule is when to take snapshots, in cron format.
+	// Default: "0 * * * *" (hourly)
+	Schedule string `json:"schedule,omitempty"`
+
+	// SnapshotsToKeep is how many of the most recent snapshots to keep.
+	// Older ones are deleted after each new snapshot.
+	// Default: 24
+	// +kubebuilder:validation:Minimum=1
+	SnapshotsToKeep *int32 `json:"snapshotsToKeep,omitempty"`
+}
+
+// EtcdLockserverRestore specifies a snapshot to seed new etcd members from.
+type EtcdLockserverRestore struct {
+	// Location is where the snapshot is stored.
+	Location VitessBackupLocation `json:"location"`
+
+	// Directory is the directory of the snapshot within the location, as
+	// reported in the backup status of the EtcdLockserver that took it.
+	Directory string `json:"directory"`
+
+	// Name is the name of the snapshot within the directory, as reported in
+	// the backup status of the EtcdLockserver that took it.
+	Name string `json:"name"`
 }
 
 // EtcdLockserverStatus defines the observed state of an EtcdLockserver.
@@ -207,6 +255,22 @@ type EtcdLockserverStatus struct {
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ClientServiceName is the name of the Service for etcd client connections.
 	ClientServiceName string `json:"clientServiceName,omitempty"`
+	// Backup is the progress of scheduled snapshots, if they're enabled.
+	Backup *EtcdLockserverBackupStatus `json:"backup,omitempty"`
+}
+
+// EtcdLockserverBackupStatus is the progress of scheduled etcd snapshots.
+type EtcdLockserverBackupStatus struct {
+	// LastScheduleTime is the scheduled time of the last snapshot that was taken.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSnapshotDirectory is the directory of the last snapshot within the
+	// backup location.
+	LastSnapshotDirectory string `json:"lastSnapshotDirectory,omitempty"`
+	// LastSnapshotName is the name of the last snapshot within its directory.
+	LastSnapshotName string `json:"lastSnapshotName,omitempty"`
+	// FailureMessage explains why the latest attempt to take a snapshot
+	// failed, if it did.
+	FailureMessage string `json:"failureMessage,omitempty"`
 }
 
 // NewEtcdLockserverStatus returns a new status with default values.
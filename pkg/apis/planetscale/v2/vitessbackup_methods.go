@@ -0,0 +1,85 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetConditionStatus ensures we have a condition of the supplied type, and then sets its status.
+// For the condition's status, it always updates the reason and message every time. If the current status is the same as the supplied
+// newStatus, then we do not update LastTransitionTime. However, if newStatus is different from current status, then
+// we update the status and update the transition time.
+func (s *VitessBackupStatus) SetConditionStatus(condType VitessBackupConditionType, newStatus corev1.ConditionStatus, reason, message string) {
+	cond, ok := s.getCondition(condType)
+	if !ok {
+		now := metav1.NewTime(time.Now())
+		cond = &VitessBackupCondition{
+			Type:               condType,
+			Status:             corev1.ConditionUnknown,
+			LastTransitionTime: &now,
+		}
+	}
+
+	// We should update reason and message regardless of whether the status type is different.
+	cond.Reason = reason
+	cond.Message = message
+
+	if cond.Status != newStatus {
+		now := metav1.NewTime(time.Now())
+		cond.Status = newStatus
+		cond.LastTransitionTime = &now
+	}
+
+	s.setCondition(cond)
+}
+
+// GetCondition provides map style access to retrieve a condition from the conditions list by it's type
+// If the condition doesn't exist, we return false for the exists named return value.
+func (s *VitessBackupStatus) GetCondition(ty VitessBackupConditionType) (value VitessBackupCondition, exists bool) {
+	cond, exists := s.getCondition(ty)
+	if !exists {
+		return VitessBackupCondition{}, false
+	}
+	return *cond.DeepCopy(), true
+}
+
+// getCondition is used internally for map style access, and returns a pointer to reduce unnecessary copying.
+func (s *VitessBackupStatus) getCondition(ty VitessBackupConditionType) (value *VitessBackupCondition, exists bool) {
+	for i := range s.Conditions {
+		condition := &s.Conditions[i]
+		if condition.Type == ty {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+// setCondition is used internally to provide map style setting of conditions, and will ensure uniqueness by using
+// upsert semantics.
+func (s *VitessBackupStatus) setCondition(newCondition *VitessBackupCondition) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == newCondition.Type {
+			s.Conditions[i] = *newCondition
+			return
+		}
+	}
+	s.Conditions = append(s.Conditions, *newCondition)
+}
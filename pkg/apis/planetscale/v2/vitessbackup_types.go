@@ -17,6 +17,7 @@ limitations under the License.
 package v2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -32,6 +33,8 @@ import (
 // These objects are created automatically by the VitessBackupStorage controller
 // to provide access to backup metadata from Kubernetes. Each backup found in
 // the storage location will be represented by its own VitessBackup object.
+// If backup verification is enabled, the VitessShard controller also records
+// the outcome of verifying the backup in its conditions.
 // +kubebuilder:resource:path=vitessbackups,shortName=vtb
 type VitessBackup struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -60,6 +63,15 @@ type VitessBackupStatus struct {
 	Position string `json:"position,omitempty"`
 	// Engine is the Vitess backup engine implementation that was used.
 	Engine string `json:"engine,omitempty"`
+	// Duration is how long the backup took, from when it started until it
+	// finished. This is only available after the backup is complete.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// SourceTablet is the alias of the tablet that took the backup.
+	// This is only available after the backup is complete.
+	SourceTablet string `json:"sourceTablet,omitempty"`
+	// MySQLVersion is the version of MySQL that the backup was taken from.
+	// This is only available after the backup is complete.
+	MySQLVersion string `json:"mysqlVersion,omitempty"`
 	// StorageDirectory is the name of the parent directory in storage that
 	// contains this backup.
 	StorageDirectory string `json:"storageDirectory,omitempty"`
@@ -67,8 +79,46 @@ type VitessBackupStatus struct {
 	// the name of the VitessBackup object created to represent metadata about
 	// the actual backup in storage.
 	StorageName string `json:"storageName,omitempty"`
+	// LastVerificationTime is the time when the last attempt to verify this
+	// backup finished, whether or not it succeeded.
+	LastVerificationTime *metav1.Time `json:"lastVerificationTime,omitempty"`
+	// Conditions is a list of all VitessBackup specific conditions we want to set and monitor.
+	// It's ok for multiple controllers to add conditions here, and those conditions will be preserved.
+	Conditions []VitessBackupCondition `json:"conditions,omitempty"`
+}
+
+// VitessBackupCondition contains details for the current condition of this VitessBackup.
+type VitessBackupCondition struct {
+	// Type is the type of the condition.
+	Type VitessBackupConditionType `json:"type"`
+	// Status is the status of the condition.
+	// Can be True, False, Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// Optional.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Unique, one-word, PascalCase reason for the condition's last transition.
+	// Optional.
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	// Optional.
+	Message string `json:"message,omitempty"`
 }
 
+// VitessBackupConditionType is a valid value for the Type of a VitessBackupCondition.
+type VitessBackupConditionType string
+
+// These are valid conditions of VitessBackup.
+const (
+	// VitessBackupVerified indicates whether the last attempt to restore the backup into a scratch volume, start
+	// mysqld on it, and checksum the configured tables succeeded.
+	VitessBackupVerified VitessBackupConditionType = "Verified"
+	// VitessBackupReplicated indicates whether the backup has been copied to every destination location configured
+	// for backup replication. It's only set on backups in the replication source location.
+	VitessBackupReplicated VitessBackupConditionType = "Replicated"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // VitessBackupList contains a list of VitessBackups.
@@ -33,7 +33,8 @@ import (
 // storage location. One VitessBackupStorage represents a storage location
 // defined at the VitessCluster level, so it provides access to metadata
 // about backups stored in that location for any keyspace and any shard in that
-// cluster.
+// cluster. Locations that a keyspace overrides get their own
+// VitessBackupStorage, which only covers that keyspace.
 // +kubebuilder:resource:path=vitessbackupstorages,shortName=vtbs
 // +kubebuilder:subresource:status
 type VitessBackupStorage struct {
@@ -51,6 +52,36 @@ type VitessBackupStorageSpec struct {
 	Location VitessBackupLocation `json:"location"`
 	// Subcontroller specifies any parameters needed for launching the VitessBackupStorage subcontroller pod.
 	Subcontroller *VitessBackupSubcontrollerSpec `json:"subcontroller,omitempty"`
+	// Retention specifies which backups to delete from the storage location.
+	Retention *VitessBackupRetentionSpec `json:"retention,omitempty"`
+	// Keyspace, if set, limits this storage location to backups of the given
+	// keyspace. This is used for locations that a keyspace overrides.
+	Keyspace string `json:"keyspace,omitempty"`
+	// ExcludedKeyspaces lists keyspaces whose backups should be ignored in
+	// this storage location, because they override it with a location of
+	// their own.
+	ExcludedKeyspaces []string `json:"excludedKeyspaces,omitempty"`
+}
+
+// VitessBackupRetentionSpec specifies how long to keep the backups of each
+// shard. Backups that fall outside the limits are deleted from storage, along
+// with their VitessBackup objects.
+//
+// The latest complete backup of each shard in each location is always kept,
+// regardless of these limits, since new tablets need it to restore from.
+type VitessBackupRetentionSpec struct {
+	// MaxCount is the maximum number of complete backups to keep for each
+	// shard in each location. Older complete backups beyond this count are
+	// deleted.
+	//
+	// Default: No limit on the number of backups.
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int32 `json:"maxCount,omitempty"`
+	// MaxAge is the maximum age of backups to keep. Backups that started
+	// longer ago than this are deleted, including incomplete backups.
+	//
+	// Default: No limit on the age of backups.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
 }
 
 type VitessBackupSubcontrollerSpec struct {
@@ -125,6 +156,8 @@ type S3BackupLocation struct {
 	// +kubebuilder:validation:MinLength=1
 	Bucket string `json:"bucket"`
 	// Endpoint is the `host:port` (port is required) for the S3 backend.
+	// This can be used to store backups in any S3-compatible service, such as
+	// MinIO or Ceph RGW, in which case you'll usually also want forcePathStyle.
 	// Default: Use the endpoint associated with `region` by the driver.
 	Endpoint string `json:"endpoint,omitempty"`
 	// ForcePathStyle is an optional param to force connection using <endpoint>/<bucket>
@@ -143,6 +176,16 @@ type S3BackupLocation struct {
 	// `~/.aws/credentials` file.
 	// Default: Use the default credentials of the Node.
 	AuthSecret *SecretSource `json:"authSecret,omitempty"`
+	// CASecret is a reference to the Secret containing the PEM-encoded
+	// certificate of a custom certificate authority to trust when connecting
+	// to the S3 backend, in addition to the system's trusted authorities.
+	// This is usually only needed for self-hosted S3-compatible services.
+	// The certificate is only trusted by programs written in Go, such as Vitess
+	// itself, since it's added through the SSL_CERT_DIR environment variable.
+	// Backup engines or tools that connect to S3 on their own with a different
+	// TLS library, rather than through Vitess, don't trust it.
+	// Default: Only trust the system's certificate authorities.
+	CASecret *SecretSource `json:"caSecret,omitempty"`
 }
 
 // AzblobBackupLocation specifies a backup location in Azure Blob Storage.
@@ -19,6 +19,7 @@ package v2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -57,6 +58,93 @@ func DefaultVitessGateway(gtway *VitessCellGatewaySpec) {
 		}
 	}
 	DefaultServiceOverrides(&gtway.Service)
+	if strategy := gtway.RolloutStrategy; strategy != nil {
+		if strategy.Type == "" {
+			strategy.Type = RollingUpdateGatewayRolloutStrategyType
+		}
+		if strategy.Type == BlueGreenGatewayRolloutStrategyType {
+			if strategy.BlueGreen == nil {
+				strategy.BlueGreen = &VitessGatewayBlueGreenStrategy{}
+			}
+			DefaultVitessGatewayBlueGreenStrategy(strategy.BlueGreen)
+		}
+	}
+	if gtway.Autoscaler != nil {
+		DefaultVitessGatewayAutoscaler(gtway.Autoscaler)
+	}
+	if gtway.ServingGate != nil {
+		DefaultVitessGatewayServingGate(gtway.ServingGate)
+	}
+	DefaultVitessGatewayAuthentication(&gtway.Authentication)
+	if gtway.ZoneSpread != nil {
+		DefaultVitessZoneSpread(gtway.ZoneSpread)
+	}
+}
+
+// DefaultVitessGatewayAuthentication fills in default values for vtgate
+// authentication.
+func DefaultVitessGatewayAuthentication(auth *VitessGatewayAuthentication) {
+	if ldap := auth.LDAP; ldap != nil {
+		if ldap.RefreshInterval == nil {
+			ldap.RefreshInterval = &metav1.Duration{Duration: defaultVtgateLDAPRefreshInterval}
+		}
+		if ldap.AuthMethod == "" {
+			ldap.AuthMethod = ClearPasswordGatewayAuthMethod
+		}
+	}
+	if clientCert := auth.ClientCert; clientCert != nil && clientCert.AuthMethod == "" {
+		clientCert.AuthMethod = ClearPasswordGatewayAuthMethod
+	}
+}
+
+// DefaultVitessGatewayServingGate fills in default values for the vtgate
+// readiness gate.
+func DefaultVitessGatewayServingGate(gate *VitessGatewayServingGate) {
+	if gate.MinHealthyShardPercent == nil {
+		gate.MinHealthyShardPercent = pointer.Int32Ptr(defaultVtgateServingGateMinHealthyShardPercent)
+	}
+	if gate.MaxWait == nil {
+		gate.MaxWait = &metav1.Duration{Duration: defaultVtgateServingGateMaxWait}
+	}
+}
+
+// DefaultVitessGatewayAutoscaler fills in default values for autoscaling vtgate.
+func DefaultVitessGatewayAutoscaler(autoscaler *VitessGatewayAutoscaler) {
+	if autoscaler.MinReplicas == nil {
+		autoscaler.MinReplicas = pointer.Int32Ptr(defaultVtgateReplicas)
+	}
+	if autoscaler.TargetCPUUtilization == nil && autoscaler.QueriesPerSecond == nil && autoscaler.Connections == nil && len(autoscaler.Metrics) == 0 {
+		autoscaler.TargetCPUUtilization = pointer.Int32Ptr(defaultVtgateAutoscalerTargetCPUUtilization)
+	}
+	if autoscaler.QueriesPerSecond != nil && autoscaler.QueriesPerSecond.MetricName == "" {
+		autoscaler.QueriesPerSecond.MetricName = defaultVtgateQueriesPerSecondMetricName
+	}
+	if autoscaler.Connections != nil && autoscaler.Connections.MetricName == "" {
+		autoscaler.Connections.MetricName = defaultVtgateConnectionsMetricName
+	}
+	if autoscaler.ScaleDownStabilizationWindow == nil {
+		autoscaler.ScaleDownStabilizationWindow = &metav1.Duration{Duration: defaultVtgateAutoscalerStabilizationWindow}
+	}
+	if autoscaler.DrainTimeout == nil {
+		autoscaler.DrainTimeout = &metav1.Duration{Duration: defaultVtgateAutoscalerDrainTimeout}
+	}
+}
+
+// DefaultVitessGatewayBlueGreenStrategy fills in default values for a
+// blue/green rollout of vtgate.
+func DefaultVitessGatewayBlueGreenStrategy(blueGreen *VitessGatewayBlueGreenStrategy) {
+	if blueGreen.StepPercent == nil {
+		blueGreen.StepPercent = pointer.Int32Ptr(defaultVtgateBlueGreenStepPercent)
+	}
+	if blueGreen.StepInterval == nil {
+		blueGreen.StepInterval = &metav1.Duration{Duration: defaultVtgateBlueGreenStepInterval}
+	}
+	if blueGreen.MaxErrorPercent == nil {
+		blueGreen.MaxErrorPercent = pointer.Int32Ptr(defaultVtgateBlueGreenMaxErrorPercent)
+	}
+	if blueGreen.MinQueries == nil {
+		blueGreen.MinQueries = pointer.Int64Ptr(defaultVtgateBlueGreenMinQueries)
+	}
 }
 
 // DefaultVitessCellImages fills in unspecified keyspace-level images from cluster-level defaults.
@@ -38,6 +38,17 @@ func (s *VitessCellGatewaySpec) ReloadSecretNames() sets.String {
 		}
 	}
 
+	if ldap := s.Authentication.LDAP; ldap != nil {
+		// The LDAP config file that the operator renders for vtgate
+		// includes the bind password.
+		if ldap.BindPasswordSecret != nil && ldap.BindPasswordSecret.Name != "" {
+			secretNames.Insert(ldap.BindPasswordSecret.Name)
+		}
+		if ldap.CACertSecret != nil && ldap.CACertSecret.Name != "" {
+			secretNames.Insert(ldap.CACertSecret.Name)
+		}
+	}
+
 	for i := range s.ExtraVolumes {
 		vol := &s.ExtraVolumes[i]
 		if vol.Secret != nil {
@@ -47,3 +58,32 @@ func (s *VitessCellGatewaySpec) ReloadSecretNames() sets.String {
 
 	return secretNames
 }
+
+// StaticAuthSecretName returns the name of the Secret that holds the vtgate
+// static auth file, or "" if static auth isn't enabled with a Secret by name.
+// vtgate reloads this file in place, so it isn't in ReloadSecretNames.
+func (s *VitessCellGatewaySpec) StaticAuthSecretName() string {
+	if s.Authentication.Static == nil || s.Authentication.Static.Secret == nil {
+		return ""
+	}
+	return s.Authentication.Static.Secret.Name
+}
+
+// BlueGreen returns the blue/green rollout strategy for vtgate, or nil if
+// vtgate changes aren't rolled out that way.
+func (s *VitessCellGatewaySpec) BlueGreen() *VitessGatewayBlueGreenStrategy {
+	if s.RolloutStrategy == nil || s.RolloutStrategy.Type != BlueGreenGatewayRolloutStrategyType {
+		return nil
+	}
+	return s.RolloutStrategy.BlueGreen
+}
+
+// IsPlaced returns whether the cell is deployed somewhere other than
+// alongside a VitessCluster in the given namespace.
+func (c *VitessCellTemplate) IsPlaced(namespace string) bool {
+	p := c.Placement
+	if p == nil {
+		return false
+	}
+	return p.KubeconfigSecret != nil || (p.Namespace != "" && p.Namespace != namespace)
+}
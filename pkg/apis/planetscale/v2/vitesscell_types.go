@@ -17,7 +17,9 @@ limitations under the License.
 package v2
 
 import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -39,6 +41,7 @@ import (
 // just like a Deployment can manage Pods that run on multiple Nodes.
 // +kubebuilder:resource:path=vitesscells,shortName=vtc
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.gateway.replicas,statuspath=.status.gateway.replicas,selectorpath=.status.gateway.labelSelector
 type VitessCell struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -102,6 +105,47 @@ type VitessCellTemplate struct {
 
 	// Gateway configures the Vitess Gateway deployment in this cell.
 	Gateway VitessCellGatewaySpec `json:"gateway,omitempty"`
+
+	// Placement deploys this cell in a different namespace or Kubernetes
+	// cluster than the VitessCluster. See VitessCellPlacement for details.
+	//
+	// Changing the placement of an existing cell is like removing it and
+	// adding it back: its tablets are drained and turned down first, and then
+	// deployed again in the new place.
+	//
+	// Default: Deploy the cell alongside the VitessCluster.
+	Placement *VitessCellPlacement `json:"placement,omitempty"`
+}
+
+// VitessCellPlacement specifies where to deploy a cell that doesn't run
+// alongside its VitessCluster.
+//
+// The operator deploys placed cells by maintaining a member VitessCluster,
+// with the same name, in each distinct place. A member deploys only its own
+// cells, along with tablets of every keyspace in those cells, and it shares
+// the global lockserver of the VitessCluster, which coordinates topology,
+// backups and reparents across all the places. Keyspaces, cells and backup
+// schedules are still only managed by the VitessCluster.
+//
+// Anything the cell needs that's referenced by name, such as backup
+// credentials or TLS Secrets, must also exist in the place it's deployed.
+// If the cell is in another Kubernetes cluster, the vitess-operator must be
+// running there too, and the global lockserver must be reachable from there,
+// such as by setting cellInfoAddress for a deployed global lockserver.
+type VitessCellPlacement struct {
+	// Namespace is the namespace to deploy the cell in. If it's not the
+	// namespace of the VitessCluster, the vitess-operator must have permission
+	// to manage resources in it, and must watch it too.
+	// Default: The namespace of the VitessCluster.
+	Namespace string `json:"namespace,omitempty"`
+
+	// KubeconfigSecret is a kubeconfig for the Kubernetes cluster to deploy
+	// the cell in. The Secret must be in the same namespace as the
+	// VitessCluster, and the kubeconfig must have permission to manage
+	// VitessCluster objects in the target namespace. Only the 'name' and 'key'
+	// fields are used.
+	// Default: Deploy the cell in the same Kubernetes cluster.
+	KubeconfigSecret *SecretSource `json:"kubeconfigSecret,omitempty"`
 }
 
 // VitessCellImages specifies container images to use for this cell.
@@ -190,6 +234,13 @@ type VitessCellGatewaySpec struct {
 	// Service can optionally be used to customize the per-cell vtgate Service.
 	Service *ServiceOverrides `json:"service,omitempty"`
 
+	// Exposure can optionally be used to expose the per-cell vtgate Service
+	// outside of the Kubernetes cluster, through a node port, a load
+	// balancer, or Gateway API routes.
+	//
+	// Default: vtgate is only reachable from inside the Kubernetes cluster.
+	Exposure *VitessGatewayExposure `json:"exposure,omitempty"`
+
 	// Tolerations allow you to schedule pods onto nodes with matching taints.
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -201,6 +252,11 @@ type VitessCellGatewaySpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 
+	// ZoneSpread can optionally be used to spread vtgate pods evenly across
+	// zones, which is useful in cells that span several zones. It adds a
+	// topologySpreadConstraint to the ones above.
+	ZoneSpread *VitessZoneSpread `json:"zoneSpread,omitempty"`
+
 	// Lifecycle can optionally be used to add container lifecycle hooks
 	// to the vtgate container.
 	Lifecycle corev1.Lifecycle `json:"lifecycle,omitempty"`
@@ -208,12 +264,452 @@ type VitessCellGatewaySpec struct {
 	// TerminationGracePeriodSeconds can optionally be used to customize
 	// terminationGracePeriodSeconds of the vtgate pod.
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// TerminationDrainDuration is how long a vtgate Pod that's being removed,
+	// whether by a rollout or by scaling down, keeps serving before vtgate is
+	// told to shut down. Kubernetes takes a terminating Pod out of the vtgate
+	// Service right away, but load balancers and connection pools may keep
+	// sending it new connections until the change reaches them. The operator
+	// waits this long in a preStop hook, unless lifecycle sets one already.
+	//
+	// Default: vtgate is told to shut down right away.
+	TerminationDrainDuration *metav1.Duration `json:"terminationDrainDuration,omitempty"`
+
+	// Lameduck can optionally be used to configure how vtgate drains its
+	// MySQL client connections once it's told to shut down.
+	Lameduck *VitessGatewayLameduck `json:"lameduck,omitempty"`
+
+	// ServingGate can optionally be used to keep new vtgate Pods out of the
+	// vtgate Service until enough shards can serve queries, so clients of a
+	// cell that's starting cold don't get a burst of errors.
+	//
+	// Default: vtgate Pods receive traffic as soon as vtgate is up.
+	ServingGate *VitessGatewayServingGate `json:"servingGate,omitempty"`
+
+	// RolloutStrategy can optionally be used to change how vtgate changes are
+	// rolled out.
+	//
+	// Default: The vtgate Deployment does a rolling update.
+	RolloutStrategy *VitessGatewayRolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// PodDisruptionBudget can optionally be used to customize the
+	// PodDisruptionBudget (PDB) that the operator manages for vtgate Pods in this cell.
+	//
+	// Default: A PDB that lets only one Pod be evicted at a time.
+	PodDisruptionBudget *VitessPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// Autoscaler can optionally be used to let a HorizontalPodAutoscaler
+	// (HPA) scale vtgate in this cell. While it's set, the HPA manages the
+	// number of replicas, so the replicas field only sets the initial size.
+	//
+	// Default: vtgate isn't autoscaled.
+	Autoscaler *VitessGatewayAutoscaler `json:"autoscaler,omitempty"`
+
+	// ScaleProfiles can optionally be used to run a different number of
+	// vtgate replicas on a schedule, such as more replicas during business
+	// hours. While a profile is in effect, its replicas are used in place of
+	// the Replicas field. If more than one profile is in effect at once, the
+	// first one listed wins.
+	//
+	// If the Autoscaler is enabled, the profile instead sets the lowest
+	// number of replicas that the autoscaler may scale down to, up to its
+	// MaxReplicas.
+	ScaleProfiles []VitessScaleProfile `json:"scaleProfiles,omitempty"`
+}
+
+// VitessGatewayLameduck configures how vtgate shuts down.
+//
+// Once vtgate is told to shut down, it stops accepting MySQL connections and
+// fails queries outside of transactions on the ones it has, so clients
+// reconnect to another vtgate. It waits up to DrainTimeout for in-flight
+// queries and open transactions to finish, and keeps running for at least
+// Period in total before it exits.
+type VitessGatewayLameduck struct {
+	// Period is the minimum time vtgate keeps running after it's told to
+	// shut down.
+	//
+	// Default: 50ms
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// DrainTimeout is how long vtgate waits for in-flight queries and open
+	// transactions to finish before it shuts down anyway. If set, it takes
+	// precedence over the drainTimeout of the autoscaler.
+	//
+	// Default: The drainTimeout of the autoscaler, if any, or else 10s.
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+}
+
+// VitessGatewayServingGate configures a readiness gate on vtgate Pods.
+//
+// New vtgate Pods aren't Ready, and so don't receive traffic through the
+// vtgate Service, until the operator marks them as serving. It does that once
+// enough of the shards of the keyspaces deployed in the cell have a primary
+// and a Ready tablet. Pods stay marked after that, so vtgate doesn't stop
+// serving the healthy shards when some other shard loses its primary.
+type VitessGatewayServingGate struct {
+	// MinHealthyShardPercent is the percentage of shards that must be
+	// healthy before new vtgate Pods receive traffic. Shards that aren't
+	// serving their key range yet, such as the targets of a resharding
+	// that's in progress, aren't counted.
+	//
+	// Default: 100
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinHealthyShardPercent *int32 `json:"minHealthyShardPercent,omitempty"`
+
+	// MaxWait is how long after it's created a vtgate Pod may be held back.
+	// After that, it receives traffic even if too few shards are healthy, so
+	// a broken shard can't keep vtgate out of service forever.
+	//
+	// Default: 10m
+	MaxWait *metav1.Duration `json:"maxWait,omitempty"`
+}
+
+// VitessGatewayExposure configures how the per-cell vtgate Service is
+// reachable from outside of the Kubernetes cluster.
+type VitessGatewayExposure struct {
+	// ServiceType is the type of the per-cell vtgate Service.
+	//
+	// Default: ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+
+	// LoadBalancer configures the load balancer when ServiceType is
+	// LoadBalancer.
+	LoadBalancer *VitessGatewayLoadBalancer `json:"loadBalancer,omitempty"`
+
+	// NodePorts can optionally be used to pick the node ports of the vtgate
+	// Service when ServiceType is NodePort or LoadBalancer.
+	//
+	// Default: Kubernetes picks free node ports.
+	NodePorts *VitessGatewayNodePorts `json:"nodePorts,omitempty"`
+
+	// ExternalTrafficPolicy is the externalTrafficPolicy of the vtgate
+	// Service when ServiceType is NodePort or LoadBalancer. Set it to Local
+	// to preserve client IP addresses.
+	//
+	// Default: Cluster
+	// +kubebuilder:validation:Enum=Cluster;Local
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+
+	// Routes can optionally be used to have the operator generate Gateway
+	// API routes to the vtgate Service. The Gateway API CRDs must be
+	// installed in the Kubernetes cluster.
+	Routes *VitessGatewayRoutes `json:"routes,omitempty"`
+}
+
+// VitessGatewayLoadBalancer configures the load balancer for vtgate.
+type VitessGatewayLoadBalancer struct {
+	// Internal requests a load balancer that's only reachable from inside
+	// the cloud network, rather than from the internet. The operator sets the
+	// annotations that the AWS, GCP, and Azure load balancer controllers
+	// look for.
+	//
+	// Default: false
+	Internal bool `json:"internal,omitempty"`
+
+	// Class is the loadBalancerClass of the vtgate Service. This field is
+	// immutable on Service objects, so changes made after the Service becomes
+	// a LoadBalancer will only be applied if you manually delete the Service.
+	//
+	// Default: The default load balancer implementation of the cloud provider.
+	Class *string `json:"class,omitempty"`
+
+	// SourceRanges can optionally be used to limit the client IP ranges that
+	// the load balancer accepts connections from.
+	SourceRanges []string `json:"sourceRanges,omitempty"`
+
+	// Annotations specifies extra annotations to add to the vtgate Service,
+	// which cloud providers use to configure load balancers.
+	// Annotations added in this way are removed from the Service object if
+	// they are removed here, or if the Service stops being a LoadBalancer.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// VitessGatewayNodePorts are the node ports of the vtgate Service. Ports that
+// are left unset are picked by Kubernetes.
+type VitessGatewayNodePorts struct {
+	// MySQL is the node port for the MySQL protocol.
+	MySQL int32 `json:"mysql,omitempty"`
+	// GRPC is the node port for the vtgate gRPC API.
+	GRPC int32 `json:"grpc,omitempty"`
+	// Web is the node port for the vtgate web UI.
+	Web int32 `json:"web,omitempty"`
+}
+
+// VitessGatewayRoutes configures the Gateway API routes that the operator
+// generates for vtgate. Routes that are removed from here are deleted.
+type VitessGatewayRoutes struct {
+	// HTTP generates an HTTPRoute to the vtgate web UI.
+	HTTP *VitessGatewayHTTPRoute `json:"http,omitempty"`
+
+	// TCP generates a TCPRoute to the vtgate MySQL port. TCPRoute is only
+	// in the experimental channel of the Gateway API.
+	TCP *VitessGatewayTCPRoute `json:"tcp,omitempty"`
+}
+
+// VitessGatewayHTTPRoute configures an HTTPRoute to vtgate.
+type VitessGatewayHTTPRoute struct {
+	// ParentRefs are the Gateways, and optionally the listeners on them,
+	// that the route attaches to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayAPIParentRef `json:"parentRefs"`
+
+	// Hostnames are the hostnames that the route matches.
+	//
+	// Default: All hostnames of the listeners it attaches to.
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+// VitessGatewayTCPRoute configures a TCPRoute to vtgate.
+type VitessGatewayTCPRoute struct {
+	// ParentRefs are the Gateways, and optionally the listeners on them,
+	// that the route attaches to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayAPIParentRef `json:"parentRefs"`
+}
+
+// GatewayAPIParentRef refers to a Gateway API Gateway that a route attaches
+// to.
+type GatewayAPIParentRef struct {
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway.
+	//
+	// Default: The namespace of the route.
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener on the Gateway to attach to.
+	//
+	// Default: All listeners that allow the route.
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// VitessGatewayAutoscaler configures the HorizontalPodAutoscaler for vtgate.
+//
+// Any combination of metrics can be used, in which case the HPA picks the
+// highest number of replicas that any of them calls for. If no metrics are
+// given, vtgate is scaled on CPU utilization.
+type VitessGatewayAutoscaler struct {
+	// MinReplicas is the lowest number of vtgate replicas to scale down to.
+	//
+	// Default: 2
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the highest number of vtgate replicas to scale up to.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilization is the average CPU utilization to aim for across
+	// vtgate Pods, as a percentage of the requested CPU.
+	//
+	// Default: 80, if no other metrics are given.
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// QueriesPerSecond scales vtgate on the average rate of queries each
+	// vtgate Pod serves. This requires an adapter that serves vtgate metrics
+	// through the Kubernetes custom metrics API, such as prometheus-adapter.
+	QueriesPerSecond *VitessGatewayAutoscalerMetric `json:"queriesPerSecond,omitempty"`
+
+	// Connections scales vtgate on the average number of MySQL client
+	// connections each vtgate Pod has open. This requires an adapter that
+	// serves vtgate metrics through the Kubernetes custom metrics API, such
+	// as prometheus-adapter.
+	Connections *VitessGatewayAutoscalerMetric `json:"connections,omitempty"`
+
+	// Metrics can optionally be used to scale vtgate on any other metrics,
+	// in the same format as the metrics of a HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// ScaleDownStabilizationWindow is how long the metrics must call for
+	// fewer replicas before vtgate is scaled down, so brief dips in load
+	// don't cause vtgates to be removed only to be added back again.
+	//
+	// Default: 5m
+	ScaleDownStabilizationWindow *metav1.Duration `json:"scaleDownStabilizationWindow,omitempty"`
+
+	// DrainTimeout is how long each vtgate Pod that's removed waits for its
+	// MySQL client connections to finish what they're doing before it shuts
+	// down. vtgate is scaled down by at most one Pod per DrainTimeout, so only
+	// one Pod is draining connections at a time.
+	//
+	// Default: 1m
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+}
+
+// VitessGatewayAutoscalerMetric is a per-Pod vtgate metric to scale vtgate on.
+type VitessGatewayAutoscalerMetric struct {
+	// MetricName is the name of the metric, as it's served through the
+	// Kubernetes custom metrics API.
+	//
+	// Default: "vtgate_queries_per_second" for queriesPerSecond, or
+	// "vtgate_mysql_server_conn_count" for connections.
+	MetricName string `json:"metricName,omitempty"`
+
+	// AverageValue is the average value of the metric per vtgate Pod to aim for.
+	AverageValue resource.Quantity `json:"averageValue"`
+}
+
+// VitessGatewayRolloutStrategyType is the type of rollout strategy for vtgate.
+type VitessGatewayRolloutStrategyType string
+
+const (
+	// RollingUpdateGatewayRolloutStrategyType updates vtgate Pods in place
+	// with a rolling update of the vtgate Deployment.
+	RollingUpdateGatewayRolloutStrategyType VitessGatewayRolloutStrategyType = "RollingUpdate"
+	// BlueGreenGatewayRolloutStrategyType brings up the new version of vtgate
+	// in a second Deployment, and shifts traffic to it gradually.
+	BlueGreenGatewayRolloutStrategyType VitessGatewayRolloutStrategyType = "BlueGreen"
+)
+
+// VitessGatewayRolloutStrategy configures how vtgate changes are rolled out.
+type VitessGatewayRolloutStrategy struct {
+	// Type selects the rollout strategy.
+	//
+	// Default: RollingUpdate
+	// +kubebuilder:validation:Enum=RollingUpdate;BlueGreen
+	Type VitessGatewayRolloutStrategyType `json:"type,omitempty"`
+
+	// BlueGreen tunes the BlueGreen rollout strategy.
+	// It's only used when Type is BlueGreen.
+	BlueGreen *VitessGatewayBlueGreenStrategy `json:"blueGreen,omitempty"`
+}
+
+// VitessGatewayBlueGreenStrategy tunes a blue/green rollout of vtgate.
+//
+// The new version of vtgate is brought up in a second Deployment alongside
+// the old one. Both are behind the same Service, so traffic is shifted by
+// scaling the new Deployment up one step at a time, and only scaling the old
+// one down as new vtgates become Ready. If too many of the queries served by
+// the new vtgates fail, the rollout is rolled back, and it isn't tried again
+// until the vtgate spec changes.
+//
+// Switching a cell to BlueGreen does one rollout, even if nothing else changed,
+// since the operator can't tell which version the existing vtgates run.
+type VitessGatewayBlueGreenStrategy struct {
+	// StepPercent is the percentage of vtgate replicas that are moved to the
+	// new version in each step.
+	//
+	// Default: 25
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercent *int32 `json:"stepPercent,omitempty"`
+
+	// StepInterval is how long each step must serve traffic without too many
+	// errors before moving on to the next one.
+	//
+	// Default: 2m
+	StepInterval *metav1.Duration `json:"stepInterval,omitempty"`
+
+	// MaxErrorPercent is the percentage of queries served by new vtgates that
+	// may fail before the rollout is rolled back.
+	//
+	// Default: 5
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MaxErrorPercent *int32 `json:"maxErrorPercent,omitempty"`
+
+	// MinQueries is how many queries the new vtgates must have served before
+	// their error rate is judged.
+	//
+	// Default: 100
+	// +kubebuilder:validation:Minimum=0
+	MinQueries *int64 `json:"minQueries,omitempty"`
 }
 
 // VitessGatewayAuthentication configures authentication for vtgate in this cell.
+//
+// Only one of Static, LDAP, and ClientCert may be set.
 type VitessGatewayAuthentication struct {
 	// Static configures vtgate to use a static file containing usernames and passwords.
 	Static *VitessGatewayStaticAuthentication `json:"static,omitempty"`
+
+	// LDAP configures vtgate to check usernames and passwords against an
+	// LDAP server.
+	LDAP *VitessGatewayLDAPAuthentication `json:"ldap,omitempty"`
+
+	// ClientCert configures vtgate to authenticate MySQL clients by their
+	// TLS client certificates. The MySQL username must match the common
+	// name (CN) of the certificate, and the DNS names in the certificate
+	// become the groups of the user, e.g. for table ACLs.
+	//
+	// This requires secureTransport.tls.clientCACertSecret, which is the
+	// certificate authority that client certificates must be signed by.
+	ClientCert *VitessGatewayClientCertAuthentication `json:"clientCert,omitempty"`
+}
+
+// VitessGatewayAuthMethod is the MySQL authentication method that vtgate asks
+// clients to use when it needs their password in plaintext.
+type VitessGatewayAuthMethod string
+
+const (
+	// ClearPasswordGatewayAuthMethod asks for the mysql_clear_password method.
+	ClearPasswordGatewayAuthMethod VitessGatewayAuthMethod = "mysql_clear_password"
+	// DialogGatewayAuthMethod asks for the dialog method.
+	DialogGatewayAuthMethod VitessGatewayAuthMethod = "dialog"
+)
+
+// VitessGatewayLDAPAuthentication configures LDAP authentication for vtgate.
+//
+// The operator renders the vtgate LDAP config file into a Secret that it
+// manages, and restarts vtgate when the config or the Secrets it refers to
+// change.
+type VitessGatewayLDAPAuthentication struct {
+	// Server is the address of the LDAP server, as host:port.
+	// vtgate always connects to it with TLS.
+	Server string `json:"server"`
+
+	// UserDNPattern turns a MySQL username into the DN that vtgate binds as
+	// to check the user's password, with %s standing in for the username.
+	// For example: uid=%s,ou=users,dc=example,dc=com
+	UserDNPattern string `json:"userDNPattern"`
+
+	// GroupBaseDN is the DN under which vtgate searches for the groups of a
+	// user, which are the entries with a memberUid attribute that matches
+	// the username. The groups are used e.g. for table ACLs.
+	GroupBaseDN string `json:"groupBaseDN,omitempty"`
+
+	// BindDN is the DN that vtgate binds as to search for groups.
+	BindDN string `json:"bindDN,omitempty"`
+
+	// BindPasswordSecret is the password of BindDN, from a given key in a
+	// given Secret. The Secret must be specified by name.
+	BindPasswordSecret *SecretSource `json:"bindPasswordSecret,omitempty"`
+
+	// CACertSecret is the certificate authority that the LDAP server's
+	// certificate must be signed by, from a given key in a given Secret.
+	//
+	// Default: Trust the system's certificate authorities.
+	CACertSecret *SecretSource `json:"caCertSecret,omitempty"`
+
+	// RefreshInterval is how often vtgate refreshes the groups of a
+	// connected user.
+	//
+	// Default: 1m
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+
+	// AuthMethod is the MySQL authentication method that clients must use
+	// to send their password.
+	//
+	// Default: mysql_clear_password
+	// +kubebuilder:validation:Enum=mysql_clear_password;dialog
+	AuthMethod VitessGatewayAuthMethod `json:"authMethod,omitempty"`
+}
+
+// VitessGatewayClientCertAuthentication configures TLS client certificate
+// authentication for vtgate.
+type VitessGatewayClientCertAuthentication struct {
+	// AuthMethod is the MySQL authentication method that clients must use.
+	// The password they send is ignored.
+	//
+	// Default: mysql_clear_password
+	// +kubebuilder:validation:Enum=mysql_clear_password;dialog
+	AuthMethod VitessGatewayAuthMethod `json:"authMethod,omitempty"`
 }
 
 // VitessGatewayStaticAuthentication configures static file authentication for vtgate.
@@ -252,6 +748,83 @@ type VitessCellGatewayStatus struct {
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ServiceName is the name of the Service for this cell's vtgate.
 	ServiceName string `json:"serviceName,omitempty"`
+	// BlueGreen reports the progress of blue/green rollouts of vtgate, if the
+	// rollout strategy calls for them.
+	BlueGreen *VitessGatewayBlueGreenStatus `json:"blueGreen,omitempty"`
+	// Rollout is the progress of rolling out the latest vtgate spec.
+	Rollout VitessRolloutCounts `json:"rollout,omitempty"`
+	// Replicas is the number of vtgate Pods that currently exist in this
+	// cell. It's reported through the VitessCell scale subresource.
+	Replicas int32 `json:"replicas,omitempty"`
+	// LabelSelector selects the vtgate Pods in this cell. It's reported
+	// through the VitessCell scale subresource, so a HorizontalPodAutoscaler
+	// can find the Pods to get metrics for.
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// ScaleProfile is the name of the scale profile in effect, if any.
+	ScaleProfile string `json:"scaleProfile,omitempty"`
+	// StaticAuth reports which vtgate Pods have picked up the current
+	// contents of the static auth Secret, if static auth is enabled.
+	StaticAuth *VitessGatewayStaticAuthStatus `json:"staticAuth,omitempty"`
+	// ServingGate reports on the readiness gate that holds new vtgate Pods
+	// back until enough shards are healthy, if it's enabled.
+	ServingGate *VitessGatewayServingGateStatus `json:"servingGate,omitempty"`
+}
+
+// VitessGatewayServingGateStatus reports on the vtgate readiness gate.
+type VitessGatewayServingGateStatus struct {
+	// Shards is the number of shards that are counted.
+	Shards int32 `json:"shards,omitempty"`
+	// HealthyShards is the number of shards that have a primary and a Ready
+	// tablet.
+	HealthyShards int32 `json:"healthyShards,omitempty"`
+	// HeldPods are the names of the vtgate Pods that are held back until
+	// enough shards are healthy.
+	HeldPods []string `json:"heldPods,omitempty"`
+}
+
+// VitessGatewayStaticAuthStatus reports on rolling out changes to the static
+// auth Secret of vtgate.
+//
+// vtgate reloads the static auth file in place, without dropping any
+// connections. When the Secret changes, the operator touches each vtgate Pod
+// so the kubelet refreshes the mounted file right away, rather than at its
+// next periodic sync, and then waits for vtgate to reload it.
+type VitessGatewayStaticAuthStatus struct {
+	// SecretHash is a hash of the current contents of the static auth
+	// Secret.
+	SecretHash string `json:"secretHash,omitempty"`
+	// ChangedTime is when the operator first saw the current contents of
+	// the static auth Secret.
+	ChangedTime *metav1.Time `json:"changedTime,omitempty"`
+	// UpdatedReplicas is the number of vtgate Pods that have picked up the
+	// current contents of the static auth Secret.
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+	// PendingPods are the names of the vtgate Pods that haven't picked up
+	// the current contents of the static auth Secret yet.
+	PendingPods []string `json:"pendingPods,omitempty"`
+}
+
+// VitessGatewayBlueGreenStatus reports the progress of blue/green rollouts of
+// vtgate in a cell.
+type VitessGatewayBlueGreenStatus struct {
+	// ActiveDeployment is the name of the vtgate Deployment that serves all
+	// traffic outside of a rollout.
+	ActiveDeployment string `json:"activeDeployment,omitempty"`
+	// CandidateDeployment is the name of the vtgate Deployment that the
+	// rollout in progress is shifting traffic to, if any.
+	CandidateDeployment string `json:"candidateDeployment,omitempty"`
+	// CandidateHash identifies the vtgate spec that's being rolled out.
+	CandidateHash string `json:"candidateHash,omitempty"`
+	// Step is the step of the rollout in progress, starting from 1.
+	Step int32 `json:"step,omitempty"`
+	// StepStartTime is when every new vtgate of the current step became Ready.
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// RolledBackHash identifies the vtgate spec of the last rollout that was
+	// rolled back. That spec isn't rolled out again.
+	RolledBackHash string `json:"rolledBackHash,omitempty"`
+	// Message describes the outcome of the last rollout or what the rollout
+	// in progress is waiting for.
+	Message string `json:"message,omitempty"`
 }
 
 // VitessCellStatus defines the observed state of VitessCell
@@ -19,6 +19,7 @@ package v2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -30,10 +31,56 @@ func DefaultVitessCluster(vt *VitessCluster) {
 	DefaultVtAdmin(&vt.Spec.VtAdmin)
 	DefaultVitessKeyspaceTemplates(vt.Spec.Keyspaces)
 	defaultClusterBackup(vt.Spec.Backup)
+	defaultInitialRestore(vt)
+	defaultStandby(vt)
 	DefaultTopoReconcileConfig(&vt.Spec.TopologyReconciliation)
 	DefaultUpdateStrategy(&vt.Spec.UpdateStrategy)
+	DefaultReparentSettings(&vt.Spec.ReparentSettings)
 	DefaultServiceOverrides(&vt.Spec.GatewayService)
 	DefaultServiceOverrides(&vt.Spec.TabletService)
+	if vt.Spec.RevisionHistoryLimit == nil {
+		vt.Spec.RevisionHistoryLimit = pointer.Int32Ptr(defaultRevisionHistoryLimit)
+	}
+}
+
+func defaultInitialRestore(vt *VitessCluster) {
+	if vt.Spec.InitialRestore == nil {
+		return
+	}
+	if vt.Spec.InitialRestore.ClusterName == "" {
+		vt.Spec.InitialRestore.ClusterName = vt.Name
+	}
+}
+
+func defaultStandby(vt *VitessCluster) {
+	if vt.Spec.ClusterRole == "" {
+		vt.Spec.ClusterRole = PrimaryVitessClusterRole
+	}
+	if vt.Spec.Standby == nil {
+		if vt.Spec.ClusterRole != StandbyVitessClusterRole {
+			return
+		}
+		vt.Spec.Standby = &VitessStandbySpec{}
+	}
+	if vt.Spec.Standby.ClusterName == "" {
+		vt.Spec.Standby.ClusterName = vt.Name
+	}
+	if vt.Spec.Standby.RefreshInterval == nil {
+		vt.Spec.Standby.RefreshInterval = &metav1.Duration{Duration: defaultStandbyRefreshInterval}
+	}
+}
+
+// DefaultVitessZoneSpread fills in default values for spreading Pods across zones.
+func DefaultVitessZoneSpread(spread *VitessZoneSpread) {
+	if spread.TopologyKey == "" {
+		spread.TopologyKey = corev1.LabelTopologyZone
+	}
+	if spread.MaxSkew == nil {
+		spread.MaxSkew = pointer.Int32Ptr(defaultZoneSpreadMaxSkew)
+	}
+	if spread.WhenUnsatisfiable == "" {
+		spread.WhenUnsatisfiable = corev1.DoNotSchedule
+	}
 }
 
 func defaultGlobalLockserver(vt *VitessCluster) {
@@ -159,6 +206,12 @@ func defaultCustomPartitioning(customPartition *VitessKeyspaceCustomPartitioning
 	for i := range customPartition.Shards {
 		DefaultVitessShardTemplate(&customPartition.Shards[i].VitessShardTemplate)
 	}
+	if customPartition.ShardTemplate != nil {
+		DefaultVitessShardTemplate(customPartition.ShardTemplate)
+	}
+	if customPartition.ProvisioningOrder == "" {
+		customPartition.ProvisioningOrder = ShardProvisioningAscending
+	}
 }
 
 func defaultEqualPartitioning(equalPartition *VitessKeyspaceEqualPartitioning) {
@@ -167,6 +220,9 @@ func defaultEqualPartitioning(equalPartition *VitessKeyspaceEqualPartitioning) {
 	}
 
 	DefaultVitessShardTemplate(&equalPartition.ShardTemplate)
+	if equalPartition.ProvisioningOrder == "" {
+		equalPartition.ProvisioningOrder = ShardProvisioningAscending
+	}
 }
 
 func defaultClusterBackup(backup *ClusterBackupSpec) {
@@ -211,6 +267,14 @@ func DefaultTopoReconcileConfig(confPtr **TopoReconcileConfig) {
 	if conf.PruneSrvKeyspaces == nil {
 		conf.PruneSrvKeyspaces = pointer.BoolPtr(true)
 	}
+
+	// Defaulting consistency check code.
+	if conf.RepairServingGraph == nil {
+		conf.RepairServingGraph = pointer.BoolPtr(true)
+	}
+	if conf.ConsistencyCheckInterval == nil {
+		conf.ConsistencyCheckInterval = &metav1.Duration{Duration: defaultTopoConsistencyCheckInterval}
+	}
 }
 
 func DefaultUpdateStrategy(updateStratPtr **VitessClusterUpdateStrategy) {
@@ -231,6 +295,71 @@ func DefaultUpdateStrategy(updateStratPtr **VitessClusterUpdateStrategy) {
 			updateStrat.External = &ExternalVitessClusterUpdateStrategyOptions{}
 		}
 	}
+
+	if canary := updateStrat.Canary; canary != nil {
+		if canary.Replicas == nil {
+			canary.Replicas = pointer.Int32Ptr(defaultCanaryReplicas)
+		}
+		if canary.SoakPeriod == nil {
+			canary.SoakPeriod = &metav1.Duration{Duration: defaultCanarySoakPeriod}
+		}
+	}
+
+	if checks := updateStrat.UpgradeChecks; checks != nil {
+		if checks.MaxBackupAge == nil {
+			checks.MaxBackupAge = &metav1.Duration{Duration: defaultUpgradeCheckMaxBackupAge}
+		}
+		if checks.MaxReplicationLag == nil {
+			checks.MaxReplicationLag = &metav1.Duration{Duration: defaultUpgradeCheckMaxReplicationLag}
+		}
+		if checks.MinHealthyReplicas == nil {
+			checks.MinHealthyReplicas = pointer.Int32Ptr(defaultUpgradeCheckMinHealthyReplicas)
+		}
+	}
+
+	if updateStrat.CrashLoopBackoff == nil {
+		updateStrat.CrashLoopBackoff = &VitessRolloutBackoff{}
+	}
+	backoff := updateStrat.CrashLoopBackoff
+	if backoff.RestartThreshold == nil {
+		backoff.RestartThreshold = pointer.Int32Ptr(defaultCrashLoopRestartThreshold)
+	}
+	if backoff.InitialDelay == nil {
+		backoff.InitialDelay = &metav1.Duration{Duration: defaultCrashLoopInitialDelay}
+	}
+	if backoff.MaxDelay == nil {
+		backoff.MaxDelay = &metav1.Duration{Duration: defaultCrashLoopMaxDelay}
+	}
+}
+
+// DefaultReparentSettings applies defaults to a ReparentSettings field.
+func DefaultReparentSettings(settingsPtr **ReparentSettings) {
+	if *settingsPtr == nil {
+		*settingsPtr = &ReparentSettings{}
+	}
+	settings := *settingsPtr
+
+	if settings.PlannedReparentTimeout == nil {
+		settings.PlannedReparentTimeout = &metav1.Duration{Duration: defaultPlannedReparentTimeout}
+	}
+	if settings.ReconcileDrainTimeout == nil {
+		settings.ReconcileDrainTimeout = &metav1.Duration{Duration: defaultReconcileDrainTimeout}
+	}
+	if settings.TolerableReplicationLag == nil {
+		settings.TolerableReplicationLag = &metav1.Duration{Duration: defaultTolerableReplicationLag}
+	}
+	if settings.AllowEmergencyFailover == nil {
+		settings.AllowEmergencyFailover = pointer.BoolPtr(defaultAllowEmergencyFailover)
+	}
+	if settings.EmergencyFailoverGracePeriod == nil {
+		settings.EmergencyFailoverGracePeriod = &metav1.Duration{Duration: defaultEmergencyFailoverGracePeriod}
+	}
+	if settings.DrainAbortGracePeriod == nil {
+		settings.DrainAbortGracePeriod = &metav1.Duration{Duration: defaultDrainAbortGracePeriod}
+	}
+	if settings.DrainTimeout == nil {
+		settings.DrainTimeout = &metav1.Duration{Duration: defaultDrainTimeout}
+	}
 }
 
 // DefaultServiceOverrides applies defaults to a ServiceOverrides field.
@@ -18,6 +18,7 @@ package v2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Cell looks up an item in the Cells list by name.
@@ -41,6 +42,36 @@ func (s *VitessClusterSpec) ZoneMap() map[string]string {
 	return zones
 }
 
+// IsStandby returns whether the cluster follows another cluster as a standby.
+func (s *VitessClusterSpec) IsStandby() bool {
+	return s.ClusterRole == StandbyVitessClusterRole
+}
+
+// StandbySpec returns the standby settings for the cluster's shards, or nil
+// if the cluster isn't a standby.
+func (s *VitessClusterSpec) StandbySpec() *VitessStandbySpec {
+	if !s.IsStandby() {
+		return nil
+	}
+	return s.Standby
+}
+
+// InitialRestoreSource returns where shards that haven't had a primary yet
+// restore from: the initialRestore if set, or else the standby source, which
+// is kept after promotion so shards can find it until they elect a primary.
+func (s *VitessClusterSpec) InitialRestoreSource() *VitessInitialRestoreSpec {
+	if s.InitialRestore != nil {
+		return s.InitialRestore
+	}
+	if s.Standby != nil {
+		return &VitessInitialRestoreSpec{
+			BackupLocationName: s.Standby.BackupLocationName,
+			ClusterName:        s.Standby.ClusterName,
+		}
+	}
+	return nil
+}
+
 // Image returns the first mysqld flavor image that's set.
 func (image *MysqldImage) Image() string {
 	switch {
@@ -48,6 +79,8 @@ func (image *MysqldImage) Image() string {
 		return image.Mysql56Compatible
 	case image.Mysql80Compatible != "":
 		return image.Mysql80Compatible
+	case image.Mysql84Compatible != "":
+		return image.Mysql84Compatible
 	case image.MariadbCompatible != "":
 		return image.MariadbCompatible
 	case image.Mariadb103Compatible != "":
@@ -65,6 +98,8 @@ func (image *MysqldImage) Flavor() string {
 		return "MySQL56"
 	case image.Mysql80Compatible != "":
 		return "MySQL80"
+	case image.Mysql84Compatible != "":
+		return "MySQL80"
 	case image.MariadbCompatible != "":
 		return "MariaDB"
 	case image.Mariadb103Compatible != "":
@@ -74,6 +109,23 @@ func (image *MysqldImage) Flavor() string {
 	}
 }
 
+// VersionSeries returns the MySQL version series, such as "8.0", implied by
+// the first flavor that has an image set, or an empty string if the flavor
+// covers more than one series.
+func (image *MysqldImage) VersionSeries() string {
+	switch {
+	case image.Mysql56Compatible != "":
+		// This flavor is used for both 5.6 and 5.7.
+		return ""
+	case image.Mysql80Compatible != "":
+		return "8.0"
+	case image.Mysql84Compatible != "":
+		return "8.4"
+	default:
+		return ""
+	}
+}
+
 func (externalOptions *ExternalVitessClusterUpdateStrategyOptions) ResourceChangesAllowed(resource corev1.ResourceName) bool {
 	for _, resourceOption := range externalOptions.AllowResourceChanges {
 		if resourceOption == resource {
@@ -83,3 +135,64 @@ func (externalOptions *ExternalVitessClusterUpdateStrategyOptions) ResourceChang
 
 	return false
 }
+
+// WithPaused returns the update strategy to pass down to child objects. If
+// paused is true, that's a copy of the update strategy with Paused set.
+func (s *VitessClusterUpdateStrategy) WithPaused(paused bool) *VitessClusterUpdateStrategy {
+	if !paused || s == nil || s.Paused {
+		return s
+	}
+	pausedStrategy := s.DeepCopy()
+	pausedStrategy.Paused = true
+	return pausedStrategy
+}
+
+// Add adds the given counts to these counts.
+func (c *VitessRolloutCounts) Add(other VitessRolloutCounts) {
+	c.Desired += other.Desired
+	c.Updated += other.Updated
+	c.Pending += other.Pending
+	c.Failed += other.Failed
+}
+
+// Done returns whether nothing is left to roll out.
+func (c *VitessRolloutCounts) Done() bool {
+	return c.Pending == 0 && c.Failed == 0
+}
+
+// TopologySpreadConstraint returns the constraint that spreads the Pods with
+// the given labels as configured.
+func (s *VitessZoneSpread) TopologySpreadConstraint(matchLabels map[string]string) corev1.TopologySpreadConstraint {
+	constraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           *s.MaxSkew,
+		TopologyKey:       s.TopologyKey,
+		WhenUnsatisfiable: s.WhenUnsatisfiable,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: matchLabels},
+	}
+	// Kubernetes only allows minDomains with DoNotSchedule.
+	if s.WhenUnsatisfiable == corev1.DoNotSchedule {
+		constraint.MinDomains = s.MinZones
+	}
+	return constraint
+}
+
+// GuaranteedZones returns the number of distinct zones that the given number
+// of Pods are guaranteed to land in. Without a minimum number of zones, the
+// scheduler is free to put them all in one zone if that's the only one that
+// has room.
+func (s *VitessZoneSpread) GuaranteedZones(replicas int32) int32 {
+	if replicas <= 0 {
+		return 0
+	}
+	isZoneKey := s.TopologyKey == corev1.LabelTopologyZone || s.TopologyKey == corev1.LabelFailureDomainBetaZone
+	if !isZoneKey || s.WhenUnsatisfiable != corev1.DoNotSchedule || s.MinZones == nil {
+		return 1
+	}
+	// No zone may get more than maxSkew Pods while there are fewer than
+	// minZones zones with any.
+	zones := (replicas + *s.MaxSkew - 1) / *s.MaxSkew
+	if zones > *s.MinZones {
+		zones = *s.MinZones
+	}
+	return zones
+}
@@ -19,6 +19,7 @@ package v2
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -39,6 +40,12 @@ import (
 // the VitessCluster object.
 // +kubebuilder:resource:path=vitessclusters,shortName=vt
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Complete",type="string",JSONPath=".status.rolloutStatus.complete",description="Whether the latest changes have been rolled out everywhere"
+// +kubebuilder:printcolumn:name="Tablets",type="integer",JSONPath=".status.rolloutStatus.vttablet.desired",description="Number of desired tablets"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.rolloutStatus.vttablet.updated",description="Number of up-to-date tablets"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.rolloutStatus.vttablet.pending",description="Number of tablets waiting to be updated"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.rolloutStatus.vttablet.failed",description="Number of tablets that failed to come up after being updated"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type VitessCluster struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -68,6 +75,44 @@ type VitessClusterSpec struct {
 	// of a new tablet in a shard with existing data, as an implementation detail.
 	Backup *ClusterBackupSpec `json:"backup,omitempty"`
 
+	// InitialRestore can optionally be used to bootstrap the cluster's shards
+	// from existing backups, rather than starting them with empty databases.
+	// This can be used to clone a cluster, or to rebuild one after a
+	// disaster. Each shard must have a complete backup in the source.
+	//
+	// Until a shard has had its first primary, its tablets restore from the
+	// backups of the source cluster. After that, they switch to the cluster's
+	// own backups, so nothing is ever written to the source. Since the new
+	// cluster starts out with no backups of its own, you should take a backup
+	// of each shard once it's up, before removing this field.
+	InitialRestore *VitessInitialRestoreSpec `json:"initialRestore,omitempty"`
+
+	// ClusterRole is either "primary" or "standby".
+	//
+	// A standby cluster is a disaster recovery copy of another cluster. Its
+	// shards restore from the other cluster's backups (see standby), but no
+	// primary is ever elected in them, so nothing can be written there. Its
+	// tablets are periodically recreated from the latest backup to keep
+	// them close to the original.
+	//
+	// To promote a standby cluster, change this to "primary". Each shard
+	// then elects a primary among the tablets that restored from the latest
+	// backup, and starts taking its own backups once it has one. Keep the
+	// standby section when promoting, so shards can still find the backups
+	// they restored from until they have a primary.
+	//
+	// Default: primary
+	// +kubebuilder:validation:Enum=primary;standby
+	ClusterRole VitessClusterRole `json:"clusterRole,omitempty"`
+
+	// Standby configures where a standby cluster restores from and how often
+	// its tablets are refreshed. It's only used if clusterRole is standby,
+	// except that shards keep restoring from it until they have a primary.
+	//
+	// Default: Restore from backups of a cluster with the same name in the
+	// backup location with an empty name, refreshing each tablet daily.
+	Standby *VitessStandbySpec `json:"standby,omitempty"`
+
 	// GlobalLockserver specifies either a deployed or external lockserver
 	// to be used as the Vitess global topology store.
 	// Default: Deploy an etcd cluster as the global lockserver.
@@ -120,6 +165,17 @@ type VitessClusterSpec struct {
 	// when a revision is made to the VitessCluster spec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// MaintenanceWindows can optionally be used to restrict disruptive changes
+	// to recurring windows of time. While no window is open, the operator
+	// holds off on releasing tablet Pods to be restarted or recreated for
+	// updates, which includes the planned reparents needed to update
+	// primaries, on expanding tablet data volumes, and on scheduled primary
+	// rotations. Everything else is still reconciled right away. Tablets that
+	// are already being updated when a window closes finish their update.
+	//
+	// Default: Disruptive changes may be made at any time.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
 	// GatewayService can optionally be used to customize the global vtgate Service.
 	// Note that per-cell vtgate Services can be customized within each cell
 	// definition.
@@ -127,6 +183,39 @@ type VitessClusterSpec struct {
 
 	// TabletService can optionally be used to customize the global, headless vttablet Service.
 	TabletService *ServiceOverrides `json:"tabletService,omitempty"`
+
+	// PreferredPrimaryCells is an optional list of cell names in which the
+	// operator should prefer to place shard primaries when it performs a
+	// planned reparent, such as when draining the current primary.
+	//
+	// When choosing a new primary, eligible tablets in these cells are
+	// preferred over eligible tablets in other cells. Tablets in other cells
+	// are only considered if no tablet in a preferred cell is eligible.
+	// If this is empty, all cells are treated equally.
+	PreferredPrimaryCells []string `json:"preferredPrimaryCells,omitempty"`
+
+	// ReparentSettings can be used to tune how the operator performs planned
+	// reparents, such as when draining the current primary of a shard.
+	ReparentSettings *ReparentSettings `json:"reparentSettings,omitempty"`
+
+	// RevisionHistoryLimit is the number of revisions of the cluster-wide
+	// images and extraVitessFlags to keep in status.revisionHistory, so they
+	// can be rolled back to with rollbackTo.
+	//
+	// Default: 10
+	// +kubebuilder:validation:Minimum=1
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// RollbackTo can be set to the number of a revision in
+	// status.revisionHistory to roll the cluster-wide images and
+	// extraVitessFlags back to what they were in that revision.
+	//
+	// While this is set, the images and extraVitessFlags fields are ignored
+	// and no new revisions are recorded. Pods are rolled back according to
+	// the update strategy, in the same order as any other update. Once the
+	// images and extraVitessFlags fields have been fixed, clear this field to
+	// go back to using them.
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
 }
 
 // VitessClusterUpdateStrategy indicates the strategy that the operator
@@ -152,6 +241,48 @@ type VitessClusterUpdateStrategy struct {
 	// External can optionally be used to enable the user to customize their external update strategy
 	// to allow certain updates to pass through immediately without using an external tool.
 	External *ExternalVitessClusterUpdateStrategyOptions `json:"external,omitempty"`
+
+	// Canary can optionally be used to upgrade the vttablet image of a few
+	// replica tablets in each shard first, and let them run on the new image
+	// for a while before upgrading the rest of the shard. The primary is
+	// still upgraded last, after a planned reparent to an upgraded replica.
+	//
+	// Default: Upgrade tablets without a canary.
+	Canary *VitessCanaryUpdateStrategy `json:"canary,omitempty"`
+
+	// Paused freezes the rollout of any changes that require restarting
+	// Pods, such as new images or flags for vttablet, vtgate, vtctld, vtadmin
+	// and vtorc, for example during a change freeze. Pending changes are
+	// still recorded in status, and are rolled out once the update strategy
+	// is unpaused. Status updates and automatic repairs, like replication
+	// repair or emergency reparents, keep working while paused.
+	//
+	// Rollouts can also be paused for a whole cluster, or just one keyspace or
+	// shard, by adding the 'planetscale.com/rollout-paused' annotation to the
+	// VitessCluster, VitessKeyspace or VitessShard object.
+	//
+	// Default: false
+	Paused bool `json:"paused,omitempty"`
+
+	// UpgradeChecks can optionally be used to run safety checks on each shard
+	// before rolling out a new vttablet or mysqld image to it. If any check
+	// fails, the upgrade of that shard is held back, and the shard reports
+	// the failed checks in its UpgradeBlocked condition. Once the first tablet
+	// in the shard has been upgraded, the rest of the upgrade proceeds
+	// without checking again.
+	//
+	// The checks are skipped for shards that use an external datastore.
+	//
+	// Default: Upgrades aren't checked.
+	UpgradeChecks *VitessUpgradeChecks `json:"upgradeChecks,omitempty"`
+
+	// CrashLoopBackoff configures how rollouts react when a tablet Pod keeps
+	// crashing after it's been updated. The shard stops releasing any more of
+	// its tablets, reports the problem in its RolloutStuck condition, and
+	// tablets in other shards of the cluster aren't updated either until it
+	// recovers. Each time this happens during a rollout, the shard waits
+	// twice as long after the Pod recovers before it updates the next tablet.
+	CrashLoopBackoff *VitessRolloutBackoff `json:"crashLoopBackoff,omitempty"`
 }
 
 // VitessClusterUpdateStrategyType is a string enumeration type that enumerates
@@ -165,6 +296,18 @@ const (
 	ImmediateVitessClusterUpdateStrategyType VitessClusterUpdateStrategyType = "Immediate"
 )
 
+// VitessClusterRole is whether a VitessCluster serves writes or follows
+// another cluster as a standby.
+type VitessClusterRole string
+
+const (
+	// PrimaryVitessClusterRole is a cluster whose shards have primaries.
+	PrimaryVitessClusterRole VitessClusterRole = "primary"
+	// StandbyVitessClusterRole is a cluster that follows the backups of
+	// another cluster, without electing primaries.
+	StandbyVitessClusterRole VitessClusterRole = "standby"
+)
+
 type ExternalVitessClusterUpdateStrategyOptions struct {
 	// AllowResourceChanges can be used to allow changes to certain resource
 	// requests and limits to propagate immediately, bypassing the external rollout tool.
@@ -176,6 +319,209 @@ type ExternalVitessClusterUpdateStrategyOptions struct {
 	AllowResourceChanges []corev1.ResourceName `json:"allowResourceChanges,omitempty"`
 }
 
+// MaintenanceWindow is a recurring window of time during which the operator
+// may make disruptive changes.
+type MaintenanceWindow struct {
+	// Schedule is a cron schedule, in the standard 5-field format
+	// (e.g. "0 2 * * 6"), for when the window opens.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open each time it opens.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the time zone in the IANA Time Zone database
+	// (e.g. "America/New_York") in which the schedule is interpreted.
+	//
+	// Default: UTC
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// VitessScaleProfile changes the number of replicas on a schedule, such as to
+// run more replicas during business hours than overnight.
+type VitessScaleProfile struct {
+	// Name identifies the profile in status and events.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule is a cron schedule, in the standard 5-field format
+	// (e.g. "0 9 * * 1-5"), for when the profile takes effect.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the profile stays in effect each time it starts.
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the name of the time zone in the IANA Time Zone database
+	// (e.g. "America/New_York") in which the schedule is interpreted.
+	//
+	// Default: UTC
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Replicas is the number of replicas to run while the profile is in
+	// effect, in place of the usual number.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+// VitessCanaryUpdateStrategy configures canary upgrades of the vttablet image.
+type VitessCanaryUpdateStrategy struct {
+	// Replicas is the number of tablets in each shard to upgrade first. The
+	// canaries are chosen from the non-primary tablets in replica-type pools.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// SoakPeriod is how long the canary tablets must stay healthy on the new
+	// image before the rest of the shard is upgraded. A canary is healthy
+	// while it's Available, replicating, and has no errant GTIDs. If a canary
+	// becomes unhealthy, the soak period starts over once it recovers.
+	//
+	// Default: 10m
+	SoakPeriod *metav1.Duration `json:"soakPeriod,omitempty"`
+}
+
+// VitessUpgradeChecks configures the safety checks that must pass before a
+// new vttablet or mysqld image is rolled out to a shard. The shard must also
+// not be part of a resharding operation that's in progress.
+type VitessUpgradeChecks struct {
+	// MaxBackupAge is how old the latest complete backup of the shard may be.
+	// Set to 0 to skip this check.
+	//
+	// Default: 24h
+	MaxBackupAge *metav1.Duration `json:"maxBackupAge,omitempty"`
+
+	// MaxReplicationLag is how far behind the primary any replica may be.
+	// Delayed replicas are left out, since they lag on purpose.
+	//
+	// Default: 30s
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// MinHealthyReplicas is how many non-primary tablets in replica-type pools
+	// must be Available and replicating.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=0
+	MinHealthyReplicas *int32 `json:"minHealthyReplicas,omitempty"`
+}
+
+// VitessRolloutBackoff configures how rollouts back off from updated tablet
+// Pods that keep crashing.
+type VitessRolloutBackoff struct {
+	// RestartThreshold is how many times a container of an updated tablet
+	// Pod may restart while it's in CrashLoopBackOff before the rollout is
+	// considered stuck.
+	//
+	// Default: 3
+	// +kubebuilder:validation:Minimum=1
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+
+	// InitialDelay is how long to wait after the first stuck Pod recovers
+	// before updating the next tablet. The delay doubles each time the
+	// rollout gets stuck again, until the rollout is complete.
+	//
+	// Default: 1m
+	InitialDelay *metav1.Duration `json:"initialDelay,omitempty"`
+
+	// MaxDelay is the longest that the delay can grow to.
+	//
+	// Default: 30m
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+// ReparentSettings can be used to tune the timeouts the operator uses when it
+// performs planned reparents. This should only be necessary for clusters with
+// unusually large transactions or slow (e.g. cross-region) replication.
+type ReparentSettings struct {
+	// PlannedReparentTimeout is the maximum time to wait for a single planned
+	// reparent (PlannedReparentShard) to complete.
+	// Default: 30s
+	PlannedReparentTimeout *metav1.Duration `json:"plannedReparentTimeout,omitempty"`
+
+	// ReconcileDrainTimeout is the maximum time the operator will spend on a
+	// single pass of processing drains for a shard, including any planned
+	// reparent it performs. It should be larger than PlannedReparentTimeout,
+	// or else planned reparents will be cut short.
+	// Default: 60s
+	ReconcileDrainTimeout *metav1.Duration `json:"reconcileDrainTimeout,omitempty"`
+
+	// TolerableReplicationLag is the maximum replication lag the candidate
+	// primary may have for a planned reparent to proceed.
+	// Default: 15s
+	TolerableReplicationLag *metav1.Duration `json:"tolerableReplicationLag,omitempty"`
+
+	// CellReplicationLagThresholds optionally maps cell names to the maximum
+	// replication lag that any replica in that cell may have for the operator
+	// to move the primary into that cell with a planned reparent.
+	//
+	// Before such a reparent, the operator checks the replication status of
+	// every replica in the candidate primary's cell. If any of them is lagging
+	// more than the threshold, or its lag can't be determined, the reparent is
+	// blocked, and the VitessShard's ReparentLagCheckPassed condition says why.
+	//
+	// Default: Replicas are only checked through tolerableReplicationLag.
+	CellReplicationLagThresholds map[string]metav1.Duration `json:"cellReplicationLagThresholds,omitempty"`
+
+	// AllowEmergencyFailover enables an emergency reparent (EmergencyReparentShard)
+	// away from a primary that has been requested to drain, but which has been
+	// unreachable (not Ready) for longer than EmergencyFailoverGracePeriod.
+	//
+	// Without this, drains in a shard are blocked until all its tablets are
+	// healthy, which can deadlock node drains when the primary's node is
+	// already down. When enabled, draining tablets that have been unreachable
+	// for longer than the grace period also no longer block other drains.
+	//
+	// WARNING: An emergency reparent may lose writes that were not yet
+	// replicated from the unreachable primary, depending on the shard's
+	// durability policy.
+	//
+	// Default: false
+	AllowEmergencyFailover *bool `json:"allowEmergencyFailover,omitempty"`
+
+	// EmergencyFailoverGracePeriod is how long a draining tablet must be
+	// unreachable before it's treated as lost when AllowEmergencyFailover is
+	// enabled.
+	// Default: 5m
+	EmergencyFailoverGracePeriod *metav1.Duration `json:"emergencyFailoverGracePeriod,omitempty"`
+
+	// DrainAbortGracePeriod is how long the operator waits for the drainer to
+	// clean up after an aborted drain before it clears the leftover
+	// acknowledged and finished annotations from tablet Pods itself, even if
+	// the shard is unhealthy.
+	//
+	// A drain is considered aborted when a tablet Pod still has those
+	// annotations, but no longer has a drain request. While that's the case,
+	// the VitessShard reports a DrainAborted condition.
+	// Default: 10m
+	DrainAbortGracePeriod *metav1.Duration `json:"drainAbortGracePeriod,omitempty"`
+
+	// DrainTimeout is how long a tablet's drain may stay acknowledged without
+	// finishing before it's considered stuck, for example because no other
+	// tablet is eligible to become primary. While any drain is stuck, the
+	// VitessShard reports a DrainStuck condition, and the operator emits
+	// DrainStuck events with the reason the drain is blocked.
+	// Set to 0 to disable this check.
+	// Default: 1h
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+
+	// PrimaryRotationSchedule is an optional cron schedule, in the standard
+	// 5-field format (e.g. "0 3 * * 0"), on which the operator rotates each
+	// shard's primary to a different tablet with a planned reparent. This
+	// keeps primaries from accumulating long uptimes on the same Pod, and
+	// regularly exercises the failover path.
+	//
+	// A primary is rotated once the first scheduled time after it became
+	// primary has passed. The new primary is chosen the same way as when the
+	// primary is drained. Rotation is skipped while the shard is unhealthy or
+	// any of its tablets are draining, and for shards that set a
+	// primaryAffinity. If the cluster has maintenanceWindows, rotation also
+	// waits for one to open.
+	//
+	// Default: Primaries are never rotated on a schedule.
+	PrimaryRotationSchedule string `json:"primaryRotationSchedule,omitempty"`
+}
+
 // TopoReconcileConfig can be used to turn on or off registration or pruning of specific vitess components from topo records.
 // This should only be necessary if you need to override defaults, and shouldn't be required for the vast majority of use cases.
 type TopoReconcileConfig struct {
@@ -210,6 +556,19 @@ type TopoReconcileConfig struct {
 	// PruneTablets can be used to enable or disable pruning of extraneous tablets from topo records.
 	// Default: true
 	PruneTablets *bool `json:"pruneTablets,omitempty"`
+
+	// RepairServingGraph can be used to enable or disable rebuilding SrvKeyspace
+	// and SrvVSchema records that the consistency check finds missing.
+	// Default: true
+	RepairServingGraph *bool `json:"repairServingGraph,omitempty"`
+
+	// ConsistencyCheckInterval is how often the VitessCluster controller
+	// compares topo records against the resources the operator manages,
+	// repairs what it's allowed to, and reports the rest in the
+	// VitessCluster status. Set to 0 to turn off the check.
+	// This is only used by the VitessCluster controller.
+	// Default: 10m
+	ConsistencyCheckInterval *metav1.Duration `json:"consistencyCheckInterval,omitempty"`
 }
 
 // VitessImages specifies container images to use for Vitess components.
@@ -254,6 +613,10 @@ type MysqldImage struct {
 	// Mysql80Compatible is a container image (including version tag) for mysqld
 	// that's compatible with the Vitess "MySQL80" flavor setting.
 	Mysql80Compatible string `json:"mysql80Compatible,omitempty"`
+	// Mysql84Compatible is a container image (including version tag) for mysqld
+	// that runs MySQL 8.4 LTS or Percona Server 8.4. Vitess uses its "MySQL80"
+	// flavor setting for these.
+	Mysql84Compatible string `json:"mysql84Compatible,omitempty"`
 	// MariadbCompatible is a container image (including version tag) for mysqld
 	// that's compatible with the Vitess "MariaDB" flavor setting.
 	MariadbCompatible string `json:"mariadbCompatible,omitempty"`
@@ -298,16 +661,241 @@ type ClusterBackupSpec struct {
 	// were originally taken.
 	// +kubebuilder:validation:MinItems=1
 	Locations []VitessBackupLocation `json:"locations"`
-	// Engine specifies the Vitess backup engine to use, either "builtin" or "xtrabackup".
+	// Engine specifies the Vitess backup engine to use, either "builtin", "xtrabackup",
+	// or "mysqlshell".
 	// Note that if you change this after a Vitess cluster is already deployed,
 	// you must roll the change out to all tablets and then take a new backup
 	// from one tablet in each shard. Otherwise, new tablets trying to restore
 	// will find that the latest backup was created with the wrong engine.
+	//
+	// The "mysqlshell" engine requires a Vitess version that supports it, and
+	// vttablet and vtbackup images that include the mysqlsh binary. It can
+	// only store backups in S3 or volume backup locations.
 	// Default: builtin
-	// +kubebuilder:validation:Enum=builtin;xtrabackup
+	// +kubebuilder:validation:Enum=builtin;xtrabackup;mysqlshell
 	Engine VitessBackupEngine `json:"engine,omitempty"`
 	// Subcontroller specifies any parameters needed for launching the VitessBackupStorage subcontroller pod.
 	Subcontroller *VitessBackupSubcontrollerSpec `json:"subcontroller,omitempty"`
+	// Schedule optionally configures the operator to take periodic backups
+	// of every shard in the cluster. Keyspaces can override this with their
+	// own backupSchedule.
+	//
+	// Default: The operator only takes the initial backup of each shard.
+	Schedule *VitessBackupScheduleSpec `json:"schedule,omitempty"`
+	// Retention optionally configures the operator to delete old backups
+	// from every storage location, so storage doesn't grow without bound.
+	//
+	// Default: Backups are never deleted.
+	Retention *VitessBackupRetentionSpec `json:"retention,omitempty"`
+	// Vtbackup optionally configures where the vtbackup Pods that take
+	// backups are scheduled, and what resources they get.
+	//
+	// Default: vtbackup Pods inherit the tolerations, annotations, and mysqld
+	// resources of the tablet pool whose backups they take.
+	Vtbackup *VitessBackupPodSpec `json:"vtbackup,omitempty"`
+	// Verification optionally configures the operator to periodically check
+	// that the latest backup of each shard can actually be restored.
+	//
+	// Default: Backups are not verified.
+	Verification *VitessBackupVerificationSpec `json:"verification,omitempty"`
+	// SourceTabletPolicy optionally configures which tablet pool of each
+	// shard the vtbackup Pods that take backups are modeled on.
+	//
+	// Default: Use the first tablet pool that stores backups in the location.
+	SourceTabletPolicy *VitessBackupSourceTabletPolicy `json:"sourceTabletPolicy,omitempty"`
+	// Replication optionally configures the operator to copy completed
+	// backups from one location to others, such as a different region or
+	// storage provider, so they can still be restored if the original
+	// location becomes unavailable.
+	//
+	// Default: Backups are only stored in the location where they were taken.
+	Replication *VitessBackupReplicationSpec `json:"replication,omitempty"`
+}
+
+// VitessInitialRestoreSpec specifies where to find the backups that a new
+// cluster's shards are initialized from.
+type VitessInitialRestoreSpec struct {
+	// BackupLocationName is the name of the location, among those defined
+	// in the cluster's backup spec, that contains the backups to restore.
+	//
+	// Default: The backup location with an empty name.
+	BackupLocationName string `json:"backupLocationName,omitempty"`
+
+	// ClusterName is the name of the VitessCluster that took the backups.
+	// Backups are stored under the name of the cluster that took them, so
+	// this must be set to restore backups of a different cluster.
+	//
+	// Default: The name of this cluster.
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
+// VitessStandbySpec configures how a standby cluster follows another cluster.
+type VitessStandbySpec struct {
+	// BackupLocationName is the name of the location, among those defined
+	// in the cluster's backup spec, that contains the other cluster's backups.
+	//
+	// Default: The backup location with an empty name.
+	BackupLocationName string `json:"backupLocationName,omitempty"`
+
+	// ClusterName is the name of the VitessCluster to follow, as it appears
+	// in the paths of its backups.
+	//
+	// Default: The name of this cluster.
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// RefreshInterval is how old a standby tablet may get before it's
+	// recreated, which restores it from the latest backup. Tablets in each
+	// shard are refreshed one at a time, and only while all other tablets
+	// in the shard are up. Set to 0 to never refresh tablets.
+	//
+	// Default: 24h
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// VitessBackupScheduleSpec configures periodic backups of each shard.
+//
+// Each backup is taken by a vtbackup Pod, which restores the latest backup,
+// catches up on replication, and then stores a new backup in the same
+// location, without affecting any serving tablets.
+type VitessBackupScheduleSpec struct {
+	// Schedule is a cron schedule, in the standard 5-field format
+	// (e.g. "0 3 * * *"), on which to back up each shard. A new backup is
+	// started in each backup location once the first scheduled time after
+	// that location's latest complete backup has passed.
+	Schedule string `json:"schedule"`
+
+	// Jitter is the maximum extra delay after each scheduled time before a
+	// shard's backup is started. Each shard is given a fixed delay within
+	// this range, so backups of many shards are spread out instead of all
+	// starting at once.
+	//
+	// Default: No jitter.
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+
+	// MaxConcurrentShards is the maximum number of shards in each keyspace
+	// that may have a scheduled backup running at the same time. Shards that
+	// would exceed the limit wait until another shard's backup finishes.
+	//
+	// Default: No limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentShards *int32 `json:"maxConcurrentShards,omitempty"`
+}
+
+// VitessBackupVerificationSpec configures periodic verification of backups.
+//
+// Each verification is done by a throwaway Pod, scheduled like a vtbackup Pod,
+// that restores the latest complete backup in a backup location into a scratch
+// volume, starts mysqld on the result, and optionally checksums some tables.
+// The outcome is recorded in the Verified condition of the VitessBackup object.
+type VitessBackupVerificationSpec struct {
+	// Schedule is a cron schedule, in the standard 5-field format
+	// (e.g. "0 6 * * *"), on which to verify backups. The latest complete
+	// backup in each location is verified once the first scheduled time after
+	// its last verification, or after it finished if it was never verified,
+	// has passed.
+	Schedule string `json:"schedule"`
+
+	// ChecksumTables is a list of tables, each in the form "keyspace.table",
+	// on which to run CHECKSUM TABLE after restoring a backup of a shard in
+	// that keyspace. Verification fails if any of the tables is missing.
+	//
+	// Default: Only check that mysqld starts on the restored data.
+	ChecksumTables []string `json:"checksumTables,omitempty"`
+}
+
+// VitessBackupPodSpec configures the vtbackup Pods created to take backups,
+// as well as the Pods that verify backups.
+//
+// vtbackup Pods restore and replay a whole shard, so they can need as much
+// memory as a tablet. Use these fields to keep them off the nodes that run
+// serving tablets, or to give them resources of their own.
+type VitessBackupPodSpec struct {
+	// Resources specify the compute resources to allocate for the vtbackup
+	// container, which runs both vtbackup and mysqld.
+	//
+	// Default: The mysqld resources of the tablet pool being backed up.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Affinity allows you to set rules that constrain the scheduling of
+	// vtbackup Pods.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// NodeSelector restricts vtbackup Pods to nodes with matching labels.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allow you to schedule vtbackup Pods onto nodes with
+	// matching taints. If set, these replace the tolerations of the tablet
+	// pool being backed up.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass to assign to
+	// vtbackup Pods.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Annotations can optionally be used to attach custom annotations to
+	// vtbackup Pods, in addition to those of the tablet pool and backup
+	// location.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// VitessBackupSourceTabletPolicy configures which tablet pool of each shard
+// backups are taken on behalf of.
+//
+// Backups are never taken from a serving tablet, including the primary.
+// Instead, a vtbackup Pod restores the latest backup into a mysqld of its own,
+// catches up by replicating from the primary, and uploads a new backup. The
+// tablet pool it's modeled on decides which cell the vtbackup Pod runs in,
+// along with its tolerations, annotations, and default resources. Picking a
+// pool in the same cell as the primary and the backup storage avoids
+// cross-region replication and egress costs.
+type VitessBackupSourceTabletPolicy struct {
+	// PreferredCells is a list of cells, in order of preference, in which to
+	// run vtbackup Pods. A tablet pool in the earliest listed cell is chosen
+	// over any other, regardless of its type.
+	//
+	// Default: No preference for any cell.
+	PreferredCells []string `json:"preferredCells,omitempty"`
+
+	// PreferredType is the type of tablet pool to prefer among those in
+	// the same cell, either "replica" or "rdonly".
+	//
+	// Default: No preference for any type.
+	// +kubebuilder:validation:Enum=replica;rdonly
+	PreferredType VitessTabletPoolType `json:"preferredType,omitempty"`
+
+	// ExcludePrimaryEligible prevents vtbackup Pods from being modeled on
+	// tablet pools whose tablets can become primary ("replica" and
+	// "externalmaster" pools). If a shard has no other pool for a backup
+	// location, the operator doesn't take scheduled backups in it.
+	//
+	// Default: Primary-eligible pools may be chosen.
+	ExcludePrimaryEligible bool `json:"excludePrimaryEligible,omitempty"`
+}
+
+// VitessBackupReplicationSpec configures copying of completed backups from
+// one backup location to others.
+//
+// For each shard, the latest complete backup in the source location is copied
+// to every destination location that doesn't already have it. The progress of
+// each copy is tracked in the Replicated condition of the VitessBackup object
+// for the source backup. Once copied, a backup is an ordinary backup in each
+// destination location, so tablet pools that use a destination location can
+// restore from it even if the source location is down.
+type VitessBackupReplicationSpec struct {
+	// SourceLocationName is the name of the backup location to copy backups
+	// from.
+	//
+	// Default: The backup location with an empty name.
+	SourceLocationName string `json:"sourceLocationName,omitempty"`
+
+	// DestinationLocationNames are the names of the backup locations to copy
+	// backups to. Each must be a different location than the source.
+	// +kubebuilder:validation:MinItems=1
+	DestinationLocationNames []string `json:"destinationLocationNames"`
 }
 
 // VitessBackupEngine is the backup implementation to use.
@@ -318,6 +906,8 @@ const (
 	VitessBackupEngineBuiltIn VitessBackupEngine = "builtin"
 	// VitessBackupEngineXtraBackup uses Percona XtraBackup for backups.
 	VitessBackupEngineXtraBackup VitessBackupEngine = "xtrabackup"
+	// VitessBackupEngineMySQLShell uses MySQL Shell's dump and load utilities for backups.
+	VitessBackupEngineMySQLShell VitessBackupEngine = "mysqlshell"
 )
 
 // LockserverSpec specifies either a deployed or external lockserver,
@@ -334,12 +924,68 @@ type LockserverSpec struct {
 	// CellInfoAddress is the host:port of topology service which will be saved to cell info.
 	// Default: etcd client service.
 	CellInfoAddress string `json:"cellInfoAddress,omitempty"`
+
+	// Migration moves the topology data to another lockserver and then
+	// switches Vitess components over to it. The lockserver selected by
+	// External or Etcd remains the source of the migration until the
+	// switch over, so keep it running until then.
+	// Migration is only supported for the global lockserver. Cells that
+	// don't define their own lockserver move along with it.
+	Migration *LockserverMigration `json:"migration,omitempty"`
+}
+
+// LockserverMigration specifies a move of topology data to another lockserver.
+type LockserverMigration struct {
+	// Target is the lockserver to move to. It must be running and reachable
+	// before the migration starts, since the operator doesn't deploy it.
+	Target VitessLockserverParams `json:"target"`
+
+	// SwitchOver tells the operator to point Vitess components at the Target
+	// once the data has been copied there. The operator copies the data one
+	// more time right before switching, but changes made to the source while
+	// components are restarting can still be lost, so avoid reparents,
+	// resharding and VSchema changes until the rollout is done.
+	//
+	// To finish the migration, set External to the Target and remove
+	// Migration. Components won't restart again since nothing changes for them.
+	// Setting SwitchOver back to false instead points components back at the
+	// source, without copying back any changes made in the meantime.
+	SwitchOver bool `json:"switchOver,omitempty"`
 }
 
 // LockserverStatus is the lockserver component of status.
 type LockserverStatus struct {
 	// Etcd is the status of the EtcdCluster, if we were asked to deploy one.
 	Etcd *EtcdLockserverStatus `json:"etcd,omitempty"`
+
+	// Migration is the progress of the lockserver migration, if one was requested.
+	Migration *LockserverMigrationStatus `json:"migration,omitempty"`
+}
+
+// LockserverMigrationPhase is the progress of a lockserver migration.
+type LockserverMigrationPhase string
+
+const (
+	// LockserverMigrationCopying means the data hasn't been copied to the target yet.
+	LockserverMigrationCopying LockserverMigrationPhase = "Copying"
+	// LockserverMigrationCopied means the data has been copied to the target,
+	// but Vitess components still use the source.
+	LockserverMigrationCopied LockserverMigrationPhase = "Copied"
+	// LockserverMigrationSwitchedOver means Vitess components have been
+	// pointed at the target.
+	LockserverMigrationSwitchedOver LockserverMigrationPhase = "SwitchedOver"
+)
+
+// LockserverMigrationStatus is the status of a lockserver migration.
+type LockserverMigrationStatus struct {
+	// Target is the lockserver the data is being moved to.
+	Target VitessLockserverParams `json:"target"`
+	// Phase is the progress of the migration.
+	Phase LockserverMigrationPhase `json:"phase,omitempty"`
+	// LastCopyTime is when the data was last copied to the target successfully.
+	LastCopyTime *metav1.Time `json:"lastCopyTime,omitempty"`
+	// Message explains why the last copy failed, if it did.
+	Message string `json:"message,omitempty"`
 }
 
 // VitessLockserverParams contains only the values that Vitess needs
@@ -429,6 +1075,73 @@ type VitessDashboardSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodDisruptionBudget can optionally be used to customize the
+	// PodDisruptionBudget (PDB) that the operator manages for vtctld Pods in each cell.
+	//
+	// Default: A PDB that lets only one Pod be evicted at a time.
+	PodDisruptionBudget *VitessPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// GRPC configures secure access to the vtctld gRPC API through the
+	// vtctld Service, so people and CI jobs can use clients like
+	// vtctldclient without port-forwarding to an insecure endpoint.
+	//
+	// Default: gRPC is served in plaintext without authentication.
+	GRPC *VitessDashboardGRPC `json:"grpc,omitempty"`
+}
+
+// VitessDashboardGRPC configures access to the vtctld gRPC API.
+type VitessDashboardGRPC struct {
+	// TLS configures vtctld to serve gRPC over TLS, and to only accept
+	// clients that present a certificate signed by the trusted certificate
+	// authority (mutual TLS).
+	//
+	// Note that other components that call vtctld, such as vtadmin, don't
+	// present client certificates, so they can't reach vtctld while this
+	// is set.
+	TLS *VitessDashboardTLS `json:"tls,omitempty"`
+
+	// Authentication configures vtctld to also require gRPC clients to
+	// authenticate with a username and password.
+	Authentication *VitessDashboardAuthentication `json:"authentication,omitempty"`
+}
+
+// VitessDashboardTLS configures TLS for the vtctld gRPC API.
+type VitessDashboardTLS struct {
+	// CertSecretName is the name of a Secret that holds the vtctld server
+	// certificate in tls.crt, its key in tls.key, and the certificate
+	// authority that client certificates must be signed by in ca.crt.
+	// This is the layout of the Secrets that cert-manager issues
+	// Certificates into. vtctld is restarted when the Secret changes.
+	//
+	// Default: The operator mints a certificate authority, a server
+	// certificate, and a client certificate, and renews the certificates
+	// before they expire. The name of the Secret with the client
+	// certificate is reported in status.
+	CertSecretName string `json:"certSecretName,omitempty"`
+
+	// DNSNames are extra names to include in the server certificate
+	// minted by the operator, such as an external name for the vtctld
+	// Service. The in-cluster names of the Service are always included.
+	// This is ignored if certSecretName is set.
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// VitessDashboardAuthentication configures authentication for the vtctld
+// gRPC API.
+type VitessDashboardAuthentication struct {
+	// Static configures vtctld to use a static file containing usernames
+	// and passwords.
+	Static *VitessDashboardStaticAuthentication `json:"static,omitempty"`
+}
+
+// VitessDashboardStaticAuthentication configures static file authentication
+// for the vtctld gRPC API.
+type VitessDashboardStaticAuthentication struct {
+	// Secret configures vtctld to load the static auth file from a given key
+	// in a given Secret. The file is a JSON list of objects with Username and
+	// Password fields.
+	Secret *SecretSource `json:"secret,omitempty"`
 }
 
 // VtAdminSpec specifies deployment parameters for vtadmin.
@@ -447,7 +1160,9 @@ type VtAdminSpec struct {
 	// Either there should be only 1 element in the list
 	// which is used by all the vtadmin-web deployments
 	// or it should match the length of the Cells list
-	APIAddresses []string `json:"apiAddresses"`
+	//
+	// Default: The address of the Ingress, if one is configured.
+	APIAddresses []string `json:"apiAddresses,omitempty"`
 
 	// Replicas is the number of vtadmin instances to deploy in each cell.
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -522,12 +1237,76 @@ type VtAdminSpec struct {
 	// Service can optionally be used to customize the vtadmin Service.
 	Service *ServiceOverrides `json:"service,omitempty"`
 
+	// Ingress can optionally be used to expose vtadmin outside the
+	// Kubernetes cluster through an Ingress.
+	Ingress *VtAdminIngress `json:"ingress,omitempty"`
+
 	// Tolerations allow you to schedule pods onto nodes with matching taints.
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
+// VtAdminIngress configures an Ingress for vtadmin. Requests for paths under
+// /api go to vtadmin-api, and everything else goes to vtadmin-web.
+type VtAdminIngress struct {
+	// Host is the hostname at which vtadmin is served.
+	Host string `json:"host"`
+
+	// ClassName is the name of the IngressClass that should serve the Ingress.
+	//
+	// Default: The default IngressClass of the Kubernetes cluster.
+	ClassName *string `json:"className,omitempty"`
+
+	// TLSSecretName is the name of a Secret of type kubernetes.io/tls that
+	// holds the certificate for Host. vtadmin doesn't serve TLS itself, so
+	// TLS is terminated at the Ingress.
+	//
+	// Default: Serve plain HTTP.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations specifies extra annotations to add to the Ingress object,
+	// which many Ingress controllers use for configuration.
+	// Annotations added in this way will NOT be automatically removed from the
+	// Ingress object if they are removed here.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// VitessZoneSpread spreads a group of Pods evenly across zones, or across
+// any other domain that Nodes are labeled with, without hand-written
+// topologySpreadConstraints.
+type VitessZoneSpread struct {
+	// TopologyKey is the Node label whose values are the domains to spread
+	// Pods across.
+	//
+	// Default: topology.kubernetes.io/zone
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// MaxSkew is the most by which the number of Pods in any two domains
+	// may differ.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew *int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable is what to do with a Pod that can't be placed
+	// without exceeding maxSkew: "DoNotSchedule" leaves it Pending, while
+	// "ScheduleAnyway" places it where it increases the skew the least.
+	//
+	// Default: DoNotSchedule
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+
+	// MinZones is the number of domains that Pods should be spread across,
+	// even while fewer domains have Nodes that fit them. Pods that would go
+	// over maxSkew in too few domains stay Pending until more domains have
+	// room. This only has an effect if whenUnsatisfiable is DoNotSchedule.
+	//
+	// Default: Only spread across domains that have Nodes that fit.
+	// +kubebuilder:validation:Minimum=1
+	MinZones *int32 `json:"minZones,omitempty"`
+}
+
 // ServiceOverrides allows customization of an arbitrary Service object.
 type ServiceOverrides struct {
 	// Annotations specifies extra annotations to add to the Service object.
@@ -542,12 +1321,41 @@ type ServiceOverrides struct {
 	ClusterIP string `json:"clusterIP,omitempty"`
 }
 
+// VitessPodDisruptionBudget configures the PodDisruptionBudget (PDB) that the
+// operator manages for the Pods of a component, so voluntary evictions, such
+// as during node upgrades, can't take down too many of them at once.
+//
+// Only one of MinAvailable and MaxUnavailable may be set.
+type VitessPodDisruptionBudget struct {
+	// Disabled turns off the PDB. If the operator created one before, it's
+	// deleted.
+	//
+	// Default: false
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MinAvailable is the number of Pods, or percentage of the desired
+	// replicas, that must stay available when Pods are evicted.
+	//
+	// Default: One less than the desired replicas.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number of Pods, or percentage of the desired
+	// replicas, that may be unavailable when Pods are evicted. If this is
+	// set, MinAvailable is ignored.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
 // VitessDashboardStatus is a summary of the status of the vtctld deployment.
 type VitessDashboardStatus struct {
 	// Available indicates whether the vtctld service has available endpoints.
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ServiceName is the name of the Service for this cluster's vtctld.
 	ServiceName string `json:"serviceName,omitempty"`
+	// GRPCClientCertSecretName is the name of the Secret that holds the
+	// client certificate the operator minted for the vtctld gRPC API, if
+	// any. The certificate is in tls.crt, its key is in tls.key, and the
+	// certificate authority of the vtctld server certificate is in ca.crt.
+	GRPCClientCertSecretName string `json:"grpcClientCertSecretName,omitempty"`
 }
 
 // VtadminStatus is a summary of the status of the vtadmin deployment.
@@ -584,6 +1392,137 @@ type VitessClusterStatus struct {
 	OrphanedCells map[string]OrphanStatus `json:"orphanedCells,omitempty"`
 	// OrphanedKeyspaces is a list of unwanted keyspaces that could not be turned down.
 	OrphanedKeyspaces map[string]OrphanStatus `json:"orphanedKeyspaces,omitempty"`
+
+	// RevisionHistory lists the most recent revisions of the cluster-wide
+	// images and extraVitessFlags that were applied, newest first.
+	RevisionHistory []VitessClusterRevision `json:"revisionHistory,omitempty"`
+	// RolledBackTo is the revision that the cluster is currently rolled back
+	// to, if spec.rollbackTo is set to a revision that was found.
+	RolledBackTo *int64 `json:"rolledBackTo,omitempty"`
+
+	// RolloutStatus is a roll-up of how far along the rollout of the latest
+	// changes is, for each component and keyspace.
+	RolloutStatus VitessClusterRolloutStatus `json:"rolloutStatus,omitempty"`
+
+	// TopologyConsistency is the outcome of the last periodic comparison of
+	// topo records against the resources the operator manages.
+	TopologyConsistency *TopologyConsistencyStatus `json:"topologyConsistency,omitempty"`
+
+	// Members lists the member VitessClusters that deploy the cells placed
+	// in other namespaces or Kubernetes clusters, including members that are
+	// being turned down.
+	Members []VitessClusterMemberStatus `json:"members,omitempty"`
+}
+
+// VitessClusterMemberStatus is the status of a member VitessCluster that
+// deploys placed cells.
+type VitessClusterMemberStatus struct {
+	// Namespace is the namespace of the member VitessCluster.
+	Namespace string `json:"namespace"`
+	// KubeconfigSecret is the kubeconfig for the Kubernetes cluster of the
+	// member VitessCluster, if it's not the same cluster.
+	KubeconfigSecret *SecretSource `json:"kubeconfigSecret,omitempty"`
+	// Cells lists the cells that the member still deploys, including cells
+	// it's in the process of removing.
+	Cells []string `json:"cells,omitempty"`
+	// Synced indicates whether the member VitessCluster was last updated
+	// successfully.
+	Synced corev1.ConditionStatus `json:"synced,omitempty"`
+	// Message explains why the member isn't synced, if it isn't.
+	Message string `json:"message,omitempty"`
+}
+
+// TopologyConsistencyStatus is the outcome of comparing topo records against
+// the resources the operator manages.
+type TopologyConsistencyStatus struct {
+	// LastCheckTime is when the topo records were last compared.
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Consistent indicates whether the last check found no drift that was
+	// left unrepaired.
+	Consistent corev1.ConditionStatus `json:"consistent,omitempty"`
+	// Repaired is the number of records the last check pruned or rebuilt.
+	Repaired int32 `json:"repaired,omitempty"`
+	// Drift lists the topo records that the last check found out of line
+	// with the resources the operator manages, and couldn't or wasn't
+	// allowed to repair. The list is truncated if it's very long.
+	Drift []TopologyDrift `json:"drift,omitempty"`
+}
+
+// TopologyRecordKind is the kind of a topo record.
+type TopologyRecordKind string
+
+const (
+	// TopologyCellRecord is a CellInfo record.
+	TopologyCellRecord TopologyRecordKind = "Cell"
+	// TopologyShardRecord is a Shard record.
+	TopologyShardRecord TopologyRecordKind = "Shard"
+	// TopologyTabletRecord is a Tablet record.
+	TopologyTabletRecord TopologyRecordKind = "Tablet"
+	// TopologySrvKeyspaceRecord is a SrvKeyspace record in a cell.
+	TopologySrvKeyspaceRecord TopologyRecordKind = "SrvKeyspace"
+	// TopologySrvVSchemaRecord is a SrvVSchema record in a cell.
+	TopologySrvVSchemaRecord TopologyRecordKind = "SrvVSchema"
+)
+
+// TopologyDrift describes a topo record that doesn't match the resources the
+// operator manages.
+type TopologyDrift struct {
+	// Kind is the kind of topo record.
+	Kind TopologyRecordKind `json:"kind"`
+	// Name identifies the record, such as a cell name, "keyspace/shard", a
+	// tablet alias, or "cell/keyspace" for a SrvKeyspace.
+	Name string `json:"name"`
+	// Message explains what's wrong with the record.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessClusterRolloutStatus is a roll-up of how far along the rollout of the
+// latest changes to a VitessCluster is.
+type VitessClusterRolloutStatus struct {
+	// ObservedGeneration is the generation of the VitessCluster that these
+	// counts reflect. It only advances once every cell and keyspace has
+	// observed the latest changes, so the counts may be stale until then.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Complete indicates whether every component is up-to-date.
+	// It's Unknown while some cell or keyspace hasn't observed the latest
+	// changes yet.
+	Complete corev1.ConditionStatus `json:"complete,omitempty"`
+	// Vtctld is the rollout progress of vtctld Pods.
+	Vtctld VitessRolloutCounts `json:"vtctld,omitempty"`
+	// Vtgate is the rollout progress of vtgate Pods in all cells.
+	Vtgate VitessRolloutCounts `json:"vtgate,omitempty"`
+	// Vttablet is the rollout progress of tablet Pods in all keyspaces.
+	Vttablet VitessRolloutCounts `json:"vttablet,omitempty"`
+	// Keyspaces is the rollout progress of tablet Pods in each keyspace.
+	Keyspaces map[string]VitessRolloutCounts `json:"keyspaces,omitempty"`
+}
+
+// VitessRolloutCounts counts Pods of some component by how far along they are
+// in a rollout.
+type VitessRolloutCounts struct {
+	// Desired is the number of Pods there should be.
+	Desired int32 `json:"desired,omitempty"`
+	// Updated is the number of Pods that are up-to-date.
+	Updated int32 `json:"updated,omitempty"`
+	// Pending is the number of desired Pods that are still waiting to be
+	// updated or created.
+	Pending int32 `json:"pending,omitempty"`
+	// Failed is the number of Pods that were updated, but failed to come up.
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// VitessClusterRevision is a record of the cluster-wide images and
+// extraVitessFlags that were applied at some point.
+type VitessClusterRevision struct {
+	// Revision is the number of this revision, which can be used in
+	// spec.rollbackTo. Revision numbers only go up.
+	Revision int64 `json:"revision"`
+	// Time is when this revision was first applied.
+	Time metav1.Time `json:"time"`
+	// Images are the images that were applied in this revision.
+	Images VitessImages `json:"images,omitempty"`
+	// ExtraVitessFlags are the extra Vitess flags that were applied in this revision.
+	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 }
 
 // NewVitessClusterStatus creates a new status object with default values.
@@ -19,6 +19,8 @@ package v2
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 // DefaultVitessKeyspace fills in VitessKeyspace defaults for unspecified fields.
@@ -29,6 +31,11 @@ func DefaultVitessKeyspace(dst *VitessKeyspace) {
 	DefaultVitessOrchestrator(&dst.Spec.VitessOrchestrator)
 	DefaultTopoReconcileConfig(&dst.Spec.TopologyReconciliation)
 	DefaultUpdateStrategy(&dst.Spec.UpdateStrategy)
+	DefaultReparentSettings(&dst.Spec.ReparentSettings)
+	DefaultVitessMaterializations(dst.Spec.Materializations, dst.Spec.Name)
+	DefaultVitessAutoReshardPolicy(dst.Spec.AutoReshard)
+	DefaultVitessKeyspaceDecommissionPolicy(&dst.Spec.Decommission, dst.Spec.TurndownPolicy)
+	DefaultVitessKeyspaceSchema(dst.Spec.Schema)
 }
 
 func DefaultVitessOrchestrator(vtorc **VitessOrchestratorSpec) {
@@ -36,6 +43,12 @@ func DefaultVitessOrchestrator(vtorc **VitessOrchestratorSpec) {
 	if *vtorc == nil {
 		*vtorc = &VitessOrchestratorSpec{}
 	}
+	if (*vtorc).Scope == "" {
+		(*vtorc).Scope = VitessOrchestratorScopeShard
+	}
+	if (*vtorc).Replicas == nil {
+		(*vtorc).Replicas = pointer.Int32Ptr(defaultVtorcReplicas)
+	}
 	if len((*vtorc).Resources.Requests) == 0 {
 		(*vtorc).Resources.Requests = corev1.ResourceList{
 			corev1.ResourceCPU:    *resource.NewMilliQuantity(defaultVtorcCPUMillis, resource.DecimalSI),
@@ -50,6 +63,71 @@ func DefaultVitessOrchestrator(vtorc **VitessOrchestratorSpec) {
 	DefaultServiceOverrides(&(*vtorc).Service)
 }
 
+// DefaultVitessMaterializations fills in defaults for the Materialize
+// workflows of the given keyspace.
+func DefaultVitessMaterializations(materializations []VitessMaterialization, keyspaceName string) {
+	for i := range materializations {
+		mz := &materializations[i]
+		if mz.SourceKeyspace == "" {
+			mz.SourceKeyspace = keyspaceName
+		}
+		if len(mz.TabletTypes) == 0 {
+			mz.TabletTypes = []string{"replica", "primary"}
+		}
+	}
+}
+
+// DefaultVitessAutoReshardPolicy fills in defaults for an autoReshard policy,
+// if there is one.
+func DefaultVitessAutoReshardPolicy(policy *VitessAutoReshardPolicy) {
+	if policy == nil {
+		return
+	}
+	if policy.SustainedFor == nil {
+		policy.SustainedFor = &metav1.Duration{Duration: defaultAutoReshardSustainedFor}
+	}
+	if policy.SwitchTraffic == "" {
+		policy.SwitchTraffic = SwitchTrafficAuto
+	}
+}
+
+// DefaultVitessKeyspaceDecommissionPolicy fills in defaults for the
+// decommission policy, if the keyspace is turned down with one.
+func DefaultVitessKeyspaceDecommissionPolicy(policy **VitessKeyspaceDecommissionPolicy, turndownPolicy VitessKeyspaceTurndownPolicy) {
+	if turndownPolicy != VitessKeyspaceTurndownPolicyDecommission {
+		return
+	}
+	if *policy == nil {
+		*policy = &VitessKeyspaceDecommissionPolicy{}
+	}
+	if (*policy).HoldPeriod == nil {
+		(*policy).HoldPeriod = &metav1.Duration{Duration: defaultDecommissionHoldPeriod}
+	}
+}
+
+// DefaultVitessKeyspaceSchema fills in defaults for schema management, if
+// it's enabled.
+func DefaultVitessKeyspaceSchema(schema *VitessKeyspaceSchema) {
+	if schema == nil {
+		return
+	}
+	if schema.DDLStrategy == "" {
+		schema.DDLStrategy = DDLStrategyVitess
+	}
+	if schema.VSchemaApplyPolicy == "" {
+		schema.VSchemaApplyPolicy = VSchemaApplyAutomatic
+	}
+	for i := range schema.Sequences {
+		sequence := &schema.Sequences[i]
+		if sequence.Start == nil {
+			sequence.Start = pointer.Int64Ptr(defaultSequenceStart)
+		}
+		if sequence.Cache == nil {
+			sequence.Cache = pointer.Int64Ptr(defaultSequenceCache)
+		}
+	}
+}
+
 // DefaultVitessKeyspaceImages fills in unspecified keyspace-level images from cluster-level defaults.
 // The clusterDefaults should have already had its unspecified fields filled in with operator defaults.
 func DefaultVitessKeyspaceImages(dst *VitessKeyspaceImages, clusterDefaults *VitessImages) {
@@ -92,8 +92,9 @@ func (spec *VitessKeyspaceTemplate) ShardTemplates() []*VitessKeyspaceKeyRangeSh
 				}
 			}
 		case partitioning.Custom != nil:
-			for i := range partitioning.Custom.Shards {
-				krShard := &partitioning.Custom.Shards[i]
+			customShards := partitioning.Custom.ShardSpecs()
+			for i := range customShards {
+				krShard := &customShards[i]
 				shardMap[krShard.KeyRange] = krShard
 			}
 		default:
@@ -117,6 +118,54 @@ func (spec *VitessKeyspaceTemplate) ShardTemplates() []*VitessKeyspaceKeyRangeSh
 	return shards
 }
 
+// EffectiveDurabilityPolicy returns the durability policy that the keyspace
+// should have in topology, or an empty string if it shouldn't be set.
+func (s *VitessKeyspaceSpec) EffectiveDurabilityPolicy() string {
+	if s.DurabilityPolicy != "" {
+		return s.DurabilityPolicy
+	}
+	for _, shard := range s.ShardTemplates() {
+		if durabilityPolicy := shard.Replication.SemiSyncDurability(); durabilityPolicy != "" {
+			return durabilityPolicy
+		}
+	}
+	return ""
+}
+
+// DurabilityProblems returns the reasons, if any, that the tablet pools of
+// the keyspace's shards can't satisfy its effective durability policy.
+func (s *VitessKeyspaceSpec) DurabilityProblems() []string {
+	durabilityPolicy := s.EffectiveDurabilityPolicy()
+	var problems []string
+	for _, shard := range s.ShardTemplates() {
+		for _, problem := range shard.SemiSyncProblems(durabilityPolicy) {
+			problems = append(problems, fmt.Sprintf("shard %v: %v", shard.KeyRange.String(), problem))
+		}
+	}
+	return problems
+}
+
+// RemoveTabletPoolsInCells removes the tablet pools in the given cells from
+// every shard of every partitioning, and returns whether any were removed.
+func (spec *VitessKeyspaceTemplate) RemoveTabletPoolsInCells(cells sets.String) bool {
+	removed := false
+	for i := range spec.Partitionings {
+		partitioning := &spec.Partitionings[i]
+		if partitioning.Equal != nil {
+			removed = partitioning.Equal.ShardTemplate.RemoveTabletPoolsInCells(cells) || removed
+		}
+		if partitioning.Custom != nil {
+			for j := range partitioning.Custom.Shards {
+				removed = partitioning.Custom.Shards[j].RemoveTabletPoolsInCells(cells) || removed
+			}
+			if partitioning.Custom.ShardTemplate != nil {
+				removed = partitioning.Custom.ShardTemplate.RemoveTabletPoolsInCells(cells) || removed
+			}
+		}
+	}
+	return removed
+}
+
 // CellNames returns a sorted list of all cells in which any part of the keyspace
 // (any tablet pool of any shard) should be deployed.
 func (s *VitessKeyspaceSpec) CellNames() []string {
@@ -152,11 +201,108 @@ func (p *VitessKeyspacePartitioning) ShardNameSet() sets.String {
 			shard := &p.Custom.Shards[i]
 			shardNames.Insert(shard.KeyRange.String())
 		}
+		for i := range p.Custom.ShardRanges {
+			shardRange := &p.Custom.ShardRanges[i]
+			shardNames.Insert(shardRange.KeyRange.String())
+		}
 	}
 
 	return shardNames
 }
 
+// ShardSpecs returns all the shards of a custom partitioning: the ones listed
+// in Shards, and the ones listed in ShardRanges, which get a copy of the
+// ShardTemplate with their overrides applied.
+func (p *VitessKeyspaceCustomPartitioning) ShardSpecs() []VitessKeyspaceKeyRangeShard {
+	shards := make([]VitessKeyspaceKeyRangeShard, 0, len(p.Shards)+len(p.ShardRanges))
+	shards = append(shards, p.Shards...)
+	if p.ShardTemplate == nil {
+		return shards
+	}
+
+	for i := range p.ShardRanges {
+		shardRange := &p.ShardRanges[i]
+		shard := VitessKeyspaceKeyRangeShard{
+			KeyRange:            shardRange.KeyRange,
+			VitessShardTemplate: *p.ShardTemplate.DeepCopy(),
+		}
+		for j := range shardRange.TabletPoolOverrides {
+			shardRange.TabletPoolOverrides[j].Apply(shard.TabletPools)
+		}
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// Apply changes the settings of the matching tablet pool, if any.
+func (o *VitessTabletPoolOverride) Apply(pools []VitessShardTabletPool) {
+	for i := range pools {
+		pool := &pools[i]
+		if pool.Cell != o.Cell || pool.Type != o.Type || pool.Name != o.Name {
+			continue
+		}
+		if o.Replicas != nil {
+			pool.Replicas = *o.Replicas
+		}
+		if o.VttabletResources != nil {
+			pool.Vttablet.Resources = *o.VttabletResources.DeepCopy()
+		}
+		if pool.Mysqld != nil {
+			if o.MysqldResources != nil {
+				pool.Mysqld.Resources = *o.MysqldResources.DeepCopy()
+			}
+			if o.MysqldConfigOverrides != nil {
+				pool.Mysqld.ConfigOverrides = *o.MysqldConfigOverrides
+			}
+		}
+	}
+}
+
+// ProvisioningKeyRanges returns the key ranges of the shards in this
+// partitioning, in the order in which they should be created.
+func (p *VitessKeyspacePartitioning) ProvisioningKeyRanges() []VitessKeyRange {
+	var keyRanges []VitessKeyRange
+	var order VitessShardProvisioningOrder
+
+	switch {
+	case p.Equal != nil:
+		keyRanges = p.Equal.KeyRanges()
+		order = p.Equal.ProvisioningOrder
+	case p.Custom != nil:
+		for i := range p.Custom.Shards {
+			keyRanges = append(keyRanges, p.Custom.Shards[i].KeyRange)
+		}
+		for i := range p.Custom.ShardRanges {
+			keyRanges = append(keyRanges, p.Custom.ShardRanges[i].KeyRange)
+		}
+		order = p.Custom.ProvisioningOrder
+	}
+
+	SortKeyRanges(keyRanges)
+	if order == ShardProvisioningDescending {
+		for i, j := 0, len(keyRanges)-1; i < j; i, j = i+1, j-1 {
+			keyRanges[i], keyRanges[j] = keyRanges[j], keyRanges[i]
+		}
+	}
+	return keyRanges
+}
+
+// MaxConcurrentShardCreations returns how many shards of this partitioning
+// may be coming up at once, or 0 if there's no limit.
+func (p *VitessKeyspacePartitioning) MaxConcurrentShardCreations() int32 {
+	var limit *int32
+	switch {
+	case p.Equal != nil:
+		limit = p.Equal.MaxConcurrentShardCreations
+	case p.Custom != nil:
+		limit = p.Custom.MaxConcurrentShardCreations
+	}
+	if limit == nil {
+		return 0
+	}
+	return *limit
+}
+
 // TabletPools returns the list of tablet pools from whichever paritioning sub-field is defined.
 func (p *VitessKeyspacePartitioning) TabletPools() []VitessShardTabletPool {
 	if p.Equal != nil {
@@ -164,8 +310,9 @@ func (p *VitessKeyspacePartitioning) TabletPools() []VitessShardTabletPool {
 	}
 	if p.Custom != nil {
 		var pools []VitessShardTabletPool
-		for i := range p.Custom.Shards {
-			pools = append(pools, p.Custom.Shards[i].TabletPools...)
+		shards := p.Custom.ShardSpecs()
+		for i := range shards {
+			pools = append(pools, shards[i].TabletPools...)
 		}
 		return pools
 	}
@@ -184,8 +331,9 @@ func (p *VitessKeyspacePartitioning) TotalReplicas() int32 {
 	}
 	if p.Custom != nil {
 		var count int32
-		for shardIdx := range p.Custom.Shards {
-			shard := &p.Custom.Shards[shardIdx]
+		shards := p.Custom.ShardSpecs()
+		for shardIdx := range shards {
+			shard := &shards[shardIdx]
 			for poolIdx := range shard.TabletPools {
 				pool := &shard.TabletPools[poolIdx]
 				count += pool.Replicas
@@ -19,6 +19,8 @@ package v2
 import (
 	"reflect"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func TestTranslationToVitessKeyRange(t *testing.T) {
@@ -132,3 +134,199 @@ func TestVitessKeyspacePartitioningTotalReplicas(t *testing.T) {
 		t.Errorf("customPartitioning.TotalReplicas() = %v; want 6", got)
 	}
 }
+
+func TestVitessKeyspaceSpecEffectiveDurabilityPolicy(t *testing.T) {
+	semiSyncTemplate := VitessShardTemplate{
+		Replication: VitessReplicationSpec{Mode: SemiSyncReplicationMode, SemiSyncDurabilityPolicy: CrossCellDurabilityPolicy},
+	}
+	table := []struct {
+		name             string
+		durabilityPolicy string
+		template         VitessShardTemplate
+		want             string
+	}{
+		{
+			name: "async",
+			want: "",
+		},
+		{
+			name:     "semi-sync shard",
+			template: semiSyncTemplate,
+			want:     CrossCellDurabilityPolicy,
+		},
+		{
+			name:     "semi-sync shard without policy",
+			template: VitessShardTemplate{Replication: VitessReplicationSpec{Mode: SemiSyncReplicationMode}},
+			want:     SemiSyncDurabilityPolicy,
+		},
+		{
+			name:             "explicit policy",
+			durabilityPolicy: "none",
+			template:         semiSyncTemplate,
+			want:             "none",
+		},
+	}
+
+	for _, test := range table {
+		spec := VitessKeyspaceSpec{}
+		spec.DurabilityPolicy = test.durabilityPolicy
+		spec.Partitionings = []VitessKeyspacePartitioning{
+			{Equal: &VitessKeyspaceEqualPartitioning{Parts: 1, ShardTemplate: test.template}},
+		}
+		if got := spec.EffectiveDurabilityPolicy(); got != test.want {
+			t.Errorf("%v: EffectiveDurabilityPolicy() = %q; want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestVitessKeyspaceSpecDurabilityProblems(t *testing.T) {
+	oneCell := VitessShardTemplate{
+		TabletPools: []VitessShardTabletPool{{Cell: "zone1", Type: ReplicaPoolType, Replicas: 3}},
+	}
+	twoCells := VitessShardTemplate{
+		TabletPools: []VitessShardTabletPool{
+			{Cell: "zone1", Type: ReplicaPoolType, Replicas: 2},
+			{Cell: "zone2", Type: ReplicaPoolType, Replicas: 1},
+		},
+	}
+	table := []struct {
+		name             string
+		durabilityPolicy string
+		template         VitessShardTemplate
+		wantProblems     int
+	}{
+		{
+			name:     "no policy",
+			template: oneCell,
+		},
+		{
+			name:             "none",
+			durabilityPolicy: NoneDurabilityPolicy,
+			template:         VitessShardTemplate{},
+		},
+		{
+			name:             "semi_sync",
+			durabilityPolicy: SemiSyncDurabilityPolicy,
+			template:         oneCell,
+		},
+		{
+			name:             "cross_cell in one cell",
+			durabilityPolicy: CrossCellDurabilityPolicy,
+			template:         oneCell,
+			wantProblems:     2,
+		},
+		{
+			name:             "cross_cell in two cells",
+			durabilityPolicy: CrossCellDurabilityPolicy,
+			template:         twoCells,
+		},
+	}
+
+	for _, test := range table {
+		spec := VitessKeyspaceSpec{}
+		spec.DurabilityPolicy = test.durabilityPolicy
+		spec.Partitionings = []VitessKeyspacePartitioning{
+			{Equal: &VitessKeyspaceEqualPartitioning{Parts: 2, ShardTemplate: test.template}},
+		}
+		if got := spec.DurabilityProblems(); len(got) != test.wantProblems {
+			t.Errorf("%v: DurabilityProblems() = %q; want %d problems", test.name, got, test.wantProblems)
+		}
+	}
+}
+
+func TestVitessKeyspaceCustomPartitioningShardSpecs(t *testing.T) {
+	hotReplicas := int32(5)
+	hotConfig := "innodb_buffer_pool_size = 8G"
+	partitioning := VitessKeyspacePartitioning{
+		Custom: &VitessKeyspaceCustomPartitioning{
+			ShardTemplate: &VitessShardTemplate{
+				TabletPools: []VitessShardTabletPool{
+					{
+						Cell:     "cell1",
+						Type:     ReplicaPoolType,
+						Replicas: 2,
+						Mysqld:   &MysqldSpec{},
+					},
+				},
+			},
+			ShardRanges: []VitessKeyspaceShardRange{
+				{
+					KeyRange: VitessKeyRange{"", "80"},
+					TabletPoolOverrides: []VitessTabletPoolOverride{
+						{
+							Cell:                  "cell1",
+							Type:                  ReplicaPoolType,
+							Replicas:              &hotReplicas,
+							MysqldConfigOverrides: &hotConfig,
+						},
+					},
+				},
+				{
+					KeyRange: VitessKeyRange{"80", ""},
+				},
+			},
+		},
+	}
+
+	shards := partitioning.Custom.ShardSpecs()
+	if len(shards) != 2 {
+		t.Fatalf("ShardSpecs() returned %v shards; want 2", len(shards))
+	}
+	if got := shards[0].TabletPools[0]; got.Replicas != 5 || got.Mysqld.ConfigOverrides != hotConfig {
+		t.Errorf("overridden pool = %#v; want 5 replicas and config %q", got, hotConfig)
+	}
+	if got := shards[1].TabletPools[0]; got.Replicas != 2 || got.Mysqld.ConfigOverrides != "" {
+		t.Errorf("template pool = %#v; want 2 replicas and no config", got)
+	}
+	if got := partitioning.Custom.ShardTemplate.TabletPools[0].Replicas; got != 2 {
+		t.Errorf("ShardSpecs() changed the template to %v replicas; want 2", got)
+	}
+	if got := partitioning.TotalReplicas(); got != 7 {
+		t.Errorf("TotalReplicas() = %v; want 7", got)
+	}
+	if got, want := partitioning.ShardNameSet().List(), []string{"-80", "80-"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ShardNameSet() = %v; want %v", got, want)
+	}
+}
+
+func TestVitessKeyspaceTemplateRemoveTabletPoolsInCells(t *testing.T) {
+	pools := func(cells ...string) []VitessShardTabletPool {
+		var pools []VitessShardTabletPool
+		for _, cell := range cells {
+			pools = append(pools, VitessShardTabletPool{Cell: cell, Type: ReplicaPoolType, Replicas: 2})
+		}
+		return pools
+	}
+	spec := VitessKeyspaceTemplate{
+		Partitionings: []VitessKeyspacePartitioning{
+			{Equal: &VitessKeyspaceEqualPartitioning{Parts: 1, ShardTemplate: VitessShardTemplate{TabletPools: pools("zone1", "zone2")}}},
+			{Custom: &VitessKeyspaceCustomPartitioning{
+				Shards: []VitessKeyspaceKeyRangeShard{
+					{KeyRange: VitessKeyRange{"", "80"}, VitessShardTemplate: VitessShardTemplate{TabletPools: pools("zone2", "zone3")}},
+				},
+				ShardTemplate: &VitessShardTemplate{TabletPools: pools("zone1", "zone2")},
+				ShardRanges:   []VitessKeyspaceShardRange{{KeyRange: VitessKeyRange{"80", ""}}},
+			}},
+		},
+	}
+
+	if spec.RemoveTabletPoolsInCells(sets.NewString("zone4")) {
+		t.Errorf("RemoveTabletPoolsInCells(zone4) = true; want false")
+	}
+	if !spec.RemoveTabletPoolsInCells(sets.NewString("zone2")) {
+		t.Errorf("RemoveTabletPoolsInCells(zone2) = false; want true")
+	}
+	for _, pool := range spec.Partitionings[0].TabletPools() {
+		if pool.Cell == "zone2" {
+			t.Errorf("equal partitioning still has a pool in zone2")
+		}
+	}
+	for _, pool := range spec.Partitionings[1].TabletPools() {
+		if pool.Cell == "zone2" {
+			t.Errorf("custom partitioning still has a pool in zone2")
+		}
+	}
+	if got, want := len(spec.Partitionings[1].TabletPools()), 2; got != want {
+		t.Errorf("custom partitioning has %v pools left; want %v", got, want)
+	}
+}
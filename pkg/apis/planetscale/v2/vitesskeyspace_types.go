@@ -18,6 +18,7 @@ package v2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -73,6 +74,20 @@ type VitessKeyspaceSpec struct {
 	// BackupEngine specifies the Vitess backup engine to use, either "builtin" or "xtrabackup".
 	BackupEngine VitessBackupEngine `json:"backupEngine,omitempty"`
 
+	// Vtbackup configures vtbackup Pods, as defined in the VitessCluster.
+	Vtbackup *VitessBackupPodSpec `json:"vtbackup,omitempty"`
+
+	// BackupVerification configures backup verification, as defined in the VitessCluster.
+	BackupVerification *VitessBackupVerificationSpec `json:"backupVerification,omitempty"`
+
+	// BackupSourceTabletPolicy configures which tablet pool backups are taken
+	// on behalf of, as defined in the VitessCluster.
+	BackupSourceTabletPolicy *VitessBackupSourceTabletPolicy `json:"backupSourceTabletPolicy,omitempty"`
+
+	// BackupReplication configures copying of completed backups to other
+	// locations, as defined in the VitessCluster.
+	BackupReplication *VitessBackupReplicationSpec `json:"backupReplication,omitempty"`
+
 	// ExtraVitessFlags is inherited from the parent's VitessClusterSpec.
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
@@ -81,6 +96,22 @@ type VitessKeyspaceSpec struct {
 
 	// UpdateStrategy is inherited from the parent's VitessClusterSpec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// MaintenanceWindows is inherited from the parent's VitessClusterSpec.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// PreferredPrimaryCells is inherited from the parent's VitessClusterSpec.
+	PreferredPrimaryCells []string `json:"preferredPrimaryCells,omitempty"`
+
+	// ReparentSettings is inherited from the parent's VitessClusterSpec.
+	ReparentSettings *ReparentSettings `json:"reparentSettings,omitempty"`
+
+	// InitialRestore is inherited from the parent's VitessClusterSpec.
+	InitialRestore *VitessInitialRestoreSpec `json:"initialRestore,omitempty"`
+
+	// Standby is inherited from the parent's VitessClusterSpec.
+	// It's only set while the cluster's role is standby.
+	Standby *VitessStandbySpec `json:"standby,omitempty"`
 }
 
 // VitessKeyspaceTemplate contains only the user-specified parts of a VitessKeyspace object.
@@ -114,7 +145,13 @@ type VitessKeyspaceTemplate struct {
 	DatabaseName string `json:"databaseName,omitempty"`
 
 	// DurabilityPolicy is the name of the durability policy to use for the keyspace.
-	// If unspecified, vtop will not set the durability policy.
+	// If unspecified, vtop will use the semiSyncDurabilityPolicy of the first shard
+	// in "semiSync" replication mode, if any. Otherwise, it will not set the durability policy.
+	//
+	// The DurabilitySatisfiable condition reports whether the tablet pools of
+	// every shard have enough replica-type tablets, in enough cells, for a
+	// primary to always get the semi-sync acks that the policy requires.
+	// +kubebuilder:validation:Enum=none;semi_sync;cross_cell
 	DurabilityPolicy string `json:"durabilityPolicy,omitempty"`
 
 	// VitessOrchestrator deploys a set of Vitess Orchestrator (vtorc) servers for the Keyspace.
@@ -122,6 +159,53 @@ type VitessKeyspaceTemplate struct {
 	// for the vttablets if enabling vtorc.
 	VitessOrchestrator *VitessOrchestratorSpec `json:"vitessOrchestrator,omitempty"`
 
+	// ReparentConcurrency is the maximum number of shards in the keyspace
+	// that the operator will reparent at the same time, whether for node
+	// drains, rolling updates, or scheduled primary rotations. Shards that
+	// would exceed the limit wait for a later pass. This avoids a burst of
+	// failovers across the keyspace when a whole node pool is rotated.
+	//
+	// Default: No limit.
+	// +kubebuilder:validation:Minimum=1
+	ReparentConcurrency *int32 `json:"reparentConcurrency,omitempty"`
+
+	// UpdateOrder can optionally be used to control which shards are updated
+	// first when a change affects the whole keyspace and the update strategy
+	// is Immediate. Each entry is a shard key range, such as "-80" or "80-".
+	//
+	// The listed shards are updated one at a time, in the order listed, and
+	// each one finishes rolling out before the next one starts. Shards that
+	// aren't listed are updated in parallel once all the listed shards are
+	// done. For example, listing a single shard makes it a canary for the
+	// rest of the keyspace.
+	//
+	// Default: All shards are updated in parallel.
+	UpdateOrder []string `json:"updateOrder,omitempty"`
+
+	// BackupSchedule can optionally be used to override the cluster-wide
+	// backup schedule for this keyspace.
+	//
+	// Default: The schedule in the cluster's backup spec, if any.
+	BackupSchedule *VitessBackupScheduleSpec `json:"backupSchedule,omitempty"`
+
+	// BackupLocationOverrides can optionally be used to store this keyspace's
+	// backups somewhere other than the cluster-wide backup locations, such as
+	// a separate bucket or account.
+	//
+	// Each location here replaces the cluster-level location with the same
+	// name for this keyspace only, so an override with an empty name changes
+	// where the keyspace's tablet pools store backups by default. A location
+	// with a name that isn't defined at the cluster level is added for this
+	// keyspace only. Cluster-level locations that aren't overridden are still
+	// used as-is.
+	//
+	// Changing the location of a keyspace that already has backups does not
+	// move them. Existing tablets keep working, but new tablets can only
+	// restore from backups that are in the new location.
+	//
+	// Default: Use the cluster-wide backup locations.
+	BackupLocationOverrides []VitessBackupLocation `json:"backupLocationOverrides,omitempty"`
+
 	// Partitionings specify how to divide the keyspace up into shards by
 	// defining the range of keyspace IDs that each shard contains.
 	// For example, you might divide the keyspace into N equal-sized key ranges.
@@ -154,6 +238,44 @@ type VitessKeyspaceTemplate struct {
 	// +kubebuilder:validation:MaxItems=2
 	Partitionings []VitessKeyspacePartitioning `json:"partitionings"`
 
+	// Materializations are VReplication Materialize workflows that fill
+	// tables in this keyspace from queries on a source keyspace, such as
+	// rollups of another table. The operator creates each workflow and keeps
+	// it around: if a workflow is dropped, it's created again, and if it's
+	// removed from this list, the workflow is deleted (but the rows it
+	// already wrote to the target table are kept).
+	//
+	// To change the query or target table of a workflow, remove it from this
+	// list and add it back under a new name.
+	Materializations []VitessMaterialization `json:"materializations,omitempty"`
+
+	// AutoReshard can optionally be used to split shards automatically once
+	// they grow too large or too busy.
+	//
+	// Default: Shards are only split when you reshard them yourself.
+	AutoReshard *VitessAutoReshardPolicy `json:"autoReshard,omitempty"`
+
+	// Throttler configures the tablet throttler of the keyspace, which holds
+	// back VReplication, Online DDL, and other background work while
+	// replicas are lagging. The operator applies the configuration to the
+	// keyspace record in the topology, which every tablet reads it from, so
+	// it also holds for tablets that are recreated or promoted to primary.
+	// Changes made by hand with vtctldclient are reverted.
+	//
+	// Note that the default throttler check measures replication lag with
+	// heartbeats, which vttablet only writes if it's started with a flag
+	// such as "heartbeat_on_demand_duration", set in extraFlags.
+	//
+	// Default: The throttler configuration is left as it is.
+	Throttler *VitessThrottlerSpec `json:"throttler,omitempty"`
+
+	// Schema can optionally be used to have the operator apply schema
+	// changes and the VSchema of the keyspace. Schema changes are made with
+	// Online DDL, so they don't block the tables they change.
+	//
+	// Default: The schema and VSchema are left as they are.
+	Schema *VitessKeyspaceSchema `json:"schema,omitempty"`
+
 	// TurndownPolicy specifies what should happen if this keyspace is ever
 	// removed from the VitessCluster spec. By default, removing a keyspace
 	// entry from the VitessCluster spec will NOT actually turn down the
@@ -172,16 +294,390 @@ type VitessKeyspaceTemplate struct {
 	// spec is only ever edited by automation whose edits you trust to be safe,
 	// you can set the policy to Immediate to skip these checks.
 	//
+	// With the Decommission policy, the keyspace may be removed from the spec
+	// without removing its tablet pools first. Instead, the operator makes
+	// sure the keyspace no longer serves any queries through vtgate, takes a
+	// final backup of every shard, and waits for the hold period set in
+	// the decommission field before it turns down the keyspace. Progress is
+	// reported by the Decommissioning condition of the VitessKeyspace.
+	//
 	// Default: RequireIdle
-	// +kubebuilder:validation:Enum=RequireIdle;Immediate
+	// +kubebuilder:validation:Enum=RequireIdle;Immediate;Decommission
 	TurndownPolicy VitessKeyspaceTurndownPolicy `json:"turndownPolicy,omitempty"`
 
+	// Decommission configures how the keyspace is turned down when
+	// turndownPolicy is Decommission.
+	Decommission *VitessKeyspaceDecommissionPolicy `json:"decommission,omitempty"`
+
 	// Annotations can optionally be used to attach custom annotations to the VitessKeyspace object.
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// VitessKeyspaceDecommissionPolicy configures the checks made before a
+// keyspace is turned down.
+type VitessKeyspaceDecommissionPolicy struct {
+	// HoldPeriod is how long to wait after the final backup, before the
+	// keyspace is turned down. Until then, adding the keyspace back to the
+	// VitessCluster spec cancels the decommission.
+	//
+	// Default: 24h
+	HoldPeriod *metav1.Duration `json:"holdPeriod,omitempty"`
+
+	// SkipFinalBackup can be set to true to turn down the keyspace without
+	// taking a final backup of each shard. A final backup is never taken if
+	// the cluster has no backup locations.
+	SkipFinalBackup bool `json:"skipFinalBackup,omitempty"`
+}
+
+// VitessMaterialization declares a VReplication Materialize workflow that
+// fills a table in the keyspace from a query on a source keyspace.
+type VitessMaterialization struct {
+	// Name is the name of the VReplication workflow. It must be unique among
+	// the workflows of the keyspace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SourceKeyspace is the name of the keyspace to read from.
+	//
+	// Default: The keyspace that the target table is in.
+	SourceKeyspace string `json:"sourceKeyspace,omitempty"`
+
+	// TargetTable is the name of the table to fill in this keyspace.
+	// +kubebuilder:validation:MinLength=1
+	TargetTable string `json:"targetTable"`
+
+	// SourceExpression is the SELECT statement, run on the source keyspace,
+	// whose results are written to the target table. For example:
+	// "select customer_id, count(*) as order_count from corder group by customer_id".
+	// +kubebuilder:validation:MinLength=1
+	SourceExpression string `json:"sourceExpression"`
+
+	// CreateDDL is the statement that creates the target table if it doesn't
+	// exist yet. The special value "copy" creates it with the same schema as
+	// the source table of the same name.
+	//
+	// Default: The target table must already exist.
+	CreateDDL string `json:"createDDL,omitempty"`
+
+	// StopAfterCopy can be set to true to stop the workflow once it has
+	// copied the existing rows, rather than keep replicating changes. Once
+	// the copy is done, the operator no longer creates the workflow again
+	// if it's dropped.
+	StopAfterCopy bool `json:"stopAfterCopy,omitempty"`
+
+	// TabletTypes are the types of tablets, in order of preference, that
+	// VReplication may read from in the source keyspace, such as "replica"
+	// or "primary".
+	//
+	// Default: replica, primary
+	TabletTypes []string `json:"tabletTypes,omitempty"`
+}
+
+// VitessThrottlerSpec configures the tablet throttler of a keyspace.
+type VitessThrottlerSpec struct {
+	// Enabled is whether the throttler checks the health of the shards.
+	// A disabled throttler lets all work through.
+	Enabled bool `json:"enabled"`
+
+	// Threshold is the value over which the throttler holds back work. For
+	// the default check, it's the replication lag in seconds, such as "5" or
+	// "1.5". For a custom query, it's compared to the value the query
+	// returns.
+	//
+	// Default: The threshold is left as it is, which is 5 seconds for a new
+	// keyspace.
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	Threshold string `json:"threshold,omitempty"`
+
+	// CustomQuery replaces the default check of replication lag with a
+	// query that returns a single number, which is compared to the
+	// threshold.
+	CustomQuery string `json:"customQuery,omitempty"`
+
+	// CheckAsCheckSelf can be set to true to have the throttler only check
+	// the tablet it runs on, instead of all the tablets in the shard.
+	CheckAsCheckSelf bool `json:"checkAsCheckSelf,omitempty"`
+
+	// ThrottledApps are rules for specific apps, such as "vreplication" or
+	// "online-ddl". Apps that aren't listed are left as they are, so
+	// temporary rules added with vtctldclient aren't undone.
+	ThrottledApps []VitessThrottledApp `json:"throttledApps,omitempty"`
+}
+
+// VitessThrottledApp is a throttler rule for a specific app.
+type VitessThrottledApp struct {
+	// Name is the name of the app, such as "vreplication" or "online-ddl".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Ratio is how much of the app's work is held back, from "0" for none
+	// of it to "1" for all of it.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Pattern=^(0(\.[0-9]+)?|1(\.0+)?)$
+	Ratio string `json:"ratio,omitempty"`
+
+	// Exempt can be set to true to never hold back the app, even while the
+	// throttler holds back other work.
+	Exempt bool `json:"exempt,omitempty"`
+
+	// ExpiresAt is when the rule stops applying.
+	//
+	// Default: The rule applies until it's removed from the throttler
+	// configuration.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// VitessKeyspaceSchema declares schema changes and the VSchema of a keyspace.
+type VitessKeyspaceSchema struct {
+	// Migrations are the schema changes to make, in order. Each one is made
+	// once, and the next one only starts once the one before it is complete
+	// on all shards. If a migration fails, the ones after it wait until it's
+	// removed from the list.
+	//
+	// Editing a migration that was already started has no effect. To change
+	// the schema again, add another migration to the end of the list.
+	Migrations []VitessSchemaMigration `json:"migrations,omitempty"`
+
+	// DDLStrategy is the Online DDL strategy that migrations are made with.
+	// Use "direct" to run statements directly, without Online DDL.
+	//
+	// Default: vitess
+	// +kubebuilder:validation:Enum=vitess;online;gh-ost;pt-osc;mysql;direct
+	DDLStrategy VitessDDLStrategy `json:"ddlStrategy,omitempty"`
+
+	// StrategyFlags are passed along with the DDL strategy, such as
+	// "--allow-concurrent" or "--prefer-instant-ddl".
+	StrategyFlags string `json:"strategyFlags,omitempty"`
+
+	// VSchema is the VSchema of the keyspace, as JSON. Whenever the VSchema
+	// in the topology is different, including when it was changed outside
+	// the operator, it's replaced with this one.
+	//
+	// Default: The VSchema is left as it is.
+	VSchema string `json:"vschema,omitempty"`
+
+	// VSchemaApplyPolicy is Automatic to apply the VSchema as soon as it
+	// differs, or Manual to first list the changes in
+	// status.schema.pendingVSchemaChanges and wait for them to be approved
+	// by setting ApprovedVSchemaHash.
+	//
+	// Default: Automatic
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	VSchemaApplyPolicy VitessVSchemaApplyPolicy `json:"vschemaApplyPolicy,omitempty"`
+
+	// ApprovedVSchemaHash approves the VSchema for the Manual apply policy.
+	// Set it to the value of status.schema.vschemaHash once the pending
+	// changes look right. Once approved, the VSchema is kept as it is in the
+	// spec, like with the Automatic policy, until the spec changes again.
+	ApprovedVSchemaHash string `json:"approvedVSchemaHash,omitempty"`
+
+	// Sequences are sequence tables to create in this keyspace, which must
+	// be unsharded. Each one is created with its first value, and added to
+	// the VSchema as a sequence. Tables in sharded keyspaces can then use
+	// it for their auto_increment column in their own VSchema.
+	Sequences []VitessSequence `json:"sequences,omitempty"`
+
+	// ReferenceTables are tables copied in full to every shard of this
+	// keyspace from another keyspace, so they can be joined with local
+	// tables without crossing shards. Each one is added to the VSchema as a
+	// reference table, and kept in sync with a Materialize workflow named
+	// after the table, whose status is listed along with the other
+	// materializations. Removing a table from this list deletes the workflow
+	// but keeps the copied rows.
+	ReferenceTables []VitessReferenceTable `json:"referenceTables,omitempty"`
+
+	// MigrationControls throttle, cancel, or retry Online DDL migrations of
+	// the keyspace by UUID, whether or not they were started by the operator.
+	// The UUIDs of migrations that haven't finished yet are listed in
+	// status.schema.activeMigrations.
+	MigrationControls []VitessSchemaMigrationControl `json:"migrationControls,omitempty"`
+}
+
+// VitessSequence declares a sequence table.
+type VitessSequence struct {
+	// Name is the name of the sequence table.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=^[a-zA-Z_][a-zA-Z0-9_]*$
+	Name string `json:"name"`
+
+	// Start is the first value that the sequence hands out. It's only used
+	// when the table is created.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	Start *int64 `json:"start,omitempty"`
+
+	// Cache is how many values each vttablet reserves at a time. Larger
+	// values mean fewer writes to the sequence table, but bigger gaps when
+	// tablets restart. It's only used when the table is created.
+	//
+	// Default: 1000
+	// +kubebuilder:validation:Minimum=1
+	Cache *int64 `json:"cache,omitempty"`
+}
+
+// VitessReferenceTable declares a table copied to every shard of the keyspace.
+type VitessReferenceTable struct {
+	// Name is the name of the table, which is the same in both keyspaces.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=^[a-zA-Z_][a-zA-Z0-9_]*$
+	Name string `json:"name"`
+
+	// SourceKeyspace is the keyspace that has the source of truth for the
+	// table, which is usually unsharded.
+	// +kubebuilder:validation:MinLength=1
+	SourceKeyspace string `json:"sourceKeyspace"`
+}
+
+// VitessVSchemaApplyPolicy is when to apply the VSchema of a keyspace.
+type VitessVSchemaApplyPolicy string
+
+const (
+	// VSchemaApplyAutomatic applies the VSchema whenever it differs.
+	VSchemaApplyAutomatic VitessVSchemaApplyPolicy = "Automatic"
+	// VSchemaApplyManual waits for changes to the VSchema to be approved.
+	VSchemaApplyManual VitessVSchemaApplyPolicy = "Manual"
+)
+
+// VitessSchemaMigrationControl is the desired state of an Online DDL migration.
+type VitessSchemaMigrationControl struct {
+	// UUID is the ID that Vitess assigned to the migration.
+	// +kubebuilder:validation:MinLength=1
+	UUID string `json:"uuid"`
+
+	// Throttled can be set to true to hold back a migration that hasn't
+	// finished yet. Setting it back to false lets the migration go on.
+	Throttled bool `json:"throttled,omitempty"`
+
+	// Cancelled can be set to true to cancel a migration that hasn't
+	// finished yet.
+	Cancelled bool `json:"cancelled,omitempty"`
+
+	// Retries is the number of times a failed or cancelled migration should
+	// have been retried. Whenever the migration failed and Vitess has retried
+	// it fewer times than this, it's retried again, so raising this number
+	// by one retries the migration once more.
+	// +kubebuilder:validation:Minimum=0
+	Retries int32 `json:"retries,omitempty"`
+}
+
+// VitessSchemaMigration is a schema change.
+type VitessSchemaMigration struct {
+	// Name identifies the migration. It must be unique among the migrations
+	// of the keyspace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SQL is the DDL statement that makes the change, such as
+	// "alter table customer add column email varchar(128)". It must be a
+	// single statement.
+	// +kubebuilder:validation:MinLength=1
+	SQL string `json:"sql"`
+}
+
+// VitessDDLStrategy is an Online DDL strategy.
+type VitessDDLStrategy string
+
+const (
+	// DDLStrategyVitess makes schema changes with VReplication.
+	DDLStrategyVitess VitessDDLStrategy = "vitess"
+	// DDLStrategyOnline is another name for DDLStrategyVitess.
+	DDLStrategyOnline VitessDDLStrategy = "online"
+	// DDLStrategyGhost makes schema changes with gh-ost.
+	DDLStrategyGhost VitessDDLStrategy = "gh-ost"
+	// DDLStrategyPtOsc makes schema changes with pt-online-schema-change.
+	DDLStrategyPtOsc VitessDDLStrategy = "pt-osc"
+	// DDLStrategyMySQL makes schema changes with MySQL, but queued and
+	// tracked like other Online DDL migrations.
+	DDLStrategyMySQL VitessDDLStrategy = "mysql"
+	// DDLStrategyDirect runs statements directly on the shards.
+	DDLStrategyDirect VitessDDLStrategy = "direct"
+)
+
+// VitessAutoReshardPolicy configures when the operator splits the shards
+// of a keyspace on its own.
+//
+// The operator checks the primary of each shard about once a minute. When a
+// shard has stayed over one of the thresholds for long enough, the operator
+// proposes to split it into two halves. Once the split is approved (if
+// approval is required) and a maintenance window is open, the operator adds
+// a partitioning with the new shards to the keyspace in the VitessCluster,
+// and creates a VitessReshard to move the data. After the VitessReshard is
+// Complete, the operator removes the old partitioning, which turns down the
+// shard that was split.
+//
+// Only one shard is split at a time, and only while the keyspace has a
+// single partitioning and no other resharding is in progress.
+type VitessAutoReshardPolicy struct {
+	// MaxShardDataSize is how much data the tables on a shard's primary may
+	// hold before the shard is split.
+	//
+	// Default: Shards are not split because of their size.
+	MaxShardDataSize *resource.Quantity `json:"maxShardDataSize,omitempty"`
+
+	// MaxPrimaryQPS is how many queries per second a shard's primary may
+	// serve before the shard is split.
+	//
+	// Default: Shards are not split because of their load.
+	// +kubebuilder:validation:Minimum=1
+	MaxPrimaryQPS *int64 `json:"maxPrimaryQPS,omitempty"`
+
+	// SustainedFor is how long a shard must stay over a threshold before
+	// it's split. This keeps short bursts of load from causing a split.
+	//
+	// Default: 1h
+	SustainedFor *metav1.Duration `json:"sustainedFor,omitempty"`
+
+	// MaxShards is how many shards the keyspace may have. Shards are no
+	// longer split once the keyspace has this many.
+	//
+	// Default: No limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxShards *int32 `json:"maxShards,omitempty"`
+
+	// RequireApproval can be set to true to wait for approval before a split
+	// starts. To approve the split proposed in status.autoReshard.proposal,
+	// set the annotation "planetscale.com/approve-auto-reshard" on the
+	// VitessKeyspace to the name of the proposal.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// SwitchTraffic is passed on to the VitessReshards that the operator
+	// creates. Setting it to Manual makes each one wait for approval before
+	// switching traffic to the new shards.
+	//
+	// Default: Auto
+	// +kubebuilder:validation:Enum=Auto;Manual
+	SwitchTraffic VitessSwitchTrafficMode `json:"switchTraffic,omitempty"`
+}
+
 // VitessOrchestratorSpec specifies deployment parameters for vtorc.
 type VitessOrchestratorSpec struct {
+	// Scope is which tablets each vtorc Deployment watches.
+	//
+	// With Shard, there's a vtorc Deployment for each shard in each cell
+	// that has replica-type tablets. With Keyspace, there's one Deployment
+	// that watches every shard of the keyspace, which needs fewer Pods for
+	// keyspaces with many shards.
+	//
+	// Default: Shard
+	// +kubebuilder:validation:Enum=Shard;Keyspace
+	Scope VitessOrchestratorScope `json:"scope,omitempty"`
+
+	// Replicas is the number of vtorc Pods in each Deployment. Replicas
+	// coordinate through shard locks in the topology, so only one of them
+	// recovers a given shard at a time.
+	//
+	// Default: 1
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Recovery configures how vtorc recovers shards whose primary failed.
+	// Settings that also apply to the operator's own planned reparents
+	// default to the values in reparentSettings, so both make the same
+	// choices.
+	Recovery *VitessOrchestratorRecovery `json:"recovery,omitempty"`
+
 	// Resources determines the compute resources reserved for each vtorc replica.
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
@@ -247,6 +743,54 @@ type VitessOrchestratorSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodDisruptionBudget can optionally be used to customize the
+	// PodDisruptionBudget (PDB) that the operator manages for vtorc Pods in each cell.
+	//
+	// Default: A PDB that lets only one Pod be evicted at a time.
+	PodDisruptionBudget *VitessPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+}
+
+// VitessOrchestratorScope is which tablets a vtorc Deployment watches.
+type VitessOrchestratorScope string
+
+const (
+	// VitessOrchestratorScopeShard deploys vtorc for each shard in each cell.
+	VitessOrchestratorScopeShard VitessOrchestratorScope = "Shard"
+	// VitessOrchestratorScopeKeyspace deploys vtorc once for the whole keyspace.
+	VitessOrchestratorScopeKeyspace VitessOrchestratorScope = "Keyspace"
+)
+
+// VitessOrchestratorRecovery configures how vtorc recovers shards.
+type VitessOrchestratorRecovery struct {
+	// AllowEmergencyReparent is whether vtorc may promote a new primary when
+	// the current one is dead. Turning this off leaves failover of dead
+	// primaries to the operator's drain handling or to people.
+	//
+	// Default: true
+	AllowEmergencyReparent *bool `json:"allowEmergencyReparent,omitempty"`
+
+	// PreventCrossCellFailover keeps vtorc from promoting a primary in a
+	// different cell than the one that failed.
+	PreventCrossCellFailover bool `json:"preventCrossCellFailover,omitempty"`
+
+	// WaitReplicasTimeout is how long vtorc waits for replicas to respond
+	// while reparenting.
+	//
+	// Default: The plannedReparentTimeout in reparentSettings.
+	WaitReplicasTimeout *metav1.Duration `json:"waitReplicasTimeout,omitempty"`
+
+	// TolerableReplicationLag is the most replication lag a tablet may have
+	// to be promoted by a planned reparent that vtorc makes.
+	//
+	// Default: The tolerableReplicationLag in reparentSettings.
+	TolerableReplicationLag *metav1.Duration `json:"tolerableReplicationLag,omitempty"`
+
+	// RecoveryPeriodBlockDuration is how long vtorc waits after recovering
+	// a shard before it may recover the same shard again.
+	//
+	// Default: The vtorc default, which is 30s.
+	RecoveryPeriodBlockDuration *metav1.Duration `json:"recoveryPeriodBlockDuration,omitempty"`
 }
 
 // VitessKeyspaceTurndownPolicy is the policy for turning down a keyspace.
@@ -261,6 +805,10 @@ const (
 	// from the VitessCluster spec should immediately trigger turn-down of
 	// all resources previously deployed for the keyspace.
 	VitessKeyspaceTurndownPolicyImmediate VitessKeyspaceTurndownPolicy = "Immediate"
+	// VitessKeyspaceTurndownPolicyDecommission specifies that a keyspace may
+	// only be turned down once it no longer serves queries, a final backup
+	// has been taken, and the hold period has passed.
+	VitessKeyspaceTurndownPolicyDecommission VitessKeyspaceTurndownPolicy = "Decommission"
 )
 
 // VitessKeyspaceImages specifies container images to use for this keyspace.
@@ -322,6 +870,23 @@ type VitessKeyspaceEqualPartitioning struct {
 	// If you need shards that don't all share the same configuration,
 	// use custom partitioning instead.
 	ShardTemplate VitessShardTemplate `json:"shardTemplate,omitempty"`
+
+	// ProvisioningOrder is the order in which new shards are created, when
+	// MaxConcurrentShardCreations limits how many may be created at once.
+	//
+	// Default: Ascending
+	// +kubebuilder:validation:Enum=Ascending;Descending
+	ProvisioningOrder VitessShardProvisioningOrder `json:"provisioningOrder,omitempty"`
+
+	// MaxConcurrentShardCreations is how many shards of this partitioning
+	// may be coming up at once. A new shard is only created once fewer than
+	// this many shards are still waiting for a primary and for all their
+	// tablets to be Ready. This keeps a partitioning with many shards from
+	// creating all their Pods and volumes at the same time.
+	//
+	// Default: No limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentShardCreations *int32 `json:"maxConcurrentShardCreations,omitempty"`
 }
 
 // VitessKeyspaceCustomPartitioning lets you explicitly specify the key range of every shard.
@@ -329,9 +894,96 @@ type VitessKeyspaceCustomPartitioning struct {
 	// Shards is a list of explicit shard specifications.
 	// +patchMergeKey=keyRange
 	// +patchStrategy=merge
-	Shards []VitessKeyspaceKeyRangeShard `json:"shards" patchStrategy:"merge" patchMergeKey:"keyRange"`
+	Shards []VitessKeyspaceKeyRangeShard `json:"shards,omitempty" patchStrategy:"merge" patchMergeKey:"keyRange"`
+
+	// ShardTemplate is the configuration shared by the shards listed in
+	// ShardRanges.
+	ShardTemplate *VitessShardTemplate `json:"shardTemplate,omitempty"`
+
+	// ShardRanges is a list of shards that use ShardTemplate, each of which
+	// may adjust some settings of its tablet pools. This lets a few hot
+	// shards get more replicas or resources without spelling out the whole
+	// configuration of every shard.
+	//
+	// A key range must not be listed in both Shards and ShardRanges.
+	// +patchMergeKey=keyRange
+	// +patchStrategy=merge
+	ShardRanges []VitessKeyspaceShardRange `json:"shardRanges,omitempty" patchStrategy:"merge" patchMergeKey:"keyRange"`
+
+	// ProvisioningOrder is the order in which new shards are created, when
+	// MaxConcurrentShardCreations limits how many may be created at once.
+	//
+	// Default: Ascending
+	// +kubebuilder:validation:Enum=Ascending;Descending
+	ProvisioningOrder VitessShardProvisioningOrder `json:"provisioningOrder,omitempty"`
+
+	// MaxConcurrentShardCreations is how many shards of this partitioning
+	// may be coming up at once. A new shard is only created once fewer than
+	// this many shards are still waiting for a primary and for all their
+	// tablets to be Ready.
+	//
+	// Default: No limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentShardCreations *int32 `json:"maxConcurrentShardCreations,omitempty"`
 }
 
+// VitessKeyspaceShardRange defines a shard of a custom partitioning that
+// uses the partitioning's ShardTemplate.
+type VitessKeyspaceShardRange struct {
+	// KeyRange is the range of keys that this shard serves.
+	//
+	// WARNING: DO NOT change the key range of a shard after deploying.
+	//          See the warning on the keyRange of custom shards.
+	KeyRange VitessKeyRange `json:"keyRange"`
+
+	// TabletPoolOverrides adjust the tablet pools from the ShardTemplate for
+	// this shard only.
+	TabletPoolOverrides []VitessTabletPoolOverride `json:"tabletPoolOverrides,omitempty"`
+}
+
+// VitessTabletPoolOverride changes some settings of a tablet pool in a
+// single shard. Settings that aren't set keep the values from the template.
+type VitessTabletPoolOverride struct {
+	// Cell is the cell of the tablet pool to override.
+	// +kubebuilder:validation:MinLength=1
+	Cell string `json:"cell"`
+
+	// Type is the type of the tablet pool to override.
+	// +kubebuilder:validation:Enum=replica;rdonly;externalmaster;externalreplica;externalrdonly
+	Type VitessTabletPoolType `json:"type"`
+
+	// Name is the name of the tablet pool to override, if it has one.
+	Name string `json:"name,omitempty"`
+
+	// Replicas overrides the number of tablets in the pool.
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// VttabletResources overrides the compute resources of vttablet.
+	VttabletResources *corev1.ResourceRequirements `json:"vttabletResources,omitempty"`
+
+	// MysqldResources overrides the compute resources of mysqld.
+	// It has no effect on pools with an external datastore.
+	MysqldResources *corev1.ResourceRequirements `json:"mysqldResources,omitempty"`
+
+	// MysqldConfigOverrides overrides the extra MySQL config of the pool.
+	// It has no effect on pools with an external datastore.
+	MysqldConfigOverrides *string `json:"mysqldConfigOverrides,omitempty"`
+}
+
+// VitessShardProvisioningOrder is the order in which the shards of a
+// partitioning are created.
+type VitessShardProvisioningOrder string
+
+const (
+	// ShardProvisioningAscending creates shards in order of key range,
+	// starting with the lowest.
+	ShardProvisioningAscending VitessShardProvisioningOrder = "Ascending"
+	// ShardProvisioningDescending creates shards in reverse order of key
+	// range, starting with the highest.
+	ShardProvisioningDescending VitessShardProvisioningOrder = "Descending"
+)
+
 // VitessKeyspaceKeyRangeShard defines a shard based on a key range.
 type VitessKeyspaceKeyRangeShard struct {
 	// KeyRange is the range of keys that this shard serves.
@@ -373,6 +1025,9 @@ type VitessKeyspaceStatus struct {
 	Partitionings []VitessKeyspacePartitioningStatus `json:"partitionings,omitempty"`
 	// OrphanedShards is a list of unwanted shards that could not be turned down.
 	OrphanedShards map[string]OrphanStatus `json:"orphanedShards,omitempty"`
+	// VitessOrchestrator is a summary of the status of the keyspace-wide
+	// vtorc Deployment, if vitessOrchestrator.scope is Keyspace.
+	VitessOrchestrator *VitessOrchestratorStatus `json:"vitessOrchestrator,omitempty"`
 	// Idle is a condition indicating whether the keyspace can be turned down.
 	// If Idle is True, the keyspace is not deployed in any cells, so it should
 	// be safe to turn down the keyspace.
@@ -381,6 +1036,18 @@ type VitessKeyspaceStatus struct {
 	// This field is only present if the ReshardingActive condition is True. If that condition is Unknown,
 	// it means the operator was unable to query resharding status from Vitess.
 	Resharding *ReshardingStatus `json:"resharding,omitempty"`
+	// Materializations is the status of each Materialize workflow that the
+	// operator manages for this keyspace.
+	Materializations []VitessMaterializationStatus `json:"materializations,omitempty"`
+	// AutoReshard reports on automatic shard splits, if the keyspace has an
+	// autoReshard policy.
+	AutoReshard *VitessAutoReshardStatus `json:"autoReshard,omitempty"`
+	// Decommission reports the progress of turning down the keyspace, if it
+	// was removed from the VitessCluster spec with the Decommission
+	// turndown policy.
+	Decommission *VitessKeyspaceDecommissionStatus `json:"decommission,omitempty"`
+	// Schema reports on the schema changes in the spec, if any.
+	Schema *VitessKeyspaceSchemaStatus `json:"schema,omitempty"`
 	// Conditions is a list of all VitessKeyspace specific conditions we want to set and monitor.
 	// It's ok for multiple controllers to add conditions here, and those conditions will be preserved.
 	Conditions []VitessKeyspaceCondition `json:"conditions,omitempty"`
@@ -416,6 +1083,185 @@ const (
 	WorkflowError WorkflowState = "Error"
 	// WorkflowUnknown indicates that we could not discover the state for the given workflow.
 	WorkflowUnknown WorkflowState = "Unknown"
+	// WorkflowStopped indicates that the workflow is not replicating, such as when it finished
+	// copying and was set to stop after copy.
+	WorkflowStopped WorkflowState = "Stopped"
+)
+
+// VitessMaterializationStatus is the status of a Materialize workflow.
+type VitessMaterializationStatus struct {
+	// Name is the name of the VReplication workflow.
+	Name string `json:"name"`
+	// State is either 'Running', 'Copying', 'Stopped', 'Error' or 'Unknown'.
+	State WorkflowState `json:"state"`
+	// ReplicationLagSeconds is how many seconds the target table is behind
+	// changes made in the source keyspace, for the shard that's furthest behind.
+	ReplicationLagSeconds int64 `json:"replicationLagSeconds,omitempty"`
+	// Message describes what the workflow is waiting for, or what went wrong.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessAutoReshardStatus reports on automatic shard splits.
+type VitessAutoReshardStatus struct {
+	// Shards are the latest measurements of each shard.
+	Shards []VitessAutoReshardShardStatus `json:"shards,omitempty"`
+	// LastMeasuredTime is when the shards were last measured.
+	LastMeasuredTime *metav1.Time `json:"lastMeasuredTime,omitempty"`
+	// Proposal is the split that the operator will start next, once it's
+	// approved and a maintenance window is open.
+	Proposal *VitessAutoReshardProposal `json:"proposal,omitempty"`
+	// Reshard is the name of the VitessReshard that the operator created
+	// for the split in progress, if any.
+	Reshard string `json:"reshard,omitempty"`
+	// Message describes what automatic resharding is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessAutoReshardShardStatus is the latest measurement of a shard.
+type VitessAutoReshardShardStatus struct {
+	// Name is the name of the shard, such as "-80".
+	Name string `json:"name"`
+	// DataSizeBytes is how much data the tables on the shard's primary hold.
+	// It's only measured if the policy sets maxShardDataSize.
+	DataSizeBytes int64 `json:"dataSizeBytes,omitempty"`
+	// PrimaryQPS is how many queries per second the shard's primary serves.
+	// It's only measured if the policy sets maxPrimaryQPS.
+	PrimaryQPS int64 `json:"primaryQPS,omitempty"`
+	// OverThresholdSince is when the shard went over a threshold, if it's
+	// over one.
+	OverThresholdSince *metav1.Time `json:"overThresholdSince,omitempty"`
+}
+
+// VitessAutoReshardProposal is a shard split that the operator intends to
+// start.
+type VitessAutoReshardProposal struct {
+	// Name is the name of the VitessReshard that will be created. This is
+	// also the value that approves the split, if approval is required.
+	Name string `json:"name"`
+	// SourceShard is the shard that will be split.
+	SourceShard string `json:"sourceShard"`
+	// TargetShards are the shards that will take over from the source shard.
+	TargetShards []string `json:"targetShards"`
+	// Reason is why the shard will be split.
+	Reason string `json:"reason,omitempty"`
+}
+
+// VitessKeyspaceSchemaStatus reports on the schema changes of a keyspace.
+type VitessKeyspaceSchemaStatus struct {
+	// Migrations reports on each migration that was started.
+	Migrations []VitessSchemaMigrationStatus `json:"migrations,omitempty"`
+	// ActiveMigrations lists the Online DDL migrations of the keyspace that
+	// haven't finished yet, including ones that weren't started by the
+	// operator.
+	ActiveMigrations []VitessActiveSchemaMigration `json:"activeMigrations,omitempty"`
+	// Sequences lists the sequence tables that were created.
+	Sequences []string `json:"sequences,omitempty"`
+	// VSchemaHash identifies the VSchema in the spec, for approving it with
+	// the Manual apply policy.
+	VSchemaHash string `json:"vschemaHash,omitempty"`
+	// PendingVSchemaChanges lists how the VSchema in the spec differs from
+	// the one in the topology, while the changes wait to be approved.
+	PendingVSchemaChanges []string `json:"pendingVSchemaChanges,omitempty"`
+	// Message describes what schema management is waiting for, or what went
+	// wrong, if anything.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessSchemaMigrationStatus reports on a schema migration.
+type VitessSchemaMigrationStatus struct {
+	// Name is the name of the migration in the spec.
+	Name string `json:"name"`
+	// UUID is the ID that Vitess assigned to the migration.
+	UUID string `json:"uuid,omitempty"`
+	// Phase is Running until the migration is Complete on all shards, or
+	// Failed if it failed or was cancelled on any of them.
+	Phase VitessSchemaMigrationPhase `json:"phase"`
+	// Shards reports on the migration on each shard.
+	Shards []VitessSchemaMigrationShardStatus `json:"shards,omitempty"`
+	// Message describes why the migration failed, if it did.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessActiveSchemaMigration reports on an Online DDL migration that
+// hasn't finished yet.
+type VitessActiveSchemaMigration struct {
+	// UUID is the ID that Vitess assigned to the migration.
+	UUID string `json:"uuid"`
+	// Table is the table that the migration changes.
+	Table string `json:"table,omitempty"`
+	// MigrationContext is the context that the migration was submitted
+	// with. Migrations started by the operator have a context that starts
+	// with "vitess-operator:".
+	MigrationContext string `json:"migrationContext,omitempty"`
+	// Shards reports on the migration on each shard.
+	Shards []VitessSchemaMigrationShardStatus `json:"shards,omitempty"`
+}
+
+// VitessSchemaMigrationShardStatus reports on a schema migration on a shard.
+type VitessSchemaMigrationShardStatus struct {
+	// Shard is the name of the shard.
+	Shard string `json:"shard"`
+	// Status is the status of the migration on the shard as reported by
+	// Vitess, such as "queued", "running", "complete", or "failed".
+	Status string `json:"status"`
+	// Progress is the percentage of rows that the migration has copied.
+	Progress int32 `json:"progress,omitempty"`
+	// Message is the error that the migration failed with, if it did.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessSchemaMigrationPhase is the phase of a schema migration.
+type VitessSchemaMigrationPhase string
+
+const (
+	// SchemaMigrationRunning means the migration was started, but isn't
+	// complete on all shards yet.
+	SchemaMigrationRunning VitessSchemaMigrationPhase = "Running"
+	// SchemaMigrationComplete means the migration is complete on all shards.
+	SchemaMigrationComplete VitessSchemaMigrationPhase = "Complete"
+	// SchemaMigrationFailed means the migration failed or was cancelled on
+	// some shards.
+	SchemaMigrationFailed VitessSchemaMigrationPhase = "Failed"
+)
+
+// VitessKeyspaceDecommissionStatus reports the progress of turning down a
+// keyspace.
+type VitessKeyspaceDecommissionStatus struct {
+	// Request is the value of the annotation that requested the
+	// decommission.
+	Request string `json:"request"`
+	// Phase is the step that the decommission is on.
+	Phase VitessKeyspaceDecommissionPhase `json:"phase"`
+	// Queries is how many queries vtgates had served for the keyspace when
+	// they were last checked.
+	Queries int64 `json:"queries,omitempty"`
+	// LastTrafficCheckTime is when vtgates were last checked for queries
+	// served for the keyspace.
+	LastTrafficCheckTime *metav1.Time `json:"lastTrafficCheckTime,omitempty"`
+	// BackupRequest is the ID of the on-demand backup requested for each
+	// shard as the final backup, if any.
+	BackupRequest string `json:"backupRequest,omitempty"`
+	// HoldStartTime is when the hold period started.
+	HoldStartTime *metav1.Time `json:"holdStartTime,omitempty"`
+	// Message describes what the decommission is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessKeyspaceDecommissionPhase is a step of turning down a keyspace.
+type VitessKeyspaceDecommissionPhase string
+
+const (
+	// DecommissionCheckingTraffic means the operator is waiting until the
+	// keyspace no longer serves queries through vtgate.
+	DecommissionCheckingTraffic VitessKeyspaceDecommissionPhase = "CheckingTraffic"
+	// DecommissionBackingUp means the operator is waiting for the final
+	// backup of each shard.
+	DecommissionBackingUp VitessKeyspaceDecommissionPhase = "BackingUp"
+	// DecommissionHolding means the operator is waiting for the hold period
+	// to pass.
+	DecommissionHolding VitessKeyspaceDecommissionPhase = "Holding"
+	// DecommissionComplete means the keyspace may be turned down.
+	DecommissionComplete VitessKeyspaceDecommissionPhase = "Complete"
 )
 
 // NewVitessKeyspaceStatus creates a new status object with default values.
@@ -450,6 +1296,9 @@ type VitessKeyspaceShardStatus struct {
 	// UpdatedTablets is the number of desired tablets that are up-to-date
 	// (have no pending changes).
 	UpdatedTablets int32 `json:"updatedTablets,omitempty"`
+	// FailedTablets is the number of up-to-date tablets that failed to come
+	// up, and are holding back the rollout.
+	FailedTablets int32 `json:"failedTablets,omitempty"`
 	// PendingChanges describes changes to the shard that will be applied
 	// the next time a rolling update allows.
 	PendingChanges string `json:"pendingChanges,omitempty"`
@@ -502,6 +1351,9 @@ type VitessKeyspacePartitioningStatus struct {
 	DesiredShards int32 `json:"desiredShards,omitempty"`
 	// ReadyShards is the number of desired shards that are Ready.
 	ReadyShards int32 `json:"readyShards,omitempty"`
+	// PendingShards is the number of desired shards that haven't been
+	// created yet because of maxConcurrentShardCreations.
+	PendingShards int32 `json:"pendingShards,omitempty"`
 }
 
 // NewVitessKeyspacePartitioningStatus creates a new status object with default values.
@@ -548,6 +1400,14 @@ const (
 	VitessKeyspaceReshardingInSync VitessKeyspaceConditionType = "ReshardingInSync"
 	// VitessKeyspaceReady indicates whether the tablet Pods of the keyspace's serving partitioning are all Ready.
 	VitessKeyspaceReady VitessKeyspaceConditionType = "Ready"
+	// VitessKeyspaceDecommissioning indicates whether the keyspace is being
+	// decommissioned because it was removed from the VitessCluster spec.
+	// The reason is the phase of the decommission.
+	VitessKeyspaceDecommissioning VitessKeyspaceConditionType = "Decommissioning"
+	// VitessKeyspaceDurabilitySatisfiable indicates whether the tablet pools
+	// of every shard can satisfy the keyspace's durability policy. It's only
+	// set if the keyspace has a durability policy.
+	VitessKeyspaceDurabilitySatisfiable VitessKeyspaceConditionType = "DurabilitySatisfiable"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
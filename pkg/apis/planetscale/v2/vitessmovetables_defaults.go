@@ -0,0 +1,44 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// DefaultVitessMoveTables fills in VitessMoveTables defaults for unspecified fields.
+func DefaultVitessMoveTables(vtmt *VitessMoveTables) {
+	if vtmt.Spec.Workflow == "" {
+		vtmt.Spec.Workflow = vtmt.Name
+	}
+	if len(vtmt.Spec.TabletTypes) == 0 {
+		vtmt.Spec.TabletTypes = []string{"replica", "primary"}
+	}
+	if vtmt.Spec.SwitchTraffic == "" {
+		vtmt.Spec.SwitchTraffic = SwitchTrafficAuto
+	}
+	if vtmt.Spec.Complete == "" {
+		vtmt.Spec.Complete = MoveTablesCompleteAuto
+	}
+	if vtmt.Spec.MaxReplicationLag == nil {
+		vtmt.Spec.MaxReplicationLag = &metav1.Duration{Duration: defaultVReplicationMaxReplicationLag}
+	}
+	if vtmt.Spec.MaxRetries == nil {
+		vtmt.Spec.MaxRetries = pointer.Int32Ptr(defaultMoveTablesMaxRetries)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetConditionStatus first ensures we have a non-nil conditions list, then
+// sets the condition, only changing the last transition time if the status
+// changed.
+func (s *VitessMoveTablesStatus) SetConditionStatus(condType VitessMoveTablesConditionType, newStatus corev1.ConditionStatus, reason, message string) {
+	cond, ok := s.getCondition(condType)
+	if !ok {
+		cond = &VitessMoveTablesCondition{
+			Type:   condType,
+			Status: corev1.ConditionUnknown,
+		}
+	}
+
+	// We should update reason and message regardless of whether the status type is different.
+	cond.Reason = reason
+	cond.Message = message
+
+	if cond.Status != newStatus || cond.LastTransitionTime == nil {
+		now := metav1.NewTime(time.Now())
+		cond.Status = newStatus
+		cond.LastTransitionTime = &now
+	}
+
+	s.setCondition(cond)
+}
+
+// GetCondition provides map style access to retrieve a condition from the
+// conditions list by its type. If the condition doesn't exist, we return
+// false for the exists named return value.
+func (s *VitessMoveTablesStatus) GetCondition(ty VitessMoveTablesConditionType) (value VitessMoveTablesCondition, exists bool) {
+	cond, exists := s.getCondition(ty)
+	if !exists {
+		return VitessMoveTablesCondition{}, false
+	}
+	return *cond.DeepCopy(), true
+}
+
+func (s *VitessMoveTablesStatus) getCondition(ty VitessMoveTablesConditionType) (*VitessMoveTablesCondition, bool) {
+	for i := range s.Conditions {
+		condition := &s.Conditions[i]
+		if condition.Type == ty {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+func (s *VitessMoveTablesStatus) setCondition(newCondition *VitessMoveTablesCondition) {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == newCondition.Type {
+			s.Conditions[i] = *newCondition
+			return
+		}
+	}
+	s.Conditions = append(s.Conditions, *newCondition)
+}
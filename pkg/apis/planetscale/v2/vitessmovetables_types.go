@@ -0,0 +1,282 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessMoveTables drives a VReplication MoveTables workflow that moves
+// tables from one keyspace to another. The operator takes the workflow
+// through each of its phases: copying the tables and catching up on changes,
+// verifying the copy with VDiff, switching traffic to the target keyspace,
+// and cleaning up after the workflow.
+//
+// Until the workflow is completed, setting reverse to true rolls it back:
+// traffic is switched back to the source keyspace if needed, and the copies
+// of the tables in the target keyspace are dropped.
+//
+// The VitessMoveTables is owned by the VitessKeyspace of the target
+// keyspace, so it's deleted along with that keyspace.
+// +kubebuilder:resource:path=vitessmovetables,shortName=vtmt
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.sourceKeyspace"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetKeyspace"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Copied",type="integer",JSONPath=".status.copyProgress",description="Percent of rows copied to the target keyspace"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VitessMoveTables struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessMoveTablesSpec   `json:"spec,omitempty"`
+	Status VitessMoveTablesStatus `json:"status,omitempty"`
+}
+
+// VitessMoveTablesSpec defines the desired state of a VitessMoveTables.
+type VitessMoveTablesSpec struct {
+	// Cluster is the name of the VitessCluster, in the same namespace, that
+	// both keyspaces belong to.
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+
+	// SourceKeyspace is the name of the keyspace to move tables out of, as
+	// it's known to Vitess.
+	// +kubebuilder:validation:MinLength=1
+	SourceKeyspace string `json:"sourceKeyspace"`
+
+	// TargetKeyspace is the name of the keyspace to move tables into, as
+	// it's known to Vitess.
+	// +kubebuilder:validation:MinLength=1
+	TargetKeyspace string `json:"targetKeyspace"`
+
+	// Workflow is the name of the VReplication workflow.
+	//
+	// Default: The name of the VitessMoveTables object.
+	Workflow string `json:"workflow,omitempty"`
+
+	// Tables are the names of the tables to move.
+	// Either tables or allTables must be set.
+	Tables []string `json:"tables,omitempty"`
+
+	// AllTables can be set to true to move every table in the source
+	// keyspace, except those in excludeTables.
+	AllTables bool `json:"allTables,omitempty"`
+
+	// ExcludeTables are the names of tables to leave in the source keyspace
+	// when allTables is set.
+	ExcludeTables []string `json:"excludeTables,omitempty"`
+
+	// TabletTypes are the types of tablets, in order of preference, that
+	// VReplication may copy data from, such as "replica" or "primary".
+	//
+	// Default: replica, primary
+	TabletTypes []string `json:"tabletTypes,omitempty"`
+
+	// SkipVDiff can be set to true to switch traffic without first
+	// verifying the copied data with VDiff.
+	SkipVDiff bool `json:"skipVDiff,omitempty"`
+
+	// SwitchTraffic is whether traffic is switched to the target keyspace
+	// automatically once it's ready, or only after this is set to Auto.
+	// Setting it to Manual lets you look things over before traffic moves.
+	//
+	// Default: Auto
+	// +kubebuilder:validation:Enum=Auto;Manual
+	SwitchTraffic VitessSwitchTrafficMode `json:"switchTraffic,omitempty"`
+
+	// ApprovalRequired can be set to true to wait for approval before
+	// traffic is switched, even once switchTraffic is Auto. The data is
+	// still copied and verified with VDiff first, and then a ReadyForCutover
+	// event is emitted. To approve, set approved to true, or set the
+	// annotation "planetscale.com/approve-switch-traffic" to "true".
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
+	// Approved approves switching traffic when approvalRequired is true.
+	Approved bool `json:"approved,omitempty"`
+
+	// Complete is whether the workflow is completed automatically once
+	// traffic was switched, or only after this is set to Auto. Completing
+	// the workflow drops the tables from the source keyspace, so it can no
+	// longer be reversed. Setting it to Manual leaves time to make sure the
+	// target keyspace serves traffic well.
+	//
+	// Default: Auto
+	// +kubebuilder:validation:Enum=Auto;Manual
+	Complete VitessMoveTablesCompleteMode `json:"complete,omitempty"`
+
+	// Reverse can be set to true to roll the workflow back. Traffic is
+	// switched back to the source keyspace if needed, and the copies of the
+	// tables in the target keyspace are dropped. This has no effect once the
+	// workflow is Complete.
+	Reverse bool `json:"reverse,omitempty"`
+
+	// MaxReplicationLag is how far behind the source keyspace the target
+	// keyspace may be for traffic to be switched.
+	//
+	// Default: 30s
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// MaxRetries is how many times in a row a step of the workflow may fail
+	// before the VitessMoveTables gives up and goes to the Failed phase.
+	//
+	// Default: 5
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// VDiff schedules more VDiffs while the workflow waits to switch traffic,
+	// so the target keyspace keeps being checked against the source keyspace.
+	VDiff *VitessVDiffSchedule `json:"vdiff,omitempty"`
+}
+
+// VitessMoveTablesCompleteMode selects when a VitessMoveTables is completed
+// after traffic was switched.
+type VitessMoveTablesCompleteMode string
+
+const (
+	// MoveTablesCompleteAuto completes the workflow as soon as traffic was
+	// switched.
+	MoveTablesCompleteAuto VitessMoveTablesCompleteMode = "Auto"
+	// MoveTablesCompleteManual waits for the mode to be set to Auto before
+	// completing the workflow.
+	MoveTablesCompleteManual VitessMoveTablesCompleteMode = "Manual"
+)
+
+// VitessMoveTablesStatus defines the observed state of a VitessMoveTables.
+type VitessMoveTablesStatus struct {
+	// The generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is where the workflow is at.
+	Phase VitessMoveTablesPhase `json:"phase,omitempty"`
+
+	// Message describes what the current phase is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+
+	// CopyProgress is the percent of rows copied from the source keyspace to
+	// the target keyspace, from 0 to 100, or -1 if it's unknown.
+	CopyProgress int `json:"copyProgress,omitempty"`
+
+	// VDiffUUID identifies the VDiff that verifies the copied data.
+	VDiffUUID string `json:"vdiffUUID,omitempty"`
+
+	// LastVDiff summarizes the results of the last VDiff, whether it's the
+	// one that verifies the copied data or a scheduled one.
+	LastVDiff *VitessVDiffReport `json:"lastVDiff,omitempty"`
+
+	// Retries is how many times in a row the current step has failed.
+	Retries int32 `json:"retries,omitempty"`
+
+	// StartTime is when the VReplication workflow was created.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the workflow was completed or rolled back.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions is a list of all VitessMoveTables specific conditions we
+	// want to set and monitor.
+	Conditions []VitessMoveTablesCondition `json:"conditions,omitempty"`
+}
+
+// VitessMoveTablesPhase is where a VitessMoveTables is at.
+type VitessMoveTablesPhase string
+
+const (
+	// MoveTablesCopyingPhase means the VReplication workflow is copying
+	// tables to the target keyspace, or catching up on changes made during
+	// the copy.
+	MoveTablesCopyingPhase VitessMoveTablesPhase = "Copying"
+	// MoveTablesVerifyingPhase means a VDiff is comparing the source and
+	// target keyspaces.
+	MoveTablesVerifyingPhase VitessMoveTablesPhase = "Verifying"
+	// MoveTablesSwitchingTrafficPhase means traffic is being switched to the
+	// target keyspace, or is waiting to be.
+	MoveTablesSwitchingTrafficPhase VitessMoveTablesPhase = "SwitchingTraffic"
+	// MoveTablesCompletingPhase means the workflow is being cleaned up after
+	// traffic was switched, or is waiting to be.
+	MoveTablesCompletingPhase VitessMoveTablesPhase = "Completing"
+	// MoveTablesCompletePhase means the target keyspace serves all traffic
+	// for the tables and the workflow is done.
+	MoveTablesCompletePhase VitessMoveTablesPhase = "Complete"
+	// MoveTablesRollingBackPhase means traffic is being switched back to the
+	// source keyspace, and the workflow is being cancelled.
+	MoveTablesRollingBackPhase VitessMoveTablesPhase = "RollingBack"
+	// MoveTablesRolledBackPhase means the source keyspace serves all traffic
+	// for the tables again and the workflow is gone.
+	MoveTablesRolledBackPhase VitessMoveTablesPhase = "RolledBack"
+	// MoveTablesFailedPhase means the workflow can't go on without help,
+	// such as when VDiff found differences between the source and target
+	// keyspaces, or a step failed more than maxRetries times in a row.
+	// The workflow can still be rolled back by setting reverse to true.
+	MoveTablesFailedPhase VitessMoveTablesPhase = "Failed"
+)
+
+// VitessMoveTablesCondition contains details for the current condition of a
+// VitessMoveTables.
+type VitessMoveTablesCondition struct {
+	// Type is the type of the condition.
+	Type VitessMoveTablesConditionType `json:"type"`
+	// Status is the status of the condition.
+	// Can be True, False, Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// Optional.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Unique, one-word, PascalCase reason for the condition's last transition.
+	// Optional.
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	// Optional.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessMoveTablesConditionType is a valid value for the Type of a
+// VitessMoveTablesCondition.
+type VitessMoveTablesConditionType string
+
+// These are valid conditions of VitessMoveTables.
+const (
+	// VitessMoveTablesInSync indicates whether the target keyspace has
+	// copied all existing rows and is replicating changes from the source
+	// keyspace.
+	VitessMoveTablesInSync VitessMoveTablesConditionType = "InSync"
+	// VitessMoveTablesVDiffPassed indicates whether VDiff found the target
+	// keyspace to match the source keyspace.
+	VitessMoveTablesVDiffPassed VitessMoveTablesConditionType = "VDiffPassed"
+	// VitessMoveTablesTrafficSwitched indicates whether the target keyspace
+	// serves all traffic for the tables.
+	VitessMoveTablesTrafficSwitched VitessMoveTablesConditionType = "TrafficSwitched"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessMoveTablesList contains a list of VitessMoveTables.
+type VitessMoveTablesList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessMoveTables `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessMoveTables{}, &VitessMoveTablesList{})
+}
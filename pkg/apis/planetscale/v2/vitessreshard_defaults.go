@@ -0,0 +1,37 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultVitessReshard fills in VitessReshard defaults for unspecified fields.
+func DefaultVitessReshard(vtr *VitessReshard) {
+	if vtr.Spec.Workflow == "" {
+		vtr.Spec.Workflow = vtr.Name
+	}
+	if len(vtr.Spec.TabletTypes) == 0 {
+		vtr.Spec.TabletTypes = []string{"replica", "primary"}
+	}
+	if vtr.Spec.SwitchTraffic == "" {
+		vtr.Spec.SwitchTraffic = SwitchTrafficAuto
+	}
+	if vtr.Spec.MaxReplicationLag == nil {
+		vtr.Spec.MaxReplicationLag = &metav1.Duration{Duration: defaultVReplicationMaxReplicationLag}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessReshard drives a VReplication Reshard workflow that moves the data
+// of a keyspace from one set of shards to another. The operator takes the
+// workflow through each of its phases: making sure the target shards exist,
+// copying data and catching up on changes, verifying the copy with VDiff,
+// switching traffic to the target shards, and cleaning up after the workflow.
+//
+// Once a VitessReshard is Complete, the partitioning that holds the source
+// shards can be removed from the VitessCluster.
+// +kubebuilder:resource:path=vitessreshards,shortName=vtrs
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Keyspace",type="string",JSONPath=".spec.keyspace"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Copied",type="integer",JSONPath=".status.copyProgress",description="Percent of rows copied to the target shards"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type VitessReshard struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VitessReshardSpec   `json:"spec,omitempty"`
+	Status VitessReshardStatus `json:"status,omitempty"`
+}
+
+// VitessReshardSpec defines the desired state of a VitessReshard.
+type VitessReshardSpec struct {
+	// Cluster is the name of the VitessCluster, in the same namespace, that
+	// the keyspace belongs to.
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+
+	// Keyspace is the name of the keyspace to reshard, as it's known to
+	// Vitess.
+	// +kubebuilder:validation:MinLength=1
+	Keyspace string `json:"keyspace"`
+
+	// Workflow is the name of the VReplication workflow.
+	//
+	// Default: The name of the VitessReshard object.
+	Workflow string `json:"workflow,omitempty"`
+
+	// SourceShards are the names of the shards to move data out of,
+	// such as "-80" and "80-".
+	// +kubebuilder:validation:MinItems=1
+	SourceShards []string `json:"sourceShards"`
+
+	// TargetShards are the names of the shards to move data into. Together,
+	// they must cover the same key ranges as the source shards.
+	//
+	// If the keyspace doesn't have a partitioning with these shards yet, the
+	// operator adds one to the VitessCluster, with the same configuration as
+	// the source shards. To configure the target shards differently, add the
+	// partitioning yourself before creating the VitessReshard.
+	// +kubebuilder:validation:MinItems=1
+	TargetShards []string `json:"targetShards"`
+
+	// TabletTypes are the types of tablets, in order of preference, that
+	// VReplication may copy data from, such as "replica" or "primary".
+	//
+	// Default: replica, primary
+	TabletTypes []string `json:"tabletTypes,omitempty"`
+
+	// SkipVDiff can be set to true to switch traffic without first
+	// verifying the copied data with VDiff.
+	SkipVDiff bool `json:"skipVDiff,omitempty"`
+
+	// SwitchTraffic is whether traffic is switched to the target shards
+	// automatically once they're ready, or only after this is set to Auto.
+	// Setting it to Manual lets you look things over before traffic moves.
+	//
+	// Default: Auto
+	// +kubebuilder:validation:Enum=Auto;Manual
+	SwitchTraffic VitessSwitchTrafficMode `json:"switchTraffic,omitempty"`
+
+	// ApprovalRequired can be set to true to wait for approval before
+	// traffic is switched, even once switchTraffic is Auto. The data is
+	// still copied and verified with VDiff first, and then a ReadyForCutover
+	// event is emitted. To approve, set approved to true, or set the
+	// annotation "planetscale.com/approve-switch-traffic" to "true".
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
+	// Approved approves switching traffic when approvalRequired is true.
+	Approved bool `json:"approved,omitempty"`
+
+	// MaxReplicationLag is how far behind the source shards the target shards
+	// may be for traffic to be switched.
+	//
+	// Default: 30s
+	MaxReplicationLag *metav1.Duration `json:"maxReplicationLag,omitempty"`
+
+	// VDiff schedules more VDiffs while the workflow waits to switch traffic,
+	// so the target shards keep being checked against the source shards.
+	VDiff *VitessVDiffSchedule `json:"vdiff,omitempty"`
+}
+
+// VitessSwitchTrafficMode selects when a VReplication workflow switches
+// traffic to its target.
+type VitessSwitchTrafficMode string
+
+const (
+	// SwitchTrafficAuto switches traffic as soon as the target is ready.
+	SwitchTrafficAuto VitessSwitchTrafficMode = "Auto"
+	// SwitchTrafficManual waits for the mode to be set to Auto before
+	// switching traffic.
+	SwitchTrafficManual VitessSwitchTrafficMode = "Manual"
+)
+
+// VitessVDiffSchedule configures VDiffs that run periodically on a
+// VReplication workflow.
+type VitessVDiffSchedule struct {
+	// Schedule is when to run a VDiff, in standard cron format, such as
+	// "0 */6 * * *" for every 6 hours. Times are in UTC.
+	//
+	// A VDiff that's still running when the next one is due delays the next
+	// one, and traffic isn't switched until a running VDiff finishes.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+}
+
+// VitessVDiffReport summarizes the results of a VDiff.
+type VitessVDiffReport struct {
+	// UUID identifies the VDiff.
+	UUID string `json:"uuid"`
+
+	// StartTime is when the VDiff was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the VDiff finished comparing every table, or
+	// found differences.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Mismatch is whether the VDiff found differences between the source
+	// and the target.
+	Mismatch bool `json:"mismatch,omitempty"`
+
+	// RowsCompared is how many rows were compared, in all tables.
+	RowsCompared int64 `json:"rowsCompared,omitempty"`
+
+	// Tables lists the differences found in each table that has any.
+	Tables []VitessVDiffTableReport `json:"tables,omitempty"`
+
+	// Message is the last error that VDiff reported, if any.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessVDiffTableReport counts the differences that a VDiff found in a
+// table.
+type VitessVDiffTableReport struct {
+	// Name is the name of the table.
+	Name string `json:"name"`
+
+	// MismatchedRows is how many rows differ between the source and the
+	// target.
+	MismatchedRows int64 `json:"mismatchedRows,omitempty"`
+
+	// ExtraSourceRows is how many rows are only in the source.
+	ExtraSourceRows int64 `json:"extraSourceRows,omitempty"`
+
+	// ExtraTargetRows is how many rows are only in the target.
+	ExtraTargetRows int64 `json:"extraTargetRows,omitempty"`
+}
+
+// VitessReshardStatus defines the observed state of a VitessReshard.
+type VitessReshardStatus struct {
+	// The generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is where the workflow is at.
+	Phase VitessReshardPhase `json:"phase,omitempty"`
+
+	// Message describes what the current phase is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+
+	// CopyProgress is the percent of rows copied from the source shards to
+	// the target shards, from 0 to 100, or -1 if it's unknown.
+	CopyProgress int `json:"copyProgress,omitempty"`
+
+	// VDiffUUID identifies the VDiff that verifies the copied data.
+	VDiffUUID string `json:"vdiffUUID,omitempty"`
+
+	// LastVDiff summarizes the results of the last VDiff, whether it's the
+	// one that verifies the copied data or a scheduled one.
+	LastVDiff *VitessVDiffReport `json:"lastVDiff,omitempty"`
+
+	// StartTime is when the VReplication workflow was created.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the workflow was completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Conditions is a list of all VitessReshard specific conditions we want
+	// to set and monitor.
+	Conditions []VitessReshardCondition `json:"conditions,omitempty"`
+}
+
+// VitessReshardPhase is where a VitessReshard is at.
+type VitessReshardPhase string
+
+const (
+	// ReshardCreatingTargetShardsPhase means the target shards are being
+	// deployed and initialized.
+	ReshardCreatingTargetShardsPhase VitessReshardPhase = "CreatingTargetShards"
+	// ReshardCopyingPhase means the VReplication workflow is copying data to
+	// the target shards, or catching up on changes made during the copy.
+	ReshardCopyingPhase VitessReshardPhase = "Copying"
+	// ReshardVerifyingPhase means a VDiff is comparing the source and target
+	// shards.
+	ReshardVerifyingPhase VitessReshardPhase = "Verifying"
+	// ReshardSwitchingTrafficPhase means traffic is being switched to the
+	// target shards, or is waiting to be.
+	ReshardSwitchingTrafficPhase VitessReshardPhase = "SwitchingTraffic"
+	// ReshardCompletingPhase means the workflow is being cleaned up after
+	// traffic was switched.
+	ReshardCompletingPhase VitessReshardPhase = "Completing"
+	// ReshardCompletePhase means the target shards serve all traffic and the
+	// workflow is done.
+	ReshardCompletePhase VitessReshardPhase = "Complete"
+	// ReshardFailedPhase means the workflow can't go on without help, such
+	// as when VDiff found differences between the source and target shards.
+	ReshardFailedPhase VitessReshardPhase = "Failed"
+)
+
+// VitessReshardCondition contains details for the current condition of a
+// VitessReshard.
+type VitessReshardCondition struct {
+	// Type is the type of the condition.
+	Type VitessReshardConditionType `json:"type"`
+	// Status is the status of the condition.
+	// Can be True, False, Unknown.
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Last time the condition transitioned from one status to another.
+	// Optional.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Unique, one-word, PascalCase reason for the condition's last transition.
+	// Optional.
+	Reason string `json:"reason,omitempty"`
+	// Human-readable message indicating details about last transition.
+	// Optional.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessReshardConditionType is a valid value for the Type of a
+// VitessReshardCondition.
+type VitessReshardConditionType string
+
+// These are valid conditions of VitessReshard.
+const (
+	// VitessReshardTargetShardsReady indicates whether every target shard has
+	// a primary that VReplication can copy data into.
+	VitessReshardTargetShardsReady VitessReshardConditionType = "TargetShardsReady"
+	// VitessReshardInSync indicates whether the target shards have copied
+	// all existing data and are replicating changes from the source shards.
+	VitessReshardInSync VitessReshardConditionType = "InSync"
+	// VitessReshardVDiffPassed indicates whether VDiff found the target
+	// shards to match the source shards.
+	VitessReshardVDiffPassed VitessReshardConditionType = "VDiffPassed"
+	// VitessReshardTrafficSwitched indicates whether the target shards serve
+	// all traffic.
+	VitessReshardTrafficSwitched VitessReshardConditionType = "TrafficSwitched"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VitessReshardList contains a list of VitessReshards.
+type VitessReshardList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VitessReshard `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VitessReshard{}, &VitessReshardList{})
+}
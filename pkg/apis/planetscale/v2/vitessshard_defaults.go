@@ -17,6 +17,11 @@ limitations under the License.
 package v2
 
 import (
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
 
@@ -27,7 +32,11 @@ import (
 func DefaultVitessShard(dst *VitessShard) {
 	DefaultUpdateStrategy(&dst.Spec.UpdateStrategy)
 	DefaultTopoReconcileConfig(&dst.Spec.TopologyReconciliation)
+	DefaultReparentSettings(&dst.Spec.ReparentSettings)
 	DefaultVitessShardTemplate(&dst.Spec.VitessShardTemplate)
+	if dst.Spec.VitessOrchestrator != nil {
+		DefaultVitessOrchestrator(&dst.Spec.VitessOrchestrator)
+	}
 }
 
 func DefaultVitessShardTemplate(shardTemplate *VitessShardTemplate) {
@@ -36,6 +45,168 @@ func DefaultVitessShardTemplate(shardTemplate *VitessShardTemplate) {
 	}
 
 	DefaultVitessReplicationSpec(&shardTemplate.Replication)
+	defaultVitessShardPrimaryAffinity(shardTemplate.PrimaryAffinity)
+
+	for i := range shardTemplate.TabletPools {
+		DefaultVitessShardTabletPool(&shardTemplate.TabletPools[i])
+	}
+}
+
+func DefaultVitessShardTabletPool(pool *VitessShardTabletPool) {
+	// Drain rdonly tablets before any others by default.
+	if pool.DrainOrder == nil {
+		switch pool.Type {
+		case RdonlyPoolType, ExternalRdonlyPoolType:
+			pool.DrainOrder = pointer.Int32Ptr(defaultRdonlyPoolDrainOrder)
+		default:
+			pool.DrainOrder = pointer.Int32Ptr(defaultPoolDrainOrder)
+		}
+	}
+	if pool.StorageType == "" {
+		pool.StorageType = PersistentStorageType
+	}
+	if pool.ZoneSpread != nil {
+		DefaultVitessZoneSpread(pool.ZoneSpread)
+	}
+	if pool.ExternalDatastore != nil {
+		defaultExternalDatastore(pool.ExternalDatastore)
+	}
+	if pool.Vttablet.QueryServing != nil {
+		defaultVttabletQueryServing(pool.Vttablet.QueryServing, pool.QueryServingCPUs())
+	}
+	if pool.Mysqld != nil && pool.Mysqld.QueryLogs != nil {
+		defaultMysqldQueryLogs(pool.Mysqld.QueryLogs)
+	}
+	if pool.Mysqld != nil && pool.Mysqld.AuditLog != nil {
+		defaultMysqldAuditLog(pool.Mysqld.AuditLog)
+	}
+	for i := range pool.MysqlUsers {
+		defaultMysqlUser(&pool.MysqlUsers[i])
+	}
+}
+
+func defaultMysqlUser(user *MysqlUser) {
+	if len(user.Hosts) == 0 {
+		user.Hosts = []string{defaultMysqlUserHost}
+	}
+	for i := range user.Grants {
+		grant := &user.Grants[i]
+		if grant.Database == "" {
+			grant.Database = defaultMysqlGrantDatabase
+		}
+		if grant.Table == "" {
+			grant.Table = defaultMysqlGrantTable
+		}
+	}
+}
+
+func defaultMysqldQueryLogs(queryLogs *MysqldQueryLogs) {
+	if queryLogs.SlowQueryLog != nil && queryLogs.SlowQueryLog.LongQueryTime == nil {
+		queryLogs.SlowQueryLog.LongQueryTime = &metav1.Duration{Duration: defaultSlowQueryLogLongQueryTime}
+	}
+	if queryLogs.MaxSize == nil {
+		maxSize := resource.MustParse(defaultQueryLogMaxSize)
+		queryLogs.MaxSize = &maxSize
+	}
+	if queryLogs.MaxFiles == nil {
+		queryLogs.MaxFiles = pointer.Int32Ptr(defaultQueryLogMaxFiles)
+	}
+	if queryLogs.Sink == "" {
+		queryLogs.Sink = FileMysqldQueryLogSink
+	}
+}
+
+func defaultMysqldAuditLog(auditLog *MysqldAuditLog) {
+	if auditLog.Destination == "" {
+		auditLog.Destination = FileMysqldAuditLogDestination
+	}
+	if auditLog.Format == "" {
+		auditLog.Format = defaultAuditLogFormat
+	}
+	if auditLog.Policy == "" {
+		auditLog.Policy = defaultAuditLogPolicy
+	}
+	if auditLog.RotateOnSize == nil {
+		rotateOnSize := resource.MustParse(defaultAuditLogRotateOnSize)
+		auditLog.RotateOnSize = &rotateOnSize
+	}
+	if auditLog.Rotations == nil {
+		auditLog.Rotations = pointer.Int32Ptr(defaultAuditLogRotations)
+	}
+}
+
+func defaultVttabletQueryServing(queryServing *VttabletQueryServingSpec, cpus float64) {
+	if queryServing.PoolSize == nil {
+		queryServing.PoolSize = pointer.Int32Ptr(scaleByCPUs(cpus, defaultQueryServingPoolSizePerCPU, defaultQueryServingMinPoolSize, defaultQueryServingMaxPoolSize))
+	}
+	if queryServing.StreamPoolSize == nil {
+		queryServing.StreamPoolSize = pointer.Int32Ptr(*queryServing.PoolSize)
+	}
+	if queryServing.TransactionCap == nil {
+		queryServing.TransactionCap = pointer.Int32Ptr(scaleByCPUs(cpus, defaultQueryServingTransactionCapPerCPU, defaultQueryServingMinTransactionCap, defaultQueryServingMaxTransactionCap))
+	}
+	if queryServing.QueryTimeout == nil {
+		queryServing.QueryTimeout = &metav1.Duration{Duration: defaultQueryServingQueryTimeout}
+	}
+	if queryServing.MaxResultSize == nil {
+		queryServing.MaxResultSize = pointer.Int32Ptr(defaultQueryServingMaxResultSize)
+	}
+	if queryServing.HotRowProtection != nil && queryServing.HotRowProtection.Mode == "" {
+		queryServing.HotRowProtection.Mode = defaultHotRowProtectionMode
+	}
+}
+
+// scaleByCPUs returns perCPU for every CPU, rounded up and kept between min
+// and max. If the number of CPUs isn't known, it returns max, which is what
+// every tablet got before these settings could be tuned.
+func scaleByCPUs(cpus float64, perCPU, min, max int32) int32 {
+	if cpus <= 0 {
+		return max
+	}
+	value := int32(math.Ceil(cpus * float64(perCPU)))
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
+
+func defaultExternalDatastore(datastore *ExternalDatastore) {
+	preset := externalDatastorePresets[datastore.Provider]
+	if datastore.SSLMode == "" {
+		if datastore.ServerCACertSecret != nil || preset.systemCAs {
+			datastore.SSLMode = preset.verifiedSSLMode
+		} else {
+			datastore.SSLMode = preset.unverifiedSSLMode
+		}
+	}
+	if datastore.Failover == nil && preset.watchDNS {
+		datastore.Failover = &ExternalFailoverSpec{WatchDNS: true}
+	}
+	defaultExternalFailover(datastore.Failover)
+	if datastore.DemotePrimaryToSpare == nil {
+		datastore.DemotePrimaryToSpare = pointer.Bool(defaultDemotePrimaryToSpare)
+	}
+}
+
+func defaultExternalFailover(failover *ExternalFailoverSpec) {
+	if failover == nil {
+		return
+	}
+	if failover.CheckInterval == nil {
+		failover.CheckInterval = &metav1.Duration{Duration: defaultExternalFailoverCheckInterval}
+	}
+}
+
+func defaultVitessShardPrimaryAffinity(affinity *VitessShardPrimaryAffinity) {
+	if affinity == nil {
+		return
+	}
+	if affinity.StableFor == nil {
+		affinity.StableFor = &metav1.Duration{Duration: defaultPrimaryAffinityStableFor}
+	}
 }
 
 func DefaultVitessReplicationSpec(replicationSpec *VitessReplicationSpec) {
@@ -53,4 +224,20 @@ func DefaultVitessReplicationSpec(replicationSpec *VitessReplicationSpec) {
 	if replicationSpec.RecoverRestartedMaster == nil {
 		replicationSpec.RecoverRestartedMaster = pointer.BoolPtr(true)
 	}
+
+	// Only allow one tablet at a time to finish draining by default.
+	if replicationSpec.DrainMaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt(defaultDrainMaxUnavailable)
+		replicationSpec.DrainMaxUnavailable = &maxUnavailable
+	}
+
+	// Use asynchronous replication by default.
+	if replicationSpec.Mode == "" {
+		replicationSpec.Mode = AsyncReplicationMode
+	}
+
+	// Require acks from any replica by default in semi-sync mode.
+	if replicationSpec.SemiSyncDurabilityPolicy == "" {
+		replicationSpec.SemiSyncDurabilityPolicy = SemiSyncDurabilityPolicy
+	}
 }
@@ -17,14 +17,23 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// mysqlPrivilegePattern matches the names of MySQL privileges, such as
+// "SELECT" or "ALL PRIVILEGES".
+var mysqlPrivilegePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z_ ]*$`)
+
 // IsExternalMaster indicates whether the tablet is in a pool of type "externalmaster".
 func (t *VitessTabletStatus) IsExternalMaster() bool {
 	return t.PoolType == ExternalMasterTabletPoolName
@@ -63,6 +72,53 @@ func (t *VitessShardTabletPool) IsMatch(inputPool *VitessShardTabletPool) bool {
 	return t.Type == inputPool.Type && t.Cell == inputPool.Cell && t.Name == inputPool.Name
 }
 
+// ReplicationDelay returns how far behind the primary tablets in the pool
+// should deliberately apply changes, or 0 if replication isn't delayed.
+func (t *VitessShardTabletPool) ReplicationDelay() time.Duration {
+	if t.DelayedReplication == nil || t.DelayedReplication.Duration < 0 {
+		return 0
+	}
+	return t.DelayedReplication.Duration
+}
+
+// EphemeralStorage returns whether tablets in the pool keep their data on
+// volumes that are deleted along with their Pods. Only rdonly pools with a
+// local mysqld can use ephemeral storage.
+func (t *VitessShardTabletPool) EphemeralStorage() bool {
+	return t.StorageType == EphemeralStorageType && t.Type == RdonlyPoolType && t.Mysqld != nil && t.ExternalDatastore == nil
+}
+
+// StorageTypeProblems returns why each tablet pool in the shard that asks for
+// ephemeral storage can't use it, if it can't.
+func (s *VitessShardSpec) StorageTypeProblems() []string {
+	var problems []string
+	for i := range s.TabletPools {
+		pool := &s.TabletPools[i]
+		if pool.StorageType != EphemeralStorageType {
+			continue
+		}
+		switch {
+		case !pool.EphemeralStorage():
+			problems = append(problems, fmt.Sprintf("tablet pool %v/%v: ephemeral storage is only allowed for rdonly pools with a local mysqld, so the pool uses persistent storage", pool.Cell, pool.Type))
+		case s.BackupLocation(pool.BackupLocationName) == nil:
+			problems = append(problems, fmt.Sprintf("tablet pool %v/%v: ephemeral storage needs a backup location to restore tablets from, so rescheduled tablets start out empty", pool.Cell, pool.Type))
+		}
+	}
+	return problems
+}
+
+// Matches returns whether a tablet, given by its alias and cell, is one the
+// affinity designates as a primary.
+func (a *VitessShardPrimaryAffinity) Matches(tabletAlias, cell string) bool {
+	if a.PreferredPrimaryTablet != "" {
+		return tabletAlias == a.PreferredPrimaryTablet
+	}
+	if a.Cell != "" {
+		return cell == a.Cell
+	}
+	return false
+}
+
 // UsingExternalDatastore indicates whether the VitessShard Spec is using
 // externally managed MySQL for any of its tablet pools.
 func (s *VitessShardSpec) UsingExternalDatastore() bool {
@@ -76,6 +132,240 @@ func (s *VitessShardSpec) UsingExternalDatastore() bool {
 	return false
 }
 
+// QueryServingCPUs returns the number of CPUs that the pool's query serving
+// defaults are derived from: the CPU limit, or else the request, of mysqld,
+// or of vttablet if the pool uses an external datastore. It returns 0 if
+// neither is set.
+func (p *VitessShardTabletPool) QueryServingCPUs() float64 {
+	resources := &p.Vttablet.Resources
+	if p.Mysqld != nil {
+		resources = &p.Mysqld.Resources
+	}
+	if cpu, ok := resources.Limits[corev1.ResourceCPU]; ok {
+		return cpu.AsApproximateFloat64()
+	}
+	if cpu, ok := resources.Requests[corev1.ResourceCPU]; ok {
+		return cpu.AsApproximateFloat64()
+	}
+	return 0
+}
+
+const (
+	// vttabletHotRowMaxQueueSize and vttabletHotRowMaxGlobalQueueSize are
+	// vttablet's own defaults for the hot row protection queues.
+	vttabletHotRowMaxQueueSize       = 20
+	vttabletHotRowMaxGlobalQueueSize = 1000
+)
+
+// Problems returns the reasons, if any, that vttablet would refuse the query
+// serving settings.
+func (s *VttabletQueryServingSpec) Problems() []string {
+	var problems []string
+	if hotRow := s.HotRowProtection; hotRow != nil {
+		queueSize := int32(vttabletHotRowMaxQueueSize)
+		if hotRow.MaxQueueSize != nil {
+			queueSize = *hotRow.MaxQueueSize
+		}
+		globalQueueSize := int32(vttabletHotRowMaxGlobalQueueSize)
+		if hotRow.MaxGlobalQueueSize != nil {
+			globalQueueSize = *hotRow.MaxGlobalQueueSize
+		}
+		if globalQueueSize < queueSize {
+			problems = append(problems, fmt.Sprintf("hotRowProtection.maxGlobalQueueSize (%d) is less than maxQueueSize (%d)", globalQueueSize, queueSize))
+		}
+	}
+	return problems
+}
+
+// Problems returns the reasons the query log settings can't be applied as
+// written.
+func (q *MysqldQueryLogs) Problems() []string {
+	var problems []string
+	if q.SlowQueryLog == nil && !q.GeneralLog {
+		problems = append(problems, "queryLogs turns on neither slowQueryLog nor generalLog")
+	}
+	if q.Sink == FluentBitMysqldQueryLogSink && q.FluentBit == nil {
+		problems = append(problems, "queryLogs.sink is fluentBit, but queryLogs.fluentBit isn't set, so logs are only kept on the data volume")
+	}
+	return problems
+}
+
+// Problems returns reasons the audit log plugin won't accept some of its
+// settings, which are left out of the mysqld config.
+func (a *MysqldAuditLog) Problems() []string {
+	var problems []string
+	if a.Plugin != PerconaMysqldAuditLogPlugin {
+		if a.Format == "CSV" {
+			problems = append(problems, fmt.Sprintf("auditLog.format CSV isn't supported by the %v plugin, so the plugin's default is used", a.Plugin))
+		}
+		if f := a.Filter; f != nil && (len(f.IncludeCommands) > 0 || len(f.ExcludeCommands) > 0 || len(f.IncludeDatabases) > 0 || len(f.ExcludeDatabases) > 0) {
+			problems = append(problems, fmt.Sprintf("auditLog.filter can only select accounts with the %v plugin, so commands and databases are ignored", a.Plugin))
+		}
+	}
+	if f := a.Filter; f != nil {
+		if len(f.IncludeAccounts) > 0 && len(f.ExcludeAccounts) > 0 {
+			problems = append(problems, "auditLog.filter can't both include and exclude accounts, so excludeAccounts is ignored")
+		}
+		if len(f.IncludeCommands) > 0 && len(f.ExcludeCommands) > 0 {
+			problems = append(problems, "auditLog.filter can't both include and exclude commands, so excludeCommands is ignored")
+		}
+		if len(f.IncludeDatabases) > 0 && len(f.ExcludeDatabases) > 0 {
+			problems = append(problems, "auditLog.filter can't both include and exclude databases, so excludeDatabases is ignored")
+		}
+	}
+	return problems
+}
+
+// ExternalMasterDatastore returns the external datastore of the first
+// "externalmaster" pool, or nil if there are none.
+func (s *VitessShardSpec) ExternalMasterDatastore() *ExternalDatastore {
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Type == ExternalMasterPoolType && p.ExternalDatastore != nil {
+			return p.ExternalDatastore
+		}
+	}
+	return nil
+}
+
+// ExternalFailover returns the external datastore of the first
+// "externalmaster" pool that configures failover settings, or nil if none do.
+func (s *VitessShardSpec) ExternalFailover() *ExternalDatastore {
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Type == ExternalMasterPoolType && p.ExternalDatastore != nil && p.ExternalDatastore.Failover != nil {
+			return p.ExternalDatastore
+		}
+	}
+	return nil
+}
+
+// externalDatastorePreset holds the settings that a provider preset fills in
+// for an external datastore.
+type externalDatastorePreset struct {
+	// verifiedSSLMode is the SSL mode to use when there's a CA to verify the
+	// server against, and unverifiedSSLMode is the one to use otherwise.
+	verifiedSSLMode, unverifiedSSLMode string
+	// systemCAs is whether the provider's server certificates are signed by
+	// a CA in the system bundle.
+	systemCAs bool
+	// watchDNS is whether failovers move the writer endpoint's DNS record.
+	watchDNS bool
+}
+
+var externalDatastorePresets = map[ExternalDatastoreProvider]externalDatastorePreset{
+	AuroraExternalDatastoreProvider: {
+		verifiedSSLMode:   "verify_identity",
+		unverifiedSSLMode: "required",
+		watchDNS:          true,
+	},
+	CloudSQLExternalDatastoreProvider: {
+		// Cloud SQL server certificates don't name the IP we connect to.
+		verifiedSSLMode:   "verify_ca",
+		unverifiedSSLMode: "required",
+	},
+	AzureMySQLExternalDatastoreProvider: {
+		verifiedSSLMode:   "verify_identity",
+		unverifiedSSLMode: "required",
+		systemCAs:         true,
+		watchDNS:          true,
+	},
+}
+
+// UsesSystemCAs returns whether vttablet should verify the datastore's server
+// certificate against the system CA bundle, because its provider's CA is in
+// there and no other CA was given.
+func (d *ExternalDatastore) UsesSystemCAs() bool {
+	return d.ServerCACertSecret == nil && externalDatastorePresets[d.Provider].systemCAs
+}
+
+// UpgradeChecksEnabled returns whether new vttablet or mysqld images must pass
+// safety checks before they're rolled out to the shard.
+func (s *VitessShardSpec) UpgradeChecksEnabled() bool {
+	return s.UpdateStrategy != nil && s.UpdateStrategy.UpgradeChecks != nil && !s.UsingExternalDatastore()
+}
+
+// MysqldExporterMonitoringUser returns whether the exporter of any tablet
+// pool with a local MySQL connects as the operator-managed monitoring user.
+func (s *VitessShardSpec) MysqldExporterMonitoringUser() bool {
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Mysqld != nil && p.MysqldExporter != nil && p.MysqldExporter.MonitoringUser {
+			return true
+		}
+	}
+	return false
+}
+
+// MysqlUsers returns the MySQL users of all tablet pools with a local MySQL,
+// sorted by username, along with problems that keep any of them from being
+// managed. Users with problems are left out.
+func (s *VitessShardSpec) MysqlUsers() ([]MysqlUser, []string) {
+	byName := map[string]*MysqlUser{}
+	invalid := sets.NewString()
+	var problems []string
+	for i := range s.TabletPools {
+		p := &s.TabletPools[i]
+		if p.Mysqld == nil {
+			continue
+		}
+		for j := range p.MysqlUsers {
+			user := &p.MysqlUsers[j]
+			if invalid.Has(user.Username) {
+				continue
+			}
+			if problem := user.problem(); problem != "" {
+				problems = append(problems, fmt.Sprintf("mysqlUsers %q: %v", user.Username, problem))
+				invalid.Insert(user.Username)
+				delete(byName, user.Username)
+				continue
+			}
+			if other, ok := byName[user.Username]; ok && !reflect.DeepEqual(user, other) {
+				problems = append(problems, fmt.Sprintf("mysqlUsers %q: listed differently in more than one tablet pool", user.Username))
+				invalid.Insert(user.Username)
+				delete(byName, user.Username)
+				continue
+			}
+			byName[user.Username] = user
+		}
+	}
+
+	users := make([]MysqlUser, 0, len(byName))
+	for _, user := range byName {
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	sort.Strings(problems)
+	return users, problems
+}
+
+// problem returns why a MySQL user can't be managed, or "" if it can.
+func (u *MysqlUser) problem() string {
+	name := strings.ToLower(u.Username)
+	if strings.HasPrefix(name, "vt_") || strings.HasPrefix(name, "mysql.") || name == "root" {
+		return "username is reserved"
+	}
+	if u.SecretRef.Name == "" || u.SecretRef.Key == "" {
+		return "secretRef must specify a Secret name and key"
+	}
+	for _, grant := range u.Grants {
+		if len(grant.Privileges) == 0 {
+			return "each grant must list privileges"
+		}
+		for _, privilege := range grant.Privileges {
+			if !mysqlPrivilegePattern.MatchString(privilege) {
+				return fmt.Sprintf("%q isn't a privilege", privilege)
+			}
+		}
+		if grant.Database == "*" && grant.Table != "*" {
+			return fmt.Sprintf("grant on table %q must name a database", grant.Table)
+		}
+	}
+	return ""
+}
+
 // AllPoolsUsingMysqld returns a boolean indicating whether the VitessShard Spec is using
 // local MySQL for all of it's pools by checking the Mysqld field of all tablet pools.
 func (s *VitessShardSpec) AllPoolsUsingMysqld() bool {
@@ -94,6 +384,9 @@ func (s *VitessShardSpec) MasterEligibleTabletCount() int32 {
 	count := int32(0)
 	for poolIndex := range s.TabletPools {
 		pool := &s.TabletPools[poolIndex]
+		if pool.ReplicationDelay() > 0 {
+			continue
+		}
 		if pool.Type == ReplicaPoolType || pool.Type == ExternalMasterPoolType {
 			count += pool.Replicas
 		}
@@ -101,6 +394,102 @@ func (s *VitessShardSpec) MasterEligibleTabletCount() int32 {
 	return count
 }
 
+// SemiSyncDurability returns the durability policy that the shard's
+// replication settings call for, or an empty string if the shard isn't in
+// semiSync mode. It works whether or not the settings have been defaulted.
+func (r *VitessReplicationSpec) SemiSyncDurability() string {
+	if r.Mode != SemiSyncReplicationMode {
+		return ""
+	}
+	if r.SemiSyncDurabilityPolicy == "" {
+		return SemiSyncDurabilityPolicy
+	}
+	return r.SemiSyncDurabilityPolicy
+}
+
+// SemiSyncProblems returns the reasons, if any, that the shard's tablet pools
+// can't always provide semi-sync acks for the primary under the given
+// durability policy.
+func (s *VitessShardTemplate) SemiSyncProblems(durabilityPolicy string) []string {
+	var total int32
+	replicasPerCell := map[string]int32{}
+	for poolIndex := range s.TabletPools {
+		pool := &s.TabletPools[poolIndex]
+		if pool.Type == ReplicaPoolType {
+			replicasPerCell[pool.Cell] += pool.Replicas
+			total += pool.Replicas
+		}
+	}
+
+	var problems []string
+	switch durabilityPolicy {
+	case CrossCellDurabilityPolicy:
+		// A primary in any cell needs at least one replica in another cell.
+		cells := make([]string, 0, len(replicasPerCell))
+		for cell, count := range replicasPerCell {
+			if count > 0 {
+				cells = append(cells, cell)
+			}
+		}
+		sort.Strings(cells)
+		for _, cell := range cells {
+			if total-replicasPerCell[cell] < 1 {
+				problems = append(problems, fmt.Sprintf("a primary in cell %v would have no replica in another cell to ack writes", cell))
+			}
+		}
+	case SemiSyncDurabilityPolicy:
+		// A primary needs at least one other replica.
+		if total < 2 {
+			problems = append(problems, fmt.Sprintf("at least 2 replica-type tablets are needed for a primary to have a replica to ack writes, but only %d are configured", total))
+		}
+	}
+	return problems
+}
+
+// RemoveTabletPoolsInCells removes the tablet pools in the given cells, and
+// returns whether any were removed.
+func (s *VitessShardTemplate) RemoveTabletPoolsInCells(cells sets.String) bool {
+	pools := s.TabletPools[:0]
+	for i := range s.TabletPools {
+		if !cells.Has(s.TabletPools[i].Cell) {
+			pools = append(pools, s.TabletPools[i])
+		}
+	}
+	removed := len(pools) != len(s.TabletPools)
+	s.TabletPools = pools
+	return removed
+}
+
+// GuaranteedReplicaZones returns the number of distinct zones that the
+// shard's replica-type tablets are guaranteed to land in. Tablets in cells
+// with a zone land in that zone. Tablets in other cells might share a zone
+// with any of them, so those only count if their zoneSpread guarantees more.
+func (s *VitessShardSpec) GuaranteedReplicaZones() int32 {
+	cellZones := sets.NewString()
+	var spreadZones int32
+	for i := range s.TabletPools {
+		pool := &s.TabletPools[i]
+		if pool.Type != ReplicaPoolType || pool.Replicas <= 0 {
+			continue
+		}
+		if zone := s.ZoneMap[pool.Cell]; zone != "" {
+			cellZones.Insert(zone)
+			continue
+		}
+		zones := int32(1)
+		if pool.ZoneSpread != nil {
+			zones = pool.ZoneSpread.GuaranteedZones(pool.Replicas)
+		}
+		if zones > spreadZones {
+			spreadZones = zones
+		}
+	}
+	if int32(cellZones.Len()) > spreadZones {
+		return int32(cellZones.Len())
+	}
+	return spreadZones
+}
+
 // BackupLocation looks up a backup location in the list by name.
 // It returns nil if no location by that name exists.
 func (s *VitessShardSpec) BackupLocation(name string) *VitessBackupLocation {
@@ -225,6 +614,54 @@ func (s *VitessShardStatus) DeepCopyConditions() map[VitessShardConditionType]Vi
 	return out
 }
 
+// maxReparentHistory is the number of reparents kept in ReparentHistory.
+const maxReparentHistory = 10
+
+// RecordReparent adds a reparent to the front of the shard's ReparentHistory,
+// dropping the oldest entries beyond the limit.
+func (s *VitessShardStatus) RecordReparent(record VitessShardReparentRecord) {
+	history := make([]VitessShardReparentRecord, 0, len(s.ReparentHistory)+1)
+	history = append(history, record)
+	history = append(history, s.ReparentHistory...)
+	if len(history) > maxReparentHistory {
+		history = history[:maxReparentHistory]
+	}
+	s.ReparentHistory = history
+}
+
+// maxRecentRecoveries is the number of records kept in RecentRecoveries.
+const maxRecentRecoveries = 10
+
+// RecordRecovery adds vtorc recoveries to the front of RecentRecoveries,
+// dropping the oldest entries beyond the limit.
+func (s *VitessOrchestratorStatus) RecordRecovery(record VitessOrchestratorRecoveryRecord) {
+	recoveries := make([]VitessOrchestratorRecoveryRecord, 0, len(s.RecentRecoveries)+1)
+	recoveries = append(recoveries, record)
+	recoveries = append(recoveries, s.RecentRecoveries...)
+	if len(recoveries) > maxRecentRecoveries {
+		recoveries = recoveries[:maxRecentRecoveries]
+	}
+	s.RecentRecoveries = recoveries
+}
+
+// InitialRestoreComplete returns whether the shard has had a primary since it
+// was bootstrapped from the backups of its initialRestore.
+func (s *VitessShardStatus) InitialRestoreComplete() bool {
+	return s.Conditions[VitessShardInitialRestoreComplete].Status == corev1.ConditionTrue
+}
+
+// UpgradeChecksPassed returns whether the pre-upgrade checks have passed for
+// the image upgrade that's pending on the shard.
+func (s *VitessShardStatus) UpgradeChecksPassed() bool {
+	return s.Conditions[VitessShardUpgradeBlocked].Status == corev1.ConditionFalse
+}
+
+// RolloutStuck returns whether the rollout of the shard is stuck because an
+// updated tablet Pod keeps crashing.
+func (s *VitessShardStatus) RolloutStuck() bool {
+	return s.Conditions[VitessShardRolloutStuck].Status == corev1.ConditionTrue
+}
+
 // TabletAliases returns a sorted list of desired tablet aliases for the shard.
 func (s *VitessShardStatus) TabletAliases() []string {
 	tabletKeys := make([]string, 0, len(s.Tablets))
@@ -235,3 +672,62 @@ func (s *VitessShardStatus) TabletAliases() []string {
 
 	return tabletKeys
 }
+
+// PoolResourceStatus returns the resource status for the given tablet pool,
+// or nil if there is none.
+func (s *VitessShardStatus) PoolResourceStatus(pool *VitessShardTabletPool) *VitessTabletPoolResourceStatus {
+	for i := range s.TabletPoolResources {
+		status := &s.TabletPoolResources[i]
+		if status.Cell == pool.Cell && status.Type == pool.Type && status.Name == pool.Name {
+			return status
+		}
+	}
+	return nil
+}
+
+// Container returns the resource status for the given container, or nil if
+// there is none.
+func (s *VitessTabletPoolResourceStatus) Container(name string) *VitessContainerResourceStatus {
+	for i := range s.Containers {
+		if s.Containers[i].Name == name {
+			return &s.Containers[i]
+		}
+	}
+	return nil
+}
+
+// PoolScaleProfile returns the scale profile in effect for the given tablet
+// pool, or nil if there is none.
+func (s *VitessShardStatus) PoolScaleProfile(pool *VitessShardTabletPool) *VitessTabletPoolScaleProfileStatus {
+	for i := range s.TabletPoolScaleProfiles {
+		status := &s.TabletPoolScaleProfiles[i]
+		if status.Cell == pool.Cell && status.Type == pool.Type && status.Name == pool.Name {
+			return status
+		}
+	}
+	return nil
+}
+
+// PoolReplicas returns the number of tablets to run in the given pool, which
+// is set by the scale profile in effect, if there is one.
+func (s *VitessShardStatus) PoolReplicas(pool *VitessShardTabletPool) int32 {
+	if profile := s.PoolScaleProfile(pool); profile != nil {
+		return profile.Replicas
+	}
+	return pool.Replicas
+}
+
+// PoolMaxUnavailable returns how many tablets in the given pool may be
+// unavailable at the same time according to the pool's update strategy, and
+// whether the pool sets that at all. Values that round down to less than 1
+// are treated as 1.
+func (s *VitessShardStatus) PoolMaxUnavailable(pool *VitessShardTabletPool) (int, bool) {
+	if pool.UpdateStrategy == nil || pool.UpdateStrategy.MaxUnavailable == nil {
+		return 1, false
+	}
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pool.UpdateStrategy.MaxUnavailable, int(s.PoolReplicas(pool)), false)
+	if err != nil || maxUnavailable < 1 {
+		return 1, true
+	}
+	return maxUnavailable, true
+}
@@ -0,0 +1,473 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestVitessShardSpecSemiSyncProblems(t *testing.T) {
+	pool := func(cell string, poolType VitessTabletPoolType, replicas int32) VitessShardTabletPool {
+		return VitessShardTabletPool{Cell: cell, Type: poolType, Replicas: replicas}
+	}
+
+	table := []struct {
+		name             string
+		durabilityPolicy string
+		pools            []VitessShardTabletPool
+		wantProblems     int
+	}{
+		{
+			name:             "semi_sync with enough replicas",
+			durabilityPolicy: SemiSyncDurabilityPolicy,
+			pools:            []VitessShardTabletPool{pool("cell1", ReplicaPoolType, 2)},
+			wantProblems:     0,
+		},
+		{
+			name:             "semi_sync with rdonly only",
+			durabilityPolicy: SemiSyncDurabilityPolicy,
+			pools:            []VitessShardTabletPool{pool("cell1", ReplicaPoolType, 1), pool("cell1", RdonlyPoolType, 3)},
+			wantProblems:     1,
+		},
+		{
+			name:             "cross_cell with replicas in every cell",
+			durabilityPolicy: CrossCellDurabilityPolicy,
+			pools:            []VitessShardTabletPool{pool("cell1", ReplicaPoolType, 1), pool("cell2", ReplicaPoolType, 1)},
+			wantProblems:     0,
+		},
+		{
+			name:             "cross_cell in a single cell",
+			durabilityPolicy: CrossCellDurabilityPolicy,
+			pools:            []VitessShardTabletPool{pool("cell1", ReplicaPoolType, 3), pool("cell2", RdonlyPoolType, 1)},
+			wantProblems:     1,
+		},
+		{
+			name:             "other policy",
+			durabilityPolicy: "none",
+			pools:            []VitessShardTabletPool{pool("cell1", ReplicaPoolType, 1)},
+			wantProblems:     0,
+		},
+	}
+
+	for _, test := range table {
+		spec := VitessShardSpec{}
+		spec.TabletPools = test.pools
+		if got := spec.SemiSyncProblems(test.durabilityPolicy); len(got) != test.wantProblems {
+			t.Errorf("%v: SemiSyncProblems(%q) = %q; want %d problems", test.name, test.durabilityPolicy, got, test.wantProblems)
+		}
+	}
+}
+
+func TestVitessShardSpecMasterEligibleTabletCount(t *testing.T) {
+	spec := &VitessShardSpec{
+		VitessShardTemplate: VitessShardTemplate{
+			TabletPools: []VitessShardTabletPool{
+				{Cell: "cell1", Type: ReplicaPoolType, Replicas: 3},
+				{Cell: "cell1", Type: RdonlyPoolType, Replicas: 2},
+				{Cell: "cell2", Type: ReplicaPoolType, Replicas: 1, DelayedReplication: &metav1.Duration{Duration: time.Hour}},
+			},
+		},
+	}
+	if got, want := spec.MasterEligibleTabletCount(), int32(3); got != want {
+		t.Errorf("MasterEligibleTabletCount() = %v, want %v", got, want)
+	}
+}
+
+func TestVitessShardStatusRecordReparent(t *testing.T) {
+	status := &VitessShardStatus{}
+	for i := 0; i < maxReparentHistory+2; i++ {
+		status.RecordReparent(VitessShardReparentRecord{
+			NewPrimary: fmt.Sprintf("zone1-%d", i),
+			Reason:     DrainReparentReason,
+			Succeeded:  true,
+		})
+	}
+
+	if got, want := len(status.ReparentHistory), maxReparentHistory; got != want {
+		t.Fatalf("len(ReparentHistory) = %v, want %v", got, want)
+	}
+	if got, want := status.ReparentHistory[0].NewPrimary, fmt.Sprintf("zone1-%d", maxReparentHistory+1); got != want {
+		t.Errorf("newest record NewPrimary = %v, want %v", got, want)
+	}
+	if got, want := status.ReparentHistory[maxReparentHistory-1].NewPrimary, "zone1-2"; got != want {
+		t.Errorf("oldest record NewPrimary = %v, want %v", got, want)
+	}
+}
+
+func TestVitessShardPrimaryAffinityMatches(t *testing.T) {
+	table := []struct {
+		name     string
+		affinity VitessShardPrimaryAffinity
+		alias    string
+		cell     string
+		want     bool
+	}{
+		{name: "tablet match", affinity: VitessShardPrimaryAffinity{PreferredPrimaryTablet: "zone1-101"}, alias: "zone1-101", cell: "zone1", want: true},
+		{name: "tablet mismatch", affinity: VitessShardPrimaryAffinity{PreferredPrimaryTablet: "zone1-101"}, alias: "zone1-102", cell: "zone1", want: false},
+		{name: "tablet wins over cell", affinity: VitessShardPrimaryAffinity{PreferredPrimaryTablet: "zone1-101", Cell: "zone2"}, alias: "zone2-201", cell: "zone2", want: false},
+		{name: "cell match", affinity: VitessShardPrimaryAffinity{Cell: "zone2"}, alias: "zone2-201", cell: "zone2", want: true},
+		{name: "empty", affinity: VitessShardPrimaryAffinity{}, alias: "zone2-201", cell: "zone2", want: false},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.affinity.Matches(test.alias, test.cell); got != test.want {
+				t.Errorf("Matches(%q, %q) = %v, want %v", test.alias, test.cell, got, test.want)
+			}
+		})
+	}
+}
+
+func TestVitessShardSpecGuaranteedReplicaZones(t *testing.T) {
+	spread := func(minZones int32) *VitessZoneSpread {
+		spread := &VitessZoneSpread{}
+		DefaultVitessZoneSpread(spread)
+		spread.MinZones = &minZones
+		return spread
+	}
+
+	table := []struct {
+		name  string
+		pools []VitessShardTabletPool
+		want  int32
+	}{
+		{
+			name: "cells in different zones",
+			pools: []VitessShardTabletPool{
+				{Cell: "cell1", Type: ReplicaPoolType, Replicas: 1},
+				{Cell: "cell2", Type: ReplicaPoolType, Replicas: 1},
+			},
+			want: 2,
+		},
+		{
+			name: "rdonly pools don't count",
+			pools: []VitessShardTabletPool{
+				{Cell: "cell1", Type: ReplicaPoolType, Replicas: 1},
+				{Cell: "cell2", Type: RdonlyPoolType, Replicas: 1},
+			},
+			want: 1,
+		},
+		{
+			name: "cell without a zone and no minimum",
+			pools: []VitessShardTabletPool{
+				{Cell: "regional", Type: ReplicaPoolType, Replicas: 3, ZoneSpread: &VitessZoneSpread{TopologyKey: corev1.LabelTopologyZone}},
+			},
+			want: 1,
+		},
+		{
+			name: "cell without a zone spread over a minimum",
+			pools: []VitessShardTabletPool{
+				{Cell: "regional", Type: ReplicaPoolType, Replicas: 3, ZoneSpread: spread(3)},
+			},
+			want: 3,
+		},
+		{
+			name: "minimum capped by replicas",
+			pools: []VitessShardTabletPool{
+				{Cell: "regional", Type: ReplicaPoolType, Replicas: 2, ZoneSpread: spread(3)},
+			},
+			want: 2,
+		},
+	}
+
+	for _, test := range table {
+		spec := VitessShardSpec{ZoneMap: map[string]string{"cell1": "zone1", "cell2": "zone2"}}
+		spec.TabletPools = test.pools
+		if got := spec.GuaranteedReplicaZones(); got != test.want {
+			t.Errorf("%v: GuaranteedReplicaZones() = %v; want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestExternalDatastorePresets(t *testing.T) {
+	caSecret := &SecretSource{Name: "ca", Key: "ca.pem"}
+
+	table := []struct {
+		name          string
+		datastore     ExternalDatastore
+		wantSSLMode   string
+		wantWatchDNS  bool
+		wantSystemCAs bool
+	}{
+		{
+			name:      "no provider",
+			datastore: ExternalDatastore{},
+		},
+		{
+			name:         "aurora without a CA",
+			datastore:    ExternalDatastore{Provider: AuroraExternalDatastoreProvider},
+			wantSSLMode:  "required",
+			wantWatchDNS: true,
+		},
+		{
+			name:         "aurora with a CA",
+			datastore:    ExternalDatastore{Provider: AuroraExternalDatastoreProvider, ServerCACertSecret: caSecret},
+			wantSSLMode:  "verify_identity",
+			wantWatchDNS: true,
+		},
+		{
+			name:        "cloudsql with a CA",
+			datastore:   ExternalDatastore{Provider: CloudSQLExternalDatastoreProvider, ServerCACertSecret: caSecret},
+			wantSSLMode: "verify_ca",
+		},
+		{
+			name:          "azure without a CA",
+			datastore:     ExternalDatastore{Provider: AzureMySQLExternalDatastoreProvider},
+			wantSSLMode:   "verify_identity",
+			wantWatchDNS:  true,
+			wantSystemCAs: true,
+		},
+		{
+			name:         "explicit settings win",
+			datastore:    ExternalDatastore{Provider: AuroraExternalDatastoreProvider, SSLMode: "disabled", Failover: &ExternalFailoverSpec{}},
+			wantSSLMode:  "disabled",
+			wantWatchDNS: false,
+		},
+	}
+
+	for _, test := range table {
+		datastore := test.datastore
+		defaultExternalDatastore(&datastore)
+		if datastore.SSLMode != test.wantSSLMode {
+			t.Errorf("%v: SSLMode = %q; want %q", test.name, datastore.SSLMode, test.wantSSLMode)
+		}
+		if got := datastore.Failover != nil && datastore.Failover.WatchDNS; got != test.wantWatchDNS {
+			t.Errorf("%v: WatchDNS = %v; want %v", test.name, got, test.wantWatchDNS)
+		}
+		if got := datastore.UsesSystemCAs(); got != test.wantSystemCAs {
+			t.Errorf("%v: UsesSystemCAs() = %v; want %v", test.name, got, test.wantSystemCAs)
+		}
+		if !*datastore.DemotePrimaryToSpare {
+			t.Errorf("%v: DemotePrimaryToSpare = false; want true", test.name)
+		}
+	}
+}
+
+func TestDefaultVttabletQueryServing(t *testing.T) {
+	pool := func(cpu string) *VitessShardTabletPool {
+		pool := &VitessShardTabletPool{
+			Vttablet: VttabletSpec{QueryServing: &VttabletQueryServingSpec{}},
+			Mysqld:   &MysqldSpec{},
+		}
+		if cpu != "" {
+			pool.Mysqld.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		}
+		DefaultVitessShardTabletPool(pool)
+		return pool
+	}
+
+	table := []struct {
+		cpu                string
+		wantPoolSize       int32
+		wantTransactionCap int32
+	}{
+		{cpu: "", wantPoolSize: 96, wantTransactionCap: 300},
+		{cpu: "500m", wantPoolSize: 16, wantTransactionCap: 50},
+		{cpu: "2", wantPoolSize: 32, wantTransactionCap: 100},
+		{cpu: "16", wantPoolSize: 96, wantTransactionCap: 300},
+	}
+	for _, test := range table {
+		queryServing := pool(test.cpu).Vttablet.QueryServing
+		if got := *queryServing.PoolSize; got != test.wantPoolSize {
+			t.Errorf("cpu %q: PoolSize = %v; want %v", test.cpu, got, test.wantPoolSize)
+		}
+		if got := *queryServing.StreamPoolSize; got != test.wantPoolSize {
+			t.Errorf("cpu %q: StreamPoolSize = %v; want %v", test.cpu, got, test.wantPoolSize)
+		}
+		if got := *queryServing.TransactionCap; got != test.wantTransactionCap {
+			t.Errorf("cpu %q: TransactionCap = %v; want %v", test.cpu, got, test.wantTransactionCap)
+		}
+	}
+
+	// Explicit settings are kept, and checked against each other.
+	queryServing := &VttabletQueryServingSpec{
+		PoolSize:         pointer.Int32Ptr(10),
+		HotRowProtection: &VttabletHotRowProtectionSpec{MaxQueueSize: pointer.Int32Ptr(2000)},
+	}
+	defaultVttabletQueryServing(queryServing, 8)
+	if got := *queryServing.PoolSize; got != 10 {
+		t.Errorf("PoolSize = %v; want 10", got)
+	}
+	if got := queryServing.HotRowProtection.Mode; got != "enable" {
+		t.Errorf("HotRowProtection.Mode = %q; want enable", got)
+	}
+	if got := queryServing.Problems(); len(got) != 1 {
+		t.Errorf("Problems() = %q; want 1 problem", got)
+	}
+}
+
+func TestMysqldQueryLogsProblems(t *testing.T) {
+	table := []struct {
+		name         string
+		queryLogs    MysqldQueryLogs
+		wantProblems int
+	}{
+		{
+			name:         "slow query log to a file",
+			queryLogs:    MysqldQueryLogs{SlowQueryLog: &MysqldSlowQueryLog{}, Sink: FileMysqldQueryLogSink},
+			wantProblems: 0,
+		},
+		{
+			name:         "general log to Fluent Bit",
+			queryLogs:    MysqldQueryLogs{GeneralLog: true, Sink: FluentBitMysqldQueryLogSink, FluentBit: &MysqldQueryLogFluentBit{Host: "fluent-bit", Port: 5170}},
+			wantProblems: 0,
+		},
+		{
+			name:         "no logs",
+			queryLogs:    MysqldQueryLogs{Sink: StdoutMysqldQueryLogSink},
+			wantProblems: 1,
+		},
+		{
+			name:         "Fluent Bit without an address",
+			queryLogs:    MysqldQueryLogs{GeneralLog: true, Sink: FluentBitMysqldQueryLogSink},
+			wantProblems: 1,
+		},
+	}
+
+	for _, test := range table {
+		if got := test.queryLogs.Problems(); len(got) != test.wantProblems {
+			t.Errorf("%v: Problems() = %q; want %d problems", test.name, got, test.wantProblems)
+		}
+	}
+}
+
+func TestVitessShardSpecMysqlUsers(t *testing.T) {
+	secretRef := corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "users"}, Key: "password"}
+	user := func(name string, grants ...MysqlGrant) MysqlUser {
+		return MysqlUser{Username: name, SecretRef: secretRef, Grants: grants}
+	}
+	selectAll := MysqlGrant{Privileges: []string{"SELECT"}}
+
+	spec := &VitessShardSpec{}
+	spec.TabletPools = []VitessShardTabletPool{
+		{
+			Cell:       "cell1",
+			Type:       ReplicaPoolType,
+			Mysqld:     &MysqldSpec{},
+			MysqlUsers: []MysqlUser{user("app", selectAll), user("vt_app"), user("reports", selectAll)},
+		},
+		{
+			Cell:       "cell1",
+			Type:       RdonlyPoolType,
+			Mysqld:     &MysqldSpec{},
+			MysqlUsers: []MysqlUser{user("app", selectAll), user("reports"), user("etl", MysqlGrant{Privileges: []string{"SELECT; DROP"}})},
+		},
+		{
+			// Pools with an external datastore are ignored.
+			Cell:              "cell2",
+			Type:              ExternalReplicaPoolType,
+			ExternalDatastore: &ExternalDatastore{},
+			MysqlUsers:        []MysqlUser{user("vt_external")},
+		},
+	}
+	for i := range spec.TabletPools {
+		DefaultVitessShardTabletPool(&spec.TabletPools[i])
+	}
+
+	users, problems := spec.MysqlUsers()
+	if len(users) != 1 || users[0].Username != "app" || !reflect.DeepEqual(users[0].Hosts, []string{"%"}) || users[0].Grants[0].Database != "*" {
+		t.Errorf("MysqlUsers() = %v; want only app, defaulted", users)
+	}
+	if len(problems) != 3 {
+		t.Errorf("MysqlUsers() problems = %q; want problems for etl, reports, and vt_app", problems)
+	}
+}
+
+func TestMysqldAuditLogProblems(t *testing.T) {
+	table := []struct {
+		name         string
+		auditLog     MysqldAuditLog
+		wantProblems int
+	}{
+		{
+			name:         "Percona with every filter",
+			auditLog:     MysqldAuditLog{Plugin: PerconaMysqldAuditLogPlugin, Format: "CSV", Filter: &MysqldAuditLogFilter{IncludeAccounts: []string{"app@%"}, ExcludeCommands: []string{"set_option"}, IncludeDatabases: []string{"commerce"}}},
+			wantProblems: 0,
+		},
+		{
+			name:         "MySQL Enterprise with Percona settings",
+			auditLog:     MysqldAuditLog{Plugin: MysqlEnterpriseMysqldAuditLogPlugin, Format: "CSV", Filter: &MysqldAuditLogFilter{IncludeCommands: []string{"drop_db"}}},
+			wantProblems: 2,
+		},
+		{
+			name:         "include and exclude",
+			auditLog:     MysqldAuditLog{Plugin: PerconaMysqldAuditLogPlugin, Filter: &MysqldAuditLogFilter{IncludeAccounts: []string{"app@%"}, ExcludeAccounts: []string{"dba@%"}}},
+			wantProblems: 1,
+		},
+	}
+
+	for _, test := range table {
+		if got := test.auditLog.Problems(); len(got) != test.wantProblems {
+			t.Errorf("%v: Problems() = %q; want %d problems", test.name, got, test.wantProblems)
+		}
+	}
+}
+
+func TestVitessShardSpecStorageTypeProblems(t *testing.T) {
+	table := []struct {
+		name         string
+		pool         VitessShardTabletPool
+		wantProblems int
+	}{
+		{
+			name:         "persistent replica pool",
+			pool:         VitessShardTabletPool{Type: ReplicaPoolType, StorageType: PersistentStorageType, Mysqld: &MysqldSpec{}},
+			wantProblems: 0,
+		},
+		{
+			name:         "ephemeral rdonly pool",
+			pool:         VitessShardTabletPool{Type: RdonlyPoolType, StorageType: EphemeralStorageType, Mysqld: &MysqldSpec{}},
+			wantProblems: 0,
+		},
+		{
+			name:         "ephemeral replica pool",
+			pool:         VitessShardTabletPool{Type: ReplicaPoolType, StorageType: EphemeralStorageType, Mysqld: &MysqldSpec{}},
+			wantProblems: 1,
+		},
+		{
+			name:         "ephemeral rdonly pool without a backup location",
+			pool:         VitessShardTabletPool{Type: RdonlyPoolType, StorageType: EphemeralStorageType, Mysqld: &MysqldSpec{}, BackupLocationName: "missing"},
+			wantProblems: 1,
+		},
+	}
+
+	for _, test := range table {
+		spec := &VitessShardSpec{
+			VitessShardTemplate: VitessShardTemplate{
+				TabletPools: []VitessShardTabletPool{test.pool},
+			},
+			BackupLocations: []VitessBackupLocation{{}},
+		}
+		if got := spec.StorageTypeProblems(); len(got) != test.wantProblems {
+			t.Errorf("%v: StorageTypeProblems() = %q; want %d problems", test.name, got, test.wantProblems)
+		}
+		// Only the pool type decides whether ephemeral storage is used, not
+		// whether there's a backup location.
+		wantEphemeral := test.pool.StorageType == EphemeralStorageType && test.pool.Type == RdonlyPoolType
+		if got := spec.TabletPools[0].EphemeralStorage(); got != wantEphemeral {
+			t.Errorf("%v: EphemeralStorage() = %v; want %v", test.name, got, wantEphemeral)
+		}
+	}
+}
@@ -18,7 +18,9 @@ package v2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -90,14 +92,49 @@ type VitessShardSpec struct {
 	// BackupEngine specifies the Vitess backup engine to use, either "builtin" or "xtrabackup".
 	BackupEngine VitessBackupEngine `json:"backupEngine,omitempty"`
 
+	// Vtbackup configures vtbackup Pods, as defined in the VitessCluster.
+	Vtbackup *VitessBackupPodSpec `json:"vtbackup,omitempty"`
+
+	// BackupVerification configures backup verification, as defined in the VitessCluster.
+	BackupVerification *VitessBackupVerificationSpec `json:"backupVerification,omitempty"`
+
+	// BackupSourceTabletPolicy configures which tablet pool backups are taken
+	// on behalf of, as defined in the VitessCluster.
+	BackupSourceTabletPolicy *VitessBackupSourceTabletPolicy `json:"backupSourceTabletPolicy,omitempty"`
+
+	// BackupReplication configures copying of completed backups to other
+	// locations, as defined in the VitessCluster.
+	BackupReplication *VitessBackupReplicationSpec `json:"backupReplication,omitempty"`
+
 	// ExtraVitessFlags is inherited from the parent's VitessClusterSpec.
 	ExtraVitessFlags map[string]string `json:"extraVitessFlags,omitempty"`
 
 	// TopologyReconciliation is inherited from the parent's VitessClusterSpec.
 	TopologyReconciliation *TopoReconcileConfig `json:"topologyReconciliation,omitempty"`
 
-	// UpdateStrategy is inherited from the parent's VitessClusterSpec.
+	// UpdateStrategy is inherited from the parent's VitessKeyspaceSpec.
 	UpdateStrategy *VitessClusterUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// MaintenanceWindows is inherited from the parent's VitessKeyspaceSpec.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// PreferredPrimaryCells is inherited from the parent's VitessKeyspaceSpec.
+	PreferredPrimaryCells []string `json:"preferredPrimaryCells,omitempty"`
+
+	// ReparentSettings is inherited from the parent's VitessKeyspaceSpec.
+	ReparentSettings *ReparentSettings `json:"reparentSettings,omitempty"`
+
+	// ReparentConcurrency is inherited from the parent's VitessKeyspace.
+	ReparentConcurrency *int32 `json:"reparentConcurrency,omitempty"`
+
+	// BackupSchedule is inherited from the parent's VitessKeyspace.
+	BackupSchedule *VitessBackupScheduleSpec `json:"backupSchedule,omitempty"`
+
+	// InitialRestore is inherited from the parent's VitessKeyspaceSpec.
+	InitialRestore *VitessInitialRestoreSpec `json:"initialRestore,omitempty"`
+
+	// Standby is inherited from the parent's VitessKeyspaceSpec.
+	Standby *VitessStandbySpec `json:"standby,omitempty"`
 }
 
 // VitessShardTemplate contains only the user-specified parts of a VitessShard object.
@@ -124,10 +161,85 @@ type VitessShardTemplate struct {
 	// Replication configures Vitess replication settings for the shard.
 	Replication VitessReplicationSpec `json:"replication,omitempty"`
 
+	// DrainHooks can optionally be used to run actions against a tablet at
+	// certain points while it's being drained.
+	DrainHooks *VitessDrainHooks `json:"drainHooks,omitempty"`
+
+	// PrimaryAffinity can optionally be used to designate where the shard's
+	// primary should normally run. When a drain or failure moves the primary
+	// elsewhere, the operator performs a planned reparent back to a matching
+	// tablet once one has been healthy and caught up on replication for a
+	// while.
+	//
+	// If this is set, the primaryRotationSchedule reparent setting has no
+	// effect on the shard, since the two would fight over the primary.
+	PrimaryAffinity *VitessShardPrimaryAffinity `json:"primaryAffinity,omitempty"`
+
+	// MinReplicaZones can optionally be used to require that the shard's
+	// replica-type tablets are guaranteed to land in at least this many
+	// distinct zones. A pool counts toward the zone of its cell, if the cell
+	// has one, or else toward as many zones as its zoneSpread guarantees.
+	// If the tablet pools can't guarantee it, the ZoneSpreadSatisfiable
+	// condition of the shard is False.
+	//
+	// Default: No requirement.
+	// +kubebuilder:validation:Minimum=1
+	MinReplicaZones *int32 `json:"minReplicaZones,omitempty"`
+
+	// MysqldConfigDriftPolicy enables periodic checks that the global
+	// variables of each running MySQL still match the structured mysqld
+	// config and auto-tuned values its tablet was started with. Variables
+	// usually drift when someone runs SET GLOBAL by hand, and the change is
+	// lost the next time the tablet restarts.
+	//
+	// Drift is reported in the mysqldConfigDrift field of each tablet's
+	// status and in the shard's MysqldConfigDriftDetected condition.
+	// Settings from configOverrides aren't checked.
+	//
+	// With "alertOnly", drift is only reported. With "reapply", the operator
+	// also sets drifted variables back with SET GLOBAL, as long as they can
+	// be changed without a restart.
+	//
+	// Default: MySQL config drift is not checked.
+	// +kubebuilder:validation:Enum=alertOnly;reapply
+	MysqldConfigDriftPolicy VitessMysqldConfigDriftPolicy `json:"mysqldConfigDriftPolicy,omitempty"`
+
 	// Annotations can optionally be used to attach custom annotations to the VitessShard object.
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// VitessShardPrimaryAffinity designates which tablets the operator should
+// keep as the shard's primary. At least one of the fields should be set.
+type VitessShardPrimaryAffinity struct {
+	// PreferredPrimaryTablet is the alias ("cell-uid") of a specific tablet
+	// to keep as the primary. Tablet aliases are listed in the VitessShard
+	// status.
+	PreferredPrimaryTablet string `json:"preferredPrimaryTablet,omitempty"`
+
+	// Cell is the name of a cell in which to keep the primary. If
+	// preferredPrimaryTablet is also set, this is ignored.
+	Cell string `json:"cell,omitempty"`
+
+	// StableFor is how long a matching tablet must have been Ready, and the
+	// current primary must have held its role, before the operator reparents
+	// back to the matching tablet. This keeps the primary from flapping while
+	// things are still settling after a drain or failure.
+	//
+	// Default: 5m
+	StableFor *metav1.Duration `json:"stableFor,omitempty"`
+}
+
+// VitessMysqldConfigDriftPolicy is what to do about MySQL global variables
+// that no longer match the config a tablet was started with.
+type VitessMysqldConfigDriftPolicy string
+
+const (
+	// AlertOnlyMysqldConfigDriftPolicy only reports drifted variables.
+	AlertOnlyMysqldConfigDriftPolicy VitessMysqldConfigDriftPolicy = "alertOnly"
+	// ReapplyMysqldConfigDriftPolicy sets dynamic drifted variables back to their configured values.
+	ReapplyMysqldConfigDriftPolicy VitessMysqldConfigDriftPolicy = "reapply"
+)
+
 // VitessReplicationSpec specifies how Vitess will set up MySQL replication.
 type VitessReplicationSpec struct {
 	// InitializeMaster specifies whether to choose an initial master for a
@@ -149,6 +261,191 @@ type VitessReplicationSpec struct {
 	//
 	// Default: true.
 	RecoverRestartedMaster *bool `json:"recoverRestartedMaster,omitempty"`
+
+	// ExternalReparents tells the operator that something outside of it,
+	// like Orchestrator or custom failover tooling, is responsible for
+	// choosing the shard's primary. The operator then never initializes the
+	// primary of a new, empty shard or performs planned, emergency, or scheduled
+	// reparents itself. It only follows primary changes that the external
+	// tooling reports to Vitess with TabletExternallyReparented. A shard that's
+	// restored from backup still gets replication started by the operator.
+	//
+	// Draining the primary waits until the external tooling has moved the
+	// primary to another tablet.
+	//
+	// Default: false.
+	ExternalReparents bool `json:"externalReparents,omitempty"`
+
+	// DrainMaxUnavailable is the maximum number of tablets in the shard that
+	// may be marked as finished draining (and therefore safe to delete) at the
+	// same time. This can be an absolute number or a percentage of the desired
+	// tablets in the shard, rounded down. Values that round down to less than 1
+	// are treated as 1.
+	//
+	// Raising this can speed up node pool rotations for shards with many
+	// replicas, at the cost of reduced redundancy while drains are in progress.
+	// The primary is never marked as finished until it has been reparented away,
+	// regardless of this setting.
+	//
+	// Within a single tablet pool, no more tablets are marked as finished than
+	// the pool's updateStrategy.maxUnavailable allows, counting tablets in the
+	// pool that are down for other reasons. Pools that don't set it are limited
+	// by this setting instead.
+	//
+	// Default: 1.
+	DrainMaxUnavailable *intstr.IntOrString `json:"drainMaxUnavailable,omitempty"`
+
+	// Mode is the replication mode of the shard.
+	//
+	// In "semiSync" mode, the operator sets the keyspace's durability policy
+	// in topology to SemiSyncDurabilityPolicy, unless the VitessKeyspace sets
+	// durabilityPolicy explicitly. It also checks that the shard has enough
+	// replica-type tablets to ack writes, and refuses planned reparents to a
+	// candidate primary whose semi-sync acks couldn't be satisfied.
+	//
+	// Default: async
+	// +kubebuilder:validation:Enum=async;semiSync
+	Mode VitessReplicationMode `json:"mode,omitempty"`
+
+	// SemiSyncDurabilityPolicy is the durability policy to use in "semiSync"
+	// mode. With "semi_sync", writes must be acked by any replica-type
+	// tablet. With "cross_cell", writes must be acked by a replica-type tablet
+	// in a different cell than the primary.
+	//
+	// Default: semi_sync
+	// +kubebuilder:validation:Enum=semi_sync;cross_cell
+	SemiSyncDurabilityPolicy string `json:"semiSyncDurabilityPolicy,omitempty"`
+
+	// Repair enables automatic checks of replication on replica-type tablets.
+	// Each time the operator reconciles the shard, it asks every Ready replica
+	// for its replication status and reports the result in the replicating
+	// field of that tablet's status. Replicas whose replication is stopped or
+	// failing are then repaired with the configured actions.
+	//
+	// Default: Replication is not checked or repaired.
+	Repair *VitessReplicationRepairSpec `json:"repair,omitempty"`
+
+	// ErrantGTIDPolicy enables periodic checks for errant transactions on
+	// replica-type tablets, meaning transactions in a replica's GTID set that
+	// the primary has never seen. These usually come from writes made
+	// directly on a replica, and can break replication or lose data if the
+	// replica is ever promoted.
+	//
+	// Errant GTIDs are reported in the errantGTIDs field of each tablet's
+	// status and in the shard's ErrantGTIDsDetected condition.
+	//
+	// With "alertOnly", errant GTIDs are only reported. With "reseed", the
+	// operator also deletes the data volume and Pod of an affected replica so
+	// it's restored from the latest backup, as long as the shard has a
+	// complete backup and no other tablet in the same pool is down. At most
+	// one tablet per shard is reseeded at a time.
+	//
+	// Default: Errant GTIDs are not checked.
+	// +kubebuilder:validation:Enum=alertOnly;reseed
+	ErrantGTIDPolicy VitessErrantGTIDPolicy `json:"errantGTIDPolicy,omitempty"`
+}
+
+// VitessErrantGTIDPolicy is what to do about errant GTIDs on a replica.
+type VitessErrantGTIDPolicy string
+
+const (
+	// AlertOnlyErrantGTIDPolicy only reports errant GTIDs.
+	AlertOnlyErrantGTIDPolicy VitessErrantGTIDPolicy = "alertOnly"
+	// ReseedErrantGTIDPolicy recreates replicas with errant GTIDs from the latest backup.
+	ReseedErrantGTIDPolicy VitessErrantGTIDPolicy = "reseed"
+)
+
+// VitessReplicationRepairSpec configures automatic repair of broken
+// replication on replica-type tablets.
+type VitessReplicationRepairSpec struct {
+	// Actions is the sequence of repair actions to consider for a replica
+	// whose replication is broken. Each time the operator finds a broken
+	// replica, it performs the first listed action that applies to the
+	// failure:
+	//
+	// "StartReplication" restarts the replication threads. It applies unless
+	// the replica's GTID set has diverged from the primary (for example,
+	// because the primary has purged binary logs the replica still needs, or
+	// the replica has errant transactions).
+	//
+	// "ReseedFromBackup" deletes the replica's data volume and Pod, so the
+	// tablet is recreated and restored from the latest backup. It only
+	// applies if the replica's GTID set has diverged, the shard has at least
+	// one complete backup, and no other tablet in the same pool is down.
+	// At most one tablet per shard is reseeded at a time.
+	//
+	// If no action applies, or the list is empty, the broken replica is only
+	// reported in status.
+	//
+	// Default: No actions.
+	Actions []VitessReplicationRepairAction `json:"actions,omitempty"`
+}
+
+// VitessReplicationRepairAction is an action to repair broken replication.
+// +kubebuilder:validation:Enum=StartReplication;ReseedFromBackup
+type VitessReplicationRepairAction string
+
+const (
+	// StartReplicationRepairAction restarts replication on a replica.
+	StartReplicationRepairAction VitessReplicationRepairAction = "StartReplication"
+	// ReseedFromBackupRepairAction recreates a replica from the latest backup.
+	ReseedFromBackupRepairAction VitessReplicationRepairAction = "ReseedFromBackup"
+)
+
+// VitessReplicationMode is the replication mode of a shard.
+type VitessReplicationMode string
+
+const (
+	// AsyncReplicationMode is the VitessReplicationMode for asynchronous replication.
+	AsyncReplicationMode VitessReplicationMode = "async"
+	// SemiSyncReplicationMode is the VitessReplicationMode for semi-synchronous replication.
+	SemiSyncReplicationMode VitessReplicationMode = "semiSync"
+)
+
+const (
+	// NoneDurabilityPolicy is the Vitess durability policy that doesn't
+	// require semi-sync acks.
+	NoneDurabilityPolicy = "none"
+	// SemiSyncDurabilityPolicy is the Vitess durability policy that requires
+	// semi-sync acks from any replica-type tablet.
+	SemiSyncDurabilityPolicy = "semi_sync"
+	// CrossCellDurabilityPolicy is the Vitess durability policy that requires
+	// semi-sync acks from a replica-type tablet in a different cell.
+	CrossCellDurabilityPolicy = "cross_cell"
+)
+
+// VitessDrainHooks specifies actions to run against a tablet while it's
+// being drained.
+type VitessDrainHooks struct {
+	// PreFinish is a list of actions to run, in order, against a tablet right
+	// before the operator marks it as finished draining (i.e. safe to delete).
+	//
+	// If any action fails, the tablet is not marked as finished, and all the
+	// actions will be retried from the beginning on a later pass. Actions
+	// should therefore be safe to run more than once.
+	PreFinish []VitessDrainHook `json:"preFinish,omitempty"`
+}
+
+// VitessDrainHook is a single action to run against a tablet.
+// Exactly one of SQL or Hook must be specified.
+type VitessDrainHook struct {
+	// SQL is a statement to execute against the tablet's MySQL as the DBA user,
+	// such as "FLUSH BINARY LOGS".
+	SQL string `json:"sql,omitempty"`
+
+	// Hook is a vttablet hook to execute on the tablet. The hook must be an
+	// executable in the vthook directory of the vttablet container image.
+	Hook *VitessTabletHook `json:"hook,omitempty"`
+}
+
+// VitessTabletHook specifies a vttablet hook to execute.
+type VitessTabletHook struct {
+	// Name is the name of the hook executable.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Parameters are passed to the hook as command-line arguments.
+	Parameters []string `json:"parameters,omitempty"`
 }
 
 // VitessShardTabletPool defines a pool of tablets with a similar purpose.
@@ -193,14 +490,110 @@ type VitessShardTabletPool struct {
 	// IMPORTANT: For a tablet pool in a Kubernetes cluster that spans multiple
 	// zones, you should ensure that `volumeBindingMode: WaitForFirstConsumer`
 	// is set on the StorageClass specified in the storageClassName field here.
+	//
+	// The storage request can be increased later, and each volume is expanded
+	// in place. If the operator runs with --check_volume_expansion and the
+	// StorageClass doesn't allow volume expansion, each tablet is restored
+	// from backup onto a new, bigger volume instead. Changing storageClassName
+	// moves each tablet onto a new volume of that class the same way, with an
+	// extra tablet in the pool until they've all moved. Replacing volumes
+	// needs a backup location, and happens one tablet at a time.
 	DataVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"dataVolumeClaimTemplate,omitempty"`
 
+	// StorageType is whether the tablets in this pool keep their data when
+	// their Pods are deleted.
+	//
+	// With "persistent", each tablet's data lives on a PersistentVolumeClaim
+	// created from dataVolumeClaimTemplate, which outlives the tablet's Pod.
+	//
+	// With "ephemeral", each tablet's data lives on a volume that's created
+	// and deleted along with its Pod: a generic ephemeral volume created from
+	// dataVolumeClaimTemplate if it's set (for example, on a StorageClass for
+	// local NVMe drives), or otherwise an emptyDir on the Node. The data is
+	// lost whenever the Pod is deleted or rescheduled, and the new Pod
+	// restores from the latest backup before it starts replicating. Tablets
+	// whose MySQL can't recover on their own are reseeded automatically, as
+	// if autoReseed were set. This can be a cheaper fit for "rdonly" pools
+	// that serve analytics queries.
+	//
+	// Ephemeral storage is only allowed for "rdonly" pools with a local
+	// mysqld, since those tablets never become primary. Other pools use
+	// persistent storage. Ephemeral pools also need a backup location to
+	// restore from; without one, the pool stays ephemeral, but rescheduled
+	// tablets start out empty. The shard's StorageTypeValid condition reports
+	// either problem.
+	//
+	// Default: persistent
+	// +kubebuilder:validation:Enum=persistent;ephemeral
+	StorageType VitessTabletPoolStorageType `json:"storageType,omitempty"`
+
 	// BackupLocationName is the name of the backup location to use for this
 	// tablet pool. It must match the name of one of the backup locations
 	// defined in the VitessCluster.
 	// Default: Use the backup location whose name is empty.
 	BackupLocationName string `json:"backupLocationName,omitempty"`
 
+	// DrainOrder controls which tablets finish draining first when tablets
+	// from several pools in the shard are being drained at the same time.
+	// Tablets in pools with lower values finish draining first. Regardless of
+	// this setting, the current primary always finishes draining last.
+	//
+	// Default: 0 for "rdonly" and "externalrdonly" pools, and 10 for all others,
+	// so rdonly tablets are drained before replicas.
+	DrainOrder *int32 `json:"drainOrder,omitempty"`
+
+	// PrimaryEligibilityWeight steers planned reparents toward tablets in
+	// this pool, for example if it runs on faster hardware. When the operator
+	// chooses a new primary, it only considers eligible tablets in the pools
+	// with the highest weight, and then picks the one whose replication
+	// position is farthest ahead. Tablets that don't belong to any pool have
+	// a weight of 0.
+	//
+	// This only matters for pools whose tablets can become primary, and only
+	// applies among tablets in the shard's preferredPrimaryCells, if any.
+	//
+	// Default: 0
+	PrimaryEligibilityWeight int32 `json:"primaryEligibilityWeight,omitempty"`
+
+	// DelayedReplication makes tablets in this pool apply changes from the
+	// primary only after this much time has passed (MySQL's MASTER_DELAY),
+	// to provide a time-delayed copy of the data for recovering from human
+	// error, like an accidentally dropped table.
+	//
+	// Tablets in a delayed pool are never chosen as a new primary, and their
+	// lag doesn't block reparents. Since the delay shows up as replication
+	// lag, it's also added to the threshold after which vttablet reports
+	// itself unhealthy. To keep queries from being routed to delayed tablets,
+	// use an "rdonly" pool that isn't used to serve traffic.
+	//
+	// Default: Replication is not delayed.
+	DelayedReplication *metav1.Duration `json:"delayedReplication,omitempty"`
+
+	// AutoReseed makes the operator recreate a replica-type tablet in this
+	// pool from the latest backup when its MySQL can't recover on its own:
+	// when mysqld keeps crashing (for example, because its data dir is
+	// corrupt), when the tablet has errant GTIDs, or when its replication has
+	// diverged from the primary. The operator deletes the tablet's data volume
+	// and Pod, and the recreated tablet restores from backup.
+	//
+	// Errant GTIDs are only detected if the shard's errantGTIDPolicy is set,
+	// and diverged replication only if replication repair is configured.
+	//
+	// To avoid making an outage worse, a tablet is only reseeded if the shard
+	// has a primary and a complete backup, the tablet isn't the primary, and
+	// every other tablet in the shard is Ready. At most one tablet per shard
+	// is reseeded at a time. This only applies to pools with a local mysqld.
+	//
+	// Default: Tablets are never reseeded automatically.
+	AutoReseed bool `json:"autoReseed,omitempty"`
+
+	// UpdateStrategy controls the pace at which pending changes, like a new
+	// image or spec change, roll out to the tablets in this pool once the
+	// cluster's update strategy releases them to the shard.
+	//
+	// Default: Tablets in the shard are updated one at a time.
+	UpdateStrategy *VitessTabletPoolUpdateStrategy `json:"updateStrategy,omitempty"`
+
 	// Vttablet configures the vttablet server within each tablet.
 	Vttablet VttabletSpec `json:"vttablet"`
 
@@ -208,9 +601,40 @@ type VitessShardTabletPool struct {
 	// You must specify either Mysqld or ExternalDatastore, but not both.
 	Mysqld *MysqldSpec `json:"mysqld,omitempty"`
 
+	// AutoTuneMysql can optionally be set to size the InnoDB buffer pool, the
+	// redo log capacity, InnoDB I/O threads, and the connection limit of the
+	// local MySQL from the resource requests of the mysqld container, or its
+	// limits if there are no requests. The connection limit always leaves
+	// room for the connection pools of vttablet.
+	//
+	// Settings in mysqld.config take precedence over tuned values. Since
+	// tuned values are part of the tablet Pod spec, changing the resources
+	// of mysqld rolls out new values by restarting tablets one at a time.
+	//
+	// This has no effect on pools with an external datastore.
+	//
+	// Default: false
+	AutoTuneMysql bool `json:"autoTuneMysql,omitempty"`
+
 	// MysqldExporter configures a MySQL exporter running inside each tablet Pod.
 	MysqldExporter *MysqldExporterSpec `json:"mysqldExporter,omitempty"`
 
+	// MysqlUsers are MySQL users that the operator creates and keeps up to
+	// date, such as users for applications, monitoring, or DBAs. This
+	// replaces creating users with init scripts.
+	//
+	// Users are created on the shard's primary and replicate from there, so a
+	// user listed in any pool exists on every tablet of the shard, and must
+	// be listed the same way in each pool that lists it. Tablets that are
+	// restored or reseeded catch up through replication, and users are
+	// created again whenever a different tablet becomes primary, such as
+	// after the whole shard is restored from an older backup.
+	//
+	// Users that are removed from this list are dropped from MySQL.
+	//
+	// This has no effect on pools with an external datastore.
+	MysqlUsers []MysqlUser `json:"mysqlUsers,omitempty"`
+
 	// ExternalDatastore provides information for an externally managed MySQL.
 	// You must specify either Mysqld or ExternalDatastore, but not both.
 	ExternalDatastore *ExternalDatastore `json:"externalDatastore,omitempty"`
@@ -275,6 +699,109 @@ type VitessShardTabletPool struct {
 	// +kubebuilder:validation:Schemaless
 	// +kubebuilder:pruning:PreserveUnknownFields
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// ZoneSpread can optionally be used to spread the tablets in this pool
+	// evenly across zones, which is useful in cells that span several zones.
+	// It adds a topologySpreadConstraint to the ones above that covers only
+	// the tablets in this pool.
+	ZoneSpread *VitessZoneSpread `json:"zoneSpread,omitempty"`
+
+	// PodDisruptionBudget can optionally be used to customize the
+	// PodDisruptionBudget (PDB) that the operator manages for the tablet Pods in this pool.
+	//
+	// Default: A PDB that lets only one Pod be evicted at a time.
+	PodDisruptionBudget *VitessPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// VerticalAutoscaling can optionally be used to have the operator
+	// recommend CPU and memory requests for the vttablet and mysqld containers
+	// in this pool, based on how much they actually use, and optionally to
+	// apply the recommendations. Usage is read from the Kubernetes resource
+	// metrics API, so metrics-server or an equivalent must be installed.
+	//
+	// Off: No recommendations are made.
+	// Recommend: Recommendations are reported in the shard status.
+	// Auto: Recommendations are also applied, once enough usage has been
+	//   observed, during the shard's maintenance windows, if it has any. The
+	//   changed Pods are rolled out like any other change.
+	//
+	// Default: Off.
+	// +kubebuilder:validation:Enum=Off;Recommend;Auto
+	VerticalAutoscaling VitessVerticalAutoscalingMode `json:"verticalAutoscaling,omitempty"`
+
+	// ScaleProfiles can optionally be used to run a different number of
+	// tablets in this pool on a schedule, such as fewer tablets overnight.
+	// While a profile is in effect, its replicas are used in place of the
+	// Replicas field. If more than one profile is in effect at once, the
+	// first one listed wins.
+	//
+	// Tablets that are added or removed this way come and go like they do
+	// when Replicas is changed.
+	ScaleProfiles []VitessScaleProfile `json:"scaleProfiles,omitempty"`
+}
+
+// VitessVerticalAutoscalingMode selects whether the operator recommends and
+// applies compute resources for a tablet pool.
+type VitessVerticalAutoscalingMode string
+
+const (
+	// VerticalAutoscalingOff means no resource recommendations are made.
+	VerticalAutoscalingOff VitessVerticalAutoscalingMode = "Off"
+	// VerticalAutoscalingRecommend means resource recommendations are
+	// reported, but not applied.
+	VerticalAutoscalingRecommend VitessVerticalAutoscalingMode = "Recommend"
+	// VerticalAutoscalingAuto means resource recommendations are reported
+	// and applied.
+	VerticalAutoscalingAuto VitessVerticalAutoscalingMode = "Auto"
+)
+
+// VitessTabletPoolUpdateStrategy controls rolling updates of the tablets in a
+// tablet pool.
+//
+// Pools in a shard are still updated one at a time: a tablet is only released
+// for update while every tablet in the shard's other pools is Available and
+// not being updated. The primary is always updated last, on its own.
+type VitessTabletPoolUpdateStrategy struct {
+	// MaxUnavailable is the maximum number of tablets in the pool that may be
+	// unavailable or being updated at the same time. This can be an absolute
+	// number or a percentage of the pool's replicas, rounded down. Values
+	// that round down to less than 1 are treated as 1.
+	//
+	// Tablets are drained before they're updated, so the shard's
+	// replication.drainMaxUnavailable also limits how many can be updated at
+	// once. This also limits how many tablets in the pool may be marked as
+	// finished draining at the same time, for any reason.
+	//
+	// Default: 1.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Partition holds back pending changes from the tablets in the pool whose
+	// index is less than or equal to this number. Tablets are numbered from 1,
+	// so lowering the partition step by step lets you roll out a change to a
+	// few tablets at a time, and check on them before continuing.
+	//
+	// Default: 0, meaning all tablets are updated.
+	// +kubebuilder:validation:Minimum=0
+	Partition int32 `json:"partition,omitempty"`
+
+	// Paused holds back pending changes from all tablets in the pool. Tablets
+	// that are already being updated finish their update.
+	//
+	// Default: false.
+	Paused bool `json:"paused,omitempty"`
+
+	// Surge brings up one extra tablet in the pool while the pool has
+	// pending changes, and turns it down again once they've rolled out. The
+	// extra tablet is created with the pending changes, restores from the
+	// latest backup, and must catch up on replication and become Available
+	// before the rollout updates any other tablet in the pool. That keeps the
+	// pool serving at full capacity throughout the rollout, at the cost of an
+	// extra Pod and PVC.
+	//
+	// Surge requires a backup location, and is ignored for pools that use an
+	// external datastore.
+	//
+	// Default: false.
+	Surge bool `json:"surge,omitempty"`
 }
 
 // VttabletSpec configures the vttablet server within a tablet.
@@ -300,6 +827,110 @@ type VttabletSpec struct {
 	// TerminationGracePeriodSeconds can optionally be used to customize
 	// terminationGracePeriodSeconds of the vttablet pod.
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// QueryServing can optionally be used to tune how vttablet serves
+	// queries, such as its connection pool sizes and transaction limits,
+	// without setting the underlying flags in extraFlags. Any settings left
+	// out are derived from the CPU given to mysqld, or to vttablet if the
+	// pool uses an external datastore.
+	//
+	// Default: The operator's fixed defaults for every tablet, regardless
+	// of its resources.
+	QueryServing *VttabletQueryServingSpec `json:"queryServing,omitempty"`
+}
+
+// VttabletQueryServingSpec configures how vttablet serves queries.
+type VttabletQueryServingSpec struct {
+	// PoolSize is the number of MySQL connections that vttablet keeps for
+	// regular queries, which are neither streaming nor in a transaction.
+	//
+	// Default: 16 per CPU, between 16 and 96.
+	// +kubebuilder:validation:Minimum=1
+	PoolSize *int32 `json:"poolSize,omitempty"`
+
+	// StreamPoolSize is the number of MySQL connections that vttablet keeps
+	// for streaming queries.
+	//
+	// Default: The same as poolSize.
+	// +kubebuilder:validation:Minimum=1
+	StreamPoolSize *int32 `json:"streamPoolSize,omitempty"`
+
+	// TransactionCap is the maximum number of transactions that can be open
+	// at once, each of which holds a MySQL connection.
+	//
+	// Default: 50 per CPU, between 50 and 300.
+	// +kubebuilder:validation:Minimum=1
+	TransactionCap *int32 `json:"transactionCap,omitempty"`
+
+	// QueryTimeout is how long a query can run before vttablet kills it.
+	//
+	// Default: 15m.
+	QueryTimeout *metav1.Duration `json:"queryTimeout,omitempty"`
+
+	// TransactionTimeout is how long a transaction can stay open before
+	// vttablet kills it.
+	//
+	// Default: vttablet's default.
+	TransactionTimeout *metav1.Duration `json:"transactionTimeout,omitempty"`
+
+	// MaxResultSize is the maximum number of rows that a non-streaming query
+	// can return.
+	//
+	// Default: 100000.
+	// +kubebuilder:validation:Minimum=1
+	MaxResultSize *int32 `json:"maxResultSize,omitempty"`
+
+	// Consolidator sets when vttablet merges identical queries that are
+	// running at the same time, so they only run once in MySQL.
+	//
+	// Supported options:
+	//   * enable - Consolidate queries on all tablets.
+	//   * disable - Never consolidate queries.
+	//   * notOnPrimary - Consolidate queries only on replica tablets, where
+	//     consolidated reads might miss the latest writes anyway.
+	//
+	// Default: vttablet's default, which is enable.
+	// +kubebuilder:validation:Enum=enable;disable;notOnPrimary
+	Consolidator string `json:"consolidator,omitempty"`
+
+	// HotRowProtection can optionally be used to queue transactions that
+	// update the same row, so they can't take up the whole transaction pool.
+	HotRowProtection *VttabletHotRowProtectionSpec `json:"hotRowProtection,omitempty"`
+}
+
+// VttabletHotRowProtectionSpec configures hot row protection in vttablet.
+type VttabletHotRowProtectionSpec struct {
+	// Mode sets whether hot row protection is enforced.
+	//
+	// Supported options:
+	//   * enable - Queue transactions that update the same row.
+	//   * dryRun - Only log the transactions that would have been queued.
+	//   * disable - Don't queue transactions.
+	//
+	// Default: enable.
+	// +kubebuilder:validation:Enum=enable;dryRun;disable
+	Mode string `json:"mode,omitempty"`
+
+	// MaxQueueSize is the maximum number of transactions that can be queued
+	// for the same row.
+	//
+	// Default: vttablet's default, which is 20.
+	// +kubebuilder:validation:Minimum=1
+	MaxQueueSize *int32 `json:"maxQueueSize,omitempty"`
+
+	// MaxGlobalQueueSize is the maximum number of transactions that can be
+	// queued across all rows. It must be at least maxQueueSize.
+	//
+	// Default: vttablet's default, which is 1000.
+	// +kubebuilder:validation:Minimum=1
+	MaxGlobalQueueSize *int32 `json:"maxGlobalQueueSize,omitempty"`
+
+	// MaxConcurrency is the number of transactions for the same row that
+	// are let through to MySQL at once.
+	//
+	// Default: vttablet's default, which is 5.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
 }
 
 // MysqldSpec configures the local MySQL server within a tablet.
@@ -312,13 +943,341 @@ type MysqldSpec struct {
 	// ConfigOverrides can optionally be used to provide a my.cnf snippet
 	// to override default my.cnf values (included with Vitess) for this
 	// particular MySQL instance.
+	//
+	// These are applied after the settings in config, so they win if both
+	// set the same option.
 	ConfigOverrides string `json:"configOverrides,omitempty"`
+
+	// Config can optionally be used to set my.cnf options for this particular
+	// MySQL instance, as a map from option name to value.
+	//
+	// Values can be Go templates that derive the value from the resources of
+	// the mysqld container, so they follow resource changes. Templates can use:
+	//   * .Memory - The memory limit in bytes, or else the memory request.
+	//   * .CPUs - The CPU limit rounded up to whole CPUs, or else the CPU request.
+	//   * percent N X - N percent of X, rounded down.
+	//   * min X Y, max X Y - The smaller or larger of two numbers.
+	//
+	// For example: "{{ percent 70 .Memory }}".
+	//
+	// Options that Vitess or the operator must control, such as datadir,
+	// server_id, gtid_mode, or binlog_format, are ignored and reported in
+	// the MysqldConfigValid condition of the shard.
+	//
+	// Changes are rolled out by restarting tablets one at a time, like other
+	// changes to the tablet Pod spec.
+	Config map[string]string `json:"config,omitempty"`
+
+	// QueryLogs can optionally be used to turn on the slow query log or the
+	// general query log of MySQL. The logs are kept on the tablet's data
+	// volume, where a sidecar container rotates them and can also ship them
+	// elsewhere.
+	//
+	// The settings become part of the structured mysqld config, so options
+	// in config that they manage are ignored and reported in the
+	// MysqldConfigValid condition of the shard.
+	QueryLogs *MysqldQueryLogs `json:"queryLogs,omitempty"`
+
+	// AuditLog can optionally be used to load an audit log plugin into
+	// MySQL, which records connections and queries for compliance. The
+	// mysqld image must include the plugin, such as a Percona Server image
+	// for the Percona plugin. MySQL refuses to start if the plugin can't be
+	// loaded, rather than run without auditing.
+	//
+	// Like queryLogs, the settings become part of the structured mysqld
+	// config, so options in config that they manage are ignored, and changes
+	// are rolled out by restarting tablets one at a time.
+	AuditLog *MysqldAuditLog `json:"auditLog,omitempty"`
+}
+
+// MysqldQueryLogs configures the slow query log and general query log of a
+// MySQL instance.
+type MysqldQueryLogs struct {
+	// SlowQueryLog can optionally be set to log queries that take longer
+	// than a threshold.
+	SlowQueryLog *MysqldSlowQueryLog `json:"slowQueryLog,omitempty"`
+
+	// GeneralLog can optionally be set to log every statement that MySQL
+	// receives. This is costly on a busy tablet, so it's best turned on only
+	// while investigating a problem.
+	//
+	// Default: false
+	GeneralLog bool `json:"generalLog,omitempty"`
+
+	// MaxSize is how large a log file can grow before the sidecar rotates it.
+	//
+	// Default: 100Mi
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// MaxFiles is how many rotated files of each log to keep on the data
+	// volume, besides the one MySQL is writing to.
+	//
+	// Default: 5
+	// +kubebuilder:validation:Minimum=1
+	MaxFiles *int32 `json:"maxFiles,omitempty"`
+
+	// Sink is where the sidecar ships log lines, in addition to keeping them
+	// on the data volume.
+	//
+	// Supported options:
+	//   * file: Only keep the logs on the tablet's data volume.
+	//   * stdout: Also print each line on the sidecar's stdout, prefixed with
+	//     the name of the log, so it's collected with other container logs.
+	//   * fluentBit: Also send each line to a Fluent Bit TCP input, which
+	//     must be set in fluentBit.
+	//
+	// Default: file
+	// +kubebuilder:validation:Enum=file;stdout;fluentBit
+	Sink MysqldQueryLogSink `json:"sink,omitempty"`
+
+	// FluentBit is the Fluent Bit TCP input to send log lines to, if the
+	// sink is fluentBit. The input should use "format none", since each log
+	// line is sent as it's written.
+	FluentBit *MysqldQueryLogFluentBit `json:"fluentBit,omitempty"`
+
+	// Resources specify the compute resources to allocate for the sidecar
+	// that rotates and ships the logs.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// MysqldSlowQueryLog configures the slow query log of a MySQL instance.
+type MysqldSlowQueryLog struct {
+	// LongQueryTime is how long a query must take to be logged.
+	//
+	// Default: 1s
+	LongQueryTime *metav1.Duration `json:"longQueryTime,omitempty"`
+
+	// LogQueriesNotUsingIndexes can optionally be set to also log queries
+	// that scan a whole table or index, no matter how long they take.
+	//
+	// Default: false
+	LogQueriesNotUsingIndexes bool `json:"logQueriesNotUsingIndexes,omitempty"`
+}
+
+// MysqldQueryLogSink is where the query logs of a MySQL instance are shipped.
+type MysqldQueryLogSink string
+
+const (
+	// FileMysqldQueryLogSink keeps query logs only on the tablet's data volume.
+	FileMysqldQueryLogSink MysqldQueryLogSink = "file"
+	// StdoutMysqldQueryLogSink also prints query logs on the sidecar's stdout.
+	StdoutMysqldQueryLogSink MysqldQueryLogSink = "stdout"
+	// FluentBitMysqldQueryLogSink also sends query logs to Fluent Bit.
+	FluentBitMysqldQueryLogSink MysqldQueryLogSink = "fluentBit"
+)
+
+// MysqldQueryLogFluentBit is a Fluent Bit TCP input.
+type MysqldQueryLogFluentBit struct {
+	// Host is the host name or IP address of Fluent Bit, such as a Service
+	// in front of a Fluent Bit DaemonSet.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Port is the port of the TCP input.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// MysqldAuditLog configures the audit log plugin of a MySQL instance.
+type MysqldAuditLog struct {
+	// Plugin is which audit log plugin the mysqld image includes.
+	//
+	// Supported options:
+	//   * percona: The audit log plugin of Percona Server.
+	//   * mysqlEnterprise: MySQL Enterprise Audit, using its legacy
+	//     filtering options.
+	//
+	// +kubebuilder:validation:Enum=percona;mysqlEnterprise
+	Plugin MysqldAuditLogPlugin `json:"plugin"`
+
+	// Destination is where the plugin writes audit events.
+	//
+	// Supported options:
+	//   * file: A file on the tablet's data volume, which the plugin rotates.
+	//   * stderr: The stderr of the mysqld container, alongside the MySQL
+	//     error log, so it's collected with other container logs. Rotation
+	//     settings are ignored.
+	//
+	// Default: file
+	// +kubebuilder:validation:Enum=file;stderr
+	Destination MysqldAuditLogDestination `json:"destination,omitempty"`
+
+	// Format is the format of audit events. CSV is only supported by the
+	// Percona plugin.
+	//
+	// Default: JSON
+	// +kubebuilder:validation:Enum=JSON;NEW;OLD;CSV
+	Format string `json:"format,omitempty"`
+
+	// Policy is which events are logged: ALL events, only LOGINS, only
+	// QUERIES, or NONE.
+	//
+	// Default: ALL
+	// +kubebuilder:validation:Enum=ALL;LOGINS;QUERIES;NONE
+	Policy string `json:"policy,omitempty"`
+
+	// RotateOnSize is how large the audit log file can grow before the
+	// plugin rotates it.
+	//
+	// Default: 100Mi
+	RotateOnSize *resource.Quantity `json:"rotateOnSize,omitempty"`
+
+	// Rotations is how many rotated audit log files to keep on the data
+	// volume.
+	//
+	// Default: 5
+	// +kubebuilder:validation:Minimum=1
+	Rotations *int32 `json:"rotations,omitempty"`
+
+	// Filter can optionally be used to only log events of some accounts,
+	// commands, or databases.
+	Filter *MysqldAuditLogFilter `json:"filter,omitempty"`
+}
+
+// MysqldAuditLogPlugin is an audit log plugin for MySQL.
+type MysqldAuditLogPlugin string
+
+const (
+	// PerconaMysqldAuditLogPlugin is the audit log plugin of Percona Server.
+	PerconaMysqldAuditLogPlugin MysqldAuditLogPlugin = "percona"
+	// MysqlEnterpriseMysqldAuditLogPlugin is MySQL Enterprise Audit.
+	MysqlEnterpriseMysqldAuditLogPlugin MysqldAuditLogPlugin = "mysqlEnterprise"
+)
+
+// MysqldAuditLogDestination is where the audit log plugin writes events.
+type MysqldAuditLogDestination string
+
+const (
+	// FileMysqldAuditLogDestination writes audit events to the data volume.
+	FileMysqldAuditLogDestination MysqldAuditLogDestination = "file"
+	// StderrMysqldAuditLogDestination writes audit events to mysqld's stderr.
+	StderrMysqldAuditLogDestination MysqldAuditLogDestination = "stderr"
+)
+
+// MysqldAuditLogFilter selects which events the audit log plugin records.
+// For each kind of filter, either the include or the exclude list can be
+// set, but not both.
+type MysqldAuditLogFilter struct {
+	// IncludeAccounts are the only accounts whose events are logged, each
+	// written as user@host, such as "app@%".
+	IncludeAccounts []string `json:"includeAccounts,omitempty"`
+	// ExcludeAccounts are accounts whose events aren't logged.
+	ExcludeAccounts []string `json:"excludeAccounts,omitempty"`
+
+	// IncludeCommands are the only commands that are logged, such as
+	// "create_table" or "drop_db". Only supported by the Percona plugin.
+	IncludeCommands []string `json:"includeCommands,omitempty"`
+	// ExcludeCommands are commands that aren't logged. Only supported by the
+	// Percona plugin.
+	ExcludeCommands []string `json:"excludeCommands,omitempty"`
+
+	// IncludeDatabases are the only databases whose queries are logged. Only
+	// supported by the Percona plugin.
+	IncludeDatabases []string `json:"includeDatabases,omitempty"`
+	// ExcludeDatabases are databases whose queries aren't logged. Only
+	// supported by the Percona plugin.
+	ExcludeDatabases []string `json:"excludeDatabases,omitempty"`
 }
 
 // MysqldExporterSpec configures the local MySQL exporter within a tablet.
 type MysqldExporterSpec struct {
 	// Resources specify the compute resources to allocate for just the MySQL Exporter.
 	Resources corev1.ResourceRequirements `json:"resources"`
+
+	// MonitoringUser can optionally be set to have the exporter connect as a
+	// dedicated MySQL user, instead of the all-powerful vt_dba user. The
+	// operator generates a password for the user in a Secret it manages for
+	// the shard, and creates the user on the shard's primary with only the
+	// privileges the exporter needs. The user replicates to the other
+	// tablets of the shard.
+	//
+	// Turning this off again switches the exporter back to vt_dba, but
+	// leaves the MySQL user in place.
+	//
+	// Default: false
+	MonitoringUser bool `json:"monitoringUser,omitempty"`
+
+	// ScrapeAnnotations can optionally be set to add the prometheus.io/scrape,
+	// prometheus.io/port, and prometheus.io/path annotations to tablet Pods,
+	// pointing at the exporter, for Prometheus setups that discover targets
+	// from annotations.
+	//
+	// Default: false
+	ScrapeAnnotations bool `json:"scrapeAnnotations,omitempty"`
+
+	// ServiceMonitor can optionally be set to create a Prometheus Operator
+	// ServiceMonitor that scrapes the exporter of each tablet in the pool,
+	// through the cluster's vttablet Service. It's ignored if the
+	// ServiceMonitor CRD isn't installed.
+	ServiceMonitor *MysqldExporterServiceMonitor `json:"serviceMonitor,omitempty"`
+}
+
+// MysqldExporterServiceMonitor configures a ServiceMonitor for the MySQL
+// exporters of a tablet pool.
+type MysqldExporterServiceMonitor struct {
+	// Interval is how often Prometheus scrapes each exporter, as a Prometheus
+	// duration like "30s". If unset, Prometheus uses its global interval.
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	Interval string `json:"interval,omitempty"`
+
+	// Labels can optionally be used to attach extra labels to the
+	// ServiceMonitor, such as the ones a Prometheus uses to select which
+	// ServiceMonitors it loads.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MysqlUser is a MySQL user that the operator manages.
+type MysqlUser struct {
+	// Username is the name of the MySQL user. Names that start with "vt_"
+	// are reserved for Vitess and the operator, as are "root" and the
+	// "mysql." system users.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=32
+	Username string `json:"username"`
+
+	// Hosts are the host patterns that the user can connect from, such as
+	// "%" for anywhere or "10.0.%" for a subnet. A MySQL account with the
+	// same password and grants is created for each one.
+	//
+	// Default: ["%"]
+	Hosts []string `json:"hosts,omitempty"`
+
+	// SecretRef selects the key of a Secret that holds the user's password.
+	// The Secret must be in the same namespace as the VitessCluster.
+	// Changing the password in the Secret updates the user.
+	SecretRef corev1.SecretKeySelector `json:"secretRef"`
+
+	// Grants are the privileges of the user. Privileges that aren't listed
+	// here are revoked whenever the user is updated.
+	Grants []MysqlGrant `json:"grants,omitempty"`
+}
+
+// MysqlGrant is a set of privileges on a database or table.
+type MysqlGrant struct {
+	// Privileges are the privileges to grant, such as SELECT, INSERT, or
+	// "ALL PRIVILEGES".
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^[A-Za-z][A-Za-z_ ]*$`
+	Privileges []string `json:"privileges"`
+
+	// Database is the database that the privileges apply to, or "*" for all
+	// databases.
+	//
+	// Default: "*"
+	Database string `json:"database,omitempty"`
+
+	// Table is the table within the database that the privileges apply to,
+	// or "*" for all tables.
+	//
+	// Default: "*"
+	Table string `json:"table,omitempty"`
+
+	// WithGrantOption can optionally be set to let the user grant these
+	// privileges to others.
+	//
+	// Default: false
+	WithGrantOption bool `json:"withGrantOption,omitempty"`
 }
 
 // VitessTabletPoolType represents the tablet types for which it makes sense
@@ -342,6 +1301,20 @@ const (
 	ExternalRdonlyPoolType VitessTabletPoolType = "externalrdonly"
 )
 
+// VitessTabletPoolStorageType is whether the tablets in a pool keep their data
+// when their Pods are deleted.
+type VitessTabletPoolStorageType string
+
+const (
+	// PersistentStorageType keeps each tablet's data on a
+	// PersistentVolumeClaim that outlives its Pod.
+	PersistentStorageType VitessTabletPoolStorageType = "persistent"
+	// EphemeralStorageType keeps each tablet's data on a volume that's
+	// deleted along with its Pod, and restores the tablet from backup
+	// whenever its Pod is recreated.
+	EphemeralStorageType VitessTabletPoolStorageType = "ephemeral"
+)
+
 // ExternalDatastore defines information that vttablet needs to connect to an
 // externally managed MySQL.
 type ExternalDatastore struct {
@@ -368,6 +1341,102 @@ type ExternalDatastore struct {
 
 	// ServerCACertSecret should link to a certificate authority file if one is required by your externally managed MySQL endpoint.
 	ServerCACertSecret *SecretSource `json:"serverCACertSecret,omitempty"`
+
+	// Provider optionally names the hosted MySQL service that manages this
+	// datastore, so the operator can apply a preset of settings that suit
+	// it. A preset only fills in settings that aren't set explicitly.
+	//
+	// Supported options:
+	//   * aws-aurora - Amazon Aurora MySQL. Requires TLS, verifying the
+	//     endpoint's identity if serverCACertSecret is given, and watches
+	//     the writer endpoint's DNS for failovers.
+	//   * gcp-cloudsql - Google Cloud SQL for MySQL. Requires TLS, verifying
+	//     the server's CA if serverCACertSecret is given. Failovers keep the
+	//     same IP, so there are no signals to watch by default.
+	//   * azure-mysql - Azure Database for MySQL. Requires TLS and verifies
+	//     the server's identity against the system CA bundle, unless
+	//     serverCACertSecret is given, and watches DNS for failovers.
+	//
+	// Default: No preset.
+	// +kubebuilder:validation:Enum=aws-aurora;gcp-cloudsql;azure-mysql
+	Provider ExternalDatastoreProvider `json:"provider,omitempty"`
+
+	// SSLMode is how vttablet secures its connections to MySQL.
+	//
+	// Default: Set by the provider preset, if any. Otherwise, vttablet's
+	// default is used.
+	// +kubebuilder:validation:Enum=disabled;preferred;required;verify_ca;verify_identity
+	SSLMode string `json:"sslMode,omitempty"`
+
+	// DemotePrimaryToSpare specifies whether the operator changes the old
+	// primary tablet to SPARE after it reparents the shard to another
+	// "externalmaster" tablet, for example while draining. This keeps the
+	// old tablet from serving replica traffic while it still points at the
+	// same writer endpoint, which is how hosted MySQL services are usually
+	// set up. Disable it if each "externalmaster" pool points at a
+	// different MySQL, and the old primary's MySQL can serve as a replica.
+	//
+	// Default: true.
+	DemotePrimaryToSpare *bool `json:"demotePrimaryToSpare,omitempty"`
+
+	// Failover optionally tells the operator how to notice that the
+	// externally managed MySQL has failed over to a new primary, so it can
+	// rewire the tablets in "externalmaster" pools and report the change to
+	// Vitess with TabletExternallyReparented.
+	//
+	// Default: Failovers are only followed when the shard has no primary.
+	Failover *ExternalFailoverSpec `json:"failover,omitempty"`
+}
+
+// ExternalDatastoreProvider names a hosted MySQL service.
+type ExternalDatastoreProvider string
+
+const (
+	// AuroraExternalDatastoreProvider is Amazon Aurora MySQL.
+	AuroraExternalDatastoreProvider ExternalDatastoreProvider = "aws-aurora"
+	// CloudSQLExternalDatastoreProvider is Google Cloud SQL for MySQL.
+	CloudSQLExternalDatastoreProvider ExternalDatastoreProvider = "gcp-cloudsql"
+	// AzureMySQLExternalDatastoreProvider is Azure Database for MySQL.
+	AzureMySQLExternalDatastoreProvider ExternalDatastoreProvider = "azure-mysql"
+)
+
+// ExternalFailoverSpec configures how the operator follows failovers of an
+// externally managed MySQL.
+//
+// When either signal changes, the operator restarts the tablets in
+// "externalmaster" pools so they drop their connections to the old primary
+// and connect to the new one. Once one of them is running again, it's
+// reported to Vitess as the shard primary.
+type ExternalFailoverSpec struct {
+	// PrimaryHostFrom optionally points at a key in a ConfigMap or Secret,
+	// in the same namespace, that external failover tooling keeps up to date
+	// with the host of the current primary. Tablets in "externalmaster"
+	// pools connect to that host instead of the one in 'host', and a change
+	// in its value is treated as a failover.
+	PrimaryHostFrom *ExternalHostSource `json:"primaryHostFrom,omitempty"`
+
+	// WatchDNS tells the operator to periodically resolve the primary host
+	// and treat a change in the addresses it resolves to as a failover.
+	// This is useful for managed databases whose writer endpoint is a DNS
+	// name that moves to the new primary.
+	//
+	// Default: false.
+	WatchDNS bool `json:"watchDNS,omitempty"`
+
+	// CheckInterval is how often the operator checks the signals for a
+	// failover.
+	//
+	// Default: 30s.
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+}
+
+// ExternalHostSource selects a key in a ConfigMap or a Secret whose value is
+// a host name or IP address. Exactly one of the fields should be set.
+type ExternalHostSource struct {
+	// ConfigMapKeyRef selects a key in a ConfigMap.
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// SecretKeyRef selects a key in a Secret.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // VitessShardStatus defines the observed state of a VitessShard.
@@ -420,11 +1489,290 @@ type VitessShardStatus struct {
 	// each backup location.
 	BackupLocations []*ShardBackupLocationStatus `json:"backupLocations,omitempty"`
 
+	// BackupRequest reports the progress of the on-demand backup requested
+	// with the "backup.planetscale.com/request" annotation, if any.
+	BackupRequest *ShardBackupRequestStatus `json:"backupRequest,omitempty"`
+
 	// LowestPodGeneration is the oldest VitessShard object generation seen across
 	// all child Pods. The tablet information in VitessShard status is guaranteed to be
 	// at least as up-to-date as this VitessShard generation. Changes made in
 	// subsequent generations that affect tablets may not be reflected in status yet.
 	LowestPodGeneration int64 `json:"lowestPodGeneration,omitempty"`
+
+	// DrainStatus reports the progress of any tablet drains in the shard.
+	DrainStatus VitessShardDrainStatus `json:"drainStatus,omitempty"`
+
+	// ReparentHistory lists the most recent reparents the operator attempted
+	// in the shard, newest first, whether or not they succeeded. Only the
+	// last 10 are kept.
+	// Like Conditions, it's preserved across status updates.
+	ReparentHistory []VitessShardReparentRecord `json:"reparentHistory,omitempty"`
+
+	// Canary reports the progress of a canary upgrade of the vttablet image,
+	// if the update strategy calls for one and an upgrade is in progress.
+	// Like Conditions, it's preserved across status updates.
+	Canary *VitessShardCanaryStatus `json:"canary,omitempty"`
+
+	// UpgradeStatus reports the progress of the most recent MySQL
+	// major-version upgrade of the shard, such as from 5.7 to 8.0, or from
+	// 8.0 to 8.4. Once the upgrade is complete, it's kept until the next one.
+	// Like Conditions, it's preserved across status updates.
+	UpgradeStatus *VitessShardUpgradeStatus `json:"upgradeStatus,omitempty"`
+
+	// RolloutBackoff reports how the current rollout is backing off from
+	// updated tablet Pods that kept crashing, if any have.
+	// Like Conditions, it's preserved across status updates.
+	RolloutBackoff *VitessShardRolloutBackoffStatus `json:"rolloutBackoff,omitempty"`
+
+	// TabletPoolResources reports the compute resources that tablets use in
+	// each pool that has vertical autoscaling enabled, and what they should
+	// request. Like Conditions, it's preserved across status updates.
+	TabletPoolResources []VitessTabletPoolResourceStatus `json:"tabletPoolResources,omitempty"`
+
+	// TabletPoolScaleProfiles lists the tablet pools that have a scale
+	// profile in effect.
+	TabletPoolScaleProfiles []VitessTabletPoolScaleProfileStatus `json:"tabletPoolScaleProfiles,omitempty"`
+
+	// ExternalFailover reports the failover signals last seen for the
+	// externally managed MySQL, if its failover settings are configured,
+	// and whether the shard has caught up with the latest failover.
+	// Like Conditions, it's preserved across status updates.
+	ExternalFailover *VitessShardExternalFailoverStatus `json:"externalFailover,omitempty"`
+}
+
+// VitessShardExternalFailoverStatus reports what the operator last saw of
+// the failover signals for an externally managed MySQL.
+type VitessShardExternalFailoverStatus struct {
+	// PrimaryHost is the host of the current primary, as last read from
+	// primaryHostFrom, or else the datastore's configured host.
+	PrimaryHost string `json:"primaryHost,omitempty"`
+	// Addresses are the addresses that PrimaryHost last resolved to,
+	// if watchDNS is enabled.
+	Addresses []string `json:"addresses,omitempty"`
+	// LastFailoverTime is when the operator last saw a signal change.
+	LastFailoverTime *metav1.Time `json:"lastFailoverTime,omitempty"`
+	// RewiredTime is when the tablets in "externalmaster" pools were last
+	// restarted and reported to Vitess after a failover. If it's before
+	// LastFailoverTime, the shard is still catching up with the failover.
+	RewiredTime *metav1.Time `json:"rewiredTime,omitempty"`
+	// Message describes the last problem checking the signals, if any.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessTabletPoolScaleProfileStatus reports the scale profile that's in
+// effect for a tablet pool.
+type VitessTabletPoolScaleProfileStatus struct {
+	// Cell is the cell of the tablet pool.
+	Cell string `json:"cell"`
+	// Type is the type of the tablet pool.
+	Type VitessTabletPoolType `json:"type"`
+	// Name is the name of the tablet pool, if it has one.
+	Name string `json:"name,omitempty"`
+	// Profile is the name of the scale profile in effect.
+	Profile string `json:"profile"`
+	// Replicas is the number of tablets that the profile calls for.
+	Replicas int32 `json:"replicas"`
+}
+
+// VitessTabletPoolResourceStatus reports the compute resources that the
+// tablets in a pool use, and what they should request.
+type VitessTabletPoolResourceStatus struct {
+	// Cell is the cell of the tablet pool.
+	Cell string `json:"cell"`
+	// Type is the type of the tablet pool.
+	Type VitessTabletPoolType `json:"type"`
+	// Name is the name of the tablet pool, if it has one.
+	Name string `json:"name,omitempty"`
+	// SampledSince is when usage started being sampled for this pool.
+	SampledSince metav1.Time `json:"sampledSince"`
+	// LastSampleTime is when usage was last sampled.
+	LastSampleTime *metav1.Time `json:"lastSampleTime,omitempty"`
+	// Containers reports on each container that resources are recommended for.
+	Containers []VitessContainerResourceStatus `json:"containers,omitempty"`
+}
+
+// VitessContainerResourceStatus reports the compute resources that a
+// container in the tablets of a pool uses, and what it should request.
+type VitessContainerResourceStatus struct {
+	// Name is the name of the container.
+	Name string `json:"name"`
+	// PeakUsage is the highest usage seen in any tablet in the pool. Older
+	// peaks count for less over time, so this follows usage down as well as up.
+	PeakUsage corev1.ResourceList `json:"peakUsage,omitempty"`
+	// Recommended is the resource requests that fit PeakUsage, with room to spare.
+	Recommended corev1.ResourceList `json:"recommended,omitempty"`
+	// Applied is the resource requests that the operator applied in place of
+	// those in the spec, if vertical autoscaling is in Auto mode.
+	Applied corev1.ResourceList `json:"applied,omitempty"`
+}
+
+// VitessShardRolloutBackoffStatus reports how a rollout is backing off from
+// updated tablet Pods that kept crashing.
+type VitessShardRolloutBackoffStatus struct {
+	// Failures is the number of times the rollout has gotten stuck.
+	Failures int32 `json:"failures"`
+	// RetryTime is when the rollout may update the next tablet, once the
+	// Pods that kept crashing have recovered.
+	RetryTime *metav1.Time `json:"retryTime,omitempty"`
+}
+
+// VitessShardUpgradePhase describes where a MySQL major-version upgrade is at.
+type VitessShardUpgradePhase string
+
+const (
+	// UpgradingReplicasPhase means non-primary tablets are being upgraded one
+	// at a time.
+	UpgradingReplicasPhase VitessShardUpgradePhase = "UpgradingReplicas"
+	// ReparentingPhase means every other tablet has been upgraded, and the
+	// primary is being drained so an upgraded tablet can take over.
+	ReparentingPhase VitessShardUpgradePhase = "Reparenting"
+	// UpgradingPrimaryPhase means an upgraded tablet has taken over as primary,
+	// and the old primary is being upgraded.
+	UpgradingPrimaryPhase VitessShardUpgradePhase = "UpgradingPrimary"
+	// UpgradeCompletePhase means every tablet came up on the new version.
+	UpgradeCompletePhase VitessShardUpgradePhase = "Complete"
+	// UpgradeFailedPhase means a tablet failed to start on the new version.
+	// The upgrade is halted until the tablet is fixed.
+	UpgradeFailedPhase VitessShardUpgradePhase = "Failed"
+)
+
+// VitessTabletUpgradePhase describes where a tablet is at in a MySQL
+// major-version upgrade.
+type VitessTabletUpgradePhase string
+
+const (
+	// TabletUpgradePendingPhase means the tablet hasn't been upgraded yet.
+	TabletUpgradePendingPhase VitessTabletUpgradePhase = "Pending"
+	// TabletUpgradingPhase means the tablet is being restarted on the new
+	// version, and hasn't come up yet.
+	TabletUpgradingPhase VitessTabletUpgradePhase = "Upgrading"
+	// TabletUpgradeSucceededPhase means the tablet is Ready on the new version,
+	// which means MySQL upgraded its data directory successfully.
+	TabletUpgradeSucceededPhase VitessTabletUpgradePhase = "Succeeded"
+	// TabletUpgradeFailedPhase means mysqld keeps failing to start on the new
+	// version.
+	TabletUpgradeFailedPhase VitessTabletUpgradePhase = "Failed"
+)
+
+// VitessShardUpgradeStatus reports the progress of a MySQL major-version
+// upgrade of a shard.
+//
+// The upgrade is guided by the operator: fast shutdown is disabled before
+// each tablet is restarted, replicas are upgraded one at a time and must come
+// up on the new version before the next one is restarted, then the primary is
+// reparented onto an upgraded tablet, and finally the old primary is upgraded.
+// If any tablet fails to come up on the new version, the upgrade is halted.
+type VitessShardUpgradeStatus struct {
+	// Image is the mysqld image being rolled out.
+	Image string `json:"image"`
+	// FromVersion is the MySQL version series the shard is upgrading from,
+	// such as "5.7".
+	FromVersion string `json:"fromVersion,omitempty"`
+	// ToVersion is the MySQL version series the shard is upgrading to, such
+	// as "8.0".
+	ToVersion string `json:"toVersion,omitempty"`
+	// Phase is where the upgrade is at.
+	Phase VitessShardUpgradePhase `json:"phase,omitempty"`
+	// Primary is the alias of the tablet that was primary when the upgrade
+	// started. It's upgraded last.
+	Primary string `json:"primary,omitempty"`
+	// StartTime is when the upgrade started.
+	StartTime metav1.Time `json:"startTime"`
+	// CompletionTime is when every tablet had come up on the new version.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Steps lists the tablets in the order they're upgraded, along with the
+	// progress of each.
+	Steps []VitessTabletUpgradeStep `json:"steps,omitempty"`
+}
+
+// VitessTabletUpgradeStep reports the progress of upgrading one tablet.
+type VitessTabletUpgradeStep struct {
+	// Tablet is the alias of the tablet.
+	Tablet string `json:"tablet"`
+	// Phase is where the tablet is at.
+	Phase VitessTabletUpgradePhase `json:"phase"`
+	// Message explains the phase, if there's more to say.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessShardCanaryStatus reports the progress of a canary upgrade of the
+// vttablet image in a shard.
+type VitessShardCanaryStatus struct {
+	// Image is the vttablet image being rolled out.
+	Image string `json:"image"`
+	// Tablets lists the aliases of the canary tablets, which are upgraded
+	// before the rest of the shard.
+	Tablets []string `json:"tablets,omitempty"`
+	// SoakStartTime is when the canary tablets were first seen healthy on the
+	// new image. It's cleared if a canary becomes unhealthy.
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+	// Promoted is whether the canary tablets made it through the soak period,
+	// so the rest of the shard may be upgraded.
+	Promoted bool `json:"promoted,omitempty"`
+}
+
+// VitessShardReparentRecord describes a reparent the operator attempted.
+type VitessShardReparentRecord struct {
+	// Time is when the reparent finished.
+	Time metav1.Time `json:"time"`
+	// OldPrimary is the alias of the primary tablet before the reparent.
+	OldPrimary string `json:"oldPrimary,omitempty"`
+	// NewPrimary is the alias of the tablet that was chosen to be the new
+	// primary. It's empty if Vitess was left to choose one and failed.
+	NewPrimary string `json:"newPrimary,omitempty"`
+	// Reason is why the operator reparented the shard.
+	Reason VitessShardReparentReason `json:"reason"`
+	// Succeeded is whether the reparent succeeded.
+	Succeeded bool `json:"succeeded"`
+	// Message describes the error if the reparent failed.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessShardReparentReason is why the operator reparented a shard.
+type VitessShardReparentReason string
+
+const (
+	// DrainReparentReason means the primary was drained, for example because its node was being drained.
+	DrainReparentReason VitessShardReparentReason = "Drain"
+	// UpdateReparentReason means the primary was drained for a rolling update.
+	UpdateReparentReason VitessShardReparentReason = "Update"
+	// RotationReparentReason means the primary was rotated on its primaryRotationSchedule.
+	RotationReparentReason VitessShardReparentReason = "Rotation"
+	// FailoverReparentReason means the primary was lost and replaced with an emergency reparent.
+	FailoverReparentReason VitessShardReparentReason = "Failover"
+	// AffinityReparentReason means the primary was moved back to where the shard's primaryAffinity says it belongs.
+	AffinityReparentReason VitessShardReparentReason = "Affinity"
+	// ExternalFailoverReparentReason means an externally managed MySQL failed over, and the tablet connected to the new primary was reported to Vitess.
+	ExternalFailoverReparentReason VitessShardReparentReason = "ExternalFailover"
+)
+
+// VitessShardDrainStatus reports the progress of tablet drains in a shard.
+// Each list contains the aliases of the tablets (desired or orphaned) whose
+// Pods are currently in that step of the drain state machine.
+type VitessShardDrainStatus struct {
+	// Draining is the list of tablets that have been requested to drain,
+	// but whose drain has not yet been acknowledged by the operator.
+	Draining []string `json:"draining,omitempty"`
+	// Acknowledged is the list of tablets whose drain has been acknowledged,
+	// but which are not yet safe to delete.
+	Acknowledged []string `json:"acknowledged,omitempty"`
+	// Finished is the list of tablets that have finished draining and are
+	// safe to delete.
+	Finished []string `json:"finished,omitempty"`
+	// Aborted is the list of tablets whose Pods still have acknowledged or
+	// finished annotations, but no longer have a drain request.
+	Aborted []string `json:"aborted,omitempty"`
+	// Stuck is the list of acknowledged tablets whose drain has not finished
+	// within the shard's drainTimeout.
+	Stuck []string `json:"stuck,omitempty"`
+	// PrimaryReparentPending is a condition indicating whether the current
+	// primary tablet is draining, and therefore needs to be reparented away
+	// before its drain can finish.
+	PrimaryReparentPending corev1.ConditionStatus `json:"primaryReparentPending,omitempty"`
+	// PrimaryReparentBlocked is a condition indicating whether a pending
+	// reparent away from the draining primary is blocked because no other
+	// tablet in the shard is currently eligible to become primary.
+	PrimaryReparentBlocked corev1.ConditionStatus `json:"primaryReparentBlocked,omitempty"`
 }
 
 // VitessOrchestratorStatus is a summary of the status of the vtorc deployment.
@@ -433,6 +1781,28 @@ type VitessOrchestratorStatus struct {
 	Available corev1.ConditionStatus `json:"available,omitempty"`
 	// ServiceName is the name of the Service for this cluster's vtorc.
 	ServiceName string `json:"serviceName,omitempty"`
+	// RecentRecoveries lists the most recent recoveries that vtorc ran in
+	// the shard, newest first. Only the last 10 are kept.
+	// Like Conditions, it's preserved across status updates. It's only
+	// reported for vtorc Deployments that watch a single shard.
+	RecentRecoveries []VitessOrchestratorRecoveryRecord `json:"recentRecoveries,omitempty"`
+}
+
+// VitessOrchestratorRecoveryRecord describes recoveries that vtorc ran.
+type VitessOrchestratorRecoveryRecord struct {
+	// Time is when the operator noticed the recoveries. vtorc doesn't report
+	// when each recovery ran, so this can be up to a resync period later.
+	Time metav1.Time `json:"time"`
+	// Type is the kind of recovery, such as RecoverDeadPrimary or
+	// ElectNewPrimary.
+	Type string `json:"type"`
+	// Succeeded is whether the recoveries succeeded.
+	Succeeded bool `json:"succeeded"`
+	// Count is the number of recoveries of this type and result since the
+	// operator last checked.
+	Count int32 `json:"count"`
+	// Pod is the name of the vtorc Pod that ran the recoveries.
+	Pod string `json:"pod,omitempty"`
 }
 
 // VitessShardConditionType is a valid value for the key of a VitessShardCondition map where the key is a
@@ -456,6 +1826,49 @@ type VitessShardCondition struct {
 	Message string `json:"message,omitempty"`
 }
 
+// These are valid conditions of VitessShard.
+const (
+	// VitessShardDrainAborted indicates whether any tablet Pods in the shard have been left partially drained
+	// by a drain that was aborted.
+	VitessShardDrainAborted VitessShardConditionType = "DrainAborted"
+	// VitessShardDrainStuck indicates whether any tablet in the shard has had its drain acknowledged for longer
+	// than the shard's drainTimeout without finishing.
+	VitessShardDrainStuck VitessShardConditionType = "DrainStuck"
+	// VitessShardReparentLagCheckPassed indicates whether the replicas in the destination cell of the last attempted
+	// planned reparent were within that cell's replication lag threshold.
+	VitessShardReparentLagCheckPassed VitessShardConditionType = "ReparentLagCheckPassed"
+	// VitessShardSemiSyncSatisfiable indicates whether the shard's tablet pools have enough replica-type tablets to
+	// ack writes for any primary, when the shard is in semiSync replication mode.
+	VitessShardSemiSyncSatisfiable VitessShardConditionType = "SemiSyncSatisfiable"
+	// VitessShardZoneSpreadSatisfiable indicates whether the shard's tablet pools guarantee that its replica-type
+	// tablets land in at least minReplicaZones distinct zones, when that's set.
+	VitessShardZoneSpreadSatisfiable VitessShardConditionType = "ZoneSpreadSatisfiable"
+	// VitessShardQueryServingValid indicates whether the queryServing settings of the shard's tablet pools are
+	// consistent and take effect, when any pool has them.
+	VitessShardQueryServingValid VitessShardConditionType = "QueryServingValid"
+	// VitessShardMysqldConfigValid indicates whether the mysqld config settings and auto-tuning of the shard's
+	// tablet pools are all applied, when any pool has them.
+	VitessShardMysqldConfigValid VitessShardConditionType = "MysqldConfigValid"
+	// VitessShardErrantGTIDsDetected indicates whether any replica-type tablet in the shard was found to have
+	// transactions that the primary doesn't have, when errant GTID checks are enabled.
+	VitessShardErrantGTIDsDetected VitessShardConditionType = "ErrantGTIDsDetected"
+	// VitessShardMysqldConfigDriftDetected indicates whether any tablet in the shard was found to have MySQL
+	// global variables that don't match its mysqld config, when config drift checks are enabled.
+	VitessShardMysqldConfigDriftDetected VitessShardConditionType = "MysqldConfigDriftDetected"
+	// VitessShardInitialRestoreComplete indicates whether the shard has had a primary since it was bootstrapped from
+	// the backups of the cluster's initialRestore, after which its tablets use the cluster's own backups.
+	VitessShardInitialRestoreComplete VitessShardConditionType = "InitialRestoreComplete"
+	// VitessShardUpgradeBlocked indicates whether the safety checks that must pass before a new vttablet or mysqld
+	// image is rolled out to the shard have failed, when the update strategy calls for upgrade checks.
+	VitessShardUpgradeBlocked VitessShardConditionType = "UpgradeBlocked"
+	// VitessShardRolloutStuck indicates whether the rollout of the shard is stuck because an updated tablet Pod
+	// keeps crashing.
+	VitessShardRolloutStuck VitessShardConditionType = "RolloutStuck"
+	// VitessShardStorageTypeValid indicates whether every tablet pool in the shard that asks for ephemeral storage
+	// can use it, when any pool does.
+	VitessShardStorageTypeValid VitessShardConditionType = "StorageTypeValid"
+)
+
 // NewVitessShardStatus creates a new status object with default values.
 func NewVitessShardStatus() VitessShardStatus {
 	return VitessShardStatus{
@@ -469,6 +1882,10 @@ func NewVitessShardStatus() VitessShardStatus {
 		ServingWrites:    corev1.ConditionUnknown,
 		Idle:             corev1.ConditionUnknown,
 		Conditions:       make(map[VitessShardConditionType]VitessShardCondition),
+		DrainStatus: VitessShardDrainStatus{
+			PrimaryReparentPending: corev1.ConditionUnknown,
+			PrimaryReparentBlocked: corev1.ConditionUnknown,
+		},
 	}
 }
 
@@ -494,6 +1911,142 @@ type VitessTabletStatus struct {
 	// PendingChanges describes changes to the tablet Pod that will be applied
 	// the next time a rolling update allows.
 	PendingChanges string `json:"pendingChanges,omitempty"`
+	// Replicating indicates whether replication was healthy the last time the
+	// operator checked it. It's only reported for replica-type tablets when
+	// replication repair is enabled for the shard.
+	Replicating corev1.ConditionStatus `json:"replicating,omitempty"`
+	// ReplicationError describes why replication was not healthy the last time
+	// the operator checked it.
+	ReplicationError string `json:"replicationError,omitempty"`
+	// ErrantGTIDs is the set of transactions the tablet has that the primary
+	// doesn't, as of the last time the operator checked. It's only reported
+	// when errant GTID checks are enabled for the shard.
+	ErrantGTIDs string `json:"errantGTIDs,omitempty"`
+	// MysqldConfigDrift describes the MySQL global variables that didn't
+	// match the tablet's mysqld config the last time the operator checked.
+	// It's only reported when config drift checks are enabled for the shard.
+	MysqldConfigDrift string `json:"mysqldConfigDrift,omitempty"`
+	// Restore reports the progress of restoring the tablet from backup, as of
+	// the last time the operator checked. It's only reported while the tablet
+	// is restoring.
+	Restore *VitessTabletRestoreStatus `json:"restore,omitempty"`
+	// DataVolumeResize reports the progress of growing the tablet's data
+	// volume to the size requested in its pool's dataVolumeClaimTemplate.
+	// It's only reported while the volume is smaller than that.
+	DataVolumeResize *VitessTabletDataVolumeResizeStatus `json:"dataVolumeResize,omitempty"`
+	// DataVolumeMigration reports the progress of moving the tablet's data
+	// volume to the StorageClass set in its pool's dataVolumeClaimTemplate.
+	// It's only reported while the volume is on a different StorageClass.
+	DataVolumeMigration *VitessTabletDataVolumeMigrationStatus `json:"dataVolumeMigration,omitempty"`
+}
+
+// VitessTabletRestorePhase is a step in restoring a tablet from backup.
+type VitessTabletRestorePhase string
+
+const (
+	// RestoreStartingPhase means the tablet is restoring, but hasn't written
+	// any data yet. It may still be looking for a backup or waiting for MySQL.
+	RestoreStartingPhase VitessTabletRestorePhase = "Starting"
+	// RestoreCopyingPhase means the tablet is writing the backup's data.
+	RestoreCopyingPhase VitessTabletRestorePhase = "Copying"
+)
+
+// VitessTabletRestoreStatus reports the progress of restoring a tablet from
+// backup.
+type VitessTabletRestoreStatus struct {
+	// Phase is the step the restore is at.
+	Phase VitessTabletRestorePhase `json:"phase,omitempty"`
+	// StartTime is when the operator first saw the tablet restoring.
+	StartTime metav1.Time `json:"startTime"`
+	// RestoredBytes is how much data the tablet has written so far, as
+	// reported by vttablet. Only some backup engines report this.
+	RestoredBytes int64 `json:"restoredBytes,omitempty"`
+	// EstimatedTotalBytes is an estimate of how much data the restore will
+	// write in total, based on the size of the primary's tables.
+	EstimatedTotalBytes int64 `json:"estimatedTotalBytes,omitempty"`
+	// EstimatedCompletionTime is when the restore is expected to finish if it
+	// keeps going at the same rate as it has so far.
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+}
+
+// VitessTabletDataVolumeResizeMethod is how a tablet's data volume is grown.
+type VitessTabletDataVolumeResizeMethod string
+
+const (
+	// ExpandDataVolumeResizeMethod means the volume is expanded in place,
+	// because its StorageClass allows volume expansion.
+	ExpandDataVolumeResizeMethod VitessTabletDataVolumeResizeMethod = "Expand"
+	// ReplaceDataVolumeResizeMethod means the volume is replaced with a new,
+	// bigger one, and the tablet is restored from backup onto it, because its
+	// StorageClass doesn't allow volume expansion.
+	ReplaceDataVolumeResizeMethod VitessTabletDataVolumeResizeMethod = "Replace"
+)
+
+// VitessTabletDataVolumeResizePhase is a step in growing a tablet's data
+// volume.
+type VitessTabletDataVolumeResizePhase string
+
+const (
+	// DataVolumeResizeWaitingPhase means the resize hasn't started, because
+	// it's waiting for a maintenance window.
+	DataVolumeResizeWaitingPhase VitessTabletDataVolumeResizePhase = "Waiting"
+	// DataVolumeResizeExpandingPhase means the volume has been asked to
+	// expand, and the storage provider is working on it.
+	DataVolumeResizeExpandingPhase VitessTabletDataVolumeResizePhase = "Expanding"
+	// DataVolumeResizeFilesystemPendingPhase means the volume has expanded,
+	// and the filesystem on it will grow when the tablet restarts.
+	DataVolumeResizeFilesystemPendingPhase VitessTabletDataVolumeResizePhase = "FilesystemResizePending"
+	// DataVolumeResizeReplacingPhase means the tablet will be drained, and
+	// then its volume and Pod deleted so they're recreated at the new size
+	// and restored from backup, as soon as it's safe. Only one tablet in a
+	// shard is replaced at a time.
+	DataVolumeResizeReplacingPhase VitessTabletDataVolumeResizePhase = "Replacing"
+)
+
+// VitessTabletDataVolumeResizeStatus reports the progress of growing a
+// tablet's data volume.
+type VitessTabletDataVolumeResizeStatus struct {
+	// Method is how the volume is being grown.
+	Method VitessTabletDataVolumeResizeMethod `json:"method,omitempty"`
+	// Phase is the step the resize is at.
+	Phase VitessTabletDataVolumeResizePhase `json:"phase,omitempty"`
+	// CurrentSize is the capacity the volume has now.
+	CurrentSize *resource.Quantity `json:"currentSize,omitempty"`
+	// DesiredSize is the size requested in the pool's dataVolumeClaimTemplate.
+	DesiredSize *resource.Quantity `json:"desiredSize,omitempty"`
+	// Message explains what the resize is waiting for, if anything.
+	Message string `json:"message,omitempty"`
+}
+
+// VitessTabletDataVolumeMigrationPhase is a step in moving a tablet's data
+// volume to a different StorageClass.
+type VitessTabletDataVolumeMigrationPhase string
+
+const (
+	// DataVolumeMigrationWaitingPhase means the migration hasn't started,
+	// because it's waiting for a maintenance window or a backup location.
+	DataVolumeMigrationWaitingPhase VitessTabletDataVolumeMigrationPhase = "Waiting"
+	// DataVolumeMigrationReplacingPhase means the tablet will be drained, and
+	// then its volume and Pod deleted so they're recreated on the new
+	// StorageClass and restored from backup, as soon as it's safe. Only one
+	// tablet in a shard is replaced at a time, and not until the extra tablet
+	// the pool gets during the migration is Ready.
+	DataVolumeMigrationReplacingPhase VitessTabletDataVolumeMigrationPhase = "Replacing"
+)
+
+// VitessTabletDataVolumeMigrationStatus reports the progress of moving a
+// tablet's data volume to a different StorageClass.
+type VitessTabletDataVolumeMigrationStatus struct {
+	// Phase is the step the migration is at.
+	Phase VitessTabletDataVolumeMigrationPhase `json:"phase,omitempty"`
+	// CurrentStorageClass is the StorageClass the volume is on now. It's
+	// empty if the volume has no StorageClass.
+	CurrentStorageClass string `json:"currentStorageClass,omitempty"`
+	// DesiredStorageClass is the StorageClass set in the pool's
+	// dataVolumeClaimTemplate.
+	DesiredStorageClass string `json:"desiredStorageClass,omitempty"`
+	// Message explains what the migration is waiting for, if anything.
+	Message string `json:"message,omitempty"`
 }
 
 // NewVitessTabletStatus creates a new status object with default values.
@@ -508,6 +2061,19 @@ func NewVitessTabletStatus(poolType VitessTabletPoolType, index int32) VitessTab
 	}
 }
 
+// ShardBackupRequestStatus reports the progress of an on-demand backup of a
+// shard, which takes a new backup in each of the shard's backup locations.
+type ShardBackupRequestStatus struct {
+	// ID is the value of the annotation that requested the backup.
+	ID string `json:"id"`
+	// Phase is "Succeeded" once a new backup has been taken in every backup
+	// location, or "Failed" if any of them failed. Until then, it's "Running"
+	// if any vtbackup Pods are running, or "Pending" otherwise. A shard that
+	// has no complete backup yet waits for its initial backup before it can
+	// take a requested one.
+	Phase corev1.PodPhase `json:"phase,omitempty"`
+}
+
 // ShardBackupLocationStatus reports status for the backups of a given shard in
 // a given backup location.
 type ShardBackupLocationStatus struct {
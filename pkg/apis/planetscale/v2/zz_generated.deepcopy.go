@@ -6,8 +6,11 @@
 package v2
 
 import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -57,6 +60,36 @@ func (in *ClusterBackupSpec) DeepCopyInto(out *ClusterBackupSpec) {
 		*out = new(VitessBackupSubcontrollerSpec)
 		**out = **in
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(VitessBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(VitessBackupRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VitessBackupPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(VitessBackupVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceTabletPolicy != nil {
+		in, out := &in.SourceTabletPolicy, &out.SourceTabletPolicy
+		*out = new(VitessBackupSourceTabletPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(VitessBackupReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackupSpec.
@@ -75,7 +108,7 @@ func (in *EtcdLockserver) DeepCopyInto(out *EtcdLockserver) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserver.
@@ -96,6 +129,46 @@ func (in *EtcdLockserver) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdLockserverBackup) DeepCopyInto(out *EtcdLockserverBackup) {
+	*out = *in
+	in.Location.DeepCopyInto(&out.Location)
+	if in.SnapshotsToKeep != nil {
+		in, out := &in.SnapshotsToKeep, &out.SnapshotsToKeep
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverBackup.
+func (in *EtcdLockserverBackup) DeepCopy() *EtcdLockserverBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdLockserverBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdLockserverBackupStatus) DeepCopyInto(out *EtcdLockserverBackupStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverBackupStatus.
+func (in *EtcdLockserverBackupStatus) DeepCopy() *EtcdLockserverBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdLockserverBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdLockserverList) DeepCopyInto(out *EtcdLockserverList) {
 	*out = *in
@@ -128,6 +201,22 @@ func (in *EtcdLockserverList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdLockserverRestore) DeepCopyInto(out *EtcdLockserverRestore) {
+	*out = *in
+	in.Location.DeepCopyInto(&out.Location)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverRestore.
+func (in *EtcdLockserverRestore) DeepCopy() *EtcdLockserverRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdLockserverRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdLockserverSpec) DeepCopyInto(out *EtcdLockserverSpec) {
 	*out = *in
@@ -147,6 +236,11 @@ func (in *EtcdLockserverSpec) DeepCopy() *EtcdLockserverSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdLockserverStatus) DeepCopyInto(out *EtcdLockserverStatus) {
 	*out = *in
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(EtcdLockserverBackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverStatus.
@@ -272,6 +366,16 @@ func (in *EtcdLockserverTemplate) DeepCopyInto(out *EtcdLockserverTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(EtcdLockserverBackup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(EtcdLockserverRestore)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdLockserverTemplate.
@@ -293,6 +397,16 @@ func (in *ExternalDatastore) DeepCopyInto(out *ExternalDatastore) {
 		*out = new(SecretSource)
 		**out = **in
 	}
+	if in.DemotePrimaryToSpare != nil {
+		in, out := &in.DemotePrimaryToSpare, &out.DemotePrimaryToSpare
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(ExternalFailoverSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDatastore.
@@ -305,6 +419,56 @@ func (in *ExternalDatastore) DeepCopy() *ExternalDatastore {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalFailoverSpec) DeepCopyInto(out *ExternalFailoverSpec) {
+	*out = *in
+	if in.PrimaryHostFrom != nil {
+		in, out := &in.PrimaryHostFrom, &out.PrimaryHostFrom
+		*out = new(ExternalHostSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalFailoverSpec.
+func (in *ExternalFailoverSpec) DeepCopy() *ExternalFailoverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalFailoverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalHostSource) DeepCopyInto(out *ExternalHostSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalHostSource.
+func (in *ExternalHostSource) DeepCopy() *ExternalHostSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalHostSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalVitessClusterUpdateStrategyOptions) DeepCopyInto(out *ExternalVitessClusterUpdateStrategyOptions) {
 	*out = *in
@@ -345,6 +509,57 @@ func (in *GCSBackupLocation) DeepCopy() *GCSBackupLocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayAPIParentRef) DeepCopyInto(out *GatewayAPIParentRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPIParentRef.
+func (in *GatewayAPIParentRef) DeepCopy() *GatewayAPIParentRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayAPIParentRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockserverMigration) DeepCopyInto(out *LockserverMigration) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockserverMigration.
+func (in *LockserverMigration) DeepCopy() *LockserverMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(LockserverMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockserverMigrationStatus) DeepCopyInto(out *LockserverMigrationStatus) {
+	*out = *in
+	out.Target = in.Target
+	if in.LastCopyTime != nil {
+		in, out := &in.LastCopyTime, &out.LastCopyTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockserverMigrationStatus.
+func (in *LockserverMigrationStatus) DeepCopy() *LockserverMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LockserverMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LockserverSpec) DeepCopyInto(out *LockserverSpec) {
 	*out = *in
@@ -358,6 +573,11 @@ func (in *LockserverSpec) DeepCopyInto(out *LockserverSpec) {
 		*out = new(EtcdLockserverTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(LockserverMigration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockserverSpec.
@@ -376,7 +596,12 @@ func (in *LockserverStatus) DeepCopyInto(out *LockserverStatus) {
 	if in.Etcd != nil {
 		in, out := &in.Etcd, &out.Etcd
 		*out = new(EtcdLockserverStatus)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(LockserverMigrationStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -391,794 +616,750 @@ func (in *LockserverStatus) DeepCopy() *LockserverStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MysqldExporterSpec) DeepCopyInto(out *MysqldExporterSpec) {
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldExporterSpec.
-func (in *MysqldExporterSpec) DeepCopy() *MysqldExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(MysqldExporterSpec)
+	out := new(MaintenanceWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MysqldImage) DeepCopyInto(out *MysqldImage) {
+func (in *MysqlGrant) DeepCopyInto(out *MysqlGrant) {
 	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldImage.
-func (in *MysqldImage) DeepCopy() *MysqldImage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqlGrant.
+func (in *MysqlGrant) DeepCopy() *MysqlGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(MysqldImage)
+	out := new(MysqlGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MysqldSpec) DeepCopyInto(out *MysqldSpec) {
+func (in *MysqlUser) DeepCopyInto(out *MysqlUser) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]MysqlGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldSpec.
-func (in *MysqldSpec) DeepCopy() *MysqldSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqlUser.
+func (in *MysqlUser) DeepCopy() *MysqlUser {
 	if in == nil {
 		return nil
 	}
-	out := new(MysqldSpec)
+	out := new(MysqlUser)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OrphanStatus) DeepCopyInto(out *OrphanStatus) {
+func (in *MysqldAuditLog) DeepCopyInto(out *MysqldAuditLog) {
 	*out = *in
+	if in.RotateOnSize != nil {
+		in, out := &in.RotateOnSize, &out.RotateOnSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Rotations != nil {
+		in, out := &in.Rotations, &out.Rotations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(MysqldAuditLogFilter)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanStatus.
-func (in *OrphanStatus) DeepCopy() *OrphanStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldAuditLog.
+func (in *MysqldAuditLog) DeepCopy() *MysqldAuditLog {
 	if in == nil {
 		return nil
 	}
-	out := new(OrphanStatus)
+	out := new(MysqldAuditLog)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReshardingStatus) DeepCopyInto(out *ReshardingStatus) {
+func (in *MysqldAuditLogFilter) DeepCopyInto(out *MysqldAuditLogFilter) {
 	*out = *in
-	if in.SourceShards != nil {
-		in, out := &in.SourceShards, &out.SourceShards
+	if in.IncludeAccounts != nil {
+		in, out := &in.IncludeAccounts, &out.IncludeAccounts
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.TargetShards != nil {
-		in, out := &in.TargetShards, &out.TargetShards
+	if in.ExcludeAccounts != nil {
+		in, out := &in.ExcludeAccounts, &out.ExcludeAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeCommands != nil {
+		in, out := &in.IncludeCommands, &out.IncludeCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeCommands != nil {
+		in, out := &in.ExcludeCommands, &out.ExcludeCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeDatabases != nil {
+		in, out := &in.IncludeDatabases, &out.IncludeDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeDatabases != nil {
+		in, out := &in.ExcludeDatabases, &out.ExcludeDatabases
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReshardingStatus.
-func (in *ReshardingStatus) DeepCopy() *ReshardingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldAuditLogFilter.
+func (in *MysqldAuditLogFilter) DeepCopy() *MysqldAuditLogFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(ReshardingStatus)
+	out := new(MysqldAuditLogFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3BackupLocation) DeepCopyInto(out *S3BackupLocation) {
+func (in *MysqldExporterServiceMonitor) DeepCopyInto(out *MysqldExporterServiceMonitor) {
 	*out = *in
-	if in.AuthSecret != nil {
-		in, out := &in.AuthSecret, &out.AuthSecret
-		*out = new(SecretSource)
-		**out = **in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupLocation.
-func (in *S3BackupLocation) DeepCopy() *S3BackupLocation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldExporterServiceMonitor.
+func (in *MysqldExporterServiceMonitor) DeepCopy() *MysqldExporterServiceMonitor {
 	if in == nil {
 		return nil
 	}
-	out := new(S3BackupLocation)
+	out := new(MysqldExporterServiceMonitor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretSource) DeepCopyInto(out *SecretSource) {
+func (in *MysqldExporterSpec) DeepCopyInto(out *MysqldExporterSpec) {
 	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(MysqldExporterServiceMonitor)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSource.
-func (in *SecretSource) DeepCopy() *SecretSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldExporterSpec.
+func (in *MysqldExporterSpec) DeepCopy() *MysqldExporterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretSource)
+	out := new(MysqldExporterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceOverrides) DeepCopyInto(out *ServiceOverrides) {
+func (in *MysqldImage) DeepCopyInto(out *MysqldImage) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverrides.
-func (in *ServiceOverrides) DeepCopy() *ServiceOverrides {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldImage.
+func (in *MysqldImage) DeepCopy() *MysqldImage {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceOverrides)
+	out := new(MysqldImage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShardBackupLocationStatus) DeepCopyInto(out *ShardBackupLocationStatus) {
+func (in *MysqldQueryLogFluentBit) DeepCopyInto(out *MysqldQueryLogFluentBit) {
 	*out = *in
-	if in.LatestCompleteBackupTime != nil {
-		in, out := &in.LatestCompleteBackupTime, &out.LatestCompleteBackupTime
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardBackupLocationStatus.
-func (in *ShardBackupLocationStatus) DeepCopy() *ShardBackupLocationStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldQueryLogFluentBit.
+func (in *MysqldQueryLogFluentBit) DeepCopy() *MysqldQueryLogFluentBit {
 	if in == nil {
 		return nil
 	}
-	out := new(ShardBackupLocationStatus)
+	out := new(MysqldQueryLogFluentBit)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TopoReconcileConfig) DeepCopyInto(out *TopoReconcileConfig) {
+func (in *MysqldQueryLogs) DeepCopyInto(out *MysqldQueryLogs) {
 	*out = *in
-	if in.RegisterCellsAliases != nil {
-		in, out := &in.RegisterCellsAliases, &out.RegisterCellsAliases
-		*out = new(bool)
-		**out = **in
+	if in.SlowQueryLog != nil {
+		in, out := &in.SlowQueryLog, &out.SlowQueryLog
+		*out = new(MysqldSlowQueryLog)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.RegisterCells != nil {
-		in, out := &in.RegisterCells, &out.RegisterCells
-		*out = new(bool)
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxFiles != nil {
+		in, out := &in.MaxFiles, &out.MaxFiles
+		*out = new(int32)
 		**out = **in
 	}
-	if in.PruneCells != nil {
-		in, out := &in.PruneCells, &out.PruneCells
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneKeyspaces != nil {
-		in, out := &in.PruneKeyspaces, &out.PruneKeyspaces
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneSrvKeyspaces != nil {
-		in, out := &in.PruneSrvKeyspaces, &out.PruneSrvKeyspaces
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneShards != nil {
-		in, out := &in.PruneShards, &out.PruneShards
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneShardCells != nil {
-		in, out := &in.PruneShardCells, &out.PruneShardCells
-		*out = new(bool)
-		**out = **in
-	}
-	if in.PruneTablets != nil {
-		in, out := &in.PruneTablets, &out.PruneTablets
-		*out = new(bool)
+	if in.FluentBit != nil {
+		in, out := &in.FluentBit, &out.FluentBit
+		*out = new(MysqldQueryLogFluentBit)
 		**out = **in
 	}
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoReconcileConfig.
-func (in *TopoReconcileConfig) DeepCopy() *TopoReconcileConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldQueryLogs.
+func (in *MysqldQueryLogs) DeepCopy() *MysqldQueryLogs {
 	if in == nil {
 		return nil
 	}
-	out := new(TopoReconcileConfig)
+	out := new(MysqldQueryLogs)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackup) DeepCopyInto(out *VitessBackup) {
+func (in *MysqldSlowQueryLog) DeepCopyInto(out *MysqldSlowQueryLog) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.LongQueryTime != nil {
+		in, out := &in.LongQueryTime, &out.LongQueryTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackup.
-func (in *VitessBackup) DeepCopy() *VitessBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldSlowQueryLog.
+func (in *MysqldSlowQueryLog) DeepCopy() *MysqldSlowQueryLog {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackup)
+	out := new(MysqldSlowQueryLog)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackup) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupList) DeepCopyInto(out *VitessBackupList) {
+func (in *MysqldSpec) DeepCopyInto(out *MysqldSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessBackup, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
+	if in.QueryLogs != nil {
+		in, out := &in.QueryLogs, &out.QueryLogs
+		*out = new(MysqldQueryLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuditLog != nil {
+		in, out := &in.AuditLog, &out.AuditLog
+		*out = new(MysqldAuditLog)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupList.
-func (in *VitessBackupList) DeepCopy() *VitessBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldSpec.
+func (in *MysqldSpec) DeepCopy() *MysqldSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupList)
+	out := new(MysqldSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanStatus) DeepCopyInto(out *OrphanStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanStatus.
+func (in *OrphanStatus) DeepCopy() *OrphanStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(OrphanStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupLocation) DeepCopyInto(out *VitessBackupLocation) {
+func (in *ReparentSettings) DeepCopyInto(out *ReparentSettings) {
 	*out = *in
-	if in.GCS != nil {
-		in, out := &in.GCS, &out.GCS
-		*out = new(GCSBackupLocation)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.S3 != nil {
-		in, out := &in.S3, &out.S3
-		*out = new(S3BackupLocation)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Azblob != nil {
-		in, out := &in.Azblob, &out.Azblob
-		*out = new(AzblobBackupLocation)
+	if in.PlannedReparentTimeout != nil {
+		in, out := &in.PlannedReparentTimeout, &out.PlannedReparentTimeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.Ceph != nil {
-		in, out := &in.Ceph, &out.Ceph
-		*out = new(CephBackupLocation)
+	if in.ReconcileDrainTimeout != nil {
+		in, out := &in.ReconcileDrainTimeout, &out.ReconcileDrainTimeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.Volume != nil {
-		in, out := &in.Volume, &out.Volume
-		*out = new(v1.VolumeSource)
-		(*in).DeepCopyInto(*out)
+	if in.TolerableReplicationLag != nil {
+		in, out := &in.TolerableReplicationLag, &out.TolerableReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
+	if in.CellReplicationLagThresholds != nil {
+		in, out := &in.CellReplicationLagThresholds, &out.CellReplicationLagThresholds
+		*out = make(map[string]metav1.Duration, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.AllowEmergencyFailover != nil {
+		in, out := &in.AllowEmergencyFailover, &out.AllowEmergencyFailover
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EmergencyFailoverGracePeriod != nil {
+		in, out := &in.EmergencyFailoverGracePeriod, &out.EmergencyFailoverGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainAbortGracePeriod != nil {
+		in, out := &in.DrainAbortGracePeriod, &out.DrainAbortGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupLocation.
-func (in *VitessBackupLocation) DeepCopy() *VitessBackupLocation {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReparentSettings.
+func (in *ReparentSettings) DeepCopy() *ReparentSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupLocation)
+	out := new(ReparentSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupSpec) DeepCopyInto(out *VitessBackupSpec) {
+func (in *ReshardingStatus) DeepCopyInto(out *ReshardingStatus) {
 	*out = *in
+	if in.SourceShards != nil {
+		in, out := &in.SourceShards, &out.SourceShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetShards != nil {
+		in, out := &in.TargetShards, &out.TargetShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSpec.
-func (in *VitessBackupSpec) DeepCopy() *VitessBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReshardingStatus.
+func (in *ReshardingStatus) DeepCopy() *ReshardingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupSpec)
+	out := new(ReshardingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStatus) DeepCopyInto(out *VitessBackupStatus) {
+func (in *S3BackupLocation) DeepCopyInto(out *S3BackupLocation) {
 	*out = *in
-	in.StartTime.DeepCopyInto(&out.StartTime)
-	if in.FinishedTime != nil {
-		in, out := &in.FinishedTime, &out.FinishedTime
-		*out = (*in).DeepCopy()
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.CASecret != nil {
+		in, out := &in.CASecret, &out.CASecret
+		*out = new(SecretSource)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStatus.
-func (in *VitessBackupStatus) DeepCopy() *VitessBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupLocation.
+func (in *S3BackupLocation) DeepCopy() *S3BackupLocation {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStatus)
+	out := new(S3BackupLocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorage) DeepCopyInto(out *VitessBackupStorage) {
+func (in *SecretSource) DeepCopyInto(out *SecretSource) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorage.
-func (in *VitessBackupStorage) DeepCopy() *VitessBackupStorage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretSource.
+func (in *SecretSource) DeepCopy() *SecretSource {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorage)
+	out := new(SecretSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupStorage) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageList) DeepCopyInto(out *VitessBackupStorageList) {
+func (in *ServiceOverrides) DeepCopyInto(out *ServiceOverrides) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessBackupStorage, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageList.
-func (in *VitessBackupStorageList) DeepCopy() *VitessBackupStorageList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverrides.
+func (in *ServiceOverrides) DeepCopy() *ServiceOverrides {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageList)
+	out := new(ServiceOverrides)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessBackupStorageList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageSpec) DeepCopyInto(out *VitessBackupStorageSpec) {
+func (in *ShardBackupLocationStatus) DeepCopyInto(out *ShardBackupLocationStatus) {
 	*out = *in
-	in.Location.DeepCopyInto(&out.Location)
-	if in.Subcontroller != nil {
-		in, out := &in.Subcontroller, &out.Subcontroller
-		*out = new(VitessBackupSubcontrollerSpec)
-		**out = **in
+	if in.LatestCompleteBackupTime != nil {
+		in, out := &in.LatestCompleteBackupTime, &out.LatestCompleteBackupTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageSpec.
-func (in *VitessBackupStorageSpec) DeepCopy() *VitessBackupStorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardBackupLocationStatus.
+func (in *ShardBackupLocationStatus) DeepCopy() *ShardBackupLocationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageSpec)
+	out := new(ShardBackupLocationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupStorageStatus) DeepCopyInto(out *VitessBackupStorageStatus) {
+func (in *ShardBackupRequestStatus) DeepCopyInto(out *ShardBackupRequestStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageStatus.
-func (in *VitessBackupStorageStatus) DeepCopy() *VitessBackupStorageStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardBackupRequestStatus.
+func (in *ShardBackupRequestStatus) DeepCopy() *ShardBackupRequestStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessBackupStorageStatus)
+	out := new(ShardBackupRequestStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessBackupSubcontrollerSpec) DeepCopyInto(out *VitessBackupSubcontrollerSpec) {
+func (in *TopoReconcileConfig) DeepCopyInto(out *TopoReconcileConfig) {
 	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSubcontrollerSpec.
-func (in *VitessBackupSubcontrollerSpec) DeepCopy() *VitessBackupSubcontrollerSpec {
-	if in == nil {
-		return nil
+	if in.RegisterCellsAliases != nil {
+		in, out := &in.RegisterCellsAliases, &out.RegisterCellsAliases
+		*out = new(bool)
+		**out = **in
 	}
-	out := new(VitessBackupSubcontrollerSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCell) DeepCopyInto(out *VitessCell) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCell.
-func (in *VitessCell) DeepCopy() *VitessCell {
-	if in == nil {
-		return nil
-	}
-	out := new(VitessCell)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCell) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellGatewaySpec) DeepCopyInto(out *VitessCellGatewaySpec) {
-	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+	if in.RegisterCells != nil {
+		in, out := &in.RegisterCells, &out.RegisterCells
+		*out = new(bool)
 		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.Authentication.DeepCopyInto(&out.Authentication)
-	if in.SecureTransport != nil {
-		in, out := &in.SecureTransport, &out.SecureTransport
-		*out = new(VitessGatewaySecureTransport)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExtraFlags != nil {
-		in, out := &in.ExtraFlags, &out.ExtraFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PruneCells != nil {
+		in, out := &in.PruneCells, &out.PruneCells
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.PruneKeyspaces != nil {
+		in, out := &in.PruneKeyspaces, &out.PruneKeyspaces
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PruneSrvKeyspaces != nil {
+		in, out := &in.PruneSrvKeyspaces, &out.PruneSrvKeyspaces
+		*out = new(bool)
+		**out = **in
 	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.PruneShards != nil {
+		in, out := &in.PruneShards, &out.PruneShards
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
+	if in.PruneShardCells != nil {
+		in, out := &in.PruneShardCells, &out.PruneShardCells
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PruneTablets != nil {
+		in, out := &in.PruneTablets, &out.PruneTablets
+		*out = new(bool)
+		**out = **in
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RepairServingGraph != nil {
+		in, out := &in.RepairServingGraph, &out.RepairServingGraph
+		*out = new(bool)
+		**out = **in
 	}
-	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
+	if in.ConsistencyCheckInterval != nil {
+		in, out := &in.ConsistencyCheckInterval, &out.ConsistencyCheckInterval
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewaySpec.
-func (in *VitessCellGatewaySpec) DeepCopy() *VitessCellGatewaySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoReconcileConfig.
+func (in *TopoReconcileConfig) DeepCopy() *TopoReconcileConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellGatewaySpec)
+	out := new(TopoReconcileConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellGatewayStatus) DeepCopyInto(out *VitessCellGatewayStatus) {
+func (in *TopologyConsistencyStatus) DeepCopyInto(out *TopologyConsistencyStatus) {
 	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]TopologyDrift, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewayStatus.
-func (in *VitessCellGatewayStatus) DeepCopy() *VitessCellGatewayStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyConsistencyStatus.
+func (in *TopologyConsistencyStatus) DeepCopy() *TopologyConsistencyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellGatewayStatus)
+	out := new(TopologyConsistencyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellImages) DeepCopyInto(out *VitessCellImages) {
+func (in *TopologyDrift) DeepCopyInto(out *TopologyDrift) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellImages.
-func (in *VitessCellImages) DeepCopy() *VitessCellImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyDrift.
+func (in *TopologyDrift) DeepCopy() *TopologyDrift {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellImages)
+	out := new(TopologyDrift)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellKeyspaceStatus) DeepCopyInto(out *VitessCellKeyspaceStatus) {
+func (in *VitessActiveSchemaMigration) DeepCopyInto(out *VitessActiveSchemaMigration) {
 	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessSchemaMigrationShardStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellKeyspaceStatus.
-func (in *VitessCellKeyspaceStatus) DeepCopy() *VitessCellKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessActiveSchemaMigration.
+func (in *VitessActiveSchemaMigration) DeepCopy() *VitessActiveSchemaMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellKeyspaceStatus)
+	out := new(VitessActiveSchemaMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellList) DeepCopyInto(out *VitessCellList) {
+func (in *VitessAutoReshardPolicy) DeepCopyInto(out *VitessAutoReshardPolicy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessCell, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxShardDataSize != nil {
+		in, out := &in.MaxShardDataSize, &out.MaxShardDataSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxPrimaryQPS != nil {
+		in, out := &in.MaxPrimaryQPS, &out.MaxPrimaryQPS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SustainedFor != nil {
+		in, out := &in.SustainedFor, &out.SustainedFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxShards != nil {
+		in, out := &in.MaxShards, &out.MaxShards
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellList.
-func (in *VitessCellList) DeepCopy() *VitessCellList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAutoReshardPolicy.
+func (in *VitessAutoReshardPolicy) DeepCopy() *VitessAutoReshardPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellList)
+	out := new(VitessAutoReshardPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCellList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellSpec) DeepCopyInto(out *VitessCellSpec) {
+func (in *VitessAutoReshardProposal) DeepCopyInto(out *VitessAutoReshardProposal) {
 	*out = *in
-	in.VitessCellTemplate.DeepCopyInto(&out.VitessCellTemplate)
-	out.GlobalLockserver = in.GlobalLockserver
-	if in.AllCells != nil {
-		in, out := &in.AllCells, &out.AllCells
+	if in.TargetShards != nil {
+		in, out := &in.TargetShards, &out.TargetShards
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.Images = in.Images
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellSpec.
-func (in *VitessCellSpec) DeepCopy() *VitessCellSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAutoReshardProposal.
+func (in *VitessAutoReshardProposal) DeepCopy() *VitessAutoReshardProposal {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellSpec)
+	out := new(VitessAutoReshardProposal)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellStatus) DeepCopyInto(out *VitessCellStatus) {
+func (in *VitessAutoReshardShardStatus) DeepCopyInto(out *VitessAutoReshardShardStatus) {
 	*out = *in
-	in.Lockserver.DeepCopyInto(&out.Lockserver)
-	out.Gateway = in.Gateway
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make(map[string]VitessCellKeyspaceStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.OverThresholdSince != nil {
+		in, out := &in.OverThresholdSince, &out.OverThresholdSince
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellStatus.
-func (in *VitessCellStatus) DeepCopy() *VitessCellStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAutoReshardShardStatus.
+func (in *VitessAutoReshardShardStatus) DeepCopy() *VitessAutoReshardShardStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellStatus)
+	out := new(VitessAutoReshardShardStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCellTemplate) DeepCopyInto(out *VitessCellTemplate) {
+func (in *VitessAutoReshardStatus) DeepCopyInto(out *VitessAutoReshardStatus) {
 	*out = *in
-	in.Lockserver.DeepCopyInto(&out.Lockserver)
-	in.Gateway.DeepCopyInto(&out.Gateway)
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessAutoReshardShardStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastMeasuredTime != nil {
+		in, out := &in.LastMeasuredTime, &out.LastMeasuredTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Proposal != nil {
+		in, out := &in.Proposal, &out.Proposal
+		*out = new(VitessAutoReshardProposal)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellTemplate.
-func (in *VitessCellTemplate) DeepCopy() *VitessCellTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessAutoReshardStatus.
+func (in *VitessAutoReshardStatus) DeepCopy() *VitessAutoReshardStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCellTemplate)
+	out := new(VitessAutoReshardStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessCluster) DeepCopyInto(out *VitessCluster) {
+func (in *VitessBackup) DeepCopyInto(out *VitessBackup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCluster.
-func (in *VitessCluster) DeepCopy() *VitessCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackup.
+func (in *VitessBackup) DeepCopy() *VitessBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessCluster)
+	out := new(VitessBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessCluster) DeepCopyObject() runtime.Object {
+func (in *VitessBackup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1186,66 +1367,50 @@ func (in *VitessCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterCellStatus) DeepCopyInto(out *VitessClusterCellStatus) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterCellStatus.
-func (in *VitessClusterCellStatus) DeepCopy() *VitessClusterCellStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(VitessClusterCellStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterKeyspaceStatus) DeepCopyInto(out *VitessClusterKeyspaceStatus) {
+func (in *VitessBackupCondition) DeepCopyInto(out *VitessBackupCondition) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterKeyspaceStatus.
-func (in *VitessClusterKeyspaceStatus) DeepCopy() *VitessClusterKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupCondition.
+func (in *VitessBackupCondition) DeepCopy() *VitessBackupCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterKeyspaceStatus)
+	out := new(VitessBackupCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterList) DeepCopyInto(out *VitessClusterList) {
+func (in *VitessBackupList) DeepCopyInto(out *VitessBackupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VitessCluster, len(*in))
+		*out = make([]VitessBackup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterList.
-func (in *VitessClusterList) DeepCopy() *VitessClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupList.
+func (in *VitessBackupList) DeepCopy() *VitessBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterList)
+	out := new(VitessBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessClusterList) DeepCopyObject() runtime.Object {
+func (in *VitessBackupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1253,411 +1418,402 @@ func (in *VitessClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterSpec) DeepCopyInto(out *VitessClusterSpec) {
+func (in *VitessBackupLocation) DeepCopyInto(out *VitessBackupLocation) {
 	*out = *in
-	in.Images.DeepCopyInto(&out.Images)
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.Backup != nil {
-		in, out := &in.Backup, &out.Backup
-		*out = new(ClusterBackupSpec)
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(GCSBackupLocation)
 		(*in).DeepCopyInto(*out)
 	}
-	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
-	if in.VitessDashboard != nil {
-		in, out := &in.VitessDashboard, &out.VitessDashboard
-		*out = new(VitessDashboardSpec)
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupLocation)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.VtAdmin != nil {
-		in, out := &in.VtAdmin, &out.VtAdmin
-		*out = new(VtAdminSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Azblob != nil {
+		in, out := &in.Azblob, &out.Azblob
+		*out = new(AzblobBackupLocation)
+		**out = **in
 	}
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]VitessCellTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Ceph != nil {
+		in, out := &in.Ceph, &out.Ceph
+		*out = new(CephBackupLocation)
+		**out = **in
 	}
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make([]VitessKeyspaceTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(v1.VolumeSource)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.GatewayService != nil {
-		in, out := &in.GatewayService, &out.GatewayService
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TabletService != nil {
-		in, out := &in.TabletService, &out.TabletService
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSpec.
-func (in *VitessClusterSpec) DeepCopy() *VitessClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupLocation.
+func (in *VitessBackupLocation) DeepCopy() *VitessBackupLocation {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterSpec)
+	out := new(VitessBackupLocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterStatus) DeepCopyInto(out *VitessClusterStatus) {
+func (in *VitessBackupPodSpec) DeepCopyInto(out *VitessBackupPodSpec) {
 	*out = *in
-	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
-	out.VitessDashboard = in.VitessDashboard
-	out.Vtadmin = in.Vtadmin
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make(map[string]VitessClusterCellStatus, len(*in))
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Keyspaces != nil {
-		in, out := &in.Keyspaces, &out.Keyspaces
-		*out = make(map[string]VitessClusterKeyspaceStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.OrphanedCells != nil {
-		in, out := &in.OrphanedCells, &out.OrphanedCells
-		*out = make(map[string]OrphanStatus, len(*in))
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.OrphanedKeyspaces != nil {
-		in, out := &in.OrphanedKeyspaces, &out.OrphanedKeyspaces
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupPodSpec.
+func (in *VitessBackupPodSpec) DeepCopy() *VitessBackupPodSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupPodSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupReplicationSpec) DeepCopyInto(out *VitessBackupReplicationSpec) {
+	*out = *in
+	if in.DestinationLocationNames != nil {
+		in, out := &in.DestinationLocationNames, &out.DestinationLocationNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterStatus.
-func (in *VitessClusterStatus) DeepCopy() *VitessClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupReplicationSpec.
+func (in *VitessBackupReplicationSpec) DeepCopy() *VitessBackupReplicationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterStatus)
+	out := new(VitessBackupReplicationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessClusterUpdateStrategy) DeepCopyInto(out *VitessClusterUpdateStrategy) {
+func (in *VitessBackupRetentionSpec) DeepCopyInto(out *VitessBackupRetentionSpec) {
 	*out = *in
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(VitessClusterUpdateStrategyType)
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
 		**out = **in
 	}
-	if in.External != nil {
-		in, out := &in.External, &out.External
-		*out = new(ExternalVitessClusterUpdateStrategyOptions)
-		(*in).DeepCopyInto(*out)
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpdateStrategy.
-func (in *VitessClusterUpdateStrategy) DeepCopy() *VitessClusterUpdateStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupRetentionSpec.
+func (in *VitessBackupRetentionSpec) DeepCopy() *VitessBackupRetentionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessClusterUpdateStrategy)
+	out := new(VitessBackupRetentionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessDashboardSpec) DeepCopyInto(out *VitessDashboardSpec) {
+func (in *VitessBackupScheduleSpec) DeepCopyInto(out *VitessBackupScheduleSpec) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
+	if in.Jitter != nil {
+		in, out := &in.Jitter, &out.Jitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxConcurrentShards != nil {
+		in, out := &in.MaxConcurrentShards, &out.MaxConcurrentShards
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupScheduleSpec.
+func (in *VitessBackupScheduleSpec) DeepCopy() *VitessBackupScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupSourceTabletPolicy) DeepCopyInto(out *VitessBackupSourceTabletPolicy) {
+	*out = *in
+	if in.PreferredCells != nil {
+		in, out := &in.PreferredCells, &out.PreferredCells
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSourceTabletPolicy.
+func (in *VitessBackupSourceTabletPolicy) DeepCopy() *VitessBackupSourceTabletPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupSourceTabletPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupSpec) DeepCopyInto(out *VitessBackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSpec.
+func (in *VitessBackupSpec) DeepCopy() *VitessBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessBackupStatus) DeepCopyInto(out *VitessBackupStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.FinishedTime != nil {
+		in, out := &in.FinishedTime, &out.FinishedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.ExtraFlags != nil {
-		in, out := &in.ExtraFlags, &out.ExtraFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.LastVerificationTime != nil {
+		in, out := &in.LastVerificationTime, &out.LastVerificationTime
+		*out = (*in).DeepCopy()
 	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessBackupCondition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardSpec.
-func (in *VitessDashboardSpec) DeepCopy() *VitessDashboardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStatus.
+func (in *VitessBackupStatus) DeepCopy() *VitessBackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessDashboardSpec)
+	out := new(VitessBackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessDashboardStatus) DeepCopyInto(out *VitessDashboardStatus) {
+func (in *VitessBackupStorage) DeepCopyInto(out *VitessBackupStorage) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardStatus.
-func (in *VitessDashboardStatus) DeepCopy() *VitessDashboardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorage.
+func (in *VitessBackupStorage) DeepCopy() *VitessBackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessDashboardStatus)
+	out := new(VitessBackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayAuthentication) DeepCopyInto(out *VitessGatewayAuthentication) {
-	*out = *in
-	if in.Static != nil {
-		in, out := &in.Static, &out.Static
-		*out = new(VitessGatewayStaticAuthentication)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAuthentication.
-func (in *VitessGatewayAuthentication) DeepCopy() *VitessGatewayAuthentication {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessBackupStorage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(VitessGatewayAuthentication)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewaySecureTransport) DeepCopyInto(out *VitessGatewaySecureTransport) {
+func (in *VitessBackupStorageList) DeepCopyInto(out *VitessBackupStorageList) {
 	*out = *in
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(VitessGatewayTLSSecureTransport)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessBackupStorage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewaySecureTransport.
-func (in *VitessGatewaySecureTransport) DeepCopy() *VitessGatewaySecureTransport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageList.
+func (in *VitessBackupStorageList) DeepCopy() *VitessBackupStorageList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewaySecureTransport)
+	out := new(VitessBackupStorageList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessBackupStorageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayStaticAuthentication) DeepCopyInto(out *VitessGatewayStaticAuthentication) {
+func (in *VitessBackupStorageSpec) DeepCopyInto(out *VitessBackupStorageSpec) {
 	*out = *in
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(SecretSource)
+	in.Location.DeepCopyInto(&out.Location)
+	if in.Subcontroller != nil {
+		in, out := &in.Subcontroller, &out.Subcontroller
+		*out = new(VitessBackupSubcontrollerSpec)
 		**out = **in
 	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(VitessBackupRetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExcludedKeyspaces != nil {
+		in, out := &in.ExcludedKeyspaces, &out.ExcludedKeyspaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayStaticAuthentication.
-func (in *VitessGatewayStaticAuthentication) DeepCopy() *VitessGatewayStaticAuthentication {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageSpec.
+func (in *VitessBackupStorageSpec) DeepCopy() *VitessBackupStorageSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewayStaticAuthentication)
+	out := new(VitessBackupStorageSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessGatewayTLSSecureTransport) DeepCopyInto(out *VitessGatewayTLSSecureTransport) {
+func (in *VitessBackupStorageStatus) DeepCopyInto(out *VitessBackupStorageStatus) {
 	*out = *in
-	if in.ClientCACertSecret != nil {
-		in, out := &in.ClientCACertSecret, &out.ClientCACertSecret
-		*out = new(SecretSource)
-		**out = **in
-	}
-	if in.CertSecret != nil {
-		in, out := &in.CertSecret, &out.CertSecret
-		*out = new(SecretSource)
-		**out = **in
-	}
-	if in.KeySecret != nil {
-		in, out := &in.KeySecret, &out.KeySecret
-		*out = new(SecretSource)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayTLSSecureTransport.
-func (in *VitessGatewayTLSSecureTransport) DeepCopy() *VitessGatewayTLSSecureTransport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupStorageStatus.
+func (in *VitessBackupStorageStatus) DeepCopy() *VitessBackupStorageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessGatewayTLSSecureTransport)
+	out := new(VitessBackupStorageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessImagePullPolicies) DeepCopyInto(out *VitessImagePullPolicies) {
+func (in *VitessBackupSubcontrollerSpec) DeepCopyInto(out *VitessBackupSubcontrollerSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImagePullPolicies.
-func (in *VitessImagePullPolicies) DeepCopy() *VitessImagePullPolicies {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupSubcontrollerSpec.
+func (in *VitessBackupSubcontrollerSpec) DeepCopy() *VitessBackupSubcontrollerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessImagePullPolicies)
+	out := new(VitessBackupSubcontrollerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessImages) DeepCopyInto(out *VitessImages) {
+func (in *VitessBackupVerificationSpec) DeepCopyInto(out *VitessBackupVerificationSpec) {
 	*out = *in
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldImage)
-		**out = **in
+	if in.ChecksumTables != nil {
+		in, out := &in.ChecksumTables, &out.ChecksumTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImages.
-func (in *VitessImages) DeepCopy() *VitessImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessBackupVerificationSpec.
+func (in *VitessBackupVerificationSpec) DeepCopy() *VitessBackupVerificationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessImages)
+	out := new(VitessBackupVerificationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyRange) DeepCopyInto(out *VitessKeyRange) {
+func (in *VitessCanaryUpdateStrategy) DeepCopyInto(out *VitessCanaryUpdateStrategy) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SoakPeriod != nil {
+		in, out := &in.SoakPeriod, &out.SoakPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyRange.
-func (in *VitessKeyRange) DeepCopy() *VitessKeyRange {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCanaryUpdateStrategy.
+func (in *VitessCanaryUpdateStrategy) DeepCopy() *VitessCanaryUpdateStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyRange)
+	out := new(VitessCanaryUpdateStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
+func (in *VitessCell) DeepCopyInto(out *VitessCell) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1665,18 +1821,18 @@ func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspace.
-func (in *VitessKeyspace) DeepCopy() *VitessKeyspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCell.
+func (in *VitessCell) DeepCopy() *VitessCell {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspace)
+	out := new(VitessCell)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
+func (in *VitessCell) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1684,125 +1840,250 @@ func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceCondition) DeepCopyInto(out *VitessKeyspaceCondition) {
+func (in *VitessCellGatewaySpec) DeepCopyInto(out *VitessCellGatewaySpec) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCondition.
-func (in *VitessKeyspaceCondition) DeepCopy() *VitessKeyspaceCondition {
-	if in == nil {
-		return nil
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Authentication.DeepCopyInto(&out.Authentication)
+	if in.SecureTransport != nil {
+		in, out := &in.SecureTransport, &out.SecureTransport
+		*out = new(VitessGatewaySecureTransport)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(VitessKeyspaceCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceCustomPartitioning) DeepCopyInto(out *VitessKeyspaceCustomPartitioning) {
-	*out = *in
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = make([]VitessKeyspaceKeyRangeShard, len(*in))
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCustomPartitioning.
-func (in *VitessKeyspaceCustomPartitioning) DeepCopy() *VitessKeyspaceCustomPartitioning {
-	if in == nil {
-		return nil
-	}
-	out := new(VitessKeyspaceCustomPartitioning)
-	in.DeepCopyInto(out)
-	return out
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(VitessGatewayExposure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ZoneSpread != nil {
+		in, out := &in.ZoneSpread, &out.ZoneSpread
+		*out = new(VitessZoneSpread)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Lifecycle.DeepCopyInto(&out.Lifecycle)
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TerminationDrainDuration != nil {
+		in, out := &in.TerminationDrainDuration, &out.TerminationDrainDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Lameduck != nil {
+		in, out := &in.Lameduck, &out.Lameduck
+		*out = new(VitessGatewayLameduck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServingGate != nil {
+		in, out := &in.ServingGate, &out.ServingGate
+		*out = new(VitessGatewayServingGate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(VitessGatewayRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(VitessPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(VitessGatewayAutoscaler)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleProfiles != nil {
+		in, out := &in.ScaleProfiles, &out.ScaleProfiles
+		*out = make([]VitessScaleProfile, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewaySpec.
+func (in *VitessCellGatewaySpec) DeepCopy() *VitessCellGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceEqualPartitioning) DeepCopyInto(out *VitessKeyspaceEqualPartitioning) {
+func (in *VitessCellGatewayStatus) DeepCopyInto(out *VitessCellGatewayStatus) {
 	*out = *in
-	in.ShardTemplate.DeepCopyInto(&out.ShardTemplate)
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(VitessGatewayBlueGreenStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Rollout = in.Rollout
+	if in.StaticAuth != nil {
+		in, out := &in.StaticAuth, &out.StaticAuth
+		*out = new(VitessGatewayStaticAuthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServingGate != nil {
+		in, out := &in.ServingGate, &out.ServingGate
+		*out = new(VitessGatewayServingGateStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceEqualPartitioning.
-func (in *VitessKeyspaceEqualPartitioning) DeepCopy() *VitessKeyspaceEqualPartitioning {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellGatewayStatus.
+func (in *VitessCellGatewayStatus) DeepCopy() *VitessCellGatewayStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceEqualPartitioning)
+	out := new(VitessCellGatewayStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceImages) DeepCopyInto(out *VitessKeyspaceImages) {
+func (in *VitessCellImages) DeepCopyInto(out *VitessCellImages) {
 	*out = *in
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldImage)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceImages.
-func (in *VitessKeyspaceImages) DeepCopy() *VitessKeyspaceImages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellImages.
+func (in *VitessCellImages) DeepCopy() *VitessCellImages {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceImages)
+	out := new(VitessCellImages)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceKeyRangeShard) DeepCopyInto(out *VitessKeyspaceKeyRangeShard) {
+func (in *VitessCellKeyspaceStatus) DeepCopyInto(out *VitessCellKeyspaceStatus) {
 	*out = *in
-	out.KeyRange = in.KeyRange
-	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceKeyRangeShard.
-func (in *VitessKeyspaceKeyRangeShard) DeepCopy() *VitessKeyspaceKeyRangeShard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellKeyspaceStatus.
+func (in *VitessCellKeyspaceStatus) DeepCopy() *VitessCellKeyspaceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceKeyRangeShard)
+	out := new(VitessCellKeyspaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceList) DeepCopyInto(out *VitessKeyspaceList) {
+func (in *VitessCellList) DeepCopyInto(out *VitessCellList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]VitessKeyspace, len(*in))
+		*out = make([]VitessCell, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceList.
-func (in *VitessKeyspaceList) DeepCopy() *VitessKeyspaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellList.
+func (in *VitessCellList) DeepCopy() *VitessCellList {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceList)
+	out := new(VitessCellList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessKeyspaceList) DeepCopyObject() runtime.Object {
+func (in *VitessCellList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1810,96 +2091,42 @@ func (in *VitessKeyspaceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspacePartitioning) DeepCopyInto(out *VitessKeyspacePartitioning) {
-	*out = *in
-	if in.Equal != nil {
-		in, out := &in.Equal, &out.Equal
-		*out = new(VitessKeyspaceEqualPartitioning)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Custom != nil {
-		in, out := &in.Custom, &out.Custom
-		*out = new(VitessKeyspaceCustomPartitioning)
-		(*in).DeepCopyInto(*out)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioning.
-func (in *VitessKeyspacePartitioning) DeepCopy() *VitessKeyspacePartitioning {
-	if in == nil {
-		return nil
-	}
-	out := new(VitessKeyspacePartitioning)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspacePartitioningStatus) DeepCopyInto(out *VitessKeyspacePartitioningStatus) {
+func (in *VitessCellPlacement) DeepCopyInto(out *VitessCellPlacement) {
 	*out = *in
-	if in.ShardNames != nil {
-		in, out := &in.ShardNames, &out.ShardNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(SecretSource)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioningStatus.
-func (in *VitessKeyspacePartitioningStatus) DeepCopy() *VitessKeyspacePartitioningStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellPlacement.
+func (in *VitessCellPlacement) DeepCopy() *VitessCellPlacement {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspacePartitioningStatus)
+	out := new(VitessCellPlacement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceShardStatus) DeepCopyInto(out *VitessKeyspaceShardStatus) {
+func (in *VitessCellSpec) DeepCopyInto(out *VitessCellSpec) {
 	*out = *in
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
+	in.VitessCellTemplate.DeepCopyInto(&out.VitessCellTemplate)
+	out.GlobalLockserver = in.GlobalLockserver
+	if in.AllCells != nil {
+		in, out := &in.AllCells, &out.AllCells
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceShardStatus.
-func (in *VitessKeyspaceShardStatus) DeepCopy() *VitessKeyspaceShardStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(VitessKeyspaceShardStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceSpec) DeepCopyInto(out *VitessKeyspaceSpec) {
-	*out = *in
-	in.VitessKeyspaceTemplate.DeepCopyInto(&out.VitessKeyspaceTemplate)
-	out.GlobalLockserver = in.GlobalLockserver
-	in.Images.DeepCopyInto(&out.Images)
+	out.Images = in.Images
 	out.ImagePullPolicies = in.ImagePullPolicies
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
-	if in.ZoneMap != nil {
-		in, out := &in.ZoneMap, &out.ZoneMap
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.BackupLocations != nil {
-		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]VitessBackupLocation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	if in.ExtraVitessFlags != nil {
 		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
 		*out = make(map[string]string, len(*in))
@@ -1912,38 +2139,1836 @@ func (in *VitessKeyspaceSpec) DeepCopyInto(out *VitessKeyspaceSpec) {
 		*out = new(TopoReconcileConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSpec.
-func (in *VitessKeyspaceSpec) DeepCopy() *VitessKeyspaceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellSpec.
+func (in *VitessCellSpec) DeepCopy() *VitessCellSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceSpec)
+	out := new(VitessCellSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceStatus) DeepCopyInto(out *VitessKeyspaceStatus) {
+func (in *VitessCellStatus) DeepCopyInto(out *VitessCellStatus) {
 	*out = *in
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = make(map[string]VitessKeyspaceShardStatus, len(*in))
+	in.Lockserver.DeepCopyInto(&out.Lockserver)
+	in.Gateway.DeepCopyInto(&out.Gateway)
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make(map[string]VitessCellKeyspaceStatus, len(*in))
 		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Partitionings != nil {
-		in, out := &in.Partitionings, &out.Partitionings
-		*out = make([]VitessKeyspacePartitioningStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellStatus.
+func (in *VitessCellStatus) DeepCopy() *VitessCellStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCellTemplate) DeepCopyInto(out *VitessCellTemplate) {
+	*out = *in
+	in.Lockserver.DeepCopyInto(&out.Lockserver)
+	in.Gateway.DeepCopyInto(&out.Gateway)
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(VitessCellPlacement)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCellTemplate.
+func (in *VitessCellTemplate) DeepCopy() *VitessCellTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCellTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessCluster) DeepCopyInto(out *VitessCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessCluster.
+func (in *VitessCluster) DeepCopy() *VitessCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterCellStatus) DeepCopyInto(out *VitessClusterCellStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterCellStatus.
+func (in *VitessClusterCellStatus) DeepCopy() *VitessClusterCellStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterCellStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterKeyspaceStatus) DeepCopyInto(out *VitessClusterKeyspaceStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterKeyspaceStatus.
+func (in *VitessClusterKeyspaceStatus) DeepCopy() *VitessClusterKeyspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterKeyspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterList) DeepCopyInto(out *VitessClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterList.
+func (in *VitessClusterList) DeepCopy() *VitessClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterMemberStatus) DeepCopyInto(out *VitessClusterMemberStatus) {
+	*out = *in
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterMemberStatus.
+func (in *VitessClusterMemberStatus) DeepCopy() *VitessClusterMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterRevision) DeepCopyInto(out *VitessClusterRevision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	in.Images.DeepCopyInto(&out.Images)
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterRevision.
+func (in *VitessClusterRevision) DeepCopy() *VitessClusterRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterRolloutStatus) DeepCopyInto(out *VitessClusterRolloutStatus) {
+	*out = *in
+	out.Vtctld = in.Vtctld
+	out.Vtgate = in.Vtgate
+	out.Vttablet = in.Vttablet
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make(map[string]VitessRolloutCounts, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterRolloutStatus.
+func (in *VitessClusterRolloutStatus) DeepCopy() *VitessClusterRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterSpec) DeepCopyInto(out *VitessClusterSpec) {
+	*out = *in
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(ClusterBackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(VitessInitialRestoreSpec)
+		**out = **in
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	if in.VitessDashboard != nil {
+		in, out := &in.VitessDashboard, &out.VitessDashboard
+		*out = new(VitessDashboardSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VtAdmin != nil {
+		in, out := &in.VtAdmin, &out.VtAdmin
+		*out = new(VtAdminSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]VitessCellTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make([]VitessKeyspaceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.GatewayService != nil {
+		in, out := &in.GatewayService, &out.GatewayService
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TabletService != nil {
+		in, out := &in.TabletService, &out.TabletService
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreferredPrimaryCells != nil {
+		in, out := &in.PreferredPrimaryCells, &out.PreferredPrimaryCells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReparentSettings != nil {
+		in, out := &in.ReparentSettings, &out.ReparentSettings
+		*out = new(ReparentSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterSpec.
+func (in *VitessClusterSpec) DeepCopy() *VitessClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterStatus) DeepCopyInto(out *VitessClusterStatus) {
+	*out = *in
+	in.GlobalLockserver.DeepCopyInto(&out.GlobalLockserver)
+	out.VitessDashboard = in.VitessDashboard
+	out.Vtadmin = in.Vtadmin
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make(map[string]VitessClusterCellStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Keyspaces != nil {
+		in, out := &in.Keyspaces, &out.Keyspaces
+		*out = make(map[string]VitessClusterKeyspaceStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.OrphanedCells != nil {
+		in, out := &in.OrphanedCells, &out.OrphanedCells
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OrphanedKeyspaces != nil {
+		in, out := &in.OrphanedKeyspaces, &out.OrphanedKeyspaces
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RevisionHistory != nil {
+		in, out := &in.RevisionHistory, &out.RevisionHistory
+		*out = make([]VitessClusterRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolledBackTo != nil {
+		in, out := &in.RolledBackTo, &out.RolledBackTo
+		*out = new(int64)
+		**out = **in
+	}
+	in.RolloutStatus.DeepCopyInto(&out.RolloutStatus)
+	if in.TopologyConsistency != nil {
+		in, out := &in.TopologyConsistency, &out.TopologyConsistency
+		*out = new(TopologyConsistencyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]VitessClusterMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterStatus.
+func (in *VitessClusterStatus) DeepCopy() *VitessClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessClusterUpdateStrategy) DeepCopyInto(out *VitessClusterUpdateStrategy) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(VitessClusterUpdateStrategyType)
+		**out = **in
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(ExternalVitessClusterUpdateStrategyOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(VitessCanaryUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeChecks != nil {
+		in, out := &in.UpgradeChecks, &out.UpgradeChecks
+		*out = new(VitessUpgradeChecks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrashLoopBackoff != nil {
+		in, out := &in.CrashLoopBackoff, &out.CrashLoopBackoff
+		*out = new(VitessRolloutBackoff)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessClusterUpdateStrategy.
+func (in *VitessClusterUpdateStrategy) DeepCopy() *VitessClusterUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessClusterUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessContainerResourceStatus) DeepCopyInto(out *VitessContainerResourceStatus) {
+	*out = *in
+	if in.PeakUsage != nil {
+		in, out := &in.PeakUsage, &out.PeakUsage
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Recommended != nil {
+		in, out := &in.Recommended, &out.Recommended
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessContainerResourceStatus.
+func (in *VitessContainerResourceStatus) DeepCopy() *VitessContainerResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessContainerResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardAuthentication) DeepCopyInto(out *VitessDashboardAuthentication) {
+	*out = *in
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(VitessDashboardStaticAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardAuthentication.
+func (in *VitessDashboardAuthentication) DeepCopy() *VitessDashboardAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardGRPC) DeepCopyInto(out *VitessDashboardGRPC) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VitessDashboardTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(VitessDashboardAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardGRPC.
+func (in *VitessDashboardGRPC) DeepCopy() *VitessDashboardGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardSpec) DeepCopyInto(out *VitessDashboardSpec) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(VitessPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(VitessDashboardGRPC)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardSpec.
+func (in *VitessDashboardSpec) DeepCopy() *VitessDashboardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardStaticAuthentication) DeepCopyInto(out *VitessDashboardStaticAuthentication) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardStaticAuthentication.
+func (in *VitessDashboardStaticAuthentication) DeepCopy() *VitessDashboardStaticAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardStaticAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardStatus) DeepCopyInto(out *VitessDashboardStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardStatus.
+func (in *VitessDashboardStatus) DeepCopy() *VitessDashboardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDashboardTLS) DeepCopyInto(out *VitessDashboardTLS) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDashboardTLS.
+func (in *VitessDashboardTLS) DeepCopy() *VitessDashboardTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDashboardTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDrainHook) DeepCopyInto(out *VitessDrainHook) {
+	*out = *in
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(VitessTabletHook)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDrainHook.
+func (in *VitessDrainHook) DeepCopy() *VitessDrainHook {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDrainHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessDrainHooks) DeepCopyInto(out *VitessDrainHooks) {
+	*out = *in
+	if in.PreFinish != nil {
+		in, out := &in.PreFinish, &out.PreFinish
+		*out = make([]VitessDrainHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessDrainHooks.
+func (in *VitessDrainHooks) DeepCopy() *VitessDrainHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessDrainHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayAuthentication) DeepCopyInto(out *VitessGatewayAuthentication) {
+	*out = *in
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(VitessGatewayStaticAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(VitessGatewayLDAPAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCert != nil {
+		in, out := &in.ClientCert, &out.ClientCert
+		*out = new(VitessGatewayClientCertAuthentication)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAuthentication.
+func (in *VitessGatewayAuthentication) DeepCopy() *VitessGatewayAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayAutoscaler) DeepCopyInto(out *VitessGatewayAutoscaler) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilization != nil {
+		in, out := &in.TargetCPUUtilization, &out.TargetCPUUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueriesPerSecond != nil {
+		in, out := &in.QueriesPerSecond, &out.QueriesPerSecond
+		*out = new(VitessGatewayAutoscalerMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(VitessGatewayAutoscalerMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]autoscalingv2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScaleDownStabilizationWindow != nil {
+		in, out := &in.ScaleDownStabilizationWindow, &out.ScaleDownStabilizationWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAutoscaler.
+func (in *VitessGatewayAutoscaler) DeepCopy() *VitessGatewayAutoscaler {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayAutoscaler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayAutoscalerMetric) DeepCopyInto(out *VitessGatewayAutoscalerMetric) {
+	*out = *in
+	out.AverageValue = in.AverageValue.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayAutoscalerMetric.
+func (in *VitessGatewayAutoscalerMetric) DeepCopy() *VitessGatewayAutoscalerMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayAutoscalerMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayBlueGreenStatus) DeepCopyInto(out *VitessGatewayBlueGreenStatus) {
+	*out = *in
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayBlueGreenStatus.
+func (in *VitessGatewayBlueGreenStatus) DeepCopy() *VitessGatewayBlueGreenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayBlueGreenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayBlueGreenStrategy) DeepCopyInto(out *VitessGatewayBlueGreenStrategy) {
+	*out = *in
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StepInterval != nil {
+		in, out := &in.StepInterval, &out.StepInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxErrorPercent != nil {
+		in, out := &in.MaxErrorPercent, &out.MaxErrorPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinQueries != nil {
+		in, out := &in.MinQueries, &out.MinQueries
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayBlueGreenStrategy.
+func (in *VitessGatewayBlueGreenStrategy) DeepCopy() *VitessGatewayBlueGreenStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayBlueGreenStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayClientCertAuthentication) DeepCopyInto(out *VitessGatewayClientCertAuthentication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayClientCertAuthentication.
+func (in *VitessGatewayClientCertAuthentication) DeepCopy() *VitessGatewayClientCertAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayClientCertAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayExposure) DeepCopyInto(out *VitessGatewayExposure) {
+	*out = *in
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(VitessGatewayLoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodePorts != nil {
+		in, out := &in.NodePorts, &out.NodePorts
+		*out = new(VitessGatewayNodePorts)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = new(VitessGatewayRoutes)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayExposure.
+func (in *VitessGatewayExposure) DeepCopy() *VitessGatewayExposure {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayExposure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayHTTPRoute) DeepCopyInto(out *VitessGatewayHTTPRoute) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayAPIParentRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayHTTPRoute.
+func (in *VitessGatewayHTTPRoute) DeepCopy() *VitessGatewayHTTPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayHTTPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayLDAPAuthentication) DeepCopyInto(out *VitessGatewayLDAPAuthentication) {
+	*out = *in
+	if in.BindPasswordSecret != nil {
+		in, out := &in.BindPasswordSecret, &out.BindPasswordSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.CACertSecret != nil {
+		in, out := &in.CACertSecret, &out.CACertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayLDAPAuthentication.
+func (in *VitessGatewayLDAPAuthentication) DeepCopy() *VitessGatewayLDAPAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayLDAPAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayLameduck) DeepCopyInto(out *VitessGatewayLameduck) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayLameduck.
+func (in *VitessGatewayLameduck) DeepCopy() *VitessGatewayLameduck {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayLameduck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayLoadBalancer) DeepCopyInto(out *VitessGatewayLoadBalancer) {
+	*out = *in
+	if in.Class != nil {
+		in, out := &in.Class, &out.Class
+		*out = new(string)
+		**out = **in
+	}
+	if in.SourceRanges != nil {
+		in, out := &in.SourceRanges, &out.SourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayLoadBalancer.
+func (in *VitessGatewayLoadBalancer) DeepCopy() *VitessGatewayLoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayLoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayNodePorts) DeepCopyInto(out *VitessGatewayNodePorts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayNodePorts.
+func (in *VitessGatewayNodePorts) DeepCopy() *VitessGatewayNodePorts {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayNodePorts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayRolloutStrategy) DeepCopyInto(out *VitessGatewayRolloutStrategy) {
+	*out = *in
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(VitessGatewayBlueGreenStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayRolloutStrategy.
+func (in *VitessGatewayRolloutStrategy) DeepCopy() *VitessGatewayRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayRoutes) DeepCopyInto(out *VitessGatewayRoutes) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(VitessGatewayHTTPRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCP != nil {
+		in, out := &in.TCP, &out.TCP
+		*out = new(VitessGatewayTCPRoute)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayRoutes.
+func (in *VitessGatewayRoutes) DeepCopy() *VitessGatewayRoutes {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayRoutes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewaySecureTransport) DeepCopyInto(out *VitessGatewaySecureTransport) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(VitessGatewayTLSSecureTransport)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewaySecureTransport.
+func (in *VitessGatewaySecureTransport) DeepCopy() *VitessGatewaySecureTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewaySecureTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayServingGate) DeepCopyInto(out *VitessGatewayServingGate) {
+	*out = *in
+	if in.MinHealthyShardPercent != nil {
+		in, out := &in.MinHealthyShardPercent, &out.MinHealthyShardPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxWait != nil {
+		in, out := &in.MaxWait, &out.MaxWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayServingGate.
+func (in *VitessGatewayServingGate) DeepCopy() *VitessGatewayServingGate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayServingGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayServingGateStatus) DeepCopyInto(out *VitessGatewayServingGateStatus) {
+	*out = *in
+	if in.HeldPods != nil {
+		in, out := &in.HeldPods, &out.HeldPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayServingGateStatus.
+func (in *VitessGatewayServingGateStatus) DeepCopy() *VitessGatewayServingGateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayServingGateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayStaticAuthStatus) DeepCopyInto(out *VitessGatewayStaticAuthStatus) {
+	*out = *in
+	if in.ChangedTime != nil {
+		in, out := &in.ChangedTime, &out.ChangedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingPods != nil {
+		in, out := &in.PendingPods, &out.PendingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayStaticAuthStatus.
+func (in *VitessGatewayStaticAuthStatus) DeepCopy() *VitessGatewayStaticAuthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayStaticAuthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayStaticAuthentication) DeepCopyInto(out *VitessGatewayStaticAuthentication) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayStaticAuthentication.
+func (in *VitessGatewayStaticAuthentication) DeepCopy() *VitessGatewayStaticAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayStaticAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayTCPRoute) DeepCopyInto(out *VitessGatewayTCPRoute) {
+	*out = *in
+	if in.ParentRefs != nil {
+		in, out := &in.ParentRefs, &out.ParentRefs
+		*out = make([]GatewayAPIParentRef, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayTCPRoute.
+func (in *VitessGatewayTCPRoute) DeepCopy() *VitessGatewayTCPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayTCPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessGatewayTLSSecureTransport) DeepCopyInto(out *VitessGatewayTLSSecureTransport) {
+	*out = *in
+	if in.ClientCACertSecret != nil {
+		in, out := &in.ClientCACertSecret, &out.ClientCACertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+	if in.KeySecret != nil {
+		in, out := &in.KeySecret, &out.KeySecret
+		*out = new(SecretSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessGatewayTLSSecureTransport.
+func (in *VitessGatewayTLSSecureTransport) DeepCopy() *VitessGatewayTLSSecureTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessGatewayTLSSecureTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessImagePullPolicies) DeepCopyInto(out *VitessImagePullPolicies) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImagePullPolicies.
+func (in *VitessImagePullPolicies) DeepCopy() *VitessImagePullPolicies {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessImagePullPolicies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessImages) DeepCopyInto(out *VitessImages) {
+	*out = *in
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldImage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessImages.
+func (in *VitessImages) DeepCopy() *VitessImages {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessInitialRestoreSpec) DeepCopyInto(out *VitessInitialRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessInitialRestoreSpec.
+func (in *VitessInitialRestoreSpec) DeepCopy() *VitessInitialRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessInitialRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyRange) DeepCopyInto(out *VitessKeyRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyRange.
+func (in *VitessKeyRange) DeepCopy() *VitessKeyRange {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspace) DeepCopyInto(out *VitessKeyspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspace.
+func (in *VitessKeyspace) DeepCopy() *VitessKeyspace {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessKeyspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceCondition) DeepCopyInto(out *VitessKeyspaceCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCondition.
+func (in *VitessKeyspaceCondition) DeepCopy() *VitessKeyspaceCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopyInto(out *VitessKeyspaceCustomPartitioning) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessKeyspaceKeyRangeShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ShardTemplate != nil {
+		in, out := &in.ShardTemplate, &out.ShardTemplate
+		*out = new(VitessShardTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ShardRanges != nil {
+		in, out := &in.ShardRanges, &out.ShardRanges
+		*out = make([]VitessKeyspaceShardRange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxConcurrentShardCreations != nil {
+		in, out := &in.MaxConcurrentShardCreations, &out.MaxConcurrentShardCreations
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceCustomPartitioning.
+func (in *VitessKeyspaceCustomPartitioning) DeepCopy() *VitessKeyspaceCustomPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceCustomPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceDecommissionPolicy) DeepCopyInto(out *VitessKeyspaceDecommissionPolicy) {
+	*out = *in
+	if in.HoldPeriod != nil {
+		in, out := &in.HoldPeriod, &out.HoldPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceDecommissionPolicy.
+func (in *VitessKeyspaceDecommissionPolicy) DeepCopy() *VitessKeyspaceDecommissionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceDecommissionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceDecommissionStatus) DeepCopyInto(out *VitessKeyspaceDecommissionStatus) {
+	*out = *in
+	if in.LastTrafficCheckTime != nil {
+		in, out := &in.LastTrafficCheckTime, &out.LastTrafficCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.HoldStartTime != nil {
+		in, out := &in.HoldStartTime, &out.HoldStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceDecommissionStatus.
+func (in *VitessKeyspaceDecommissionStatus) DeepCopy() *VitessKeyspaceDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopyInto(out *VitessKeyspaceEqualPartitioning) {
+	*out = *in
+	in.ShardTemplate.DeepCopyInto(&out.ShardTemplate)
+	if in.MaxConcurrentShardCreations != nil {
+		in, out := &in.MaxConcurrentShardCreations, &out.MaxConcurrentShardCreations
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceEqualPartitioning.
+func (in *VitessKeyspaceEqualPartitioning) DeepCopy() *VitessKeyspaceEqualPartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceEqualPartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceImages) DeepCopyInto(out *VitessKeyspaceImages) {
+	*out = *in
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldImage)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceImages.
+func (in *VitessKeyspaceImages) DeepCopy() *VitessKeyspaceImages {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopyInto(out *VitessKeyspaceKeyRangeShard) {
+	*out = *in
+	out.KeyRange = in.KeyRange
+	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceKeyRangeShard.
+func (in *VitessKeyspaceKeyRangeShard) DeepCopy() *VitessKeyspaceKeyRangeShard {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceKeyRangeShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceList) DeepCopyInto(out *VitessKeyspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessKeyspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceList.
+func (in *VitessKeyspaceList) DeepCopy() *VitessKeyspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessKeyspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspacePartitioning) DeepCopyInto(out *VitessKeyspacePartitioning) {
+	*out = *in
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = new(VitessKeyspaceEqualPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(VitessKeyspaceCustomPartitioning)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioning.
+func (in *VitessKeyspacePartitioning) DeepCopy() *VitessKeyspacePartitioning {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspacePartitioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspacePartitioningStatus) DeepCopyInto(out *VitessKeyspacePartitioningStatus) {
+	*out = *in
+	if in.ShardNames != nil {
+		in, out := &in.ShardNames, &out.ShardNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspacePartitioningStatus.
+func (in *VitessKeyspacePartitioningStatus) DeepCopy() *VitessKeyspacePartitioningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspacePartitioningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceSchema) DeepCopyInto(out *VitessKeyspaceSchema) {
+	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]VitessSchemaMigration, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sequences != nil {
+		in, out := &in.Sequences, &out.Sequences
+		*out = make([]VitessSequence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReferenceTables != nil {
+		in, out := &in.ReferenceTables, &out.ReferenceTables
+		*out = make([]VitessReferenceTable, len(*in))
+		copy(*out, *in)
+	}
+	if in.MigrationControls != nil {
+		in, out := &in.MigrationControls, &out.MigrationControls
+		*out = make([]VitessSchemaMigrationControl, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSchema.
+func (in *VitessKeyspaceSchema) DeepCopy() *VitessKeyspaceSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceSchemaStatus) DeepCopyInto(out *VitessKeyspaceSchemaStatus) {
+	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]VitessSchemaMigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveMigrations != nil {
+		in, out := &in.ActiveMigrations, &out.ActiveMigrations
+		*out = make([]VitessActiveSchemaMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sequences != nil {
+		in, out := &in.Sequences, &out.Sequences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingVSchemaChanges != nil {
+		in, out := &in.PendingVSchemaChanges, &out.PendingVSchemaChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSchemaStatus.
+func (in *VitessKeyspaceSchemaStatus) DeepCopy() *VitessKeyspaceSchemaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceSchemaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceShardRange) DeepCopyInto(out *VitessKeyspaceShardRange) {
+	*out = *in
+	out.KeyRange = in.KeyRange
+	if in.TabletPoolOverrides != nil {
+		in, out := &in.TabletPoolOverrides, &out.TabletPoolOverrides
+		*out = make([]VitessTabletPoolOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceShardRange.
+func (in *VitessKeyspaceShardRange) DeepCopy() *VitessKeyspaceShardRange {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceShardRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceShardStatus) DeepCopyInto(out *VitessKeyspaceShardStatus) {
+	*out = *in
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceShardStatus.
+func (in *VitessKeyspaceShardStatus) DeepCopy() *VitessKeyspaceShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceSpec) DeepCopyInto(out *VitessKeyspaceSpec) {
+	*out = *in
+	in.VitessKeyspaceTemplate.DeepCopyInto(&out.VitessKeyspaceTemplate)
+	out.GlobalLockserver = in.GlobalLockserver
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ZoneMap != nil {
+		in, out := &in.ZoneMap, &out.ZoneMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]VitessBackupLocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VitessBackupPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupVerification != nil {
+		in, out := &in.BackupVerification, &out.BackupVerification
+		*out = new(VitessBackupVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupSourceTabletPolicy != nil {
+		in, out := &in.BackupSourceTabletPolicy, &out.BackupSourceTabletPolicy
+		*out = new(VitessBackupSourceTabletPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupReplication != nil {
+		in, out := &in.BackupReplication, &out.BackupReplication
+		*out = new(VitessBackupReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredPrimaryCells != nil {
+		in, out := &in.PreferredPrimaryCells, &out.PreferredPrimaryCells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReparentSettings != nil {
+		in, out := &in.ReparentSettings, &out.ReparentSettings
+		*out = new(ReparentSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(VitessInitialRestoreSpec)
+		**out = **in
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceSpec.
+func (in *VitessKeyspaceSpec) DeepCopy() *VitessKeyspaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceStatus) DeepCopyInto(out *VitessKeyspaceStatus) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make(map[string]VitessKeyspaceShardStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Partitionings != nil {
+		in, out := &in.Partitionings, &out.Partitionings
+		*out = make([]VitessKeyspacePartitioningStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.OrphanedShards != nil {
@@ -1953,551 +3978,2003 @@ func (in *VitessKeyspaceStatus) DeepCopyInto(out *VitessKeyspaceStatus) {
 			(*out)[key] = val
 		}
 	}
-	if in.Resharding != nil {
-		in, out := &in.Resharding, &out.Resharding
-		*out = new(ReshardingStatus)
-		(*in).DeepCopyInto(*out)
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(VitessOrchestratorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resharding != nil {
+		in, out := &in.Resharding, &out.Resharding
+		*out = new(ReshardingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Materializations != nil {
+		in, out := &in.Materializations, &out.Materializations
+		*out = make([]VitessMaterializationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoReshard != nil {
+		in, out := &in.AutoReshard, &out.AutoReshard
+		*out = new(VitessAutoReshardStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(VitessKeyspaceDecommissionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(VitessKeyspaceSchemaStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessKeyspaceCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceStatus.
+func (in *VitessKeyspaceStatus) DeepCopy() *VitessKeyspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessKeyspaceTemplate) DeepCopyInto(out *VitessKeyspaceTemplate) {
+	*out = *in
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(VitessOrchestratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReparentConcurrency != nil {
+		in, out := &in.ReparentConcurrency, &out.ReparentConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpdateOrder != nil {
+		in, out := &in.UpdateOrder, &out.UpdateOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackupSchedule != nil {
+		in, out := &in.BackupSchedule, &out.BackupSchedule
+		*out = new(VitessBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupLocationOverrides != nil {
+		in, out := &in.BackupLocationOverrides, &out.BackupLocationOverrides
+		*out = make([]VitessBackupLocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Partitionings != nil {
+		in, out := &in.Partitionings, &out.Partitionings
+		*out = make([]VitessKeyspacePartitioning, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Materializations != nil {
+		in, out := &in.Materializations, &out.Materializations
+		*out = make([]VitessMaterialization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoReshard != nil {
+		in, out := &in.AutoReshard, &out.AutoReshard
+		*out = new(VitessAutoReshardPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Throttler != nil {
+		in, out := &in.Throttler, &out.Throttler
+		*out = new(VitessThrottlerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(VitessKeyspaceSchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = new(VitessKeyspaceDecommissionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceTemplate.
+func (in *VitessKeyspaceTemplate) DeepCopy() *VitessKeyspaceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessKeyspaceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessLockserverParams) DeepCopyInto(out *VitessLockserverParams) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLockserverParams.
+func (in *VitessLockserverParams) DeepCopy() *VitessLockserverParams {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessLockserverParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMaterialization) DeepCopyInto(out *VitessMaterialization) {
+	*out = *in
+	if in.TabletTypes != nil {
+		in, out := &in.TabletTypes, &out.TabletTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterialization.
+func (in *VitessMaterialization) DeepCopy() *VitessMaterialization {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMaterialization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMaterializationStatus) DeepCopyInto(out *VitessMaterializationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMaterializationStatus.
+func (in *VitessMaterializationStatus) DeepCopy() *VitessMaterializationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMaterializationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMoveTables) DeepCopyInto(out *VitessMoveTables) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMoveTables.
+func (in *VitessMoveTables) DeepCopy() *VitessMoveTables {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMoveTables)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessMoveTables) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMoveTablesCondition) DeepCopyInto(out *VitessMoveTablesCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMoveTablesCondition.
+func (in *VitessMoveTablesCondition) DeepCopy() *VitessMoveTablesCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMoveTablesCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMoveTablesList) DeepCopyInto(out *VitessMoveTablesList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessMoveTables, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMoveTablesList.
+func (in *VitessMoveTablesList) DeepCopy() *VitessMoveTablesList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMoveTablesList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessMoveTablesList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMoveTablesSpec) DeepCopyInto(out *VitessMoveTablesSpec) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeTables != nil {
+		in, out := &in.ExcludeTables, &out.ExcludeTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TabletTypes != nil {
+		in, out := &in.TabletTypes, &out.TabletTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VDiff != nil {
+		in, out := &in.VDiff, &out.VDiff
+		*out = new(VitessVDiffSchedule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMoveTablesSpec.
+func (in *VitessMoveTablesSpec) DeepCopy() *VitessMoveTablesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMoveTablesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessMoveTablesStatus) DeepCopyInto(out *VitessMoveTablesStatus) {
+	*out = *in
+	if in.LastVDiff != nil {
+		in, out := &in.LastVDiff, &out.LastVDiff
+		*out = new(VitessVDiffReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessMoveTablesCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessMoveTablesStatus.
+func (in *VitessMoveTablesStatus) DeepCopy() *VitessMoveTablesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessMoveTablesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessOrchestratorRecovery) DeepCopyInto(out *VitessOrchestratorRecovery) {
+	*out = *in
+	if in.AllowEmergencyReparent != nil {
+		in, out := &in.AllowEmergencyReparent, &out.AllowEmergencyReparent
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WaitReplicasTimeout != nil {
+		in, out := &in.WaitReplicasTimeout, &out.WaitReplicasTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TolerableReplicationLag != nil {
+		in, out := &in.TolerableReplicationLag, &out.TolerableReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RecoveryPeriodBlockDuration != nil {
+		in, out := &in.RecoveryPeriodBlockDuration, &out.RecoveryPeriodBlockDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorRecovery.
+func (in *VitessOrchestratorRecovery) DeepCopy() *VitessOrchestratorRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessOrchestratorRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessOrchestratorRecoveryRecord) DeepCopyInto(out *VitessOrchestratorRecoveryRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorRecoveryRecord.
+func (in *VitessOrchestratorRecoveryRecord) DeepCopy() *VitessOrchestratorRecoveryRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessOrchestratorRecoveryRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Recovery != nil {
+		in, out := &in.Recovery, &out.Recovery
+		*out = new(VitessOrchestratorRecovery)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ExtraFlags != nil {
+		in, out := &in.ExtraFlags, &out.ExtraFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceOverrides)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(VitessPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorSpec.
+func (in *VitessOrchestratorSpec) DeepCopy() *VitessOrchestratorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessOrchestratorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessOrchestratorStatus) DeepCopyInto(out *VitessOrchestratorStatus) {
+	*out = *in
+	if in.RecentRecoveries != nil {
+		in, out := &in.RecentRecoveries, &out.RecentRecoveries
+		*out = make([]VitessOrchestratorRecoveryRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorStatus.
+func (in *VitessOrchestratorStatus) DeepCopy() *VitessOrchestratorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessOrchestratorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessPodDisruptionBudget) DeepCopyInto(out *VitessPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessPodDisruptionBudget.
+func (in *VitessPodDisruptionBudget) DeepCopy() *VitessPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReferenceTable) DeepCopyInto(out *VitessReferenceTable) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReferenceTable.
+func (in *VitessReferenceTable) DeepCopy() *VitessReferenceTable {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReferenceTable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReplicationRepairSpec) DeepCopyInto(out *VitessReplicationRepairSpec) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]VitessReplicationRepairAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationRepairSpec.
+func (in *VitessReplicationRepairSpec) DeepCopy() *VitessReplicationRepairSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReplicationRepairSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReplicationSpec) DeepCopyInto(out *VitessReplicationSpec) {
+	*out = *in
+	if in.InitializeMaster != nil {
+		in, out := &in.InitializeMaster, &out.InitializeMaster
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InitializeBackup != nil {
+		in, out := &in.InitializeBackup, &out.InitializeBackup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RecoverRestartedMaster != nil {
+		in, out := &in.RecoverRestartedMaster, &out.RecoverRestartedMaster
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DrainMaxUnavailable != nil {
+		in, out := &in.DrainMaxUnavailable, &out.DrainMaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Repair != nil {
+		in, out := &in.Repair, &out.Repair
+		*out = new(VitessReplicationRepairSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationSpec.
+func (in *VitessReplicationSpec) DeepCopy() *VitessReplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReshard) DeepCopyInto(out *VitessReshard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshard.
+func (in *VitessReshard) DeepCopy() *VitessReshard {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReshard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessReshard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReshardCondition) DeepCopyInto(out *VitessReshardCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardCondition.
+func (in *VitessReshardCondition) DeepCopy() *VitessReshardCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReshardCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReshardList) DeepCopyInto(out *VitessReshardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessReshard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardList.
+func (in *VitessReshardList) DeepCopy() *VitessReshardList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReshardList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessReshardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReshardSpec) DeepCopyInto(out *VitessReshardSpec) {
+	*out = *in
+	if in.SourceShards != nil {
+		in, out := &in.SourceShards, &out.SourceShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetShards != nil {
+		in, out := &in.TargetShards, &out.TargetShards
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TabletTypes != nil {
+		in, out := &in.TabletTypes, &out.TabletTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.VDiff != nil {
+		in, out := &in.VDiff, &out.VDiff
+		*out = new(VitessVDiffSchedule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardSpec.
+func (in *VitessReshardSpec) DeepCopy() *VitessReshardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReshardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessReshardStatus) DeepCopyInto(out *VitessReshardStatus) {
+	*out = *in
+	if in.LastVDiff != nil {
+		in, out := &in.LastVDiff, &out.LastVDiff
+		*out = new(VitessVDiffReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VitessReshardCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReshardStatus.
+func (in *VitessReshardStatus) DeepCopy() *VitessReshardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessReshardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessRolloutBackoff) DeepCopyInto(out *VitessRolloutBackoff) {
+	*out = *in
+	if in.RestartThreshold != nil {
+		in, out := &in.RestartThreshold, &out.RestartThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialDelay != nil {
+		in, out := &in.InitialDelay, &out.InitialDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessRolloutBackoff.
+func (in *VitessRolloutBackoff) DeepCopy() *VitessRolloutBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessRolloutBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessRolloutCounts) DeepCopyInto(out *VitessRolloutCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessRolloutCounts.
+func (in *VitessRolloutCounts) DeepCopy() *VitessRolloutCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessRolloutCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessScaleProfile) DeepCopyInto(out *VitessScaleProfile) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessScaleProfile.
+func (in *VitessScaleProfile) DeepCopy() *VitessScaleProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessScaleProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessSchemaMigration) DeepCopyInto(out *VitessSchemaMigration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessSchemaMigration.
+func (in *VitessSchemaMigration) DeepCopy() *VitessSchemaMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessSchemaMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessSchemaMigrationControl) DeepCopyInto(out *VitessSchemaMigrationControl) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessSchemaMigrationControl.
+func (in *VitessSchemaMigrationControl) DeepCopy() *VitessSchemaMigrationControl {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessSchemaMigrationControl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessSchemaMigrationShardStatus) DeepCopyInto(out *VitessSchemaMigrationShardStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessSchemaMigrationShardStatus.
+func (in *VitessSchemaMigrationShardStatus) DeepCopy() *VitessSchemaMigrationShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessSchemaMigrationShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessSchemaMigrationStatus) DeepCopyInto(out *VitessSchemaMigrationStatus) {
+	*out = *in
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = make([]VitessSchemaMigrationShardStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessSchemaMigrationStatus.
+func (in *VitessSchemaMigrationStatus) DeepCopy() *VitessSchemaMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessSchemaMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessSequence) DeepCopyInto(out *VitessSequence) {
+	*out = *in
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessSequence.
+func (in *VitessSequence) DeepCopy() *VitessSequence {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessSequence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShard) DeepCopyInto(out *VitessShard) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShard.
+func (in *VitessShard) DeepCopy() *VitessShard {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessShard) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardCanaryStatus) DeepCopyInto(out *VitessShardCanaryStatus) {
+	*out = *in
+	if in.Tablets != nil {
+		in, out := &in.Tablets, &out.Tablets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SoakStartTime != nil {
+		in, out := &in.SoakStartTime, &out.SoakStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardCanaryStatus.
+func (in *VitessShardCanaryStatus) DeepCopy() *VitessShardCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardCondition) DeepCopyInto(out *VitessShardCondition) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardCondition.
+func (in *VitessShardCondition) DeepCopy() *VitessShardCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardDrainStatus) DeepCopyInto(out *VitessShardDrainStatus) {
+	*out = *in
+	if in.Draining != nil {
+		in, out := &in.Draining, &out.Draining
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Acknowledged != nil {
+		in, out := &in.Acknowledged, &out.Acknowledged
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Finished != nil {
+		in, out := &in.Finished, &out.Finished
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aborted != nil {
+		in, out := &in.Aborted, &out.Aborted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Stuck != nil {
+		in, out := &in.Stuck, &out.Stuck
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardDrainStatus.
+func (in *VitessShardDrainStatus) DeepCopy() *VitessShardDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardExternalFailoverStatus) DeepCopyInto(out *VitessShardExternalFailoverStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastFailoverTime != nil {
+		in, out := &in.LastFailoverTime, &out.LastFailoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RewiredTime != nil {
+		in, out := &in.RewiredTime, &out.RewiredTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardExternalFailoverStatus.
+func (in *VitessShardExternalFailoverStatus) DeepCopy() *VitessShardExternalFailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardExternalFailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardList) DeepCopyInto(out *VitessShardList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VitessShard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardList.
+func (in *VitessShardList) DeepCopy() *VitessShardList {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VitessShardList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardPrimaryAffinity) DeepCopyInto(out *VitessShardPrimaryAffinity) {
+	*out = *in
+	if in.StableFor != nil {
+		in, out := &in.StableFor, &out.StableFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardPrimaryAffinity.
+func (in *VitessShardPrimaryAffinity) DeepCopy() *VitessShardPrimaryAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardPrimaryAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardReparentRecord) DeepCopyInto(out *VitessShardReparentRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardReparentRecord.
+func (in *VitessShardReparentRecord) DeepCopy() *VitessShardReparentRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardReparentRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardRolloutBackoffStatus) DeepCopyInto(out *VitessShardRolloutBackoffStatus) {
+	*out = *in
+	if in.RetryTime != nil {
+		in, out := &in.RetryTime, &out.RetryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardRolloutBackoffStatus.
+func (in *VitessShardRolloutBackoffStatus) DeepCopy() *VitessShardRolloutBackoffStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardRolloutBackoffStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardSpec) DeepCopyInto(out *VitessShardSpec) {
+	*out = *in
+	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
+	if in.ZoneMap != nil {
+		in, out := &in.ZoneMap, &out.ZoneMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Images.DeepCopyInto(&out.Images)
+	out.ImagePullPolicies = in.ImagePullPolicies
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	out.KeyRange = in.KeyRange
+	out.GlobalLockserver = in.GlobalLockserver
+	if in.VitessOrchestrator != nil {
+		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
+		*out = new(VitessOrchestratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]VitessBackupLocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Vtbackup != nil {
+		in, out := &in.Vtbackup, &out.Vtbackup
+		*out = new(VitessBackupPodSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupVerification != nil {
+		in, out := &in.BackupVerification, &out.BackupVerification
+		*out = new(VitessBackupVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupSourceTabletPolicy != nil {
+		in, out := &in.BackupSourceTabletPolicy, &out.BackupSourceTabletPolicy
+		*out = new(VitessBackupSourceTabletPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupReplication != nil {
+		in, out := &in.BackupReplication, &out.BackupReplication
+		*out = new(VitessBackupReplicationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExtraVitessFlags != nil {
+		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologyReconciliation != nil {
+		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
+		*out = new(TopoReconcileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessClusterUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreferredPrimaryCells != nil {
+		in, out := &in.PreferredPrimaryCells, &out.PreferredPrimaryCells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReparentSettings != nil {
+		in, out := &in.ReparentSettings, &out.ReparentSettings
+		*out = new(ReparentSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReparentConcurrency != nil {
+		in, out := &in.ReparentConcurrency, &out.ReparentConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackupSchedule != nil {
+		in, out := &in.BackupSchedule, &out.BackupSchedule
+		*out = new(VitessBackupScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(VitessInitialRestoreSpec)
+		**out = **in
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(VitessStandbySpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardSpec.
+func (in *VitessShardSpec) DeepCopy() *VitessShardSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardStatus) DeepCopyInto(out *VitessShardStatus) {
+	*out = *in
+	if in.Tablets != nil {
+		in, out := &in.Tablets, &out.Tablets
+		*out = make(map[string]VitessTabletStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.OrphanedTablets != nil {
+		in, out := &in.OrphanedTablets, &out.OrphanedTablets
+		*out = make(map[string]OrphanStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Cells != nil {
+		in, out := &in.Cells, &out.Cells
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
+	in.VitessOrchestrator.DeepCopyInto(&out.VitessOrchestrator)
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]VitessKeyspaceCondition, len(*in))
+		*out = make(map[VitessShardConditionType]VitessShardCondition, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.BackupLocations != nil {
+		in, out := &in.BackupLocations, &out.BackupLocations
+		*out = make([]*ShardBackupLocationStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ShardBackupLocationStatus)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.BackupRequest != nil {
+		in, out := &in.BackupRequest, &out.BackupRequest
+		*out = new(ShardBackupRequestStatus)
+		**out = **in
+	}
+	in.DrainStatus.DeepCopyInto(&out.DrainStatus)
+	if in.ReparentHistory != nil {
+		in, out := &in.ReparentHistory, &out.ReparentHistory
+		*out = make([]VitessShardReparentRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(VitessShardCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeStatus != nil {
+		in, out := &in.UpgradeStatus, &out.UpgradeStatus
+		*out = new(VitessShardUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutBackoff != nil {
+		in, out := &in.RolloutBackoff, &out.RolloutBackoff
+		*out = new(VitessShardRolloutBackoffStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TabletPoolResources != nil {
+		in, out := &in.TabletPoolResources, &out.TabletPoolResources
+		*out = make([]VitessTabletPoolResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TabletPoolScaleProfiles != nil {
+		in, out := &in.TabletPoolScaleProfiles, &out.TabletPoolScaleProfiles
+		*out = make([]VitessTabletPoolScaleProfileStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalFailover != nil {
+		in, out := &in.ExternalFailover, &out.ExternalFailover
+		*out = new(VitessShardExternalFailoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardStatus.
+func (in *VitessShardStatus) DeepCopy() *VitessShardStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardTabletPool) DeepCopyInto(out *VitessShardTabletPool) {
+	*out = *in
+	if in.DataVolumeClaimTemplate != nil {
+		in, out := &in.DataVolumeClaimTemplate, &out.DataVolumeClaimTemplate
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainOrder != nil {
+		in, out := &in.DrainOrder, &out.DrainOrder
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DelayedReplication != nil {
+		in, out := &in.DelayedReplication, &out.DelayedReplication
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(VitessTabletPoolUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Vttablet.DeepCopyInto(&out.Vttablet)
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqldExporter != nil {
+		in, out := &in.MysqldExporter, &out.MysqldExporter
+		*out = new(MysqldExporterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqlUsers != nil {
+		in, out := &in.MysqlUsers, &out.MysqlUsers
+		*out = make([]MysqlUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExternalDatastore != nil {
+		in, out := &in.ExternalDatastore, &out.ExternalDatastore
+		*out = new(ExternalDatastore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraLabels != nil {
+		in, out := &in.ExtraLabels, &out.ExtraLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarContainers != nil {
+		in, out := &in.SidecarContainers, &out.SidecarContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ZoneSpread != nil {
+		in, out := &in.ZoneSpread, &out.ZoneSpread
+		*out = new(VitessZoneSpread)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(VitessPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleProfiles != nil {
+		in, out := &in.ScaleProfiles, &out.ScaleProfiles
+		*out = make([]VitessScaleProfile, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPool.
+func (in *VitessShardTabletPool) DeepCopy() *VitessShardTabletPool {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardTabletPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardTemplate) DeepCopyInto(out *VitessShardTemplate) {
+	*out = *in
+	if in.TabletPools != nil {
+		in, out := &in.TabletPools, &out.TabletPools
+		*out = make([]VitessShardTabletPool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.DatabaseInitScriptSecret = in.DatabaseInitScriptSecret
+	in.Replication.DeepCopyInto(&out.Replication)
+	if in.DrainHooks != nil {
+		in, out := &in.DrainHooks, &out.DrainHooks
+		*out = new(VitessDrainHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrimaryAffinity != nil {
+		in, out := &in.PrimaryAffinity, &out.PrimaryAffinity
+		*out = new(VitessShardPrimaryAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MinReplicaZones != nil {
+		in, out := &in.MinReplicaZones, &out.MinReplicaZones
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTemplate.
+func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VitessShardTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessShardUpgradeStatus) DeepCopyInto(out *VitessShardUpgradeStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]VitessTabletUpgradeStep, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceStatus.
-func (in *VitessKeyspaceStatus) DeepCopy() *VitessKeyspaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardUpgradeStatus.
+func (in *VitessShardUpgradeStatus) DeepCopy() *VitessShardUpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceStatus)
+	out := new(VitessShardUpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessKeyspaceTemplate) DeepCopyInto(out *VitessKeyspaceTemplate) {
+func (in *VitessStandbySpec) DeepCopyInto(out *VitessStandbySpec) {
 	*out = *in
-	if in.VitessOrchestrator != nil {
-		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
-		*out = new(VitessOrchestratorSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Partitionings != nil {
-		in, out := &in.Partitionings, &out.Partitionings
-		*out = make([]VitessKeyspacePartitioning, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessKeyspaceTemplate.
-func (in *VitessKeyspaceTemplate) DeepCopy() *VitessKeyspaceTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessStandbySpec.
+func (in *VitessStandbySpec) DeepCopy() *VitessStandbySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessKeyspaceTemplate)
+	out := new(VitessStandbySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessLockserverParams) DeepCopyInto(out *VitessLockserverParams) {
+func (in *VitessTabletDataVolumeMigrationStatus) DeepCopyInto(out *VitessTabletDataVolumeMigrationStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessLockserverParams.
-func (in *VitessLockserverParams) DeepCopy() *VitessLockserverParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletDataVolumeMigrationStatus.
+func (in *VitessTabletDataVolumeMigrationStatus) DeepCopy() *VitessTabletDataVolumeMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessLockserverParams)
+	out := new(VitessTabletDataVolumeMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessOrchestratorSpec) DeepCopyInto(out *VitessOrchestratorSpec) {
+func (in *VitessTabletDataVolumeResizeStatus) DeepCopyInto(out *VitessTabletDataVolumeResizeStatus) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.ExtraFlags != nil {
-		in, out := &in.ExtraFlags, &out.ExtraFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.CurrentSize != nil {
+		in, out := &in.CurrentSize, &out.CurrentSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceOverrides)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.DesiredSize != nil {
+		in, out := &in.DesiredSize, &out.DesiredSize
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorSpec.
-func (in *VitessOrchestratorSpec) DeepCopy() *VitessOrchestratorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletDataVolumeResizeStatus.
+func (in *VitessTabletDataVolumeResizeStatus) DeepCopy() *VitessTabletDataVolumeResizeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessOrchestratorSpec)
+	out := new(VitessTabletDataVolumeResizeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessOrchestratorStatus) DeepCopyInto(out *VitessOrchestratorStatus) {
+func (in *VitessTabletHook) DeepCopyInto(out *VitessTabletHook) {
 	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessOrchestratorStatus.
-func (in *VitessOrchestratorStatus) DeepCopy() *VitessOrchestratorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletHook.
+func (in *VitessTabletHook) DeepCopy() *VitessTabletHook {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessOrchestratorStatus)
+	out := new(VitessTabletHook)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessReplicationSpec) DeepCopyInto(out *VitessReplicationSpec) {
+func (in *VitessTabletPoolOverride) DeepCopyInto(out *VitessTabletPoolOverride) {
 	*out = *in
-	if in.InitializeMaster != nil {
-		in, out := &in.InitializeMaster, &out.InitializeMaster
-		*out = new(bool)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.InitializeBackup != nil {
-		in, out := &in.InitializeBackup, &out.InitializeBackup
-		*out = new(bool)
-		**out = **in
+	if in.VttabletResources != nil {
+		in, out := &in.VttabletResources, &out.VttabletResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.RecoverRestartedMaster != nil {
-		in, out := &in.RecoverRestartedMaster, &out.RecoverRestartedMaster
-		*out = new(bool)
+	if in.MysqldResources != nil {
+		in, out := &in.MysqldResources, &out.MysqldResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MysqldConfigOverrides != nil {
+		in, out := &in.MysqldConfigOverrides, &out.MysqldConfigOverrides
+		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessReplicationSpec.
-func (in *VitessReplicationSpec) DeepCopy() *VitessReplicationSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolOverride.
+func (in *VitessTabletPoolOverride) DeepCopy() *VitessTabletPoolOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessReplicationSpec)
+	out := new(VitessTabletPoolOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShard) DeepCopyInto(out *VitessShard) {
+func (in *VitessTabletPoolResourceStatus) DeepCopyInto(out *VitessTabletPoolResourceStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.SampledSince.DeepCopyInto(&out.SampledSince)
+	if in.LastSampleTime != nil {
+		in, out := &in.LastSampleTime, &out.LastSampleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]VitessContainerResourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShard.
-func (in *VitessShard) DeepCopy() *VitessShard {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolResourceStatus.
+func (in *VitessTabletPoolResourceStatus) DeepCopy() *VitessTabletPoolResourceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShard)
+	out := new(VitessTabletPoolResourceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessShard) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessTabletPoolScaleProfileStatus) DeepCopyInto(out *VitessTabletPoolScaleProfileStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolScaleProfileStatus.
+func (in *VitessTabletPoolScaleProfileStatus) DeepCopy() *VitessTabletPoolScaleProfileStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(VitessTabletPoolScaleProfileStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardCondition) DeepCopyInto(out *VitessShardCondition) {
+func (in *VitessTabletPoolUpdateStrategy) DeepCopyInto(out *VitessTabletPoolUpdateStrategy) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardCondition.
-func (in *VitessShardCondition) DeepCopy() *VitessShardCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletPoolUpdateStrategy.
+func (in *VitessTabletPoolUpdateStrategy) DeepCopy() *VitessTabletPoolUpdateStrategy {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardCondition)
+	out := new(VitessTabletPoolUpdateStrategy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardList) DeepCopyInto(out *VitessShardList) {
+func (in *VitessTabletRestoreStatus) DeepCopyInto(out *VitessTabletRestoreStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VitessShard, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardList.
-func (in *VitessShardList) DeepCopy() *VitessShardList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletRestoreStatus.
+func (in *VitessTabletRestoreStatus) DeepCopy() *VitessTabletRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardList)
+	out := new(VitessTabletRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *VitessShardList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardSpec) DeepCopyInto(out *VitessShardSpec) {
+func (in *VitessTabletStatus) DeepCopyInto(out *VitessTabletStatus) {
 	*out = *in
-	in.VitessShardTemplate.DeepCopyInto(&out.VitessShardTemplate)
-	if in.ZoneMap != nil {
-		in, out := &in.ZoneMap, &out.ZoneMap
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Images.DeepCopyInto(&out.Images)
-	out.ImagePullPolicies = in.ImagePullPolicies
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(VitessTabletRestoreStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	out.KeyRange = in.KeyRange
-	out.GlobalLockserver = in.GlobalLockserver
-	if in.VitessOrchestrator != nil {
-		in, out := &in.VitessOrchestrator, &out.VitessOrchestrator
-		*out = new(VitessOrchestratorSpec)
+	if in.DataVolumeResize != nil {
+		in, out := &in.DataVolumeResize, &out.DataVolumeResize
+		*out = new(VitessTabletDataVolumeResizeStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.BackupLocations != nil {
-		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]VitessBackupLocation, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.DataVolumeMigration != nil {
+		in, out := &in.DataVolumeMigration, &out.DataVolumeMigration
+		*out = new(VitessTabletDataVolumeMigrationStatus)
+		**out = **in
 	}
-	if in.ExtraVitessFlags != nil {
-		in, out := &in.ExtraVitessFlags, &out.ExtraVitessFlags
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletStatus.
+func (in *VitessTabletStatus) DeepCopy() *VitessTabletStatus {
+	if in == nil {
+		return nil
 	}
-	if in.TopologyReconciliation != nil {
-		in, out := &in.TopologyReconciliation, &out.TopologyReconciliation
-		*out = new(TopoReconcileConfig)
-		(*in).DeepCopyInto(*out)
+	out := new(VitessTabletStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessTabletUpgradeStep) DeepCopyInto(out *VitessTabletUpgradeStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletUpgradeStep.
+func (in *VitessTabletUpgradeStep) DeepCopy() *VitessTabletUpgradeStep {
+	if in == nil {
+		return nil
 	}
-	if in.UpdateStrategy != nil {
-		in, out := &in.UpdateStrategy, &out.UpdateStrategy
-		*out = new(VitessClusterUpdateStrategy)
-		(*in).DeepCopyInto(*out)
+	out := new(VitessTabletUpgradeStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessThrottledApp) DeepCopyInto(out *VitessThrottledApp) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardSpec.
-func (in *VitessShardSpec) DeepCopy() *VitessShardSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessThrottledApp.
+func (in *VitessThrottledApp) DeepCopy() *VitessThrottledApp {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardSpec)
+	out := new(VitessThrottledApp)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardStatus) DeepCopyInto(out *VitessShardStatus) {
+func (in *VitessThrottlerSpec) DeepCopyInto(out *VitessThrottlerSpec) {
 	*out = *in
-	if in.Tablets != nil {
-		in, out := &in.Tablets, &out.Tablets
-		*out = make(map[string]VitessTabletStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.OrphanedTablets != nil {
-		in, out := &in.OrphanedTablets, &out.OrphanedTablets
-		*out = make(map[string]OrphanStatus, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Cells != nil {
-		in, out := &in.Cells, &out.Cells
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	out.VitessOrchestrator = in.VitessOrchestrator
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(map[VitessShardConditionType]VitessShardCondition, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.BackupLocations != nil {
-		in, out := &in.BackupLocations, &out.BackupLocations
-		*out = make([]*ShardBackupLocationStatus, len(*in))
+	if in.ThrottledApps != nil {
+		in, out := &in.ThrottledApps, &out.ThrottledApps
+		*out = make([]VitessThrottledApp, len(*in))
 		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(ShardBackupLocationStatus)
-				(*in).DeepCopyInto(*out)
-			}
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardStatus.
-func (in *VitessShardStatus) DeepCopy() *VitessShardStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessThrottlerSpec.
+func (in *VitessThrottlerSpec) DeepCopy() *VitessThrottlerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardStatus)
+	out := new(VitessThrottlerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardTabletPool) DeepCopyInto(out *VitessShardTabletPool) {
+func (in *VitessUpgradeChecks) DeepCopyInto(out *VitessUpgradeChecks) {
 	*out = *in
-	if in.DataVolumeClaimTemplate != nil {
-		in, out := &in.DataVolumeClaimTemplate, &out.DataVolumeClaimTemplate
-		*out = new(v1.PersistentVolumeClaimSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	in.Vttablet.DeepCopyInto(&out.Vttablet)
-	if in.Mysqld != nil {
-		in, out := &in.Mysqld, &out.Mysqld
-		*out = new(MysqldSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.MysqldExporter != nil {
-		in, out := &in.MysqldExporter, &out.MysqldExporter
-		*out = new(MysqldExporterSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ExternalDatastore != nil {
-		in, out := &in.ExternalDatastore, &out.ExternalDatastore
-		*out = new(ExternalDatastore)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MaxBackupAge != nil {
+		in, out := &in.MaxBackupAge, &out.MaxBackupAge
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.ExtraLabels != nil {
-		in, out := &in.ExtraLabels, &out.ExtraLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MaxReplicationLag != nil {
+		in, out := &in.MaxReplicationLag, &out.MaxReplicationLag
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.ExtraEnv != nil {
-		in, out := &in.ExtraEnv, &out.ExtraEnv
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MinHealthyReplicas != nil {
+		in, out := &in.MinHealthyReplicas, &out.MinHealthyReplicas
+		*out = new(int32)
+		**out = **in
 	}
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessUpgradeChecks.
+func (in *VitessUpgradeChecks) DeepCopy() *VitessUpgradeChecks {
+	if in == nil {
+		return nil
 	}
-	if in.ExtraVolumeMounts != nil {
-		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(VitessUpgradeChecks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessVDiffReport) DeepCopyInto(out *VitessVDiffReport) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.SidecarContainers != nil {
-		in, out := &in.SidecarContainers, &out.SidecarContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]VitessVDiffTableReport, len(*in))
+		copy(*out, *in)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessVDiffReport.
+func (in *VitessVDiffReport) DeepCopy() *VitessVDiffReport {
+	if in == nil {
+		return nil
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]v1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(VitessVDiffReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessVDiffSchedule) DeepCopyInto(out *VitessVDiffSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessVDiffSchedule.
+func (in *VitessVDiffSchedule) DeepCopy() *VitessVDiffSchedule {
+	if in == nil {
+		return nil
 	}
+	out := new(VitessVDiffSchedule)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTabletPool.
-func (in *VitessShardTabletPool) DeepCopy() *VitessShardTabletPool {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VitessVDiffTableReport) DeepCopyInto(out *VitessVDiffTableReport) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessVDiffTableReport.
+func (in *VitessVDiffTableReport) DeepCopy() *VitessVDiffTableReport {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardTabletPool)
+	out := new(VitessVDiffTableReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessShardTemplate) DeepCopyInto(out *VitessShardTemplate) {
+func (in *VitessZoneSpread) DeepCopyInto(out *VitessZoneSpread) {
 	*out = *in
-	if in.TabletPools != nil {
-		in, out := &in.TabletPools, &out.TabletPools
-		*out = make([]VitessShardTabletPool, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.MaxSkew != nil {
+		in, out := &in.MaxSkew, &out.MaxSkew
+		*out = new(int32)
+		**out = **in
 	}
-	out.DatabaseInitScriptSecret = in.DatabaseInitScriptSecret
-	in.Replication.DeepCopyInto(&out.Replication)
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.MinZones != nil {
+		in, out := &in.MinZones, &out.MinZones
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessShardTemplate.
-func (in *VitessShardTemplate) DeepCopy() *VitessShardTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessZoneSpread.
+func (in *VitessZoneSpread) DeepCopy() *VitessZoneSpread {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessShardTemplate)
+	out := new(VitessZoneSpread)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VitessTabletStatus) DeepCopyInto(out *VitessTabletStatus) {
+func (in *VtAdminIngress) DeepCopyInto(out *VtAdminIngress) {
 	*out = *in
+	if in.ClassName != nil {
+		in, out := &in.ClassName, &out.ClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VitessTabletStatus.
-func (in *VitessTabletStatus) DeepCopy() *VitessTabletStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VtAdminIngress.
+func (in *VtAdminIngress) DeepCopy() *VtAdminIngress {
 	if in == nil {
 		return nil
 	}
-	out := new(VitessTabletStatus)
+	out := new(VtAdminIngress)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2598,6 +6075,11 @@ func (in *VtAdminSpec) DeepCopyInto(out *VtAdminSpec) {
 		*out = new(ServiceOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(VtAdminIngress)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -2632,6 +6114,86 @@ func (in *VtadminStatus) DeepCopy() *VtadminStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletHotRowProtectionSpec) DeepCopyInto(out *VttabletHotRowProtectionSpec) {
+	*out = *in
+	if in.MaxQueueSize != nil {
+		in, out := &in.MaxQueueSize, &out.MaxQueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxGlobalQueueSize != nil {
+		in, out := &in.MaxGlobalQueueSize, &out.MaxGlobalQueueSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxConcurrency != nil {
+		in, out := &in.MaxConcurrency, &out.MaxConcurrency
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletHotRowProtectionSpec.
+func (in *VttabletHotRowProtectionSpec) DeepCopy() *VttabletHotRowProtectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletHotRowProtectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VttabletQueryServingSpec) DeepCopyInto(out *VttabletQueryServingSpec) {
+	*out = *in
+	if in.PoolSize != nil {
+		in, out := &in.PoolSize, &out.PoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StreamPoolSize != nil {
+		in, out := &in.StreamPoolSize, &out.StreamPoolSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TransactionCap != nil {
+		in, out := &in.TransactionCap, &out.TransactionCap
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueryTimeout != nil {
+		in, out := &in.QueryTimeout, &out.QueryTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TransactionTimeout != nil {
+		in, out := &in.TransactionTimeout, &out.TransactionTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxResultSize != nil {
+		in, out := &in.MaxResultSize, &out.MaxResultSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HotRowProtection != nil {
+		in, out := &in.HotRowProtection, &out.HotRowProtection
+		*out = new(VttabletHotRowProtectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletQueryServingSpec.
+func (in *VttabletQueryServingSpec) DeepCopy() *VttabletQueryServingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VttabletQueryServingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VttabletSpec) DeepCopyInto(out *VttabletSpec) {
 	*out = *in
@@ -2649,6 +6211,11 @@ func (in *VttabletSpec) DeepCopyInto(out *VttabletSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.QueryServing != nil {
+		in, out := &in.QueryServing, &out.QueryServing
+		*out = new(VttabletQueryServingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VttabletSpec.
@@ -175,6 +175,10 @@ func (r *ReconcileEtcdLockserver) Reconcile(cctx context.Context, request reconc
 	pdbResult, err := r.reconcilePodDisruptionBudget(ctx, ls)
 	resultBuilder.Merge(pdbResult, err)
 
+	// Take scheduled snapshots.
+	snapshotResult, err := r.reconcileSnapshots(ctx, ls, &oldStatus)
+	resultBuilder.Merge(snapshotResult, err)
+
 	// Update status if needed.
 	ls.Status.ObservedGeneration = ls.Generation
 	if !apiequality.Semantic.DeepEqual(&ls.Status, &oldStatus) {
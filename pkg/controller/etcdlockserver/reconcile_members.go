@@ -28,6 +28,7 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/etcd"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
@@ -58,6 +59,11 @@ func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *plan
 		memberMap[key] = member
 	}
 
+	// Decide which members to seed from a snapshot.
+	if err := r.setMemberRestores(ctx, ls, labels, memberMap); err != nil {
+		return resultBuilder.Error(err)
+	}
+
 	// Reconcile member PVCs. Note that we use the same keys as the corresponding Pods.
 	err := r.reconciler.ReconcileObjectSet(ctx, ls, keys, labels, reconciler.Strategy{
 		Kind: &corev1.PersistentVolumeClaim{},
@@ -116,6 +122,58 @@ func (r *ReconcileEtcdLockserver) reconcileMembers(ctx context.Context, ls *plan
 	return resultBuilder.Result()
 }
 
+// setMemberRestores sets which members should be seeded from a snapshot.
+//
+// A snapshot is only restored when there are no member PVCs at all, since a
+// member that's replaced on its own must rejoin the existing members instead.
+// The PVCs created for a restore are annotated, so members keep trying to
+// restore if their Pods are recreated before they're done.
+func (r *ReconcileEtcdLockserver) setMemberRestores(ctx context.Context, ls *planetscalev2.EtcdLockserver, labels map[string]string, memberMap map[client.ObjectKey]*etcd.Spec) error {
+	restore := ls.Spec.Restore
+	if restore == nil {
+		return nil
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := r.client.List(ctx, pvcList, client.InNamespace(ls.Namespace), client.MatchingLabels(labels)); err != nil {
+		return err
+	}
+	annotationValue := etcd.RestoreAnnotationValue(restore.Directory, restore.Name)
+	existing := make(map[client.ObjectKey]bool, len(pvcList.Items))
+	restoring := make(map[client.ObjectKey]bool, len(pvcList.Items))
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		key := client.ObjectKey{Namespace: pvc.Namespace, Name: pvc.Name}
+		existing[key] = true
+		restoring[key] = pvc.Annotations[etcd.RestoreAnnotation] == annotationValue
+	}
+
+	var operatorImage string
+	for key, member := range memberMap {
+		if len(existing) > 0 && !restoring[key] {
+			continue
+		}
+		// The restore tool is part of the operator binary, so the member
+		// needs the image we're running in.
+		if operatorImage == "" {
+			var err error
+			operatorImage, err = fork.OperatorImage(ctx, r.client)
+			if err != nil {
+				r.recorder.Eventf(ls, corev1.EventTypeWarning, "RestoreFailed", "can't restore etcd snapshot: %v", err)
+				return err
+			}
+		}
+		member.Restore = &etcd.RestoreSpec{
+			Location:      &restore.Location,
+			ClusterName:   snapshotClusterName(ls),
+			Directory:     restore.Directory,
+			Name:          restore.Name,
+			OperatorImage: operatorImage,
+		}
+	}
+	return nil
+}
+
 // memberSpecs creates a list of etcd.Specs for desired members.
 func memberSpecs(ls *planetscalev2.EtcdLockserver, parentLabels map[string]string) []*etcd.Spec {
 	members := make([]*etcd.Spec, 0, etcd.NumReplicas)
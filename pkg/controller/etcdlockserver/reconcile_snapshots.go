@@ -0,0 +1,172 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdlockserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/etcd"
+	"planetscale.dev/vitess-operator/pkg/operator/etcdbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	// snapshotRetryDelay is how long to keep a failed snapshot Pod around
+	// for inspection before trying again.
+	snapshotRetryDelay = 10 * time.Minute
+)
+
+// reconcileSnapshots creates a snapshot Pod when the next scheduled snapshot
+// is due, and records the outcome in the status.
+//
+// The Pod name includes the scheduled time of the snapshot. Once it succeeds,
+// the status moves on to that time, so the Pod is no longer wanted and gets
+// cleaned up until the next scheduled time comes around.
+func (r *ReconcileEtcdLockserver) reconcileSnapshots(ctx context.Context, ls *planetscalev2.EtcdLockserver, oldStatus *planetscalev2.EtcdLockserverStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	labels := map[string]string{
+		etcd.SnapshotLabel: ls.Name,
+	}
+
+	var keys []client.ObjectKey
+	var spec *etcd.SnapshotSpec
+
+	// If snapshots are turned off, we still reconcile the (now empty) set of
+	// snapshot Pods below, so old ones get cleaned up.
+	if backup := ls.Spec.Backup; backup != nil {
+		status := oldStatus.Backup.DeepCopy()
+		if status == nil {
+			status = &planetscalev2.EtcdLockserverBackupStatus{}
+		}
+		ls.Status.Backup = status
+
+		schedule, err := cron.ParseStandard(backup.Schedule)
+		if err != nil {
+			r.recorder.Eventf(ls, corev1.EventTypeWarning, "InvalidSnapshotSchedule", "failed to parse snapshot schedule %q: %v", backup.Schedule, err)
+			return resultBuilder.Result()
+		}
+
+		lastScheduleTime := ls.CreationTimestamp.Time
+		if status.LastScheduleTime != nil {
+			lastScheduleTime = status.LastScheduleTime.Time
+		}
+		scheduleTime, wait := snapshotDue(schedule, lastScheduleTime, time.Now())
+		resultBuilder.RequeueAfter(wait)
+
+		if !scheduleTime.IsZero() {
+			// The snapshot tool is part of the operator binary, so the Pod
+			// needs the image we're running in.
+			operatorImage, err := fork.OperatorImage(ctx, r.client)
+			if err != nil {
+				r.recorder.Eventf(ls, corev1.EventTypeWarning, "SnapshotFailed", "can't take etcd snapshot: %v", err)
+				return resultBuilder.Error(err)
+			}
+			keys = append(keys, client.ObjectKey{
+				Namespace: ls.Namespace,
+				Name:      etcd.SnapshotPodName(ls.Name, scheduleTime),
+			})
+			spec = &etcd.SnapshotSpec{
+				LockserverName:  ls.Name,
+				ClusterName:     snapshotClusterName(ls),
+				Location:        &backup.Location,
+				SnapshotsToKeep: *backup.SnapshotsToKeep,
+				ScheduleTime:    scheduleTime,
+				OperatorImage:   operatorImage,
+				Labels:          labels,
+			}
+		}
+	}
+
+	now := time.Now()
+	err := r.reconciler.ReconcileObjectSet(ctx, ls, keys, labels, reconciler.Strategy{
+		Kind: &corev1.Pod{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return etcd.NewSnapshotPod(key, spec)
+		},
+		Status: func(key client.ObjectKey, obj runtime.Object) {
+			pod := obj.(*corev1.Pod)
+			status := ls.Status.Backup
+
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded:
+				status.LastScheduleTime = &metav1.Time{Time: spec.ScheduleTime}
+				status.LastSnapshotDirectory = etcdbackup.SnapshotDir(ls.Name)
+				status.LastSnapshotName = pod.Annotations[etcd.SnapshotNameAnnotation]
+				status.FailureMessage = ""
+				r.recorder.Eventf(ls, corev1.EventTypeNormal, "SnapshotTaken", "took etcd snapshot %v/%v", status.LastSnapshotDirectory, status.LastSnapshotName)
+			case corev1.PodFailed:
+				finishedTime, message := k8s.PodTermination(pod)
+				if status.FailureMessage != message {
+					r.recorder.Eventf(ls, corev1.EventTypeWarning, "SnapshotFailed", "failed to take etcd snapshot: %v", message)
+				}
+				status.FailureMessage = message
+
+				// Keep the failed Pod for a while, then delete it so a new
+				// one gets created on the next pass.
+				if wait := finishedTime.Add(snapshotRetryDelay).Sub(now); wait > 0 {
+					resultBuilder.RequeueAfter(wait)
+					return
+				}
+				if err := r.client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+					resultBuilder.Error(err)
+					return
+				}
+				resultBuilder.RequeueAfter(time.Second)
+			}
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// snapshotDue returns the latest time a snapshot was scheduled for since the
+// last one, or the zero time if none are due yet, along with how long to wait
+// before the next one is due.
+func snapshotDue(schedule cron.Schedule, lastScheduleTime, now time.Time) (time.Time, time.Duration) {
+	// Skip over any snapshots we missed, so we only take the latest one.
+	var due time.Time
+	for next := schedule.Next(lastScheduleTime); !next.After(now); next = schedule.Next(next) {
+		due = next
+	}
+	return due, schedule.Next(now).Sub(now)
+}
+
+// snapshotClusterName returns the cluster name that scopes where snapshots
+// are kept in backup storage, the same way it does for Vitess backups.
+func snapshotClusterName(ls *planetscalev2.EtcdLockserver) string {
+	if clusterName := ls.Labels[planetscalev2.ClusterLabel]; clusterName != "" {
+		return clusterName
+	}
+	return ls.Name
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdlockserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotDue(t *testing.T) {
+	// Every hour on the hour.
+	schedule, err := cron.ParseStandard("0 * * * *")
+	require.NoError(t, err)
+
+	lastScheduleTime := time.Date(2024, 3, 1, 3, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantDue  time.Time
+		wantWait time.Duration
+	}{
+		{
+			name:     "before next scheduled time",
+			now:      time.Date(2024, 3, 1, 3, 20, 0, 0, time.Local),
+			wantWait: 40 * time.Minute,
+		},
+		{
+			name:     "at next scheduled time",
+			now:      time.Date(2024, 3, 1, 4, 0, 0, 0, time.Local),
+			wantDue:  time.Date(2024, 3, 1, 4, 0, 0, 0, time.Local),
+			wantWait: time.Hour,
+		},
+		{
+			name:     "missed several scheduled times",
+			now:      time.Date(2024, 3, 1, 7, 30, 0, 0, time.Local),
+			wantDue:  time.Date(2024, 3, 1, 7, 0, 0, 0, time.Local),
+			wantWait: 30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, wait := snapshotDue(schedule, lastScheduleTime, tt.now)
+			assert.True(t, tt.wantDue.Equal(due), "due = %v; want %v", due, tt.wantDue)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package nodedrain implements a controller that watches Nodes, and starts drains
+on any vttablet Pods running on a Node that is being taken out of service, as
+indicated by the Node being cordoned or receiving certain taints.
+
+This completes the drain loop for Node maintenance without requiring an
+external drainer to set drain annotations. The controller is disabled unless
+at least one of the --node_drain_on_cordon or --node_drain_taints flags is set.
+*/
+package nodedrain
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	controllerName = "nodedrain-controller"
+)
+
+var (
+	maxConcurrentReconciles = flag.Int("nodedrain_concurrent_reconciles", 10, "the maximum number of different nodes to reconcile concurrently")
+	drainOnCordon           = flag.Bool("node_drain_on_cordon", false, "start drains on vttablet Pods running on a Node when the Node is cordoned (marked unschedulable)")
+	drainTaints             = flag.String("node_drain_taints", "", "comma-separated list of taint keys; start drains on vttablet Pods running on a Node when the Node has any of these taints")
+)
+
+var log = logrus.WithField("controller", "NodeDrain")
+
+// Add creates a new NodeDrain Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r := newReconciler(mgr)
+	if !r.enabled() {
+		// Don't watch Nodes at all unless we need to, since that requires
+		// extra permissions.
+		return nil
+	}
+	return add(mgr, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileNode {
+	var taints []string
+	for _, taint := range strings.Split(*drainTaints, ",") {
+		if taint = strings.TrimSpace(taint); taint != "" {
+			taints = append(taints, taint)
+		}
+	}
+
+	return &ReconcileNode{
+		client:        mgr.GetClient(),
+		recorder:      mgr.GetEventRecorderFor(controllerName),
+		drainOnCordon: *drainOnCordon,
+		drainTaints:   taints,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileNode) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Nodes.
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Node{}), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileNode{}
+
+// ReconcileNode reconciles a Node object
+type ReconcileNode struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	recorder record.EventRecorder
+
+	drainOnCordon bool
+	drainTaints   []string
+}
+
+func (r *ReconcileNode) enabled() bool {
+	return r.drainOnCordon || len(r.drainTaints) > 0
+}
+
+// Reconcile starts drains on all vttablet Pods running on a Node, if the Node
+// is being taken out of service.
+func (r *ReconcileNode) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+	defer cancel()
+
+	resultBuilder := &results.Builder{}
+
+	log := log.WithField("node", request.Name)
+	log.Debug("Reconciling Node")
+
+	// Fetch the Node instance
+	node := &corev1.Node{}
+	err := r.client.Get(ctx, request.NamespacedName, node)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return resultBuilder.Result()
+		}
+		// Error reading the object - requeue the request.
+		return resultBuilder.Error(err)
+	}
+
+	reason := r.drainReason(node)
+	if reason == "" {
+		return resultBuilder.Result()
+	}
+
+	// Find all vttablet Pods on this Node.
+	podList := &corev1.PodList{}
+	listOpts := []client.ListOption{
+		client.MatchingLabels{planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName},
+	}
+	if err := r.client.List(ctx, podList, listOpts...); err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName != node.Name || !drain.Supported(pod) || drain.Started(pod) {
+			continue
+		}
+		drain.Start(pod, reason)
+		if err := r.client.Update(ctx, pod); err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "UpdateFailed", "failed to start drain: %v", err)
+			resultBuilder.Error(err)
+			continue
+		}
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "DrainStarted", "started drain: %v", reason)
+		log.Infof("started drain on Pod %v/%v: %v", pod.Namespace, pod.Name, reason)
+	}
+
+	return resultBuilder.Result()
+}
+
+// drainReason returns a human-readable reason why Pods on the Node should be
+// drained, or an empty string if they shouldn't be.
+func (r *ReconcileNode) drainReason(node *corev1.Node) string {
+	if r.drainOnCordon && node.Spec.Unschedulable {
+		return fmt.Sprintf("node %v is cordoned", node.Name)
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range r.drainTaints {
+			if taint.Key == key {
+				return fmt.Sprintf("node %v has taint %v", node.Name, key)
+			}
+		}
+	}
+	return ""
+}
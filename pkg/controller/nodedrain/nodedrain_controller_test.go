@@ -0,0 +1,72 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodedrain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDrainReason(t *testing.T) {
+	node := func(unschedulable bool, taints ...string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+		n.Spec.Unschedulable = unschedulable
+		for _, key := range taints {
+			n.Spec.Taints = append(n.Spec.Taints, corev1.Taint{Key: key, Effect: corev1.TaintEffectNoSchedule})
+		}
+		return n
+	}
+
+	tests := []struct {
+		name       string
+		r          *ReconcileNode
+		node       *corev1.Node
+		wantReason string
+	}{
+		{
+			name:       "cordoned",
+			r:          &ReconcileNode{drainOnCordon: true},
+			node:       node(true),
+			wantReason: "node node1 is cordoned",
+		},
+		{
+			name: "cordoned but disabled",
+			r:    &ReconcileNode{drainTaints: []string{"maintenance"}},
+			node: node(true),
+		},
+		{
+			name:       "matching taint",
+			r:          &ReconcileNode{drainTaints: []string{"other", "maintenance"}},
+			node:       node(false, "maintenance"),
+			wantReason: "node node1 has taint maintenance",
+		},
+		{
+			name: "other taint",
+			r:    &ReconcileNode{drainOnCordon: true, drainTaints: []string{"maintenance"}},
+			node: node(false, "something-else"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantReason, tt.r.drainReason(tt.node))
+		})
+	}
+}
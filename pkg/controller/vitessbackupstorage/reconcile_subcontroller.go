@@ -41,10 +41,6 @@ import (
 )
 
 const (
-	// operatorContainerNameSubstring needs to be contained within the name of
-	// the main container in deploy/operator.yaml.
-	operatorContainerNameSubstring = "-operator"
-
 	vitessHomeDir = "/home/vitess"
 
 	subcontrollerCPUMillis   = 100
@@ -118,13 +114,13 @@ func (r *ReconcileVitessBackupStorage) newSubcontrollerPodSpec(ctx context.Conte
 	// Find the main operator container.
 	var container *corev1.Container
 	for i := range spec.Containers {
-		if strings.Contains(spec.Containers[i].Name, operatorContainerNameSubstring) {
+		if strings.Contains(spec.Containers[i].Name, fork.OperatorContainerNameSubstring) {
 			container = &spec.Containers[i]
 			break
 		}
 	}
 	if container == nil {
-		return nil, fmt.Errorf("can't find operator container (name containing %q) in my own Pod", operatorContainerNameSubstring)
+		return nil, fmt.Errorf("can't find operator container (name containing %q) in my own Pod", fork.OperatorContainerNameSubstring)
 	}
 
 	// Filter out the service account token (volume and mounts) and let the
@@ -33,10 +33,18 @@ var (
 		Name:      "reconcile_count",
 		Help:      "Reconciliation attempts for a VitessBackupStorage",
 	}, []string{metrics.BackupStorageLabel, metrics.ResultLabel})
+
+	prunedBackupCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "pruned_backup_count",
+		Help:      "Backups deleted from a VitessBackupStorage for falling outside its retention limits",
+	}, []string{metrics.BackupStorageLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
+		prunedBackupCount,
 	)
 }
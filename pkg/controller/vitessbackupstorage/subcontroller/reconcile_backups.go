@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -48,9 +49,13 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 	clusterName := vbs.Labels[planetscalev2.ClusterLabel]
 	backupLocationName := vbs.Spec.Location.Name
 
+	// Keyspaces can override a location with one of their own, so more than
+	// one VBS object may share a location name. The storage label keeps each
+	// one from touching the VitessBackup objects of the others.
 	parentLabels := map[string]string{
 		planetscalev2.ClusterLabel: clusterName,
 		vitessbackup.LocationLabel: backupLocationName,
+		vitessbackup.StorageLabel:  vbs.Name,
 	}
 
 	// Make a list of all desired VitessBackup object keys.
@@ -60,11 +65,16 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 	// Keep a map from object key to Vitess BackupHandle.
 	backupHandles := map[client.ObjectKey]backupstorage.BackupHandle{}
 
-	// List VitessShard objects for this cluster.
+	// List VitessShard objects for this cluster, or only for one keyspace
+	// if this location is overridden by that keyspace.
 	shardList := &planetscalev2.VitessShardList{}
 	clusterLabels := apilabels.Set{
 		planetscalev2.ClusterLabel: clusterName,
 	}
+	if vbs.Spec.Keyspace != "" {
+		clusterLabels[planetscalev2.KeyspaceLabel] = vbs.Spec.Keyspace
+	}
+	excludedKeyspaces := sets.New(vbs.Spec.ExcludedKeyspaces...)
 	listOpts := &client.ListOptions{
 		Namespace:     vbs.Namespace,
 		LabelSelector: apilabels.SelectorFromSet(clusterLabels),
@@ -82,10 +92,31 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 	}
 	defer backupStorage.Close()
 
+	// If old backups should be pruned, we need to know which ones are
+	// complete, which we only learn by checking on them in past passes.
+	knownBackups := map[string]*planetscalev2.VitessBackup{}
+	if vbs.Spec.Retention != nil {
+		existing := &planetscalev2.VitessBackupList{}
+		if err := r.client.List(ctx, existing, &client.ListOptions{
+			Namespace:     vbs.Namespace,
+			LabelSelector: apilabels.SelectorFromSet(parentLabels),
+		}); err != nil {
+			r.recorder.Eventf(vbs, corev1.EventTypeWarning, "ListFailed", "failed to list backups: %v", err)
+			return resultBuilder.Error(err)
+		}
+		for i := range existing.Items {
+			knownBackups[existing.Items[i].Name] = &existing.Items[i]
+		}
+	}
+	now := time.Now()
+
 	// List backups for each shard in this storage location.
 	for i := range shardList.Items {
 		shard := &shardList.Items[i]
 		keyspaceName := shard.Labels[planetscalev2.KeyspaceLabel]
+		if excludedKeyspaces.Has(keyspaceName) {
+			continue
+		}
 
 		// Note that this we don't include the cluster prefix. That's added
 		// automatically by the backup storage client, based on flags that
@@ -108,6 +139,7 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 		}
 
 		// For each backup, generate a VitessBackup object.
+		shardKeys := []client.ObjectKey{}
 		for _, backup := range backups {
 			// Unfortunately, the backup time is not stored anywhere except
 			// the name, so we have to parse it.
@@ -121,7 +153,7 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 				Namespace: vbs.Namespace,
 				Name:      vitessbackup.ObjectName(clusterName, backupLocationName, keyspaceName, shard.Spec.KeyRange, backupTime, tabletAlias),
 			}
-			keys = append(keys, key)
+			shardKeys = append(shardKeys, key)
 			backupHandles[key] = backup
 			backupObjects[key] = &planetscalev2.VitessBackup{
 				ObjectMeta: metav1.ObjectMeta{
@@ -133,6 +165,42 @@ func (r *ReconcileVitessBackupStorage) reconcileBackups(ctx context.Context, vbs
 					StorageName:      backup.Name(),
 				},
 			}
+		}
+
+		// Delete backups that fall outside the retention limits. We only
+		// consider backups whose VitessBackup objects already exist, since
+		// we don't know yet whether any others are complete.
+		expiredKeys := map[client.ObjectKey]bool{}
+		if vbs.Spec.Retention != nil {
+			candidates := []*planetscalev2.VitessBackup{}
+			candidateKeys := map[*planetscalev2.VitessBackup]client.ObjectKey{}
+			for _, key := range shardKeys {
+				known := knownBackups[key.Name]
+				if known == nil {
+					continue
+				}
+				candidates = append(candidates, known)
+				candidateKeys[known] = key
+			}
+			for _, expired := range vitessbackup.ExpiredBackups(candidates, vbs.Spec.Retention, now) {
+				if err := backupStorage.RemoveBackup(ctx, backupDir, expired.Status.StorageName); err != nil {
+					r.recorder.Eventf(vbs, corev1.EventTypeWarning, "PruneFailed", "failed to delete expired backup %v/%v: %v", backupDir, expired.Status.StorageName, err)
+					resultBuilder.Error(err)
+					continue
+				}
+				r.recorder.Eventf(vbs, corev1.EventTypeNormal, "BackupPruned", "deleted expired backup %v/%v", backupDir, expired.Status.StorageName)
+				prunedBackupCount.WithLabelValues(vbs.Name).Inc()
+				expiredKeys[candidateKeys[expired]] = true
+			}
+		}
+
+		// Any VitessBackup objects for deleted backups will be cleaned up
+		// when we reconcile the set below.
+		for _, key := range shardKeys {
+			if expiredKeys[key] {
+				continue
+			}
+			keys = append(keys, key)
 			vbs.Status.TotalBackupCount++
 		}
 	}
@@ -191,8 +259,11 @@ func updateBackupStatus(ctx context.Context, vb *planetscalev2.VitessBackup, bac
 	vb.Status.Complete = true
 	vb.Status.Position = manifest.Position.String()
 	vb.Status.Engine = manifest.BackupMethod
+	vb.Status.SourceTablet = manifest.TabletAlias
+	vb.Status.MySQLVersion = manifest.MySQLVersion
 	if finishedTime, err := time.Parse(time.RFC3339, manifest.FinishedTime); err == nil {
 		vb.Status.FinishedTime = &metav1.Time{Time: finishedTime}
+		vb.Status.Duration = &metav1.Duration{Duration: finishedTime.Sub(vb.Status.StartTime.Time)}
 	} else {
 		log.Warningf("Can't parse FinishedTime from MANIFEST of backup %v/%v: %v", backup.Directory(), backup.Name(), err)
 	}
@@ -56,35 +56,12 @@ func keyspaceCellsMapper(ctx context.Context, obj client.Object) []reconcile.Req
 func (r *ReconcileVitessCell) reconcileKeyspaces(ctx context.Context, vtc *planetscalev2.VitessCell) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
-	// List all keyspaces in the same cluster.
-	// Note that this is cheap because it comes from the local cache.
-	labels := map[string]string{
-		planetscalev2.ClusterLabel: vtc.Labels[planetscalev2.ClusterLabel],
-	}
-	opts := &client.ListOptions{
-		Namespace:     vtc.Namespace,
-		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
-	}
-	list := &planetscalev2.VitessKeyspaceList{}
-	if err := r.client.List(ctx, list, opts); err != nil {
+	keyspaces, err := r.cellKeyspaces(ctx, vtc)
+	if err != nil {
 		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "ListFailed", "failed to list VitessKeyspace objects: %v", err)
 		return resultBuilder.Error(err)
 	}
 
-	// Find the keyspaces deployed in this cell.
-	var keyspaces []*planetscalev2.VitessKeyspace
-	for vtkIndex := range list.Items {
-		vtk := &list.Items[vtkIndex]
-		// Is the keyspace deployed in this cell?
-		for _, cellName := range vtk.Spec.CellNames() {
-			if cellName == vtc.Spec.Name {
-				// Yes, it's deployed here.
-				keyspaces = append(keyspaces, vtk)
-				break
-			}
-		}
-	}
-
 	// Record status for the keyspaces deployed in this cell.
 	for _, vtk := range keyspaces {
 		// TODO(enisoc): Fill in status fields when VitessKeyspace has status.
@@ -138,3 +115,35 @@ func (r *ReconcileVitessCell) reconcileKeyspaces(ctx context.Context, vtc *plane
 
 	return resultBuilder.Result()
 }
+
+// cellKeyspaces returns the keyspaces that are deployed in a cell.
+func (r *ReconcileVitessCell) cellKeyspaces(ctx context.Context, vtc *planetscalev2.VitessCell) ([]*planetscalev2.VitessKeyspace, error) {
+	// List all keyspaces in the same cluster.
+	// Note that this is cheap because it comes from the local cache.
+	labels := map[string]string{
+		planetscalev2.ClusterLabel: vtc.Labels[planetscalev2.ClusterLabel],
+	}
+	opts := &client.ListOptions{
+		Namespace:     vtc.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	list := &planetscalev2.VitessKeyspaceList{}
+	if err := r.client.List(ctx, list, opts); err != nil {
+		return nil, err
+	}
+
+	// Find the keyspaces deployed in this cell.
+	var keyspaces []*planetscalev2.VitessKeyspace
+	for vtkIndex := range list.Items {
+		vtk := &list.Items[vtkIndex]
+		// Is the keyspace deployed in this cell?
+		for _, cellName := range vtk.Spec.CellNames() {
+			if cellName == vtc.Spec.Name {
+				// Yes, it's deployed here.
+				keyspaces = append(keyspaces, vtk)
+				break
+			}
+		}
+	}
+	return keyspaces, nil
+}
@@ -18,9 +18,14 @@ package vitesscell
 
 import (
 	"context"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,13 +33,22 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/pdb"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/scaleprofile"
 	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
 )
 
+const (
+	// scaleProfileCheckInterval is how often to check whether a vtgate scale
+	// profile has started or ended.
+	scaleProfileCheckInterval = time.Minute
+)
+
 type secretCellsMapper struct {
 	client client.Client
 }
@@ -59,7 +73,7 @@ func (m *secretCellsMapper) Map(ctx context.Context, obj client.Object) []reconc
 	var requests []reconcile.Request
 	for i := range cellList.Items {
 		cell := &cellList.Items[i]
-		if cell.Spec.Gateway.ReloadSecretNames().Has(secretName) {
+		if cell.Spec.Gateway.ReloadSecretNames().Has(secretName) || cell.Spec.Gateway.StaticAuthSecretName() == secretName {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: apitypes.NamespacedName{
 					Namespace: cell.Namespace,
@@ -88,12 +102,14 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 
 		New: func(key client.ObjectKey) runtime.Object {
 			svc := vtgate.NewService(key, labels)
+			vtgate.UpdateServiceExposure(svc, vtc.Spec.Gateway.Exposure)
 			update.ServiceOverrides(svc, vtc.Spec.Gateway.Service)
 			return svc
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			svc := obj.(*corev1.Service)
 			vtgate.UpdateService(svc, labels)
+			vtgate.UpdateServiceExposure(svc, vtc.Spec.Gateway.Exposure)
 			update.InPlaceServiceOverrides(svc, vtc.Spec.Gateway.Service)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
@@ -106,12 +122,30 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 		resultBuilder.Error(err)
 	}
 
+	// Reconcile the Gateway API routes to the vtgate Service, if any.
+	if err := r.reconcileVtgateRoutes(ctx, vtc, key, labels); err != nil {
+		// Record error but continue.
+		resultBuilder.Error(err)
+	}
+
+	ldapConfigSecret, err := r.reconcileVtgateAuth(ctx, vtc, clusterName, labels)
+	if err != nil {
+		// Record error and return, to avoid generating a Deployment based on incomplete information.
+		return resultBuilder.Error(err)
+	}
+
 	reloadSecretNames := vtc.Spec.Gateway.ReloadSecretNames()
 	gatewaySecrets, err := secrets.GetByNames(ctx, r.client, vtc.Namespace, reloadSecretNames)
 	if err != nil {
 		// Record error and return, to avoid generating a Deployment based on incomplete information.
 		return resultBuilder.Error(err)
 	}
+	var ldapConfigSecretName string
+	if ldapConfigSecret != nil {
+		// Restart vtgate when the rendered LDAP config changes.
+		gatewaySecrets = append(gatewaySecrets, ldapConfigSecret)
+		ldapConfigSecretName = ldapConfigSecret.Name
+	}
 
 	annotations := map[string]string{
 		"planetscale.com/secret-hash": secrets.ContentHash(gatewaySecrets...),
@@ -123,11 +157,24 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 	update.StringMap(&extraFlags, vtc.Spec.ExtraVitessFlags)
 	update.StringMap(&extraFlags, vtc.Spec.Gateway.ExtraFlags)
 
+	// Apply the scale profile in effect, if any.
+	replicas, autoscaler := r.gatewayScale(vtc, time.Now())
+	if len(vtc.Spec.Gateway.ScaleProfiles) > 0 {
+		// Check again soon, since profiles come and go with the clock.
+		resultBuilder.RequeueAfter(scaleProfileCheckInterval)
+	}
+
+	// Spread vtgate Pods across zones, if requested.
+	topologySpreadConstraints := vtc.Spec.Gateway.TopologySpreadConstraints
+	if zoneSpread := vtc.Spec.Gateway.ZoneSpread; zoneSpread != nil {
+		topologySpreadConstraints = append(append([]corev1.TopologySpreadConstraint{}, topologySpreadConstraints...), zoneSpread.TopologySpreadConstraint(labels))
+	}
+
 	// Reconcile vtgate Deployment.
 	spec := &vtgate.Spec{
 		Cell:                          &vtc.Spec,
 		Labels:                        labels,
-		Replicas:                      *vtc.Spec.Gateway.Replicas,
+		Replicas:                      replicas,
 		Resources:                     vtc.Spec.Gateway.Resources,
 		Authentication:                &vtc.Spec.Gateway.Authentication,
 		SecureTransport:               vtc.Spec.Gateway.SecureTransport,
@@ -141,10 +188,89 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 		Annotations:                   annotations,
 		ExtraLabels:                   vtc.Spec.Gateway.ExtraLabels,
 		Tolerations:                   vtc.Spec.Gateway.Tolerations,
-		TopologySpreadConstraints:     vtc.Spec.Gateway.TopologySpreadConstraints,
+		TopologySpreadConstraints:     topologySpreadConstraints,
 		Lifecycle:                     vtc.Spec.Gateway.Lifecycle,
 		TerminationGracePeriodSeconds: vtc.Spec.Gateway.TerminationGracePeriodSeconds,
+		LDAPConfigSecretName:          ldapConfigSecretName,
+	}
+	if autoscaler != nil {
+		spec.DrainTimeout = autoscaler.DrainTimeout.Duration
+	}
+	if lameduck := vtc.Spec.Gateway.Lameduck; lameduck != nil {
+		if lameduck.DrainTimeout != nil {
+			spec.DrainTimeout = lameduck.DrainTimeout.Duration
+		}
+		if lameduck.Period != nil {
+			spec.LameduckPeriod = lameduck.Period.Duration
+		}
+	}
+	if vtc.Spec.Gateway.TerminationDrainDuration != nil {
+		spec.TerminationDrainDuration = vtc.Spec.Gateway.TerminationDrainDuration.Duration
+	}
+	spec.ServingGate = vtc.Spec.Gateway.ServingGate != nil
+
+	// Report the vtgate Pods through the VitessCell scale subresource.
+	vtc.Status.Gateway.LabelSelector = apilabels.SelectorFromSet(labels).String()
+
+	// Reconcile the vtgate PDB, which covers both Deployments during a
+	// blue/green rollout.
+	pdbKey := client.ObjectKey{Namespace: vtc.Namespace, Name: vtgate.DeploymentName(clusterName, vtc.Spec.Name)}
+	pdbSpec := &pdb.Spec{
+		Labels:   labels,
+		Selector: &metav1.LabelSelector{MatchLabels: labels},
+		Replicas: replicas,
+		Config:   vtc.Spec.Gateway.PodDisruptionBudget,
+	}
+	err = r.reconciler.ReconcileObject(ctx, vtc, pdbKey, labels, pdb.Wanted(pdbSpec.Config), reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return pdb.NewPodDisruptionBudget(key, pdbSpec)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			pdb.UpdatePodDisruptionBudget(obj.(*policyv1.PodDisruptionBudget), pdbSpec)
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Reconcile the vtgate HPA, if autoscaling is enabled. It's named after
+	// the Deployment, just like the PDB.
+	hpaSpec := &vtgate.AutoscalerSpec{
+		Labels:         labels,
+		CellObjectName: vtc.Name,
+		Config:         autoscaler,
+	}
+	err = r.reconciler.ReconcileObject(ctx, vtc, pdbKey, labels, hpaSpec.Config != nil, reconciler.Strategy{
+		Kind: &autoscalingv2.HorizontalPodAutoscaler{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vtgate.NewHorizontalPodAutoscaler(key, hpaSpec)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			vtgate.UpdateHorizontalPodAutoscaler(obj.(*autoscalingv2.HorizontalPodAutoscaler), hpaSpec)
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Roll out rotated static auth credentials without restarting vtgate.
+	staticAuthResult, err := r.reconcileVtgateStaticAuth(ctx, vtc, labels)
+	resultBuilder.Merge(staticAuthResult, err)
+
+	// Let new vtgate Pods serve once enough shards are healthy.
+	servingGateResult, err := r.reconcileVtgateServingGate(ctx, vtc, labels)
+	resultBuilder.Merge(servingGateResult, err)
+
+	if blueGreen := vtc.Spec.Gateway.BlueGreen(); blueGreen != nil {
+		blueGreenResult, err := r.reconcileVtgateBlueGreen(ctx, vtc, spec, blueGreen)
+		resultBuilder.Merge(blueGreenResult, err)
+		return resultBuilder.Result()
 	}
+	vtc.Status.Gateway.BlueGreen = nil
+
 	key = client.ObjectKey{Namespace: vtc.Namespace, Name: vtgate.DeploymentName(clusterName, vtc.Spec.Name)}
 
 	err = r.reconciler.ReconcileObject(ctx, vtc, key, labels, true, reconciler.Strategy{
@@ -164,11 +290,63 @@ func (r *ReconcileVitessCell) reconcileVtgate(ctx context.Context, vtc *planetsc
 			if available := conditions.Deployment(curObj.Status.Conditions, appsv1.DeploymentAvailable); available != nil {
 				status.Available = available.Status
 			}
+			status.Rollout = rollout.DeploymentProgress(curObj)
+			status.Replicas = curObj.Status.Replicas
 		},
 	})
 	if err != nil {
 		resultBuilder.Error(err)
 	}
 
+	// Clean up after any earlier blue/green rollouts once the Deployment that
+	// takes over from them is available.
+	if vtc.Status.Gateway.Available == corev1.ConditionTrue {
+		if err := r.deleteVtgateDeployment(ctx, vtc, spec, vtgate.AlternateDeploymentName(clusterName, vtc.Spec.Name)); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
 	return resultBuilder.Result()
 }
+
+// gatewayScale returns the number of vtgate replicas and the autoscaler
+// config to use, given the scale profile in effect, if any. With autoscaling
+// enabled, the profile raises the autoscaler's MinReplicas instead, up to its
+// MaxReplicas.
+func (r *ReconcileVitessCell) gatewayScale(vtc *planetscalev2.VitessCell, now time.Time) (int32, *planetscalev2.VitessGatewayAutoscaler) {
+	replicas := *vtc.Spec.Gateway.Replicas
+	autoscaler := vtc.Spec.Gateway.Autoscaler
+	status := &vtc.Status.Gateway
+
+	profile, err := scaleprofile.Active(vtc.Spec.Gateway.ScaleProfiles, now)
+	if err != nil {
+		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "InvalidScaleProfile", "vtgate: %v", err)
+		status.ScaleProfile = ""
+		return replicas, autoscaler
+	}
+	if profile == nil {
+		if status.ScaleProfile != "" {
+			r.recorder.Eventf(vtc, corev1.EventTypeNormal, "ScaleProfileEnded", "Scale profile %v ended for vtgate.", status.ScaleProfile)
+		}
+		status.ScaleProfile = ""
+		return replicas, autoscaler
+	}
+	if status.ScaleProfile != profile.Name {
+		r.recorder.Eventf(vtc, corev1.EventTypeNormal, "ScaleProfileStarted", "Scale profile %v is in effect for vtgate, with %d replicas.", profile.Name, profile.Replicas)
+	}
+	status.ScaleProfile = profile.Name
+
+	if autoscaler == nil {
+		return profile.Replicas, nil
+	}
+	minReplicas := profile.Replicas
+	if minReplicas > autoscaler.MaxReplicas {
+		minReplicas = autoscaler.MaxReplicas
+	}
+	if autoscaler.MinReplicas != nil && *autoscaler.MinReplicas > minReplicas {
+		return replicas, autoscaler
+	}
+	autoscaler = autoscaler.DeepCopy()
+	autoscaler.MinReplicas = &minReplicas
+	return replicas, autoscaler
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+// reconcileVtgateAuth checks the vtgate authentication config, and renders
+// the LDAP config file into a Secret if LDAP authentication is enabled. It
+// returns the rendered Secret, if any.
+func (r *ReconcileVitessCell) reconcileVtgateAuth(ctx context.Context, vtc *planetscalev2.VitessCell, clusterName string, labels map[string]string) (*corev1.Secret, error) {
+	auth := &vtc.Spec.Gateway.Authentication
+	if err := validateVtgateAuth(auth, vtc.Spec.Gateway.SecureTransport); err != nil {
+		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "InvalidSpec", "%v", err)
+	}
+
+	key := client.ObjectKey{Namespace: vtc.Namespace, Name: vtgate.LDAPConfigSecretName(clusterName, vtc.Spec.Name)}
+	// Only the first auth backend that's set is used.
+	ldap := auth.LDAP
+	if auth.Static != nil {
+		ldap = nil
+	}
+
+	var secret *corev1.Secret
+	if ldap != nil {
+		var bindPassword string
+		if ldap.BindPasswordSecret != nil {
+			if ldap.BindPasswordSecret.Name == "" {
+				return nil, fmt.Errorf("spec.gateway.authentication.ldap.bindPasswordSecret must specify a Secret name")
+			}
+			passwordSecret := &corev1.Secret{}
+			if err := r.client.Get(ctx, client.ObjectKey{Namespace: vtc.Namespace, Name: ldap.BindPasswordSecret.Name}, passwordSecret); err != nil {
+				return nil, fmt.Errorf("failed to get LDAP bind password: %v", err)
+			}
+			password, ok := passwordSecret.Data[ldap.BindPasswordSecret.Key]
+			if !ok {
+				return nil, fmt.Errorf("LDAP bind password Secret %v has no key %q", passwordSecret.Name, ldap.BindPasswordSecret.Key)
+			}
+			bindPassword = string(password)
+		}
+		var err error
+		secret, err = vtgate.NewLDAPConfigSecret(key, labels, ldap, bindPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.reconciler.ReconcileObject(ctx, vtc, key, labels, secret != nil, reconciler.Strategy{
+		Kind: &corev1.Secret{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return secret
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			curObj := obj.(*corev1.Secret)
+			curObj.Labels = secret.Labels
+			curObj.Data = secret.Data
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// validateVtgateAuth returns an error if the vtgate authentication config
+// can't be applied as written.
+func validateVtgateAuth(auth *planetscalev2.VitessGatewayAuthentication, transport *planetscalev2.VitessGatewaySecureTransport) error {
+	count := 0
+	for _, set := range []bool{auth.Static != nil, auth.LDAP != nil, auth.ClientCert != nil} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return fmt.Errorf("only one of static, ldap, and clientCert may be set in spec.gateway.authentication; using the first one that's set")
+	}
+	if auth.ClientCert != nil && (transport == nil || transport.TLS == nil || transport.TLS.ClientCACertSecret == nil) {
+		return fmt.Errorf("spec.gateway.authentication.clientCert requires spec.gateway.secureTransport.tls.clientCACertSecret; client certificate authentication is disabled")
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/desiredstatehash"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+const (
+	// blueGreenCheckInterval is how often the error rate of new vtgates is
+	// checked while a step of a blue/green rollout is in progress.
+	blueGreenCheckInterval = 15 * time.Second
+)
+
+// reconcileVtgateBlueGreen reconciles the vtgate Deployments of a cell whose
+// rollout strategy is BlueGreen.
+//
+// Outside of a rollout, only the active Deployment exists, and it's updated in
+// place for changes that don't need new Pods, like the number of replicas.
+// When the Pod template changes, the new version is brought up in the other
+// Deployment, and traffic is shifted to it by scaling it up one step at a
+// time. The active Deployment is only scaled down as new vtgates become Ready,
+// and both are behind the same Service. Once every replica runs the new
+// version, the other Deployment becomes the active one, and the old one is
+// deleted. If the new vtgates fail too many queries, the rollout is rolled
+// back instead.
+func (r *ReconcileVitessCell) reconcileVtgateBlueGreen(ctx context.Context, vtc *planetscalev2.VitessCell, spec *vtgate.Spec, blueGreen *planetscalev2.VitessGatewayBlueGreenStrategy) (reconcile.Result, error) {
+	clusterName := vtc.Labels[planetscalev2.ClusterLabel]
+	resultBuilder := &results.Builder{}
+
+	baseName := vtgate.DeploymentName(clusterName, vtc.Spec.Name)
+	altName := vtgate.AlternateDeploymentName(clusterName, vtc.Spec.Name)
+	status := vtc.Status.Gateway.BlueGreen
+	if status == nil {
+		status = &planetscalev2.VitessGatewayBlueGreenStatus{ActiveDeployment: baseName}
+		vtc.Status.Gateway.BlueGreen = status
+	}
+	otherName := altName
+	if status.ActiveDeployment == altName {
+		otherName = baseName
+	}
+
+	total := spec.Replicas
+	desiredHash := vtgate.TemplateHash(spec)
+
+	active := &appsv1.Deployment{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vtc.Namespace, Name: status.ActiveDeployment}, active); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return resultBuilder.Error(err)
+		}
+		active = nil
+	}
+
+	switch {
+	case active == nil || active.Annotations[desiredstatehash.Annotation] == desiredHash:
+		// There's nothing to roll out, so just keep the active Deployment up
+		// to date, and clean up after any earlier rollout.
+		clearBlueGreenCandidate(status)
+		if _, err := r.reconcileVtgateDeployment(ctx, vtc, status.ActiveDeployment, spec, total, desiredHash, true); err != nil {
+			resultBuilder.Error(err)
+		}
+		if err := r.deleteVtgateDeployment(ctx, vtc, spec, otherName); err != nil {
+			resultBuilder.Error(err)
+		}
+		return resultBuilder.Result()
+	case desiredHash == status.RolledBackHash:
+		// Don't try the same rollout again. Keep serving from the active
+		// Deployment as it is until the spec changes.
+		updateVtgateAvailable(vtc, active)
+		vtc.Status.Gateway.Rollout = planetscalev2.VitessRolloutCounts{Desired: total, Failed: total}
+		if err := r.scaleVtgateDeployment(ctx, active, total); err != nil {
+			resultBuilder.Error(err)
+		}
+		if err := r.deleteVtgateDeployment(ctx, vtc, spec, otherName); err != nil {
+			resultBuilder.Error(err)
+		}
+		return resultBuilder.Result()
+	}
+	updateVtgateAvailable(vtc, active)
+
+	if status.CandidateHash != desiredHash {
+		// Start a new rollout, replacing any that was in progress.
+		status.CandidateDeployment = otherName
+		status.CandidateHash = desiredHash
+		status.Step = 1
+		status.StepStartTime = nil
+		r.recorder.Eventf(vtc, corev1.EventTypeNormal, "VtgateRolloutStarted", "Rolling out new vtgates in Deployment %v.", otherName)
+	}
+
+	// Bring up the new vtgates for this step, and only scale down the old ones
+	// as the new ones become Ready.
+	target := blueGreenReplicas(total, *blueGreen.StepPercent, status.Step)
+	ready, err := r.reconcileVtgateDeployment(ctx, vtc, status.CandidateDeployment, spec, target, desiredHash, false)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	if ready > target {
+		ready = target
+	}
+	vtc.Status.Gateway.Rollout = planetscalev2.VitessRolloutCounts{Desired: total, Updated: ready, Pending: total - ready}
+	if err := r.scaleVtgateDeployment(ctx, active, total-ready); err != nil {
+		return resultBuilder.Error(err)
+	}
+	if ready < target {
+		status.StepStartTime = nil
+		status.Message = fmt.Sprintf("Step %d: waiting for %d of %d new vtgates to become Ready.", status.Step, target-ready, target)
+		return resultBuilder.RequeueAfter(blueGreenCheckInterval)
+	}
+
+	// Roll back if the new vtgates are failing too many queries.
+	stats, err := r.vtgateQueryStats(ctx, vtc, spec, status.CandidateDeployment)
+	if err != nil {
+		r.recorder.Eventf(vtc, corev1.EventTypeWarning, "VtgateStatsFailed", "Can't check the error rate of new vtgates: %v", err)
+		return resultBuilder.RequeueAfter(blueGreenCheckInterval)
+	}
+	if stats.Queries >= *blueGreen.MinQueries && stats.ErrorPercent() > float64(*blueGreen.MaxErrorPercent) {
+		status.Message = fmt.Sprintf("Rolled back new vtgates after %.1f%% of %d queries failed.", stats.ErrorPercent(), stats.Queries)
+		r.recorder.Event(vtc, corev1.EventTypeWarning, "VtgateRolledBack", status.Message)
+		status.RolledBackHash = desiredHash
+		clearBlueGreenCandidate(status)
+		if err := r.scaleVtgateDeployment(ctx, active, total); err != nil {
+			return resultBuilder.Error(err)
+		}
+		if err := r.deleteVtgateDeployment(ctx, vtc, spec, otherName); err != nil {
+			return resultBuilder.Error(err)
+		}
+		return resultBuilder.Result()
+	}
+
+	now := time.Now()
+	if status.StepStartTime == nil {
+		status.StepStartTime = &metav1.Time{Time: now}
+	}
+	if remaining := status.StepStartTime.Add(blueGreen.StepInterval.Duration).Sub(now); remaining > 0 {
+		status.Message = fmt.Sprintf("Step %d: %d of %d vtgates run the new version.", status.Step, target, total)
+		if remaining > blueGreenCheckInterval {
+			remaining = blueGreenCheckInterval
+		}
+		return resultBuilder.RequeueAfter(remaining)
+	}
+
+	if target < total {
+		status.Step = nextBlueGreenStep(total, *blueGreen.StepPercent, status.Step)
+		status.StepStartTime = nil
+		return resultBuilder.RequeueAfter(time.Second)
+	}
+
+	// Every replica runs the new version, so make it the active Deployment.
+	status.ActiveDeployment = status.CandidateDeployment
+	clearBlueGreenCandidate(status)
+	status.Message = fmt.Sprintf("Rolled out new vtgates in Deployment %v.", status.ActiveDeployment)
+	r.recorder.Event(vtc, corev1.EventTypeNormal, "VtgateRolloutComplete", status.Message)
+	if err := r.deleteVtgateDeployment(ctx, vtc, spec, active.Name); err != nil {
+		return resultBuilder.Error(err)
+	}
+	return resultBuilder.Result()
+}
+
+// reconcileVtgateDeployment creates or updates one of the vtgate Deployments
+// with the given number of replicas, and returns how many of its replicas are
+// Ready. If it's the active Deployment, it also records whether vtgate is
+// available.
+func (r *ReconcileVitessCell) reconcileVtgateDeployment(ctx context.Context, vtc *planetscalev2.VitessCell, name string, spec *vtgate.Spec, replicas int32, hash string, active bool) (int32, error) {
+	clusterName := vtc.Labels[planetscalev2.ClusterLabel]
+	deploymentSpec := *spec
+	deploymentSpec.Replicas = replicas
+	deploymentSpec.Labels = vtgateDeploymentLabels(spec.Labels, name == vtgate.AlternateDeploymentName(clusterName, vtc.Spec.Name))
+	annotations := map[string]string{desiredstatehash.Annotation: hash}
+
+	var ready int32
+	key := client.ObjectKey{Namespace: vtc.Namespace, Name: name}
+	err := r.reconciler.ReconcileObject(ctx, vtc, key, deploymentSpec.Labels, true, reconciler.Strategy{
+		Kind: &appsv1.Deployment{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			obj := vtgate.NewDeployment(key, &deploymentSpec)
+			update.Annotations(&obj.Annotations, annotations)
+			return obj
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*appsv1.Deployment)
+			vtgate.UpdateDeployment(newObj, &deploymentSpec)
+			update.Annotations(&newObj.Annotations, annotations)
+		},
+		Status: func(key client.ObjectKey, obj runtime.Object) {
+			curObj := obj.(*appsv1.Deployment)
+			ready = curObj.Status.ReadyReplicas
+			vtc.Status.Gateway.Replicas += curObj.Status.Replicas
+			if active {
+				updateVtgateAvailable(vtc, curObj)
+				vtc.Status.Gateway.Rollout = rollout.DeploymentProgress(curObj)
+			}
+		},
+	})
+	return ready, err
+}
+
+// scaleVtgateDeployment changes the number of replicas of a vtgate Deployment
+// without touching anything else.
+func (r *ReconcileVitessCell) scaleVtgateDeployment(ctx context.Context, obj *appsv1.Deployment, replicas int32) error {
+	if obj.Spec.Replicas != nil && *obj.Spec.Replicas == replicas {
+		return nil
+	}
+	obj.Spec.Replicas = &replicas
+	return r.client.Update(ctx, obj)
+}
+
+// deleteVtgateDeployment deletes one of the vtgate Deployments, if it exists.
+func (r *ReconcileVitessCell) deleteVtgateDeployment(ctx context.Context, vtc *planetscalev2.VitessCell, spec *vtgate.Spec, name string) error {
+	key := client.ObjectKey{Namespace: vtc.Namespace, Name: name}
+	return r.reconciler.ReconcileObject(ctx, vtc, key, spec.Labels, false, reconciler.Strategy{
+		Kind: &appsv1.Deployment{},
+	})
+}
+
+// vtgateQueryStats adds up the query stats of the Ready Pods of one of the
+// vtgate Deployments.
+func (r *ReconcileVitessCell) vtgateQueryStats(ctx context.Context, vtc *planetscalev2.VitessCell, spec *vtgate.Spec, name string) (vtgate.QueryStats, error) {
+	clusterName := vtc.Labels[planetscalev2.ClusterLabel]
+	selector := apilabels.SelectorFromSet(spec.Labels)
+	op := selection.DoesNotExist
+	var values []string
+	if name == vtgate.AlternateDeploymentName(clusterName, vtc.Spec.Name) {
+		op, values = selection.Equals, []string{vtgate.GreenColor}
+	}
+	requirement, err := apilabels.NewRequirement(vtgate.ColorLabel, op, values)
+	if err != nil {
+		return vtgate.QueryStats{}, err
+	}
+	selector = selector.Add(*requirement)
+
+	podList := &corev1.PodList{}
+	if err := r.client.List(ctx, podList, &client.ListOptions{Namespace: vtc.Namespace, LabelSelector: selector}); err != nil {
+		return vtgate.QueryStats{}, err
+	}
+	var total vtgate.QueryStats
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		stats, err := vtgate.GetQueryStats(ctx, pod)
+		if err != nil {
+			return vtgate.QueryStats{}, err
+		}
+		total = total.Add(stats)
+	}
+	return total, nil
+}
+
+// vtgateDeploymentLabels returns the labels for one of the vtgate Deployments.
+// The alternate Deployment needs an extra label to tell its Pods apart, since
+// the selector of the original Deployment can't be changed.
+func vtgateDeploymentLabels(labels map[string]string, alternate bool) map[string]string {
+	if !alternate {
+		return labels
+	}
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[vtgate.ColorLabel] = vtgate.GreenColor
+	return result
+}
+
+// updateVtgateAvailable records whether vtgate is available, based on the
+// active vtgate Deployment.
+func updateVtgateAvailable(vtc *planetscalev2.VitessCell, obj *appsv1.Deployment) {
+	if available := conditions.Deployment(obj.Status.Conditions, appsv1.DeploymentAvailable); available != nil {
+		vtc.Status.Gateway.Available = available.Status
+	}
+}
+
+// clearBlueGreenCandidate forgets about the rollout in progress, if any.
+func clearBlueGreenCandidate(status *planetscalev2.VitessGatewayBlueGreenStatus) {
+	status.CandidateDeployment = ""
+	status.CandidateHash = ""
+	status.Step = 0
+	status.StepStartTime = nil
+}
+
+// blueGreenReplicas returns how many replicas should run the new version of
+// vtgate in the given step of a blue/green rollout.
+func blueGreenReplicas(total, stepPercent, step int32) int32 {
+	replicas := (int64(total)*int64(stepPercent)*int64(step) + 99) / 100
+	if replicas > int64(total) {
+		return total
+	}
+	return int32(replicas)
+}
+
+// nextBlueGreenStep returns the next step of a blue/green rollout that moves
+// more replicas to the new version than the given one, skipping steps that
+// round to the same number of replicas.
+func nextBlueGreenStep(total, stepPercent, step int32) int32 {
+	replicas := blueGreenReplicas(total, stepPercent, step)
+	next := step + 1
+	for replicas < total && blueGreenReplicas(total, stepPercent, next) == replicas {
+		next++
+	}
+	return next
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueGreenSteps(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int32
+		stepPercent int32
+		want        []int32
+	}{
+		{
+			name:        "even steps",
+			total:       8,
+			stepPercent: 25,
+			want:        []int32{2, 4, 6, 8},
+		},
+		{
+			name:        "steps that round to the same replicas are skipped",
+			total:       2,
+			stepPercent: 25,
+			want:        []int32{1, 2},
+		},
+		{
+			name:        "single step",
+			total:       3,
+			stepPercent: 100,
+			want:        []int32{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int32
+			for step := int32(1); ; step = nextBlueGreenStep(tt.total, tt.stepPercent, step) {
+				replicas := blueGreenReplicas(tt.total, tt.stepPercent, step)
+				got = append(got, replicas)
+				if replicas >= tt.total {
+					break
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
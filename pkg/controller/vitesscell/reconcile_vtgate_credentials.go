@@ -0,0 +1,111 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+// reconcileVtgateStaticAuth rolls out changes to the static auth Secret of
+// vtgate without restarting it. vtgate reloads the static auth file in place,
+// so the operator only signals each vtgate Pod to get the kubelet to refresh
+// the file promptly, and then tracks which Pods have picked it up.
+func (r *ReconcileVitessCell) reconcileVtgateStaticAuth(ctx context.Context, vtc *planetscalev2.VitessCell, labels map[string]string) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	secretName := vtc.Spec.Gateway.StaticAuthSecretName()
+	if secretName == "" {
+		vtc.Status.Gateway.StaticAuth = nil
+		return resultBuilder.Result()
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vtc.Namespace, Name: secretName}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// vtgate can't start without it, so there's nothing to roll out.
+			return resultBuilder.Result()
+		}
+		return resultBuilder.Error(err)
+	}
+	hash := secrets.ContentHash(secret)
+	now := time.Now()
+
+	status := vtc.Status.Gateway.StaticAuth
+	switch {
+	case status == nil:
+		// This is the first time we've seen the Secret, so the vtgates
+		// started with its current contents.
+		status = &planetscalev2.VitessGatewayStaticAuthStatus{SecretHash: hash}
+	case status.SecretHash != hash:
+		r.recorder.Eventf(vtc, corev1.EventTypeNormal, "CredentialsRotated", "Static auth Secret %v changed; rolling out new credentials to vtgate.", secretName)
+		status = &planetscalev2.VitessGatewayStaticAuthStatus{
+			SecretHash:  hash,
+			ChangedTime: &metav1.Time{Time: now},
+		}
+	}
+	vtc.Status.Gateway.StaticAuth = status
+	var changed time.Time
+	if status.ChangedTime != nil {
+		changed = status.ChangedTime.Time
+	}
+
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vtc.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	status.UpdatedReplicas = 0
+	status.PendingPods = nil
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if vtgate.SignalStaticAuth(pod, hash, now) {
+			if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+				resultBuilder.Error(err)
+			}
+		}
+		if remaining := vtgate.StaticAuthPickupRemaining(pod, hash, changed, now); remaining > 0 {
+			status.PendingPods = append(status.PendingPods, pod.Name)
+			resultBuilder.RequeueAfter(remaining)
+			continue
+		}
+		status.UpdatedReplicas++
+	}
+	sort.Strings(status.PendingPods)
+
+	return resultBuilder.Result()
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+// reconcileVtgateRoutes reconciles the Gateway API routes to the vtgate
+// Service of a cell. The routes have the same name as the Service.
+func (r *ReconcileVitessCell) reconcileVtgateRoutes(ctx context.Context, vtc *planetscalev2.VitessCell, key client.ObjectKey, labels map[string]string) error {
+	var routes planetscalev2.VitessGatewayRoutes
+	if vtc.Spec.Gateway.Exposure != nil && vtc.Spec.Gateway.Exposure.Routes != nil {
+		routes = *vtc.Spec.Gateway.Exposure.Routes
+	}
+
+	err := r.reconcileVtgateRoute(ctx, vtc, key, labels, vtgate.HTTPRouteGVK, routes.HTTP != nil, func(obj *unstructured.Unstructured) {
+		vtgate.UpdateHTTPRoute(obj, labels, key.Name, routes.HTTP)
+	})
+	if err != nil {
+		return err
+	}
+	return r.reconcileVtgateRoute(ctx, vtc, key, labels, vtgate.TCPRouteGVK, routes.TCP != nil, func(obj *unstructured.Unstructured) {
+		vtgate.UpdateTCPRoute(obj, labels, key.Name, routes.TCP)
+	})
+}
+
+// reconcileVtgateRoute reconciles one kind of route to vtgate, if the
+// Kubernetes cluster serves that kind.
+func (r *ReconcileVitessCell) reconcileVtgateRoute(ctx context.Context, vtc *planetscalev2.VitessCell, key client.ObjectKey, labels map[string]string, gvk schema.GroupVersionKind, wanted bool, updateRoute func(obj *unstructured.Unstructured)) error {
+	if _, err := r.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		// There can't be any routes of this kind to clean up.
+		if wanted {
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "GatewayAPIUnavailable", "Can't create vtgate %v because the Gateway API (%v) isn't installed.", gvk.Kind, gvk.GroupVersion())
+		}
+		return nil
+	}
+
+	return r.reconciler.ReconcileObject(ctx, vtc, key, labels, wanted, reconciler.Strategy{
+		Kind: vtgate.NewRoute(client.ObjectKey{}, gvk),
+
+		New: func(key client.ObjectKey) runtime.Object {
+			obj := vtgate.NewRoute(key, gvk)
+			updateRoute(obj)
+			return obj
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			updateRoute(obj.(*unstructured.Unstructured))
+		},
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+const (
+	// servingGateCheckInterval is how often to check on vtgate Pods that are
+	// held back by the readiness gate. Changes in shard health also trigger
+	// a check, through the VitessKeyspace watch.
+	servingGateCheckInterval = 10 * time.Second
+)
+
+// reconcileVtgateServingGate lets new vtgate Pods through the readiness gate
+// once enough shards in the cell are healthy, or once they've waited too long.
+func (r *ReconcileVitessCell) reconcileVtgateServingGate(ctx context.Context, vtc *planetscalev2.VitessCell, labels map[string]string) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	gate := vtc.Spec.Gateway.ServingGate
+	if gate == nil {
+		return resultBuilder.Result()
+	}
+
+	keyspaces, err := r.cellKeyspaces(ctx, vtc)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	status := &planetscalev2.VitessGatewayServingGateStatus{}
+	vtc.Status.Gateway.ServingGate = status
+	for _, vtk := range keyspaces {
+		for name := range vtk.Status.Shards {
+			shard := vtk.Status.Shards[name]
+			healthy, counted := vtgate.ShardHealthy(&shard)
+			if !counted {
+				continue
+			}
+			status.Shards++
+			if healthy {
+				status.HealthyShards++
+			}
+		}
+	}
+	open := vtgate.ServingGateOpen(status.HealthyShards, status.Shards, *gate.MinHealthyShardPercent)
+
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vtc.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	now := time.Now()
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || vtgate.IsServing(pod) {
+			continue
+		}
+
+		reason := "ShardsHealthy"
+		message := fmt.Sprintf("%d of %d shards are healthy.", status.HealthyShards, status.Shards)
+		if !open {
+			waited := now.Sub(pod.CreationTimestamp.Time)
+			if waited < gate.MaxWait.Duration {
+				status.HeldPods = append(status.HeldPods, pod.Name)
+				continue
+			}
+			reason = "MaxWaitExceeded"
+			message = fmt.Sprintf("Only %d of %d shards are healthy after waiting %v.", status.HealthyShards, status.Shards, gate.MaxWait.Duration)
+			r.recorder.Eventf(vtc, corev1.EventTypeWarning, "ServingGateTimeout", "Letting vtgate Pod %v serve: %v", pod.Name, message)
+		}
+
+		vtgate.SetServing(pod, reason, message, now)
+		if err := r.client.Status().Update(ctx, pod); err != nil {
+			if !apierrors.IsConflict(err) {
+				resultBuilder.Error(err)
+			}
+			// Try again with the latest version of the Pod.
+			resultBuilder.RequeueAfter(servingGateCheckInterval)
+		}
+	}
+	sort.Strings(status.HeldPods)
+
+	if len(status.HeldPods) != 0 {
+		resultBuilder.RequeueAfter(servingGateCheckInterval)
+	}
+	return resultBuilder.Result()
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// TestServingGateRBAC checks that the operator's roles let it set the
+// serving gate condition, which is written through the pods/status
+// subresource rather than the Pod itself.
+func TestServingGateRBAC(t *testing.T) {
+	for _, path := range []string{"../../../deploy/role.yaml", "../../../deploy/federation_rbac.yaml"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("can't read %v: %v", path, err)
+		}
+		// The first document in each file is the role itself.
+		doc := strings.SplitN(string(data), "\n---\n", 2)[0]
+		role := &rbacv1.ClusterRole{}
+		if err := yaml.Unmarshal([]byte(doc), role); err != nil {
+			t.Fatalf("can't parse %v: %v", path, err)
+		}
+		if !allowsUpdate(role.Rules, "pods/status") {
+			t.Errorf("%v doesn't allow updating pods/status", path)
+		}
+	}
+}
+
+func allowsUpdate(rules []rbacv1.PolicyRule, resource string) bool {
+	for _, rule := range rules {
+		if !contains(rule.APIGroups, "") || !contains(rule.Resources, resource) {
+			continue
+		}
+		if contains(rule.Verbs, "update") || contains(rule.Verbs, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
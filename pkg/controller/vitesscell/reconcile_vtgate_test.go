@@ -0,0 +1,102 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscell
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestGatewayScale(t *testing.T) {
+	// A weekday at 10:00 UTC, during business hours.
+	now := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	businessHours := []planetscalev2.VitessScaleProfile{{
+		Name:     "business-hours",
+		Schedule: "0 9 * * 1-5",
+		Duration: metav1.Duration{Duration: 12 * time.Hour},
+		Replicas: 6,
+	}}
+
+	tests := []struct {
+		name         string
+		profiles     []planetscalev2.VitessScaleProfile
+		autoscaler   *planetscalev2.VitessGatewayAutoscaler
+		wantReplicas int32
+		wantMin      *int32
+		wantProfile  string
+	}{
+		{
+			name:         "no profiles",
+			wantReplicas: 3,
+		},
+		{
+			name:         "profile in effect",
+			profiles:     businessHours,
+			wantReplicas: 6,
+			wantProfile:  "business-hours",
+		},
+		{
+			name:         "profile raises autoscaler minimum",
+			profiles:     businessHours,
+			autoscaler:   &planetscalev2.VitessGatewayAutoscaler{MinReplicas: pointer.Int32(2), MaxReplicas: 10},
+			wantReplicas: 3,
+			wantMin:      pointer.Int32(6),
+			wantProfile:  "business-hours",
+		},
+		{
+			name:         "autoscaler minimum is capped at maximum",
+			profiles:     businessHours,
+			autoscaler:   &planetscalev2.VitessGatewayAutoscaler{MinReplicas: pointer.Int32(2), MaxReplicas: 4},
+			wantReplicas: 3,
+			wantMin:      pointer.Int32(4),
+			wantProfile:  "business-hours",
+		},
+		{
+			name:         "higher autoscaler minimum is kept",
+			profiles:     businessHours,
+			autoscaler:   &planetscalev2.VitessGatewayAutoscaler{MinReplicas: pointer.Int32(8), MaxReplicas: 10},
+			wantReplicas: 3,
+			wantMin:      pointer.Int32(8),
+			wantProfile:  "business-hours",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileVitessCell{recorder: record.NewFakeRecorder(10)}
+			vtc := &planetscalev2.VitessCell{}
+			vtc.Spec.Gateway.Replicas = pointer.Int32(3)
+			vtc.Spec.Gateway.ScaleProfiles = tt.profiles
+			vtc.Spec.Gateway.Autoscaler = tt.autoscaler
+
+			replicas, autoscaler := r.gatewayScale(vtc, now)
+			assert.Equal(t, tt.wantReplicas, replicas)
+			if tt.autoscaler != nil {
+				assert.Equal(t, tt.wantMin, autoscaler.MinReplicas)
+				// The spec isn't modified.
+				assert.Same(t, tt.autoscaler, vtc.Spec.Gateway.Autoscaler)
+			}
+			assert.Equal(t, tt.wantProfile, vtc.Status.Gateway.ScaleProfile)
+		})
+	}
+}
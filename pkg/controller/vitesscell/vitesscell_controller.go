@@ -24,7 +24,9 @@ import (
 	"github.com/sirupsen/logrus"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -59,6 +61,8 @@ var log = logrus.WithField("controller", "VitessCell")
 var watchResources = []client.Object{
 	&corev1.Service{},
 	&appsv1.Deployment{},
+	&policyv1.PodDisruptionBudget{},
+	&autoscalingv2.HorizontalPodAutoscaler{},
 
 	&planetscalev2.EtcdLockserver{},
 }
@@ -184,6 +188,13 @@ func (r *ReconcileVitessCell) Reconcile(cctx context.Context, request reconcile.
 	// Reset status so it's all based on the latest observed state.
 	oldStatus := vtc.Status
 	vtc.Status = planetscalev2.NewVitessCellStatus()
+	// Blue/green rollouts of vtgate progress across many passes.
+	vtc.Status.Gateway.BlueGreen = oldStatus.Gateway.BlueGreen
+	// Remember the scale profile in effect, so we can tell when it changes.
+	vtc.Status.Gateway.ScaleProfile = oldStatus.Gateway.ScaleProfile
+	// Remember which static auth credentials vtgate has, so we can tell
+	// when they're rotated.
+	vtc.Status.Gateway.StaticAuth = oldStatus.Gateway.StaticAuth.DeepCopy()
 
 	// Materialize all hard-coded default values into the object.
 	// TODO(enisoc): Use versioned defaults when operator-sdk supports mutating webhooks.
@@ -43,6 +43,26 @@ func (r *ReconcileVitessCluster) reconcileBackupStorage(ctx context.Context, vt
 	vbsMap := map[client.ObjectKey]*planetscalev2.VitessBackupStorage{}
 
 	if vt.Spec.Backup != nil {
+		// Keyspaces that override a location get a VBS object of their own
+		// for it, and are left out of the cluster-level one.
+		excludedKeyspaces := map[string][]string{}
+		for i := range vt.Spec.Keyspaces {
+			keyspace := &vt.Spec.Keyspaces[i]
+			for j := range keyspace.BackupLocationOverrides {
+				location := &keyspace.BackupLocationOverrides[j]
+				key := client.ObjectKey{
+					Namespace: vt.Namespace,
+					Name:      vitessbackup.KeyspaceStorageObjectName(vt.Name, keyspace.Name, location.Name),
+				}
+				keys = append(keys, key)
+				vbs := newVitessBackupStorage(key, labels, location, vt.Spec.Backup.Subcontroller, vt.Spec.Backup.Retention)
+				vbs.Labels[planetscalev2.KeyspaceLabel] = keyspace.Name
+				vbs.Spec.Keyspace = keyspace.Name
+				vbsMap[key] = vbs
+				excludedKeyspaces[location.Name] = append(excludedKeyspaces[location.Name], keyspace.Name)
+			}
+		}
+
 		for i := range vt.Spec.Backup.Locations {
 			location := &vt.Spec.Backup.Locations[i]
 			key := client.ObjectKey{
@@ -50,7 +70,9 @@ func (r *ReconcileVitessCluster) reconcileBackupStorage(ctx context.Context, vt
 				Name:      vitessbackup.StorageObjectName(vt.Name, location.Name),
 			}
 			keys = append(keys, key)
-			vbsMap[key] = newVitessBackupStorage(key, labels, location, vt.Spec.Backup.Subcontroller)
+			vbs := newVitessBackupStorage(key, labels, location, vt.Spec.Backup.Subcontroller, vt.Spec.Backup.Retention)
+			vbs.Spec.ExcludedKeyspaces = excludedKeyspaces[location.Name]
+			vbsMap[key] = vbs
 		}
 	}
 
@@ -72,7 +94,7 @@ func (r *ReconcileVitessCluster) reconcileBackupStorage(ctx context.Context, vt
 	})
 }
 
-func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string, location *planetscalev2.VitessBackupLocation, subcontroller *planetscalev2.VitessBackupSubcontrollerSpec) *planetscalev2.VitessBackupStorage {
+func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string, location *planetscalev2.VitessBackupLocation, subcontroller *planetscalev2.VitessBackupSubcontrollerSpec, retention *planetscalev2.VitessBackupRetentionSpec) *planetscalev2.VitessBackupStorage {
 	// Copy parent labels and add child-specific labels.
 	labels := map[string]string{
 		vitessbackup.LocationLabel: location.Name,
@@ -90,6 +112,39 @@ func newVitessBackupStorage(key client.ObjectKey, parentLabels map[string]string
 		Spec: planetscalev2.VitessBackupStorageSpec{
 			Location:      *location,
 			Subcontroller: subcontroller,
+			Retention:     retention,
 		},
 	}
 }
+
+// keyspaceBackupLocations returns the backup locations for a keyspace, which
+// are the cluster-level locations with any overrides from the keyspace
+// applied.
+func keyspaceBackupLocations(clusterLocations []planetscalev2.VitessBackupLocation, keyspace *planetscalev2.VitessKeyspaceTemplate) []planetscalev2.VitessBackupLocation {
+	if len(keyspace.BackupLocationOverrides) == 0 {
+		return clusterLocations
+	}
+
+	overrides := make(map[string]*planetscalev2.VitessBackupLocation, len(keyspace.BackupLocationOverrides))
+	for i := range keyspace.BackupLocationOverrides {
+		location := &keyspace.BackupLocationOverrides[i]
+		overrides[location.Name] = location
+	}
+
+	locations := make([]planetscalev2.VitessBackupLocation, 0, len(clusterLocations)+len(overrides))
+	for i := range clusterLocations {
+		if override := overrides[clusterLocations[i].Name]; override != nil {
+			locations = append(locations, *override)
+			delete(overrides, override.Name)
+			continue
+		}
+		locations = append(locations, clusterLocations[i])
+	}
+	// Add locations that only the keyspace defines, in the order it lists them.
+	for i := range keyspace.BackupLocationOverrides {
+		if location := &keyspace.BackupLocationOverrides[i]; overrides[location.Name] != nil {
+			locations = append(locations, *location)
+		}
+	}
+	return locations
+}
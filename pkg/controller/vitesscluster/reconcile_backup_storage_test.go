@@ -0,0 +1,63 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestKeyspaceBackupLocations(t *testing.T) {
+	clusterDefault := planetscalev2.VitessBackupLocation{GCS: &planetscalev2.GCSBackupLocation{Bucket: "cluster"}}
+	clusterArchive := planetscalev2.VitessBackupLocation{Name: "archive", GCS: &planetscalev2.GCSBackupLocation{Bucket: "cluster-archive"}}
+	keyspaceDefault := planetscalev2.VitessBackupLocation{GCS: &planetscalev2.GCSBackupLocation{Bucket: "keyspace"}}
+	keyspaceExtra := planetscalev2.VitessBackupLocation{Name: "extra", GCS: &planetscalev2.GCSBackupLocation{Bucket: "keyspace-extra"}}
+	clusterLocations := []planetscalev2.VitessBackupLocation{clusterDefault, clusterArchive}
+
+	tests := []struct {
+		name      string
+		overrides []planetscalev2.VitessBackupLocation
+		want      []planetscalev2.VitessBackupLocation
+	}{
+		{
+			name: "no overrides",
+			want: clusterLocations,
+		},
+		{
+			name:      "override replaces location with the same name",
+			overrides: []planetscalev2.VitessBackupLocation{keyspaceDefault},
+			want:      []planetscalev2.VitessBackupLocation{keyspaceDefault, clusterArchive},
+		},
+		{
+			name:      "override with a new name is added",
+			overrides: []planetscalev2.VitessBackupLocation{keyspaceExtra, keyspaceDefault},
+			want:      []planetscalev2.VitessBackupLocation{keyspaceDefault, clusterArchive, keyspaceExtra},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyspace := &planetscalev2.VitessKeyspaceTemplate{
+				Name:                    "ks",
+				BackupLocationOverrides: tt.overrides,
+			}
+			assert.Equal(t, tt.want, keyspaceBackupLocations(clusterLocations, keyspace))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+)
+
+// Removing a cell from the VitessCluster spec happens in stages, since the
+// cell's vtgates and local lockserver must outlive everything that uses them:
+//
+//  1. The cell's VitessCell is kept (orphaned) and the cell is listed in
+//     OrphanedCells until the remaining stages are done.
+//  2. Tablet pools in the cell are left out of the keyspaces, so the shards
+//     drain and turn down those tablets like any other unwanted tablet.
+//     This only happens for keyspaces whose durability policy can still be
+//     met without the cell.
+//  3. Once no tablets are left and the cell is idle, the cell is removed
+//     from topology.
+//  4. Only then is the VitessCell deleted, taking its vtgates and local
+//     lockserver with it.
+
+// removingCells returns the cells that are being removed from the cluster,
+// which are the cells whose turn-down is still blocked.
+func removingCells(vt *planetscalev2.VitessCluster) sets.String {
+	cells := sets.NewString()
+	for name := range vt.Status.OrphanedCells {
+		cells.Insert(name)
+	}
+	return cells
+}
+
+// withoutRemovingCells returns a copy of the keyspace template with the tablet
+// pools in the given cells left out, so those tablets get drained and turned
+// down. If that would leave the keyspace's shards unable to meet its
+// durability policy, the template is returned unchanged along with the
+// durability problems that removing the pools would cause.
+func withoutRemovingCells(keyspace *planetscalev2.VitessKeyspaceTemplate, cells sets.String) (*planetscalev2.VitessKeyspaceTemplate, []string) {
+	if cells.Len() == 0 {
+		return keyspace, nil
+	}
+	filtered := keyspace.DeepCopy()
+	if !filtered.RemoveTabletPoolsInCells(cells) {
+		return keyspace, nil
+	}
+
+	// Only block on problems that removing the pools would add, so a keyspace
+	// that already falls short doesn't hold up the cell forever.
+	before := (&planetscalev2.VitessKeyspaceSpec{VitessKeyspaceTemplate: *keyspace.DeepCopy()}).DurabilityProblems()
+	after := (&planetscalev2.VitessKeyspaceSpec{VitessKeyspaceTemplate: *filtered.DeepCopy()}).DurabilityProblems()
+	if len(after) > len(before) {
+		return keyspace, after
+	}
+	return filtered, nil
+}
+
+// blockCellRemoval records that removing the given cells is blocked because
+// the keyspace's durability policy can't be met without them.
+func (r *ReconcileVitessCluster) blockCellRemoval(vt *planetscalev2.VitessCluster, keyspace *planetscalev2.VitessKeyspaceTemplate, cells sets.String, problems []string) {
+	keyspaceCells := sets.NewString((&planetscalev2.VitessKeyspaceSpec{VitessKeyspaceTemplate: *keyspace.DeepCopy()}).CellNames()...)
+	for _, cell := range cells.Intersection(keyspaceCells).List() {
+		vt.Status.OrphanedCells[cell] = *planetscalev2.NewOrphanStatus("DurabilityAtRisk", fmt.Sprintf("The cell can't be turned down because keyspace %v couldn't meet its durability policy without it: %v. Add tablet pools for the keyspace in other cells first.", keyspace.Name, strings.Join(problems, "; ")))
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "CellRemovalBlocked", "not draining tablets of keyspace %s in cell %s: %s", keyspace.Name, cell, strings.Join(problems, "; "))
+	}
+}
+
+// prepareCellForTurndown returns nil once a cell that's being removed has no
+// tablets left and has been removed from topology, so its VitessCell can be
+// deleted. Otherwise, it returns why the cell must be kept for now.
+func (r *ReconcileVitessCluster) prepareCellForTurndown(ctx context.Context, vt *planetscalev2.VitessCluster, vtc *planetscalev2.VitessCell) *planetscalev2.OrphanStatus {
+	cell := vtc.Spec.Name
+
+	// Tablets in the cell are drained and turned down by the shards, since we
+	// leave the cell's tablet pools out of the keyspaces (see reconcileKeyspaces).
+	podList := &corev1.PodList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(vt.Namespace),
+		client.MatchingLabels{
+			planetscalev2.ClusterLabel:   vt.Name,
+			planetscalev2.CellLabel:      cell,
+			planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		},
+	}
+	if err := r.client.List(ctx, podList, listOpts...); err != nil {
+		return planetscalev2.NewOrphanStatus("TabletsUnknown", fmt.Sprintf("The cell can't be turned down because its tablets couldn't be listed: %v", err))
+	}
+	if len(podList.Items) > 0 {
+		return planetscalev2.NewOrphanStatus("DrainingTablets", fmt.Sprintf("Waiting for %d tablets in the cell to be drained and turned down.", len(podList.Items)))
+	}
+
+	// We err on the safe side since losing a cell accidentally is very disruptive.
+	if vtc.Status.Idle != corev1.ConditionTrue {
+		// The cell is either not idle (Idle=False),
+		// or we can't be sure whether it's idle (Idle=Unknown).
+		return planetscalev2.NewOrphanStatus("NotIdle", "The cell can't be turned down because it's not idle. Waiting for keyspaces to stop serving in the cell.")
+	}
+
+	// Remove the cell from topology while its local lockserver still exists,
+	// so nothing is left pointing at a lockserver that's gone.
+	if *vt.Spec.TopologyReconciliation.PruneCells {
+		if err := r.deregisterCell(ctx, vt, cell); err != nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoCleanupFailed", "unable to remove cell %s from topology: %v", cell, err)
+			return planetscalev2.NewOrphanStatus("RemovingFromTopo", fmt.Sprintf("The cell can't be turned down until it's removed from topology: %v", err))
+		}
+	}
+	return nil
+}
+
+// deregisterCell removes a cell's CellInfo from the global lockserver.
+func (r *ReconcileVitessCluster) deregisterCell(ctx context.Context, vt *planetscalev2.VitessCluster, cell string) error {
+	globalParams := lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name)
+	if globalParams == nil {
+		return fmt.Errorf("no global lockserver is configured")
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	ts, err := toposerver.Open(ctx, *globalParams)
+	if err != nil {
+		return fmt.Errorf("failed to connect to global lockserver: %w", err)
+	}
+	defer ts.Close()
+
+	// This fails if any keyspace still has serving graph records in the cell.
+	if err := ts.DeleteCellInfo(ctx, cell, false /* force */); err != nil {
+		if topo.IsErrType(err, topo.NoNode) {
+			return nil
+		}
+		return err
+	}
+	r.recorder.Eventf(vt, corev1.EventTypeNormal, "TopoCleanup", "removed unwanted cell %s from topology", cell)
+	return nil
+}
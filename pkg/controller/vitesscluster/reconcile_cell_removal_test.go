@@ -0,0 +1,101 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestWithoutRemovingCells(t *testing.T) {
+	keyspace := func(durabilityPolicy string, replicasPerCell map[string]int32) *planetscalev2.VitessKeyspaceTemplate {
+		template := planetscalev2.VitessShardTemplate{}
+		for _, cell := range sets.StringKeySet(replicasPerCell).List() {
+			template.TabletPools = append(template.TabletPools, planetscalev2.VitessShardTabletPool{
+				Cell:     cell,
+				Type:     planetscalev2.ReplicaPoolType,
+				Replicas: replicasPerCell[cell],
+			})
+		}
+		return &planetscalev2.VitessKeyspaceTemplate{
+			Name:             "commerce",
+			DurabilityPolicy: durabilityPolicy,
+			Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+				{Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{Parts: 2, ShardTemplate: template}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		keyspace     *planetscalev2.VitessKeyspaceTemplate
+		removing     sets.String
+		wantPools    int
+		wantProblems bool
+	}{
+		{
+			name:      "no cells being removed",
+			keyspace:  keyspace(planetscalev2.CrossCellDurabilityPolicy, map[string]int32{"zone1": 2, "zone2": 1}),
+			removing:  sets.NewString(),
+			wantPools: 2,
+		},
+		{
+			name:      "keyspace not in removed cell",
+			keyspace:  keyspace(planetscalev2.CrossCellDurabilityPolicy, map[string]int32{"zone1": 2, "zone2": 1}),
+			removing:  sets.NewString("zone3"),
+			wantPools: 2,
+		},
+		{
+			name:      "cross_cell still met",
+			keyspace:  keyspace(planetscalev2.CrossCellDurabilityPolicy, map[string]int32{"zone1": 2, "zone2": 1, "zone3": 1}),
+			removing:  sets.NewString("zone3"),
+			wantPools: 2,
+		},
+		{
+			name:         "cross_cell no longer met",
+			keyspace:     keyspace(planetscalev2.CrossCellDurabilityPolicy, map[string]int32{"zone1": 2, "zone2": 1}),
+			removing:     sets.NewString("zone2"),
+			wantPools:    2,
+			wantProblems: true,
+		},
+		{
+			name:         "semi_sync no longer met",
+			keyspace:     keyspace(planetscalev2.SemiSyncDurabilityPolicy, map[string]int32{"zone1": 1, "zone2": 2}),
+			removing:     sets.NewString("zone2"),
+			wantPools:    2,
+			wantProblems: true,
+		},
+		{
+			name:      "existing problems don't block",
+			keyspace:  keyspace(planetscalev2.SemiSyncDurabilityPolicy, map[string]int32{"zone1": 1}),
+			removing:  sets.NewString("zone1"),
+			wantPools: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problems := withoutRemovingCells(tt.keyspace, tt.removing)
+			assert.Equal(t, tt.wantProblems, len(problems) > 0, "problems = %q", problems)
+			assert.Len(t, got.Partitionings[0].TabletPools(), tt.wantPools)
+		})
+	}
+}
@@ -19,7 +19,6 @@ package vitesscluster
 import (
 	"context"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -39,10 +38,15 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 
 	// Generate keys (object names) for all desired cells.
 	// Keep a map back from generated names to the cell specs.
+	// Cells deployed by members are left to them (see reconcileMembers).
+	remote := remoteCells(vt)
 	keys := make([]client.ObjectKey, 0, len(vt.Spec.Cells))
 	cellMap := make(map[client.ObjectKey]*planetscalev2.VitessCellTemplate, len(vt.Spec.Cells))
 	for i := range vt.Spec.Cells {
 		cell := &vt.Spec.Cells[i]
+		if remote.Has(cell.Name) {
+			continue
+		}
 		key := client.ObjectKey{Namespace: vt.Namespace, Name: vitesscell.Name(vt.Name, cell.Name)}
 		keys = append(keys, key)
 		cellMap[key] = cell
@@ -52,15 +56,20 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 		vt.Status.Cells[cell.Name] = planetscalev2.NewVitessClusterCellStatus()
 	}
 
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := rolloutPaused(vt)
+	immediate := !paused && *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType
+
 	return r.reconciler.ReconcileObjectSet(ctx, vt, keys, labels, reconciler.Strategy{
-		Kind: &planetscalev2.VitessCell{},
+		Kind:          &planetscalev2.VitessCell{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return newVitessCell(key, vt, labels, cellMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessCell)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if immediate {
 				updateVitessCell(key, newObj, vt, labels, cellMap[key])
 				return
 			}
@@ -68,7 +77,7 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 		},
 		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessCell)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if immediate {
 				// In this case we should use UpdateInPlace for all updates.
 				return
 			}
@@ -81,6 +90,9 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 			status.PendingChanges = curObj.Annotations[rollout.ScheduledAnnotation]
 			status.GatewayAvailable = curObj.Status.Gateway.Available
 			vt.Status.Cells[curObj.Spec.Name] = status
+
+			observeRolloutChild(&vt.Status.RolloutStatus, curObj, curObj.Status.ObservedGeneration)
+			vt.Status.RolloutStatus.Vtgate.Add(childRolloutCounts(curObj, curObj.Status.Gateway.Rollout))
 		},
 		OrphanStatus: func(key client.ObjectKey, obj runtime.Object, orphanStatus *planetscalev2.OrphanStatus) {
 			curObj := obj.(*planetscalev2.VitessCell)
@@ -88,15 +100,8 @@ func (r *ReconcileVitessCluster) reconcileCells(ctx context.Context, vt *planets
 		},
 		PrepareForTurndown: func(key client.ObjectKey, obj runtime.Object) *planetscalev2.OrphanStatus {
 			// Make sure it's ok to delete this cell.
-			// We err on the safe side since losing a cell accidentally is very disruptive.
 			curObj := obj.(*planetscalev2.VitessCell)
-			if curObj.Status.Idle == corev1.ConditionTrue {
-				// The cell has no keyspaces deployed in it.
-				return nil
-			}
-			// The cell is either not idle (Idle=False),
-			// or we can't be sure whether it's idle (Idle=Unknown).
-			return planetscalev2.NewOrphanStatus("NotIdle", "The cell can't be turned down because it's not idle. You must remove all keyspaces from the cell first before removing the cell.")
+			return r.prepareCellForTurndown(ctx, vt, curObj)
 		},
 	})
 }
@@ -134,7 +139,7 @@ func newVitessCell(key client.ObjectKey, vt *planetscalev2.VitessCluster, parent
 		},
 		Spec: planetscalev2.VitessCellSpec{
 			VitessCellTemplate:     *template,
-			GlobalLockserver:       *lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name),
+			GlobalLockserver:       *lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name),
 			AllCells:               allCells,
 			Images:                 images,
 			ImagePullPolicies:      vt.Spec.ImagePullPolicies,
@@ -154,7 +159,7 @@ func updateVitessCellInPlace(key client.ObjectKey, vtc *planetscalev2.VitessCell
 
 	// We allow immediate update of replica counts for stateless workloads,
 	// like Deployment does.
-	vtc.Spec.Gateway.Replicas = newCell.Spec.Gateway.Replicas
+	vtc.Spec.Gateway.Replicas = gatewayReplicas(vtc, newCell)
 }
 
 func updateVitessCell(key client.ObjectKey, vtc *planetscalev2.VitessCell, vt *planetscalev2.VitessCluster, parentLabels map[string]string, cell *planetscalev2.VitessCellTemplate) {
@@ -164,5 +169,16 @@ func updateVitessCell(key client.ObjectKey, vtc *planetscalev2.VitessCell, vt *p
 	update.Labels(&vtc.Labels, newCell.Labels)
 
 	// For now, everything in Spec is safe to update.
+	newCell.Spec.Gateway.Replicas = gatewayReplicas(vtc, newCell)
 	vtc.Spec = newCell.Spec
 }
+
+// gatewayReplicas returns the number of vtgate replicas to set in an existing
+// VitessCell. If vtgate is autoscaled, the HPA manages the number of replicas
+// through the VitessCell scale subresource, so we leave it alone.
+func gatewayReplicas(vtc, newCell *planetscalev2.VitessCell) *int32 {
+	if newCell.Spec.Gateway.Autoscaler != nil && vtc.Spec.Gateway.Replicas != nil {
+		return vtc.Spec.Gateway.Replicas
+	}
+	return newCell.Spec.Gateway.Replicas
+}
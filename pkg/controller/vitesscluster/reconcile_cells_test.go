@@ -0,0 +1,67 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestGatewayReplicas(t *testing.T) {
+	newCell := func(replicas *int32, autoscaler *planetscalev2.VitessGatewayAutoscaler) *planetscalev2.VitessCell {
+		vtc := &planetscalev2.VitessCell{}
+		vtc.Spec.Gateway.Replicas = replicas
+		vtc.Spec.Gateway.Autoscaler = autoscaler
+		return vtc
+	}
+	autoscaler := &planetscalev2.VitessGatewayAutoscaler{MaxReplicas: 10}
+
+	tests := []struct {
+		name    string
+		vtc     *planetscalev2.VitessCell
+		newCell *planetscalev2.VitessCell
+		want    *int32
+	}{
+		{
+			name:    "not autoscaled",
+			vtc:     newCell(pointer.Int32Ptr(5), nil),
+			newCell: newCell(pointer.Int32Ptr(2), nil),
+			want:    pointer.Int32Ptr(2),
+		},
+		{
+			name:    "autoscaled",
+			vtc:     newCell(pointer.Int32Ptr(5), nil),
+			newCell: newCell(pointer.Int32Ptr(2), autoscaler),
+			want:    pointer.Int32Ptr(5),
+		},
+		{
+			name:    "autoscaled before replicas are set",
+			vtc:     newCell(nil, nil),
+			newCell: newCell(pointer.Int32Ptr(2), autoscaler),
+			want:    pointer.Int32Ptr(2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gatewayReplicas(tt.vtc, tt.newCell))
+		})
+	}
+}
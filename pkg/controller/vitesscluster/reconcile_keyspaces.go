@@ -18,11 +18,15 @@ package vitesscluster
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
@@ -54,23 +58,47 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 		vt.Status.Keyspaces[keyspace.Name] = planetscalev2.NewVitessClusterKeyspaceStatus(keyspace)
 	}
 
+	// Leave out tablet pools in cells that members deploy (see reconcileMembers).
+	// Also leave out tablet pools in cells that are being removed, so those
+	// tablets get drained and turned down before the cells themselves.
+	remote := remoteCells(vt)
+	removing := removingCells(vt)
+	for key, keyspace := range keyspaceMap {
+		if remote.Len() > 0 {
+			keyspace = keyspace.DeepCopy()
+			keyspace.RemoveTabletPoolsInCells(remote)
+		}
+		filtered, problems := withoutRemovingCells(keyspace, removing)
+		if len(problems) > 0 {
+			r.blockCellRemoval(vt, keyspace, removing, problems)
+		}
+		keyspaceMap[key] = filtered
+	}
+
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := rolloutPaused(vt)
+	immediate := !paused && *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType
+
 	return r.reconciler.ReconcileObjectSet(ctx, vt, keys, labels, reconciler.Strategy{
-		Kind: &planetscalev2.VitessKeyspace{},
+		Kind:          &planetscalev2.VitessKeyspace{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return newVitessKeyspace(key, vt, labels, keyspaceMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessKeyspace)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			// The keyspace was added back, so cancel any decommission.
+			delete(newObj.Annotations, vitesskeyspace.DecommissionAnnotation)
+			if immediate {
 				updateVitessKeyspace(key, newObj, vt, labels, keyspaceMap[key])
 				return
 			}
-			updateVitessKeyspaceInPlace(key, newObj, vt, labels, keyspaceMap[key])
+			updateVitessKeyspaceInPlace(key, newObj, vt, labels, keyspaceMap[key], removing)
 		},
 		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessKeyspace)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if immediate {
 				// In this case we should use UpdateInPlace for all updates.
 				return
 			}
@@ -111,6 +139,12 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 			sort.Strings(status.Cells)
 
 			vt.Status.Keyspaces[curObj.Spec.Name] = status
+
+			observeRolloutChild(&vt.Status.RolloutStatus, curObj, curObj.Status.ObservedGeneration)
+			if vt.Status.RolloutStatus.Keyspaces == nil {
+				vt.Status.RolloutStatus.Keyspaces = make(map[string]planetscalev2.VitessRolloutCounts)
+			}
+			vt.Status.RolloutStatus.Keyspaces[curObj.Spec.Name] = childRolloutCounts(curObj, keyspaceRolloutCounts(curObj))
 		},
 		OrphanStatus: func(key client.ObjectKey, obj runtime.Object, orphanStatus *planetscalev2.OrphanStatus) {
 			curObj := obj.(*planetscalev2.VitessKeyspace)
@@ -125,6 +159,25 @@ func (r *ReconcileVitessCluster) reconcileKeyspaces(ctx context.Context, vt *pla
 				return nil
 			}
 
+			// The user may instead ask for the keyspace to be decommissioned,
+			// which the keyspace controller does once we request it.
+			if curObj.Spec.TurndownPolicy == planetscalev2.VitessKeyspaceTurndownPolicyDecommission {
+				request := curObj.Annotations[vitesskeyspace.DecommissionAnnotation]
+				if request == "" {
+					request = strconv.FormatInt(time.Now().Unix(), 10)
+					metav1.SetMetaDataAnnotation(&curObj.ObjectMeta, vitesskeyspace.DecommissionAnnotation, request)
+					return planetscalev2.NewOrphanStatus("Decommissioning", "The keyspace is being decommissioned.")
+				}
+				status := curObj.Status.Decommission
+				if status == nil || status.Request != request {
+					return planetscalev2.NewOrphanStatus("Decommissioning", "The keyspace is being decommissioned.")
+				}
+				if status.Phase != planetscalev2.DecommissionComplete {
+					return planetscalev2.NewOrphanStatus("Decommissioning", fmt.Sprintf("The keyspace is being decommissioned (%v). %v", status.Phase, status.Message))
+				}
+				return nil
+			}
+
 			// Otherwise, we err on the safe side since losing a keyspace accidentally is very disruptive.
 			if curObj.Status.Idle == corev1.ConditionTrue {
 				// The keyspace is not depoyed in any cells.
@@ -158,9 +211,27 @@ func newVitessKeyspace(key client.ObjectKey, vt *planetscalev2.VitessCluster, pa
 
 	var backupLocations []planetscalev2.VitessBackupLocation
 	var backupEngine planetscalev2.VitessBackupEngine
+	var vtbackup *planetscalev2.VitessBackupPodSpec
+	var backupVerification *planetscalev2.VitessBackupVerificationSpec
+	var backupSourceTabletPolicy *planetscalev2.VitessBackupSourceTabletPolicy
+	var backupReplication *planetscalev2.VitessBackupReplicationSpec
 	if vt.Spec.Backup != nil {
-		backupLocations = vt.Spec.Backup.Locations
+		backupLocations = keyspaceBackupLocations(vt.Spec.Backup.Locations, template)
 		backupEngine = vt.Spec.Backup.Engine
+		vtbackup = vt.Spec.Backup.Vtbackup
+		backupVerification = vt.Spec.Backup.Verification
+		backupSourceTabletPolicy = vt.Spec.Backup.SourceTabletPolicy
+		backupReplication = vt.Spec.Backup.Replication
+
+		// Keyspaces without their own backup schedule use the cluster's.
+		if template.BackupSchedule == nil {
+			template.BackupSchedule = vt.Spec.Backup.Schedule
+		}
+	}
+
+	// A standby must never elect a primary, which vtorc would try to do.
+	if vt.Spec.IsStandby() {
+		template.VitessOrchestrator = nil
 	}
 
 	return &planetscalev2.VitessKeyspace{
@@ -171,17 +242,26 @@ func newVitessKeyspace(key client.ObjectKey, vt *planetscalev2.VitessCluster, pa
 			Annotations: keyspace.Annotations,
 		},
 		Spec: planetscalev2.VitessKeyspaceSpec{
-			VitessKeyspaceTemplate: *template,
-			GlobalLockserver:       *lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name),
-			Images:                 images,
-			ImagePullPolicies:      vt.Spec.ImagePullPolicies,
-			ImagePullSecrets:       vt.Spec.ImagePullSecrets,
-			ZoneMap:                vt.Spec.ZoneMap(),
-			BackupLocations:        backupLocations,
-			BackupEngine:           backupEngine,
-			ExtraVitessFlags:       vt.Spec.ExtraVitessFlags,
-			TopologyReconciliation: vt.Spec.TopologyReconciliation,
-			UpdateStrategy:         vt.Spec.UpdateStrategy,
+			VitessKeyspaceTemplate:   *template,
+			GlobalLockserver:         *lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name),
+			Images:                   images,
+			ImagePullPolicies:        vt.Spec.ImagePullPolicies,
+			ImagePullSecrets:         vt.Spec.ImagePullSecrets,
+			ZoneMap:                  localZoneMap(vt),
+			BackupLocations:          backupLocations,
+			BackupEngine:             backupEngine,
+			Vtbackup:                 vtbackup,
+			BackupVerification:       backupVerification,
+			BackupSourceTabletPolicy: backupSourceTabletPolicy,
+			BackupReplication:        backupReplication,
+			ExtraVitessFlags:         vt.Spec.ExtraVitessFlags,
+			TopologyReconciliation:   vt.Spec.TopologyReconciliation,
+			UpdateStrategy:           vt.Spec.UpdateStrategy.WithPaused(rollout.Paused(vt)),
+			MaintenanceWindows:       vt.Spec.MaintenanceWindows,
+			PreferredPrimaryCells:    vt.Spec.PreferredPrimaryCells,
+			ReparentSettings:         vt.Spec.ReparentSettings,
+			InitialRestore:           vt.Spec.InitialRestoreSource(),
+			Standby:                  vt.Spec.StandbySpec(),
 		},
 	}
 }
@@ -200,7 +280,7 @@ func updateVitessKeyspace(key client.ObjectKey, vtk *planetscalev2.VitessKeyspac
 	vtk.Spec = newKeyspace.Spec
 }
 
-func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.VitessKeyspace, vt *planetscalev2.VitessCluster, parentLabels map[string]string, keyspace *planetscalev2.VitessKeyspaceTemplate) {
+func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.VitessKeyspace, vt *planetscalev2.VitessCluster, parentLabels map[string]string, keyspace *planetscalev2.VitessKeyspaceTemplate, removing sets.String) {
 	newKeyspace := newVitessKeyspace(key, vt, parentLabels, keyspace)
 
 	// Update labels, but ignore existing ones we don't set.
@@ -208,6 +288,8 @@ func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.Vitess
 
 	// Switching update strategies should always take effect immediately.
 	vtk.Spec.UpdateStrategy = newKeyspace.Spec.UpdateStrategy
+	// So should changing when disruptive changes may be made.
+	vtk.Spec.MaintenanceWindows = newKeyspace.Spec.MaintenanceWindows
 
 	// Update disk size immediately if specified to.
 	if *vtk.Spec.UpdateStrategy.Type == planetscalev2.ExternalVitessClusterUpdateStrategyType {
@@ -220,8 +302,19 @@ func updateVitessKeyspaceInPlace(key client.ObjectKey, vtk *planetscalev2.Vitess
 	// partitionings that already exist.
 	update.PartitioningSet(&vtk.Spec.Partitionings, newKeyspace.Spec.Partitionings)
 
+	// Removing a cell from the cluster is the go-ahead to turn down the tablets
+	// in it, so that doesn't wait for a rollout. Cells that the new spec still
+	// deploys to are the ones whose removal is blocked.
+	removedCells := removing.Difference(sets.NewString(newKeyspace.Spec.CellNames()...))
+	if vtk.Spec.RemoveTabletPoolsInCells(removedCells) {
+		for _, cell := range removedCells.List() {
+			delete(vtk.Spec.ZoneMap, cell)
+		}
+	}
+
 	// Only update things that are safe to roll out immediately.
 	vtk.Spec.TurndownPolicy = newKeyspace.Spec.TurndownPolicy
+	vtk.Spec.Decommission = newKeyspace.Spec.Decommission
 
 	// Add or remove annotations requested in vtk.Spec.Annotations.
 	updateVitessKeyspaceAnnotations(vtk, newKeyspace)
@@ -250,3 +343,13 @@ func differentKeys(oldMap, newMap map[string]string) []string {
 
 	return differentKeys
 }
+
+// localZoneMap returns a map from the names of cells deployed alongside the
+// VitessCluster to their zones. Shards only manage tablets in these cells.
+func localZoneMap(vt *planetscalev2.VitessCluster) map[string]string {
+	zoneMap := vt.Spec.ZoneMap()
+	for _, cell := range remoteCells(vt).List() {
+		delete(zoneMap, cell)
+	}
+	return zoneMap
+}
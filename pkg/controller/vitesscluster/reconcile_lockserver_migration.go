@@ -0,0 +1,150 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesstopo"
+)
+
+const (
+	// topoMigrationTimeout is how long to spend copying topology data to
+	// the target of a lockserver migration in one pass.
+	topoMigrationTimeout = 1 * time.Minute
+)
+
+// reconcileLockserverMigration copies the topology data to the target of a
+// global lockserver migration, and records when it's safe to switch Vitess
+// components over to it. This must run before anything that passes the
+// global lockserver on to other objects, since they look at the status it
+// records to decide which lockserver to use.
+func (r *ReconcileVitessCluster) reconcileLockserverMigration(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	migration := vt.Spec.GlobalLockserver.Migration
+	if migration == nil {
+		return resultBuilder.Result()
+	}
+
+	status := oldStatus.GlobalLockserver.Migration.DeepCopy()
+	if status == nil || status.Target != migration.Target {
+		// Start over if the target changed.
+		status = &planetscalev2.LockserverMigrationStatus{
+			Target: migration.Target,
+			Phase:  planetscalev2.LockserverMigrationCopying,
+		}
+	}
+	vt.Status.GlobalLockserver.Migration = status
+
+	switch status.Phase {
+	case planetscalev2.LockserverMigrationSwitchedOver:
+		if !migration.SwitchOver {
+			// The target has been authoritative since the switch over, so
+			// the source is missing anything written in the meantime.
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "LockserverSwitchedBack", "Switching back to the source lockserver. Changes made in %v since the switch over aren't copied back.", migration.Target.Address)
+			status.Phase = planetscalev2.LockserverMigrationCopied
+		}
+		return resultBuilder.Result()
+	case planetscalev2.LockserverMigrationCopied:
+		if !migration.SwitchOver {
+			return resultBuilder.Result()
+		}
+		// Copy once more right before switching, to pick up anything that
+		// changed since the last copy.
+	}
+
+	sourceParams := lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name)
+	if sourceParams == nil {
+		r.recorder.Event(vt, corev1.EventTypeWarning, "TopoInvalid", "no global lockserver is defined to migrate from")
+		return resultBuilder.Result()
+	}
+	if *sourceParams == migration.Target {
+		r.recorder.Event(vt, corev1.EventTypeWarning, "TopoInvalid", "the lockserver migration target is the same as the current global lockserver")
+		return resultBuilder.Result()
+	}
+
+	if err := r.copyLockserverData(ctx, vt, sourceParams, &migration.Target); err != nil {
+		status.Message = err.Error()
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "LockserverCopyFailed", "failed to copy topology data to %v: %v", migration.Target.Address, err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	now := metav1.Now()
+	status.LastCopyTime = &now
+	status.Message = ""
+	if migration.SwitchOver {
+		status.Phase = planetscalev2.LockserverMigrationSwitchedOver
+		r.recorder.Eventf(vt, corev1.EventTypeNormal, "LockserverSwitchedOver", "Switching Vitess components over to lockserver %v", migration.Target.Address)
+	} else {
+		status.Phase = planetscalev2.LockserverMigrationCopied
+		r.recorder.Eventf(vt, corev1.EventTypeNormal, "LockserverCopied", "Copied topology data to lockserver %v", migration.Target.Address)
+	}
+	return resultBuilder.Result()
+}
+
+// copyLockserverData registers the cells in the target lockserver as they'll
+// be after the switch over, and then copies the topology data there.
+func (r *ReconcileVitessCluster) copyLockserverData(ctx context.Context, vt *planetscalev2.VitessCluster, sourceParams, targetParams *planetscalev2.VitessLockserverParams) error {
+	ctx, cancel := context.WithTimeout(ctx, topoMigrationTimeout)
+	defer cancel()
+
+	source, err := toposerver.Open(ctx, *sourceParams)
+	if err != nil {
+		return fmt.Errorf("failed to connect to source lockserver: %w", err)
+	}
+	defer source.Close()
+	target, err := toposerver.Open(ctx, *targetParams)
+	if err != nil {
+		return fmt.Errorf("failed to connect to target lockserver: %w", err)
+	}
+	defer target.Close()
+
+	desiredCells := make(map[string]*planetscalev2.LockserverSpec, len(vt.Spec.Cells))
+	for i := range vt.Spec.Cells {
+		cell := &vt.Spec.Cells[i]
+		desiredCells[cell.Name] = &cell.Lockserver
+	}
+	result, err := vitesstopo.RegisterCells(ctx, vitesstopo.RegisterCellsParams{
+		EventObj:         vt,
+		TopoServer:       target.Server,
+		Recorder:         r.recorder,
+		GlobalLockserver: &planetscalev2.LockserverSpec{External: targetParams},
+		ClusterName:      vt.Name,
+		GlobalTopoImpl:   targetParams.Implementation,
+		DesiredCells:     desiredCells,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Requeue || result.RequeueAfter > 0 {
+		return errors.New("failed to register cells in target lockserver")
+	}
+
+	return vitesstopo.CopyTopology(ctx, source.Server, target.Server)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/federation"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesscell"
+)
+
+// memberResyncPeriod is how often to sync members while there are any,
+// since changes to them don't trigger a reconcile of the parent.
+const memberResyncPeriod = time.Minute
+
+// reconcileMembers creates, updates and turns down the member VitessClusters
+// that deploy cells placed in other namespaces or Kubernetes clusters, and
+// reports the status of those cells from their members.
+func (r *ReconcileVitessCluster) reconcileMembers(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	// Group placed cells by where they go.
+	targets := map[string]federation.Target{}
+	targetCells := map[string]sets.String{}
+	for i := range vt.Spec.Cells {
+		cell := &vt.Spec.Cells[i]
+		if !cell.IsPlaced(vt.Namespace) {
+			continue
+		}
+		vt.Status.Cells[cell.Name] = planetscalev2.NewVitessClusterCellStatus()
+
+		// A cell that's moving away from here has to be turned down here
+		// first, or the same tablets would run in two places.
+		localCell := &planetscalev2.VitessCell{}
+		err := r.client.Get(ctx, client.ObjectKey{Namespace: vt.Namespace, Name: vitesscell.Name(vt.Name, cell.Name)}, localCell)
+		if err == nil || !apierrors.IsNotFound(err) {
+			continue
+		}
+
+		target := federation.TargetFor(vt.Namespace, cell.Placement)
+		key := target.Key()
+		if _, ok := targets[key]; !ok {
+			targets[key] = target
+			targetCells[key] = sets.NewString()
+		}
+		targetCells[key].Insert(cell.Name)
+	}
+
+	// Keep syncing members we had before until they're turned down.
+	oldMembers := make(map[string]*planetscalev2.VitessClusterMemberStatus, len(oldStatus.Members))
+	for i := range oldStatus.Members {
+		member := &oldStatus.Members[i]
+		target := federation.StatusTarget(member)
+		key := target.Key()
+		oldMembers[key] = member
+		if _, ok := targets[key]; !ok {
+			targets[key] = target
+			targetCells[key] = sets.NewString()
+		}
+	}
+
+	// Likewise, a cell that's moving from one member to another has to be
+	// turned down by the old member first.
+	for key, cells := range targetCells {
+		for otherKey, member := range oldMembers {
+			if otherKey != key {
+				cells.Delete(member.Cells...)
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		return resultBuilder.Result()
+	}
+
+	keys := make([]string, 0, len(targets))
+	for key := range targets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	globalSpec := lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver)
+	for _, key := range keys {
+		status := r.reconcileMember(ctx, vt, targets[key], targetCells[key], globalSpec, oldMembers[key])
+		if status != nil {
+			vt.Status.Members = append(vt.Status.Members, *status)
+		}
+	}
+
+	if len(vt.Status.Members) > 0 {
+		resultBuilder.RequeueAfter(memberResyncPeriod)
+	}
+	return resultBuilder.Result()
+}
+
+// reconcileMember syncs one member VitessCluster, and returns its status, or
+// nil if the member is gone and no longer needed.
+func (r *ReconcileVitessCluster) reconcileMember(ctx context.Context, vt *planetscalev2.VitessCluster, target federation.Target, cells sets.String, globalSpec *planetscalev2.LockserverSpec, oldMember *planetscalev2.VitessClusterMemberStatus) *planetscalev2.VitessClusterMemberStatus {
+	status := &planetscalev2.VitessClusterMemberStatus{
+		Namespace:        target.Namespace,
+		KubeconfigSecret: target.KubeconfigSecret,
+		Synced:           corev1.ConditionFalse,
+	}
+	// Until we hear otherwise, assume the member still deploys what it did.
+	if oldMember != nil {
+		status.Cells = oldMember.Cells
+	}
+	notSynced := func(reason, format string, args ...interface{}) *planetscalev2.VitessClusterMemberStatus {
+		status.Message = fmt.Sprintf(format, args...)
+		if oldMember == nil || oldMember.Message != status.Message {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, reason, "member VitessCluster in %v: %v", target, status.Message)
+		}
+		return status
+	}
+
+	globalParams := lockserver.GlobalConnectionParams(globalSpec, vt.Namespace, vt.Name)
+	if globalParams == nil {
+		return notSynced("MemberInvalid", "no global lockserver is defined")
+	}
+	if target.KubeconfigSecret != nil && globalSpec.Etcd != nil && globalSpec.CellInfoAddress == "" {
+		return notSynced("MemberInvalid", "the deployed global lockserver isn't reachable from other Kubernetes clusters; set its cellInfoAddress or use an external lockserver")
+	}
+
+	c, err := r.members.For(ctx, vt.Namespace, target)
+	if err != nil {
+		return notSynced("MemberSyncFailed", "%v", err)
+	}
+
+	key := client.ObjectKey{Namespace: target.Namespace, Name: vt.Name}
+	newMember := federation.NewMember(key, vt, cells, globalParams)
+	member := &planetscalev2.VitessCluster{}
+	err = c.Get(ctx, key, member)
+	switch {
+	case apierrors.IsNotFound(err):
+		if cells.Len() == 0 {
+			// The member is gone and there's nothing left for it to do.
+			return nil
+		}
+		if err := c.Create(ctx, newMember); err != nil {
+			return notSynced("MemberSyncFailed", "failed to create: %v", err)
+		}
+		r.recorder.Eventf(vt, corev1.EventTypeNormal, "MemberCreated", "created member VitessCluster in %v for cells %v", target, cells.List())
+		member = newMember
+	case err != nil:
+		return notSynced("MemberSyncFailed", "failed to get: %v", err)
+	default:
+		if member.Annotations[federation.ParentAnnotation] != federation.ParentValue(vt) {
+			return notSynced("MemberConflict", "a VitessCluster named %v that isn't a member of this one already exists there", vt.Name)
+		}
+
+		// A member with no cells left is turned down once it's done removing
+		// its cells, which it does as carefully as any other cell removal.
+		if cells.Len() == 0 && member.Status.ObservedGeneration == member.Generation && len(federation.MemberCells(member)) == 0 {
+			if err := c.Delete(ctx, member); err != nil && !apierrors.IsNotFound(err) {
+				return notSynced("MemberSyncFailed", "failed to delete: %v", err)
+			}
+			r.recorder.Eventf(vt, corev1.EventTypeNormal, "MemberDeleted", "deleted member VitessCluster in %v", target)
+			return nil
+		}
+
+		if !apiequality.Semantic.DeepEqual(&member.Spec, &newMember.Spec) {
+			member.Spec = newMember.Spec
+			if err := c.Update(ctx, member); err != nil {
+				return notSynced("MemberSyncFailed", "failed to update: %v", err)
+			}
+		}
+	}
+
+	status.Synced = corev1.ConditionTrue
+	status.Message = ""
+	status.Cells = federation.MemberCells(member)
+
+	// Report the status of the cells from the member that deploys them.
+	for name, cellStatus := range member.Status.Cells {
+		if cells.Has(name) {
+			vt.Status.Cells[name] = cellStatus
+		}
+	}
+	return status
+}
+
+// remoteCells returns the cells that aren't deployed alongside the
+// VitessCluster: cells placed elsewhere, and cells that members still deploy.
+func remoteCells(vt *planetscalev2.VitessCluster) sets.String {
+	cells := sets.NewString()
+	for i := range vt.Spec.Cells {
+		if vt.Spec.Cells[i].IsPlaced(vt.Namespace) {
+			cells.Insert(vt.Spec.Cells[i].Name)
+		}
+	}
+	for i := range vt.Status.Members {
+		cells.Insert(vt.Status.Members[i].Cells...)
+	}
+	return cells
+}
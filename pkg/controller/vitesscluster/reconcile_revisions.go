@@ -0,0 +1,113 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// reconcileRevisions records the cluster-wide images and extraVitessFlags in
+// the revision history, or if spec.rollbackTo is set, replaces them in memory
+// with the ones from that revision. Everything that's reconciled afterwards
+// then sees the rolled-back values, so Pods get rolled back through the same
+// update strategy as any other change.
+func (r *ReconcileVitessCluster) reconcileRevisions(vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus, now time.Time) {
+	vt.Status.RevisionHistory = oldStatus.RevisionHistory
+
+	if vt.Spec.RollbackTo != nil {
+		revision := findRevision(oldStatus.RevisionHistory, *vt.Spec.RollbackTo)
+		if revision == nil {
+			r.recorder.Eventf(vt, corev1.EventTypeWarning, "RollbackFailed", "revision %d is not in the revision history; using the images and extraVitessFlags from the spec", *vt.Spec.RollbackTo)
+			return
+		}
+		vt.Spec.Images = *revision.Images.DeepCopy()
+		vt.Spec.ExtraVitessFlags = make(map[string]string, len(revision.ExtraVitessFlags))
+		for key, value := range revision.ExtraVitessFlags {
+			vt.Spec.ExtraVitessFlags[key] = value
+		}
+		vt.Status.RolledBackTo = &revision.Revision
+		if oldStatus.RolledBackTo == nil || *oldStatus.RolledBackTo != revision.Revision {
+			r.recorder.Eventf(vt, corev1.EventTypeNormal, "RollingBack", "Rolling back images and extraVitessFlags to revision %d.", revision.Revision)
+		}
+		return
+	}
+
+	vt.Status.RevisionHistory = recordRevision(oldStatus.RevisionHistory, &vt.Spec, int(*vt.Spec.RevisionHistoryLimit), now)
+}
+
+// findRevision returns the revision with the given number, or nil if it's not
+// in the history.
+func findRevision(history []planetscalev2.VitessClusterRevision, number int64) *planetscalev2.VitessClusterRevision {
+	for i := range history {
+		if history[i].Revision == number {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+// recordRevision returns the revision history with the images and
+// extraVitessFlags from the spec added as a new revision, if they differ from
+// the newest one, keeping at most limit revisions.
+func recordRevision(history []planetscalev2.VitessClusterRevision, spec *planetscalev2.VitessClusterSpec, limit int, now time.Time) []planetscalev2.VitessClusterRevision {
+	var number int64 = 1
+	if len(history) > 0 {
+		newest := &history[0]
+		if apiequality.Semantic.DeepEqual(newest.Images, spec.Images) && equalFlags(newest.ExtraVitessFlags, spec.ExtraVitessFlags) {
+			return history
+		}
+		number = newest.Revision + 1
+	}
+
+	revision := planetscalev2.VitessClusterRevision{
+		Revision: number,
+		Time:     metav1.Time{Time: now},
+		Images:   *spec.Images.DeepCopy(),
+	}
+	if len(spec.ExtraVitessFlags) > 0 {
+		revision.ExtraVitessFlags = make(map[string]string, len(spec.ExtraVitessFlags))
+		for key, value := range spec.ExtraVitessFlags {
+			revision.ExtraVitessFlags[key] = value
+		}
+	}
+
+	newHistory := append([]planetscalev2.VitessClusterRevision{revision}, history...)
+	if len(newHistory) > limit {
+		newHistory = newHistory[:limit]
+	}
+	return newHistory
+}
+
+// equalFlags returns whether two sets of flags are the same, treating nil and
+// empty as equal.
+func equalFlags(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestReconcileRevisions(t *testing.T) {
+	r := &ReconcileVitessCluster{recorder: record.NewFakeRecorder(100)}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	newCluster := func(vttablet string, flags map[string]string, status planetscalev2.VitessClusterStatus) *planetscalev2.VitessCluster {
+		vt := &planetscalev2.VitessCluster{}
+		vt.Spec.Images.Vttablet = vttablet
+		vt.Spec.ExtraVitessFlags = flags
+		vt.Spec.RevisionHistoryLimit = pointer.Int32Ptr(2)
+		vt.Status = status
+		return vt
+	}
+	reconcile := func(vt *planetscalev2.VitessCluster, now time.Time) {
+		oldStatus := vt.Status
+		vt.Status = planetscalev2.NewVitessClusterStatus()
+		r.reconcileRevisions(vt, &oldStatus, now)
+	}
+
+	// The first revision is recorded, and not again while nothing changes.
+	vt := newCluster("vitess:v1", nil, planetscalev2.VitessClusterStatus{})
+	reconcile(vt, start)
+	reconcile(vt, start.Add(time.Minute))
+	assert.Equal(t, []int64{1}, revisionNumbers(vt.Status.RevisionHistory))
+	assert.Equal(t, start, vt.Status.RevisionHistory[0].Time.Time)
+
+	// A flag change is a new revision.
+	vt = newCluster("vitess:v1", map[string]string{"queryserver-config-pool-size": "32"}, vt.Status)
+	reconcile(vt, start.Add(time.Hour))
+	assert.Equal(t, []int64{2, 1}, revisionNumbers(vt.Status.RevisionHistory))
+
+	// An image change is too, and the oldest revision is trimmed.
+	vt = newCluster("vitess:v2", map[string]string{"queryserver-config-pool-size": "32"}, vt.Status)
+	reconcile(vt, start.Add(2*time.Hour))
+	assert.Equal(t, []int64{3, 2}, revisionNumbers(vt.Status.RevisionHistory))
+
+	// Rolling back replaces the images and flags in memory without recording
+	// a new revision.
+	vt = newCluster("vitess:v2", map[string]string{"queryserver-config-pool-size": "32"}, vt.Status)
+	vt.Spec.RollbackTo = pointer.Int64Ptr(2)
+	vt.Spec.ExtraVitessFlags = nil
+	reconcile(vt, start.Add(3*time.Hour))
+	assert.Equal(t, "vitess:v1", vt.Spec.Images.Vttablet)
+	assert.Equal(t, map[string]string{"queryserver-config-pool-size": "32"}, vt.Spec.ExtraVitessFlags)
+	assert.Equal(t, pointer.Int64Ptr(2), vt.Status.RolledBackTo)
+	assert.Equal(t, []int64{3, 2}, revisionNumbers(vt.Status.RevisionHistory))
+
+	// A revision that's not in the history leaves the spec alone.
+	vt = newCluster("vitess:v2", nil, vt.Status)
+	vt.Spec.RollbackTo = pointer.Int64Ptr(1)
+	reconcile(vt, start.Add(4*time.Hour))
+	assert.Equal(t, "vitess:v2", vt.Spec.Images.Vttablet)
+	assert.Nil(t, vt.Status.RolledBackTo)
+	assert.Equal(t, []int64{3, 2}, revisionNumbers(vt.Status.RevisionHistory))
+
+	// Once rollbackTo is cleared, the fixed spec is recorded as usual.
+	vt = newCluster("vitess:v3", nil, vt.Status)
+	reconcile(vt, start.Add(5*time.Hour))
+	assert.Equal(t, []int64{4, 3}, revisionNumbers(vt.Status.RevisionHistory))
+	assert.Equal(t, "vitess:v3", vt.Status.RevisionHistory[0].Images.Vttablet)
+}
+
+func revisionNumbers(history []planetscalev2.VitessClusterRevision) []int64 {
+	numbers := make([]int64, 0, len(history))
+	for _, revision := range history {
+		numbers = append(numbers, revision.Revision)
+	}
+	return numbers
+}
@@ -46,7 +46,7 @@ func (r *ReconcileVitessCluster) reconcileTopology(ctx context.Context, vt *plan
 	resultBuilder := &results.Builder{}
 
 	// Connect to the global lockserver.
-	globalParams := lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name)
+	globalParams := lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name)
 	if globalParams == nil {
 		// This is an invalid config. There's no reason to request a retry. Just wait for the next mutation to trigger us.
 		r.recorder.Event(vt, corev1.EventTypeWarning, "TopoInvalid", "no global lockserver is defined")
@@ -79,6 +79,23 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 		desiredCells[cell.Name] = &cell.Lockserver
 	}
 
+	// Members register the cells they deploy (see reconcileMembers), but
+	// those cells are still part of this cluster, so they aren't pruned.
+	remote := remoteCells(vt)
+	localCells := make(map[string]*planetscalev2.LockserverSpec, len(desiredCells))
+	keepCells := make(map[string]*planetscalev2.LockserverSpec, len(desiredCells))
+	for name, spec := range desiredCells {
+		keepCells[name] = spec
+		if !remote.Has(name) {
+			localCells[name] = spec
+		}
+	}
+	for _, name := range remote.List() {
+		if keepCells[name] == nil {
+			keepCells[name] = &planetscalev2.LockserverSpec{}
+		}
+	}
+
 	if *vt.Spec.TopologyReconciliation.RegisterCellsAliases {
 		// We need to add an alias for all the cells in each region so that vtgate
 		// knows that it can route traffic between them.
@@ -102,10 +119,10 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 			EventObj:         vt,
 			TopoServer:       ts,
 			Recorder:         r.recorder,
-			GlobalLockserver: &vt.Spec.GlobalLockserver,
+			GlobalLockserver: lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver),
 			ClusterName:      vt.Name,
 			GlobalTopoImpl:   globalTopoImpl,
-			DesiredCells:     desiredCells,
+			DesiredCells:     localCells,
 		})
 		resultBuilder.Merge(result, err)
 	}
@@ -119,7 +136,7 @@ func (r *ReconcileVitessCluster) reconcileCellTopology(ctx context.Context, vt *
 			EventObj:      vt,
 			TopoServer:    ts,
 			Recorder:      r.recorder,
-			DesiredCells:  desiredCells,
+			DesiredCells:  keepCells,
 			OrphanedCells: vt.Status.OrphanedCells,
 		})
 		resultBuilder.Merge(result, err)
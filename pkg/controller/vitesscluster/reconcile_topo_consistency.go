@@ -0,0 +1,156 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesstopo"
+)
+
+const (
+	// topoConsistencyTimeout is how long to spend on one consistency check,
+	// which lists every tablet in every cell.
+	topoConsistencyTimeout = 30 * time.Second
+
+	// maxTopologyDrift is how many drifted records to list in the status.
+	maxTopologyDrift = 20
+)
+
+// reconcileTopologyConsistency periodically compares topo records against the
+// resources the operator manages, repairs what it's allowed to, and reports
+// the rest in the status. Between checks, the last outcome is carried over.
+func (r *ReconcileVitessCluster) reconcileTopologyConsistency(ctx context.Context, vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	conf := vt.Spec.TopologyReconciliation
+	interval := conf.ConsistencyCheckInterval.Duration
+	if interval <= 0 {
+		return resultBuilder.Result()
+	}
+
+	vt.Status.TopologyConsistency = oldStatus.TopologyConsistency.DeepCopy()
+	now := time.Now()
+	var lastCheckTime *metav1.Time
+	if vt.Status.TopologyConsistency != nil {
+		lastCheckTime = vt.Status.TopologyConsistency.LastCheckTime
+	}
+	if due, wait := consistencyCheckDue(lastCheckTime, interval, now); !due {
+		return resultBuilder.RequeueAfter(wait)
+	}
+
+	// Find the shards that are actually managed by a VitessShard.
+	shardList := &planetscalev2.VitessShardList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(vt.Namespace),
+		client.MatchingLabels{planetscalev2.ClusterLabel: vt.Name},
+	}
+	if err := r.client.List(ctx, shardList, listOpts...); err != nil {
+		return resultBuilder.Error(err)
+	}
+	shards := sets.NewString()
+	for i := range shardList.Items {
+		vts := &shardList.Items[i]
+		shards.Insert(vts.Labels[planetscalev2.KeyspaceLabel] + "/" + vts.Spec.Name)
+	}
+	cells := sets.NewString()
+	for i := range vt.Spec.Cells {
+		cells.Insert(vt.Spec.Cells[i].Name)
+	}
+	keyspaces := sets.NewString()
+	for i := range vt.Spec.Keyspaces {
+		keyspaces.Insert(vt.Spec.Keyspaces[i].Name)
+	}
+
+	globalParams := lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name)
+	if globalParams == nil {
+		// reconcileTopology already reported this.
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, topoConsistencyTimeout)
+	defer cancel()
+
+	ts, err := toposerver.Open(ctx, *globalParams)
+	if err != nil {
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	defer ts.Close()
+
+	report, err := vitesstopo.CheckConsistency(ctx, vitesstopo.CheckConsistencyParams{
+		EventObj:           vt,
+		TopoServer:         ts.Server,
+		Recorder:           r.recorder,
+		Cells:              cells,
+		Keyspaces:          keyspaces,
+		Shards:             shards,
+		PruneTablets:       *conf.PruneTablets,
+		RepairServingGraph: *conf.RepairServingGraph,
+	})
+	if err != nil {
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoConsistencyCheckFailed", "failed to check topology consistency: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	vt.Status.TopologyConsistency = topologyConsistencyStatus(report, now)
+	if len(report.Drift) > 0 && (oldStatus.TopologyConsistency == nil || oldStatus.TopologyConsistency.Consistent != corev1.ConditionFalse) {
+		r.recorder.Eventf(vt, corev1.EventTypeWarning, "TopoDrift", "found %d topology records that don't match the managed resources", len(report.Drift))
+	}
+	return resultBuilder.RequeueAfter(interval)
+}
+
+// consistencyCheckDue returns whether it's time for the next consistency
+// check, and if not, how long until it is.
+func consistencyCheckDue(lastCheckTime *metav1.Time, interval time.Duration, now time.Time) (bool, time.Duration) {
+	if lastCheckTime == nil {
+		return true, 0
+	}
+	next := lastCheckTime.Add(interval)
+	if next.After(now) {
+		return false, next.Sub(now)
+	}
+	return true, 0
+}
+
+// topologyConsistencyStatus turns the outcome of a consistency check into a
+// status, keeping only as much of the drift as fits in the status.
+func topologyConsistencyStatus(report *vitesstopo.ConsistencyReport, now time.Time) *planetscalev2.TopologyConsistencyStatus {
+	drift := report.Drift
+	if len(drift) > maxTopologyDrift {
+		drift = drift[:maxTopologyDrift]
+	}
+	return &planetscalev2.TopologyConsistencyStatus{
+		LastCheckTime: &metav1.Time{Time: now},
+		Consistent:    k8s.ConditionStatus(len(report.Drift) == 0),
+		Repaired:      report.Repaired,
+		Drift:         drift,
+	}
+}
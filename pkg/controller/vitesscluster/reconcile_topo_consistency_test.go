@@ -0,0 +1,64 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesstopo"
+)
+
+func TestConsistencyCheckDue(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	due, _ := consistencyCheckDue(nil, 10*time.Minute, now)
+	assert.True(t, due, "first check")
+
+	due, wait := consistencyCheckDue(&metav1.Time{Time: now.Add(-4 * time.Minute)}, 10*time.Minute, now)
+	assert.False(t, due)
+	assert.Equal(t, 6*time.Minute, wait)
+
+	due, _ = consistencyCheckDue(&metav1.Time{Time: now.Add(-10 * time.Minute)}, 10*time.Minute, now)
+	assert.True(t, due, "interval passed")
+}
+
+func TestTopologyConsistencyStatus(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	status := topologyConsistencyStatus(&vitesstopo.ConsistencyReport{Repaired: 2}, now)
+	assert.Equal(t, corev1.ConditionTrue, status.Consistent)
+	assert.Equal(t, int32(2), status.Repaired)
+	assert.Empty(t, status.Drift)
+
+	report := &vitesstopo.ConsistencyReport{}
+	for i := 0; i < maxTopologyDrift+5; i++ {
+		report.Drift = append(report.Drift, planetscalev2.TopologyDrift{
+			Kind: planetscalev2.TopologyTabletRecord,
+			Name: fmt.Sprintf("zone1-%010d", i),
+		})
+	}
+	status = topologyConsistencyStatus(report, now)
+	assert.Equal(t, corev1.ConditionFalse, status.Consistent)
+	assert.Len(t, status.Drift, maxTopologyDrift)
+}
@@ -23,6 +23,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,12 +53,15 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 		return resultBuilder.Result()
 	}
 
-	if len(vt.Spec.VtAdmin.APIAddresses) == 0 {
-		log.Errorf("Not deploying vtadmin since api addresses field is not specified. Atleast 1 value is required")
+	apiAddresses := vtadminAPIAddresses(vt.Spec.VtAdmin)
+	if len(apiAddresses) == 0 {
+		log.Errorf("Not deploying vtadmin since api addresses field is not specified and there's no ingress. Atleast 1 value is required")
+		return resultBuilder.Result()
 	}
 
-	if len(vt.Spec.VtAdmin.APIAddresses) != 1 && len(vt.Spec.VtAdmin.APIAddresses) != len(vt.Spec.VtAdmin.Cells) {
+	if len(apiAddresses) != 1 && len(apiAddresses) != len(vt.Spec.VtAdmin.Cells) {
 		log.Errorf("Not deploying vtadmin since api addresses field doesn't align with cells field")
+		return resultBuilder.Result()
 	}
 
 	key := client.ObjectKey{Namespace: vt.Namespace, Name: vtadmin.ServiceName(vt.Name)}
@@ -90,8 +94,26 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 		resultBuilder.Error(err)
 	}
 
+	// Reconcile vtadmin Ingress, if any.
+	ingressKey := client.ObjectKey{Namespace: vt.Namespace, Name: vtadmin.IngressName(vt.Name)}
+	ingress := vt.Spec.VtAdmin.Ingress
+	err = r.reconciler.ReconcileObject(ctx, vt, ingressKey, labels, ingress != nil, reconciler.Strategy{
+		Kind: &networkingv1.Ingress{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vtadmin.NewIngress(key, labels, vtadmin.ServiceName(vt.Name), ingress)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			vtadmin.UpdateIngress(obj.(*networkingv1.Ingress), labels, vtadmin.ServiceName(vt.Name), ingress)
+		},
+	})
+	if err != nil {
+		// Record error but continue.
+		resultBuilder.Error(err)
+	}
+
 	// Reconcile vtadmin Deployments.
-	specs, err := r.vtadminSpecs(ctx, vt, labels)
+	specs, err := r.vtadminSpecs(ctx, vt, labels, apiAddresses)
 	if err != nil {
 		// Record error and stop.
 		resultBuilder.Error(err)
@@ -108,15 +130,20 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 		specMap[key] = spec
 	}
 
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := rolloutPaused(vt)
+	immediate := !paused && *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType
+
 	err = r.reconciler.ReconcileObjectSet(ctx, vt, keys, labels, reconciler.Strategy{
-		Kind: &appsv1.Deployment{},
+		Kind:          &appsv1.Deployment{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return vtadmin.NewDeployment(key, specMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*appsv1.Deployment)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if immediate {
 				vtadmin.UpdateDeployment(newObj, specMap[key])
 				return
 			}
@@ -150,7 +177,7 @@ func (r *ReconcileVitessCluster) reconcileVtadmin(ctx context.Context, vt *plane
 	return resultBuilder.Result()
 }
 
-func (r *ReconcileVitessCluster) vtadminSpecs(ctx context.Context, vt *planetscalev2.VitessCluster, parentLabels map[string]string) ([]*vtadmin.Spec, error) {
+func (r *ReconcileVitessCluster) vtadminSpecs(ctx context.Context, vt *planetscalev2.VitessCluster, parentLabels map[string]string, apiAddresses []string) ([]*vtadmin.Spec, error) {
 	var cells []*planetscalev2.VitessCellTemplate
 	if len(vt.Spec.VtAdmin.Cells) != 0 {
 		// Deploy only to the specified cells.
@@ -188,10 +215,10 @@ func (r *ReconcileVitessCluster) vtadminSpecs(ctx context.Context, vt *planetsca
 			return nil, err
 		}
 
-		// We have already checked that atleast 1 value should be available in APIAddresses
-		apiAddress := vt.Spec.VtAdmin.APIAddresses[0]
-		if len(vt.Spec.VtAdmin.APIAddresses) > 1 {
-			apiAddress = vt.Spec.VtAdmin.APIAddresses[idx]
+		// We have already checked that atleast 1 value should be available in apiAddresses
+		apiAddress := apiAddresses[0]
+		if len(apiAddresses) > 1 {
+			apiAddress = apiAddresses[idx]
 		}
 
 		webConfigSecret, err := r.createWebConfigSecret(ctx, vt, cell, apiAddress)
@@ -227,6 +254,16 @@ func (r *ReconcileVitessCluster) vtadminSpecs(ctx context.Context, vt *planetsca
 	return specs, nil
 }
 
+// vtadminAPIAddresses returns the addresses at which vtadmin-web should reach
+// vtadmin-api. If none are given, the Ingress address is used, since the
+// Ingress routes API requests to vtadmin-api.
+func vtadminAPIAddresses(spec *planetscalev2.VtAdminSpec) []string {
+	if len(spec.APIAddresses) != 0 || spec.Ingress == nil {
+		return spec.APIAddresses
+	}
+	return []string{vtadmin.IngressAddress(spec.Ingress)}
+}
+
 func (r *ReconcileVitessCluster) createDiscoverySecret(ctx context.Context, vt *planetscalev2.VitessCluster, cell *planetscalev2.VitessCellTemplate) (*planetscalev2.SecretSource, *planetscalev2.SecretSource, error) {
 	// Get the vtctld service
 	vtctldService := corev1.Service{}
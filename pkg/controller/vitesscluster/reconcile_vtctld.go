@@ -23,15 +23,21 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
 	"planetscale.dev/vitess-operator/pkg/operator/lockserver"
+	"planetscale.dev/vitess-operator/pkg/operator/pdb"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/vtctld"
 )
 
@@ -67,8 +73,44 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		resultBuilder.Error(err)
 	}
 
+	// Reconcile the certificates for the vtctld gRPC API.
+	grpcTLSSecretName, err := r.reconcileVtctldCerts(ctx, vt, labels)
+	if err != nil {
+		// Record error and return, to avoid generating a Deployment based on incomplete information.
+		return resultBuilder.Error(err)
+	}
+	var grpcStaticAuth *planetscalev2.SecretSource
+	if grpc := vt.Spec.VitessDashboard.GRPC; grpc != nil && grpc.Authentication != nil && grpc.Authentication.Static != nil {
+		grpcStaticAuth = grpc.Authentication.Static.Secret
+	}
+
+	// Restart vtctld when the Secrets it reads at startup change.
+	reloadSecretNames := sets.NewString()
+	if grpcTLSSecretName != "" {
+		reloadSecretNames.Insert(grpcTLSSecretName)
+	}
+	if grpcStaticAuth != nil && grpcStaticAuth.Name != "" {
+		reloadSecretNames.Insert(grpcStaticAuth.Name)
+	}
+	var annotations map[string]string
+	if reloadSecretNames.Len() != 0 {
+		grpcSecrets, err := secrets.GetByNames(ctx, r.client, vt.Namespace, reloadSecretNames)
+		if err != nil {
+			return resultBuilder.Error(err)
+		}
+		annotations = map[string]string{
+			"planetscale.com/secret-hash": secrets.ContentHash(grpcSecrets...),
+		}
+	}
+	update.Annotations(&annotations, vt.Spec.VitessDashboard.Annotations)
+
 	// Reconcile vtctld Deployments.
 	specs := r.vtctldSpecs(vt, labels)
+	for _, spec := range specs {
+		spec.Annotations = annotations
+		spec.GRPCTLSSecretName = grpcTLSSecretName
+		spec.GRPCStaticAuth = grpcStaticAuth
+	}
 
 	// Generate keys (object names) for all desired vtctld Deployments.
 	// Keep a map back from generated names to the vtctld specs.
@@ -80,15 +122,20 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		specMap[key] = spec
 	}
 
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := rolloutPaused(vt)
+	immediate := !paused && *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType
+
 	err = r.reconciler.ReconcileObjectSet(ctx, vt, keys, labels, reconciler.Strategy{
-		Kind: &appsv1.Deployment{},
+		Kind:          &appsv1.Deployment{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return vtctld.NewDeployment(key, specMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*appsv1.Deployment)
-			if *vt.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if immediate {
 				vtctld.UpdateDeployment(newObj, specMap[key])
 				return
 			}
@@ -112,6 +159,8 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 				}
 			}
 
+			vt.Status.RolloutStatus.Vtctld.Add(rollout.DeploymentProgress(curObj))
+
 			// TODO(enisoc): Aggregate other important parts of status besides conditions.
 		},
 	})
@@ -119,6 +168,33 @@ func (r *ReconcileVitessCluster) reconcileVtctld(ctx context.Context, vt *planet
 		resultBuilder.Error(err)
 	}
 
+	// Reconcile a vtctld PDB for each Deployment.
+	pdbKeys := keys
+	if !pdb.Wanted(vt.Spec.VitessDashboard.PodDisruptionBudget) {
+		pdbKeys = nil
+	}
+	pdbSpec := func(key client.ObjectKey) *pdb.Spec {
+		return &pdb.Spec{
+			Labels:   labels,
+			Selector: &metav1.LabelSelector{MatchLabels: specMap[key].Labels},
+			Replicas: specMap[key].Replicas,
+			Config:   vt.Spec.VitessDashboard.PodDisruptionBudget,
+		}
+	}
+	err = r.reconciler.ReconcileObjectSet(ctx, vt, pdbKeys, labels, reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return pdb.NewPodDisruptionBudget(key, pdbSpec(key))
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			pdb.UpdatePodDisruptionBudget(obj.(*policyv1.PodDisruptionBudget), pdbSpec(key))
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
 	return resultBuilder.Result()
 }
 
@@ -141,7 +217,7 @@ func (r *ReconcileVitessCluster) vtctldSpecs(vt *planetscalev2.VitessCluster, pa
 		}
 	}
 
-	glsParams := lockserver.GlobalConnectionParams(&vt.Spec.GlobalLockserver, vt.Namespace, vt.Name)
+	glsParams := lockserver.GlobalConnectionParams(lockserver.ActiveGlobalSpec(&vt.Spec.GlobalLockserver, &vt.Status.GlobalLockserver), vt.Namespace, vt.Name)
 
 	// Make a vtctld Deployment spec for each cell.
 	specs := make([]*vtctld.Spec, 0, len(cells))
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vtctld"
+)
+
+// reconcileVtctldCerts mints the certificates for the vtctld gRPC API, if
+// TLS is enabled without a Secret from elsewhere, and returns the name of the
+// Secret that holds the server certificate, if any.
+func (r *ReconcileVitessCluster) reconcileVtctldCerts(ctx context.Context, vt *planetscalev2.VitessCluster, labels map[string]string) (string, error) {
+	var tls *planetscalev2.VitessDashboardTLS
+	if vt.Spec.VitessDashboard.GRPC != nil {
+		tls = vt.Spec.VitessDashboard.GRPC.TLS
+	}
+	if tls != nil && tls.CertSecretName != "" {
+		// The certificates are managed elsewhere, e.g. by cert-manager.
+		// Clean up any that we minted before.
+		return tls.CertSecretName, r.reconcileVtctldCertSecrets(ctx, vt, labels, nil, nil)
+	}
+	if tls == nil {
+		return "", r.reconcileVtctldCertSecrets(ctx, vt, labels, nil, nil)
+	}
+
+	// Load the certificate authority, or mint one if there isn't one yet.
+	caKey := client.ObjectKey{Namespace: vt.Namespace, Name: vtctld.CASecretName(vt.Name)}
+	caSecret := &corev1.Secret{}
+	var ca *vtctld.CA
+	err := r.client.Get(ctx, caKey, caSecret)
+	switch {
+	case err == nil:
+		ca, err = vtctld.ParseCA(caSecret)
+	case apierrors.IsNotFound(err):
+		ca, err = vtctld.NewCA(vt.Name, time.Now())
+	}
+	if err != nil {
+		return "", err
+	}
+
+	serverDNSNames := vtctld.ServerDNSNames(vt.Namespace, vt.Name, tls.DNSNames)
+	if err := r.reconcileVtctldCertSecrets(ctx, vt, labels, ca, serverDNSNames); err != nil {
+		return "", err
+	}
+	vt.Status.VitessDashboard.GRPCClientCertSecretName = vtctld.ClientCertSecretName(vt.Name)
+	return vtctld.ServerCertSecretName(vt.Name), nil
+}
+
+// reconcileVtctldCertSecrets reconciles the Secrets that hold the certificate
+// authority and certificates the operator minted. If ca is nil, they're
+// deleted.
+func (r *ReconcileVitessCluster) reconcileVtctldCertSecrets(ctx context.Context, vt *planetscalev2.VitessCluster, labels map[string]string, ca *vtctld.CA, serverDNSNames []string) error {
+	wanted := ca != nil
+
+	err := r.reconciler.ReconcileObject(ctx, vt, client.ObjectKey{Namespace: vt.Namespace, Name: vtctld.CASecretName(vt.Name)}, labels, wanted, reconciler.Strategy{
+		Kind: &corev1.Secret{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vtctld.NewCASecret(key, labels, ca)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			update.Labels(&obj.(*corev1.Secret).Labels, labels)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileVtctldCertSecret(ctx, vt, vtctld.ServerCertSecretName(vt.Name), labels, ca, planetscalev2.VtctldComponentName, serverDNSNames); err != nil {
+		return err
+	}
+	return r.reconcileVtctldCertSecret(ctx, vt, vtctld.ClientCertSecretName(vt.Name), labels, ca, vtctld.ClientCommonName, nil)
+}
+
+// reconcileVtctldCertSecret reconciles a Secret that holds a certificate the
+// operator minted. A new certificate is minted when the Secret doesn't exist
+// yet, or when the certificate in it needs to be renewed.
+func (r *ReconcileVitessCluster) reconcileVtctldCertSecret(ctx context.Context, vt *planetscalev2.VitessCluster, name string, labels map[string]string, ca *vtctld.CA, commonName string, dnsNames []string) error {
+	key := client.ObjectKey{Namespace: vt.Namespace, Name: name}
+
+	var minted *corev1.Secret
+	if ca != nil {
+		now := time.Now()
+		existing := &corev1.Secret{}
+		err := r.client.Get(ctx, key, existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err != nil || !vtctld.CertUpToDate(existing, ca, dnsNames, now) {
+			minted, err = vtctld.NewCertSecret(key, labels, ca, commonName, dnsNames, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.reconciler.ReconcileObject(ctx, vt, key, labels, ca != nil, reconciler.Strategy{
+		Kind: &corev1.Secret{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return minted
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			secret := obj.(*corev1.Secret)
+			update.Labels(&secret.Labels, labels)
+			if minted != nil {
+				r.recorder.Eventf(vt, corev1.EventTypeNormal, "CertificateRenewed", "Renewed vtctld certificate in Secret %v.", secret.Name)
+				secret.Data = minted.Data
+			}
+		},
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// observeRolloutChild notes whether a child VitessCell or VitessKeyspace has
+// observed its latest generation. Until every child has, the rollout status
+// can't say whether the latest changes have been rolled out.
+func observeRolloutChild(status *planetscalev2.VitessClusterRolloutStatus, obj metav1.Object, observedGeneration int64) {
+	if observedGeneration != obj.GetGeneration() {
+		status.Complete = corev1.ConditionUnknown
+	}
+}
+
+// childRolloutCounts returns the rollout counts for a child object. If the
+// child has changes scheduled, none of its Pods have them yet.
+func childRolloutCounts(obj metav1.Object, counts planetscalev2.VitessRolloutCounts) planetscalev2.VitessRolloutCounts {
+	if rollout.Scheduled(obj) {
+		return planetscalev2.VitessRolloutCounts{Desired: counts.Desired, Pending: counts.Desired}
+	}
+	return counts
+}
+
+// keyspaceRolloutCounts returns how far along the rollout of tablets in a
+// keyspace is, based on the status of each of its shards.
+func keyspaceRolloutCounts(vtk *planetscalev2.VitessKeyspace) planetscalev2.VitessRolloutCounts {
+	var counts planetscalev2.VitessRolloutCounts
+	for _, shard := range vtk.Status.Shards {
+		updated := shard.UpdatedTablets - shard.FailedTablets
+		if updated > shard.DesiredTablets {
+			updated = shard.DesiredTablets
+		}
+		counts.Desired += shard.DesiredTablets
+		counts.Updated += updated
+		counts.Failed += shard.FailedTablets
+		if pending := shard.DesiredTablets - updated - shard.FailedTablets; pending > 0 {
+			counts.Pending += pending
+		}
+	}
+	return counts
+}
+
+// updateRolloutStatus finishes rolling up the progress of the rollout, once
+// the counts for each component have been filled in. The observed generation
+// only advances once every child object has observed its latest generation.
+func updateRolloutStatus(vt *planetscalev2.VitessCluster, oldStatus *planetscalev2.VitessClusterStatus) {
+	status := &vt.Status.RolloutStatus
+	for _, counts := range status.Keyspaces {
+		status.Vttablet.Add(counts)
+	}
+
+	if status.Complete == corev1.ConditionUnknown {
+		status.ObservedGeneration = oldStatus.RolloutStatus.ObservedGeneration
+		return
+	}
+	status.ObservedGeneration = vt.Generation
+	status.Complete = corev1.ConditionFalse
+	if status.Vtctld.Done() && status.Vtgate.Done() && status.Vttablet.Done() {
+		status.Complete = corev1.ConditionTrue
+	}
+}
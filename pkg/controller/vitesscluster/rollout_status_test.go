@@ -0,0 +1,106 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesscluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestKeyspaceRolloutCounts(t *testing.T) {
+	vtk := &planetscalev2.VitessKeyspace{}
+	vtk.Status.Shards = map[string]planetscalev2.VitessKeyspaceShardStatus{
+		"-80": {DesiredTablets: 3, Tablets: 3, UpdatedTablets: 3},
+		"80-": {DesiredTablets: 3, Tablets: 3, UpdatedTablets: 2, FailedTablets: 1},
+		// A surge tablet can make more tablets up-to-date than are desired.
+		"c0-": {DesiredTablets: 2, Tablets: 3, UpdatedTablets: 3},
+	}
+	want := planetscalev2.VitessRolloutCounts{Desired: 8, Updated: 6, Pending: 1, Failed: 1}
+	assert.Equal(t, want, keyspaceRolloutCounts(vtk))
+
+	rollout.Schedule(vtk, "new image")
+	assert.Equal(t, planetscalev2.VitessRolloutCounts{Desired: 8, Pending: 8}, childRolloutCounts(vtk, keyspaceRolloutCounts(vtk)))
+}
+
+func TestUpdateRolloutStatus(t *testing.T) {
+	done := planetscalev2.VitessRolloutCounts{Desired: 2, Updated: 2}
+	pending := planetscalev2.VitessRolloutCounts{Desired: 2, Updated: 1, Pending: 1}
+
+	tests := []struct {
+		name           string
+		status         planetscalev2.VitessClusterRolloutStatus
+		wantComplete   corev1.ConditionStatus
+		wantGeneration int64
+	}{
+		{
+			name: "everything up-to-date",
+			status: planetscalev2.VitessClusterRolloutStatus{
+				Vtctld:    done,
+				Vtgate:    done,
+				Keyspaces: map[string]planetscalev2.VitessRolloutCounts{"ks1": done, "ks2": done},
+			},
+			wantComplete:   corev1.ConditionTrue,
+			wantGeneration: 3,
+		},
+		{
+			name: "keyspace pending",
+			status: planetscalev2.VitessClusterRolloutStatus{
+				Vtctld:    done,
+				Vtgate:    done,
+				Keyspaces: map[string]planetscalev2.VitessRolloutCounts{"ks1": done, "ks2": pending},
+			},
+			wantComplete:   corev1.ConditionFalse,
+			wantGeneration: 3,
+		},
+		{
+			name: "child hasn't observed changes",
+			status: planetscalev2.VitessClusterRolloutStatus{
+				Complete:  corev1.ConditionUnknown,
+				Vtctld:    done,
+				Vtgate:    done,
+				Keyspaces: map[string]planetscalev2.VitessRolloutCounts{"ks1": done},
+			},
+			wantComplete:   corev1.ConditionUnknown,
+			wantGeneration: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vt := &planetscalev2.VitessCluster{}
+			vt.Generation = 3
+			vt.Status.RolloutStatus = tt.status
+			oldStatus := &planetscalev2.VitessClusterStatus{}
+			oldStatus.RolloutStatus.ObservedGeneration = 2
+
+			updateRolloutStatus(vt, oldStatus)
+			status := vt.Status.RolloutStatus
+			assert.Equal(t, tt.wantComplete, status.Complete)
+			assert.Equal(t, tt.wantGeneration, status.ObservedGeneration)
+
+			var wantVttablet planetscalev2.VitessRolloutCounts
+			for _, counts := range tt.status.Keyspaces {
+				wantVttablet.Add(counts)
+			}
+			assert.Equal(t, wantVttablet, status.Vttablet)
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,10 +40,12 @@ import (
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/federation"
 	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 )
 
 const (
@@ -58,7 +62,10 @@ var log = logrus.WithField("controller", "VitessCluster")
 // watchResources should contain all the resource types that this controller creates.
 var watchResources = []client.Object{
 	&corev1.Service{},
+	&corev1.Secret{},
 	&appsv1.Deployment{},
+	&policyv1.PodDisruptionBudget{},
+	&networkingv1.Ingress{},
 
 	&planetscalev2.VitessCell{},
 	&planetscalev2.VitessKeyspace{},
@@ -84,6 +91,7 @@ func newReconciler(mgr manager.Manager) *ReconcileVitessCluster {
 		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
 		recorder:   recorder,
 		reconciler: reconciler.New(c, scheme, recorder),
+		members:    federation.NewClients(c, scheme),
 	}
 }
 
@@ -135,6 +143,7 @@ type ReconcileVitessCluster struct {
 	resync     *resync.Periodic
 	recorder   record.EventRecorder
 	reconciler *reconciler.Reconciler
+	members    *federation.Clients
 }
 
 // Reconcile reads that state of the cluster for a VitessCluster object and makes changes based on the state read
@@ -175,17 +184,30 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 	// TODO(enisoc): Use versioned defaults when operator-sdk supports mutating webhooks.
 	planetscalev2.DefaultVitessCluster(vt)
 
+	// Record the applied images and flags, or roll them back if requested.
+	r.reconcileRevisions(vt, &oldStatus, time.Now())
+
 	// Create/update global etcd, if requested.
 	if err := r.reconcileGlobalEtcd(ctx, vt); err != nil {
 		// Record result but continue to reconcile cells.
 		resultBuilder.Error(err)
 	}
 
+	// Copy topology data to the target of a lockserver migration, if requested.
+	// This decides which global lockserver the steps below pass on.
+	migrationResult, err := r.reconcileLockserverMigration(ctx, vt, &oldStatus)
+	resultBuilder.Merge(migrationResult, err)
+
 	// Create/update VitessBackupStorage objects.
 	if err := r.reconcileBackupStorage(ctx, vt); err != nil {
 		resultBuilder.Error(err)
 	}
 
+	// Create/update member VitessClusters for cells placed elsewhere.
+	// This decides which cells the steps below deploy here.
+	membersResult, err := r.reconcileMembers(ctx, vt, &oldStatus)
+	resultBuilder.Merge(membersResult, err)
+
 	// Create/update desired VitessCells.
 	if err := r.reconcileCells(ctx, vt); err != nil {
 		resultBuilder.Error(err)
@@ -216,6 +238,13 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 	topoResult, err := r.reconcileTopology(ctx, vt)
 	resultBuilder.Merge(topoResult, err)
 
+	// Periodically check topology records for drift.
+	consistencyResult, err := r.reconcileTopologyConsistency(ctx, vt, &oldStatus)
+	resultBuilder.Merge(consistencyResult, err)
+
+	// Roll up the progress of the rollout from what the steps above observed.
+	updateRolloutStatus(vt, &oldStatus)
+
 	// Update status if needed.
 	vt.Status.ObservedGeneration = vt.Generation
 	if !apiequality.Semantic.DeepEqual(&vt.Status, &oldStatus) {
@@ -235,3 +264,9 @@ func (r *ReconcileVitessCluster) Reconcile(cctx context.Context, request reconci
 	reconcileCount.WithLabelValues(vt.Name, metrics.Result(err)).Inc()
 	return result, err
 }
+
+// rolloutPaused returns whether rollouts are paused for the whole cluster,
+// either by the update strategy or with the rollout-paused annotation.
+func rolloutPaused(vt *planetscalev2.VitessCluster) bool {
+	return vt.Spec.UpdateStrategy.Paused || rollout.Paused(vt)
+}
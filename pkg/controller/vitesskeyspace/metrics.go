@@ -32,10 +32,18 @@ var (
 		Name:      "reconcile_count",
 		Help:      "Reconciliation attempts for a VitessKeyspace",
 	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ResultLabel})
+
+	autoReshardCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "auto_reshard_count",
+		Help:      "Shard splits started by the autoReshard policy of a VitessKeyspace",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
+		autoReshardCount,
 	)
 }
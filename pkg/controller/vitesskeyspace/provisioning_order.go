@@ -0,0 +1,105 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// shardsPending returns the key ranges of the shards that must not be created
+// yet because of a partitioning's maxConcurrentShardCreations.
+func (r *reconcileHandler) shardsPending(ctx context.Context, labels map[string]string) (map[string]bool, error) {
+	limited := false
+	for i := range r.vtk.Spec.Partitionings {
+		if r.vtk.Spec.Partitionings[i].MaxConcurrentShardCreations() > 0 {
+			limited = true
+		}
+	}
+	if !limited {
+		return nil, nil
+	}
+
+	shardList := &planetscalev2.VitessShardList{}
+	listOpts := &client.ListOptions{
+		Namespace:     r.vtk.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, shardList, listOpts); err != nil {
+		return nil, err
+	}
+	shards := make(map[string]*planetscalev2.VitessShard, len(shardList.Items))
+	for i := range shardList.Items {
+		shard := &shardList.Items[i]
+		shards[shard.Spec.KeyRange.String()] = shard
+	}
+
+	return shardProvisioningOrder(r.vtk.Spec.Partitionings, shards), nil
+}
+
+// shardProvisioningOrder goes through the shards of each partitioning in the
+// order in which they should be created, and returns the key ranges of those
+// that must wait because too many shards before them are still coming up.
+func shardProvisioningOrder(partitionings []planetscalev2.VitessKeyspacePartitioning, shards map[string]*planetscalev2.VitessShard) map[string]bool {
+	pending := map[string]bool{}
+
+	for i := range partitionings {
+		partitioning := &partitionings[i]
+		limit := partitioning.MaxConcurrentShardCreations()
+		if limit == 0 {
+			continue
+		}
+		keyRanges := partitioning.ProvisioningKeyRanges()
+
+		var comingUp int32
+		for _, keyRange := range keyRanges {
+			if shard, ok := shards[keyRange.String()]; ok && !shardProvisioned(shard) {
+				comingUp++
+			}
+		}
+		for _, keyRange := range keyRanges {
+			if _, ok := shards[keyRange.String()]; ok {
+				continue
+			}
+			if comingUp < limit {
+				comingUp++
+				continue
+			}
+			pending[keyRange.String()] = true
+		}
+	}
+	return pending
+}
+
+// shardProvisioned returns whether a shard has come up: it has a primary,
+// and all of its tablets are Ready.
+func shardProvisioned(vts *planetscalev2.VitessShard) bool {
+	if vts.Status.HasMaster != corev1.ConditionTrue || len(vts.Status.Tablets) == 0 {
+		return false
+	}
+	for _, tablet := range vts.Status.Tablets {
+		if tablet.Ready != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestShardProvisioningOrder(t *testing.T) {
+	newShard := func(ready corev1.ConditionStatus) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Status.HasMaster = ready
+		vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+			"zone1-1": {Ready: ready},
+		}
+		return vts
+	}
+	up := newShard(corev1.ConditionTrue)
+	comingUp := newShard(corev1.ConditionFalse)
+	partitioning := func(order planetscalev2.VitessShardProvisioningOrder, limit int32) []planetscalev2.VitessKeyspacePartitioning {
+		return []planetscalev2.VitessKeyspacePartitioning{{
+			Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{
+				Parts:                       4,
+				ProvisioningOrder:           order,
+				MaxConcurrentShardCreations: &limit,
+			},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		partitionings []planetscalev2.VitessKeyspacePartitioning
+		shards        map[string]*planetscalev2.VitessShard
+		wantPending   map[string]bool
+	}{
+		{
+			name:          "first batch",
+			partitionings: partitioning(planetscalev2.ShardProvisioningAscending, 2),
+			shards:        map[string]*planetscalev2.VitessShard{},
+			wantPending:   map[string]bool{"80-c0": true, "c0-": true},
+		},
+		{
+			name:          "first batch descending",
+			partitionings: partitioning(planetscalev2.ShardProvisioningDescending, 2),
+			shards:        map[string]*planetscalev2.VitessShard{},
+			wantPending:   map[string]bool{"-40": true, "40-80": true},
+		},
+		{
+			name:          "one shard still coming up",
+			partitionings: partitioning(planetscalev2.ShardProvisioningAscending, 2),
+			shards:        map[string]*planetscalev2.VitessShard{"-40": up, "40-80": comingUp},
+			wantPending:   map[string]bool{"c0-": true},
+		},
+		{
+			name:          "all shards up",
+			partitionings: partitioning(planetscalev2.ShardProvisioningAscending, 1),
+			shards:        map[string]*planetscalev2.VitessShard{"-40": up, "40-80": up, "80-c0": up},
+			wantPending:   map[string]bool{},
+		},
+		{
+			name:          "no limit",
+			partitionings: []planetscalev2.VitessKeyspacePartitioning{{Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{Parts: 4}}},
+			shards:        map[string]*planetscalev2.VitessShard{},
+			wantPending:   map[string]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantPending, shardProvisioningOrder(tt.partitionings, tt.shards))
+		})
+	}
+}
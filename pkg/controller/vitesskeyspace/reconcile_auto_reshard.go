@@ -0,0 +1,510 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// autoReshardMeasureInterval is how often the shards are measured.
+	autoReshardMeasureInterval = time.Minute
+	// maxSplitKeyRangeWidth is how many hex digits a split point may have.
+	maxSplitKeyRangeWidth = 16
+)
+
+// reconcileAutoReshard splits shards that have outgrown the thresholds of the
+// keyspace's autoReshard policy, one at a time. The actual work is done by a
+// VitessReshard, which this follows up on until it's Complete.
+func (r *reconcileHandler) reconcileAutoReshard(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	policy := r.vtk.Spec.AutoReshard
+	if policy == nil {
+		return resultBuilder.Result()
+	}
+	status := &planetscalev2.VitessAutoReshardStatus{}
+	if r.oldStatus.AutoReshard != nil {
+		status = r.oldStatus.AutoReshard.DeepCopy()
+	}
+	status.Message = ""
+	r.vtk.Status.AutoReshard = status
+
+	// Follow through with the split in progress, if any.
+	if status.Reshard != "" {
+		done, err := r.finishAutoReshard(ctx, status)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "AutoReshardFailed", "failed to follow up on VitessReshard %v: %v", status.Reshard, err)
+			return resultBuilder.Error(err)
+		}
+		if !done {
+			return resultBuilder.Result()
+		}
+	}
+
+	if policy.MaxShardDataSize == nil && policy.MaxPrimaryQPS == nil {
+		status.Message = "The policy sets neither maxShardDataSize nor maxPrimaryQPS, so no shard will be split."
+		return resultBuilder.Result()
+	}
+	if r.vtk.Status.Resharding != nil {
+		status.Message = fmt.Sprintf("Waiting for resharding workflow %v to finish.", r.vtk.Status.Resharding.Workflow)
+		return resultBuilder.Result()
+	}
+	if len(r.vtk.Spec.Partitionings) != 1 {
+		status.Message = "Waiting for the keyspace to have only one partitioning."
+		return resultBuilder.Result()
+	}
+
+	now := time.Now()
+	if status.LastMeasuredTime == nil || now.Sub(status.LastMeasuredTime.Time) >= autoReshardMeasureInterval {
+		if err := r.measureShards(ctx, policy, status, now); err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "AutoReshardMeasureFailed", "failed to measure shards: %v", err)
+			status.Message = fmt.Sprintf("Failed to measure shards: %v", err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+	}
+
+	source, reason := nextSplit(policy, status.Shards, now)
+	if source == "" {
+		status.Proposal = nil
+		return resultBuilder.Result()
+	}
+	if policy.MaxShards != nil && int32(len(status.Shards)) >= *policy.MaxShards {
+		status.Proposal = nil
+		status.Message = fmt.Sprintf("Shard %v %v, but the keyspace already has the maximum of %v shards.", source, reason, *policy.MaxShards)
+		return resultBuilder.Result()
+	}
+	if status.Proposal == nil || status.Proposal.SourceShard != source {
+		proposal, err := r.proposeSplit(source, reason)
+		if err != nil {
+			status.Proposal = nil
+			status.Message = fmt.Sprintf("Can't split shard %v: %v", source, err)
+			return resultBuilder.Result()
+		}
+		status.Proposal = proposal
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "AutoReshardProposed", "Proposed to split shard %v into %v because it %v.", source, strings.Join(proposal.TargetShards, ","), reason)
+	}
+	proposal := status.Proposal
+
+	if policy.RequireApproval && r.vtk.Annotations[vitesskeyspace.AutoReshardApprovalAnnotation] != proposal.Name {
+		status.Message = fmt.Sprintf("Waiting for approval to split shard %v. Set the annotation %v=%v to approve.", source, vitesskeyspace.AutoReshardApprovalAnnotation, proposal.Name)
+		return resultBuilder.Result()
+	}
+
+	open, wait, err := maintenance.Open(r.vtk.Spec.MaintenanceWindows, now)
+	if err != nil {
+		status.Message = fmt.Sprintf("Can't tell whether a maintenance window is open: %v", err)
+		return resultBuilder.Result()
+	}
+	if !open {
+		status.Message = fmt.Sprintf("Waiting for a maintenance window to split shard %v.", source)
+		return resultBuilder.RequeueAfter(wait)
+	}
+
+	if err := r.startAutoReshard(ctx, policy, proposal); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "AutoReshardFailed", "failed to start splitting shard %v: %v", source, err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "AutoReshardStarted", "Started splitting shard %v with VitessReshard %v.", source, proposal.Name)
+	autoReshardCount.WithLabelValues(r.vtk.Labels[planetscalev2.ClusterLabel], r.vtk.Spec.Name).Inc()
+
+	status.Reshard = proposal.Name
+	status.Proposal = nil
+	for i := range status.Shards {
+		status.Shards[i].OverThresholdSince = nil
+	}
+	status.Message = fmt.Sprintf("Waiting for VitessReshard %v to finish.", status.Reshard)
+	return resultBuilder.Result()
+}
+
+// finishAutoReshard follows up on the VitessReshard of the split in progress.
+// Once it's Complete, the old partitioning is removed from the VitessCluster.
+// It returns whether the split is done.
+func (r *reconcileHandler) finishAutoReshard(ctx context.Context, status *planetscalev2.VitessAutoReshardStatus) (bool, error) {
+	vtr := &planetscalev2.VitessReshard{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: r.vtk.Namespace, Name: status.Reshard}, vtr)
+	if apierrors.IsNotFound(err) {
+		// Someone deleted it, so they've taken over.
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "AutoReshardAbandoned", "VitessReshard %v is gone, so the split was abandoned.", status.Reshard)
+		status.Reshard = ""
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch vtr.Status.Phase {
+	case planetscalev2.ReshardCompletePhase:
+	case planetscalev2.ReshardFailedPhase:
+		status.Message = fmt.Sprintf("VitessReshard %v failed. Fix it, or delete it to abandon the split.", vtr.Name)
+		return false, nil
+	default:
+		status.Message = fmt.Sprintf("Waiting for VitessReshard %v to finish.", vtr.Name)
+		return false, nil
+	}
+
+	if err := r.removeSourcePartitioning(ctx, vtr); err != nil {
+		return false, err
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "AutoReshardCompleted", "Finished splitting shard %v into %v.", strings.Join(vtr.Spec.SourceShards, ","), strings.Join(vtr.Spec.TargetShards, ","))
+	status.Reshard = ""
+	return true, nil
+}
+
+// measureShards measures the primary of every shard, and notes when each
+// shard went over a threshold of the policy.
+func (r *reconcileHandler) measureShards(ctx context.Context, policy *planetscalev2.VitessAutoReshardPolicy, status *planetscalev2.VitessAutoReshardStatus, now time.Time) error {
+	if err := r.tsInit(ctx); err != nil {
+		return err
+	}
+
+	oldShards := make(map[string]*planetscalev2.VitessAutoReshardShardStatus, len(status.Shards))
+	for i := range status.Shards {
+		oldShards[status.Shards[i].Name] = &status.Shards[i]
+	}
+
+	var shards []planetscalev2.VitessAutoReshardShardStatus
+	for _, shard := range r.vtk.Spec.ShardTemplates() {
+		shardStatus, err := r.measureShard(ctx, policy, shard.KeyRange.String())
+		if err != nil {
+			return err
+		}
+		if overThreshold(policy, &shardStatus) != "" {
+			shardStatus.OverThresholdSince = &metav1.Time{Time: now}
+			if old := oldShards[shardStatus.Name]; old != nil && old.OverThresholdSince != nil {
+				shardStatus.OverThresholdSince = old.OverThresholdSince
+			}
+		}
+		shards = append(shards, shardStatus)
+	}
+	status.Shards = shards
+	status.LastMeasuredTime = &metav1.Time{Time: now}
+	return nil
+}
+
+// measureShard measures what the policy has thresholds for on the primary of
+// a shard.
+func (r *reconcileHandler) measureShard(ctx context.Context, policy *planetscalev2.VitessAutoReshardPolicy, shardName string) (planetscalev2.VitessAutoReshardShardStatus, error) {
+	status := planetscalev2.VitessAutoReshardShardStatus{Name: shardName}
+
+	ctx, cancel := context.WithTimeout(ctx, topoReconcileTimeout)
+	defer cancel()
+
+	shardInfo, err := r.ts.GetShard(ctx, r.vtk.Spec.Name, shardName)
+	if err != nil {
+		return status, fmt.Errorf("failed to get shard %v: %v", shardName, err)
+	}
+	if shardInfo.PrimaryAlias == nil {
+		return status, fmt.Errorf("shard %v has no primary", shardName)
+	}
+
+	if policy.MaxShardDataSize != nil {
+		resp, err := r.wr.VtctldServer().GetSchema(ctx, &vtctldatapb.GetSchemaRequest{
+			TabletAlias:    shardInfo.PrimaryAlias,
+			TableSizesOnly: true,
+		})
+		if err != nil {
+			return status, fmt.Errorf("failed to get table sizes of shard %v: %v", shardName, err)
+		}
+		for _, table := range resp.Schema.TableDefinitions {
+			status.DataSizeBytes += int64(table.DataLength)
+		}
+	}
+
+	if policy.MaxPrimaryQPS != nil {
+		tablet, err := r.ts.GetTablet(ctx, shardInfo.PrimaryAlias)
+		if err != nil {
+			return status, fmt.Errorf("failed to get primary of shard %v: %v", shardName, err)
+		}
+		qps, err := vttablet.GetQPS(ctx, tablet.Hostname)
+		if err != nil {
+			return status, err
+		}
+		status.PrimaryQPS = int64(qps)
+	}
+
+	return status, nil
+}
+
+// overThreshold returns why a shard is over a threshold of the policy, or an
+// empty string if it isn't.
+func overThreshold(policy *planetscalev2.VitessAutoReshardPolicy, shard *planetscalev2.VitessAutoReshardShardStatus) string {
+	if policy.MaxShardDataSize != nil && shard.DataSizeBytes > policy.MaxShardDataSize.Value() {
+		return fmt.Sprintf("holds %v of data, over the maximum of %v", resource.NewQuantity(shard.DataSizeBytes, resource.BinarySI), policy.MaxShardDataSize)
+	}
+	if policy.MaxPrimaryQPS != nil && shard.PrimaryQPS > *policy.MaxPrimaryQPS {
+		return fmt.Sprintf("serves %v queries per second on its primary, over the maximum of %v", shard.PrimaryQPS, *policy.MaxPrimaryQPS)
+	}
+	return ""
+}
+
+// nextSplit picks the shard that has been over a threshold the longest, as
+// long as it's been over for at least as long as the policy requires. It
+// returns an empty shard name if no shard is due to be split.
+func nextSplit(policy *planetscalev2.VitessAutoReshardPolicy, shards []planetscalev2.VitessAutoReshardShardStatus, now time.Time) (string, string) {
+	var next *planetscalev2.VitessAutoReshardShardStatus
+	for i := range shards {
+		shard := &shards[i]
+		if shard.OverThresholdSince == nil || now.Sub(shard.OverThresholdSince.Time) < policy.SustainedFor.Duration {
+			continue
+		}
+		if next == nil || shard.OverThresholdSince.Before(next.OverThresholdSince) {
+			next = shard
+		}
+	}
+	if next == nil {
+		return "", ""
+	}
+	return next.Name, overThreshold(policy, next)
+}
+
+// proposeSplit proposes to split a shard into two halves.
+func (r *reconcileHandler) proposeSplit(shardName, reason string) (*planetscalev2.VitessAutoReshardProposal, error) {
+	start, end, ok := strings.Cut(shardName, "-")
+	if !ok {
+		return nil, fmt.Errorf("shard name %q is not a key range", shardName)
+	}
+	source := planetscalev2.VitessKeyRange{Start: start, End: end}
+	targets, err := splitKeyRange(source)
+	if err != nil {
+		return nil, err
+	}
+	proposal := &planetscalev2.VitessAutoReshardProposal{
+		Name:        names.JoinWithConstraints(names.DefaultConstraints, r.vtk.Name, "split", source.SafeName()),
+		SourceShard: shardName,
+		Reason:      reason,
+	}
+	for i := range targets {
+		proposal.TargetShards = append(proposal.TargetShards, targets[i].String())
+	}
+	return proposal, nil
+}
+
+// startAutoReshard adds a partitioning with the target shards to the keyspace
+// in the VitessCluster, and creates a VitessReshard to move the data into
+// them.
+func (r *reconcileHandler) startAutoReshard(ctx context.Context, policy *planetscalev2.VitessAutoReshardPolicy, proposal *planetscalev2.VitessAutoReshardProposal) error {
+	clusterName := r.vtk.Labels[planetscalev2.ClusterLabel]
+	vt := &planetscalev2.VitessCluster{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: r.vtk.Namespace, Name: clusterName}, vt); err != nil {
+		return fmt.Errorf("failed to get VitessCluster %v: %v", clusterName, err)
+	}
+	keyspace := clusterKeyspace(vt, r.vtk.Spec.Name)
+	if keyspace == nil {
+		return fmt.Errorf("keyspace %v is not in VitessCluster %v", r.vtk.Spec.Name, clusterName)
+	}
+
+	// The partitioning may already be there if we got this far before.
+	added := false
+	for i := range keyspace.Partitionings {
+		if keyspace.Partitionings[i].ShardNameSet().HasAll(proposal.TargetShards...) {
+			added = true
+			break
+		}
+	}
+	if !added {
+		if len(keyspace.Partitionings) != 1 {
+			return fmt.Errorf("keyspace %v in VitessCluster %v has more than one partitioning", r.vtk.Spec.Name, clusterName)
+		}
+		partitioning, err := splitPartitioning(&keyspace.Partitionings[0], proposal.SourceShard, proposal.TargetShards)
+		if err != nil {
+			return err
+		}
+		keyspace.Partitionings = append(keyspace.Partitionings, *partitioning)
+		if err := r.client.Update(ctx, vt); err != nil {
+			return fmt.Errorf("failed to add shards %v to VitessCluster %v: %v", strings.Join(proposal.TargetShards, ","), clusterName, err)
+		}
+	}
+
+	vtr := &planetscalev2.VitessReshard{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: r.vtk.Namespace,
+			Name:      proposal.Name,
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  clusterName,
+				planetscalev2.KeyspaceLabel: r.vtk.Spec.Name,
+			},
+		},
+		Spec: planetscalev2.VitessReshardSpec{
+			Cluster:       clusterName,
+			Keyspace:      r.vtk.Spec.Name,
+			SourceShards:  []string{proposal.SourceShard},
+			TargetShards:  proposal.TargetShards,
+			SwitchTraffic: policy.SwitchTraffic,
+		},
+	}
+	if err := controllerutil.SetControllerReference(r.vtk, vtr, r.client.Scheme()); err != nil {
+		return err
+	}
+	if err := r.client.Create(ctx, vtr); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create VitessReshard %v: %v", vtr.Name, err)
+	}
+	return nil
+}
+
+// removeSourcePartitioning removes the partitioning with the source shards of
+// a Complete VitessReshard from the keyspace in the VitessCluster, which turns
+// down the source shards.
+func (r *reconcileHandler) removeSourcePartitioning(ctx context.Context, vtr *planetscalev2.VitessReshard) error {
+	vt := &planetscalev2.VitessCluster{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: r.vtk.Namespace, Name: vtr.Spec.Cluster}, vt); err != nil {
+		return fmt.Errorf("failed to get VitessCluster %v: %v", vtr.Spec.Cluster, err)
+	}
+	keyspace := clusterKeyspace(vt, r.vtk.Spec.Name)
+	if keyspace == nil {
+		return fmt.Errorf("keyspace %v is not in VitessCluster %v", r.vtk.Spec.Name, vtr.Spec.Cluster)
+	}
+
+	var partitionings []planetscalev2.VitessKeyspacePartitioning
+	hasTargets := false
+	for i := range keyspace.Partitionings {
+		shardNames := keyspace.Partitionings[i].ShardNameSet()
+		if shardNames.HasAny(vtr.Spec.SourceShards...) {
+			continue
+		}
+		if shardNames.HasAll(vtr.Spec.TargetShards...) {
+			hasTargets = true
+		}
+		partitionings = append(partitionings, keyspace.Partitionings[i])
+	}
+	if len(partitionings) == len(keyspace.Partitionings) {
+		// Someone already removed it.
+		return nil
+	}
+	if !hasTargets {
+		return fmt.Errorf("no partitioning of keyspace %v would be left with shards %v", r.vtk.Spec.Name, strings.Join(vtr.Spec.TargetShards, ","))
+	}
+	keyspace.Partitionings = partitionings
+	if err := r.client.Update(ctx, vt); err != nil {
+		return fmt.Errorf("failed to remove shards %v from VitessCluster %v: %v", strings.Join(vtr.Spec.SourceShards, ","), vtr.Spec.Cluster, err)
+	}
+	return nil
+}
+
+// clusterKeyspace returns the keyspace with the given name in a VitessCluster,
+// or nil if there isn't one.
+func clusterKeyspace(vt *planetscalev2.VitessCluster, keyspaceName string) *planetscalev2.VitessKeyspaceTemplate {
+	for i := range vt.Spec.Keyspaces {
+		if vt.Spec.Keyspaces[i].Name == keyspaceName {
+			return &vt.Spec.Keyspaces[i]
+		}
+	}
+	return nil
+}
+
+// splitPartitioning returns a partitioning with the same shards as the given
+// one, except that the source shard is replaced by the target shards, which
+// copy its template.
+func splitPartitioning(partitioning *planetscalev2.VitessKeyspacePartitioning, sourceShard string, targetShards []string) (*planetscalev2.VitessKeyspacePartitioning, error) {
+	var shards []planetscalev2.VitessKeyspaceKeyRangeShard
+	switch {
+	case partitioning.Equal != nil:
+		for _, keyRange := range partitioning.Equal.KeyRanges() {
+			shards = append(shards, planetscalev2.VitessKeyspaceKeyRangeShard{
+				KeyRange:            keyRange,
+				VitessShardTemplate: partitioning.Equal.ShardTemplate,
+			})
+		}
+	case partitioning.Custom != nil:
+		shards = partitioning.Custom.ShardSpecs()
+	}
+
+	custom := &planetscalev2.VitessKeyspaceCustomPartitioning{}
+	found := false
+	for i := range shards {
+		shard := &shards[i]
+		if shard.KeyRange.String() != sourceShard {
+			custom.Shards = append(custom.Shards, *shard.DeepCopy())
+			continue
+		}
+		found = true
+		for _, shardName := range targetShards {
+			start, end, _ := strings.Cut(shardName, "-")
+			custom.Shards = append(custom.Shards, planetscalev2.VitessKeyspaceKeyRangeShard{
+				KeyRange:            planetscalev2.VitessKeyRange{Start: start, End: end},
+				VitessShardTemplate: *shard.VitessShardTemplate.DeepCopy(),
+			})
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("shard %v is not in the partitioning", sourceShard)
+	}
+	return &planetscalev2.VitessKeyspacePartitioning{Custom: custom}, nil
+}
+
+// splitKeyRange splits a key range into two halves.
+func splitKeyRange(kr planetscalev2.VitessKeyRange) ([]planetscalev2.VitessKeyRange, error) {
+	if _, err := hex.DecodeString(kr.Start); err != nil {
+		return nil, fmt.Errorf("invalid key range %v: %v", kr.String(), err)
+	}
+	if _, err := hex.DecodeString(kr.End); err != nil {
+		return nil, fmt.Errorf("invalid key range %v: %v", kr.String(), err)
+	}
+
+	// Compare the bounds with the same number of digits, and add more digits
+	// until there's room for a split point between them.
+	width := max(len(kr.Start), len(kr.End), 2)
+	for ; width <= maxSplitKeyRangeWidth; width += 2 {
+		start := keyRangeBound(kr.Start, width, big.NewInt(0))
+		end := keyRangeBound(kr.End, width, new(big.Int).Lsh(big.NewInt(1), uint(4*width)))
+		mid := new(big.Int).Add(start, end)
+		mid.Rsh(mid, 1)
+		if mid.Cmp(start) <= 0 {
+			continue
+		}
+		split := fmt.Sprintf("%0*x", width, mid)
+		for len(split) > 2 && strings.HasSuffix(split, "00") {
+			split = split[:len(split)-2]
+		}
+		return []planetscalev2.VitessKeyRange{
+			{Start: kr.Start, End: split},
+			{Start: split, End: kr.End},
+		}, nil
+	}
+	return nil, fmt.Errorf("key range %v is too narrow to split", kr.String())
+}
+
+// keyRangeBound returns the value of a key range bound with the given number
+// of hex digits, or unbounded if the bound is empty.
+func keyRangeBound(bound string, width int, unbounded *big.Int) *big.Int {
+	if bound == "" {
+		return unbounded
+	}
+	value, _ := new(big.Int).SetString(bound+strings.Repeat("0", width-len(bound)), 16)
+	return value
+}
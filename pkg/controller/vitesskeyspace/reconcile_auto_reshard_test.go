@@ -0,0 +1,112 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestSplitKeyRange(t *testing.T) {
+	table := []struct {
+		keyRange string
+		want     []string
+	}{
+		{keyRange: "-", want: []string{"-80", "80-"}},
+		{keyRange: "-80", want: []string{"-40", "40-80"}},
+		{keyRange: "80-", want: []string{"80-c0", "c0-"}},
+		{keyRange: "40-80", want: []string{"40-60", "60-80"}},
+		{keyRange: "80-81", want: []string{"80-8080", "8080-81"}},
+		{keyRange: "fe-", want: []string{"fe-ff", "ff-"}},
+	}
+
+	for _, test := range table {
+		t.Run(test.keyRange, func(t *testing.T) {
+			start, end, _ := strings.Cut(test.keyRange, "-")
+			got, err := splitKeyRange(planetscalev2.VitessKeyRange{Start: start, End: end})
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.want, []string{got[0].String(), got[1].String()})
+			}
+		})
+	}
+
+	_, err := splitKeyRange(planetscalev2.VitessKeyRange{Start: "8", End: ""})
+	assert.Error(t, err, "odd number of hex digits")
+}
+
+func TestSplitPartitioning(t *testing.T) {
+	partitioning := &planetscalev2.VitessKeyspacePartitioning{
+		Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{
+			Parts: 2,
+			ShardTemplate: planetscalev2.VitessShardTemplate{
+				DatabaseInitScriptSecret: planetscalev2.SecretSource{Name: "init"},
+			},
+		},
+	}
+
+	split, err := splitPartitioning(partitioning, "80-", []string{"80-c0", "c0-"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Nil(t, split.Equal)
+	var got []string
+	for _, shard := range split.Custom.Shards {
+		got = append(got, shard.KeyRange.String())
+		assert.Equal(t, "init", shard.DatabaseInitScriptSecret.Name)
+	}
+	assert.Equal(t, []string{"-80", "80-c0", "c0-"}, got)
+
+	_, err = splitPartitioning(partitioning, "40-80", []string{"40-60", "60-80"})
+	assert.Error(t, err)
+}
+
+func TestNextSplit(t *testing.T) {
+	now := time.Now()
+	since := func(ago time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(-ago)}
+	}
+	maxSize := resource.MustParse("100Gi")
+	policy := &planetscalev2.VitessAutoReshardPolicy{
+		MaxShardDataSize: &maxSize,
+		SustainedFor:     &metav1.Duration{Duration: time.Hour},
+	}
+	over := maxSize.Value() + 1
+
+	// A shard that only just went over isn't split yet.
+	shards := []planetscalev2.VitessAutoReshardShardStatus{
+		{Name: "-80", DataSizeBytes: 1},
+		{Name: "80-", DataSizeBytes: over, OverThresholdSince: since(time.Minute)},
+	}
+	shard, _ := nextSplit(policy, shards, now)
+	assert.Equal(t, "", shard)
+
+	// The shard that's been over the longest goes first.
+	shards = []planetscalev2.VitessAutoReshardShardStatus{
+		{Name: "-80", DataSizeBytes: over, OverThresholdSince: since(2 * time.Hour)},
+		{Name: "80-", DataSizeBytes: over, OverThresholdSince: since(3 * time.Hour)},
+	}
+	shard, reason := nextSplit(policy, shards, now)
+	assert.Equal(t, "80-", shard)
+	assert.Contains(t, reason, "over the maximum of 100Gi")
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+	"planetscale.dev/vitess-operator/pkg/operator/vtgate"
+)
+
+const (
+	// decommissionTrafficCheckInterval is how long the query counts of
+	// vtgates must stay the same before we consider the keyspace to no
+	// longer serve traffic.
+	decommissionTrafficCheckInterval = 5 * time.Minute
+)
+
+// reconcileDecommission takes the keyspace through the steps of the
+// Decommission turndown policy, once the VitessCluster controller has
+// requested it with the decommission annotation. The VitessCluster controller
+// only deletes the keyspace once the decommission is Complete.
+//
+// NOTE: This must always be done before reconcileShards, so the final backup
+// can be requested from the shards.
+func (r *reconcileHandler) reconcileDecommission(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	request := r.vtk.Annotations[vitesskeyspace.DecommissionAnnotation]
+	policy := r.vtk.Spec.Decommission
+	if request == "" || policy == nil {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDecommissioning, corev1.ConditionFalse, "NotRequested", "The keyspace is not being decommissioned.")
+		return resultBuilder.Result()
+	}
+
+	status := &planetscalev2.VitessKeyspaceDecommissionStatus{
+		Request: request,
+		Phase:   planetscalev2.DecommissionCheckingTraffic,
+	}
+	if r.oldStatus.Decommission != nil && r.oldStatus.Decommission.Request == request {
+		status = r.oldStatus.Decommission.DeepCopy()
+	} else {
+		r.recorder.Event(r.vtk, corev1.EventTypeNormal, "DecommissionStarted", "Started decommissioning the keyspace because it was removed from the VitessCluster spec.")
+	}
+	status.Message = ""
+	r.vtk.Status.Decommission = status
+	defer func() {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDecommissioning, corev1.ConditionTrue, string(status.Phase), status.Message)
+	}()
+
+	now := time.Now()
+
+	if status.Phase == planetscalev2.DecommissionCheckingTraffic {
+		queries, err := r.keyspaceQueries(ctx)
+		if err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "DecommissionTrafficCheckFailed", "failed to check vtgates for queries served for the keyspace: %v", err)
+			status.Message = fmt.Sprintf("Failed to check vtgates for queries served for the keyspace: %v", err)
+			return resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+		wait := checkTraffic(status, queries, now)
+		if wait > 0 {
+			return resultBuilder.RequeueAfter(wait)
+		}
+
+		status.Phase = planetscalev2.DecommissionBackingUp
+		if len(r.vtk.Spec.BackupLocations) != 0 && !policy.SkipFinalBackup {
+			status.BackupRequest = "decommission-" + request
+		}
+	}
+
+	if status.Phase == planetscalev2.DecommissionBackingUp {
+		if status.BackupRequest != "" {
+			done, err := r.finalBackupDone(ctx, status)
+			if err != nil {
+				r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "DecommissionBackupCheckFailed", "failed to check the final backup: %v", err)
+				status.Message = fmt.Sprintf("Failed to check the final backup: %v", err)
+				return resultBuilder.RequeueAfter(topoRequeueDelay)
+			}
+			if !done {
+				// Changes in shard status will trigger another reconcile.
+				return resultBuilder.Result()
+			}
+		}
+
+		status.Phase = planetscalev2.DecommissionHolding
+		status.HoldStartTime = &metav1.Time{Time: now}
+	}
+
+	if status.Phase == planetscalev2.DecommissionHolding {
+		holdUntil := status.HoldStartTime.Add(policy.HoldPeriod.Duration)
+		if now.Before(holdUntil) {
+			status.Message = fmt.Sprintf("Waiting until %v to turn down the keyspace. Add the keyspace back to the VitessCluster spec to cancel.", holdUntil.UTC().Format(time.RFC3339))
+			return resultBuilder.RequeueAfter(holdUntil.Sub(now))
+		}
+
+		status.Phase = planetscalev2.DecommissionComplete
+		r.recorder.Event(r.vtk, corev1.EventTypeNormal, "DecommissionComplete", "The keyspace is ready to be turned down.")
+	}
+
+	status.Message = "The keyspace is ready to be turned down."
+	return resultBuilder.Result()
+}
+
+// checkTraffic records the number of queries that vtgates have served for
+// the keyspace, and returns how much longer to wait before the keyspace can
+// be considered to no longer serve traffic. It returns 0 once the count has
+// stayed the same for decommissionTrafficCheckInterval.
+//
+// The counts of vtgates start over when they restart, so the count going down
+// also starts the wait over.
+func checkTraffic(status *planetscalev2.VitessKeyspaceDecommissionStatus, queries int64, now time.Time) time.Duration {
+	if status.LastTrafficCheckTime == nil || queries != status.Queries {
+		if status.LastTrafficCheckTime != nil && queries > status.Queries {
+			status.Message = fmt.Sprintf("Waiting for the keyspace to stop serving queries. vtgates served %v queries for it since %v.", queries-status.Queries, status.LastTrafficCheckTime.UTC().Format(time.RFC3339))
+		} else {
+			status.Message = "Waiting to make sure the keyspace no longer serves queries."
+		}
+		status.Queries = queries
+		status.LastTrafficCheckTime = &metav1.Time{Time: now}
+		return decommissionTrafficCheckInterval
+	}
+	if wait := decommissionTrafficCheckInterval - now.Sub(status.LastTrafficCheckTime.Time); wait > 0 {
+		status.Message = "Waiting to make sure the keyspace no longer serves queries."
+		return wait
+	}
+	return 0
+}
+
+// keyspaceQueries adds up how many queries the Ready vtgate Pods of the
+// cluster have served for the keyspace.
+func (r *reconcileHandler) keyspaceQueries(ctx context.Context) (int64, error) {
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtgateComponentName,
+		planetscalev2.ClusterLabel:   r.vtk.Labels[planetscalev2.ClusterLabel],
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     r.vtk.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return 0, err
+	}
+	var total int64
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		queries, err := vtgate.GetKeyspaceQueries(ctx, pod, r.vtk.Spec.Name)
+		if err != nil {
+			return 0, err
+		}
+		total += queries
+	}
+	return total, nil
+}
+
+// finalBackupDone checks on the final backup of each shard, which is
+// requested by passing the request ID on to the shards.
+func (r *reconcileHandler) finalBackupDone(ctx context.Context, status *planetscalev2.VitessKeyspaceDecommissionStatus) (bool, error) {
+	labels := map[string]string{
+		planetscalev2.ClusterLabel:  r.vtk.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel: r.vtk.Spec.Name,
+	}
+	shardList := &planetscalev2.VitessShardList{}
+	listOpts := &client.ListOptions{
+		Namespace:     r.vtk.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, shardList, listOpts); err != nil {
+		return false, err
+	}
+
+	done, message := finalBackupPhase(shardList.Items, status.BackupRequest)
+	status.Message = message
+	return done, nil
+}
+
+// finalBackupPhase returns whether every shard has finished its final backup,
+// and if not, what it's waiting for.
+func finalBackupPhase(shards []planetscalev2.VitessShard, requestID string) (bool, string) {
+	var failed, waiting []string
+	for i := range shards {
+		shard := &shards[i]
+		request := shard.Status.BackupRequest
+		switch {
+		case request != nil && request.ID == requestID && request.Phase == corev1.PodSucceeded:
+		case request != nil && request.ID == requestID && request.Phase == corev1.PodFailed:
+			failed = append(failed, shard.Spec.Name)
+		default:
+			waiting = append(waiting, shard.Spec.Name)
+		}
+	}
+	sort.Strings(failed)
+	sort.Strings(waiting)
+
+	if len(failed) != 0 {
+		return false, fmt.Sprintf("The final backup failed for shards %v. Add the keyspace back to the VitessCluster spec to cancel the decommission, or set its turndownPolicy to Immediate to turn it down without a final backup.", strings.Join(failed, ", "))
+	}
+	if len(waiting) != 0 {
+		return false, fmt.Sprintf("Waiting for the final backup of shards %v.", strings.Join(waiting, ", "))
+	}
+	return true, ""
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestCheckTraffic(t *testing.T) {
+	now := time.Now()
+	status := &planetscalev2.VitessKeyspaceDecommissionStatus{}
+
+	// The first check only records the count.
+	assert.Equal(t, decommissionTrafficCheckInterval, checkTraffic(status, 100, now))
+	assert.Equal(t, int64(100), status.Queries)
+
+	// More queries start the wait over.
+	now = now.Add(decommissionTrafficCheckInterval)
+	assert.Equal(t, decommissionTrafficCheckInterval, checkTraffic(status, 150, now))
+	assert.Contains(t, status.Message, "served 50 queries")
+
+	// So does a vtgate restarting.
+	now = now.Add(decommissionTrafficCheckInterval)
+	assert.Equal(t, decommissionTrafficCheckInterval, checkTraffic(status, 20, now))
+
+	// The count has to stay the same for the whole interval.
+	assert.Equal(t, time.Minute, checkTraffic(status, 20, now.Add(decommissionTrafficCheckInterval-time.Minute)))
+	assert.Equal(t, time.Duration(0), checkTraffic(status, 20, now.Add(decommissionTrafficCheckInterval)))
+}
+
+func TestFinalBackupPhase(t *testing.T) {
+	newShard := func(name, requestID string, phase corev1.PodPhase) planetscalev2.VitessShard {
+		shard := planetscalev2.VitessShard{}
+		shard.Spec.Name = name
+		if requestID != "" {
+			shard.Status.BackupRequest = &planetscalev2.ShardBackupRequestStatus{ID: requestID, Phase: phase}
+		}
+		return shard
+	}
+
+	done, message := finalBackupPhase([]planetscalev2.VitessShard{
+		newShard("80-", "decommission-1", corev1.PodRunning),
+		newShard("-80", "", ""),
+	}, "decommission-1")
+	assert.False(t, done)
+	assert.Equal(t, "Waiting for the final backup of shards -80, 80-.", message)
+
+	// A backup requested for something else doesn't count.
+	done, _ = finalBackupPhase([]planetscalev2.VitessShard{
+		newShard("-80", "decommission-1", corev1.PodSucceeded),
+		newShard("80-", "nightly", corev1.PodSucceeded),
+	}, "decommission-1")
+	assert.False(t, done)
+
+	done, message = finalBackupPhase([]planetscalev2.VitessShard{
+		newShard("-80", "decommission-1", corev1.PodSucceeded),
+		newShard("80-", "decommission-1", corev1.PodFailed),
+	}, "decommission-1")
+	assert.False(t, done)
+	assert.Contains(t, message, "failed for shards 80-")
+
+	done, _ = finalBackupPhase([]planetscalev2.VitessShard{
+		newShard("-80", "decommission-1", corev1.PodSucceeded),
+		newShard("80-", "decommission-1", corev1.PodSucceeded),
+	}, "decommission-1")
+	assert.True(t, done)
+}
@@ -18,6 +18,9 @@ package vitesskeyspace
 
 import (
 	"context"
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,12 +29,15 @@ import (
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 	"vitess.io/vitess/go/vt/topo"
 
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 )
 
 func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
+	r.updateDurabilityCondition()
+
 	// Initialize the topo server before using it.
 	// This call is idempotent, so it is safe to call each time
 	// before using the topo server.
@@ -42,7 +48,7 @@ func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (re
 
 	topoServer := r.ts.Server
 	keyspaceName := r.vtk.Spec.Name
-	durabilityPolicy := r.vtk.Spec.DurabilityPolicy
+	durabilityPolicy := r.vtk.Spec.EffectiveDurabilityPolicy()
 	keyspaceInfo, err := topoServer.GetKeyspace(ctx, keyspaceName)
 	if err != nil {
 		// The keyspace information record does not exist in the topo server.
@@ -79,3 +85,21 @@ func (r *reconcileHandler) reconcileKeyspaceInformation(ctx context.Context) (re
 	}
 	return resultBuilder.Result()
 }
+
+// updateDurabilityCondition sets the DurabilitySatisfiable condition based on
+// whether the tablet pools of every shard can satisfy the keyspace's durability
+// policy. The condition is left unset if the keyspace has no durability policy.
+func (r *reconcileHandler) updateDurabilityCondition() {
+	durabilityPolicy := r.vtk.Spec.EffectiveDurabilityPolicy()
+	if durabilityPolicy == "" {
+		return
+	}
+
+	if problems := r.vtk.Spec.DurabilityProblems(); len(problems) > 0 {
+		r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilitySatisfiable, corev1.ConditionFalse, "NotEnoughReplicas",
+			fmt.Sprintf("Tablet pools can't satisfy the %v durability policy: %v", durabilityPolicy, strings.Join(problems, "; ")))
+		return
+	}
+	r.setConditionStatus(planetscalev2.VitessKeyspaceDurabilitySatisfiable, corev1.ConditionTrue, "EnoughReplicas",
+		fmt.Sprintf("Tablet pools can satisfy the %v durability policy", durabilityPolicy))
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// reconcileMaterializations creates the Materialize workflows in the spec
+// that don't exist, deletes the ones that were removed from the spec, and
+// reports on each one in the status.
+func (r *reconcileHandler) reconcileMaterializations(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	// Reference tables are kept in sync with Materialize workflows too.
+	materializations := append([]planetscalev2.VitessMaterialization{}, r.vtk.Spec.Materializations...)
+	referenceTables := referenceTableMaterializations(r.vtk.Spec.Schema)
+	materializations = append(materializations, referenceTables...)
+
+	// Workflows that were removed from the spec must be deleted, so there's
+	// still work to do if the status lists any.
+	if len(materializations) == 0 && len(r.oldStatus.Materializations) == 0 {
+		return resultBuilder.Result()
+	}
+	// Until we can tell otherwise, report what we knew last time.
+	r.vtk.Status.Materializations = r.oldStatus.Materializations
+
+	if err := r.tsInit(ctx); err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	resp, err := r.wr.VtctldServer().GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: r.vtk.Spec.Name})
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "GetWorkflowsFailed", "failed to list workflows: %v", err)
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	workflows := make(map[string]*vtctldatapb.Workflow, len(resp.Workflows))
+	for _, workflow := range resp.Workflows {
+		workflows[workflow.Name] = workflow
+	}
+	// The workflow of a reference table only copies every row to every
+	// shard once the VSchema says it's a reference table, so it has to wait
+	// until then.
+	isReferenceTable := make(map[string]bool, len(referenceTables))
+	var vschema *vschemapb.Keyspace
+	if len(referenceTables) != 0 {
+		for i := range referenceTables {
+			isReferenceTable[referenceTables[i].Name] = true
+		}
+		if resp, err := r.wr.VtctldServer().GetVSchema(ctx, &vtctldatapb.GetVSchemaRequest{Keyspace: r.vtk.Spec.Name}); err == nil {
+			vschema = resp.VSchema
+		}
+	}
+	oldStatuses := make(map[string]planetscalev2.VitessMaterializationStatus, len(r.oldStatus.Materializations))
+	for _, status := range r.oldStatus.Materializations {
+		oldStatuses[status.Name] = status
+	}
+
+	var statuses []planetscalev2.VitessMaterializationStatus
+	wanted := make(map[string]bool, len(materializations))
+	for i := range materializations {
+		mz := &materializations[i]
+		wanted[mz.Name] = true
+
+		if workflow := workflows[mz.Name]; workflow != nil {
+			statuses = append(statuses, materializationStatus(mz.Name, workflow))
+			continue
+		}
+
+		oldStatus, seen := oldStatuses[mz.Name]
+		if seen && mz.StopAfterCopy && oldStatus.State == planetscalev2.WorkflowStopped {
+			// The workflow did its job, so it's fine that it's gone.
+			oldStatus.Message = "The workflow finished copying and was deleted."
+			statuses = append(statuses, oldStatus)
+			continue
+		}
+
+		status := planetscalev2.VitessMaterializationStatus{Name: mz.Name, State: planetscalev2.WorkflowUnknown}
+		if isReferenceTable[mz.Name] && vschema.GetTables()[mz.TargetTable].GetType() != "reference" {
+			status.Message = "Waiting for the VSchema to declare the reference table."
+			statuses = append(statuses, status)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+			continue
+		}
+		if err := r.createMaterialization(ctx, mz); err != nil {
+			status.Message = fmt.Sprintf("Failed to create the workflow: %v", err)
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "CreateMaterializationFailed", "failed to create Materialize workflow %v: %v", mz.Name, err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+		} else if seen && oldStatus.State != planetscalev2.WorkflowUnknown {
+			status.Message = "The workflow was dropped, so it was created again."
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "MaterializationRecreated", "Materialize workflow %v was dropped, so it was created again.", mz.Name)
+		} else {
+			status.Message = "The workflow was created."
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "MaterializationCreated", "Created Materialize workflow %v.", mz.Name)
+		}
+		statuses = append(statuses, status)
+	}
+
+	// Only delete workflows that we know we created, which are the ones that
+	// we reported on last time.
+	for _, oldStatus := range r.oldStatus.Materializations {
+		if wanted[oldStatus.Name] || workflows[oldStatus.Name] == nil {
+			continue
+		}
+		_, err := r.wr.VtctldServer().WorkflowDelete(ctx, &vtctldatapb.WorkflowDeleteRequest{
+			Keyspace: r.vtk.Spec.Name,
+			Workflow: oldStatus.Name,
+			KeepData: true,
+		})
+		if err != nil {
+			// Keep reporting on the workflow, so we try again next time.
+			oldStatus.Message = fmt.Sprintf("Failed to delete the workflow: %v", err)
+			statuses = append(statuses, oldStatus)
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "DeleteMaterializationFailed", "failed to delete Materialize workflow %v: %v", oldStatus.Name, err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+			continue
+		}
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "MaterializationDeleted", "Deleted Materialize workflow %v.", oldStatus.Name)
+	}
+
+	r.vtk.Status.Materializations = statuses
+	return resultBuilder.Result()
+}
+
+// createMaterialization creates the Materialize workflow for a spec entry.
+func (r *reconcileHandler) createMaterialization(ctx context.Context, mz *planetscalev2.VitessMaterialization) error {
+	_, err := r.wr.VtctldServer().MaterializeCreate(ctx, &vtctldatapb.MaterializeCreateRequest{
+		Settings: &vtctldatapb.MaterializeSettings{
+			Workflow:       mz.Name,
+			SourceKeyspace: mz.SourceKeyspace,
+			TargetKeyspace: r.vtk.Spec.Name,
+			StopAfterCopy:  mz.StopAfterCopy,
+			TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+				TargetTable:      mz.TargetTable,
+				SourceExpression: mz.SourceExpression,
+				CreateDdl:        mz.CreateDDL,
+			}},
+			TabletTypes:               strings.Join(mz.TabletTypes, ","),
+			TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+		},
+	})
+	return err
+}
+
+// referenceTableMaterializations returns the Materialize workflows that copy
+// the reference tables in the spec to every shard.
+func referenceTableMaterializations(schema *planetscalev2.VitessKeyspaceSchema) []planetscalev2.VitessMaterialization {
+	if schema == nil {
+		return nil
+	}
+	var materializations []planetscalev2.VitessMaterialization
+	for i := range schema.ReferenceTables {
+		table := &schema.ReferenceTables[i]
+		materializations = append(materializations, planetscalev2.VitessMaterialization{
+			Name:             table.Name,
+			SourceKeyspace:   table.SourceKeyspace,
+			TargetTable:      table.Name,
+			SourceExpression: fmt.Sprintf("select * from `%s`", table.Name),
+			CreateDDL:        "copy",
+			TabletTypes:      []string{"replica", "primary"},
+		})
+	}
+	return materializations
+}
+
+// materializationStatus summarizes the streams of a Materialize workflow.
+func materializationStatus(name string, workflow *vtctldatapb.Workflow) planetscalev2.VitessMaterializationStatus {
+	status := planetscalev2.VitessMaterializationStatus{
+		Name:                  name,
+		State:                 planetscalev2.WorkflowUnknown,
+		ReplicationLagSeconds: workflow.MaxVReplicationLag,
+	}
+
+	shards := make([]string, 0, len(workflow.ShardStreams))
+	for shard := range workflow.ShardStreams {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	var streams, running, stopped int
+	for _, shard := range shards {
+		for _, stream := range workflow.ShardStreams[shard].GetStreams() {
+			streams++
+			switch stream.State {
+			case "Error":
+				if status.State != planetscalev2.WorkflowError {
+					status.State = planetscalev2.WorkflowError
+					status.Message = fmt.Sprintf("VReplication reported an error on shard %v: %v", shard, stream.Message)
+				}
+			case "Copying":
+				if status.State != planetscalev2.WorkflowError {
+					status.State = planetscalev2.WorkflowCopying
+				}
+			case "Running", "Lagging":
+				running++
+			case "Stopped":
+				stopped++
+			}
+		}
+	}
+	if status.State == planetscalev2.WorkflowUnknown && streams > 0 {
+		switch streams {
+		case running:
+			status.State = planetscalev2.WorkflowRunning
+		case stopped:
+			status.State = planetscalev2.WorkflowStopped
+		}
+	}
+	return status
+}
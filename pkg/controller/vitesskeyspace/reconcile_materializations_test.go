@@ -0,0 +1,67 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMaterializationStatus(t *testing.T) {
+	newWorkflow := func(states ...string) *vtctldatapb.Workflow {
+		workflow := &vtctldatapb.Workflow{
+			MaxVReplicationLag: 7,
+			ShardStreams:       map[string]*vtctldatapb.Workflow_ShardStream{},
+		}
+		for i, state := range states {
+			shard := []string{"-80", "80-"}[i]
+			workflow.ShardStreams[shard] = &vtctldatapb.Workflow_ShardStream{
+				Streams: []*vtctldatapb.Workflow_Stream{{State: state, Message: "oops"}},
+			}
+		}
+		return workflow
+	}
+
+	table := []struct {
+		name     string
+		workflow *vtctldatapb.Workflow
+		want     planetscalev2.WorkflowState
+	}{
+		{name: "no streams", workflow: newWorkflow(), want: planetscalev2.WorkflowUnknown},
+		{name: "running", workflow: newWorkflow("Running", "Lagging"), want: planetscalev2.WorkflowRunning},
+		{name: "copying", workflow: newWorkflow("Running", "Copying"), want: planetscalev2.WorkflowCopying},
+		{name: "stopped", workflow: newWorkflow("Stopped", "Stopped"), want: planetscalev2.WorkflowStopped},
+		{name: "partly stopped", workflow: newWorkflow("Running", "Stopped"), want: planetscalev2.WorkflowUnknown},
+		{name: "error", workflow: newWorkflow("Copying", "Error"), want: planetscalev2.WorkflowError},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			status := materializationStatus("rollup", test.workflow)
+			assert.Equal(t, "rollup", status.Name)
+			assert.Equal(t, test.want, status.State)
+			assert.Equal(t, int64(7), status.ReplicationLagSeconds)
+			if test.want == planetscalev2.WorkflowError {
+				assert.Contains(t, status.Message, "shard 80-")
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"vitess.io/vitess/go/protoutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// activeSchemaMigrationStatuses are the statuses of migrations that haven't
+// finished yet.
+var activeSchemaMigrationStatuses = []vtctldatapb.SchemaMigration_Status{
+	vtctldatapb.SchemaMigration_REQUESTED,
+	vtctldatapb.SchemaMigration_QUEUED,
+	vtctldatapb.SchemaMigration_READY,
+	vtctldatapb.SchemaMigration_RUNNING,
+}
+
+// reconcileMigrationControls lists the migrations of the keyspace that haven't
+// finished yet, and throttles, cancels, or retries migrations as requested in
+// the spec.
+func (r *reconcileHandler) reconcileMigrationControls(ctx context.Context, spec *planetscalev2.VitessKeyspaceSchema, status *planetscalev2.VitessKeyspaceSchemaStatus) error {
+	var active []*vtctldatapb.SchemaMigration
+	for _, migrationStatus := range activeSchemaMigrationStatuses {
+		resp, err := r.wr.VtctldServer().GetSchemaMigrations(ctx, &vtctldatapb.GetSchemaMigrationsRequest{
+			Keyspace: r.vtk.Spec.Name,
+			Status:   migrationStatus,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list migrations: %v", err)
+		}
+		active = append(active, resp.Migrations...)
+	}
+	status.ActiveMigrations = activeSchemaMigrations(active)
+
+	if len(spec.MigrationControls) == 0 {
+		return nil
+	}
+	keyspaceInfo, err := r.ts.Server.GetKeyspace(ctx, r.vtk.Spec.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get keyspace record: %v", err)
+	}
+	now := time.Now()
+
+	for i := range spec.MigrationControls {
+		control := &spec.MigrationControls[i]
+		resp, err := r.wr.VtctldServer().GetSchemaMigrations(ctx, &vtctldatapb.GetSchemaMigrationsRequest{
+			Keyspace: r.vtk.Spec.Name,
+			Uuid:     control.UUID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get migration %v: %v", control.UUID, err)
+		}
+		if len(resp.Migrations) == 0 {
+			// Vitess doesn't know this migration, so there's nothing to do.
+			continue
+		}
+
+		cancel, retry := migrationControlActions(control, resp.Migrations)
+		if cancel {
+			if _, err := r.wr.VtctldServer().CancelSchemaMigration(ctx, &vtctldatapb.CancelSchemaMigrationRequest{
+				Keyspace: r.vtk.Spec.Name,
+				Uuid:     control.UUID,
+			}); err != nil {
+				return fmt.Errorf("failed to cancel migration %v: %v", control.UUID, err)
+			}
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationCancelled", "Cancelled schema migration %v.", control.UUID)
+		}
+		if retry {
+			if _, err := r.wr.VtctldServer().RetrySchemaMigration(ctx, &vtctldatapb.RetrySchemaMigrationRequest{
+				Keyspace: r.vtk.Spec.Name,
+				Uuid:     control.UUID,
+			}); err != nil {
+				return fmt.Errorf("failed to retry migration %v: %v", control.UUID, err)
+			}
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationRetried", "Retried schema migration %v.", control.UUID)
+			// If it's one of the migrations in the spec, go back to
+			// waiting for it to complete.
+			for j := range status.Migrations {
+				if status.Migrations[j].UUID == control.UUID {
+					status.Migrations[j].Phase = planetscalev2.SchemaMigrationRunning
+				}
+			}
+		}
+
+		request := migrationThrottleUpdate(r.vtk.Spec.Name, control, schemaMigrationActive(resp.Migrations) && !cancel, keyspaceInfo.ThrottlerConfig, now)
+		if request != nil {
+			if _, err := r.wr.VtctldServer().UpdateThrottlerConfig(ctx, request); err != nil {
+				return fmt.Errorf("failed to throttle migration %v: %v", control.UUID, err)
+			}
+			if request.ThrottledApp.Ratio > 0 {
+				r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationThrottled", "Throttled schema migration %v.", control.UUID)
+			} else {
+				r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationUnthrottled", "Unthrottled schema migration %v.", control.UUID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// activeSchemaMigrations groups the rows of migrations that haven't finished
+// yet by UUID.
+func activeSchemaMigrations(rows []*vtctldatapb.SchemaMigration) []planetscalev2.VitessActiveSchemaMigration {
+	var migrations []planetscalev2.VitessActiveSchemaMigration
+	byUUID := make(map[string]int)
+	for _, row := range rows {
+		i, ok := byUUID[row.Uuid]
+		if !ok {
+			i = len(migrations)
+			byUUID[row.Uuid] = i
+			migrations = append(migrations, planetscalev2.VitessActiveSchemaMigration{
+				UUID:             row.Uuid,
+				Table:            row.Table,
+				MigrationContext: row.MigrationContext,
+			})
+		}
+		migrations[i].Shards = append(migrations[i].Shards, schemaMigrationShardStatus(row))
+	}
+	for i := range migrations {
+		shards := migrations[i].Shards
+		sort.Slice(shards, func(a, b int) bool {
+			return shards[a].Shard < shards[b].Shard
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].UUID < migrations[j].UUID
+	})
+	return migrations
+}
+
+// schemaMigrationActive returns whether a migration hasn't finished yet on
+// some shard.
+func schemaMigrationActive(rows []*vtctldatapb.SchemaMigration) bool {
+	for _, row := range rows {
+		for _, status := range activeSchemaMigrationStatuses {
+			if row.Status == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// migrationControlActions returns whether a migration needs to be cancelled
+// or retried to match its control.
+func migrationControlActions(control *planetscalev2.VitessSchemaMigrationControl, rows []*vtctldatapb.SchemaMigration) (cancel, retry bool) {
+	if control.Cancelled {
+		return schemaMigrationActive(rows), false
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case vtctldatapb.SchemaMigration_FAILED, vtctldatapb.SchemaMigration_CANCELLED:
+			if row.Retries < uint64(control.Retries) {
+				retry = true
+			}
+		}
+	}
+	return false, retry
+}
+
+// migrationThrottleUpdate returns the request needed to throttle or unthrottle
+// a migration, if any. Migrations are throttled with a throttled app rule
+// named after their UUID. The rule is removed once the migration finishes.
+func migrationThrottleUpdate(keyspace string, control *planetscalev2.VitessSchemaMigrationControl, active bool, current *topodatapb.ThrottlerConfig, now time.Time) *vtctldatapb.UpdateThrottlerConfigRequest {
+	rule := current.GetThrottledApps()[control.UUID]
+	var expiresAt time.Time
+	if rule != nil {
+		expiresAt = protoutil.TimeFromProto(rule.ExpiresAt)
+	}
+	inEffect := rule != nil && rule.Ratio > 0 && expiresAt.After(now)
+
+	if control.Throttled && active {
+		if inEffect && rule.Ratio >= 1 && expiresAt.Sub(now) > throttledAppRenewBefore {
+			return nil
+		}
+		return &vtctldatapb.UpdateThrottlerConfigRequest{
+			Keyspace: keyspace,
+			ThrottledApp: &topodatapb.ThrottledAppRule{
+				Name:      control.UUID,
+				Ratio:     1,
+				ExpiresAt: protoutil.TimeToProto(now.Add(throttledAppLifetime)),
+			},
+		}
+	}
+
+	if !inEffect {
+		return nil
+	}
+	return &vtctldatapb.UpdateThrottlerConfigRequest{
+		Keyspace: keyspace,
+		ThrottledApp: &topodatapb.ThrottledAppRule{
+			Name:      control.UUID,
+			ExpiresAt: protoutil.TimeToProto(now),
+		},
+	}
+}
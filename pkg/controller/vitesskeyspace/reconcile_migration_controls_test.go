@@ -0,0 +1,100 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/protoutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMigrationControlActions(t *testing.T) {
+	running := []*vtctldatapb.SchemaMigration{
+		{Shard: "-80", Status: vtctldatapb.SchemaMigration_RUNNING},
+		{Shard: "80-", Status: vtctldatapb.SchemaMigration_COMPLETE},
+	}
+	failed := []*vtctldatapb.SchemaMigration{
+		{Shard: "-80", Status: vtctldatapb.SchemaMigration_FAILED, Retries: 1},
+		{Shard: "80-", Status: vtctldatapb.SchemaMigration_COMPLETE},
+	}
+
+	cancel, retry := migrationControlActions(&planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Cancelled: true}, running)
+	assert.True(t, cancel)
+	assert.False(t, retry)
+
+	// There's nothing left to cancel once it's finished.
+	cancel, _ = migrationControlActions(&planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Cancelled: true}, failed)
+	assert.False(t, cancel)
+
+	// Failed migrations are only retried until they've been retried enough.
+	_, retry = migrationControlActions(&planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Retries: 1}, failed)
+	assert.False(t, retry)
+	_, retry = migrationControlActions(&planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Retries: 2}, failed)
+	assert.True(t, retry)
+	_, retry = migrationControlActions(&planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Retries: 2}, running)
+	assert.False(t, retry)
+}
+
+func TestMigrationThrottleUpdate(t *testing.T) {
+	now := time.Now()
+	control := &planetscalev2.VitessSchemaMigrationControl{UUID: "u1", Throttled: true}
+
+	request := migrationThrottleUpdate("commerce", control, true, nil, now)
+	if assert.NotNil(t, request) {
+		assert.Equal(t, "u1", request.ThrottledApp.Name)
+		assert.Equal(t, 1.0, request.ThrottledApp.Ratio)
+	}
+
+	current := &topodatapb.ThrottlerConfig{
+		ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+			"u1": {Name: "u1", Ratio: 1, ExpiresAt: protoutil.TimeToProto(now.Add(throttledAppLifetime))},
+		},
+	}
+	assert.Nil(t, migrationThrottleUpdate("commerce", control, true, current, now))
+
+	// The rule is removed when it's no longer wanted, or once the migration
+	// has finished.
+	request = migrationThrottleUpdate("commerce", control, false, current, now)
+	if assert.NotNil(t, request) {
+		assert.Equal(t, 0.0, request.ThrottledApp.Ratio)
+	}
+	request = migrationThrottleUpdate("commerce", &planetscalev2.VitessSchemaMigrationControl{UUID: "u1"}, true, current, now)
+	assert.NotNil(t, request)
+	assert.Nil(t, migrationThrottleUpdate("commerce", &planetscalev2.VitessSchemaMigrationControl{UUID: "u1"}, true, nil, now))
+}
+
+func TestActiveSchemaMigrations(t *testing.T) {
+	migrations := activeSchemaMigrations([]*vtctldatapb.SchemaMigration{
+		{Uuid: "u2", Shard: "80-", Table: "customer", Status: vtctldatapb.SchemaMigration_QUEUED},
+		{Uuid: "u1", Shard: "-80", Table: "orders", Status: vtctldatapb.SchemaMigration_RUNNING},
+		{Uuid: "u2", Shard: "-80", Table: "customer", Status: vtctldatapb.SchemaMigration_RUNNING},
+	})
+	if assert.Len(t, migrations, 2) {
+		assert.Equal(t, "u1", migrations[0].UUID)
+		assert.Equal(t, "customer", migrations[1].Table)
+		if assert.Len(t, migrations[1].Shards, 2) {
+			assert.Equal(t, "-80", migrations[1].Shards[0].Shard)
+			assert.Equal(t, "queued", migrations[1].Shards[1].Status)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	// schemaMigrationPollInterval is how often we check on a running schema
+	// migration.
+	schemaMigrationPollInterval = 30 * time.Second
+)
+
+// reconcileSchema creates the sequence tables and applies the VSchema from
+// the spec, and makes the schema migrations in the spec one at a time with
+// Online DDL.
+func (r *reconcileHandler) reconcileSchema(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	spec := r.vtk.Spec.Schema
+	if spec == nil {
+		return resultBuilder.Result()
+	}
+
+	// Carry over what we know about migrations that are still in the spec.
+	status := &planetscalev2.VitessKeyspaceSchemaStatus{}
+	r.vtk.Status.Schema = status
+	if r.oldStatus.Schema != nil {
+		oldMigrations := make(map[string]*planetscalev2.VitessSchemaMigrationStatus, len(r.oldStatus.Schema.Migrations))
+		for i := range r.oldStatus.Schema.Migrations {
+			oldMigrations[r.oldStatus.Schema.Migrations[i].Name] = &r.oldStatus.Schema.Migrations[i]
+		}
+		for i := range spec.Migrations {
+			if old := oldMigrations[spec.Migrations[i].Name]; old != nil {
+				status.Migrations = append(status.Migrations, *old.DeepCopy())
+			}
+		}
+	}
+
+	if err := r.tsInit(ctx); err != nil {
+		status.Message = "Waiting for the topology server to be available."
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	if err := r.reconcileSequences(ctx, spec, status); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "SequenceCreateFailed", "%v", err)
+		status.Message = fmt.Sprintf("Failed to create sequences: %v", err)
+		resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	if spec.VSchema != "" || len(spec.Sequences) != 0 || len(spec.ReferenceTables) != 0 {
+		if err := r.reconcileVSchema(ctx, spec, status); err != nil {
+			status.Message = fmt.Sprintf("Failed to apply the VSchema: %v", err)
+			resultBuilder.RequeueAfter(topoRequeueDelay)
+		}
+	}
+
+	if err := r.reconcileMigrationControls(ctx, spec, status); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "SchemaMigrationControlFailed", "%v", err)
+		status.Message = fmt.Sprintf("Failed to control schema migrations: %v", err)
+		resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	if len(status.ActiveMigrations) != 0 {
+		// Keep the list of active migrations up to date.
+		resultBuilder.RequeueAfter(schemaMigrationPollInterval)
+	}
+
+	for i := range spec.Migrations {
+		migration := &spec.Migrations[i]
+		migrationStatus := findSchemaMigrationStatus(status, migration.Name)
+		if migrationStatus == nil {
+			// Each migration waits for the one before it, so this one can
+			// be started now.
+			var err error
+			migrationStatus, err = r.startSchemaMigration(ctx, spec, migration)
+			if err != nil {
+				r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "SchemaMigrationFailed", "failed to start schema migration %v: %v", migration.Name, err)
+				status.Message = fmt.Sprintf("Failed to start schema migration %v: %v", migration.Name, err)
+				return resultBuilder.RequeueAfter(topoRequeueDelay)
+			}
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationStarted", "Started schema migration %v with UUID %q.", migration.Name, migrationStatus.UUID)
+			status.Migrations = append(status.Migrations, *migrationStatus)
+			migrationStatus = &status.Migrations[len(status.Migrations)-1]
+		}
+
+		if migrationStatus.Phase == planetscalev2.SchemaMigrationRunning {
+			resp, err := r.wr.VtctldServer().GetSchemaMigrations(ctx, &vtctldatapb.GetSchemaMigrationsRequest{
+				Keyspace: r.vtk.Spec.Name,
+				Uuid:     migrationStatus.UUID,
+			})
+			if err != nil {
+				status.Message = fmt.Sprintf("Failed to check on schema migration %v: %v", migration.Name, err)
+				return resultBuilder.RequeueAfter(topoRequeueDelay)
+			}
+			updateSchemaMigrationStatus(migrationStatus, resp.Migrations)
+
+			switch migrationStatus.Phase {
+			case planetscalev2.SchemaMigrationComplete:
+				r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SchemaMigrationComplete", "Schema migration %v is complete.", migration.Name)
+			case planetscalev2.SchemaMigrationFailed:
+				r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "SchemaMigrationFailed", "Schema migration %v failed: %v", migration.Name, migrationStatus.Message)
+			}
+		}
+
+		switch migrationStatus.Phase {
+		case planetscalev2.SchemaMigrationRunning:
+			status.Message = fmt.Sprintf("Waiting for schema migration %v to complete.", migration.Name)
+			return resultBuilder.RequeueAfter(schemaMigrationPollInterval)
+		case planetscalev2.SchemaMigrationFailed:
+			status.Message = fmt.Sprintf("Schema migration %v failed. Migrations after it wait until it's removed from the spec.", migration.Name)
+			return resultBuilder.Result()
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// startSchemaMigration submits a schema migration, unless it was already
+// submitted by an earlier reconcile whose status update was lost. Migrations
+// are tagged with a migration context that identifies them, so we can tell.
+func (r *reconcileHandler) startSchemaMigration(ctx context.Context, spec *planetscalev2.VitessKeyspaceSchema, migration *planetscalev2.VitessSchemaMigration) (*planetscalev2.VitessSchemaMigrationStatus, error) {
+	migrationContext := schemaMigrationContext(r.vtk.Name, migration.Name)
+	status := &planetscalev2.VitessSchemaMigrationStatus{
+		Name:  migration.Name,
+		Phase: planetscalev2.SchemaMigrationRunning,
+	}
+
+	existing, err := r.wr.VtctldServer().GetSchemaMigrations(ctx, &vtctldatapb.GetSchemaMigrationsRequest{
+		Keyspace:         r.vtk.Spec.Name,
+		MigrationContext: migrationContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing.Migrations) != 0 {
+		status.UUID = existing.Migrations[0].Uuid
+		return status, nil
+	}
+
+	ddlStrategy := string(spec.DDLStrategy)
+	if spec.StrategyFlags != "" {
+		ddlStrategy += " " + spec.StrategyFlags
+	}
+	resp, err := r.wr.VtctldServer().ApplySchema(ctx, &vtctldatapb.ApplySchemaRequest{
+		Keyspace:         r.vtk.Spec.Name,
+		Sql:              []string{migration.SQL},
+		DdlStrategy:      ddlStrategy,
+		MigrationContext: migrationContext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.UuidList) == 0 {
+		// Statements run with the direct strategy are done once
+		// ApplySchema returns, and aren't tracked.
+		status.Phase = planetscalev2.SchemaMigrationComplete
+		return status, nil
+	}
+	status.UUID = resp.UuidList[0]
+	return status, nil
+}
+
+// schemaMigrationContext returns the migration context that the migration
+// with the given name in the given VitessKeyspace is tagged with.
+func schemaMigrationContext(vtkName, migrationName string) string {
+	return fmt.Sprintf("vitess-operator:%s:%s", vtkName, migrationName)
+}
+
+func findSchemaMigrationStatus(status *planetscalev2.VitessKeyspaceSchemaStatus, name string) *planetscalev2.VitessSchemaMigrationStatus {
+	for i := range status.Migrations {
+		if status.Migrations[i].Name == name {
+			return &status.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// updateSchemaMigrationStatus fills in the per-shard progress of a migration
+// and sums it up into a phase.
+func updateSchemaMigrationStatus(status *planetscalev2.VitessSchemaMigrationStatus, rows []*vtctldatapb.SchemaMigration) {
+	status.Shards = nil
+	status.Message = ""
+
+	var failed []string
+	complete := 0
+	for _, row := range rows {
+		status.Shards = append(status.Shards, schemaMigrationShardStatus(row))
+		switch row.Status {
+		case vtctldatapb.SchemaMigration_COMPLETE:
+			complete++
+		case vtctldatapb.SchemaMigration_FAILED, vtctldatapb.SchemaMigration_CANCELLED:
+			failed = append(failed, fmt.Sprintf("%v: %v %v", row.Shard, strings.ToLower(row.Status.String()), row.Message))
+		}
+	}
+	sort.Slice(status.Shards, func(i, j int) bool {
+		return status.Shards[i].Shard < status.Shards[j].Shard
+	})
+	sort.Strings(failed)
+
+	switch {
+	case len(failed) != 0:
+		status.Phase = planetscalev2.SchemaMigrationFailed
+		status.Message = strings.Join(failed, "; ")
+	case len(rows) != 0 && complete == len(rows):
+		status.Phase = planetscalev2.SchemaMigrationComplete
+	default:
+		status.Phase = planetscalev2.SchemaMigrationRunning
+	}
+}
+
+// schemaMigrationShardStatus reports on a migration on one shard.
+func schemaMigrationShardStatus(row *vtctldatapb.SchemaMigration) planetscalev2.VitessSchemaMigrationShardStatus {
+	return planetscalev2.VitessSchemaMigrationShardStatus{
+		Shard:    row.Shard,
+		Status:   strings.ToLower(row.Status.String()),
+		Progress: int32(row.Progress),
+		Message:  row.Message,
+	}
+}
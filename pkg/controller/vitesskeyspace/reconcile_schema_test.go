@@ -0,0 +1,59 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateSchemaMigrationStatus(t *testing.T) {
+	status := &planetscalev2.VitessSchemaMigrationStatus{Name: "add-email", UUID: "u1"}
+
+	// Nothing reported yet means it's still running.
+	updateSchemaMigrationStatus(status, nil)
+	assert.Equal(t, planetscalev2.SchemaMigrationRunning, status.Phase)
+
+	updateSchemaMigrationStatus(status, []*vtctldatapb.SchemaMigration{
+		{Shard: "80-", Status: vtctldatapb.SchemaMigration_RUNNING, Progress: 42.5},
+		{Shard: "-80", Status: vtctldatapb.SchemaMigration_COMPLETE, Progress: 100},
+	})
+	assert.Equal(t, planetscalev2.SchemaMigrationRunning, status.Phase)
+	if assert.Len(t, status.Shards, 2) {
+		assert.Equal(t, "-80", status.Shards[0].Shard)
+		assert.Equal(t, "running", status.Shards[1].Status)
+		assert.Equal(t, int32(42), status.Shards[1].Progress)
+	}
+
+	updateSchemaMigrationStatus(status, []*vtctldatapb.SchemaMigration{
+		{Shard: "80-", Status: vtctldatapb.SchemaMigration_COMPLETE, Progress: 100},
+		{Shard: "-80", Status: vtctldatapb.SchemaMigration_COMPLETE, Progress: 100},
+	})
+	assert.Equal(t, planetscalev2.SchemaMigrationComplete, status.Phase)
+
+	// A failure on any shard fails the migration.
+	updateSchemaMigrationStatus(status, []*vtctldatapb.SchemaMigration{
+		{Shard: "80-", Status: vtctldatapb.SchemaMigration_FAILED, Message: "duplicate column name"},
+		{Shard: "-80", Status: vtctldatapb.SchemaMigration_COMPLETE, Progress: 100},
+	})
+	assert.Equal(t, planetscalev2.SchemaMigrationFailed, status.Phase)
+	assert.Equal(t, "80-: failed duplicate column name", status.Message)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// reconcileSequences creates the sequence tables in the spec that weren't
+// created yet. Sequence tables live in an unsharded keyspace, so they're
+// created directly on the primary of its only shard.
+func (r *reconcileHandler) reconcileSequences(ctx context.Context, spec *planetscalev2.VitessKeyspaceSchema, status *planetscalev2.VitessKeyspaceSchemaStatus) error {
+	created := make(map[string]bool)
+	if r.oldStatus.Schema != nil {
+		for _, name := range r.oldStatus.Schema.Sequences {
+			created[name] = true
+		}
+	}
+
+	var missing []*planetscalev2.VitessSequence
+	for i := range spec.Sequences {
+		sequence := &spec.Sequences[i]
+		if created[sequence.Name] {
+			status.Sequences = append(status.Sequences, sequence.Name)
+			continue
+		}
+		missing = append(missing, sequence)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	shards, err := r.ts.FindAllShardsInKeyspace(ctx, r.vtk.Spec.Name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list shards: %v", err)
+	}
+	if len(shards) != 1 {
+		return fmt.Errorf("sequences can only be created in an unsharded keyspace, but the keyspace has %v shards", len(shards))
+	}
+	for _, shard := range shards {
+		if shard.PrimaryAlias == nil {
+			return fmt.Errorf("shard %v has no primary yet", shard.ShardName())
+		}
+		for _, sequence := range missing {
+			for _, query := range sequenceTableQueries(sequence) {
+				if _, err := r.wr.VtctldServer().ExecuteFetchAsDBA(ctx, &vtctldatapb.ExecuteFetchAsDBARequest{
+					TabletAlias:  shard.PrimaryAlias,
+					Query:        query,
+					ReloadSchema: true,
+				}); err != nil {
+					return fmt.Errorf("failed to create sequence %v: %v", sequence.Name, err)
+				}
+			}
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "SequenceCreated", "Created sequence table %v.", sequence.Name)
+			status.Sequences = append(status.Sequences, sequence.Name)
+		}
+	}
+	return nil
+}
+
+// sequenceTableQueries returns the statements that create a sequence table
+// and its row, without touching either if they already exist.
+func sequenceTableQueries(sequence *planetscalev2.VitessSequence) []string {
+	return []string{
+		fmt.Sprintf("create table if not exists `%s` (id int, next_id bigint, cache bigint, primary key(id)) comment 'vitess_sequence'", sequence.Name),
+		fmt.Sprintf("insert ignore into `%s` (id, next_id, cache) values (0, %d, %d)", sequence.Name, *sequence.Start, *sequence.Cache),
+	}
+}
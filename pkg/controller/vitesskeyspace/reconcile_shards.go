@@ -18,16 +18,19 @@ package vitesskeyspace
 
 import (
 	"context"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessshard"
 )
 
@@ -42,18 +45,27 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 	// Compute the set of all desired shards based on the defined partitionings.
 	shards := r.vtk.Spec.ShardTemplates()
 
+	// Hold off on creating shards while too many others are still coming up.
+	pending, err := r.shardsPending(ctx, labels)
+	if err != nil {
+		return err
+	}
+
 	// Generate keys (object names) for all desired shards.
 	// Keep a map back from generated names to the shard specs.
 	keys := make([]client.ObjectKey, 0, len(shards))
 	shardMap := make(map[client.ObjectKey]*planetscalev2.VitessKeyspaceKeyRangeShard, len(shards))
 	for _, shard := range shards {
-		key := client.ObjectKey{Namespace: r.vtk.Namespace, Name: vitessshard.Name(clusterName, r.vtk.Spec.Name, shard.KeyRange)}
-		keys = append(keys, key)
-		shardMap[key] = shard
-
 		// Initialize a status entry for every desired shard, so it will be
 		// listed even if we end up not having anything to report about it.
 		r.vtk.Status.Shards[shard.KeyRange.String()] = planetscalev2.NewVitessKeyspaceShardStatus(shard)
+
+		if pending[shard.KeyRange.String()] {
+			continue
+		}
+		key := client.ObjectKey{Namespace: r.vtk.Namespace, Name: vitessshard.Name(clusterName, r.vtk.Spec.Name, shard.KeyRange)}
+		keys = append(keys, key)
+		shardMap[key] = shard
 	}
 
 	// Initialize a status entry for each desired partitioning, so it will be
@@ -63,16 +75,30 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 		p := &r.vtk.Spec.Partitionings[i]
 		r.vtk.Status.Partitionings[i] = planetscalev2.NewVitessKeyspacePartitioningStatus(p)
 	}
+	if len(pending) > 0 {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "ShardCreationPending", "Waiting to create %d shards until fewer shards are coming up.", len(pending))
+	}
+
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := r.vtk.Spec.UpdateStrategy.Paused || rollout.Paused(r.vtk)
+
+	// Hold back shards that must wait for earlier shards in the update order.
+	heldBack, waitingFor, err := r.shardsHeldBack(ctx, labels)
+	if err != nil {
+		return err
+	}
+	var heldBackShards []string
 
-	err := r.reconciler.ReconcileObjectSet(ctx, r.vtk, keys, labels, reconciler.Strategy{
-		Kind: &planetscalev2.VitessShard{},
+	err = r.reconciler.ReconcileObjectSet(ctx, r.vtk, keys, labels, reconciler.Strategy{
+		Kind:          &planetscalev2.VitessShard{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return newVitessShard(key, r.vtk, labels, shardMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessShard)
-			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ExternalVitessClusterUpdateStrategyType {
+			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ExternalVitessClusterUpdateStrategyType || paused {
 				updateVitessShardInPlace(key, newObj, r.vtk, labels, shardMap[key])
 				return
 			}
@@ -87,6 +113,10 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 			// our current shard generation, then we should cascade changes.
 			for _, tabletStatus := range newObj.Status.Tablets {
 				if tabletStatus.PendingChanges != "" {
+					if heldBack[newObj.Spec.KeyRange.String()] && !rollout.Cascading(newObj) {
+						heldBackShards = append(heldBackShards, newObj.Spec.KeyRange.String())
+						return
+					}
 					rollout.Cascade(newObj)
 					return
 				}
@@ -94,7 +124,7 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 		},
 		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*planetscalev2.VitessShard)
-			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType && !paused {
 				// In this case we should use UpdateInPlace for all updates.
 				return
 			}
@@ -117,12 +147,19 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 
 			status.ReadyTablets = 0
 			status.UpdatedTablets = 0
+			status.FailedTablets = 0
+			stuck := curObj.Status.RolloutStuck()
 			for _, tablet := range curObj.Status.Tablets {
 				if tablet.Ready == corev1.ConditionTrue {
 					status.ReadyTablets++
 				}
 				if tablet.PendingChanges == "" {
 					status.UpdatedTablets++
+					// While the rollout is stuck, updated tablets that
+					// aren't coming up are the ones holding it back.
+					if stuck && tablet.Ready != corev1.ConditionTrue {
+						status.FailedTablets++
+					}
 				}
 			}
 			r.vtk.Status.Shards[keyRange] = status
@@ -147,6 +184,10 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if len(heldBackShards) > 0 {
+		sort.Strings(heldBackShards)
+		r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "RolloutPaused", "Holding back updates to shards %v until shard %v finishes rolling out.", heldBackShards, waitingFor)
+	}
 
 	// Aggregate per-shard status, grouped by partitioning.
 	var foundServingPartitioning bool
@@ -157,6 +198,7 @@ func (r *reconcileHandler) reconcileShards(ctx context.Context) error {
 		status.ReadyTablets = totalReadyTablets(status.ShardNames, r.vtk.Status.Shards)
 		status.UpdatedTablets = totalUpdatedTablets(status.ShardNames, r.vtk.Status.Shards)
 		status.ReadyShards = totalReadyShards(status.ShardNames, r.vtk.Status.Shards)
+		status.PendingShards = totalPendingShards(status.ShardNames, pending)
 
 		if status.ServingWrites == corev1.ConditionTrue {
 			foundServingPartitioning = true
@@ -243,6 +285,17 @@ func totalReadyShards(shardNames []string, shardStatus map[string]planetscalev2.
 	return readyShards
 }
 
+func totalPendingShards(shardNames []string, pending map[string]bool) int32 {
+	var pendingShards int32
+	for _, shardName := range shardNames {
+		if pending[shardName] {
+			pendingShards++
+		}
+	}
+
+	return pendingShards
+}
+
 // newVitessShard expands a complete VitessShard from a VitessShardTemplate.
 //
 // A VitessShard consists of both user-configured parts, which come from VitessShardTemplate,
@@ -259,29 +312,57 @@ func newVitessShard(key client.ObjectKey, vtk *planetscalev2.VitessKeyspace, par
 	}
 	labels[planetscalev2.ShardLabel] = shard.KeyRange.SafeName()
 
+	// An on-demand backup requested for the whole keyspace is passed on to
+	// each shard, as is the final backup of a decommission. Copy the map,
+	// since the template's annotations also end up in the shard spec, which
+	// should only list the ones from the template.
+	annotations := template.Annotations
+	requestID := vtk.Annotations[vitessbackup.RequestAnnotation]
+	if vtk.Status.Decommission != nil && vtk.Status.Decommission.BackupRequest != "" {
+		requestID = vtk.Status.Decommission.BackupRequest
+	}
+	if requestID != "" {
+		annotations = make(map[string]string, len(template.Annotations)+1)
+		for k, v := range template.Annotations {
+			annotations[k] = v
+		}
+		annotations[vitessbackup.RequestAnnotation] = requestID
+	}
+
 	return &planetscalev2.VitessShard{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   key.Namespace,
 			Name:        key.Name,
 			Labels:      labels,
-			Annotations: template.Annotations,
+			Annotations: annotations,
 		},
 		Spec: planetscalev2.VitessShardSpec{
-			VitessShardTemplate:    *template,
-			GlobalLockserver:       vtk.Spec.GlobalLockserver,
-			VitessOrchestrator:     vtk.Spec.VitessOrchestrator,
-			Images:                 vtk.Spec.Images,
-			ImagePullPolicies:      vtk.Spec.ImagePullPolicies,
-			ImagePullSecrets:       vtk.Spec.ImagePullSecrets,
-			Name:                   shard.KeyRange.String(),
-			DatabaseName:           vtk.Spec.DatabaseName,
-			KeyRange:               shard.KeyRange,
-			ZoneMap:                vtk.Spec.ZoneMap,
-			BackupLocations:        vtk.Spec.BackupLocations,
-			BackupEngine:           vtk.Spec.BackupEngine,
-			ExtraVitessFlags:       vtk.Spec.ExtraVitessFlags,
-			TopologyReconciliation: vtk.Spec.TopologyReconciliation,
-			UpdateStrategy:         vtk.Spec.UpdateStrategy,
+			VitessShardTemplate:      *template,
+			GlobalLockserver:         vtk.Spec.GlobalLockserver,
+			VitessOrchestrator:       vtk.Spec.VitessOrchestrator,
+			Images:                   vtk.Spec.Images,
+			ImagePullPolicies:        vtk.Spec.ImagePullPolicies,
+			ImagePullSecrets:         vtk.Spec.ImagePullSecrets,
+			Name:                     shard.KeyRange.String(),
+			DatabaseName:             vtk.Spec.DatabaseName,
+			KeyRange:                 shard.KeyRange,
+			ZoneMap:                  vtk.Spec.ZoneMap,
+			BackupLocations:          vtk.Spec.BackupLocations,
+			BackupEngine:             vtk.Spec.BackupEngine,
+			Vtbackup:                 vtk.Spec.Vtbackup,
+			BackupVerification:       vtk.Spec.BackupVerification,
+			BackupSourceTabletPolicy: vtk.Spec.BackupSourceTabletPolicy,
+			BackupReplication:        vtk.Spec.BackupReplication,
+			ExtraVitessFlags:         vtk.Spec.ExtraVitessFlags,
+			TopologyReconciliation:   vtk.Spec.TopologyReconciliation,
+			UpdateStrategy:           vtk.Spec.UpdateStrategy.WithPaused(rollout.Paused(vtk)),
+			MaintenanceWindows:       vtk.Spec.MaintenanceWindows,
+			PreferredPrimaryCells:    vtk.Spec.PreferredPrimaryCells,
+			ReparentSettings:         vtk.Spec.ReparentSettings,
+			ReparentConcurrency:      vtk.Spec.ReparentConcurrency,
+			BackupSchedule:           vtk.Spec.BackupSchedule,
+			InitialRestore:           vtk.Spec.InitialRestore,
+			Standby:                  vtk.Spec.Standby,
 		},
 	}
 }
@@ -305,6 +386,8 @@ func updateVitessShardInPlace(key client.ObjectKey, vts *planetscalev2.VitessSha
 
 	// Switching update strategies should always take effect immediately.
 	vts.Spec.UpdateStrategy = newShard.Spec.UpdateStrategy
+	// So should changing when disruptive changes may be made.
+	vts.Spec.MaintenanceWindows = newShard.Spec.MaintenanceWindows
 
 	// For now, only disk size & annotations are safe to update in place.
 	// However, only update disk size immediately if specified to.
@@ -314,6 +397,22 @@ func updateVitessShardInPlace(key client.ObjectKey, vts *planetscalev2.VitessSha
 		}
 	}
 
+	// Tablet pools in cells that were removed from the cluster are turned down
+	// right away, since the VitessCluster controller only leaves them out once
+	// it's safe to do so.
+	removedCells := sets.NewString()
+	for cell := range vts.Spec.ZoneMap {
+		if _, ok := newShard.Spec.ZoneMap[cell]; !ok {
+			removedCells.Insert(cell)
+		}
+	}
+	removedCells = removedCells.Difference(newShard.Spec.GetCells())
+	if vts.Spec.RemoveTabletPoolsInCells(removedCells) {
+		for _, cell := range removedCells.List() {
+			delete(vts.Spec.ZoneMap, cell)
+		}
+	}
+
 	// Add or remove annotations requested in vts.Spec.Annotations.
 	updateVitessShardAnnotations(vts, newShard)
 }
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/protoutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+const (
+	// throttledAppLifetime is how long a throttled app rule without an
+	// expiry in the spec is set to last. Vitess requires every rule to
+	// expire, so we renew these before they do.
+	throttledAppLifetime = 365 * 24 * time.Hour
+	// throttledAppRenewBefore is how long before a rule without an expiry in
+	// the spec would expire that we renew it.
+	throttledAppRenewBefore = 30 * 24 * time.Hour
+)
+
+// reconcileThrottler applies the throttler configuration from the spec to
+// the keyspace record in the topology. Tablets read the configuration from
+// there, so it doesn't matter which tablets exist or which one is primary.
+func (r *reconcileHandler) reconcileThrottler(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	spec := r.vtk.Spec.Throttler
+	if spec == nil {
+		return resultBuilder.Result()
+	}
+
+	if err := r.tsInit(ctx); err != nil {
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+	keyspaceInfo, err := r.ts.Server.GetKeyspace(ctx, r.vtk.Spec.Name)
+	if err != nil {
+		// The keyspace record is created by reconcileKeyspaceInformation.
+		return resultBuilder.RequeueAfter(topoRequeueDelay)
+	}
+
+	requests, err := throttlerConfigUpdates(r.vtk.Spec.Name, spec, keyspaceInfo.ThrottlerConfig, time.Now())
+	if err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ThrottlerConfigInvalid", "invalid throttler configuration: %v", err)
+		return resultBuilder.Result()
+	}
+	for _, request := range requests {
+		if _, err := r.wr.VtctldServer().UpdateThrottlerConfig(ctx, request); err != nil {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "ThrottlerConfigFailed", "failed to update throttler configuration: %v", err)
+			return resultBuilder.Error(err)
+		}
+	}
+	if len(requests) != 0 {
+		r.recorder.Event(r.vtk, corev1.EventTypeNormal, "ThrottlerConfigUpdated", "Updated throttler configuration.")
+	}
+
+	return resultBuilder.Result()
+}
+
+// throttlerConfigUpdates returns the requests needed to bring the current
+// throttler configuration of a keyspace in line with the spec. Each request
+// can only set one throttled app, so there's one more for each app that
+// needs to change.
+func throttlerConfigUpdates(keyspace string, spec *planetscalev2.VitessThrottlerSpec, current *topodatapb.ThrottlerConfig, now time.Time) ([]*vtctldatapb.UpdateThrottlerConfigRequest, error) {
+	if current == nil {
+		current = &topodatapb.ThrottlerConfig{}
+	}
+	var requests []*vtctldatapb.UpdateThrottlerConfigRequest
+
+	var threshold float64
+	if spec.Threshold != "" {
+		var err error
+		threshold, err = strconv.ParseFloat(spec.Threshold, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q: %v", spec.Threshold, err)
+		}
+	}
+	if current.Enabled != spec.Enabled ||
+		(threshold != 0 && current.Threshold != threshold) ||
+		current.CustomQuery != spec.CustomQuery ||
+		current.CheckAsCheckSelf != spec.CheckAsCheckSelf {
+		requests = append(requests, &vtctldatapb.UpdateThrottlerConfigRequest{
+			Keyspace:          keyspace,
+			Enable:            spec.Enabled,
+			Disable:           !spec.Enabled,
+			Threshold:         threshold,
+			CustomQuery:       spec.CustomQuery,
+			CustomQuerySet:    current.CustomQuery != spec.CustomQuery,
+			CheckAsCheckSelf:  spec.CheckAsCheckSelf,
+			CheckAsCheckShard: !spec.CheckAsCheckSelf,
+		})
+	}
+
+	for i := range spec.ThrottledApps {
+		app := &spec.ThrottledApps[i]
+		ratio := 1.0
+		if app.Ratio != "" {
+			var err error
+			ratio, err = strconv.ParseFloat(app.Ratio, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ratio %q for throttled app %v: %v", app.Ratio, app.Name, err)
+			}
+		}
+
+		rule := current.ThrottledApps[app.Name]
+		if rule != nil && rule.Ratio == ratio && rule.Exempt == app.Exempt {
+			expiresAt := protoutil.TimeFromProto(rule.ExpiresAt)
+			if app.ExpiresAt != nil && expiresAt.Equal(app.ExpiresAt.Time) {
+				continue
+			}
+			if app.ExpiresAt == nil && expiresAt.Sub(now) > throttledAppRenewBefore {
+				continue
+			}
+		}
+
+		expiresAt := now.Add(throttledAppLifetime)
+		if app.ExpiresAt != nil {
+			expiresAt = app.ExpiresAt.Time
+		}
+		requests = append(requests, &vtctldatapb.UpdateThrottlerConfigRequest{
+			Keyspace: keyspace,
+			ThrottledApp: &topodatapb.ThrottledAppRule{
+				Name:      app.Name,
+				Ratio:     ratio,
+				Exempt:    app.Exempt,
+				ExpiresAt: protoutil.TimeToProto(expiresAt),
+			},
+		})
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/protoutil"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestThrottlerConfigUpdates(t *testing.T) {
+	now := time.Now()
+	spec := &planetscalev2.VitessThrottlerSpec{
+		Enabled:   true,
+		Threshold: "1.5",
+		ThrottledApps: []planetscalev2.VitessThrottledApp{
+			{Name: "online-ddl", Ratio: "0.5"},
+			{Name: "vreplication", Exempt: true, Ratio: "0"},
+		},
+	}
+
+	// Everything is set on a keyspace that has no throttler configuration.
+	requests, err := throttlerConfigUpdates("commerce", spec, nil, now)
+	if !assert.NoError(t, err) || !assert.Len(t, requests, 3) {
+		return
+	}
+	assert.True(t, requests[0].Enable)
+	assert.Equal(t, 1.5, requests[0].Threshold)
+	assert.Equal(t, "online-ddl", requests[1].ThrottledApp.Name)
+	assert.Equal(t, 0.5, requests[1].ThrottledApp.Ratio)
+	assert.True(t, requests[2].ThrottledApp.Exempt)
+
+	// Nothing is set once the configuration matches, and apps that aren't
+	// in the spec are left alone.
+	current := &topodatapb.ThrottlerConfig{
+		Enabled:   true,
+		Threshold: 1.5,
+		ThrottledApps: map[string]*topodatapb.ThrottledAppRule{
+			"online-ddl":   {Name: "online-ddl", Ratio: 0.5, ExpiresAt: protoutil.TimeToProto(now.Add(throttledAppLifetime))},
+			"vreplication": {Name: "vreplication", Exempt: true, ExpiresAt: protoutil.TimeToProto(now.Add(throttledAppLifetime))},
+			"schema-diff":  {Name: "schema-diff", Ratio: 1, ExpiresAt: protoutil.TimeToProto(now.Add(time.Hour))},
+		},
+	}
+	requests, err = throttlerConfigUpdates("commerce", spec, current, now)
+	if assert.NoError(t, err) {
+		assert.Empty(t, requests)
+	}
+
+	// Rules are renewed before they expire.
+	requests, err = throttlerConfigUpdates("commerce", spec, current, now.Add(throttledAppLifetime-throttledAppRenewBefore))
+	if assert.NoError(t, err) {
+		assert.Len(t, requests, 2)
+	}
+
+	// Disabling the throttler leaves the threshold alone if it's not set.
+	requests, err = throttlerConfigUpdates("commerce", &planetscalev2.VitessThrottlerSpec{}, current, now)
+	if assert.NoError(t, err) && assert.Len(t, requests, 1) {
+		assert.True(t, requests[0].Disable)
+		assert.Equal(t, 0.0, requests[0].Threshold)
+		assert.False(t, requests[0].CustomQuerySet)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	"vitess.io/vitess/go/json2"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+)
+
+// reconcileVSchema replaces the VSchema of the keyspace if it's different
+// from the one in the spec. With the Manual apply policy, the differences are
+// listed in status until the VSchema in the spec is approved.
+func (r *reconcileHandler) reconcileVSchema(ctx context.Context, spec *planetscalev2.VitessKeyspaceSchema, status *planetscalev2.VitessKeyspaceSchemaStatus) error {
+	// A keyspace without a VSchema yet is compared to an empty one.
+	current := &vschemapb.Keyspace{}
+	if resp, err := r.wr.VtctldServer().GetVSchema(ctx, &vtctldatapb.GetVSchemaRequest{Keyspace: r.vtk.Spec.Name}); err == nil && resp.VSchema != nil {
+		current = resp.VSchema
+	}
+
+	// Without a VSchema in the spec, we only add the sequences and reference
+	// tables to the one that's there.
+	want := proto.Clone(current).(*vschemapb.Keyspace)
+	if spec.VSchema != "" {
+		want = &vschemapb.Keyspace{}
+		if err := json2.Unmarshal([]byte(spec.VSchema), want); err != nil {
+			return fmt.Errorf("invalid VSchema: %v", err)
+		}
+	}
+	addManagedVSchemaTables(want, spec)
+	hash, err := vschemaHash(want)
+	if err != nil {
+		return fmt.Errorf("invalid VSchema: %v", err)
+	}
+	status.VSchemaHash = hash
+
+	changes := vschemaChanges(current, want)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// If the spec hasn't changed since the VSchema was last in sync, it was
+	// changed outside the operator.
+	oldStatus := r.oldStatus.Schema
+	if oldStatus != nil && oldStatus.VSchemaHash == hash && len(oldStatus.PendingVSchemaChanges) == 0 {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "VSchemaDrift", "VSchema was changed outside the operator: %v", strings.Join(changes, "; "))
+	}
+
+	if spec.VSchemaApplyPolicy == planetscalev2.VSchemaApplyManual && spec.ApprovedVSchemaHash != hash {
+		// Make sure the VSchema would be accepted before asking for approval.
+		if _, err := r.wr.VtctldServer().ApplyVSchema(ctx, &vtctldatapb.ApplyVSchemaRequest{
+			Keyspace: r.vtk.Spec.Name,
+			VSchema:  want,
+			DryRun:   true,
+		}); err != nil {
+			return fmt.Errorf("invalid VSchema: %v", err)
+		}
+		status.PendingVSchemaChanges = changes
+		if oldStatus == nil || oldStatus.VSchemaHash != hash || len(oldStatus.PendingVSchemaChanges) == 0 {
+			r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "VSchemaPendingApproval", "VSchema changes are waiting to be approved with hash %v: %v", hash, strings.Join(changes, "; "))
+		}
+		return nil
+	}
+
+	if _, err := r.wr.VtctldServer().ApplyVSchema(ctx, &vtctldatapb.ApplyVSchemaRequest{
+		Keyspace: r.vtk.Spec.Name,
+		VSchema:  want,
+	}); err != nil {
+		r.recorder.Eventf(r.vtk, corev1.EventTypeWarning, "VSchemaApplyFailed", "failed to apply VSchema: %v", err)
+		status.PendingVSchemaChanges = changes
+		return err
+	}
+	r.recorder.Eventf(r.vtk, corev1.EventTypeNormal, "VSchemaApplied", "Applied VSchema: %v", strings.Join(changes, "; "))
+	return nil
+}
+
+// addManagedVSchemaTables adds the sequences and reference tables in the spec
+// to a VSchema, unless it already declares them some other way.
+func addManagedVSchemaTables(vschema *vschemapb.Keyspace, spec *planetscalev2.VitessKeyspaceSchema) {
+	if len(spec.Sequences) == 0 && len(spec.ReferenceTables) == 0 {
+		return
+	}
+	if vschema.Tables == nil {
+		vschema.Tables = make(map[string]*vschemapb.Table)
+	}
+	for i := range spec.Sequences {
+		name := spec.Sequences[i].Name
+		if vschema.Tables[name] == nil {
+			vschema.Tables[name] = &vschemapb.Table{Type: "sequence"}
+		}
+	}
+	for i := range spec.ReferenceTables {
+		table := &spec.ReferenceTables[i]
+		if vschema.Tables[table.Name] == nil {
+			vschema.Tables[table.Name] = &vschemapb.Table{
+				Type:   "reference",
+				Source: table.SourceKeyspace + "." + table.Name,
+			}
+		}
+	}
+}
+
+// vschemaHash identifies a VSchema regardless of how its JSON was formatted.
+func vschemaHash(vschema *vschemapb.Keyspace) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(vschema)
+	if err != nil {
+		return "", err
+	}
+	return contenthash.StringList([]string{string(data)}), nil
+}
+
+// vschemaChanges describes how the wanted VSchema differs from the current
+// one, one change per vindex or table.
+func vschemaChanges(current, want *vschemapb.Keyspace) []string {
+	if proto.Equal(current, want) {
+		return nil
+	}
+
+	var changes []string
+	if current.Sharded != want.Sharded {
+		changes = append(changes, fmt.Sprintf("sharded changed from %v to %v", current.Sharded, want.Sharded))
+	}
+	if current.RequireExplicitRouting != want.RequireExplicitRouting {
+		changes = append(changes, fmt.Sprintf("require_explicit_routing changed from %v to %v", current.RequireExplicitRouting, want.RequireExplicitRouting))
+	}
+	if current.ForeignKeyMode != want.ForeignKeyMode {
+		changes = append(changes, fmt.Sprintf("foreign_key_mode changed from %v to %v", current.ForeignKeyMode, want.ForeignKeyMode))
+	}
+	changes = append(changes, vschemaMapChanges("vindex", current.Vindexes, want.Vindexes)...)
+	changes = append(changes, vschemaMapChanges("table", current.Tables, want.Tables)...)
+
+	if len(changes) == 0 {
+		changes = append(changes, "other settings changed")
+	}
+	return changes
+}
+
+func vschemaMapChanges[T proto.Message](kind string, current, want map[string]T) []string {
+	var changes []string
+	for name, wantValue := range want {
+		currentValue, ok := current[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s %s added", kind, name))
+		case !proto.Equal(currentValue, wantValue):
+			changes = append(changes, fmt.Sprintf("%s %s changed", kind, name))
+		}
+	}
+	for name := range current {
+		if _, ok := want[name]; !ok {
+			changes = append(changes, fmt.Sprintf("%s %s removed", kind, name))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
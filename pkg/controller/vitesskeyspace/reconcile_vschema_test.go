@@ -0,0 +1,95 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/json2"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestVSchemaChanges(t *testing.T) {
+	parse := func(vschemaJSON string) *vschemapb.Keyspace {
+		vschema := &vschemapb.Keyspace{}
+		if err := json2.Unmarshal([]byte(vschemaJSON), vschema); err != nil {
+			t.Fatal(err)
+		}
+		return vschema
+	}
+
+	current := parse(`{
+		"sharded": true,
+		"vindexes": {"hash": {"type": "hash"}},
+		"tables": {
+			"customer": {"column_vindexes": [{"column": "customer_id", "name": "hash"}]},
+			"orders": {"column_vindexes": [{"column": "customer_id", "name": "hash"}]}
+		}
+	}`)
+	want := parse(`{"sharded":true,"vindexes":{"hash":{"type":"hash"}},"tables":{"customer":{"column_vindexes":[{"column":"customer_id","name":"hash"}]},"orders":{"column_vindexes":[{"column":"customer_id","name":"hash"}]}}}`)
+
+	// Formatting doesn't matter.
+	assert.Empty(t, vschemaChanges(current, want))
+	currentHash, err := vschemaHash(current)
+	assert.NoError(t, err)
+	wantHash, err := vschemaHash(want)
+	assert.NoError(t, err)
+	assert.Equal(t, currentHash, wantHash)
+
+	want = parse(`{
+		"sharded": true,
+		"vindexes": {"hash": {"type": "hash"}, "email_lookup": {"type": "consistent_lookup_unique"}},
+		"tables": {
+			"customer": {"column_vindexes": [{"column": "customer_id", "name": "hash"}, {"column": "email", "name": "email_lookup"}]}
+		}
+	}`)
+	assert.Equal(t, []string{
+		"vindex email_lookup added",
+		"table customer changed",
+		"table orders removed",
+	}, vschemaChanges(current, want))
+	wantHash, err = vschemaHash(want)
+	assert.NoError(t, err)
+	assert.NotEqual(t, currentHash, wantHash)
+
+	assert.Equal(t, []string{"sharded changed from false to true"}, vschemaChanges(&vschemapb.Keyspace{}, parse(`{"sharded": true}`)))
+}
+
+func TestAddManagedVSchemaTables(t *testing.T) {
+	spec := &planetscalev2.VitessKeyspaceSchema{
+		Sequences: []planetscalev2.VitessSequence{{Name: "customer_seq"}},
+		ReferenceTables: []planetscalev2.VitessReferenceTable{
+			{Name: "countries", SourceKeyspace: "lookup"},
+			{Name: "currencies", SourceKeyspace: "lookup"},
+		},
+	}
+	vschema := &vschemapb.Keyspace{
+		Tables: map[string]*vschemapb.Table{
+			// Tables that the VSchema declares itself are left alone.
+			"currencies": {Type: "reference", Source: "commerce.currencies"},
+		},
+	}
+
+	addManagedVSchemaTables(vschema, spec)
+	assert.Equal(t, "sequence", vschema.Tables["customer_seq"].Type)
+	assert.Equal(t, "reference", vschema.Tables["countries"].Type)
+	assert.Equal(t, "lookup.countries", vschema.Tables["countries"].Source)
+	assert.Equal(t, "commerce.currencies", vschema.Tables["currencies"].Source)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/pdb"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
+)
+
+// reconcileVtorc deploys a single vtorc for the whole keyspace, if the
+// vtorc scope is Keyspace. Otherwise, each VitessShard deploys its own.
+func (r *reconcileHandler) reconcileVtorc(ctx context.Context) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	clusterName := r.vtk.Labels[planetscalev2.ClusterLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtorcComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  r.vtk.Spec.Name,
+	}
+
+	spec := r.vtorcSpec(labels)
+	key := client.ObjectKey{Namespace: r.vtk.Namespace, Name: vtorc.KeyspaceDeploymentName(clusterName, r.vtk.Spec.Name)}
+
+	// Passing no keys deletes the Deployment if it's no longer wanted.
+	var keys []client.ObjectKey
+	if spec != nil {
+		keys = []client.ObjectKey{key}
+		r.vtk.Status.VitessOrchestrator = &planetscalev2.VitessOrchestratorStatus{}
+	}
+
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := r.vtk.Spec.UpdateStrategy.Paused || rollout.Paused(r.vtk)
+
+	err := r.reconciler.ReconcileObjectSet(ctx, r.vtk, keys, labels, reconciler.Strategy{
+		Kind:          &appsv1.Deployment{},
+		RolloutPaused: paused,
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vtorc.NewDeployment(key, spec)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*appsv1.Deployment)
+			if *r.vtk.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType && !paused {
+				vtorc.UpdateDeployment(newObj, spec)
+				return
+			}
+			vtorc.UpdateDeploymentImmediate(newObj, spec)
+		},
+		UpdateRollingInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			newObj := obj.(*appsv1.Deployment)
+			vtorc.UpdateDeployment(newObj, spec)
+		},
+		Status: func(key client.ObjectKey, obj runtime.Object) {
+			curObj := obj.(*appsv1.Deployment)
+			if available := conditions.Deployment(curObj.Status.Conditions, appsv1.DeploymentAvailable); available != nil {
+				r.vtk.Status.VitessOrchestrator.Available = available.Status
+			}
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Reconcile a vtorc PDB for the Deployment.
+	var pdbConfig *planetscalev2.VitessPodDisruptionBudget
+	if spec != nil {
+		pdbConfig = r.vtk.Spec.VitessOrchestrator.PodDisruptionBudget
+	}
+	pdbKeys := keys
+	if !pdb.Wanted(pdbConfig) {
+		pdbKeys = nil
+	}
+	pdbSpec := func() *pdb.Spec {
+		return &pdb.Spec{
+			Labels:   labels,
+			Selector: &metav1.LabelSelector{MatchLabels: spec.Labels},
+			Replicas: spec.Replicas,
+			Config:   pdbConfig,
+		}
+	}
+	err = r.reconciler.ReconcileObjectSet(ctx, r.vtk, pdbKeys, labels, reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return pdb.NewPodDisruptionBudget(key, pdbSpec())
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			pdb.UpdatePodDisruptionBudget(obj.(*policyv1.PodDisruptionBudget), pdbSpec())
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// vtorcSpec returns the spec for the keyspace-wide vtorc Deployment, or nil
+// if the keyspace doesn't want one.
+func (r *reconcileHandler) vtorcSpec(labels map[string]string) *vtorc.Spec {
+	vtorcSpec := r.vtk.Spec.VitessOrchestrator
+	if vtorcSpec == nil || vtorcSpec.Scope != planetscalev2.VitessOrchestratorScopeKeyspace {
+		return nil
+	}
+	if len(r.vtk.Spec.CellNames()) == 0 {
+		// There are no tablets to watch.
+		return nil
+	}
+
+	// Merge recovery flags, ExtraVitessFlags and ExtraFlags into a new map.
+	extraFlags := vtorc.RecoveryFlags(vtorcSpec.Recovery, r.vtk.Spec.ReparentSettings)
+	update.StringMap(&extraFlags, r.vtk.Spec.ExtraVitessFlags)
+	update.StringMap(&extraFlags, vtorcSpec.ExtraFlags)
+
+	return &vtorc.Spec{
+		GlobalLockserver:  r.vtk.Spec.GlobalLockserver,
+		Image:             r.vtk.Spec.Images.Vtorc,
+		ImagePullPolicy:   r.vtk.Spec.ImagePullPolicies.Vtorc,
+		ImagePullSecrets:  r.vtk.Spec.ImagePullSecrets,
+		Keyspace:          r.vtk.Spec.Name,
+		Labels:            labels,
+		Replicas:          *vtorcSpec.Replicas,
+		Resources:         vtorcSpec.Resources,
+		Affinity:          vtorcSpec.Affinity,
+		ExtraFlags:        extraFlags,
+		ExtraEnv:          vtorcSpec.ExtraEnv,
+		ExtraVolumes:      vtorcSpec.ExtraVolumes,
+		ExtraVolumeMounts: vtorcSpec.ExtraVolumeMounts,
+		InitContainers:    vtorcSpec.InitContainers,
+		SidecarContainers: vtorcSpec.SidecarContainers,
+		Annotations:       vtorcSpec.Annotations,
+		ExtraLabels:       vtorcSpec.ExtraLabels,
+		Tolerations:       vtorcSpec.Tolerations,
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"context"
+
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// shardsHeldBack returns the key ranges of the shards that must not start
+// rolling out yet because of the keyspace's updateOrder, along with the key
+// range of the shard they're waiting for.
+func (r *reconcileHandler) shardsHeldBack(ctx context.Context, labels map[string]string) (map[string]bool, string, error) {
+	if len(r.vtk.Spec.UpdateOrder) == 0 {
+		return nil, "", nil
+	}
+
+	shardList := &planetscalev2.VitessShardList{}
+	listOpts := &client.ListOptions{
+		Namespace:     r.vtk.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(labels),
+	}
+	if err := r.client.List(ctx, shardList, listOpts); err != nil {
+		return nil, "", err
+	}
+	shards := make(map[string]*planetscalev2.VitessShard, len(shardList.Items))
+	for i := range shardList.Items {
+		shard := &shardList.Items[i]
+		shards[shard.Spec.KeyRange.String()] = shard
+	}
+
+	held, waitingFor := shardUpdateOrder(r.vtk.Spec.UpdateOrder, shards)
+	return held, waitingFor, nil
+}
+
+// shardUpdateOrder finds the first shard in the update order that's still
+// rolling out, and returns the key ranges of all the shards that must wait for
+// it: the shards after it in the order, and any shards that aren't listed.
+func shardUpdateOrder(order []string, shards map[string]*planetscalev2.VitessShard) (held map[string]bool, waitingFor string) {
+	listed := make(map[string]bool, len(order))
+	for _, keyRange := range order {
+		listed[keyRange] = true
+	}
+
+	for i, keyRange := range order {
+		shard, ok := shards[keyRange]
+		if !ok || !shardUpdatePending(shard) {
+			continue
+		}
+		held = map[string]bool{}
+		for _, later := range order[i+1:] {
+			if later != keyRange {
+				held[later] = true
+			}
+		}
+		for other := range shards {
+			if !listed[other] {
+				held[other] = true
+			}
+		}
+		return held, keyRange
+	}
+	return nil, ""
+}
+
+// shardUpdatePending returns whether a shard has changes that haven't
+// finished rolling out to its tablets.
+func shardUpdatePending(vts *planetscalev2.VitessShard) bool {
+	if rollout.Cascading(vts) {
+		return true
+	}
+	// Until every tablet Pod has seen the latest shard spec, we don't know
+	// whether it has pending changes.
+	if len(vts.Status.Tablets) > 0 && vts.Status.LowestPodGeneration != vts.Generation {
+		return true
+	}
+	for _, tablet := range vts.Status.Tablets {
+		if tablet.PendingChanges != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesskeyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestShardUpdateOrder(t *testing.T) {
+	newShard := func(pendingChanges string) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Generation = 2
+		vts.Status.LowestPodGeneration = 2
+		vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+			"zone1-1": {PendingChanges: pendingChanges},
+		}
+		return vts
+	}
+	cascading := newShard("")
+	rollout.Cascade(cascading)
+	unobserved := newShard("")
+	unobserved.Status.LowestPodGeneration = 1
+
+	tests := []struct {
+		name           string
+		order          []string
+		shards         map[string]*planetscalev2.VitessShard
+		wantHeld       map[string]bool
+		wantWaitingFor string
+	}{
+		{
+			name:   "nothing pending",
+			order:  []string{"-80", "80-"},
+			shards: map[string]*planetscalev2.VitessShard{"-80": newShard(""), "80-": newShard("")},
+		},
+		{
+			name:           "first shard pending",
+			order:          []string{"-80", "80-"},
+			shards:         map[string]*planetscalev2.VitessShard{"-80": newShard("image"), "80-": newShard("image")},
+			wantHeld:       map[string]bool{"80-": true},
+			wantWaitingFor: "-80",
+		},
+		{
+			name:           "first shard done, second rolling",
+			order:          []string{"-80", "80-"},
+			shards:         map[string]*planetscalev2.VitessShard{"-80": newShard(""), "80-": cascading},
+			wantHeld:       map[string]bool{},
+			wantWaitingFor: "80-",
+		},
+		{
+			name:           "canary shard holds back unlisted shards",
+			order:          []string{"-40"},
+			shards:         map[string]*planetscalev2.VitessShard{"-40": unobserved, "40-80": newShard("image"), "80-": newShard("image")},
+			wantHeld:       map[string]bool{"40-80": true, "80-": true},
+			wantWaitingFor: "-40",
+		},
+		{
+			name:   "unknown shard in order",
+			order:  []string{"-20"},
+			shards: map[string]*planetscalev2.VitessShard{"-80": newShard("image")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			held, waitingFor := shardUpdateOrder(tt.order, tt.shards)
+			assert.Equal(t, tt.wantHeld, held)
+			assert.Equal(t, tt.wantWaitingFor, waitingFor)
+		})
+	}
+}
@@ -23,6 +23,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
@@ -52,9 +54,11 @@ var (
 
 	// keyspaceConditions lists all the conditions that the keyspace controller is responsible for updating.
 	keyspaceConditions = map[planetscalev2.VitessKeyspaceConditionType]bool{
-		planetscalev2.VitessKeyspaceReshardingActive: true,
-		planetscalev2.VitessKeyspaceReshardingInSync: true,
-		planetscalev2.VitessKeyspaceReady:            true,
+		planetscalev2.VitessKeyspaceReshardingActive:      true,
+		planetscalev2.VitessKeyspaceReshardingInSync:      true,
+		planetscalev2.VitessKeyspaceReady:                 true,
+		planetscalev2.VitessKeyspaceDecommissioning:       true,
+		planetscalev2.VitessKeyspaceDurabilitySatisfiable: true,
 	}
 )
 
@@ -63,6 +67,9 @@ var log = logrus.WithField("controller", "VitessKeyspace")
 // watchResources should contain all the resource types that this controller creates.
 var watchResources = []client.Object{
 	&planetscalev2.VitessShard{},
+	&planetscalev2.VitessReshard{},
+	&appsv1.Deployment{},
+	&policyv1.PodDisruptionBudget{},
 }
 
 // Add creates a new VitessKeyspace Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -174,11 +181,29 @@ func (r *ReconcileVitessKeyspace) Reconcile(cctx context.Context, request reconc
 	keyspaceInfoRes, err := handler.reconcileKeyspaceInformation(ctx)
 	resultBuilder.Merge(keyspaceInfoRes, err)
 
+	// Apply the throttler configuration, if any.
+	throttlerResult, err := handler.reconcileThrottler(ctx)
+	resultBuilder.Merge(throttlerResult, err)
+
+	// Apply the VSchema and schema migrations, if any.
+	schemaResult, err := handler.reconcileSchema(ctx)
+	resultBuilder.Merge(schemaResult, err)
+
+	// Take the keyspace through the steps of decommissioning, if requested.
+	// NOTE: This must always be done before reconcileShards, so the final
+	// backup can be requested from the shards.
+	decommissionResult, err := handler.reconcileDecommission(ctx)
+	resultBuilder.Merge(decommissionResult, err)
+
 	// Create/update desired VitessShards.
 	if err := handler.reconcileShards(ctx); err != nil {
 		resultBuilder.Error(err)
 	}
 
+	// Deploy a keyspace-wide vtorc, if requested.
+	vtorcResult, err := handler.reconcileVtorc(ctx)
+	resultBuilder.Merge(vtorcResult, err)
+
 	// Check latest Vitess topology state and update as needed.
 	// NOTE: This must always be done after reconcileShards, so Status.Shards is populated.
 	topoResult, err := handler.reconcileTopology(ctx)
@@ -188,6 +213,15 @@ func (r *ReconcileVitessKeyspace) Reconcile(cctx context.Context, request reconc
 	reshardingResult, err := handler.reconcileResharding(ctx)
 	resultBuilder.Merge(reshardingResult, err)
 
+	// Keep the declared Materialize workflows around.
+	materializationsResult, err := handler.reconcileMaterializations(ctx)
+	resultBuilder.Merge(materializationsResult, err)
+
+	// Split shards that outgrew the autoReshard policy, if any.
+	// NOTE: This must always be done after reconcileResharding, so Status.Resharding is populated.
+	autoReshardResult, err := handler.reconcileAutoReshard(ctx)
+	resultBuilder.Merge(autoReshardResult, err)
+
 	// Request a periodic resync for the keyspace so we can recheck topology
 	// even if no Kubernetes events have occurred.
 	r.resync.Enqueue(request.NamespacedName)
@@ -0,0 +1,497 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessmovetables
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/workflow"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	// register grpc tabletmanager client
+	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+)
+
+const (
+	// workflowRequeueDelay is how long to wait before checking on a workflow
+	// that's waiting for something, or trying a failed step again.
+	workflowRequeueDelay = 10 * time.Second
+	// switchTrafficTimeout is how long switching traffic may take before
+	// it's cancelled and reverted.
+	switchTrafficTimeout = 30 * time.Second
+)
+
+// allTabletTypes are the tablet types that traffic is switched for.
+var allTabletTypes = []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY}
+
+// reconcileWorkflow takes the MoveTables workflow of a VitessMoveTables one
+// step further through its phases, and records where it's at in the status.
+func (r *ReconcileVitessMoveTables) reconcileWorkflow(ctx context.Context, vtmt *planetscalev2.VitessMoveTables) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if moveTablesFinished(vtmt) {
+		return resultBuilder.Result()
+	}
+	if vtmt.Spec.Reverse && status.Phase != planetscalev2.MoveTablesRollingBackPhase {
+		if status.Phase == "" {
+			r.setPhase(vtmt, planetscalev2.MoveTablesRolledBackPhase, "The workflow was rolled back before it started.")
+			return resultBuilder.Result()
+		}
+		r.setPhase(vtmt, planetscalev2.MoveTablesRollingBackPhase, "")
+		r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "RollbackStarted", "Rolling back MoveTables workflow %v.", vtmt.Spec.Workflow)
+	}
+	if status.Phase == "" {
+		if err := validateSpec(&vtmt.Spec); err != nil {
+			r.fail(vtmt, "InvalidSpec", err.Error())
+			return resultBuilder.Result()
+		}
+		r.setPhase(vtmt, planetscalev2.MoveTablesCopyingPhase, "")
+		status.CopyProgress = -1
+		r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "MoveTablesStarted", "Moving tables from keyspace %v to %v.", vtmt.Spec.SourceKeyspace, vtmt.Spec.TargetKeyspace)
+	}
+
+	// Both keyspaces must be deployed, and we need the target keyspace to
+	// find the lockserver.
+	var vtk *planetscalev2.VitessKeyspace
+	for _, keyspaceName := range []string{vtmt.Spec.SourceKeyspace, vtmt.Spec.TargetKeyspace} {
+		vtk = &planetscalev2.VitessKeyspace{}
+		vtkKey := client.ObjectKey{Namespace: vtmt.Namespace, Name: vitesskeyspace.Name(vtmt.Spec.Cluster, keyspaceName)}
+		if err := r.client.Get(ctx, vtkKey, vtk); err != nil {
+			if apierrors.IsNotFound(err) {
+				status.Message = fmt.Sprintf("Waiting for keyspace %v to exist in cluster %v.", keyspaceName, vtmt.Spec.Cluster)
+				return resultBuilder.RequeueAfter(workflowRequeueDelay)
+			}
+			return resultBuilder.Error(err)
+		}
+	}
+
+	// Get a connection to Vitess topology for this cluster.
+	ts, err := toposerver.Open(ctx, vtk.Spec.GlobalLockserver)
+	if err != nil {
+		r.recorder.Eventf(vtmt, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+	defer ts.Close()
+
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+
+	collationEnv, parser, err := environment.CollationEnvAndParser()
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts.Server, tmc, collationEnv, parser)
+
+	switch status.Phase {
+	case planetscalev2.MoveTablesCopyingPhase:
+		return r.reconcileCopy(ctx, vtmt, wr)
+	case planetscalev2.MoveTablesVerifyingPhase:
+		return r.reconcileVDiff(ctx, vtmt, wr)
+	case planetscalev2.MoveTablesSwitchingTrafficPhase:
+		return r.reconcileSwitchTraffic(ctx, vtmt, wr)
+	case planetscalev2.MoveTablesCompletingPhase:
+		return r.reconcileComplete(ctx, vtmt, wr)
+	case planetscalev2.MoveTablesRollingBackPhase:
+		return r.reconcileRollback(ctx, vtmt, wr)
+	}
+	return resultBuilder.Result()
+}
+
+// reconcileCopy creates the VReplication workflow, and then follows it while
+// it copies the tables to the target keyspace, until it has caught up with
+// the source keyspace.
+func (r *ReconcileVitessMoveTables) reconcileCopy(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if status.StartTime == nil {
+		tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtmt.Spec.TabletTypes, ","))
+		if err != nil {
+			r.fail(vtmt, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+			return resultBuilder.Result()
+		}
+
+		// Don't create the workflow again if an earlier pass already did, but
+		// failed to record it.
+		exists, err := vreplication.WorkflowExists(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow)
+		if err != nil {
+			return r.retry(vtmt, "GetWorkflowsFailed", fmt.Sprintf("Failed to list workflows: %v", err))
+		}
+		if !exists {
+			_, err := wr.VtctldServer().MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
+				Workflow:                  vtmt.Spec.Workflow,
+				SourceKeyspace:            vtmt.Spec.SourceKeyspace,
+				TargetKeyspace:            vtmt.Spec.TargetKeyspace,
+				TabletTypes:               tabletTypes,
+				TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+				AllTables:                 vtmt.Spec.AllTables,
+				IncludeTables:             vtmt.Spec.Tables,
+				ExcludeTables:             vtmt.Spec.ExcludeTables,
+				AutoStart:                 true,
+			})
+			if err != nil {
+				return r.retry(vtmt, "CreateWorkflowFailed", fmt.Sprintf("Failed to create the MoveTables workflow: %v", err))
+			}
+			r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "WorkflowCreated", "Created MoveTables workflow %v.", vtmt.Spec.Workflow)
+		}
+		status.StartTime = &metav1.Time{Time: time.Now()}
+	}
+
+	resp, err := wr.VtctldServer().WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: vtmt.Spec.TargetKeyspace,
+		Workflow: vtmt.Spec.Workflow,
+	})
+	if err != nil {
+		return r.retry(vtmt, "WorkflowStatusFailed", fmt.Sprintf("Failed to get the workflow status: %v", err))
+	}
+	status.Retries = 0
+
+	progress := vreplication.WorkflowCopyProgress(resp)
+	status.CopyProgress = progress.Percent
+	switch {
+	case len(progress.Errors) > 0:
+		// VReplication retries on its own, so we keep waiting.
+		status.Message = fmt.Sprintf("VReplication reported an error: %v", progress.Errors[0])
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesInSync, corev1.ConditionFalse, "Error", status.Message)
+		r.recorder.Event(vtmt, corev1.EventTypeWarning, "VReplicationError", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case progress.Copying:
+		status.Message = "Copying existing rows to the target keyspace."
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesInSync, corev1.ConditionFalse, "Copying", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case !progress.Running:
+		status.Message = "Waiting for VReplication to start in the target keyspace."
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesInSync, corev1.ConditionFalse, "NotRunning", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessMoveTablesInSync, corev1.ConditionTrue, "Running", "The target keyspace copied all existing rows and is replicating changes from the source keyspace.")
+	if vtmt.Spec.SkipVDiff {
+		r.setPhase(vtmt, planetscalev2.MoveTablesSwitchingTrafficPhase, "")
+	} else {
+		r.setPhase(vtmt, planetscalev2.MoveTablesVerifyingPhase, "")
+	}
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileVDiff starts a VDiff to compare the target keyspace with the
+// source keyspace, and waits for it to pass.
+func (r *ReconcileVitessMoveTables) reconcileVDiff(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if status.VDiffUUID == "" {
+		tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtmt.Spec.TabletTypes, ","))
+		if err != nil {
+			r.fail(vtmt, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+			return resultBuilder.Result()
+		}
+		vdiffUUID, err := vreplication.CreateVDiff(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow, tabletTypes)
+		if err != nil {
+			return r.retry(vtmt, "VDiffCreateFailed", fmt.Sprintf("Failed to start VDiff: %v", err))
+		}
+		status.VDiffUUID = vdiffUUID
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: vdiffUUID, StartTime: &metav1.Time{Time: time.Now()}}
+		r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "VDiffStarted", "Started VDiff %v.", vdiffUUID)
+	}
+
+	outcome, err := vreplication.ShowVDiff(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow, status.VDiffUUID)
+	if err != nil {
+		return r.retry(vtmt, "VDiffShowFailed", fmt.Sprintf("Failed to get VDiff results: %v", err))
+	}
+	status.Retries = 0
+	if status.LastVDiff == nil || status.LastVDiff.UUID != status.VDiffUUID {
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: status.VDiffUUID}
+	}
+	vreplication.UpdateVDiffReport(status.LastVDiff, outcome, time.Now())
+
+	switch {
+	case outcome.Mismatch:
+		r.failVDiff(vtmt)
+		return resultBuilder.Result()
+	case outcome.LastError != "":
+		// VDiff retries on its own, so we keep waiting.
+		status.Message = fmt.Sprintf("VDiff reported an error: %v", outcome.LastError)
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesVDiffPassed, corev1.ConditionUnknown, "Error", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case !outcome.Completed:
+		status.Message = fmt.Sprintf("Waiting for VDiff %v to finish comparing %d tables.", status.VDiffUUID, outcome.Tables)
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesVDiffPassed, corev1.ConditionUnknown, "Running", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessMoveTablesVDiffPassed, corev1.ConditionTrue, "Match", fmt.Sprintf("VDiff %v found the target keyspace to match the source keyspace.", status.VDiffUUID))
+	r.setPhase(vtmt, planetscalev2.MoveTablesSwitchingTrafficPhase, "")
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileSwitchTraffic switches reads and then writes to the target
+// keyspace, once the spec allows it. Reverse replication is set up, so
+// traffic can be switched back until the workflow is completed.
+func (r *ReconcileVitessMoveTables) reconcileSwitchTraffic(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	wait, result, err := r.reconcileScheduledVDiff(ctx, vtmt, wr)
+	resultBuilder.Merge(result, err)
+	if wait {
+		return resultBuilder.Result()
+	}
+
+	if message := vreplication.SwitchTrafficWaitReason(vtmt.Spec.SwitchTraffic, vtmt.Spec.ApprovalRequired, vtmt.Spec.Approved, vtmt.Annotations); message != "" {
+		// Let the user know once that it's time to approve.
+		if cond, _ := status.GetCondition(planetscalev2.VitessMoveTablesTrafficSwitched); cond.Reason != "WaitingForApproval" {
+			r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "ReadyForCutover", "Keyspace %v is ready to take over traffic for the tables. %v", vtmt.Spec.TargetKeyspace, message)
+		}
+		status.Message = message
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesTrafficSwitched, corev1.ConditionFalse, "WaitingForApproval", status.Message)
+		return resultBuilder.Result()
+	}
+
+	_, err = wr.VtctldServer().WorkflowSwitchTraffic(ctx, &vtctldatapb.WorkflowSwitchTrafficRequest{
+		Keyspace:                 vtmt.Spec.TargetKeyspace,
+		Workflow:                 vtmt.Spec.Workflow,
+		TabletTypes:              allTabletTypes,
+		MaxReplicationLagAllowed: protoutil.DurationToProto(vtmt.Spec.MaxReplicationLag.Duration),
+		EnableReverseReplication: true,
+		Timeout:                  protoutil.DurationToProto(switchTrafficTimeout),
+	})
+	if err != nil {
+		message := fmt.Sprintf("Failed to switch traffic: %v", err)
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesTrafficSwitched, corev1.ConditionFalse, "SwitchFailed", message)
+		return r.retry(vtmt, "SwitchTrafficFailed", message)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessMoveTablesTrafficSwitched, corev1.ConditionTrue, "Switched", "The target keyspace serves all traffic for the tables.")
+	r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "TrafficSwitched", "Switched traffic to keyspace %v.", vtmt.Spec.TargetKeyspace)
+	r.setPhase(vtmt, planetscalev2.MoveTablesCompletingPhase, "")
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileScheduledVDiff runs the VDiffs that spec.vdiff schedules while
+// the workflow waits to switch traffic. It returns true while one is running,
+// since traffic isn't switched until it passes.
+func (r *ReconcileVitessMoveTables) reconcileScheduledVDiff(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (bool, reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if vtmt.Spec.VDiff == nil {
+		return false, reconcile.Result{}, nil
+	}
+	now := time.Now()
+
+	if last := status.LastVDiff; last == nil || last.CompletionTime != nil {
+		// Scheduled VDiffs are due relative to the start of the last one.
+		since := status.StartTime
+		if last != nil && last.StartTime != nil {
+			since = last.StartTime
+		}
+		if since == nil {
+			since = &metav1.Time{Time: now}
+		}
+		next, err := vreplication.NextScheduledVDiff(vtmt.Spec.VDiff.Schedule, since.Time)
+		if err != nil {
+			r.recorder.Event(vtmt, corev1.EventTypeWarning, "InvalidVDiffSchedule", err.Error())
+			return false, reconcile.Result{}, nil
+		}
+		if now.Before(next) {
+			// Make sure we come back when it's time, even if nothing else changes.
+			result, err := resultBuilder.RequeueAfter(next.Sub(now))
+			return false, result, err
+		}
+
+		tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtmt.Spec.TabletTypes, ","))
+		if err != nil {
+			r.fail(vtmt, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+			return true, reconcile.Result{}, nil
+		}
+		vdiffUUID, err := vreplication.CreateVDiff(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow, tabletTypes)
+		if err != nil {
+			result, err := r.retry(vtmt, "VDiffCreateFailed", fmt.Sprintf("Failed to start scheduled VDiff: %v", err))
+			return true, result, err
+		}
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: vdiffUUID, StartTime: &metav1.Time{Time: now}}
+		r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "VDiffStarted", "Started scheduled VDiff %v.", vdiffUUID)
+	}
+
+	last := status.LastVDiff
+	outcome, err := vreplication.ShowVDiff(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow, last.UUID)
+	if err != nil {
+		result, err := r.retry(vtmt, "VDiffShowFailed", fmt.Sprintf("Failed to get VDiff results: %v", err))
+		return true, result, err
+	}
+	status.Retries = 0
+	vreplication.UpdateVDiffReport(last, outcome, now)
+
+	switch {
+	case outcome.Mismatch:
+		r.failVDiff(vtmt)
+		return true, reconcile.Result{}, nil
+	case !outcome.Completed:
+		status.Message = fmt.Sprintf("Waiting for scheduled VDiff %v to finish comparing %d tables.", last.UUID, outcome.Tables)
+		result, err := resultBuilder.RequeueAfter(workflowRequeueDelay)
+		return true, result, err
+	}
+	status.SetConditionStatus(planetscalev2.VitessMoveTablesVDiffPassed, corev1.ConditionTrue, "Match", fmt.Sprintf("VDiff %v found the target keyspace to match the source keyspace.", last.UUID))
+	return false, reconcile.Result{}, nil
+}
+
+// reconcileComplete cleans up after the workflow once traffic was switched
+// and the spec allows it. This drops the tables from the source keyspace.
+func (r *ReconcileVitessMoveTables) reconcileComplete(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if vtmt.Spec.Complete != planetscalev2.MoveTablesCompleteAuto {
+		status.Message = "Waiting for complete to be set to Auto. Set reverse to true instead to switch traffic back to the source keyspace."
+		return resultBuilder.Result()
+	}
+
+	_, err := wr.VtctldServer().MoveTablesComplete(ctx, &vtctldatapb.MoveTablesCompleteRequest{
+		Workflow:       vtmt.Spec.Workflow,
+		TargetKeyspace: vtmt.Spec.TargetKeyspace,
+	})
+	if err != nil {
+		return r.retry(vtmt, "CompleteFailed", fmt.Sprintf("Failed to complete the workflow: %v", err))
+	}
+
+	status.CompletionTime = &metav1.Time{Time: time.Now()}
+	r.setPhase(vtmt, planetscalev2.MoveTablesCompletePhase, fmt.Sprintf("The tables were moved to keyspace %v.", vtmt.Spec.TargetKeyspace))
+	r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "MoveTablesComplete", "Completed MoveTables workflow %v.", vtmt.Spec.Workflow)
+	return resultBuilder.Result()
+}
+
+// reconcileRollback switches traffic back to the source keyspace if it was
+// switched, and then cancels the workflow, which drops the copies of the
+// tables from the target keyspace.
+func (r *ReconcileVitessMoveTables) reconcileRollback(ctx context.Context, vtmt *planetscalev2.VitessMoveTables, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if cond, ok := status.GetCondition(planetscalev2.VitessMoveTablesTrafficSwitched); ok && cond.Status == corev1.ConditionTrue {
+		_, err := wr.VtctldServer().WorkflowSwitchTraffic(ctx, &vtctldatapb.WorkflowSwitchTrafficRequest{
+			Keyspace:                 vtmt.Spec.TargetKeyspace,
+			Workflow:                 vtmt.Spec.Workflow,
+			TabletTypes:              allTabletTypes,
+			MaxReplicationLagAllowed: protoutil.DurationToProto(vtmt.Spec.MaxReplicationLag.Duration),
+			EnableReverseReplication: true,
+			Direction:                int32(workflow.DirectionBackward),
+			Timeout:                  protoutil.DurationToProto(switchTrafficTimeout),
+		})
+		if err != nil {
+			return r.retry(vtmt, "ReverseTrafficFailed", fmt.Sprintf("Failed to switch traffic back: %v", err))
+		}
+		status.SetConditionStatus(planetscalev2.VitessMoveTablesTrafficSwitched, corev1.ConditionFalse, "SwitchedBack", "The source keyspace serves all traffic for the tables.")
+		r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "TrafficSwitchedBack", "Switched traffic back to keyspace %v.", vtmt.Spec.SourceKeyspace)
+	}
+
+	exists, err := vreplication.WorkflowExists(ctx, wr, vtmt.Spec.TargetKeyspace, vtmt.Spec.Workflow)
+	if err != nil {
+		return r.retry(vtmt, "GetWorkflowsFailed", fmt.Sprintf("Failed to list workflows: %v", err))
+	}
+	if exists {
+		_, err := wr.VtctldServer().WorkflowDelete(ctx, &vtctldatapb.WorkflowDeleteRequest{
+			Keyspace: vtmt.Spec.TargetKeyspace,
+			Workflow: vtmt.Spec.Workflow,
+		})
+		if err != nil {
+			return r.retry(vtmt, "CancelFailed", fmt.Sprintf("Failed to cancel the workflow: %v", err))
+		}
+	}
+
+	status.CompletionTime = &metav1.Time{Time: time.Now()}
+	r.setPhase(vtmt, planetscalev2.MoveTablesRolledBackPhase, fmt.Sprintf("The tables were left in keyspace %v.", vtmt.Spec.SourceKeyspace))
+	r.recorder.Eventf(vtmt, corev1.EventTypeNormal, "RollbackComplete", "Rolled back MoveTables workflow %v.", vtmt.Spec.Workflow)
+	return resultBuilder.Result()
+}
+
+// validateSpec checks the parts of the spec that the CRD schema can't.
+func validateSpec(spec *planetscalev2.VitessMoveTablesSpec) error {
+	if spec.SourceKeyspace == spec.TargetKeyspace {
+		return fmt.Errorf("sourceKeyspace and targetKeyspace must be different")
+	}
+	if spec.AllTables == (len(spec.Tables) > 0) {
+		return fmt.Errorf("exactly one of tables or allTables must be set")
+	}
+	if len(spec.ExcludeTables) > 0 && !spec.AllTables {
+		return fmt.Errorf("excludeTables can only be set along with allTables")
+	}
+	return nil
+}
+
+// retry records that the current step failed, and tries it again later,
+// unless it already failed more than maxRetries times in a row.
+func (r *ReconcileVitessMoveTables) retry(vtmt *planetscalev2.VitessMoveTables, reason, message string) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtmt.Status
+
+	if status.Retries >= *vtmt.Spec.MaxRetries {
+		r.fail(vtmt, reason, fmt.Sprintf("%v (gave up after %d retries)", message, status.Retries))
+		return resultBuilder.Result()
+	}
+	status.Retries++
+	status.Message = message
+	retryCount.WithLabelValues(moveTablesLabels(vtmt, string(status.Phase))...).Inc()
+	r.recorder.Event(vtmt, corev1.EventTypeWarning, reason, message)
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// setPhase moves a VitessMoveTables to the given phase.
+func (r *ReconcileVitessMoveTables) setPhase(vtmt *planetscalev2.VitessMoveTables, phase planetscalev2.VitessMoveTablesPhase, message string) {
+	vtmt.Status.Phase = phase
+	vtmt.Status.Message = message
+	vtmt.Status.Retries = 0
+}
+
+// failVDiff stops a VitessMoveTables in the Failed phase because the last
+// VDiff found differences between the source and target keyspaces.
+func (r *ReconcileVitessMoveTables) failVDiff(vtmt *planetscalev2.VitessMoveTables) {
+	last := vtmt.Status.LastVDiff
+	message := fmt.Sprintf("VDiff %v found differences between the source and target keyspaces%v.", last.UUID, vreplication.VDiffTablesSummary(last))
+	vtmt.Status.SetConditionStatus(planetscalev2.VitessMoveTablesVDiffPassed, corev1.ConditionFalse, "Mismatch", message)
+	vdiffMismatchCount.WithLabelValues(moveTablesLabels(vtmt)...).Inc()
+	r.fail(vtmt, "VDiffMismatch", message)
+}
+
+// fail stops a VitessMoveTables in the Failed phase.
+func (r *ReconcileVitessMoveTables) fail(vtmt *planetscalev2.VitessMoveTables, reason, message string) {
+	r.setPhase(vtmt, planetscalev2.MoveTablesFailedPhase, message)
+	r.recorder.Event(vtmt, corev1.EventTypeWarning, reason, message)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessmovetables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    planetscalev2.VitessMoveTablesSpec
+		wantErr bool
+	}{
+		{
+			name: "tables",
+			spec: planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "customer", Tables: []string{"customer", "corder"}},
+		},
+		{
+			name: "all tables with exclusions",
+			spec: planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "customer", AllTables: true, ExcludeTables: []string{"product"}},
+		},
+		{
+			name:    "same keyspace",
+			spec:    planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "commerce", Tables: []string{"customer"}},
+			wantErr: true,
+		},
+		{
+			name:    "no tables",
+			spec:    planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "customer"},
+			wantErr: true,
+		},
+		{
+			name:    "tables and all tables",
+			spec:    planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "customer", AllTables: true, Tables: []string{"customer"}},
+			wantErr: true,
+		},
+		{
+			name:    "exclusions without all tables",
+			spec:    planetscalev2.VitessMoveTablesSpec{SourceKeyspace: "commerce", TargetKeyspace: "customer", Tables: []string{"customer"}, ExcludeTables: []string{"product"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSpec(&tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMoveTablesFinished(t *testing.T) {
+	tests := []struct {
+		phase   planetscalev2.VitessMoveTablesPhase
+		reverse bool
+		want    bool
+	}{
+		{phase: "", want: false},
+		{phase: planetscalev2.MoveTablesCompletingPhase, want: false},
+		{phase: planetscalev2.MoveTablesCompletePhase, want: true},
+		{phase: planetscalev2.MoveTablesCompletePhase, reverse: true, want: true},
+		{phase: planetscalev2.MoveTablesRolledBackPhase, reverse: true, want: true},
+		{phase: planetscalev2.MoveTablesFailedPhase, want: true},
+		{phase: planetscalev2.MoveTablesFailedPhase, reverse: true, want: false},
+	}
+	for _, tt := range tests {
+		vtmt := &planetscalev2.VitessMoveTables{
+			Spec:   planetscalev2.VitessMoveTablesSpec{Reverse: tt.reverse},
+			Status: planetscalev2.VitessMoveTablesStatus{Phase: tt.phase},
+		}
+		assert.Equal(t, tt.want, moveTablesFinished(vtmt), "phase %q, reverse %v", tt.phase, tt.reverse)
+	}
+}
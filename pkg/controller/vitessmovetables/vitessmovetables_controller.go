@@ -0,0 +1,212 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessmovetables
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/resync"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+)
+
+const (
+	controllerName = "vitessmovetables-controller"
+)
+
+var (
+	maxConcurrentReconciles = flag.Int("vitessmovetables_concurrent_reconciles", 10, "the maximum number of different vitessmovetables to reconcile concurrently")
+	resyncPeriod            = flag.Duration("vitessmovetables_resync_period", 15*time.Second, "reconcile vitessmovetables with this period even if no Kubernetes events occur")
+)
+
+var log = logrus.WithField("controller", "VitessMoveTables")
+
+// Add creates a new VitessMoveTables Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileVitessMoveTables {
+	return &ReconcileVitessMoveTables{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		resync:   resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder: mgr.GetEventRecorderFor(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileVitessMoveTables) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource VitessMoveTables
+	err = c.Watch(source.Kind(mgr.GetCache(), &planetscalev2.VitessMoveTables{}), &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Periodically resync even when no Kubernetes events have come in.
+	if err := c.Watch(r.resync.WatchSource(), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileVitessMoveTables{}
+
+// ReconcileVitessMoveTables reconciles a VitessMoveTables object
+type ReconcileVitessMoveTables struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	resync   *resync.Periodic
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a VitessMoveTables object and makes changes based on the state read
+// and what is in the VitessMoveTables.Spec
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileVitessMoveTables) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+	defer cancel()
+
+	resultBuilder := &results.Builder{}
+
+	log := log.WithFields(logrus.Fields{
+		"namespace":        request.Namespace,
+		"vitessmovetables": request.Name,
+	})
+	log.Info("Reconciling VitessMoveTables")
+
+	// Fetch the VitessMoveTables instance
+	vtmt := &planetscalev2.VitessMoveTables{}
+	err := r.client.Get(ctx, request.NamespacedName, vtmt)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return resultBuilder.Result()
+		}
+		// Error reading the object - requeue the request.
+		return resultBuilder.Error(err)
+	}
+	// Set the owner before filling in defaults, so they aren't saved.
+	if err := r.ensureOwner(ctx, vtmt); err != nil {
+		r.recorder.Eventf(vtmt, corev1.EventTypeWarning, "SetOwnerFailed", "failed to set the target keyspace as owner: %v", err)
+		resultBuilder.Error(err)
+	}
+	planetscalev2.DefaultVitessMoveTables(vtmt)
+
+	// The workflow progresses across many passes, so the status carries over.
+	oldStatus := vtmt.Status.DeepCopy()
+
+	workflowResult, err := r.reconcileWorkflow(ctx, vtmt)
+	resultBuilder.Merge(workflowResult, err)
+
+	if vtmt.Status.Phase != oldStatus.Phase {
+		phaseTransitionCount.WithLabelValues(moveTablesLabels(vtmt, string(vtmt.Status.Phase))...).Inc()
+	}
+
+	// Update status if needed.
+	vtmt.Status.ObservedGeneration = vtmt.Generation
+	if !apiequality.Semantic.DeepEqual(&vtmt.Status, oldStatus) {
+		if err := r.client.Status().Update(ctx, vtmt); err != nil {
+			if !apierrors.IsConflict(err) {
+				r.recorder.Eventf(vtmt, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
+			}
+			resultBuilder.Error(err)
+		}
+	}
+
+	// Keep checking on the workflow until it's done.
+	if !moveTablesFinished(vtmt) {
+		r.resync.Enqueue(request.NamespacedName)
+	}
+
+	result, err := resultBuilder.Result()
+	reconcileCount.WithLabelValues(moveTablesLabels(vtmt, metrics.Result(err))...).Inc()
+	return result, err
+}
+
+// moveTablesFinished returns whether there's nothing left to do for a
+// VitessMoveTables unless its spec changes. A Failed workflow can still be
+// rolled back.
+func moveTablesFinished(vtmt *planetscalev2.VitessMoveTables) bool {
+	switch vtmt.Status.Phase {
+	case planetscalev2.MoveTablesCompletePhase, planetscalev2.MoveTablesRolledBackPhase:
+		return true
+	case planetscalev2.MoveTablesFailedPhase:
+		return !vtmt.Spec.Reverse
+	}
+	return false
+}
+
+// ensureOwner makes the VitessKeyspace of the target keyspace an owner of
+// the VitessMoveTables, so it's deleted along with the keyspace. It does
+// nothing until the VitessKeyspace exists.
+func (r *ReconcileVitessMoveTables) ensureOwner(ctx context.Context, vtmt *planetscalev2.VitessMoveTables) error {
+	vtk := &planetscalev2.VitessKeyspace{}
+	vtkKey := client.ObjectKey{Namespace: vtmt.Namespace, Name: vitesskeyspace.Name(vtmt.Spec.Cluster, vtmt.Spec.TargetKeyspace)}
+	if err := r.client.Get(ctx, vtkKey, vtk); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	for _, ref := range vtmt.OwnerReferences {
+		if ref.UID == vtk.UID {
+			return nil
+		}
+	}
+	if err := controllerutil.SetOwnerReference(vtk, vtmt, r.scheme); err != nil {
+		return err
+	}
+	return r.client.Update(ctx, vtmt)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessreshard
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+)
+
+const (
+	metricsSubsystemName = "reshard"
+
+	phaseLabel = "phase"
+)
+
+var (
+	reconcileCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "reconcile_count",
+		Help:      "Reconciliation attempts for a VitessReshard",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ResultLabel})
+
+	phaseTransitionCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "phase_transition_count",
+		Help:      "Number of times a VitessReshard entered each phase",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, phaseLabel})
+
+	vdiffMismatchCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "vdiff_mismatch_count",
+		Help:      "Number of VDiffs that found differences between the source and target shards of a VitessReshard",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileCount,
+		phaseTransitionCount,
+		vdiffMismatchCount,
+	)
+}
+
+func reshardLabels(vtr *planetscalev2.VitessReshard, extra ...string) []string {
+	return append([]string{vtr.Spec.Cluster, vtr.Spec.Keyspace}, extra...)
+}
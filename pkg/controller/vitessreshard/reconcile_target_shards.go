@@ -0,0 +1,117 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessreshard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// ensureTargetShards adds a partitioning with the target shards to the
+// keyspace in the VitessCluster, unless one is already there. The usual
+// controllers then deploy the target shards alongside the source shards.
+func (r *ReconcileVitessReshard) ensureTargetShards(ctx context.Context, vtr *planetscalev2.VitessReshard) error {
+	vt := &planetscalev2.VitessCluster{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: vtr.Namespace, Name: vtr.Spec.Cluster}, vt); err != nil {
+		return fmt.Errorf("failed to get VitessCluster %v: %v", vtr.Spec.Cluster, err)
+	}
+
+	var keyspace *planetscalev2.VitessKeyspaceTemplate
+	for i := range vt.Spec.Keyspaces {
+		if vt.Spec.Keyspaces[i].Name == vtr.Spec.Keyspace {
+			keyspace = &vt.Spec.Keyspaces[i]
+			break
+		}
+	}
+	if keyspace == nil {
+		return fmt.Errorf("keyspace %v is not in VitessCluster %v", vtr.Spec.Keyspace, vtr.Spec.Cluster)
+	}
+
+	partitioning, err := targetPartitioning(keyspace, vtr.Spec.SourceShards, vtr.Spec.TargetShards)
+	if err != nil {
+		return err
+	}
+	if partitioning == nil {
+		// The target shards are already there.
+		return nil
+	}
+	keyspace.Partitionings = append(keyspace.Partitionings, *partitioning)
+	if err := r.client.Update(ctx, vt); err != nil {
+		return fmt.Errorf("failed to add target shards to VitessCluster %v: %v", vtr.Spec.Cluster, err)
+	}
+	r.recorder.Eventf(vtr, corev1.EventTypeNormal, "TargetShardsAdded", "Added shards %v to keyspace %v.", strings.Join(vtr.Spec.TargetShards, ","), vtr.Spec.Keyspace)
+	return nil
+}
+
+// targetPartitioning returns a partitioning with the target shards, which
+// copies its shard template from the partitioning that has the first source
+// shard. It returns nil if a partitioning already has all the target shards.
+func targetPartitioning(keyspace *planetscalev2.VitessKeyspaceTemplate, sourceShards, targetShards []string) (*planetscalev2.VitessKeyspacePartitioning, error) {
+	if len(sourceShards) == 0 || len(targetShards) == 0 {
+		return nil, fmt.Errorf("source and target shards must not be empty")
+	}
+
+	var template *planetscalev2.VitessShardTemplate
+	for i := range keyspace.Partitionings {
+		partitioning := &keyspace.Partitionings[i]
+		shardNames := partitioning.ShardNameSet()
+		if shardNames.HasAll(targetShards...) {
+			return nil, nil
+		}
+		if template != nil || !shardNames.Has(sourceShards[0]) {
+			continue
+		}
+		switch {
+		case partitioning.Equal != nil:
+			template = &partitioning.Equal.ShardTemplate
+		case partitioning.Custom != nil:
+			shards := partitioning.Custom.ShardSpecs()
+			for j := range shards {
+				shard := &shards[j]
+				if shard.KeyRange.String() == sourceShards[0] {
+					template = &shard.VitessShardTemplate
+					break
+				}
+			}
+		}
+	}
+	if template == nil {
+		return nil, fmt.Errorf("source shard %v is not in keyspace %v", sourceShards[0], keyspace.Name)
+	}
+
+	custom := &planetscalev2.VitessKeyspaceCustomPartitioning{}
+	seen := sets.NewString()
+	for _, shardName := range targetShards {
+		start, end, ok := strings.Cut(shardName, "-")
+		if !ok || seen.Has(shardName) {
+			return nil, fmt.Errorf("invalid target shard %q", shardName)
+		}
+		seen.Insert(shardName)
+		custom.Shards = append(custom.Shards, planetscalev2.VitessKeyspaceKeyRangeShard{
+			KeyRange:            planetscalev2.VitessKeyRange{Start: start, End: end},
+			VitessShardTemplate: *template.DeepCopy(),
+		})
+	}
+	return &planetscalev2.VitessKeyspacePartitioning{Custom: custom}, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessreshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestTargetPartitioning(t *testing.T) {
+	template := planetscalev2.VitessShardTemplate{
+		TabletPools: []planetscalev2.VitessShardTabletPool{{Cell: "zone1", Type: "replica", Replicas: 3}},
+	}
+	keyspace := &planetscalev2.VitessKeyspaceTemplate{
+		Name: "commerce",
+		Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+			{Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{Parts: 2, ShardTemplate: template}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		sourceShards []string
+		targetShards []string
+		want         *planetscalev2.VitessKeyspacePartitioning
+		wantErr      bool
+	}{
+		{
+			name:         "split one shard",
+			sourceShards: []string{"-80"},
+			targetShards: []string{"-40", "40-80"},
+			want: &planetscalev2.VitessKeyspacePartitioning{Custom: &planetscalev2.VitessKeyspaceCustomPartitioning{
+				Shards: []planetscalev2.VitessKeyspaceKeyRangeShard{
+					{KeyRange: planetscalev2.VitessKeyRange{End: "40"}, VitessShardTemplate: template},
+					{KeyRange: planetscalev2.VitessKeyRange{Start: "40", End: "80"}, VitessShardTemplate: template},
+				},
+			}},
+		},
+		{
+			name:         "target shards already exist",
+			sourceShards: []string{"-80", "80-"},
+			targetShards: []string{"-80", "80-"},
+			want:         nil,
+		},
+		{
+			name:         "unknown source shard",
+			sourceShards: []string{"-40"},
+			targetShards: []string{"-20", "20-40"},
+			wantErr:      true,
+		},
+		{
+			name:         "invalid target shard",
+			sourceShards: []string{"-80"},
+			targetShards: []string{"0"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := targetPartitioning(keyspace, tt.sourceShards, tt.targetShards)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessreshard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/logutil"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tmclient"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	// register grpc tabletmanager client
+	_ "vitess.io/vitess/go/vt/vttablet/grpctmclient"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/toposerver"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+	"planetscale.dev/vitess-operator/pkg/operator/vreplication"
+)
+
+const (
+	// workflowRequeueDelay is how long to wait before checking on a workflow
+	// that's waiting for something.
+	workflowRequeueDelay = 10 * time.Second
+	// switchTrafficTimeout is how long switching traffic may take before
+	// it's cancelled and reverted.
+	switchTrafficTimeout = 30 * time.Second
+)
+
+// reconcileWorkflow takes the Reshard workflow of a VitessReshard one step
+// further through its phases, and records where it's at in the status.
+func (r *ReconcileVitessReshard) reconcileWorkflow(ctx context.Context, vtr *planetscalev2.VitessReshard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	if reshardFinished(vtr) {
+		return resultBuilder.Result()
+	}
+	if status.Phase == "" {
+		r.setPhase(vtr, planetscalev2.ReshardCreatingTargetShardsPhase, "")
+		status.CopyProgress = -1
+		r.recorder.Eventf(vtr, corev1.EventTypeNormal, "ReshardStarted", "Resharding keyspace %v from %v to %v.", vtr.Spec.Keyspace, strings.Join(vtr.Spec.SourceShards, ","), strings.Join(vtr.Spec.TargetShards, ","))
+	}
+
+	// We need the keyspace to find the lockserver.
+	vtk := &planetscalev2.VitessKeyspace{}
+	vtkKey := client.ObjectKey{Namespace: vtr.Namespace, Name: vitesskeyspace.Name(vtr.Spec.Cluster, vtr.Spec.Keyspace)}
+	if err := r.client.Get(ctx, vtkKey, vtk); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Message = fmt.Sprintf("Waiting for keyspace %v to exist in cluster %v.", vtr.Spec.Keyspace, vtr.Spec.Cluster)
+			return resultBuilder.RequeueAfter(workflowRequeueDelay)
+		}
+		return resultBuilder.Error(err)
+	}
+
+	// Get a connection to Vitess topology for this cluster.
+	ts, err := toposerver.Open(ctx, vtk.Spec.GlobalLockserver)
+	if err != nil {
+		r.recorder.Eventf(vtr, corev1.EventTypeWarning, "TopoConnectFailed", "failed to connect to global lockserver: %v", err)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+	defer ts.Close()
+
+	tmc := tmclient.NewTabletManagerClient()
+	defer tmc.Close()
+
+	collationEnv, parser, err := environment.CollationEnvAndParser()
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts.Server, tmc, collationEnv, parser)
+
+	switch status.Phase {
+	case planetscalev2.ReshardCreatingTargetShardsPhase:
+		return r.reconcileTargetShards(ctx, vtr, wr)
+	case planetscalev2.ReshardCopyingPhase:
+		return r.reconcileCopy(ctx, vtr, wr)
+	case planetscalev2.ReshardVerifyingPhase:
+		return r.reconcileVDiff(ctx, vtr, wr)
+	case planetscalev2.ReshardSwitchingTrafficPhase:
+		return r.reconcileSwitchTraffic(ctx, vtr, wr)
+	case planetscalev2.ReshardCompletingPhase:
+		return r.reconcileComplete(ctx, vtr, wr)
+	}
+	return resultBuilder.Result()
+}
+
+// reconcileTargetShards makes sure the target shards exist and have a
+// primary, and then creates the VReplication workflow.
+func (r *ReconcileVitessReshard) reconcileTargetShards(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	if err := r.ensureTargetShards(ctx, vtr); err != nil {
+		r.fail(vtr, "CreateTargetShardsFailed", fmt.Sprintf("Can't create the target shards: %v", err))
+		return resultBuilder.Result()
+	}
+
+	var waiting []string
+	for _, shardName := range vtr.Spec.TargetShards {
+		shard, err := wr.TopoServer().GetShard(ctx, vtr.Spec.Keyspace, shardName)
+		if err != nil || !shard.HasPrimary() {
+			waiting = append(waiting, shardName)
+		}
+	}
+	if len(waiting) > 0 {
+		status.Message = fmt.Sprintf("Waiting for target shards to have a primary: %v", strings.Join(waiting, ", "))
+		status.SetConditionStatus(planetscalev2.VitessReshardTargetShardsReady, corev1.ConditionFalse, "WaitingForPrimary", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+	status.SetConditionStatus(planetscalev2.VitessReshardTargetShardsReady, corev1.ConditionTrue, "PrimariesElected", "Every target shard has a primary.")
+
+	tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtr.Spec.TabletTypes, ","))
+	if err != nil {
+		r.fail(vtr, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+		return resultBuilder.Result()
+	}
+
+	// Don't create the workflow again if an earlier pass already did, but
+	// failed to record it.
+	exists, err := vreplication.WorkflowExists(ctx, wr, vtr.Spec.Keyspace, vtr.Spec.Workflow)
+	if err != nil {
+		r.recorder.Eventf(vtr, corev1.EventTypeWarning, "GetWorkflowsFailed", "failed to list workflows: %v", err)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+	if !exists {
+		_, err := wr.VtctldServer().ReshardCreate(ctx, &vtctldatapb.ReshardCreateRequest{
+			Workflow:                  vtr.Spec.Workflow,
+			Keyspace:                  vtr.Spec.Keyspace,
+			SourceShards:              vtr.Spec.SourceShards,
+			TargetShards:              vtr.Spec.TargetShards,
+			TabletTypes:               tabletTypes,
+			TabletSelectionPreference: tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+			AutoStart:                 true,
+		})
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to create the Reshard workflow: %v", err)
+			r.recorder.Event(vtr, corev1.EventTypeWarning, "CreateWorkflowFailed", status.Message)
+			return resultBuilder.RequeueAfter(workflowRequeueDelay)
+		}
+		r.recorder.Eventf(vtr, corev1.EventTypeNormal, "WorkflowCreated", "Created Reshard workflow %v.", vtr.Spec.Workflow)
+	}
+	status.StartTime = &metav1.Time{Time: time.Now()}
+	r.setPhase(vtr, planetscalev2.ReshardCopyingPhase, "")
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileCopy follows the workflow while it copies data to the target
+// shards, until they've caught up with the source shards.
+func (r *ReconcileVitessReshard) reconcileCopy(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	resp, err := wr.VtctldServer().WorkflowStatus(ctx, &vtctldatapb.WorkflowStatusRequest{
+		Keyspace: vtr.Spec.Keyspace,
+		Workflow: vtr.Spec.Workflow,
+	})
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to get the workflow status: %v", err)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "WorkflowStatusFailed", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	progress := vreplication.WorkflowCopyProgress(resp)
+	status.CopyProgress = progress.Percent
+	switch {
+	case len(progress.Errors) > 0:
+		// VReplication retries on its own, so we keep waiting.
+		status.Message = fmt.Sprintf("VReplication reported an error: %v", progress.Errors[0])
+		status.SetConditionStatus(planetscalev2.VitessReshardInSync, corev1.ConditionFalse, "Error", status.Message)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "VReplicationError", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case progress.Copying:
+		status.Message = "Copying existing data to the target shards."
+		status.SetConditionStatus(planetscalev2.VitessReshardInSync, corev1.ConditionFalse, "Copying", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case !progress.Running:
+		status.Message = "Waiting for VReplication to start on the target shards."
+		status.SetConditionStatus(planetscalev2.VitessReshardInSync, corev1.ConditionFalse, "NotRunning", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessReshardInSync, corev1.ConditionTrue, "Running", "The target shards copied all existing data and are replicating changes from the source shards.")
+	if vtr.Spec.SkipVDiff {
+		r.setPhase(vtr, planetscalev2.ReshardSwitchingTrafficPhase, "")
+	} else {
+		r.setPhase(vtr, planetscalev2.ReshardVerifyingPhase, "")
+	}
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileVDiff starts a VDiff to compare the target shards with the source
+// shards, and waits for it to pass.
+func (r *ReconcileVitessReshard) reconcileVDiff(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	if status.VDiffUUID == "" {
+		tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtr.Spec.TabletTypes, ","))
+		if err != nil {
+			r.fail(vtr, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+			return resultBuilder.Result()
+		}
+		vdiffUUID, err := vreplication.CreateVDiff(ctx, wr, vtr.Spec.Keyspace, vtr.Spec.Workflow, tabletTypes)
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to start VDiff: %v", err)
+			r.recorder.Event(vtr, corev1.EventTypeWarning, "VDiffCreateFailed", status.Message)
+			return resultBuilder.RequeueAfter(workflowRequeueDelay)
+		}
+		status.VDiffUUID = vdiffUUID
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: vdiffUUID, StartTime: &metav1.Time{Time: time.Now()}}
+		r.recorder.Eventf(vtr, corev1.EventTypeNormal, "VDiffStarted", "Started VDiff %v.", vdiffUUID)
+	}
+
+	outcome, err := vreplication.ShowVDiff(ctx, wr, vtr.Spec.Keyspace, vtr.Spec.Workflow, status.VDiffUUID)
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to get VDiff results: %v", err)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "VDiffShowFailed", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+	if status.LastVDiff == nil || status.LastVDiff.UUID != status.VDiffUUID {
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: status.VDiffUUID}
+	}
+	vreplication.UpdateVDiffReport(status.LastVDiff, outcome, time.Now())
+
+	switch {
+	case outcome.Mismatch:
+		r.failVDiff(vtr)
+		return resultBuilder.Result()
+	case outcome.LastError != "":
+		// VDiff retries on its own, so we keep waiting.
+		status.Message = fmt.Sprintf("VDiff reported an error: %v", outcome.LastError)
+		status.SetConditionStatus(planetscalev2.VitessReshardVDiffPassed, corev1.ConditionUnknown, "Error", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	case !outcome.Completed:
+		status.Message = fmt.Sprintf("Waiting for VDiff %v to finish comparing %d tables.", status.VDiffUUID, outcome.Tables)
+		status.SetConditionStatus(planetscalev2.VitessReshardVDiffPassed, corev1.ConditionUnknown, "Running", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessReshardVDiffPassed, corev1.ConditionTrue, "Match", fmt.Sprintf("VDiff %v found the target shards to match the source shards.", status.VDiffUUID))
+	r.setPhase(vtr, planetscalev2.ReshardSwitchingTrafficPhase, "")
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileSwitchTraffic switches reads and then writes to the target shards,
+// once the spec allows it. Reverse replication is set up, so traffic can
+// still be switched back by hand until the workflow is completed.
+func (r *ReconcileVitessReshard) reconcileSwitchTraffic(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	wait, result, err := r.reconcileScheduledVDiff(ctx, vtr, wr)
+	resultBuilder.Merge(result, err)
+	if wait {
+		return resultBuilder.Result()
+	}
+
+	if message := vreplication.SwitchTrafficWaitReason(vtr.Spec.SwitchTraffic, vtr.Spec.ApprovalRequired, vtr.Spec.Approved, vtr.Annotations); message != "" {
+		// Let the user know once that it's time to approve.
+		if cond, _ := status.GetCondition(planetscalev2.VitessReshardTrafficSwitched); cond.Reason != "WaitingForApproval" {
+			r.recorder.Eventf(vtr, corev1.EventTypeNormal, "ReadyForCutover", "The target shards are ready to take over traffic from shards %v. %v", strings.Join(vtr.Spec.SourceShards, ","), message)
+		}
+		status.Message = message
+		status.SetConditionStatus(planetscalev2.VitessReshardTrafficSwitched, corev1.ConditionFalse, "WaitingForApproval", status.Message)
+		return resultBuilder.Result()
+	}
+
+	_, err = wr.VtctldServer().WorkflowSwitchTraffic(ctx, &vtctldatapb.WorkflowSwitchTrafficRequest{
+		Keyspace:                 vtr.Spec.Keyspace,
+		Workflow:                 vtr.Spec.Workflow,
+		TabletTypes:              []topodatapb.TabletType{topodatapb.TabletType_RDONLY, topodatapb.TabletType_REPLICA, topodatapb.TabletType_PRIMARY},
+		MaxReplicationLagAllowed: protoutil.DurationToProto(vtr.Spec.MaxReplicationLag.Duration),
+		EnableReverseReplication: true,
+		Timeout:                  protoutil.DurationToProto(switchTrafficTimeout),
+	})
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to switch traffic: %v", err)
+		status.SetConditionStatus(planetscalev2.VitessReshardTrafficSwitched, corev1.ConditionFalse, "SwitchFailed", status.Message)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "SwitchTrafficFailed", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.SetConditionStatus(planetscalev2.VitessReshardTrafficSwitched, corev1.ConditionTrue, "Switched", "The target shards serve all traffic.")
+	r.recorder.Eventf(vtr, corev1.EventTypeNormal, "TrafficSwitched", "Switched traffic to shards %v.", strings.Join(vtr.Spec.TargetShards, ","))
+	r.setPhase(vtr, planetscalev2.ReshardCompletingPhase, "")
+	return resultBuilder.RequeueAfter(workflowRequeueDelay)
+}
+
+// reconcileScheduledVDiff runs the VDiffs that spec.vdiff schedules while
+// the workflow waits to switch traffic. It returns true while one is running,
+// since traffic isn't switched until it passes.
+func (r *ReconcileVitessReshard) reconcileScheduledVDiff(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (bool, reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	if vtr.Spec.VDiff == nil {
+		return false, reconcile.Result{}, nil
+	}
+	now := time.Now()
+
+	if last := status.LastVDiff; last == nil || last.CompletionTime != nil {
+		// Scheduled VDiffs are due relative to the start of the last one.
+		since := status.StartTime
+		if last != nil && last.StartTime != nil {
+			since = last.StartTime
+		}
+		if since == nil {
+			since = &metav1.Time{Time: now}
+		}
+		next, err := vreplication.NextScheduledVDiff(vtr.Spec.VDiff.Schedule, since.Time)
+		if err != nil {
+			r.recorder.Event(vtr, corev1.EventTypeWarning, "InvalidVDiffSchedule", err.Error())
+			return false, reconcile.Result{}, nil
+		}
+		if now.Before(next) {
+			// Make sure we come back when it's time, even if nothing else changes.
+			result, err := resultBuilder.RequeueAfter(next.Sub(now))
+			return false, result, err
+		}
+
+		tabletTypes, err := topoproto.ParseTabletTypes(strings.Join(vtr.Spec.TabletTypes, ","))
+		if err != nil {
+			r.fail(vtr, "InvalidTabletTypes", fmt.Sprintf("Invalid tabletTypes: %v", err))
+			return true, reconcile.Result{}, nil
+		}
+		vdiffUUID, err := vreplication.CreateVDiff(ctx, wr, vtr.Spec.Keyspace, vtr.Spec.Workflow, tabletTypes)
+		if err != nil {
+			status.Message = fmt.Sprintf("Failed to start scheduled VDiff: %v", err)
+			r.recorder.Event(vtr, corev1.EventTypeWarning, "VDiffCreateFailed", status.Message)
+			result, err := resultBuilder.RequeueAfter(workflowRequeueDelay)
+			return true, result, err
+		}
+		status.LastVDiff = &planetscalev2.VitessVDiffReport{UUID: vdiffUUID, StartTime: &metav1.Time{Time: now}}
+		r.recorder.Eventf(vtr, corev1.EventTypeNormal, "VDiffStarted", "Started scheduled VDiff %v.", vdiffUUID)
+	}
+
+	last := status.LastVDiff
+	outcome, err := vreplication.ShowVDiff(ctx, wr, vtr.Spec.Keyspace, vtr.Spec.Workflow, last.UUID)
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to get VDiff results: %v", err)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "VDiffShowFailed", status.Message)
+		result, err := resultBuilder.RequeueAfter(workflowRequeueDelay)
+		return true, result, err
+	}
+	vreplication.UpdateVDiffReport(last, outcome, now)
+
+	switch {
+	case outcome.Mismatch:
+		r.failVDiff(vtr)
+		return true, reconcile.Result{}, nil
+	case !outcome.Completed:
+		status.Message = fmt.Sprintf("Waiting for scheduled VDiff %v to finish comparing %d tables.", last.UUID, outcome.Tables)
+		result, err := resultBuilder.RequeueAfter(workflowRequeueDelay)
+		return true, result, err
+	}
+	status.SetConditionStatus(planetscalev2.VitessReshardVDiffPassed, corev1.ConditionTrue, "Match", fmt.Sprintf("VDiff %v found the target shards to match the source shards.", last.UUID))
+	return false, reconcile.Result{}, nil
+}
+
+// reconcileComplete cleans up after the workflow once traffic was switched.
+func (r *ReconcileVitessReshard) reconcileComplete(ctx context.Context, vtr *planetscalev2.VitessReshard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	status := &vtr.Status
+
+	_, err := wr.VtctldServer().MoveTablesComplete(ctx, &vtctldatapb.MoveTablesCompleteRequest{
+		Workflow:       vtr.Spec.Workflow,
+		TargetKeyspace: vtr.Spec.Keyspace,
+	})
+	if err != nil {
+		status.Message = fmt.Sprintf("Failed to complete the workflow: %v", err)
+		r.recorder.Event(vtr, corev1.EventTypeWarning, "CompleteFailed", status.Message)
+		return resultBuilder.RequeueAfter(workflowRequeueDelay)
+	}
+
+	status.CompletionTime = &metav1.Time{Time: time.Now()}
+	r.setPhase(vtr, planetscalev2.ReshardCompletePhase, fmt.Sprintf("Resharding is done. The partitioning with shards %v can now be removed from the VitessCluster.", strings.Join(vtr.Spec.SourceShards, ",")))
+	r.recorder.Eventf(vtr, corev1.EventTypeNormal, "ReshardComplete", "Completed Reshard workflow %v.", vtr.Spec.Workflow)
+	return resultBuilder.Result()
+}
+
+// setPhase moves a VitessReshard to the given phase.
+func (r *ReconcileVitessReshard) setPhase(vtr *planetscalev2.VitessReshard, phase planetscalev2.VitessReshardPhase, message string) {
+	vtr.Status.Phase = phase
+	vtr.Status.Message = message
+}
+
+// failVDiff stops a VitessReshard in the Failed phase because the last VDiff
+// found differences between the source and target shards.
+func (r *ReconcileVitessReshard) failVDiff(vtr *planetscalev2.VitessReshard) {
+	last := vtr.Status.LastVDiff
+	message := fmt.Sprintf("VDiff %v found differences between the source and target shards%v.", last.UUID, vreplication.VDiffTablesSummary(last))
+	vtr.Status.SetConditionStatus(planetscalev2.VitessReshardVDiffPassed, corev1.ConditionFalse, "Mismatch", message)
+	vdiffMismatchCount.WithLabelValues(reshardLabels(vtr)...).Inc()
+	r.fail(vtr, "VDiffMismatch", message)
+}
+
+// fail stops a VitessReshard in the Failed phase.
+func (r *ReconcileVitessReshard) fail(vtr *planetscalev2.VitessReshard, reason, message string) {
+	r.setPhase(vtr, planetscalev2.ReshardFailedPhase, message)
+	r.recorder.Event(vtr, corev1.EventTypeWarning, reason, message)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessreshard
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/resync"
+)
+
+const (
+	controllerName = "vitessreshard-controller"
+)
+
+var (
+	maxConcurrentReconciles = flag.Int("vitessreshard_concurrent_reconciles", 10, "the maximum number of different vitessreshards to reconcile concurrently")
+	resyncPeriod            = flag.Duration("vitessreshard_resync_period", 15*time.Second, "reconcile vitessreshards with this period even if no Kubernetes events occur")
+)
+
+var log = logrus.WithField("controller", "VitessReshard")
+
+// Add creates a new VitessReshard Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileVitessReshard {
+	return &ReconcileVitessReshard{
+		client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		resync:   resync.NewPeriodic(controllerName, *resyncPeriod),
+		recorder: mgr.GetEventRecorderFor(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileVitessReshard) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: *maxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource VitessReshard
+	err = c.Watch(source.Kind(mgr.GetCache(), &planetscalev2.VitessReshard{}), &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Periodically resync even when no Kubernetes events have come in.
+	if err := c.Watch(r.resync.WatchSource(), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileVitessReshard{}
+
+// ReconcileVitessReshard reconciles a VitessReshard object
+type ReconcileVitessReshard struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	client   client.Client
+	scheme   *runtime.Scheme
+	resync   *resync.Periodic
+	recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a VitessReshard object and makes changes based on the state read
+// and what is in the VitessReshard.Spec
+// Note:
+// The Controller will requeue the Request to be processed again if the returned error is non-nil or
+// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileVitessReshard) Reconcile(cctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(cctx, environment.ReconcileTimeout())
+	defer cancel()
+
+	resultBuilder := &results.Builder{}
+
+	log := log.WithFields(logrus.Fields{
+		"namespace":     request.Namespace,
+		"vitessreshard": request.Name,
+	})
+	log.Info("Reconciling VitessReshard")
+
+	// Fetch the VitessReshard instance
+	vtr := &planetscalev2.VitessReshard{}
+	err := r.client.Get(ctx, request.NamespacedName, vtr)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Return and don't requeue
+			return resultBuilder.Result()
+		}
+		// Error reading the object - requeue the request.
+		return resultBuilder.Error(err)
+	}
+	planetscalev2.DefaultVitessReshard(vtr)
+
+	// The workflow progresses across many passes, so the status carries over.
+	oldStatus := vtr.Status.DeepCopy()
+
+	workflowResult, err := r.reconcileWorkflow(ctx, vtr)
+	resultBuilder.Merge(workflowResult, err)
+
+	if vtr.Status.Phase != oldStatus.Phase {
+		phaseTransitionCount.WithLabelValues(reshardLabels(vtr, string(vtr.Status.Phase))...).Inc()
+	}
+
+	// Update status if needed.
+	vtr.Status.ObservedGeneration = vtr.Generation
+	if !apiequality.Semantic.DeepEqual(&vtr.Status, oldStatus) {
+		if err := r.client.Status().Update(ctx, vtr); err != nil {
+			if !apierrors.IsConflict(err) {
+				r.recorder.Eventf(vtr, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update status: %v", err)
+			}
+			resultBuilder.Error(err)
+		}
+	}
+
+	// Keep checking on the workflow until it's done.
+	if !reshardFinished(vtr) {
+		r.resync.Enqueue(request.NamespacedName)
+	}
+
+	result, err := resultBuilder.Result()
+	reconcileCount.WithLabelValues(reshardLabels(vtr, metrics.Result(err))...).Inc()
+	return result, err
+}
+
+// reshardFinished returns whether there's nothing left to do for a
+// VitessReshard unless its spec changes.
+func reshardFinished(vtr *planetscalev2.VitessReshard) bool {
+	return vtr.Status.Phase == planetscalev2.ReshardCompletePhase || vtr.Status.Phase == planetscalev2.ReshardFailedPhase
+}
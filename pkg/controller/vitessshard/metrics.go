@@ -17,10 +17,12 @@ limitations under the License.
 package vitessshard
 
 import (
-	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
 )
 
 const (
@@ -35,17 +37,64 @@ var (
 		metrics.ResultLabel,
 	}
 
+	backupMetricLabels = []string{
+		metrics.ClusterLabel,
+		metrics.KeyspaceLabel,
+		metrics.ShardLabel,
+		metrics.BackupLocationLabel,
+	}
+
+	vtorcRecoveryMetricLabels = []string{
+		metrics.ClusterLabel,
+		metrics.KeyspaceLabel,
+		metrics.ShardLabel,
+		"recovery_type",
+		metrics.ResultLabel,
+	}
+
 	reconcileCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metrics.Namespace,
 		Subsystem: metricsSubsystemName,
 		Name:      "reconcile_count",
 		Help:      "Reconciliation attempts for a VitessShard",
 	}, shardMetricLabels)
+
+	latestBackupAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "latest_complete_backup_age_seconds",
+		Help:      "Time since the latest complete backup of a VitessShard started, for each backup location. Absent if there is no complete backup.",
+	}, backupMetricLabels)
+
+	completeBackupCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "complete_backup_count",
+		Help:      "Complete backups of a VitessShard in each backup location",
+	}, backupMetricLabels)
+
+	incompleteBackupCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "incomplete_backup_count",
+		Help:      "Backups of a VitessShard in each backup location that are in progress or never completed",
+	}, backupMetricLabels)
+
+	vtorcRecoveryCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "vtorc_recovery_count",
+		Help:      "Recoveries run by the vtorc of a VitessShard, by recovery type",
+	}, vtorcRecoveryMetricLabels)
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
+		latestBackupAge,
+		completeBackupCount,
+		incompleteBackupCount,
+		vtorcRecoveryCount,
 	)
 }
 
@@ -57,3 +106,47 @@ func metricLabels(vts *planetscalev2.VitessShard, err error) []string {
 		metrics.Result(err),
 	}
 }
+
+func vtorcRecoveryLabels(vts *planetscalev2.VitessShard, record planetscalev2.VitessOrchestratorRecoveryRecord) []string {
+	result := metrics.ResultSuccess
+	if !record.Succeeded {
+		result = metrics.ResultError
+	}
+	return []string{
+		vts.Labels[planetscalev2.ClusterLabel],
+		vts.Labels[planetscalev2.KeyspaceLabel],
+		vts.Spec.Name,
+		record.Type,
+		result,
+	}
+}
+
+// reportBackupMetrics exports the backup status of each of the shard's backup
+// locations, as computed by updateBackupStatus.
+func reportBackupMetrics(vts *planetscalev2.VitessShard, now time.Time) {
+	shardLabels := prometheus.Labels{
+		metrics.ClusterLabel:  vts.Labels[planetscalev2.ClusterLabel],
+		metrics.KeyspaceLabel: vts.Labels[planetscalev2.KeyspaceLabel],
+		metrics.ShardLabel:    vts.Spec.Name,
+	}
+
+	// Start over each time, so locations that were removed, or that lost
+	// their last complete backup, don't keep reporting stale values.
+	latestBackupAge.DeletePartialMatch(shardLabels)
+	completeBackupCount.DeletePartialMatch(shardLabels)
+	incompleteBackupCount.DeletePartialMatch(shardLabels)
+
+	for _, location := range vts.Status.BackupLocations {
+		labels := []string{
+			shardLabels[metrics.ClusterLabel],
+			shardLabels[metrics.KeyspaceLabel],
+			shardLabels[metrics.ShardLabel],
+			location.Name,
+		}
+		completeBackupCount.WithLabelValues(labels...).Set(float64(location.CompleteBackups))
+		incompleteBackupCount.WithLabelValues(labels...).Set(float64(location.IncompleteBackups))
+		if location.LatestCompleteBackupTime != nil {
+			latestBackupAge.WithLabelValues(labels...).Set(now.Sub(location.LatestCompleteBackupTime.Time).Seconds())
+		}
+	}
+}
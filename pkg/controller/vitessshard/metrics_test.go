@@ -0,0 +1,71 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestReportBackupMetrics(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	vts := &planetscalev2.VitessShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				planetscalev2.ClusterLabel:  "metrics-cluster",
+				planetscalev2.KeyspaceLabel: "ks",
+			},
+		},
+		Spec: planetscalev2.VitessShardSpec{
+			Name: "-80",
+		},
+		Status: planetscalev2.VitessShardStatus{
+			BackupLocations: []*planetscalev2.ShardBackupLocationStatus{
+				{
+					Name:                     "",
+					CompleteBackups:          3,
+					IncompleteBackups:        1,
+					LatestCompleteBackupTime: &metav1.Time{Time: now.Add(-time.Hour)},
+				},
+				{
+					Name:              "archive",
+					IncompleteBackups: 2,
+				},
+			},
+		},
+	}
+
+	reportBackupMetrics(vts, now)
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(completeBackupCount.WithLabelValues("metrics-cluster", "ks", "-80", "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(incompleteBackupCount.WithLabelValues("metrics-cluster", "ks", "-80", "")))
+	assert.Equal(t, 3600.0, testutil.ToFloat64(latestBackupAge.WithLabelValues("metrics-cluster", "ks", "-80", "")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(incompleteBackupCount.WithLabelValues("metrics-cluster", "ks", "-80", "archive")))
+	// A location without a complete backup has no age to report.
+	assert.Equal(t, 1, testutil.CollectAndCount(latestBackupAge))
+
+	// Locations that go away stop being reported.
+	vts.Status.BackupLocations = vts.Status.BackupLocations[:1]
+	reportBackupMetrics(vts, now)
+	assert.Equal(t, 1, testutil.CollectAndCount(completeBackupCount))
+}
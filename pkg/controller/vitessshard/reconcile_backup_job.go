@@ -74,6 +74,13 @@ func (r *ReconcileVitessShard) reconcileBackupJob(ctx context.Context, vts *plan
 		return resultBuilder.Error(err)
 	}
 	updateBackupStatus(vts, allBackups.Items)
+	reportBackupMetrics(vts, time.Now())
+
+	// A standby only restores the backups of the cluster it follows. It has
+	// no primary to take backups of its own from.
+	if vts.Spec.Standby != nil {
+		return resultBuilder.Result()
+	}
 
 	// Here we only care about complete backups.
 	completeBackups := vitessbackup.CompleteBackups(allBackups.Items)
@@ -110,6 +117,48 @@ func (r *ReconcileVitessShard) reconcileBackupJob(ctx context.Context, vts *plan
 		vts.Status.HasInitialBackup = corev1.ConditionTrue
 	}
 
+	err := r.reconcileBackupObjects(ctx, vts, labels, podKeys, pvcKeys, specMap, func(key client.ObjectKey, pod *corev1.Pod) {
+		// If this status hook is telling us about the special init Pod,
+		// we can update HasInitialBackup.
+		if key == initPodKey {
+			// If the Pod is Suceeded or Failed, we can update status.
+			// Otherwise, we leave it as Unknown since we can't tell.
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded:
+				vts.Status.HasInitialBackup = corev1.ConditionTrue
+			case corev1.PodFailed:
+				vts.Status.HasInitialBackup = corev1.ConditionFalse
+			}
+		}
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	// Take periodic backups on a schedule, if configured.
+	scheduleResult, err := r.reconcileBackupSchedule(ctx, vts)
+	resultBuilder.Merge(scheduleResult, err)
+
+	// Take an on-demand backup, if requested.
+	requestResult, err := r.reconcileBackupRequest(ctx, vts)
+	resultBuilder.Merge(requestResult, err)
+
+	// Verify the latest backups on a schedule, if configured.
+	verificationResult, err := r.reconcileBackupVerification(ctx, vts, completeBackups)
+	resultBuilder.Merge(verificationResult, err)
+
+	// Copy the latest backups to other locations, if configured.
+	replicationResult, err := r.reconcileBackupReplication(ctx, vts, completeBackups)
+	resultBuilder.Merge(replicationResult, err)
+
+	return resultBuilder.Result()
+}
+
+// reconcileBackupObjects reconciles the set of vtbackup Pods and PVCs that
+// match the given labels, calling podStatus for each vtbackup Pod that exists.
+func (r *ReconcileVitessShard) reconcileBackupObjects(ctx context.Context, vts *planetscalev2.VitessShard, labels map[string]string, podKeys, pvcKeys []client.ObjectKey, specMap map[client.ObjectKey]*vttablet.BackupSpec, podStatus func(key client.ObjectKey, pod *corev1.Pod)) error {
+	var firstErr error
+
 	// Reconcile vtbackup PVCs. Use the same key as the corresponding Pod,
 	// but only if the Pod expects a PVC.
 	err := r.reconciler.ReconcileObjectSet(ctx, vts, pvcKeys, labels, reconciler.Strategy{
@@ -133,7 +182,7 @@ func (r *ReconcileVitessShard) reconcileBackupJob(ctx context.Context, vts *plan
 		},
 	})
 	if err != nil {
-		resultBuilder.Error(err)
+		firstErr = err
 	}
 
 	// Reconcile vtbackup Pods.
@@ -144,20 +193,7 @@ func (r *ReconcileVitessShard) reconcileBackupJob(ctx context.Context, vts *plan
 			return vttablet.NewBackupPod(key, specMap[key])
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
-			pod := obj.(*corev1.Pod)
-
-			// If this status hook is telling us about the special init Pod,
-			// we can update HasInitialBackup.
-			if key == initPodKey {
-				// If the Pod is Suceeded or Failed, we can update status.
-				// Otherwise, we leave it as Unknown since we can't tell.
-				switch pod.Status.Phase {
-				case corev1.PodSucceeded:
-					vts.Status.HasInitialBackup = corev1.ConditionTrue
-				case corev1.PodFailed:
-					vts.Status.HasInitialBackup = corev1.ConditionFalse
-				}
-			}
+			podStatus(key, obj.(*corev1.Pod))
 		},
 		PrepareForTurndown: func(key client.ObjectKey, obj runtime.Object) *planetscalev2.OrphanStatus {
 			// As soon as the new backup is complete, the backup policy logic
@@ -174,11 +210,11 @@ func (r *ReconcileVitessShard) reconcileBackupJob(ctx context.Context, vts *plan
 			return nil
 		},
 	})
-	if err != nil {
-		resultBuilder.Error(err)
+	if err != nil && firstErr == nil {
+		firstErr = err
 	}
 
-	return resultBuilder.Result()
+	return firstErr
 }
 
 func vtbackupInitSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLabels map[string]string) *vttablet.BackupSpec {
@@ -187,16 +223,26 @@ func vtbackupInitSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, pare
 		return nil
 	}
 
+	// Shards bootstrapped from another cluster's backups must never get an
+	// empty initial backup, or new tablets would restore empty databases.
+	if vts.Spec.InitialRestore != nil {
+		return nil
+	}
+
 	if len(vts.Spec.TabletPools) == 0 {
 		// No tablet pools are defined for this shard.
 		// We don't know enough to make a vtbackup spec.
 		return nil
 	}
 
-	// Make a vtbackup spec that's a similar shape to the first tablet pool.
+	// Make a vtbackup spec that's a similar shape to a tablet pool, preferring
+	// the cell and type that the backup source tablet policy asks for.
 	// This should give it enough resources to run mysqld and restore a backup,
 	// since all tablets need to be able to do that, regardless of type.
-	return vtbackupSpec(key, vts, parentLabels, &vts.Spec.TabletPools[0], vitessbackup.TypeInit)
+	// The initial backup isn't taken from any tablet, so there's no need to
+	// exclude primary-eligible pools here.
+	pool := preferredBackupPool(vts, func(*planetscalev2.VitessShardTabletPool) bool { return true })
+	return vtbackupSpec(key, vts, parentLabels, pool, vitessbackup.TypeInit)
 }
 
 func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLabels map[string]string, pool *planetscalev2.VitessShardTabletPool, backupType string) *vttablet.BackupSpec {
@@ -227,6 +273,16 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 	update.Annotations(&annotations, pool.Annotations)
 	update.Annotations(&annotations, backupLocation.Annotations)
 
+	tolerations := pool.Tolerations
+	var affinity *corev1.Affinity
+	if vtbackup := vts.Spec.Vtbackup; vtbackup != nil {
+		update.Annotations(&annotations, vtbackup.Annotations)
+		affinity = vtbackup.Affinity
+		if len(vtbackup.Tolerations) > 0 {
+			tolerations = vtbackup.Tolerations
+		}
+	}
+
 	// Fill in the parts of a vttablet spec that make sense for vtbackup.
 	tabletSpec := &vttablet.Spec{
 		GlobalLockserver:         vts.Spec.GlobalLockserver,
@@ -235,6 +291,7 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 		KeyRange:                 vts.Spec.KeyRange,
 		Vttablet:                 &pool.Vttablet,
 		Mysqld:                   pool.Mysqld,
+		AutoTuneMysql:            pool.AutoTuneMysql,
 		MysqldExporter:           pool.MysqldExporter,
 		DataVolumePVCName:        key.Name,
 		DataVolumePVCSpec:        pool.DataVolumeClaimTemplate,
@@ -247,11 +304,12 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 		SidecarContainers:        pool.SidecarContainers,
 		ExtraEnv:                 pool.ExtraEnv,
 		Annotations:              annotations,
-		Tolerations:              pool.Tolerations,
+		Affinity:                 affinity,
+		Tolerations:              tolerations,
 		ImagePullSecrets:         vts.Spec.ImagePullSecrets,
 	}
 
-	return &vttablet.BackupSpec{
+	backupSpec := &vttablet.BackupSpec{
 		InitialBackup:     backupType == vitessbackup.TypeInit,
 		MinBackupInterval: minBackupInterval,
 		MinRetentionTime:  minRetentionTime,
@@ -259,6 +317,14 @@ func vtbackupSpec(key client.ObjectKey, vts *planetscalev2.VitessShard, parentLa
 
 		TabletSpec: tabletSpec,
 	}
+	if vtbackup := vts.Spec.Vtbackup; vtbackup != nil {
+		if len(vtbackup.Resources.Requests) > 0 || len(vtbackup.Resources.Limits) > 0 {
+			backupSpec.Resources = &vtbackup.Resources
+		}
+		backupSpec.NodeSelector = vtbackup.NodeSelector
+		backupSpec.PriorityClassName = vtbackup.PriorityClassName
+	}
+	return backupSpec
 }
 
 func updateBackupStatus(vts *planetscalev2.VitessShard, allBackups []planetscalev2.VitessBackup) {
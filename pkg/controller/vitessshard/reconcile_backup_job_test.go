@@ -0,0 +1,110 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestVtbackupSpecPodSettings(t *testing.T) {
+	poolToleration := corev1.Toleration{Key: "tablets", Operator: corev1.TolerationOpExists}
+	backupToleration := corev1.Toleration{Key: "backups", Operator: corev1.TolerationOpExists}
+	backupResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+	}
+
+	newShard := func(vtbackup *planetscalev2.VitessBackupPodSpec) *planetscalev2.VitessShard {
+		return &planetscalev2.VitessShard{
+			Spec: planetscalev2.VitessShardSpec{
+				BackupLocations: []planetscalev2.VitessBackupLocation{
+					{Annotations: map[string]string{"location": "x"}},
+				},
+				Vtbackup: vtbackup,
+			},
+		}
+	}
+	pool := &planetscalev2.VitessShardTabletPool{
+		Mysqld:      &planetscalev2.MysqldSpec{},
+		Annotations: map[string]string{"pool": "x"},
+		Tolerations: []corev1.Toleration{poolToleration},
+	}
+	key := client.ObjectKey{Namespace: "ns", Name: "backup"}
+
+	tests := []struct {
+		name     string
+		vtbackup *planetscalev2.VitessBackupPodSpec
+		check    func(t *testing.T, spec *vttablet.BackupSpec)
+	}{
+		{
+			name: "inherits pool settings by default",
+			check: func(t *testing.T, spec *vttablet.BackupSpec) {
+				assert.Equal(t, []corev1.Toleration{poolToleration}, spec.TabletSpec.Tolerations)
+				assert.Nil(t, spec.TabletSpec.Affinity)
+				assert.Nil(t, spec.Resources)
+				assert.Empty(t, spec.NodeSelector)
+				assert.Empty(t, spec.PriorityClassName)
+			},
+		},
+		{
+			name: "vtbackup settings override pool settings",
+			vtbackup: &planetscalev2.VitessBackupPodSpec{
+				Resources:         backupResources,
+				Affinity:          &corev1.Affinity{},
+				NodeSelector:      map[string]string{"pool": "backups"},
+				Tolerations:       []corev1.Toleration{backupToleration},
+				PriorityClassName: "batch",
+				Annotations:       map[string]string{"vtbackup": "x"},
+			},
+			check: func(t *testing.T, spec *vttablet.BackupSpec) {
+				assert.Equal(t, []corev1.Toleration{backupToleration}, spec.TabletSpec.Tolerations)
+				assert.NotNil(t, spec.TabletSpec.Affinity)
+				assert.Equal(t, &backupResources, spec.Resources)
+				assert.Equal(t, map[string]string{"pool": "backups"}, spec.NodeSelector)
+				assert.Equal(t, "batch", spec.PriorityClassName)
+				assert.Equal(t, map[string]string{"pool": "x", "location": "x", "vtbackup": "x"}, spec.TabletSpec.Annotations)
+			},
+		},
+		{
+			name: "empty vtbackup resources and tolerations fall back to pool",
+			vtbackup: &planetscalev2.VitessBackupPodSpec{
+				PriorityClassName: "batch",
+			},
+			check: func(t *testing.T, spec *vttablet.BackupSpec) {
+				assert.Equal(t, []corev1.Toleration{poolToleration}, spec.TabletSpec.Tolerations)
+				assert.Nil(t, spec.Resources)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := vtbackupSpec(key, newShard(tt.vtbackup), nil, pool, vitessbackup.TypeUpdate)
+			require.NotNil(t, spec)
+			tt.check(t, spec)
+		})
+	}
+}
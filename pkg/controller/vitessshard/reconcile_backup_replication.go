@@ -0,0 +1,232 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// backupReplicationRetryDelay is how long a failed backup replication Pod
+	// is kept around, so its outcome can be inspected, before it's replaced
+	// with a new attempt.
+	backupReplicationRetryDelay = 10 * time.Minute
+)
+
+// reconcileBackupReplication creates a Pod to copy the latest complete backup
+// in the replication source location to each destination location that
+// doesn't have it yet, and records the progress in the Replicated condition of
+// the source VitessBackup object.
+//
+// A copy is done once the VitessBackupStorage controller for the destination
+// location finds the backup there, at which point the Pod is no longer wanted
+// and gets cleaned up along with its scratch PVC.
+func (r *ReconcileVitessShard) reconcileBackupReplication(ctx context.Context, vts *planetscalev2.VitessShard, completeBackups []*planetscalev2.VitessBackup) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtbackupComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+		vitessbackup.TypeLabel:       vitessbackup.TypeReplicate,
+	}
+
+	podKeys := []client.ObjectKey{}
+	pvcKeys := []client.ObjectKey{}
+	specMap := map[client.ObjectKey]*vttablet.BackupSpec{}
+
+	// The backup to copy, and the progress of copying it to each destination.
+	var source *planetscalev2.VitessBackup
+	var destinations []string
+	replicated := map[string]bool{}
+	failures := map[string]string{}
+	podDestinations := map[client.ObjectKey]string{}
+
+	// If replication is turned off, we still reconcile the (now empty) set of
+	// replication Pods below, so old ones get cleaned up.
+	if replication := vts.Spec.BackupReplication; replication != nil {
+		source = vitessbackup.LatestForLocation(replication.SourceLocationName, completeBackups)
+		pool := backupPool(vts, replication.SourceLocationName)
+		if pool == nil {
+			// We don't know what a Pod to copy backups out of this location
+			// should look like.
+			source = nil
+		}
+
+		var operatorImage string
+		for _, destName := range replication.DestinationLocationNames {
+			if source == nil || destName == replication.SourceLocationName {
+				continue
+			}
+			destLocation := vts.Spec.BackupLocation(destName)
+			if destLocation == nil {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidBackupReplication", "backup replication destination location %q is not defined", destName)
+				continue
+			}
+			destinations = append(destinations, destName)
+			if backupReplicated(source, destName, completeBackups) {
+				replicated[destName] = true
+				continue
+			}
+
+			// The replicator is part of the operator binary, so the Pod
+			// needs the image we're running in.
+			if operatorImage == "" {
+				var err error
+				operatorImage, err = fork.OperatorImage(ctx, r.client)
+				if err != nil {
+					r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupReplicationFailed", "can't replicate backups: %v", err)
+					return resultBuilder.Error(err)
+				}
+			}
+
+			key := client.ObjectKey{
+				Namespace: vts.Namespace,
+				Name:      vttablet.BackupReplicationPodName(clusterName, keyspaceName, vts.Spec.KeyRange, destName, source.Status.StartTime.Time),
+			}
+			spec := vtbackupSpec(key, vts, labels, pool, vitessbackup.TypeReplicate)
+			if spec == nil {
+				continue
+			}
+			spec.Replication = &vttablet.BackupReplication{
+				OperatorImage:       operatorImage,
+				StorageDirectory:    source.Status.StorageDirectory,
+				StorageName:         source.Status.StorageName,
+				DestinationLocation: destLocation,
+			}
+			podKeys = append(podKeys, key)
+			if spec.TabletSpec.DataVolumePVCSpec != nil {
+				pvcKeys = append(pvcKeys, key)
+			}
+			specMap[key] = spec
+			podDestinations[key] = destName
+		}
+	}
+
+	now := time.Now()
+	err := r.reconcileBackupObjects(ctx, vts, labels, podKeys, pvcKeys, specMap, func(key client.ObjectKey, pod *corev1.Pod) {
+		if pod.Status.Phase != corev1.PodFailed {
+			return
+		}
+		destName, ok := podDestinations[key]
+		if !ok {
+			return
+		}
+		finishedTime, message := k8s.PodTermination(pod)
+		failures[destName] = message
+
+		// Keep the failed Pod for a while, then delete it so a new one gets
+		// created on the next pass. The scratch PVC is kept for the new Pod.
+		if wait := finishedTime.Add(backupReplicationRetryDelay).Sub(now); wait > 0 {
+			resultBuilder.RequeueAfter(wait)
+			return
+		}
+		if err := r.client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			resultBuilder.Error(err)
+			return
+		}
+		resultBuilder.RequeueAfter(backupScheduleRequeueDelay)
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	if source != nil && len(destinations) > 0 {
+		if err := r.recordBackupReplication(ctx, vts, source, destinations, replicated, failures); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// recordBackupReplication records the progress of copying a backup to each
+// destination location in the VitessBackup object for the source backup.
+func (r *ReconcileVitessShard) recordBackupReplication(ctx context.Context, vts *planetscalev2.VitessShard, source *planetscalev2.VitessBackup, destinations []string, replicated map[string]bool, failures map[string]string) error {
+	status, reason, message := backupReplicationCondition(destinations, replicated, failures)
+	if cond, ok := source.Status.GetCondition(planetscalev2.VitessBackupReplicated); ok && cond.Status == status && cond.Reason == reason && cond.Message == message {
+		// Nothing changed.
+		return nil
+	}
+
+	vb := source.DeepCopy()
+	vb.Status.SetConditionStatus(planetscalev2.VitessBackupReplicated, status, reason, message)
+	switch reason {
+	case "ReplicationSucceeded":
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "BackupReplicated", "replicated backup %v: %v", vb.Name, message)
+	case "ReplicationFailed":
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupReplicationFailed", "failed to replicate backup %v: %v", vb.Name, message)
+	}
+	return r.client.Update(ctx, vb)
+}
+
+// backupReplicationCondition returns the status, reason, and message for the
+// Replicated condition of a backup that's being copied to the given
+// destination locations.
+func backupReplicationCondition(destinations []string, replicated map[string]bool, failures map[string]string) (corev1.ConditionStatus, string, string) {
+	var pending, failed []string
+	for _, destName := range destinations {
+		if replicated[destName] {
+			continue
+		}
+		if message, ok := failures[destName]; ok {
+			failed = append(failed, fmt.Sprintf("%q: %v", destName, message))
+			continue
+		}
+		pending = append(pending, fmt.Sprintf("%q", destName))
+	}
+	sort.Strings(failed)
+	sort.Strings(pending)
+
+	switch {
+	case len(failed) > 0:
+		return corev1.ConditionFalse, "ReplicationFailed", fmt.Sprintf("Failed to copy backup to locations %v.", strings.Join(failed, "; "))
+	case len(pending) > 0:
+		return corev1.ConditionFalse, "Replicating", fmt.Sprintf("Copying backup to locations %v.", strings.Join(pending, ", "))
+	}
+	return corev1.ConditionTrue, "ReplicationSucceeded", fmt.Sprintf("Backup is in all %d destination locations.", len(destinations))
+}
+
+// backupReplicated returns whether there's a complete copy of the source
+// backup in the given location.
+func backupReplicated(source *planetscalev2.VitessBackup, locationName string, completeBackups []*planetscalev2.VitessBackup) bool {
+	for _, backup := range completeBackups {
+		if backup.Labels[vitessbackup.LocationLabel] == locationName && backup.Status.StorageName == source.Status.StorageName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+)
+
+func TestBackupReplicationCondition(t *testing.T) {
+	destinations := []string{"dr-east", "dr-west"}
+
+	tests := []struct {
+		name        string
+		replicated  map[string]bool
+		failures    map[string]string
+		wantStatus  corev1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:        "all replicated",
+			replicated:  map[string]bool{"dr-east": true, "dr-west": true},
+			wantStatus:  corev1.ConditionTrue,
+			wantReason:  "ReplicationSucceeded",
+			wantMessage: "Backup is in all 2 destination locations.",
+		},
+		{
+			name:        "some pending",
+			replicated:  map[string]bool{"dr-east": true},
+			wantStatus:  corev1.ConditionFalse,
+			wantReason:  "Replicating",
+			wantMessage: `Copying backup to locations "dr-west".`,
+		},
+		{
+			name:        "failure wins over pending",
+			failures:    map[string]string{"dr-west": "access denied"},
+			wantStatus:  corev1.ConditionFalse,
+			wantReason:  "ReplicationFailed",
+			wantMessage: `Failed to copy backup to locations "dr-west": access denied.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason, message := backupReplicationCondition(destinations, tt.replicated, tt.failures)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantReason, reason)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestBackupReplicated(t *testing.T) {
+	newBackup := func(location, storageName string) *planetscalev2.VitessBackup {
+		return &planetscalev2.VitessBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{vitessbackup.LocationLabel: location},
+			},
+			Status: planetscalev2.VitessBackupStatus{StorageName: storageName},
+		}
+	}
+	source := newBackup("", "2024-03-01.030000.zone1-0000000101")
+	completeBackups := []*planetscalev2.VitessBackup{
+		source,
+		newBackup("dr-east", "2024-03-01.030000.zone1-0000000101"),
+		newBackup("dr-west", "2024-02-29.030000.zone1-0000000101"),
+	}
+
+	assert.True(t, backupReplicated(source, "dr-east", completeBackups))
+	assert.False(t, backupReplicated(source, "dr-west", completeBackups))
+	assert.False(t, backupReplicated(source, "dr-south", completeBackups))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileBackupRequest creates a vtbackup Pod in each backup location when
+// an on-demand backup is requested with the backup request annotation, and
+// reports its progress in the shard status.
+//
+// The vtbackup Pods are kept after they finish, so we can keep reporting the
+// outcome of the request. They're cleaned up once the annotation is removed
+// or changed to request another backup.
+func (r *ReconcileVitessShard) reconcileBackupRequest(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtbackupComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+		vitessbackup.TypeLabel:       vitessbackup.TypeRequest,
+	}
+
+	podKeys := []client.ObjectKey{}
+	pvcKeys := []client.ObjectKey{}
+	specMap := map[client.ObjectKey]*vttablet.BackupSpec{}
+
+	// If the annotation is removed, we still reconcile the (now empty) set of
+	// requested vtbackup Pods below, so old ones get cleaned up.
+	requestID := vts.Annotations[vitessbackup.RequestAnnotation]
+	if requestID != "" {
+		for _, location := range vts.Status.BackupLocations {
+			// vtbackup starts from the latest complete backup, so the initial
+			// backup has to come first.
+			if location.LatestCompleteBackupTime == nil {
+				continue
+			}
+			pool := backupPool(vts, location.Name)
+			if pool == nil {
+				continue
+			}
+
+			key := client.ObjectKey{
+				Namespace: vts.Namespace,
+				Name:      vttablet.RequestedBackupPodName(clusterName, keyspaceName, vts.Spec.KeyRange, location.Name, requestID),
+			}
+			spec := vtbackupSpec(key, vts, labels, pool, vitessbackup.TypeRequest)
+			if spec == nil {
+				continue
+			}
+			podKeys = append(podKeys, key)
+			if spec.TabletSpec.DataVolumePVCSpec != nil {
+				pvcKeys = append(pvcKeys, key)
+			}
+			specMap[key] = spec
+		}
+	}
+
+	podPhases := map[client.ObjectKey]corev1.PodPhase{}
+	err := r.reconcileBackupObjects(ctx, vts, labels, podKeys, pvcKeys, specMap, func(key client.ObjectKey, pod *corev1.Pod) {
+		podPhases[key] = pod.Status.Phase
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	if requestID != "" {
+		vts.Status.BackupRequest = &planetscalev2.ShardBackupRequestStatus{
+			ID:    requestID,
+			Phase: backupRequestPhase(podKeys, podPhases),
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// backupRequestPhase summarizes the phases of the vtbackup Pods for an
+// on-demand backup into the phase of the request as a whole.
+func backupRequestPhase(podKeys []client.ObjectKey, podPhases map[client.ObjectKey]corev1.PodPhase) corev1.PodPhase {
+	if len(podKeys) == 0 {
+		// We're waiting for an initial backup to start from.
+		return corev1.PodPending
+	}
+
+	succeeded := 0
+	running := false
+	for _, key := range podKeys {
+		switch podPhases[key] {
+		case corev1.PodFailed:
+			return corev1.PodFailed
+		case corev1.PodSucceeded:
+			succeeded++
+		case corev1.PodRunning:
+			running = true
+		}
+	}
+	switch {
+	case succeeded == len(podKeys):
+		return corev1.PodSucceeded
+	case running || succeeded > 0:
+		return corev1.PodRunning
+	default:
+		return corev1.PodPending
+	}
+}
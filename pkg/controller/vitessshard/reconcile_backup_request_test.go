@@ -0,0 +1,70 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestBackupRequestPhase(t *testing.T) {
+	east := client.ObjectKey{Name: "east"}
+	west := client.ObjectKey{Name: "west"}
+
+	tests := []struct {
+		name      string
+		podKeys   []client.ObjectKey
+		podPhases map[client.ObjectKey]corev1.PodPhase
+		want      corev1.PodPhase
+	}{
+		{
+			name: "waiting for initial backup",
+			want: corev1.PodPending,
+		},
+		{
+			name:    "pods not created yet",
+			podKeys: []client.ObjectKey{east, west},
+			want:    corev1.PodPending,
+		},
+		{
+			name:      "one location done",
+			podKeys:   []client.ObjectKey{east, west},
+			podPhases: map[client.ObjectKey]corev1.PodPhase{east: corev1.PodSucceeded, west: corev1.PodPending},
+			want:      corev1.PodRunning,
+		},
+		{
+			name:      "all locations done",
+			podKeys:   []client.ObjectKey{east, west},
+			podPhases: map[client.ObjectKey]corev1.PodPhase{east: corev1.PodSucceeded, west: corev1.PodSucceeded},
+			want:      corev1.PodSucceeded,
+		},
+		{
+			name:      "one location failed",
+			podKeys:   []client.ObjectKey{east, west},
+			podPhases: map[client.ObjectKey]corev1.PodPhase{east: corev1.PodFailed, west: corev1.PodSucceeded},
+			want:      corev1.PodFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, backupRequestPhase(tt.podKeys, tt.podPhases))
+		})
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// backupScheduleRequeueDelay is how long to wait before checking again
+	// whether a scheduled backup that had to wait for other shards can start.
+	backupScheduleRequeueDelay = time.Minute
+)
+
+// reconcileBackupSchedule creates a vtbackup Pod for each backup location
+// whose next scheduled backup is due.
+//
+// The schedule is evaluated relative to the latest complete backup in each
+// location, so we don't need to keep any state of our own about when we last
+// took a backup. The vtbackup Pod name includes the time of that backup, so
+// once the new backup shows up, the old Pod is no longer wanted and a new one
+// will be created for the next scheduled time.
+func (r *ReconcileVitessShard) reconcileBackupSchedule(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	shardSafeName := vts.Spec.KeyRange.SafeName()
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtbackupComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     shardSafeName,
+		vitessbackup.TypeLabel:       vitessbackup.TypeUpdate,
+	}
+
+	podKeys := []client.ObjectKey{}
+	pvcKeys := []client.ObjectKey{}
+	specMap := map[client.ObjectKey]*vttablet.BackupSpec{}
+
+	// If the schedule is removed, we still reconcile the (now empty) set of
+	// scheduled vtbackup Pods below, so old ones get cleaned up.
+	if backupSchedule := vts.Spec.BackupSchedule; backupSchedule != nil {
+		schedule, err := cron.ParseStandard(backupSchedule.Schedule)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidBackupSchedule", "failed to parse backup schedule %q: %v", backupSchedule.Schedule, err)
+			return resultBuilder.Result()
+		}
+		var maxJitter time.Duration
+		if backupSchedule.Jitter != nil {
+			maxJitter = backupSchedule.Jitter.Duration
+		}
+
+		// Find which shards in the keyspace have scheduled backups running,
+		// so we can respect the keyspace's concurrency limit.
+		existingPods, otherShards, err := r.scheduledBackupsRunning(ctx, vts)
+		if err != nil {
+			return resultBuilder.Error(err)
+		}
+
+		now := time.Now()
+		for _, location := range vts.Status.BackupLocations {
+			// Scheduled backups start from the latest complete backup, so
+			// the initial backup has to come first.
+			if location.LatestCompleteBackupTime == nil {
+				continue
+			}
+			latestBackupTime := location.LatestCompleteBackupTime.Time
+			pool := backupPool(vts, location.Name)
+			if pool == nil {
+				// No tablet pool uses this location, or the backup source
+				// tablet policy rules them all out, so we don't know what a
+				// vtbackup Pod for it should look like.
+				continue
+			}
+
+			key := client.ObjectKey{
+				Namespace: vts.Namespace,
+				Name:      vttablet.BackupPodName(clusterName, keyspaceName, vts.Spec.KeyRange, location.Name, latestBackupTime),
+			}
+			phase, exists := existingPods[key.Name]
+			if phase == corev1.PodFailed {
+				// Let the failed Pod be cleaned up, and try again on a later pass.
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupFailed", "scheduled vtbackup Pod %v failed; it will be retried", key.Name)
+				resultBuilder.RequeueAfter(backupScheduleRequeueDelay)
+				continue
+			}
+			if !exists {
+				due, wait := scheduledBackupDue(schedule, latestBackupTime, backupJitter(vts, location.Name, maxJitter), now)
+				if !due {
+					// Make sure we come back when it's time, even if nothing else changes.
+					resultBuilder.RequeueAfter(wait)
+					continue
+				}
+				if limit := backupSchedule.MaxConcurrentShards; limit != nil && otherShards.Len() >= int(*limit) {
+					r.recorder.Eventf(vts, corev1.EventTypeNormal, "BackupDeferred", "not starting scheduled backup in location %q yet: keyspace maxConcurrentShards of %d is in use by other shards", location.Name, *limit)
+					resultBuilder.RequeueAfter(backupScheduleRequeueDelay)
+					continue
+				}
+			}
+
+			spec := vtbackupSpec(key, vts, labels, pool, vitessbackup.TypeUpdate)
+			if spec == nil {
+				continue
+			}
+			podKeys = append(podKeys, key)
+			if spec.TabletSpec.DataVolumePVCSpec != nil {
+				pvcKeys = append(pvcKeys, key)
+			}
+			specMap[key] = spec
+		}
+	}
+
+	err := r.reconcileBackupObjects(ctx, vts, labels, podKeys, pvcKeys, specMap, func(client.ObjectKey, *corev1.Pod) {})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// scheduledBackupsRunning returns the phases of this shard's existing
+// scheduled vtbackup Pods, by name, and the set of other shards in the same
+// keyspace that have a scheduled vtbackup Pod that hasn't finished.
+func (r *ReconcileVitessShard) scheduledBackupsRunning(ctx context.Context, vts *planetscalev2.VitessShard) (map[string]corev1.PodPhase, sets.Set[string], error) {
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace: vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set{
+			planetscalev2.ComponentLabel: planetscalev2.VtbackupComponentName,
+			planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+			planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+			vitessbackup.TypeLabel:       vitessbackup.TypeUpdate,
+		}),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return nil, nil, err
+	}
+
+	shardSafeName := vts.Spec.KeyRange.SafeName()
+	existingPods := make(map[string]corev1.PodPhase)
+	otherShards := sets.New[string]()
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Labels[planetscalev2.ShardLabel] == shardSafeName {
+			existingPods[pod.Name] = pod.Status.Phase
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		otherShards.Insert(pod.Labels[planetscalev2.ShardLabel])
+	}
+	return existingPods, otherShards, nil
+}
+
+// backupPool returns the tablet pool that vtbackup Pods for the given location
+// should be modeled on, according to the shard's backup source tablet policy,
+// or nil if there is none.
+func backupPool(vts *planetscalev2.VitessShard, locationName string) *planetscalev2.VitessShardTabletPool {
+	policy := vts.Spec.BackupSourceTabletPolicy
+	return preferredBackupPool(vts, func(pool *planetscalev2.VitessShardTabletPool) bool {
+		if pool.BackupLocationName != locationName {
+			return false
+		}
+		return policy == nil || !policy.ExcludePrimaryEligible || !primaryEligible(pool.Type)
+	})
+}
+
+// preferredBackupPool returns the tablet pool that best matches the shard's
+// backup source tablet policy, among those for which eligible returns true.
+// Pools in a more preferred cell win over pools of the preferred type.
+// Among equally preferred pools, the first one listed wins.
+func preferredBackupPool(vts *planetscalev2.VitessShard, eligible func(pool *planetscalev2.VitessShardTabletPool) bool) *planetscalev2.VitessShardTabletPool {
+	policy := vts.Spec.BackupSourceTabletPolicy
+
+	rank := func(pool *planetscalev2.VitessShardTabletPool) (int, int) {
+		if policy == nil {
+			return 0, 0
+		}
+		cellRank := len(policy.PreferredCells)
+		for i, cell := range policy.PreferredCells {
+			if pool.Cell == cell {
+				cellRank = i
+				break
+			}
+		}
+		typeRank := 0
+		if policy.PreferredType != "" && pool.Type != policy.PreferredType {
+			typeRank = 1
+		}
+		return cellRank, typeRank
+	}
+
+	var best *planetscalev2.VitessShardTabletPool
+	var bestCellRank, bestTypeRank int
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if !eligible(pool) {
+			continue
+		}
+		cellRank, typeRank := rank(pool)
+		if best == nil || cellRank < bestCellRank || (cellRank == bestCellRank && typeRank < bestTypeRank) {
+			best, bestCellRank, bestTypeRank = pool, cellRank, typeRank
+		}
+	}
+	return best
+}
+
+// primaryEligible returns whether tablets in a pool of the given type can
+// become primary.
+func primaryEligible(poolType planetscalev2.VitessTabletPoolType) bool {
+	return poolType == planetscalev2.ReplicaPoolType || poolType == planetscalev2.ExternalMasterPoolType
+}
+
+// scheduledBackupDue returns whether the first scheduled backup after the
+// latest complete backup, plus jitter, has passed. If not, it also returns how
+// long to wait until it does.
+func scheduledBackupDue(schedule cron.Schedule, latestBackupTime time.Time, jitter time.Duration, now time.Time) (bool, time.Duration) {
+	next := schedule.Next(latestBackupTime).Add(jitter)
+	if next.After(now) {
+		return false, next.Sub(now)
+	}
+	return true, 0
+}
+
+// backupJitter returns a delay within [0, maxJitter) for a shard's scheduled
+// backups in a given location. The delay is derived from the shard and
+// location names, so it stays the same from one reconcile pass to the next.
+func backupJitter(vts *planetscalev2.VitessShard, locationName string, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(vts.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(locationName))
+	return time.Duration(h.Sum64() % uint64(maxJitter))
+}
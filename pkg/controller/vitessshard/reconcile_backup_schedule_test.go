@@ -0,0 +1,167 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestScheduledBackupDue(t *testing.T) {
+	// Every day at 03:00.
+	schedule, err := cron.ParseStandard("0 3 * * *")
+	require.NoError(t, err)
+
+	latestBackup := time.Date(2024, 3, 1, 3, 10, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		jitter   time.Duration
+		wantDue  bool
+		wantWait time.Duration
+	}{
+		{
+			name:     "before next scheduled time",
+			now:      time.Date(2024, 3, 2, 1, 0, 0, 0, time.Local),
+			wantDue:  false,
+			wantWait: 2 * time.Hour,
+		},
+		{
+			name:    "after next scheduled time",
+			now:     time.Date(2024, 3, 2, 3, 0, 0, 0, time.Local),
+			wantDue: true,
+		},
+		{
+			name:     "within jitter",
+			now:      time.Date(2024, 3, 2, 3, 0, 0, 0, time.Local),
+			jitter:   10 * time.Minute,
+			wantDue:  false,
+			wantWait: 10 * time.Minute,
+		},
+		{
+			name:    "missed several scheduled times",
+			now:     time.Date(2024, 3, 9, 0, 0, 0, 0, time.Local),
+			jitter:  10 * time.Minute,
+			wantDue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, wait := scheduledBackupDue(schedule, latestBackup, tt.jitter, tt.now)
+			assert.Equal(t, tt.wantDue, due)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
+
+func TestBackupJitter(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Name = "example-commerce-x-x"
+
+	assert.Zero(t, backupJitter(vts, "default", 0))
+
+	maxJitter := 30 * time.Minute
+	jitter := backupJitter(vts, "default", maxJitter)
+	assert.GreaterOrEqual(t, jitter, time.Duration(0))
+	assert.Less(t, jitter, maxJitter)
+	// The same shard and location always get the same delay.
+	assert.Equal(t, jitter, backupJitter(vts, "default", maxJitter))
+}
+
+func TestBackupPool(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", BackupLocationName: ""},
+		{Cell: "zone2", BackupLocationName: "east"},
+		{Cell: "zone3", BackupLocationName: "east"},
+	}
+
+	assert.Equal(t, "zone1", backupPool(vts, "").Cell)
+	assert.Equal(t, "zone2", backupPool(vts, "east").Cell)
+	assert.Nil(t, backupPool(vts, "west"))
+}
+
+func TestBackupPoolSourceTabletPolicy(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "uswest", Type: planetscalev2.ReplicaPoolType},
+		{Cell: "useast", Type: planetscalev2.ReplicaPoolType},
+		{Cell: "useast", Type: planetscalev2.RdonlyPoolType},
+		{Cell: "uswest", Type: planetscalev2.RdonlyPoolType},
+	}
+
+	tests := []struct {
+		name     string
+		policy   *planetscalev2.VitessBackupSourceTabletPolicy
+		wantCell string
+		wantType planetscalev2.VitessTabletPoolType
+	}{
+		{
+			name:     "no policy",
+			wantCell: "uswest",
+			wantType: planetscalev2.ReplicaPoolType,
+		},
+		{
+			name:     "prefer rdonly",
+			policy:   &planetscalev2.VitessBackupSourceTabletPolicy{PreferredType: planetscalev2.RdonlyPoolType},
+			wantCell: "useast",
+			wantType: planetscalev2.RdonlyPoolType,
+		},
+		{
+			name:     "prefer cell",
+			policy:   &planetscalev2.VitessBackupSourceTabletPolicy{PreferredCells: []string{"useast"}},
+			wantCell: "useast",
+			wantType: planetscalev2.ReplicaPoolType,
+		},
+		{
+			name: "cell wins over type",
+			policy: &planetscalev2.VitessBackupSourceTabletPolicy{
+				PreferredCells: []string{"uswest"},
+				PreferredType:  planetscalev2.RdonlyPoolType,
+			},
+			wantCell: "uswest",
+			wantType: planetscalev2.RdonlyPoolType,
+		},
+		{
+			name:     "exclude primary-eligible",
+			policy:   &planetscalev2.VitessBackupSourceTabletPolicy{ExcludePrimaryEligible: true},
+			wantCell: "useast",
+			wantType: planetscalev2.RdonlyPoolType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vts.Spec.BackupSourceTabletPolicy = tt.policy
+			pool := backupPool(vts, "")
+			assert.Equal(t, tt.wantCell, pool.Cell)
+			assert.Equal(t, tt.wantType, pool.Type)
+		})
+	}
+
+	// If every pool is primary-eligible, there's nothing left to choose.
+	vts.Spec.TabletPools = vts.Spec.TabletPools[:2]
+	vts.Spec.BackupSourceTabletPolicy = &planetscalev2.VitessBackupSourceTabletPolicy{ExcludePrimaryEligible: true}
+	assert.Nil(t, backupPool(vts, ""))
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileBackupVerification creates a Pod to verify the latest complete
+// backup in each location whenever it's due, and records the outcome in the
+// Verified condition of the VitessBackup object.
+//
+// Like scheduled backups, verification is scheduled relative to what's
+// recorded in the VitessBackup object, so once the outcome is recorded, the
+// Pod is no longer wanted and gets cleaned up along with its scratch PVC.
+func (r *ReconcileVitessShard) reconcileBackupVerification(ctx context.Context, vts *planetscalev2.VitessShard, completeBackups []*planetscalev2.VitessBackup) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtbackupComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+		vitessbackup.TypeLabel:       vitessbackup.TypeVerify,
+	}
+
+	podKeys := []client.ObjectKey{}
+	pvcKeys := []client.ObjectKey{}
+	specMap := map[client.ObjectKey]*vttablet.BackupSpec{}
+
+	// If verification is turned off, we still reconcile the (now empty) set
+	// of verification Pods below, so old ones get cleaned up.
+	if verification := vts.Spec.BackupVerification; verification != nil {
+		schedule, err := cron.ParseStandard(verification.Schedule)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidBackupVerificationSchedule", "failed to parse backup verification schedule %q: %v", verification.Schedule, err)
+			return resultBuilder.Result()
+		}
+		checksumTables := checksumTablesForKeyspace(verification.ChecksumTables, keyspaceName)
+
+		var operatorImage string
+		now := time.Now()
+		for _, location := range vts.Status.BackupLocations {
+			backup := vitessbackup.LatestForLocation(location.Name, completeBackups)
+			if backup == nil {
+				continue
+			}
+			pool := backupPool(vts, location.Name)
+			if pool == nil {
+				continue
+			}
+
+			dueSince := verificationDueSince(backup)
+			due, wait := scheduledBackupDue(schedule, dueSince, 0, now)
+			if !due {
+				// Make sure we come back when it's time, even if nothing else changes.
+				resultBuilder.RequeueAfter(wait)
+				continue
+			}
+
+			// The verifier is part of the operator binary, so the Pod needs
+			// the image we're running in.
+			if operatorImage == "" {
+				operatorImage, err = fork.OperatorImage(ctx, r.client)
+				if err != nil {
+					r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupVerificationFailed", "can't verify backups: %v", err)
+					return resultBuilder.Error(err)
+				}
+			}
+
+			key := client.ObjectKey{
+				Namespace: vts.Namespace,
+				Name:      vttablet.BackupVerificationPodName(clusterName, keyspaceName, vts.Spec.KeyRange, location.Name, dueSince),
+			}
+			spec := vtbackupSpec(key, vts, labels, pool, vitessbackup.TypeVerify)
+			if spec == nil {
+				continue
+			}
+			spec.Verification = &vttablet.BackupVerification{
+				OperatorImage:  operatorImage,
+				BackupName:     backup.Name,
+				BackupTime:     backup.Status.StartTime.Time,
+				ChecksumTables: checksumTables,
+			}
+			podKeys = append(podKeys, key)
+			if spec.TabletSpec.DataVolumePVCSpec != nil {
+				pvcKeys = append(pvcKeys, key)
+			}
+			specMap[key] = spec
+		}
+	}
+
+	err := r.reconcileBackupObjects(ctx, vts, labels, podKeys, pvcKeys, specMap, func(key client.ObjectKey, pod *corev1.Pod) {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			return
+		}
+		if err := r.recordBackupVerification(ctx, vts, pod); err != nil {
+			resultBuilder.Error(err)
+		}
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// recordBackupVerification records the outcome of a finished verification Pod
+// in the VitessBackup object it verified.
+func (r *ReconcileVitessShard) recordBackupVerification(ctx context.Context, vts *planetscalev2.VitessShard, pod *corev1.Pod) error {
+	vb := &planetscalev2.VitessBackup{}
+	key := client.ObjectKey{Namespace: pod.Namespace, Name: pod.Annotations[vitessbackup.VerifiedBackupAnnotation]}
+	if err := r.client.Get(ctx, key, vb); err != nil {
+		// If the backup is gone, there's nothing to record.
+		return client.IgnoreNotFound(err)
+	}
+
+	finishedTime := time.Now()
+	var message string
+	for i := range pod.Status.ContainerStatuses {
+		if terminated := pod.Status.ContainerStatuses[i].State.Terminated; terminated != nil {
+			finishedTime = terminated.FinishedAt.Time
+			message = terminated.Message
+		}
+	}
+	if last := vb.Status.LastVerificationTime; last != nil && !last.Time.Before(finishedTime) {
+		// We already recorded this one.
+		return nil
+	}
+
+	vb.Status.LastVerificationTime = &metav1.Time{Time: finishedTime}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		vb.Status.SetConditionStatus(planetscalev2.VitessBackupVerified, corev1.ConditionTrue, "VerificationSucceeded", message)
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "BackupVerified", "verified backup %v: %v", vb.Name, message)
+	} else {
+		vb.Status.SetConditionStatus(planetscalev2.VitessBackupVerified, corev1.ConditionFalse, "VerificationFailed", message)
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "BackupVerificationFailed", "failed to verify backup %v: %v", vb.Name, message)
+	}
+	return r.client.Update(ctx, vb)
+}
+
+// verificationDueSince returns the time after which the next scheduled
+// verification of a backup is due: when it was last verified, or when it
+// finished if it was never verified.
+func verificationDueSince(backup *planetscalev2.VitessBackup) time.Time {
+	if backup.Status.LastVerificationTime != nil {
+		return backup.Status.LastVerificationTime.Time
+	}
+	if backup.Status.FinishedTime != nil {
+		return backup.Status.FinishedTime.Time
+	}
+	return backup.Status.StartTime.Time
+}
+
+// checksumTablesForKeyspace returns the names of the tables in the given
+// keyspace from a list of tables in the form "keyspace.table".
+func checksumTablesForKeyspace(tables []string, keyspaceName string) []string {
+	var result []string
+	for _, table := range tables {
+		keyspace, name, ok := strings.Cut(table, ".")
+		if ok && keyspace == keyspaceName && name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestChecksumTablesForKeyspace(t *testing.T) {
+	tables := []string{"commerce.customer", "customer.corder", "commerce.product", "commerce.", "invalid"}
+	assert.Equal(t, []string{"customer", "product"}, checksumTablesForKeyspace(tables, "commerce"))
+	assert.Equal(t, []string{"corder"}, checksumTablesForKeyspace(tables, "customer"))
+	assert.Empty(t, checksumTablesForKeyspace(tables, "other"))
+}
+
+func TestVerificationDueSince(t *testing.T) {
+	start := time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)
+	finished := start.Add(time.Hour)
+	verified := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		status planetscalev2.VitessBackupStatus
+		want   time.Time
+	}{
+		{
+			name:   "no finish time",
+			status: planetscalev2.VitessBackupStatus{StartTime: metav1.Time{Time: start}},
+			want:   start,
+		},
+		{
+			name: "never verified",
+			status: planetscalev2.VitessBackupStatus{
+				StartTime:    metav1.Time{Time: start},
+				FinishedTime: &metav1.Time{Time: finished},
+			},
+			want: finished,
+		},
+		{
+			name: "verified before",
+			status: planetscalev2.VitessBackupStatus{
+				StartTime:            metav1.Time{Time: start},
+				FinishedTime:         &metav1.Time{Time: finished},
+				LastVerificationTime: &metav1.Time{Time: verified},
+			},
+			want: verified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := &planetscalev2.VitessBackup{Status: tt.status}
+			assert.Equal(t, tt.want, verificationDueSince(backup))
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileCanary updates the progress of a canary upgrade of the vttablet
+// image in the shard status, if the update strategy calls for one.
+//
+// It returns the set of tablets that may be released for update, or nil if
+// every tablet may be. While the canary tablets are being upgraded or soaking,
+// only they may be released, and waiting describes what the rollout is
+// waiting for. If the canaries are soaking, soakRemaining is how much longer
+// they need to stay healthy.
+func (r *ReconcileVitessShard) reconcileCanary(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, primaryAlias string, now time.Time) (allowed map[string]bool, waiting string, soakRemaining time.Duration) {
+	canary := vts.Spec.UpdateStrategy.Canary
+	image := vts.Spec.Images.Vttablet
+	if canary == nil || !vttabletUpgradePending(tabletPods, image) {
+		vts.Status.Canary = nil
+		return nil, "", 0
+	}
+
+	status := vts.Status.Canary
+	if !canaryStatusCurrent(vts, image) {
+		// Start a new canary for this image.
+		status = &planetscalev2.VitessShardCanaryStatus{
+			Image:   image,
+			Tablets: pickCanaryTablets(vts, tabletPods, primaryAlias, int(*canary.Replicas)),
+		}
+		vts.Status.Canary = status
+		if len(status.Tablets) == 0 {
+			// There are no tablets we can use as canaries, so carry on
+			// without one.
+			status.Promoted = true
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "CanarySkipped", "No replica tablets can be upgraded to %v as canaries.", image)
+		} else {
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "CanaryStarted", "Upgrading canary tablets %v to %v.", status.Tablets, image)
+		}
+	}
+	if status.Promoted {
+		return nil, "", 0
+	}
+
+	allowed = make(map[string]bool, len(status.Tablets))
+	for _, tabletKey := range status.Tablets {
+		allowed[tabletKey] = true
+	}
+
+	if reason := canaryNotHealthy(vts, tabletPods, status.Tablets, image); reason != "" {
+		if status.SoakStartTime != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "CanaryUnhealthy", "Restarting soak period of canary tablets: %v", reason)
+			status.SoakStartTime = nil
+		}
+		return allowed, fmt.Sprintf("Waiting for canary tablets: %v", reason), 0
+	}
+
+	if status.SoakStartTime == nil {
+		status.SoakStartTime = &metav1.Time{Time: now}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "CanarySoaking", "Canary tablets %v are healthy on %v. Waiting %v before upgrading the rest of the shard.", status.Tablets, image, canary.SoakPeriod.Duration)
+	}
+	if remaining := status.SoakStartTime.Add(canary.SoakPeriod.Duration).Sub(now); remaining > 0 {
+		return allowed, fmt.Sprintf("Waiting %v more for canary tablets to soak.", remaining.Round(time.Second)), remaining
+	}
+
+	status.Promoted = true
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "CanaryPromoted", "Canary tablets %v stayed healthy on %v. Upgrading the rest of the shard.", status.Tablets, image)
+	return nil, "", 0
+}
+
+// vttabletUpgradePending returns whether any tablet Pod isn't running the
+// given vttablet image yet.
+func vttabletUpgradePending(tabletPods map[string]*corev1.Pod, image string) bool {
+	for _, pod := range tabletPods {
+		if vttablet.VttabletImage(pod) != image {
+			return true
+		}
+	}
+	return false
+}
+
+// canaryStatusCurrent returns whether the canary in the shard status is for
+// the given image, and all of its tablets are still wanted.
+func canaryStatusCurrent(vts *planetscalev2.VitessShard, image string) bool {
+	status := vts.Status.Canary
+	if status == nil || status.Image != image {
+		return false
+	}
+	for _, tabletKey := range status.Tablets {
+		if _, ok := vts.Status.Tablets[tabletKey]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// pickCanaryTablets chooses up to count non-primary tablets in replica-type
+// pools to upgrade first.
+func pickCanaryTablets(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, primaryAlias string, count int) []string {
+	var canaries []string
+	for _, tabletKey := range vts.Status.TabletAliases() {
+		if len(canaries) >= count {
+			break
+		}
+		if tabletKey == primaryAlias || vts.Status.Tablets[tabletKey].PoolType != string(planetscalev2.ReplicaPoolType) {
+			continue
+		}
+		if _, ok := tabletPods[tabletKey]; !ok {
+			continue
+		}
+		canaries = append(canaries, tabletKey)
+	}
+	return canaries
+}
+
+// canaryNotHealthy returns why the canary tablets aren't all healthy on the
+// given image yet, or an empty string if they are.
+func canaryNotHealthy(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, canaries []string, image string) string {
+	for _, tabletKey := range canaries {
+		tablet := vts.Status.Tablets[tabletKey]
+		pod, ok := tabletPods[tabletKey]
+		switch {
+		case !ok:
+			return fmt.Sprintf("tablet %v has no Pod.", tabletKey)
+		case vttablet.VttabletImage(pod) != image || rollout.Scheduled(pod) || rollout.Released(pod):
+			return fmt.Sprintf("tablet %v is not upgraded yet.", tabletKey)
+		case tablet.Available != corev1.ConditionTrue:
+			return fmt.Sprintf("tablet %v is not Available.", tabletKey)
+		case tablet.Replicating == corev1.ConditionFalse:
+			return fmt.Sprintf("tablet %v is not replicating: %v", tabletKey, tablet.ReplicationError)
+		case tablet.ErrantGTIDs != "":
+			return fmt.Sprintf("tablet %v has errant GTIDs: %v", tabletKey, tablet.ErrantGTIDs)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestReconcileCanary(t *testing.T) {
+	const (
+		oldImage = "vitess/lite:v17"
+		newImage = "vitess/lite:v18"
+	)
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.Images.Vttablet = newImage
+	vts.Spec.UpdateStrategy = &planetscalev2.VitessClusterUpdateStrategy{
+		Canary: &planetscalev2.VitessCanaryUpdateStrategy{
+			Replicas:   pointer.Int32Ptr(1),
+			SoakPeriod: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {PoolType: "replica", Available: corev1.ConditionTrue},
+		"zone1-2": {PoolType: "replica", Available: corev1.ConditionTrue},
+		"zone1-3": {PoolType: "rdonly", Available: corev1.ConditionTrue},
+		"zone1-4": {PoolType: "replica", Available: corev1.ConditionTrue},
+	}
+	newPod := func(image string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "vttablet", Image: image}}}}
+	}
+	pods := map[string]*corev1.Pod{
+		"zone1-1": newPod(oldImage),
+		"zone1-2": newPod(oldImage),
+		"zone1-3": newPod(oldImage),
+		"zone1-4": newPod(oldImage),
+	}
+
+	// The first non-primary replica is chosen as the canary.
+	allowed, waiting, soakRemaining := r.reconcileCanary(vts, pods, "zone1-1", start)
+	assert.Equal(t, map[string]bool{"zone1-2": true}, allowed)
+	assert.NotEmpty(t, waiting)
+	assert.Zero(t, soakRemaining)
+	assert.Equal(t, []string{"zone1-2"}, vts.Status.Canary.Tablets)
+
+	// Once the canary is upgraded and healthy, it soaks.
+	pods["zone1-2"] = newPod(newImage)
+	allowed, _, soakRemaining = r.reconcileCanary(vts, pods, "zone1-1", start.Add(time.Minute))
+	assert.Equal(t, map[string]bool{"zone1-2": true}, allowed)
+	assert.Equal(t, 10*time.Minute, soakRemaining)
+
+	// If the canary breaks, the soak period starts over.
+	tablet := vts.Status.Tablets["zone1-2"]
+	tablet.Replicating = corev1.ConditionFalse
+	vts.Status.Tablets["zone1-2"] = tablet
+	allowed, _, soakRemaining = r.reconcileCanary(vts, pods, "zone1-1", start.Add(5*time.Minute))
+	assert.Equal(t, map[string]bool{"zone1-2": true}, allowed)
+	assert.Zero(t, soakRemaining)
+	assert.Nil(t, vts.Status.Canary.SoakStartTime)
+
+	tablet.Replicating = corev1.ConditionTrue
+	vts.Status.Tablets["zone1-2"] = tablet
+	_, _, soakRemaining = r.reconcileCanary(vts, pods, "zone1-1", start.Add(6*time.Minute))
+	assert.Equal(t, 10*time.Minute, soakRemaining)
+
+	// After the soak period, the rest of the shard may be upgraded.
+	allowed, waiting, _ = r.reconcileCanary(vts, pods, "zone1-1", start.Add(16*time.Minute))
+	assert.Nil(t, allowed)
+	assert.Empty(t, waiting)
+	assert.True(t, vts.Status.Canary.Promoted)
+
+	// Once every tablet is upgraded, the canary is done.
+	for key := range pods {
+		pods[key] = newPod(newImage)
+	}
+	r.reconcileCanary(vts, pods, "zone1-1", start.Add(time.Hour))
+	assert.Nil(t, vts.Status.Canary)
+}
+
+func TestPickCanaryTablets(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {PoolType: "replica"},
+		"zone1-2": {PoolType: "rdonly"},
+		"zone1-3": {PoolType: "replica"},
+		"zone2-1": {PoolType: "replica"},
+		"zone2-2": {PoolType: "replica"},
+	}
+	pods := map[string]*corev1.Pod{"zone1-1": {}, "zone1-2": {}, "zone1-3": {}, "zone2-2": {}}
+
+	assert.Equal(t, []string{"zone1-3", "zone2-2"}, pickCanaryTablets(vts, pods, "zone1-1", 2))
+	assert.Equal(t, []string{"zone1-3"}, pickCanaryTablets(vts, pods, "zone1-1", 1))
+	assert.Empty(t, pickCanaryTablets(vts, map[string]*corev1.Pod{"zone1-1": {}}, "zone1-1", 2))
+}
@@ -2,8 +2,11 @@ package vitessshard
 
 import (
 	"context"
+	"flag"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -12,12 +15,17 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
 )
 
 const (
 	pvcFilesystemResizeAnnotation = "planetscale.com/pvc-filesystem-resize"
 )
 
+var (
+	checkVolumeExpansion = flag.Bool("check_volume_expansion", false, "look up whether the StorageClass of each tablet data volume allows volume expansion, and grow volumes that can't be expanded by restoring their tablets from backup onto new ones (requires permission to read StorageClasses)")
+)
+
 func (r *ReconcileVitessShard) reconcileDisk(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
 	resultBuilder := &results.Builder{}
 
@@ -44,7 +52,8 @@ func (r *ReconcileVitessShard) reconcileDisk(ctx context.Context, vts *planetsca
 
 	for i := range vts.Spec.TabletPools {
 		tabletPool := &vts.Spec.TabletPools[i]
-		if tabletPool.DataVolumeClaimTemplate == nil {
+		// Ephemeral volumes are replaced whenever their Pods are.
+		if tabletPool.DataVolumeClaimTemplate == nil || tabletPool.EphemeralStorage() {
 			continue
 		}
 
@@ -67,6 +76,12 @@ func (r *ReconcileVitessShard) reconcileDisk(ctx context.Context, vts *planetsca
 				continue
 			}
 
+			// Volumes that are being replaced don't need a restart to grow.
+			tabletStatus := vts.Status.Tablets[tabletKey]
+			if resize := tabletStatus.DataVolumeResize; tabletStatus.DataVolumeMigration != nil || (resize != nil && resize.Method == planetscalev2.ReplaceDataVolumeResizeMethod) {
+				continue
+			}
+
 			pvc, err := r.claimForTabletPod(ctx, pod)
 			if apierrors.IsNotFound(err) {
 				continue
@@ -154,3 +169,72 @@ func tabletKeysForPool(vts *planetscalev2.VitessShard, poolCell string, poolType
 
 	return tabletsInCell, nil
 }
+
+// volumeExpansionAllowed returns whether the StorageClass of a PVC allows it
+// to be expanded in place. Unless we've been asked to check, we assume it
+// does, and let the request fail if it doesn't.
+func (r *ReconcileVitessShard) volumeExpansionAllowed(ctx context.Context, pvc *v1.PersistentVolumeClaim) bool {
+	if !*checkVolumeExpansion {
+		return true
+	}
+	className := pvc.Spec.StorageClassName
+	if className == nil || *className == "" {
+		// Volumes without a StorageClass were provisioned by hand, and can't
+		// be expanded.
+		return false
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: *className}, storageClass); err != nil {
+		// If we can't tell, keep asking for expansion. The worst case is that
+		// the request is rejected.
+		return !apierrors.IsNotFound(err)
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion
+}
+
+// dataVolumeResizeStatus returns the progress of growing a tablet's data
+// volume to the size requested in its pool, or nil if it's big enough.
+func (r *ReconcileVitessShard) dataVolumeResizeStatus(ctx context.Context, vts *planetscalev2.VitessShard, resultBuilder *results.Builder, pvc *v1.PersistentVolumeClaim, tablet *vttablet.Spec) *planetscalev2.VitessTabletDataVolumeResizeStatus {
+	desiredSize, ok := tablet.DataVolumePVCSpec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+	// A volume that isn't bound yet is created at the requested size.
+	currentSize, ok := pvc.Status.Capacity[v1.ResourceStorage]
+	if !ok || currentSize.Cmp(desiredSize) >= 0 {
+		return nil
+	}
+
+	status := &planetscalev2.VitessTabletDataVolumeResizeStatus{
+		CurrentSize: &currentSize,
+		DesiredSize: &desiredSize,
+	}
+	windowOpen := r.maintenanceWindowOpen(vts, resultBuilder, time.Now())
+
+	if !r.volumeExpansionAllowed(ctx, pvc) {
+		status.Method = planetscalev2.ReplaceDataVolumeResizeMethod
+		if !windowOpen {
+			status.Phase = planetscalev2.DataVolumeResizeWaitingPhase
+			status.Message = "waiting for a maintenance window to replace the volume"
+			return status
+		}
+		status.Phase = planetscalev2.DataVolumeResizeReplacingPhase
+		status.Message = "the StorageClass doesn't allow volume expansion, so the tablet will be restored from backup onto a new volume"
+		return status
+	}
+
+	status.Method = planetscalev2.ExpandDataVolumeResizeMethod
+	requestedSize := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	switch {
+	case requestedSize.Cmp(desiredSize) < 0:
+		status.Phase = planetscalev2.DataVolumeResizeWaitingPhase
+		status.Message = "waiting for a maintenance window to expand the volume"
+	case checkPVCFileSystemResizeCondition(pvc):
+		status.Phase = planetscalev2.DataVolumeResizeFilesystemPendingPhase
+		status.Message = "waiting for the tablet to restart so the filesystem can grow"
+	default:
+		status.Phase = planetscalev2.DataVolumeResizeExpandingPhase
+		status.Message = "waiting for the storage provider to expand the volume"
+	}
+	return status
+}
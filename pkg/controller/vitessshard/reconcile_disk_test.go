@@ -0,0 +1,80 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestDataVolumeResizeStatus(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	ctx := context.Background()
+	vts := &planetscalev2.VitessShard{}
+	tablet := &vttablet.Spec{
+		DataVolumePVCSpec: &corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+			},
+		},
+	}
+	newPVC := func(requested, capacity string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(requested)}
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		return pvc
+	}
+
+	// Volumes that are big enough, or not provisioned yet, have nothing to report.
+	assert.Nil(t, r.dataVolumeResizeStatus(ctx, vts, &results.Builder{}, newPVC("20Gi", "20Gi"), tablet))
+	assert.Nil(t, r.dataVolumeResizeStatus(ctx, vts, &results.Builder{}, &corev1.PersistentVolumeClaim{}, tablet))
+
+	status := r.dataVolumeResizeStatus(ctx, vts, &results.Builder{}, newPVC("20Gi", "10Gi"), tablet)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, planetscalev2.ExpandDataVolumeResizeMethod, status.Method)
+		assert.Equal(t, planetscalev2.DataVolumeResizeExpandingPhase, status.Phase)
+		assert.Equal(t, "10Gi", status.CurrentSize.String())
+		assert.Equal(t, "20Gi", status.DesiredSize.String())
+	}
+
+	pvc := newPVC("20Gi", "10Gi")
+	pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+		{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+	}
+	status = r.dataVolumeResizeStatus(ctx, vts, &results.Builder{}, pvc, tablet)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, planetscalev2.DataVolumeResizeFilesystemPendingPhase, status.Phase)
+	}
+
+	// A volume without a StorageClass can't be expanded, so it's replaced.
+	*checkVolumeExpansion = true
+	defer func() { *checkVolumeExpansion = false }()
+	status = r.dataVolumeResizeStatus(ctx, vts, &results.Builder{}, newPVC("10Gi", "10Gi"), tablet)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, planetscalev2.ReplaceDataVolumeResizeMethod, status.Method)
+		assert.Equal(t, planetscalev2.DataVolumeResizeReplacingPhase, status.Phase)
+	}
+}
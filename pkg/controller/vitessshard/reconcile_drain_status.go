@@ -0,0 +1,137 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+// recordDrainState adds a tablet to the drain status list matching the drain
+// state of its Pod, if any.
+func recordDrainState(vts *planetscalev2.VitessShard, tabletAlias string, pod *corev1.Pod) {
+	status := &vts.Status.DrainStatus
+
+	// Leftover annotations without a drain request mean the drain was aborted.
+	if !drain.Started(pod) && (drain.Acknowledged(pod) || drain.Finished(pod)) {
+		status.Aborted = append(status.Aborted, tabletAlias)
+		return
+	}
+
+	// GetState only returns an error for impossible combinations of
+	// annotations, and even then it returns the state we'd act on.
+	state, _ := drain.GetState(pod)
+	switch state {
+	case drain.DrainingState:
+		status.Draining = append(status.Draining, tabletAlias)
+	case drain.AcknowledgedState:
+		status.Acknowledged = append(status.Acknowledged, tabletAlias)
+		if drain.Stuck(pod, vts.Spec.ReparentSettings.DrainTimeout.Duration, time.Now()) {
+			status.Stuck = append(status.Stuck, tabletAlias)
+		}
+	case drain.FinishedState:
+		status.Finished = append(status.Finished, tabletAlias)
+	}
+}
+
+// updateDrainStatus fills in the parts of the drain status that depend on
+// the shard's topology, sorts the per-state tablet lists so the order is
+// consistent, and sets the DrainAborted and DrainStuck conditions.
+//
+// NOTE: This must always be done after reconcileTablets and
+// reconcileTopology, so the per-state lists, Status.Tablets and
+// Status.MasterAlias are populated.
+func updateDrainStatus(vts *planetscalev2.VitessShard) {
+	status := &vts.Status.DrainStatus
+	sort.Strings(status.Draining)
+	sort.Strings(status.Acknowledged)
+	sort.Strings(status.Finished)
+	sort.Strings(status.Aborted)
+	sort.Strings(status.Stuck)
+
+	if len(status.Aborted) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardDrainAborted, corev1.ConditionTrue, "PartiallyDrainedPods",
+			fmt.Sprintf("Tablets left partially drained by an aborted drain: %s", strings.Join(status.Aborted, ", ")))
+	} else {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardDrainAborted, corev1.ConditionFalse, "NoAbortedDrains", "")
+	}
+
+	if len(status.Stuck) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardDrainStuck, corev1.ConditionTrue, "DrainTimeoutExceeded",
+			fmt.Sprintf("Tablets acknowledged for longer than %v without finishing: %s", vts.Spec.ReparentSettings.DrainTimeout.Duration, strings.Join(status.Stuck, ", ")))
+	} else {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardDrainStuck, corev1.ConditionFalse, "NoStuckDrains", "")
+	}
+
+	// We can't say anything about the primary if we couldn't read the
+	// shard record.
+	if vts.Status.HasMaster == corev1.ConditionUnknown {
+		return
+	}
+
+	primaryAlias := vts.Status.MasterAlias
+	primaryDraining := primaryAlias != "" &&
+		(slices.Contains(status.Draining, primaryAlias) ||
+			slices.Contains(status.Acknowledged, primaryAlias) ||
+			slices.Contains(status.Finished, primaryAlias))
+	status.PrimaryReparentPending = k8s.ConditionStatus(primaryDraining)
+	status.PrimaryReparentBlocked = k8s.ConditionStatus(primaryDraining && !hasPrimaryCandidate(vts))
+}
+
+// hasPrimaryCandidate returns whether any tablet other than the current
+// primary is eligible to become primary in a planned reparent.
+//
+// This mirrors the basic eligibility rules used by the replication controller
+// when it chooses a new primary for a drain, based on what we know from status.
+func hasPrimaryCandidate(vts *planetscalev2.VitessShard) bool {
+	status := &vts.Status.DrainStatus
+	usingExternal := vts.Spec.UsingExternalDatastore()
+
+	for tabletAlias, tablet := range vts.Status.Tablets {
+		if tabletAlias == vts.Status.MasterAlias {
+			continue
+		}
+		if tablet.Ready != corev1.ConditionTrue {
+			continue
+		}
+		if slices.Contains(status.Draining, tabletAlias) ||
+			slices.Contains(status.Acknowledged, tabletAlias) ||
+			slices.Contains(status.Finished, tabletAlias) {
+			continue
+		}
+		if usingExternal {
+			// Because we aren't handling MySQL replication, a tablet in an
+			// external primary pool is eligible if it's either spare or primary.
+			if !tablet.IsExternalMaster() || (tablet.Type != "spare" && tablet.Type != "primary") {
+				continue
+			}
+		} else if tablet.Type != "replica" {
+			continue
+		}
+		return true
+	}
+	return false
+}
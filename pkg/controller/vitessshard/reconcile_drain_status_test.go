@@ -0,0 +1,167 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+func TestRecordDrainState(t *testing.T) {
+	pod := func(annotations ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+		for _, key := range annotations {
+			p.Annotations[key] = "test"
+		}
+		return p
+	}
+
+	vts := &planetscalev2.VitessShard{}
+	planetscalev2.DefaultVitessShard(vts)
+	vts.Spec.ReparentSettings.DrainTimeout = &metav1.Duration{Duration: time.Hour}
+	status := &vts.Status.DrainStatus
+
+	stuckPod := pod(drain.StartedAnnotation)
+	stuckPod.Annotations[drain.AcknowledgedAnnotation] = time.Now().Add(-2 * time.Hour).UTC().String()
+
+	recordDrainState(vts, "zone1-1", pod())
+	recordDrainState(vts, "zone1-2", pod(drain.StartedAnnotation))
+	recordDrainState(vts, "zone1-3", pod(drain.StartedAnnotation, drain.AcknowledgedAnnotation))
+	recordDrainState(vts, "zone1-4", pod(drain.StartedAnnotation, drain.AcknowledgedAnnotation, drain.FinishedAnnotation))
+	recordDrainState(vts, "zone1-5", pod(drain.AcknowledgedAnnotation))
+	recordDrainState(vts, "zone1-6", pod(drain.AcknowledgedAnnotation, drain.FinishedAnnotation))
+	recordDrainState(vts, "zone1-7", stuckPod)
+
+	assert.Equal(t, []string{"zone1-2"}, status.Draining)
+	assert.Equal(t, []string{"zone1-3", "zone1-7"}, status.Acknowledged)
+	assert.Equal(t, []string{"zone1-7"}, status.Stuck)
+	assert.Equal(t, []string{"zone1-4"}, status.Finished)
+	assert.Equal(t, []string{"zone1-5", "zone1-6"}, status.Aborted)
+}
+
+func TestUpdateDrainStatusAborted(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+
+	vts.Status.DrainStatus.Aborted = []string{"zone1-2", "zone1-1"}
+	updateDrainStatus(vts)
+	cond := vts.Status.Conditions[planetscalev2.VitessShardDrainAborted]
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, []string{"zone1-1", "zone1-2"}, vts.Status.DrainStatus.Aborted)
+
+	vts.Status.DrainStatus.Aborted = nil
+	updateDrainStatus(vts)
+	cond = vts.Status.Conditions[planetscalev2.VitessShardDrainAborted]
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+}
+
+func TestUpdateDrainStatusStuck(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	planetscalev2.DefaultVitessShard(vts)
+	vts.Status = planetscalev2.NewVitessShardStatus()
+
+	vts.Status.DrainStatus.Stuck = []string{"zone1-1"}
+	updateDrainStatus(vts)
+	cond := vts.Status.Conditions[planetscalev2.VitessShardDrainStuck]
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Equal(t, "DrainTimeoutExceeded", cond.Reason)
+
+	vts.Status.DrainStatus.Stuck = nil
+	updateDrainStatus(vts)
+	cond = vts.Status.Conditions[planetscalev2.VitessShardDrainStuck]
+	assert.Equal(t, corev1.ConditionFalse, cond.Status)
+}
+
+func TestUpdateDrainStatus(t *testing.T) {
+	readyReplica := planetscalev2.VitessTabletStatus{Ready: corev1.ConditionTrue, Type: "replica"}
+	notReadyReplica := planetscalev2.VitessTabletStatus{Ready: corev1.ConditionFalse, Type: "replica"}
+	readyRdonly := planetscalev2.VitessTabletStatus{Ready: corev1.ConditionTrue, Type: "rdonly"}
+	primary := planetscalev2.VitessTabletStatus{Ready: corev1.ConditionTrue, Type: "primary"}
+
+	tests := []struct {
+		name        string
+		hasMaster   corev1.ConditionStatus
+		tablets     map[string]planetscalev2.VitessTabletStatus
+		draining    []string
+		finished    []string
+		wantPending corev1.ConditionStatus
+		wantBlocked corev1.ConditionStatus
+	}{
+		{
+			name:        "unknown primary",
+			hasMaster:   corev1.ConditionUnknown,
+			tablets:     map[string]planetscalev2.VitessTabletStatus{"zone1-1": primary},
+			draining:    []string{"zone1-1"},
+			wantPending: corev1.ConditionUnknown,
+			wantBlocked: corev1.ConditionUnknown,
+		},
+		{
+			name:        "primary not draining",
+			hasMaster:   corev1.ConditionTrue,
+			tablets:     map[string]planetscalev2.VitessTabletStatus{"zone1-1": primary, "zone1-2": readyReplica},
+			draining:    []string{"zone1-2"},
+			wantPending: corev1.ConditionFalse,
+			wantBlocked: corev1.ConditionFalse,
+		},
+		{
+			name:        "primary draining with candidate",
+			hasMaster:   corev1.ConditionTrue,
+			tablets:     map[string]planetscalev2.VitessTabletStatus{"zone1-1": primary, "zone1-2": readyReplica},
+			draining:    []string{"zone1-1"},
+			wantPending: corev1.ConditionTrue,
+			wantBlocked: corev1.ConditionFalse,
+		},
+		{
+			name:      "primary draining without candidate",
+			hasMaster: corev1.ConditionTrue,
+			tablets: map[string]planetscalev2.VitessTabletStatus{
+				"zone1-1": primary,
+				"zone1-2": notReadyReplica,
+				"zone1-3": readyRdonly,
+				"zone1-4": readyReplica,
+			},
+			draining:    []string{"zone1-1"},
+			finished:    []string{"zone1-4"},
+			wantPending: corev1.ConditionTrue,
+			wantBlocked: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vts := &planetscalev2.VitessShard{}
+			vts.Status = planetscalev2.NewVitessShardStatus()
+			vts.Status.HasMaster = tt.hasMaster
+			vts.Status.MasterAlias = "zone1-1"
+			vts.Status.Tablets = tt.tablets
+			vts.Status.DrainStatus.Draining = tt.draining
+			vts.Status.DrainStatus.Finished = tt.finished
+
+			updateDrainStatus(vts)
+
+			assert.Equal(t, tt.wantPending, vts.Status.DrainStatus.PrimaryReparentPending)
+			assert.Equal(t, tt.wantBlocked, vts.Status.DrainStatus.PrimaryReparentBlocked)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// updateErrantGTIDCondition sets the ErrantGTIDsDetected condition based on
+// the errant GTIDs reported for each tablet. The condition is removed if
+// errant GTID checks are disabled.
+func updateErrantGTIDCondition(vts *planetscalev2.VitessShard) {
+	if vts.Spec.Replication.ErrantGTIDPolicy == "" {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardErrantGTIDsDetected)
+		return
+	}
+
+	var affected []string
+	for tabletAlias, tablet := range vts.Status.Tablets {
+		if tablet.ErrantGTIDs != "" {
+			affected = append(affected, tabletAlias)
+		}
+	}
+	if len(affected) > 0 {
+		sort.Strings(affected)
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardErrantGTIDsDetected, corev1.ConditionTrue, "ErrantGTIDsFound",
+			fmt.Sprintf("Tablets have transactions that the primary doesn't have: %v", strings.Join(affected, ", ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardErrantGTIDsDetected, corev1.ConditionFalse, "NoErrantGTIDs",
+		"No tablets have transactions that the primary doesn't have")
+}
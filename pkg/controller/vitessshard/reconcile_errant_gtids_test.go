@@ -0,0 +1,48 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateErrantGTIDCondition(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+	vts.Status.Tablets["zone1-1"] = planetscalev2.VitessTabletStatus{}
+	vts.Status.Tablets["zone1-2"] = planetscalev2.VitessTabletStatus{ErrantGTIDs: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:1"}
+
+	// Disabled checks don't report a condition.
+	updateErrantGTIDCondition(vts)
+	_, ok := vts.Status.Conditions[planetscalev2.VitessShardErrantGTIDsDetected]
+	assert.False(t, ok)
+
+	vts.Spec.Replication.ErrantGTIDPolicy = planetscalev2.AlertOnlyErrantGTIDPolicy
+	updateErrantGTIDCondition(vts)
+	cond := vts.Status.Conditions[planetscalev2.VitessShardErrantGTIDsDetected]
+	assert.Equal(t, corev1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "zone1-2")
+
+	vts.Status.Tablets["zone1-2"] = planetscalev2.VitessTabletStatus{}
+	updateErrantGTIDCondition(vts)
+	assert.Equal(t, corev1.ConditionFalse, vts.Status.Conditions[planetscalev2.VitessShardErrantGTIDsDetected].Status)
+}
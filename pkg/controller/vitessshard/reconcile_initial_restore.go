@@ -0,0 +1,54 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// updateInitialRestoreCondition sets the InitialRestoreComplete condition once
+// a shard that's bootstrapped from its initialRestore has a primary, based on
+// the last observed HasMaster status. Once set, the condition stays True even
+// if the shard later loses its primary, so tablets don't switch back to the
+// source backups. The condition is removed if there's no initialRestore.
+func updateInitialRestoreCondition(vts *planetscalev2.VitessShard, hasMaster corev1.ConditionStatus) {
+	restore := vts.Spec.InitialRestore
+	if restore == nil {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardInitialRestoreComplete)
+		return
+	}
+	if vts.Status.InitialRestoreComplete() {
+		return
+	}
+
+	if vts.Spec.Standby != nil {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardInitialRestoreComplete, corev1.ConditionFalse, "Standby",
+			fmt.Sprintf("Following backups of cluster %v as a standby", restore.ClusterName))
+		return
+	}
+	if hasMaster == corev1.ConditionTrue {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardInitialRestoreComplete, corev1.ConditionTrue, "Restored",
+			fmt.Sprintf("Shard was restored from backups of cluster %v; tablets now use this cluster's own backups", restore.ClusterName))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardInitialRestoreComplete, corev1.ConditionFalse, "Restoring",
+		fmt.Sprintf("Waiting for tablets to restore from backups of cluster %v", restore.ClusterName))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateInitialRestoreCondition(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+
+	// Without an initialRestore, there's no condition.
+	updateInitialRestoreCondition(vts, corev1.ConditionFalse)
+	_, ok := vts.Status.Conditions[planetscalev2.VitessShardInitialRestoreComplete]
+	assert.False(t, ok)
+
+	vts.Spec.InitialRestore = &planetscalev2.VitessInitialRestoreSpec{ClusterName: "source"}
+	updateInitialRestoreCondition(vts, corev1.ConditionFalse)
+	assert.Equal(t, corev1.ConditionFalse, vts.Status.Conditions[planetscalev2.VitessShardInitialRestoreComplete].Status)
+	assert.False(t, vts.Status.InitialRestoreComplete())
+
+	updateInitialRestoreCondition(vts, corev1.ConditionTrue)
+	assert.True(t, vts.Status.InitialRestoreComplete())
+
+	// Losing the primary later doesn't undo it.
+	updateInitialRestoreCondition(vts, corev1.ConditionFalse)
+	assert.True(t, vts.Status.InitialRestoreComplete())
+
+	// A standby shard never completes its initial restore.
+	standby := &planetscalev2.VitessShard{}
+	standby.Status = planetscalev2.NewVitessShardStatus()
+	standby.Spec.InitialRestore = &planetscalev2.VitessInitialRestoreSpec{ClusterName: "source"}
+	standby.Spec.Standby = &planetscalev2.VitessStandbySpec{ClusterName: "source"}
+	updateInitialRestoreCondition(standby, corev1.ConditionTrue)
+	assert.Equal(t, "Standby", standby.Status.Conditions[planetscalev2.VitessShardInitialRestoreComplete].Reason)
+	assert.False(t, standby.Status.InitialRestoreComplete())
+
+	// Removing the initialRestore removes the condition.
+	vts.Spec.InitialRestore = nil
+	updateInitialRestoreCondition(vts, corev1.ConditionTrue)
+	_, ok = vts.Status.Conditions[planetscalev2.VitessShardInitialRestoreComplete]
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// updateMysqldConfigCondition sets the MysqldConfigValid condition based on
+// whether all the mysqld config settings of the shard's tablet pools can be
+// applied, including auto-tuned ones, query logs, and audit logs. The
+// condition is removed if no pool has any of those.
+func updateMysqldConfigCondition(vts *planetscalev2.VitessShard) {
+	configured := false
+	var problems []string
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.Mysqld == nil || (len(pool.Mysqld.Config) == 0 && !pool.AutoTuneMysql && pool.Mysqld.QueryLogs == nil && pool.Mysqld.AuditLog == nil) {
+			continue
+		}
+		configured = true
+		var tuned map[string]string
+		var poolProblems []string
+		if pool.Mysqld.QueryLogs != nil {
+			poolProblems = append(poolProblems, pool.Mysqld.QueryLogs.Problems()...)
+		}
+		if pool.Mysqld.AuditLog != nil {
+			poolProblems = append(poolProblems, pool.Mysqld.AuditLog.Problems()...)
+		}
+		if pool.AutoTuneMysql {
+			var err error
+			if tuned, err = vttablet.AutoTuneMysqld(pool.Mysqld, pool.Vttablet.QueryServing); err != nil {
+				poolProblems = append(poolProblems, err.Error())
+			}
+		}
+		_, configProblems := vttablet.RenderMysqldConfig(pool.Mysqld, tuned)
+		poolProblems = append(poolProblems, configProblems...)
+		for _, problem := range poolProblems {
+			problems = append(problems, fmt.Sprintf("pool %v: %v", poolDescription(pool), problem))
+		}
+	}
+
+	if !configured {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardMysqldConfigValid)
+		return
+	}
+	if len(problems) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardMysqldConfigValid, corev1.ConditionFalse, "InvalidSettings",
+			fmt.Sprintf("Some mysqld config settings were left out: %v", strings.Join(problems, "; ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardMysqldConfigValid, corev1.ConditionTrue, "ValidSettings",
+		"All mysqld config settings are applied")
+}
+
+// updateMysqldConfigDriftCondition sets the MysqldConfigDriftDetected
+// condition based on the config drift reported for each tablet. The condition
+// is removed if config drift checks are disabled.
+func updateMysqldConfigDriftCondition(vts *planetscalev2.VitessShard) {
+	if vts.Spec.MysqldConfigDriftPolicy == "" {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardMysqldConfigDriftDetected)
+		return
+	}
+
+	var affected []string
+	for tabletAlias, tablet := range vts.Status.Tablets {
+		if tablet.MysqldConfigDrift != "" {
+			affected = append(affected, tabletAlias)
+		}
+	}
+	if len(affected) > 0 {
+		sort.Strings(affected)
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardMysqldConfigDriftDetected, corev1.ConditionTrue, "DriftFound",
+			fmt.Sprintf("Tablets have MySQL global variables that don't match their mysqld config: %v", strings.Join(affected, ", ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardMysqldConfigDriftDetected, corev1.ConditionFalse, "NoDrift",
+		"All tablets have MySQL global variables that match their mysqld config")
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileMysqldExporter makes sure the shard has a Secret with the password
+// of the monitoring user if any tablet pool's exporter uses it, and a
+// ServiceMonitor if any pool asks for one.
+func (r *ReconcileVitessShard) reconcileMysqldExporter(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	key := client.ObjectKey{
+		Namespace: vts.Namespace,
+		Name:      vttablet.MysqldExporterName(clusterName, keyspaceName, vts.Spec.KeyRange),
+	}
+
+	// Generate a password only if there's no Secret yet.
+	wanted := vts.Spec.MysqldExporterMonitoringUser()
+	password := ""
+	if wanted {
+		err := r.client.Get(ctx, key, &corev1.Secret{})
+		switch {
+		case apierrors.IsNotFound(err):
+			password, err = vttablet.NewMysqldExporterPassword()
+			if err != nil {
+				return resultBuilder.Error(err)
+			}
+		case err != nil:
+			return resultBuilder.Error(err)
+		}
+	}
+	err := r.reconciler.ReconcileObject(ctx, vts, key, labels, wanted, reconciler.Strategy{
+		Kind: &corev1.Secret{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return vttablet.NewMysqldExporterSecret(key, labels, password)
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			update.Labels(&obj.(*corev1.Secret).Labels, labels)
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	endpoints := mysqldExporterServiceMonitorEndpoints(vts, labels)
+	monitorLabels := map[string]string{}
+	for i := range vts.Spec.TabletPools {
+		if exporter := vts.Spec.TabletPools[i].MysqldExporter; exporter != nil && exporter.ServiceMonitor != nil {
+			update.Labels(&monitorLabels, exporter.ServiceMonitor.Labels)
+		}
+	}
+	// Our own labels win over user-provided ones.
+	update.Labels(&monitorLabels, labels)
+	serviceLabels := map[string]string{
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+	}
+	if err := r.reconcileMysqldExporterServiceMonitor(ctx, vts, key, labels, len(endpoints) > 0, func(obj *unstructured.Unstructured) {
+		vttablet.UpdateServiceMonitor(obj, monitorLabels, serviceLabels, endpoints)
+	}); err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// reconcileMysqldExporterServiceMonitor reconciles the ServiceMonitor of a
+// shard, if the Kubernetes cluster serves ServiceMonitors.
+func (r *ReconcileVitessShard) reconcileMysqldExporterServiceMonitor(ctx context.Context, vts *planetscalev2.VitessShard, key client.ObjectKey, labels map[string]string, wanted bool, updateServiceMonitor func(obj *unstructured.Unstructured)) error {
+	gvk := vttablet.ServiceMonitorGVK
+	if _, err := r.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return err
+		}
+		// There can't be any ServiceMonitors to clean up.
+		if wanted {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrometheusOperatorUnavailable", "Can't create mysqld-exporter %v because the Prometheus Operator (%v) isn't installed.", gvk.Kind, gvk.GroupVersion())
+		}
+		return nil
+	}
+
+	return r.reconciler.ReconcileObject(ctx, vts, key, labels, wanted, reconciler.Strategy{
+		Kind: vttablet.NewServiceMonitor(client.ObjectKey{}),
+
+		New: func(key client.ObjectKey) runtime.Object {
+			obj := vttablet.NewServiceMonitor(key)
+			updateServiceMonitor(obj)
+			return obj
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			updateServiceMonitor(obj.(*unstructured.Unstructured))
+		},
+	})
+}
+
+// mysqldExporterServiceMonitorEndpoints returns a ServiceMonitor endpoint for
+// each tablet pool with a local MySQL that asks for one.
+func mysqldExporterServiceMonitorEndpoints(vts *planetscalev2.VitessShard, shardLabels map[string]string) []vttablet.ServiceMonitorEndpoint {
+	var endpoints []vttablet.ServiceMonitorEndpoint
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.Mysqld == nil || pool.MysqldExporter == nil || pool.MysqldExporter.ServiceMonitor == nil {
+			continue
+		}
+		podLabels := make(map[string]string, len(shardLabels)+3)
+		for k, v := range shardLabels {
+			podLabels[k] = v
+		}
+		podLabels[planetscalev2.CellLabel] = pool.Cell
+		podLabels[planetscalev2.TabletTypeLabel] = string(pool.Type)
+		// Unnamed pools must not match named pools of the same cell and type.
+		podLabels[planetscalev2.TabletPoolNameLabel] = pool.Name
+
+		endpoints = append(endpoints, vttablet.ServiceMonitorEndpoint{
+			PodLabels: podLabels,
+			Interval:  pool.MysqldExporter.ServiceMonitor.Interval,
+		})
+	}
+	return endpoints
+}
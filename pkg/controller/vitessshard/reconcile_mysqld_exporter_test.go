@@ -0,0 +1,83 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestMysqldExporterServiceMonitorEndpoints(t *testing.T) {
+	shardLabels := map[string]string{planetscalev2.ShardLabel: "x-80"}
+	serviceMonitor := &planetscalev2.MysqldExporterServiceMonitor{Interval: "30s"}
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{
+			Cell:           "zone1",
+			Type:           "replica",
+			Mysqld:         &planetscalev2.MysqldSpec{},
+			MysqldExporter: &planetscalev2.MysqldExporterSpec{ServiceMonitor: serviceMonitor},
+		},
+		{
+			// Pools without a ServiceMonitor aren't scraped.
+			Cell:           "zone1",
+			Type:           "rdonly",
+			Mysqld:         &planetscalev2.MysqldSpec{},
+			MysqldExporter: &planetscalev2.MysqldExporterSpec{},
+		},
+		{
+			// Pools with an external datastore have no exporter.
+			Cell:              "zone1",
+			Type:              "externalmaster",
+			ExternalDatastore: &planetscalev2.ExternalDatastore{},
+			MysqldExporter:    &planetscalev2.MysqldExporterSpec{ServiceMonitor: serviceMonitor},
+		},
+		{
+			Cell:           "zone2",
+			Type:           "replica",
+			Name:           "big",
+			Mysqld:         &planetscalev2.MysqldSpec{},
+			MysqldExporter: &planetscalev2.MysqldExporterSpec{ServiceMonitor: &planetscalev2.MysqldExporterServiceMonitor{}},
+		},
+	}
+
+	assert.Equal(t, []vttablet.ServiceMonitorEndpoint{
+		{
+			PodLabels: map[string]string{
+				planetscalev2.ShardLabel:          "x-80",
+				planetscalev2.CellLabel:           "zone1",
+				planetscalev2.TabletTypeLabel:     "replica",
+				planetscalev2.TabletPoolNameLabel: "",
+			},
+			Interval: "30s",
+		},
+		{
+			PodLabels: map[string]string{
+				planetscalev2.ShardLabel:          "x-80",
+				planetscalev2.CellLabel:           "zone2",
+				planetscalev2.TabletTypeLabel:     "replica",
+				planetscalev2.TabletPoolNameLabel: "big",
+			},
+		},
+	}, mysqldExporterServiceMonitorEndpoints(vts, shardLabels))
+	assert.False(t, vts.Spec.MysqldExporterMonitoringUser())
+}
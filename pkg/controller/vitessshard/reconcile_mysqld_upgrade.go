@@ -0,0 +1,233 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// crashLoopBackOffReason is the reason Kubernetes gives for a container
+	// that's waiting to be restarted after crashing repeatedly.
+	crashLoopBackOffReason = "CrashLoopBackOff"
+)
+
+// reconcileMysqldUpgrade guides a MySQL major-version upgrade of the shard,
+// such as from 5.7 to 8.0, and records its progress in the shard status.
+//
+// Fast shutdown is disabled by the replication controller when each tablet is
+// drained for the upgrade. Here, we make sure replicas are upgraded one at a
+// time, that each one comes up on the new version before the next one is
+// released, and that the primary is upgraded last, once it's been reparented
+// onto an upgraded tablet. If a tablet fails to come up on the new version,
+// the upgrade is halted.
+//
+// Like reconcileCanary, it returns the set of tablets that may be released for
+// update, or nil if every tablet may be, along with what the rollout is
+// waiting for, if anything.
+func (r *ReconcileVitessShard) reconcileMysqldUpgrade(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, primaryAlias string, now time.Time) (allowed map[string]bool, waiting string) {
+	if vts.Spec.UsingExternalDatastore() || vts.Spec.Images.Mysqld == nil {
+		return nil, ""
+	}
+	image := vts.Spec.Images.Mysqld.Image()
+	toVersion := vttablet.MysqldVersionSeries(image)
+
+	status := vts.Status.UpgradeStatus
+	if status == nil || status.Image != image {
+		fromVersion := mysqldMajorUpgradeFrom(tabletPods, toVersion)
+		if fromVersion == "" {
+			// There's no major-version upgrade to guide. Keep the record of
+			// the last one, unless it was abandoned part way.
+			if status != nil && status.Phase != planetscalev2.UpgradeCompletePhase {
+				vts.Status.UpgradeStatus = nil
+			}
+			return nil, ""
+		}
+		status = &planetscalev2.VitessShardUpgradeStatus{
+			Image:       image,
+			FromVersion: fromVersion,
+			ToVersion:   toVersion,
+			Phase:       planetscalev2.UpgradingReplicasPhase,
+			Primary:     primaryAlias,
+			StartTime:   metav1.Time{Time: now},
+		}
+		vts.Status.UpgradeStatus = status
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeStarted", "Upgrading MySQL from %v to %v, one tablet at a time.", fromVersion, toVersion)
+	}
+	if status.Phase == planetscalev2.UpgradeCompletePhase {
+		return nil, ""
+	}
+
+	oldPhase := status.Phase
+	status.Steps = mysqldUpgradeSteps(vts, tabletPods, status)
+	var next, failed *planetscalev2.VitessTabletUpgradeStep
+	upgrading := ""
+	for i := range status.Steps {
+		step := &status.Steps[i]
+		switch step.Phase {
+		case planetscalev2.TabletUpgradeFailedPhase:
+			if failed == nil {
+				failed = step
+			}
+		case planetscalev2.TabletUpgradingPhase:
+			if upgrading == "" {
+				upgrading = step.Tablet
+			}
+		case planetscalev2.TabletUpgradePendingPhase:
+			if next == nil {
+				next = step
+			}
+		}
+	}
+
+	// Only the next tablet in line may be released, and only once no other
+	// tablet is in the middle of upgrading.
+	allowed = map[string]bool{}
+	switch {
+	case failed != nil:
+		status.Phase = planetscalev2.UpgradeFailedPhase
+		waiting = fmt.Sprintf("MySQL upgrade is halted because tablet %v failed to start on %v: %v", failed.Tablet, status.ToVersion, failed.Message)
+		if oldPhase != status.Phase {
+			r.recorder.Event(vts, corev1.EventTypeWarning, "MysqlUpgradeFailed", waiting)
+		}
+		return allowed, waiting
+	case upgrading != "":
+		status.Phase = mysqldUpgradePhase(status, upgrading, primaryAlias)
+		waiting = fmt.Sprintf("Waiting for tablet %v to come up on MySQL %v.", upgrading, status.ToVersion)
+	case next != nil:
+		status.Phase = mysqldUpgradePhase(status, next.Tablet, primaryAlias)
+		allowed[next.Tablet] = true
+	default:
+		status.Phase = planetscalev2.UpgradeCompletePhase
+		status.CompletionTime = &metav1.Time{Time: now}
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeComplete", "All tablets came up on MySQL %v.", status.ToVersion)
+		return nil, ""
+	}
+	if oldPhase != status.Phase && status.Phase == planetscalev2.ReparentingPhase {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUpgradeReparenting", "All replicas came up on MySQL %v. Reparenting away from primary %v.", status.ToVersion, status.Primary)
+	}
+	return allowed, waiting
+}
+
+// mysqldMajorUpgradeFrom returns the MySQL version series that tablets are
+// being upgraded from, if any tablet Pod runs a different version series than
+// toVersion. It returns an empty string if there's no major-version upgrade
+// to guide, including when either version is unknown.
+func mysqldMajorUpgradeFrom(tabletPods map[string]*corev1.Pod, toVersion string) string {
+	if toVersion == "" {
+		return ""
+	}
+	for _, pod := range tabletPods {
+		fromVersion := vttablet.MysqldVersionSeries(vttablet.MysqldImage(pod))
+		if fromVersion != "" && fromVersion != toVersion {
+			return fromVersion
+		}
+	}
+	return ""
+}
+
+// mysqldUpgradePhase returns the upgrade phase while the given tablet is next
+// in line or being upgraded.
+func mysqldUpgradePhase(status *planetscalev2.VitessShardUpgradeStatus, tabletKey, primaryAlias string) planetscalev2.VitessShardUpgradePhase {
+	switch {
+	case tabletKey != status.Primary:
+		return planetscalev2.UpgradingReplicasPhase
+	case primaryAlias == status.Primary:
+		return planetscalev2.ReparentingPhase
+	}
+	return planetscalev2.UpgradingPrimaryPhase
+}
+
+// mysqldUpgradeSteps returns the progress of upgrading each tablet that runs
+// mysqld, in the order they're upgraded: canary tablets first, if there are
+// any, then the other replicas, and finally the tablet that was primary when
+// the upgrade started.
+func mysqldUpgradeSteps(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, status *planetscalev2.VitessShardUpgradeStatus) []planetscalev2.VitessTabletUpgradeStep {
+	order := map[string]int{}
+	if canary := vts.Status.Canary; canary != nil {
+		for _, tabletKey := range canary.Tablets {
+			order[tabletKey] = -1
+		}
+	}
+	order[status.Primary] = 1
+
+	var tabletKeys []string
+	for _, tabletKey := range vts.Status.TabletAliases() {
+		if pod, ok := tabletPods[tabletKey]; ok && vttablet.MysqldImage(pod) == "" {
+			// This tablet doesn't run mysqld.
+			continue
+		}
+		tabletKeys = append(tabletKeys, tabletKey)
+	}
+	sort.SliceStable(tabletKeys, func(i, j int) bool {
+		return order[tabletKeys[i]] < order[tabletKeys[j]]
+	})
+
+	steps := make([]planetscalev2.VitessTabletUpgradeStep, 0, len(tabletKeys))
+	for _, tabletKey := range tabletKeys {
+		phase, message := mysqldUpgradeTabletPhase(vts.Status.Tablets[tabletKey], tabletPods[tabletKey], status.ToVersion)
+		steps = append(steps, planetscalev2.VitessTabletUpgradeStep{
+			Tablet:  tabletKey,
+			Phase:   phase,
+			Message: message,
+		})
+	}
+	return steps
+}
+
+// mysqldUpgradeTabletPhase returns where a tablet is at in upgrading to the
+// given MySQL version series, and why, if there's more to say.
+func mysqldUpgradeTabletPhase(tablet planetscalev2.VitessTabletStatus, pod *corev1.Pod, toVersion string) (planetscalev2.VitessTabletUpgradePhase, string) {
+	switch {
+	case pod == nil:
+		return planetscalev2.TabletUpgradingPhase, "Pod is being recreated."
+	case vttablet.MysqldVersionSeries(vttablet.MysqldImage(pod)) != toVersion:
+		if rollout.Released(pod) || pod.DeletionTimestamp != nil {
+			return planetscalev2.TabletUpgradingPhase, "Pod is being restarted."
+		}
+		return planetscalev2.TabletUpgradePendingPhase, ""
+	}
+
+	// The tablet runs the new version. If mysqld can't upgrade the data
+	// directory, it exits, so the container keeps crashing.
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if status.Name != vttablet.MysqldContainerName {
+			continue
+		}
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == crashLoopBackOffReason {
+			message := fmt.Sprintf("mysqld keeps crashing after %d restarts", status.RestartCount)
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Message != "" {
+				message = fmt.Sprintf("%v: %v", message, terminated.Message)
+			}
+			return planetscalev2.TabletUpgradeFailedPhase, message
+		}
+	}
+	if tablet.Ready != corev1.ConditionTrue {
+		return planetscalev2.TabletUpgradingPhase, "Waiting for the tablet to become Ready."
+	}
+	return planetscalev2.TabletUpgradeSucceededPhase, ""
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestReconcileMysqldUpgrade(t *testing.T) {
+	const (
+		oldImage = "mysql:5.7.44"
+		newImage = "mysql:8.0.36"
+	)
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.Images.Mysqld = &planetscalev2.MysqldImage{Mysql80Compatible: newImage}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {PoolType: "replica", Ready: corev1.ConditionTrue},
+		"zone1-2": {PoolType: "replica", Ready: corev1.ConditionTrue},
+		"zone1-3": {PoolType: "rdonly", Ready: corev1.ConditionTrue},
+	}
+	newPod := func(image string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: vttablet.MysqldContainerName, Image: image}}}}
+	}
+	pods := map[string]*corev1.Pod{
+		"zone1-1": newPod(oldImage),
+		"zone1-2": newPod(oldImage),
+		"zone1-3": newPod(oldImage),
+	}
+
+	// Replicas go first, one at a time.
+	allowed, waiting := r.reconcileMysqldUpgrade(vts, pods, "zone1-1", start)
+	assert.Equal(t, map[string]bool{"zone1-2": true}, allowed)
+	assert.Empty(t, waiting)
+	status := vts.Status.UpgradeStatus
+	assert.Equal(t, "5.7", status.FromVersion)
+	assert.Equal(t, "8.0", status.ToVersion)
+	assert.Equal(t, planetscalev2.UpgradingReplicasPhase, status.Phase)
+	assert.Equal(t, []string{"zone1-2", "zone1-3", "zone1-1"}, upgradeStepTablets(status))
+
+	// Nothing else is released until the tablet comes up on the new version.
+	rollout.Release(pods["zone1-2"])
+	allowed, waiting = r.reconcileMysqldUpgrade(vts, pods, "zone1-1", start)
+	assert.Empty(t, allowed)
+	assert.NotEmpty(t, waiting)
+	assert.Equal(t, planetscalev2.TabletUpgradingPhase, status.Steps[0].Phase)
+
+	// If mysqld can't start on the new version, the upgrade is halted.
+	pods["zone1-2"] = newPod(newImage)
+	pods["zone1-2"].Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         vttablet.MysqldContainerName,
+		RestartCount: 3,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+	}}
+	allowed, waiting = r.reconcileMysqldUpgrade(vts, pods, "zone1-1", start)
+	assert.Empty(t, allowed)
+	assert.Contains(t, waiting, "failed to start")
+	assert.Equal(t, planetscalev2.UpgradeFailedPhase, status.Phase)
+	assert.Equal(t, planetscalev2.TabletUpgradeFailedPhase, status.Steps[0].Phase)
+
+	// Once it's up, the next replica is released.
+	pods["zone1-2"] = newPod(newImage)
+	allowed, _ = r.reconcileMysqldUpgrade(vts, pods, "zone1-1", start)
+	assert.Equal(t, map[string]bool{"zone1-3": true}, allowed)
+	assert.Equal(t, planetscalev2.UpgradingReplicasPhase, status.Phase)
+
+	// With every replica done, the primary is drained so it gets reparented.
+	pods["zone1-3"] = newPod(newImage)
+	allowed, _ = r.reconcileMysqldUpgrade(vts, pods, "zone1-1", start)
+	assert.Equal(t, map[string]bool{"zone1-1": true}, allowed)
+	assert.Equal(t, planetscalev2.ReparentingPhase, status.Phase)
+
+	// Once an upgraded tablet has taken over, the old primary is upgraded.
+	rollout.Release(pods["zone1-1"])
+	r.reconcileMysqldUpgrade(vts, pods, "zone1-2", start)
+	assert.Equal(t, planetscalev2.UpgradingPrimaryPhase, status.Phase)
+
+	pods["zone1-1"] = newPod(newImage)
+	allowed, waiting = r.reconcileMysqldUpgrade(vts, pods, "zone1-2", start.Add(time.Hour))
+	assert.Nil(t, allowed)
+	assert.Empty(t, waiting)
+	assert.Equal(t, planetscalev2.UpgradeCompletePhase, status.Phase)
+	assert.Equal(t, start.Add(time.Hour), status.CompletionTime.Time)
+
+	// The record of the upgrade is kept after it's done.
+	r.reconcileMysqldUpgrade(vts, pods, "zone1-2", start.Add(2*time.Hour))
+	assert.Equal(t, status, vts.Status.UpgradeStatus)
+}
+
+func TestReconcileMysqldUpgradeMinorVersion(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.Images.Mysqld = &planetscalev2.MysqldImage{Mysql80Compatible: "mysql:8.0.36"}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{"zone1-1": {}}
+	pods := map[string]*corev1.Pod{
+		"zone1-1": {Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: vttablet.MysqldContainerName, Image: "mysql:8.0.35"}}}},
+	}
+
+	allowed, waiting := r.reconcileMysqldUpgrade(vts, pods, "zone1-1", time.Now())
+	assert.Nil(t, allowed)
+	assert.Empty(t, waiting)
+	assert.Nil(t, vts.Status.UpgradeStatus)
+}
+
+func upgradeStepTablets(status *planetscalev2.VitessShardUpgradeStatus) []string {
+	tablets := make([]string, 0, len(status.Steps))
+	for _, step := range status.Steps {
+		tablets = append(tablets, step.Tablet)
+	}
+	return tablets
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/pdb"
+	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileTabletPodDisruptionBudgets makes sure each tablet pool has a PDB,
+// unless it's been disabled, so evictions can't take down too many tablets in
+// the pool at once.
+func (r *ReconcileVitessShard) reconcileTabletPodDisruptionBudgets(ctx context.Context, vts *planetscalev2.VitessShard) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   clusterName,
+		planetscalev2.KeyspaceLabel:  keyspaceName,
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+
+	keys := make([]client.ObjectKey, 0, len(vts.Spec.TabletPools))
+	specMap := make(map[client.ObjectKey]*pdb.Spec, len(vts.Spec.TabletPools))
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if !pdb.Wanted(pool.PodDisruptionBudget) {
+			continue
+		}
+		key := client.ObjectKey{
+			Namespace: vts.Namespace,
+			Name:      vttablet.PDBName(clusterName, keyspaceName, vts.Spec.KeyRange, pool.Cell, pool.Type, pool.Name),
+		}
+		keys = append(keys, key)
+		specMap[key] = &pdb.Spec{
+			Labels:   labels,
+			Selector: tabletPoolSelector(labels, pool),
+			Replicas: vts.Status.PoolReplicas(pool),
+			Config:   pool.PodDisruptionBudget,
+		}
+	}
+
+	err := r.reconciler.ReconcileObjectSet(ctx, vts, keys, labels, reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return pdb.NewPodDisruptionBudget(key, specMap[key])
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			pdb.UpdatePodDisruptionBudget(obj.(*policyv1.PodDisruptionBudget), specMap[key])
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
+	return resultBuilder.Result()
+}
+
+// tabletPoolSelector returns a selector for the tablet Pods in a pool. Pools
+// without a name only have the cell and type labels, so they must also be told
+// apart from named pools of the same cell and type.
+func tabletPoolSelector(shardLabels map[string]string, pool *planetscalev2.VitessShardTabletPool) *metav1.LabelSelector {
+	matchLabels := make(map[string]string, len(shardLabels)+3)
+	for k, v := range shardLabels {
+		matchLabels[k] = v
+	}
+	matchLabels[planetscalev2.CellLabel] = pool.Cell
+	matchLabels[planetscalev2.TabletTypeLabel] = string(pool.Type)
+
+	selector := &metav1.LabelSelector{MatchLabels: matchLabels}
+	if pool.Name != "" {
+		matchLabels[planetscalev2.TabletPoolNameLabel] = pool.Name
+	} else {
+		selector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+			Key:      planetscalev2.TabletPoolNameLabel,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		}}
+	}
+	return selector
+}
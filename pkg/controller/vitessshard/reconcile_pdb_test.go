@@ -0,0 +1,72 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestTabletPoolSelector(t *testing.T) {
+	shardLabels := map[string]string{planetscalev2.ShardLabel: "x-80"}
+	unnamedPod := labels.Set{
+		planetscalev2.ShardLabel:      "x-80",
+		planetscalev2.CellLabel:       "zone1",
+		planetscalev2.TabletTypeLabel: "replica",
+	}
+	namedPod := labels.Set{
+		planetscalev2.ShardLabel:          "x-80",
+		planetscalev2.CellLabel:           "zone1",
+		planetscalev2.TabletTypeLabel:     "replica",
+		planetscalev2.TabletPoolNameLabel: "big",
+	}
+
+	tests := []struct {
+		name        string
+		pool        planetscalev2.VitessShardTabletPool
+		wantUnnamed bool
+		wantNamed   bool
+	}{
+		{
+			name:        "unnamed pool",
+			pool:        planetscalev2.VitessShardTabletPool{Cell: "zone1", Type: "replica"},
+			wantUnnamed: true,
+		},
+		{
+			name:      "named pool",
+			pool:      planetscalev2.VitessShardTabletPool{Cell: "zone1", Type: "replica", Name: "big"},
+			wantNamed: true,
+		},
+		{
+			name: "other cell",
+			pool: planetscalev2.VitessShardTabletPool{Cell: "zone2", Type: "replica"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := metav1.LabelSelectorAsSelector(tabletPoolSelector(shardLabels, &tt.pool))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUnnamed, selector.Matches(unnamedPod))
+			assert.Equal(t, tt.wantNamed, selector.Matches(namedPod))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// updateQueryServingCondition sets the QueryServingValid condition based on
+// whether vttablet accepts the queryServing settings of the shard's tablet
+// pools, and whether any of them are overridden by extra flags. The condition
+// is removed if no pool has queryServing settings.
+func updateQueryServingCondition(vts *planetscalev2.VitessShard) {
+	configured := false
+	var problems []string
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		queryServing := pool.Vttablet.QueryServing
+		if queryServing == nil {
+			continue
+		}
+		configured = true
+		for _, problem := range queryServingProblems(queryServing, vts.Spec.ExtraVitessFlags, pool.Vttablet.ExtraFlags) {
+			problems = append(problems, fmt.Sprintf("pool %v: %v", poolDescription(pool), problem))
+		}
+	}
+
+	if !configured {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardQueryServingValid)
+		return
+	}
+	if len(problems) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardQueryServingValid, corev1.ConditionFalse, "InvalidSettings",
+			fmt.Sprintf("Query serving settings won't take effect as written: %v", strings.Join(problems, "; ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardQueryServingValid, corev1.ConditionTrue, "ValidSettings",
+		"Query serving settings are valid")
+}
+
+// queryServingProblems returns the problems with a pool's query serving
+// settings, including any flags that extra flags override.
+func queryServingProblems(queryServing *planetscalev2.VttabletQueryServingSpec, extraFlagMaps ...map[string]string) []string {
+	problems := queryServing.Problems()
+
+	overridden := map[string]bool{}
+	for flag := range vttablet.QueryServingFlags(queryServing) {
+		for _, extraFlags := range extraFlagMaps {
+			for key := range extraFlags {
+				if normalizeFlagName(key) == normalizeFlagName(flag) {
+					overridden[flag] = true
+				}
+			}
+		}
+	}
+	flags := make([]string, 0, len(overridden))
+	for flag := range overridden {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		problems = append(problems, fmt.Sprintf("extra flags override %v", flag))
+	}
+	return problems
+}
+
+// normalizeFlagName returns a flag name without leading dashes, and with
+// underscores in place of dashes, since vttablet accepts either.
+func normalizeFlagName(name string) string {
+	return strings.ReplaceAll(strings.TrimLeft(name, "-"), "-", "_")
+}
+
+// poolDescription returns a short description of a tablet pool for messages.
+func poolDescription(pool *planetscalev2.VitessShardTabletPool) string {
+	if pool.Name != "" {
+		return fmt.Sprintf("%v/%v/%v", pool.Cell, pool.Type, pool.Name)
+	}
+	return fmt.Sprintf("%v/%v", pool.Cell, pool.Type)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateQueryServingCondition(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType},
+	}
+
+	// Without queryServing settings, there's no condition.
+	updateQueryServingCondition(vts)
+	_, ok := vts.Status.Conditions[planetscalev2.VitessShardQueryServingValid]
+	assert.False(t, ok)
+
+	pool := &vts.Spec.TabletPools[0]
+	pool.Vttablet.QueryServing = &planetscalev2.VttabletQueryServingSpec{PoolSize: pointer.Int32Ptr(32)}
+	updateQueryServingCondition(vts)
+	assert.Equal(t, corev1.ConditionTrue, vts.Status.Conditions[planetscalev2.VitessShardQueryServingValid].Status)
+
+	// Extra flags that override the settings are reported, however they're spelled.
+	vts.Spec.ExtraVitessFlags = map[string]string{"-queryserver_config_pool_size": "64"}
+	updateQueryServingCondition(vts)
+	condition := vts.Status.Conditions[planetscalev2.VitessShardQueryServingValid]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Contains(t, condition.Message, "zone1/replica: extra flags override queryserver-config-pool-size")
+}
@@ -2,6 +2,10 @@ package vitessshard
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
@@ -12,6 +16,7 @@ import (
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/rollout"
 	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
@@ -22,6 +27,7 @@ func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planet
 
 	if !rollout.Cascading(vts) {
 		// If the shard is not scheduled for a cascading update, silently bail out and do nothing.
+		resetRolloutBackoff(vts)
 		return resultBuilder.Result()
 	}
 
@@ -30,64 +36,156 @@ func (r *ReconcileVitessShard) reconcileRollout(ctx context.Context, vts *planet
 		return resultBuilder.Error(err)
 	}
 
-	tabletKeys := vts.Status.TabletAliases()
+	primaryAlias, err := getPrimaryTabletAlias(ctx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RolloutBlocked", "Could not get TabletAlias for the Primary.")
+		return resultBuilder.Error(err)
+	}
 
-	for _, tabletKey := range tabletKeys {
-		tablet := vts.Status.Tablets[tabletKey]
-		if tablet.Available != corev1.ConditionTrue {
-			// If any tablets are unhealthy, we should bail and not perform a rolling restart.
-			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for tablet %v to be Available.", tabletKey)
-			return resultBuilder.Result()
-		}
+	// If the update strategy calls for a canary, only the canary tablets may
+	// be released until they've soaked on the new vttablet image.
+	canaryTablets, canaryWaiting, soakRemaining := r.reconcileCanary(vts, tabletPods, primaryAlias, time.Now())
+	if soakRemaining > 0 {
+		resultBuilder.RequeueAfter(soakRemaining)
+	}
 
-		pod, ok := tabletPods[tabletKey]
-		if !ok {
-			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for desired tablet %v to be created.", tabletKey)
-			return resultBuilder.Result()
-		}
+	// A MySQL major-version upgrade is guided one tablet at a time.
+	upgradeTablets, upgradeWaiting := r.reconcileMysqldUpgrade(vts, tabletPods, primaryAlias, time.Now())
+
+	// Start each image upgrade over with fresh pre-upgrade checks.
+	upgradePending := vttablet.ImageUpgradePending(tabletPods, &vts.Spec.Images)
+	if !upgradePending {
+		resetUpgradeChecks(vts)
+	}
+
+	// If updated tablet Pods keep crashing, stop updating tablets until they
+	// recover, and then back off for a while.
+	crashing, backoffWaiting, retryAfter := r.reconcileRolloutBackoff(vts, tabletPods, time.Now())
+	if crashing {
+		r.recorder.Event(vts, corev1.EventTypeNormal, "RolloutPaused", backoffWaiting)
+		return resultBuilder.Result()
+	}
 
-		if rollout.Released(pod) {
-			// If any tablet has already been released, we should wait until it is finished to release another one.
-			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for tablet %v to finish release.", tabletKey)
-			return resultBuilder.Result()
+	// Decide which tablet Pods to release during this reconcile.
+	plan := planRollout(vts, vts.Status.TabletAliases(), tabletPods, primaryAlias, intersectAllowed(canaryTablets, upgradeTablets))
+	if len(plan.release) == 0 {
+		switch {
+		case plan.waiting != "":
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", plan.waiting)
+		case plan.heldBack > 0 && upgradeWaiting != "":
+			r.recorder.Event(vts, corev1.EventTypeNormal, "RolloutPaused", upgradeWaiting)
+		case plan.heldBack > 0 && canaryWaiting != "":
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", canaryWaiting)
+		case plan.heldBack > 0:
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Pending changes for %d tablet(s) are held back by their pool's update strategy.", plan.heldBack)
+		default:
+			// If we have no more scheduled tablets, uncascade the shard.
+			if err := r.uncascadeShard(ctx, vts); err != nil {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "UncascadeFailed", "Failed to mark cascading shard rollout as complete: %v", err)
+				return resultBuilder.Error(err)
+			}
+			resetRolloutBackoff(vts)
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "RollingRestartComplete", "Cascading rollout of tablets is complete.")
 		}
+		return resultBuilder.Result()
 	}
 
-	primaryAlias, err := getPrimaryTabletAlias(ctx, vts)
+	// Releasing a tablet restarts it, so hold off while rollouts are paused.
+	if rolloutPaused(vts) {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Rollouts are paused. Not updating tablets.")
+		return resultBuilder.Result()
+	}
+	// Also wait for a maintenance window.
+	if !r.maintenanceWindowOpen(vts, resultBuilder, time.Now()) {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for a maintenance window to update tablets.")
+		return resultBuilder.Result()
+	}
+	// Also wait for the pre-upgrade checks, which the replication controller
+	// runs, before rolling out new images.
+	if upgradePending && vts.Spec.UpgradeChecksEnabled() && !vts.Status.UpgradeChecksPassed() {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Waiting for pre-upgrade checks to pass before updating tablets.")
+		return resultBuilder.Result()
+	}
+	if backoffWaiting != "" {
+		r.recorder.Event(vts, corev1.EventTypeNormal, "RolloutPaused", backoffWaiting)
+		return resultBuilder.RequeueAfter(retryAfter)
+	}
+	// Also don't keep updating tablets while the rollout is stuck elsewhere.
+	stuckShard, err := r.stuckShardInCluster(ctx, vts)
 	if err != nil {
-		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RolloutBlocked", "Could not get TabletAlias for the Primary.")
 		return resultBuilder.Error(err)
 	}
+	if stuckShard != "" {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RolloutPaused", "Rollout is stuck in shard %v. Not updating tablets until it recovers.", stuckShard)
+		return resultBuilder.RequeueAfter(stuckShardRequeueDelay)
+	}
 
-	// Retrieve tablet pod to be released during this reconcile.
-	tabletKey, pod := getNextScheduledTablet(tabletKeys, tabletPods, primaryAlias)
-	if tabletKey == "" {
-		// If we have no more scheduled tablets, uncascade the shard.
-		if err := r.uncascadeShard(ctx, vts); err != nil {
-			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UncascadeFailed", "Failed to mark cascading shard rollout as complete: %v", err)
-			return resultBuilder.Error(err)
+	masterEligibleTablets := vts.Spec.MasterEligibleTabletCount()
+	for _, tabletKey := range plan.release {
+		pod := tabletPods[tabletKey]
+		deletePod := false
+		tabletType := pod.Labels[planetscalev2.TabletTypeLabel]
+		// These two conditions guarantee that the tablet is a lone master.
+		if masterEligibleTablets < 2 &&
+			(tabletType == string(planetscalev2.ReplicaPoolType) || tabletType == string(planetscalev2.ExternalMasterPoolType)) {
+			// If we have a lone master, we must delete it since reparenting is impossible.
+			deletePod = true
 		}
 
-		r.recorder.Eventf(vts, corev1.EventTypeNormal, "RollingRestartComplete", "Cascading rollout of tablets is complete.")
-		return resultBuilder.Result()
+		if err := r.releaseTabletPod(ctx, pod, deletePod); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "RollingRestartBlocked", "release of Pod %v (tablet %v) failed: %v", pod.Name, tabletKey, err)
+			resultBuilder.Error(err)
+		}
 	}
 
-	masterEligibleTablets := vts.Spec.MasterEligibleTabletCount()
-	deletePod := false
-	tabletType := pod.Labels[planetscalev2.TabletTypeLabel]
-	// These two conditions guarantee that the tablet is a lone master.
-	if masterEligibleTablets < 2 &&
-		(tabletType == string(planetscalev2.ReplicaPoolType) || tabletType == string(planetscalev2.ExternalMasterPoolType)) {
-		// If we have a lone master, we must delete it since reparenting is impossible.
-		deletePod = true
+	return resultBuilder.Result()
+}
+
+// intersectAllowed returns the tablets that are in both sets of tablets that
+// may be released, where a nil set means every tablet may be.
+func intersectAllowed(a, b map[string]bool) map[string]bool {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	allowed := make(map[string]bool, len(a))
+	for tabletKey := range a {
+		if b[tabletKey] {
+			allowed[tabletKey] = true
+		}
 	}
+	return allowed
+}
+
+// rolloutPaused returns whether rollouts are paused for the shard, either by
+// the update strategy or with the rollout-paused annotation.
+func rolloutPaused(vts *planetscalev2.VitessShard) bool {
+	return vts.Spec.UpdateStrategy.Paused || rollout.Paused(vts)
+}
 
-	if err := r.releaseTabletPod(ctx, pod, deletePod); err != nil {
-		r.recorder.Eventf(vts, corev1.EventTypeWarning, "RollingRestartBlocked", "release of Pod %v (tablet %v) failed: %v", pod.Name, tabletKey, err)
-		resultBuilder.Error(err)
+// maintenanceWindowOpen returns whether disruptive changes may be made to the
+// shard now. If not, it makes sure we come back when the next maintenance
+// window opens.
+func (r *ReconcileVitessShard) maintenanceWindowOpen(vts *planetscalev2.VitessShard, resultBuilder *results.Builder, now time.Time) bool {
+	open, wait, err := maintenance.Open(vts.Spec.MaintenanceWindows, now)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidMaintenanceWindow", "holding off on disruptive changes: %v", err)
+		return false
+	}
+	if !open {
+		resultBuilder.RequeueAfter(wait)
 	}
+	return open
+}
 
-	return resultBuilder.Result()
+// resetUpgradeChecks clears the outcome of the pre-upgrade checks, if any, so
+// they're run again for the next image upgrade.
+func resetUpgradeChecks(vts *planetscalev2.VitessShard) {
+	if _, ok := vts.Status.Conditions[planetscalev2.VitessShardUpgradeBlocked]; ok {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardUpgradeBlocked, corev1.ConditionUnknown, "NoUpgradePending", "No image upgrade is pending.")
+	}
 }
 
 func (r *ReconcileVitessShard) tabletPodsFromShard(ctx context.Context, vts *planetscalev2.VitessShard) (map[string]*corev1.Pod, error) {
@@ -135,37 +233,161 @@ func (r *ReconcileVitessShard) uncascadeShard(ctx context.Context, vts *planetsc
 	return r.client.Update(ctx, vts)
 }
 
-func getNextScheduledTablet(tabletKeys []string, tabletPods map[string]*corev1.Pod, primaryAlias string) (string, *corev1.Pod) {
-	scheduledTablets := map[string]bool{}
+// rolloutPlan is the outcome of deciding which tablets to release next.
+type rolloutPlan struct {
+	// release is the list of tablets to release now.
+	release []string
+	// waiting describes the first thing the rollout is waiting for, if any.
+	waiting string
+	// heldBack is the number of tablets with pending changes that are held
+	// back, either by their pool's update strategy or by a canary.
+	heldBack int
+}
 
+// planRollout decides which tablets with pending changes to release next.
+//
+// A tablet is only released while every tablet in the other pools of the
+// shard is Available and not being updated, and while fewer tablets in its own
+// pool than the pool's maxUnavailable are unavailable or being updated. That
+// means pools are updated one at a time, and without any pool update
+// strategies, one tablet at a time. The primary is released last, on its own.
+//
+// If allowed is not nil, only the tablets in it may be released, and the rest
+// are held back.
+func planRollout(vts *planetscalev2.VitessShard, tabletKeys []string, tabletPods map[string]*corev1.Pod, primaryAlias string, allowed map[string]bool) rolloutPlan {
+	plan := rolloutPlan{}
+
+	// Count the tablets in each pool that are unavailable or being updated.
+	disrupted := map[string]int{}
+	totalDisrupted := 0
 	for _, tabletKey := range tabletKeys {
+		tablet := vts.Status.Tablets[tabletKey]
+		pod, ok := tabletPods[tabletKey]
+
+		var reason string
+		switch {
+		case tablet.Available != corev1.ConditionTrue:
+			reason = fmt.Sprintf("Waiting for tablet %v to be Available.", tabletKey)
+		case !ok:
+			reason = fmt.Sprintf("Waiting for desired tablet %v to be created.", tabletKey)
+		case rollout.Released(pod):
+			reason = fmt.Sprintf("Waiting for tablet %v to finish release.", tabletKey)
+		default:
+			continue
+		}
+		disrupted[rolloutPoolKey(vts, tabletKey, pod)]++
+		totalDisrupted++
+		if plan.waiting == "" {
+			plan.waiting = reason
+		}
+	}
+
+	// Find the tablets that are ready to be released, as far as their pool's
+	// update strategy is concerned.
+	candidates := []string{}
+	for _, tabletKey := range tabletKeys {
+		tablet := vts.Status.Tablets[tabletKey]
+		pod, ok := tabletPods[tabletKey]
+		if !ok || !rollout.Scheduled(pod) || rollout.Released(pod) || tablet.Available != corev1.ConditionTrue {
+			continue
+		}
+		if allowed != nil && !allowed[tabletKey] {
+			plan.heldBack++
+			continue
+		}
+		if strategy := rolloutPool(vts, tabletKey, pod).UpdateStrategy; strategy != nil && (strategy.Paused || tablet.Index <= strategy.Partition) {
+			plan.heldBack++
+			continue
+		}
+		candidates = append(candidates, tabletKey)
+	}
+
+	// If a tablet is scheduled for rollout and it's already drained, it goes
+	// first since the drain controller might not drain any more tablets in the
+	// shard until it's released. A tablet may have been drained by something
+	// other than a rollout. Otherwise, the primary goes last.
+	rank := func(tabletKey string) int {
+		switch {
+		case drain.Finished(tabletPods[tabletKey]):
+			return 0
+		case tabletKey == primaryAlias:
+			return 2
+		}
+		return 1
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return rank(candidates[i]) < rank(candidates[j])
+	})
+
+	for _, tabletKey := range candidates {
 		pod := tabletPods[tabletKey]
-		if rollout.Scheduled(pod) {
-			scheduledTablets[tabletKey] = true
-
-			// If a Pod is scheduled for rollout and it's already drained
-			// then it's the next tablet to release since the drain controller
-			// will not drain any more tablets in the shard.
-			// A tablet may have been drained by something other than a rollout.
-			if drain.Finished(pod) {
-				return tabletKey, pod
+		if rank(tabletKey) == 2 {
+			// Only release the primary once nothing else is going on.
+			if totalDisrupted == 0 {
+				plan.release = append(plan.release, tabletKey)
 			}
+			break
+		}
+
+		poolKey := rolloutPoolKey(vts, tabletKey, pod)
+		if totalDisrupted > disrupted[poolKey] {
+			// Another pool is being updated, or has tablets down.
+			continue
 		}
+		if disrupted[poolKey] >= rolloutMaxUnavailable(vts, rolloutPool(vts, tabletKey, pod)) {
+			continue
+		}
+		plan.release = append(plan.release, tabletKey)
+		disrupted[poolKey]++
+		totalDisrupted++
 	}
 
-	// Release any scheduled tablet
-	for tabletKey := range scheduledTablets {
-		if tabletKey != primaryAlias {
-			return tabletKey, tabletPods[tabletKey]
+	return plan
+}
+
+// rolloutPool returns the tablet pool in the shard spec that a tablet belongs
+// to, or an empty pool if it doesn't belong to any.
+func rolloutPool(vts *planetscalev2.VitessShard, tabletKey string, pod *corev1.Pod) *planetscalev2.VitessShardTabletPool {
+	podPool := rolloutPoolIdentity(vts, tabletKey, pod)
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.IsMatch(podPool) {
+			return pool
 		}
 	}
+	return podPool
+}
 
-	// If there are no remaining scheduled tablets, then release the Primary if its scheduled
-	if _, scheduled := scheduledTablets[primaryAlias]; scheduled {
-		return primaryAlias, tabletPods[primaryAlias]
+// rolloutPoolKey returns a string that identifies the tablet pool a tablet
+// belongs to.
+func rolloutPoolKey(vts *planetscalev2.VitessShard, tabletKey string, pod *corev1.Pod) string {
+	pool := rolloutPoolIdentity(vts, tabletKey, pod)
+	return fmt.Sprintf("%s/%s/%s", pool.Cell, pool.Type, pool.Name)
+}
+
+// rolloutPoolIdentity returns a tablet pool with only the fields that
+// identify which pool a tablet belongs to. If the tablet's Pod doesn't exist,
+// we go by the tablet's status instead.
+func rolloutPoolIdentity(vts *planetscalev2.VitessShard, tabletKey string, pod *corev1.Pod) *planetscalev2.VitessShardTabletPool {
+	if pod != nil {
+		return &planetscalev2.VitessShardTabletPool{
+			Cell: pod.Labels[planetscalev2.CellLabel],
+			Type: planetscalev2.VitessTabletPoolType(pod.Labels[planetscalev2.TabletTypeLabel]),
+			Name: pod.Labels[planetscalev2.TabletPoolNameLabel],
+		}
 	}
+	cell, _, _ := strings.Cut(tabletKey, "-")
+	return &planetscalev2.VitessShardTabletPool{
+		Cell: cell,
+		Type: planetscalev2.VitessTabletPoolType(vts.Status.Tablets[tabletKey].PoolType),
+	}
+}
 
-	return "", nil
+// rolloutMaxUnavailable returns how many tablets in a pool may be unavailable
+// or being updated at the same time.
+func rolloutMaxUnavailable(vts *planetscalev2.VitessShard, pool *planetscalev2.VitessShardTabletPool) int {
+	maxUnavailable, _ := vts.Status.PoolMaxUnavailable(pool)
+	return maxUnavailable
 }
 
 func getPrimaryTabletAlias(ctx context.Context, vts *planetscalev2.VitessShard) (string, error) {
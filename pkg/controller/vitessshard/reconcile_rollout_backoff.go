@@ -0,0 +1,146 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+const (
+	// stuckShardRequeueDelay is how often to check whether a rollout that's
+	// stuck in another shard has recovered.
+	stuckShardRequeueDelay = time.Minute
+)
+
+// reconcileRolloutBackoff checks whether any tablet Pod that's been updated
+// keeps crashing, and if so, marks the rollout of the shard as stuck. Each time
+// the rollout gets stuck, the delay before updating the next tablet after the
+// Pods recover doubles, up to the configured limit.
+//
+// It returns whether updated Pods are crashing, what the rollout is waiting
+// for, if anything, and how long until it may continue.
+func (r *ReconcileVitessShard) reconcileRolloutBackoff(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, now time.Time) (crashing bool, waiting string, retryAfter time.Duration) {
+	config := vts.Spec.UpdateStrategy.CrashLoopBackoff
+	crashingTablets := crashLoopingTablets(vts, tabletPods, *config.RestartThreshold)
+
+	backoff := vts.Status.RolloutBackoff
+	if len(crashingTablets) > 0 {
+		if backoff == nil {
+			backoff = &planetscalev2.VitessShardRolloutBackoffStatus{}
+			vts.Status.RolloutBackoff = backoff
+		}
+		message := fmt.Sprintf("Updated tablet Pods keep crashing: %v. Not updating any more tablets until they recover.", strings.Join(crashingTablets, "; "))
+		if !vts.Status.RolloutStuck() {
+			backoff.Failures++
+			r.recorder.Event(vts, corev1.EventTypeWarning, "RolloutStuck", message)
+		}
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardRolloutStuck, corev1.ConditionTrue, "CrashLoopBackOff", message)
+		// Keep pushing the retry time out while the Pods are crashing, so the
+		// delay starts once they recover.
+		backoff.RetryTime = &metav1.Time{Time: now.Add(rolloutBackoffDelay(config, backoff.Failures))}
+		return true, message, 0
+	}
+
+	if vts.Status.RolloutStuck() {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardRolloutStuck, corev1.ConditionFalse, "PodsRecovered", "Updated tablet Pods have recovered.")
+	}
+	if backoff != nil && backoff.RetryTime != nil && now.Before(backoff.RetryTime.Time) {
+		retryAfter = backoff.RetryTime.Sub(now)
+		return false, fmt.Sprintf("Backing off for %v after the rollout got stuck %d time(s).", retryAfter.Round(time.Second), backoff.Failures), retryAfter
+	}
+	return false, "", 0
+}
+
+// resetRolloutBackoff forgets about any trouble the rollout ran into, once
+// it's complete.
+func resetRolloutBackoff(vts *planetscalev2.VitessShard) {
+	vts.Status.RolloutBackoff = nil
+	if _, ok := vts.Status.Conditions[planetscalev2.VitessShardRolloutStuck]; ok {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardRolloutStuck, corev1.ConditionFalse, "RolloutComplete", "No rollout is in progress.")
+	}
+}
+
+// rolloutBackoffDelay returns how long to wait after the rollout has gotten
+// stuck the given number of times.
+func rolloutBackoffDelay(config *planetscalev2.VitessRolloutBackoff, failures int32) time.Duration {
+	delay := config.InitialDelay.Duration
+	for i := int32(1); i < failures && delay < config.MaxDelay.Duration; i++ {
+		delay *= 2
+	}
+	if delay > config.MaxDelay.Duration {
+		delay = config.MaxDelay.Duration
+	}
+	return delay
+}
+
+// crashLoopingTablets returns a description of each tablet whose Pod has
+// been updated, and has a container in CrashLoopBackOff that has restarted at
+// least the given number of times.
+func crashLoopingTablets(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod, restartThreshold int32) []string {
+	var crashing []string
+	for _, tabletKey := range vts.Status.TabletAliases() {
+		pod := tabletPods[tabletKey]
+		if pod == nil || rollout.Scheduled(pod) {
+			// The Pod hasn't been updated yet.
+			continue
+		}
+		for i := range pod.Status.ContainerStatuses {
+			status := &pod.Status.ContainerStatuses[i]
+			waiting := status.State.Waiting
+			if waiting == nil || waiting.Reason != crashLoopBackOffReason || status.RestartCount < restartThreshold {
+				continue
+			}
+			crashing = append(crashing, fmt.Sprintf("tablet %v container %v restarted %d times", tabletKey, status.Name, status.RestartCount))
+			break
+		}
+	}
+	return crashing
+}
+
+// stuckShardInCluster returns the name of another shard in the same cluster
+// whose rollout is stuck, if any, so tablets in this shard aren't updated in
+// the meantime.
+func (r *ReconcileVitessShard) stuckShardInCluster(ctx context.Context, vts *planetscalev2.VitessShard) (string, error) {
+	shardList := &planetscalev2.VitessShardList{}
+	listOpts := &client.ListOptions{
+		Namespace: vts.Namespace,
+		LabelSelector: apilabels.Set{
+			planetscalev2.ClusterLabel: vts.Labels[planetscalev2.ClusterLabel],
+		}.AsSelector(),
+	}
+	if err := r.client.List(ctx, shardList, listOpts); err != nil {
+		return "", err
+	}
+	for i := range shardList.Items {
+		shard := &shardList.Items[i]
+		if shard.Name != vts.Name && shard.Status.RolloutStuck() {
+			return shard.Name, nil
+		}
+	}
+	return "", nil
+}
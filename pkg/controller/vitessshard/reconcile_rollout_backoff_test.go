@@ -0,0 +1,118 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestReconcileRolloutBackoff(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.UpdateStrategy = &planetscalev2.VitessClusterUpdateStrategy{
+		CrashLoopBackoff: &planetscalev2.VitessRolloutBackoff{
+			RestartThreshold: pointer.Int32Ptr(3),
+			InitialDelay:     &metav1.Duration{Duration: time.Minute},
+			MaxDelay:         &metav1.Duration{Duration: 3 * time.Minute},
+		},
+	}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{"zone1-1": {}, "zone1-2": {}}
+
+	crashLooping := func(restarts int32) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "vttablet",
+			RestartCount: restarts,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: crashLoopBackOffReason}},
+		}}}}
+	}
+	notUpdated := crashLooping(10)
+	rollout.Schedule(notUpdated, "new image")
+	pods := map[string]*corev1.Pod{"zone1-1": crashLooping(2), "zone1-2": notUpdated}
+
+	// A Pod that hasn't been updated yet, or hasn't restarted enough, doesn't count.
+	crashing, waiting, _ := r.reconcileRolloutBackoff(vts, pods, start)
+	assert.False(t, crashing)
+	assert.Empty(t, waiting)
+	assert.Nil(t, vts.Status.RolloutBackoff)
+
+	// Once an updated Pod has restarted enough, the rollout is stuck.
+	pods["zone1-1"] = crashLooping(3)
+	crashing, waiting, _ = r.reconcileRolloutBackoff(vts, pods, start)
+	assert.True(t, crashing)
+	assert.Contains(t, waiting, "tablet zone1-1 container vttablet restarted 3 times")
+	assert.True(t, vts.Status.RolloutStuck())
+	assert.Equal(t, int32(1), vts.Status.RolloutBackoff.Failures)
+
+	// Staying stuck isn't another failure.
+	r.reconcileRolloutBackoff(vts, pods, start.Add(time.Minute))
+	assert.Equal(t, int32(1), vts.Status.RolloutBackoff.Failures)
+
+	// Once the Pod recovers, the rollout waits before going on.
+	pods["zone1-1"] = &corev1.Pod{}
+	crashing, waiting, retryAfter := r.reconcileRolloutBackoff(vts, pods, start.Add(90*time.Second))
+	assert.False(t, crashing)
+	assert.NotEmpty(t, waiting)
+	assert.Equal(t, 30*time.Second, retryAfter)
+	assert.False(t, vts.Status.RolloutStuck())
+
+	_, waiting, _ = r.reconcileRolloutBackoff(vts, pods, start.Add(2*time.Minute))
+	assert.Empty(t, waiting)
+
+	// Getting stuck again doubles the delay.
+	pods["zone1-1"] = crashLooping(5)
+	r.reconcileRolloutBackoff(vts, pods, start.Add(time.Hour))
+	assert.Equal(t, int32(2), vts.Status.RolloutBackoff.Failures)
+	assert.Equal(t, start.Add(time.Hour+2*time.Minute), vts.Status.RolloutBackoff.RetryTime.Time)
+
+	// Once the rollout is complete, the backoff is forgotten.
+	resetRolloutBackoff(vts)
+	assert.Nil(t, vts.Status.RolloutBackoff)
+	assert.False(t, vts.Status.RolloutStuck())
+}
+
+func TestRolloutBackoffDelay(t *testing.T) {
+	config := &planetscalev2.VitessRolloutBackoff{
+		InitialDelay: &metav1.Duration{Duration: time.Minute},
+		MaxDelay:     &metav1.Duration{Duration: 5 * time.Minute},
+	}
+	tests := []struct {
+		failures int32
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Minute},
+		{failures: 2, want: 2 * time.Minute},
+		{failures: 3, want: 4 * time.Minute},
+		{failures: 4, want: 5 * time.Minute},
+		{failures: 100, want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rolloutBackoffDelay(config, tt.failures), "failures: %d", tt.failures)
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestPlanRollout(t *testing.T) {
+	// The shard has 3 replicas in zone1 (the primary is zone1-1) and
+	// 4 rdonly tablets in zone2.
+	newShard := func(rdonlyStrategy *planetscalev2.VitessTabletPoolUpdateStrategy) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+			{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 3},
+			{Cell: "zone2", Type: planetscalev2.RdonlyPoolType, Replicas: 4, UpdateStrategy: rdonlyStrategy},
+		}
+		vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{}
+		for i := int32(1); i <= 3; i++ {
+			vts.Status.Tablets[fmt.Sprintf("zone1-%d", i)] = planetscalev2.VitessTabletStatus{PoolType: "replica", Index: i, Available: corev1.ConditionTrue}
+		}
+		for i := int32(1); i <= 4; i++ {
+			vts.Status.Tablets[fmt.Sprintf("zone2-%d", i)] = planetscalev2.VitessTabletStatus{PoolType: "rdonly", Index: i, Available: corev1.ConditionTrue}
+		}
+		return vts
+	}
+	// newPods returns Pods for every tablet, with the given annotations set.
+	newPods := func(vts *planetscalev2.VitessShard, annotations map[string][]string) map[string]*corev1.Pod {
+		pods := map[string]*corev1.Pod{}
+		for key, tablet := range vts.Status.Tablets {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					planetscalev2.CellLabel:       key[:5],
+					planetscalev2.TabletTypeLabel: tablet.PoolType,
+				},
+				Annotations: map[string]string{},
+			}}
+			for _, ann := range annotations[key] {
+				pod.Annotations[ann] = ""
+			}
+			pods[key] = pod
+		}
+		return pods
+	}
+	scheduledAll := func(vts *planetscalev2.VitessShard) map[string][]string {
+		annotations := map[string][]string{}
+		for key := range vts.Status.Tablets {
+			annotations[key] = []string{rollout.ScheduledAnnotation}
+		}
+		return annotations
+	}
+	maxUnavailable := func(value intstr.IntOrString) *planetscalev2.VitessTabletPoolUpdateStrategy {
+		return &planetscalev2.VitessTabletPoolUpdateStrategy{MaxUnavailable: &value}
+	}
+
+	tests := []struct {
+		name         string
+		vts          *planetscalev2.VitessShard
+		annotations  func(vts *planetscalev2.VitessShard) map[string][]string
+		allowed      map[string]bool
+		unavailable  []string
+		missing      []string
+		wantRelease  []string
+		wantWaiting  bool
+		wantHeldBack int
+	}{
+		{
+			name:        "one at a time by default",
+			vts:         newShard(nil),
+			annotations: scheduledAll,
+			wantRelease: []string{"zone1-2"},
+		},
+		{
+			name: "drained tablets go first",
+			vts:  newShard(nil),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				annotations := scheduledAll(vts)
+				annotations["zone2-3"] = append(annotations["zone2-3"], drain.FinishedAnnotation)
+				return annotations
+			},
+			wantRelease: []string{"zone2-3"},
+		},
+		{
+			name:        "maxUnavailable count",
+			vts:         newShard(maxUnavailable(intstr.FromInt(3))),
+			annotations: scheduledAll,
+			// The replica pool comes first and only allows one at a time.
+			wantRelease: []string{"zone1-2"},
+		},
+		{
+			name: "maxUnavailable percent",
+			vts:  newShard(maxUnavailable(intstr.FromString("50%"))),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				annotations := scheduledAll(vts)
+				delete(annotations, "zone1-1")
+				delete(annotations, "zone1-2")
+				delete(annotations, "zone1-3")
+				return annotations
+			},
+			wantRelease: []string{"zone2-1", "zone2-2"},
+		},
+		{
+			name: "maxUnavailable counts released tablets",
+			vts:  newShard(maxUnavailable(intstr.FromInt(2))),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				annotations := scheduledAll(vts)
+				delete(annotations, "zone1-1")
+				delete(annotations, "zone1-2")
+				delete(annotations, "zone1-3")
+				annotations["zone2-1"] = append(annotations["zone2-1"], rollout.ReleasedAnnotation)
+				return annotations
+			},
+			wantRelease: []string{"zone2-2"},
+			wantWaiting: true,
+		},
+		{
+			name:        "wait for other pools",
+			vts:         newShard(maxUnavailable(intstr.FromInt(2))),
+			annotations: scheduledAll,
+			unavailable: []string{"zone1-3"},
+			wantWaiting: true,
+		},
+		{
+			name:        "wait for missing tablet",
+			vts:         newShard(nil),
+			annotations: scheduledAll,
+			missing:     []string{"zone2-4"},
+			wantWaiting: true,
+		},
+		{
+			name: "partition holds back low indexes",
+			vts:  newShard(&planetscalev2.VitessTabletPoolUpdateStrategy{Partition: 3}),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				annotations := scheduledAll(vts)
+				delete(annotations, "zone1-1")
+				delete(annotations, "zone1-2")
+				delete(annotations, "zone1-3")
+				return annotations
+			},
+			wantRelease:  []string{"zone2-4"},
+			wantHeldBack: 3,
+		},
+		{
+			name: "paused pool",
+			vts:  newShard(&planetscalev2.VitessTabletPoolUpdateStrategy{Paused: true}),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				return map[string][]string{"zone2-1": {rollout.ScheduledAnnotation}}
+			},
+			wantHeldBack: 1,
+		},
+		{
+			name: "primary goes last",
+			vts:  newShard(nil),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				return map[string][]string{"zone1-1": {rollout.ScheduledAnnotation}}
+			},
+			wantRelease: []string{"zone1-1"},
+		},
+		{
+			name: "primary waits for paused pool to be available",
+			vts:  newShard(&planetscalev2.VitessTabletPoolUpdateStrategy{Paused: true}),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string {
+				return map[string][]string{"zone1-1": {rollout.ScheduledAnnotation}, "zone2-1": {rollout.ScheduledAnnotation}}
+			},
+			unavailable:  []string{"zone2-2"},
+			wantWaiting:  true,
+			wantHeldBack: 1,
+		},
+		{
+			name:         "only allowed tablets",
+			vts:          newShard(nil),
+			annotations:  scheduledAll,
+			allowed:      map[string]bool{"zone1-3": true},
+			wantRelease:  []string{"zone1-3"},
+			wantHeldBack: 6,
+		},
+		{
+			name:        "nothing scheduled",
+			vts:         newShard(nil),
+			annotations: func(vts *planetscalev2.VitessShard) map[string][]string { return nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range tt.unavailable {
+				tablet := tt.vts.Status.Tablets[key]
+				tablet.Available = corev1.ConditionFalse
+				tt.vts.Status.Tablets[key] = tablet
+			}
+			pods := newPods(tt.vts, tt.annotations(tt.vts))
+			for _, key := range tt.missing {
+				delete(pods, key)
+			}
+
+			plan := planRollout(tt.vts, tt.vts.Status.TabletAliases(), pods, "zone1-1", tt.allowed)
+			assert.Equal(t, tt.wantRelease, plan.release)
+			assert.Equal(t, tt.wantWaiting, plan.waiting != "")
+			assert.Equal(t, tt.wantHeldBack, plan.heldBack)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/scaleprofile"
+)
+
+// updateScaleProfiles records which scale profile, if any, is in effect for
+// each tablet pool, given the profiles that were in effect before. Everything
+// that sizes a pool goes through vts.Status.PoolReplicas, so the profile's
+// replicas are used in place of those in the spec.
+//
+// NOTE: This must be done before anything that uses PoolReplicas.
+func (r *ReconcileVitessShard) updateScaleProfiles(vts *planetscalev2.VitessShard, oldProfiles []planetscalev2.VitessTabletPoolScaleProfileStatus, now time.Time) {
+	old := &planetscalev2.VitessShardStatus{TabletPoolScaleProfiles: oldProfiles}
+	vts.Status.TabletPoolScaleProfiles = nil
+
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		oldProfile := old.PoolScaleProfile(pool)
+
+		profile, err := scaleprofile.Active(pool.ScaleProfiles, now)
+		if err != nil {
+			// Keep the pool at whatever size it was, rather than flapping
+			// between sizes, until the profiles are fixed.
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidScaleProfile", "tablet pool %v/%v: %v", pool.Cell, pool.Type, err)
+			if oldProfile != nil {
+				vts.Status.TabletPoolScaleProfiles = append(vts.Status.TabletPoolScaleProfiles, *oldProfile)
+			}
+			continue
+		}
+
+		switch {
+		case profile != nil:
+			vts.Status.TabletPoolScaleProfiles = append(vts.Status.TabletPoolScaleProfiles, planetscalev2.VitessTabletPoolScaleProfileStatus{
+				Cell:     pool.Cell,
+				Type:     pool.Type,
+				Name:     pool.Name,
+				Profile:  profile.Name,
+				Replicas: profile.Replicas,
+			})
+			if oldProfile == nil || oldProfile.Profile != profile.Name {
+				r.recorder.Eventf(vts, corev1.EventTypeNormal, "ScaleProfileStarted", "Scale profile %v is in effect for tablet pool %v/%v, with %d replicas.", profile.Name, pool.Cell, pool.Type, profile.Replicas)
+			}
+		case oldProfile != nil:
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "ScaleProfileEnded", "Scale profile %v ended for tablet pool %v/%v. Going back to %d replicas.", oldProfile.Profile, pool.Cell, pool.Type, pool.Replicas)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateScaleProfiles(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	overnight := planetscalev2.VitessScaleProfile{
+		Name:     "overnight",
+		Schedule: "0 21 * * *",
+		Duration: metav1.Duration{Duration: 12 * time.Hour},
+		Replicas: 3,
+	}
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 6, ScaleProfiles: []planetscalev2.VitessScaleProfile{overnight}},
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, Replicas: 2},
+	}
+	replica, rdonly := &vts.Spec.TabletPools[0], &vts.Spec.TabletPools[1]
+
+	// During the day, the spec is used.
+	r.updateScaleProfiles(vts, nil, time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC))
+	assert.Empty(t, vts.Status.TabletPoolScaleProfiles)
+	assert.Equal(t, int32(6), vts.Status.PoolReplicas(replica))
+
+	// Overnight, the profile takes over.
+	r.updateScaleProfiles(vts, vts.Status.TabletPoolScaleProfiles, time.Date(2024, 3, 5, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, int32(3), vts.Status.PoolReplicas(replica))
+	assert.Equal(t, "overnight", vts.Status.PoolScaleProfile(replica).Profile)
+	assert.Equal(t, int32(2), vts.Status.PoolReplicas(rdonly))
+
+	// If the profiles become invalid, the pool stays as it was.
+	replica.ScaleProfiles[0].Schedule = "nightly"
+	r.updateScaleProfiles(vts, vts.Status.TabletPoolScaleProfiles, time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, int32(3), vts.Status.PoolReplicas(replica))
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// updateSemiSyncCondition sets the SemiSyncSatisfiable condition based on
+// whether the shard's tablet pools can always provide semi-sync acks for the
+// primary. The condition is removed if the shard isn't in semiSync mode.
+func updateSemiSyncCondition(vts *planetscalev2.VitessShard) {
+	durabilityPolicy := vts.Spec.Replication.SemiSyncDurability()
+	if durabilityPolicy == "" {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardSemiSyncSatisfiable)
+		return
+	}
+
+	if problems := vts.Spec.SemiSyncProblems(durabilityPolicy); len(problems) > 0 {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardSemiSyncSatisfiable, corev1.ConditionFalse, "NotEnoughReplicas",
+			fmt.Sprintf("Tablet pools can't satisfy the %v durability policy: %v", durabilityPolicy, strings.Join(problems, "; ")))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardSemiSyncSatisfiable, corev1.ConditionTrue, "EnoughReplicas",
+		fmt.Sprintf("Tablet pools can satisfy the %v durability policy", durabilityPolicy))
+}
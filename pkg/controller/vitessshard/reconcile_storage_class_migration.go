@@ -0,0 +1,128 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// migratingPools returns the indexes of the tablet pools in the shard spec
+// that have tablets whose data volume is on a different StorageClass than the
+// pool asks for.
+//
+// The StorageClass of a PVC can't be changed, so each of these tablets is
+// moved to a new volume instead:
+//
+//  1. The pool gets an extra tablet, like a surge during a rollout. It's
+//     created on the new StorageClass, restores from the latest backup, and
+//     catches up on replication, so the pool keeps its capacity throughout.
+//  2. Once every other tablet is Ready, the replication controller drains one
+//     tablet that's still on the old StorageClass, which moves the primary
+//     away with a planned reparent if needed, and then deletes its volume
+//     and Pod.
+//  3. We recreate both on the new StorageClass, and the tablet restores from
+//     backup and catches up in turn. The next tablet isn't replaced until it's
+//     Ready again.
+//  4. When no tablet in the pool is on the old StorageClass anymore, the extra
+//     tablet is drained and turned down like any other unwanted tablet.
+func (r *ReconcileVitessShard) migratingPools(ctx context.Context, vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod) map[int]bool {
+	migrating := map[int]bool{}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if !storageClassMigrationEnabled(vts, pool) {
+			continue
+		}
+		for _, pod := range tabletPods {
+			if pod.Labels[planetscalev2.CellLabel] != pool.Cell || pod.Labels[planetscalev2.TabletTypeLabel] != string(pool.Type) {
+				continue
+			}
+			index, err := strconv.ParseInt(pod.Labels[planetscalev2.TabletIndexLabel], 10, 32)
+			if err != nil || int32(index) > vts.Status.PoolReplicas(pool) {
+				// This is the extra tablet itself.
+				continue
+			}
+			pvc, err := r.claimForTabletPod(ctx, pod)
+			if err != nil {
+				continue
+			}
+			if storageClassChanged(pvc, pool.DataVolumeClaimTemplate) {
+				migrating[i] = true
+				break
+			}
+		}
+	}
+	return migrating
+}
+
+// storageClassMigrationEnabled returns whether the tablets of a pool can be
+// moved to a new StorageClass. The new volumes are restored from backup.
+func storageClassMigrationEnabled(vts *planetscalev2.VitessShard, pool *planetscalev2.VitessShardTabletPool) bool {
+	if pool.DataVolumeClaimTemplate == nil || pool.DataVolumeClaimTemplate.StorageClassName == nil || pool.EphemeralStorage() {
+		return false
+	}
+	return pool.ExternalDatastore == nil && vts.Status.PoolReplicas(pool) > 0 && vts.Spec.BackupLocation(pool.BackupLocationName) != nil
+}
+
+// storageClassChanged returns whether a tablet's data volume is on a
+// different StorageClass than the given PVC spec asks for. A spec that
+// doesn't name a StorageClass is happy with any.
+func storageClassChanged(pvc *corev1.PersistentVolumeClaim, pvcSpec *corev1.PersistentVolumeClaimSpec) bool {
+	if pvcSpec == nil || pvcSpec.StorageClassName == nil {
+		return false
+	}
+	return pvcStorageClass(pvc) != *pvcSpec.StorageClassName
+}
+
+func pvcStorageClass(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName == nil {
+		return ""
+	}
+	return *pvc.Spec.StorageClassName
+}
+
+// dataVolumeMigrationStatus returns the progress of moving a tablet's data
+// volume to the StorageClass its pool asks for, or nil if it's already there.
+func (r *ReconcileVitessShard) dataVolumeMigrationStatus(vts *planetscalev2.VitessShard, resultBuilder *results.Builder, pvc *corev1.PersistentVolumeClaim, tablet *vttablet.Spec) *planetscalev2.VitessTabletDataVolumeMigrationStatus {
+	if !storageClassChanged(pvc, tablet.DataVolumePVCSpec) {
+		return nil
+	}
+
+	status := &planetscalev2.VitessTabletDataVolumeMigrationStatus{
+		CurrentStorageClass: pvcStorageClass(pvc),
+		DesiredStorageClass: *tablet.DataVolumePVCSpec.StorageClassName,
+	}
+	switch {
+	case tablet.BackupLocation == nil:
+		status.Phase = planetscalev2.DataVolumeMigrationWaitingPhase
+		status.Message = "the pool needs a backup location to restore the new volume from"
+	case !r.maintenanceWindowOpen(vts, resultBuilder, time.Now()):
+		status.Phase = planetscalev2.DataVolumeMigrationWaitingPhase
+		status.Message = "waiting for a maintenance window to replace the volume"
+	default:
+		status.Phase = planetscalev2.DataVolumeMigrationReplacingPhase
+		status.Message = "the tablet will be restored from backup onto a new volume on the desired StorageClass"
+	}
+	return status
+}
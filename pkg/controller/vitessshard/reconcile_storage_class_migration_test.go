@@ -0,0 +1,70 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestStorageClassChanged(t *testing.T) {
+	pvc := func(className *string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: className}}
+	}
+	spec := func(className *string) *corev1.PersistentVolumeClaimSpec {
+		return &corev1.PersistentVolumeClaimSpec{StorageClassName: className}
+	}
+
+	assert.False(t, storageClassChanged(pvc(pointer.String("gp2")), spec(nil)), "no StorageClass in the spec")
+	assert.False(t, storageClassChanged(pvc(pointer.String("gp3")), spec(pointer.String("gp3"))), "same StorageClass")
+	assert.True(t, storageClassChanged(pvc(pointer.String("gp2")), spec(pointer.String("gp3"))), "different StorageClass")
+	assert.True(t, storageClassChanged(pvc(nil), spec(pointer.String("gp3"))), "volume without a StorageClass")
+}
+
+func TestDataVolumeMigrationStatus(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	vts := &planetscalev2.VitessShard{}
+	pvc := &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: pointer.String("gp2")}}
+	tablet := &vttablet.Spec{
+		DataVolumePVCSpec: &corev1.PersistentVolumeClaimSpec{StorageClassName: pointer.String("gp3")},
+	}
+
+	// Without a backup location, there's nothing to restore the new volume from.
+	status := r.dataVolumeMigrationStatus(vts, &results.Builder{}, pvc, tablet)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, planetscalev2.DataVolumeMigrationWaitingPhase, status.Phase)
+		assert.Equal(t, "gp2", status.CurrentStorageClass)
+		assert.Equal(t, "gp3", status.DesiredStorageClass)
+	}
+
+	tablet.BackupLocation = &planetscalev2.VitessBackupLocation{}
+	status = r.dataVolumeMigrationStatus(vts, &results.Builder{}, pvc, tablet)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, planetscalev2.DataVolumeMigrationReplacingPhase, status.Phase)
+	}
+
+	pvc.Spec.StorageClassName = pointer.String("gp3")
+	assert.Nil(t, r.dataVolumeMigrationStatus(vts, &results.Builder{}, pvc, tablet))
+}
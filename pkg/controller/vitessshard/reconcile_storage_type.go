@@ -0,0 +1,55 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// updateStorageTypeCondition sets the StorageTypeValid condition based on
+// whether every tablet pool that asks for ephemeral storage can use it. The
+// condition is removed if no pool asks for ephemeral storage. Whenever new
+// problems come up, they're also reported in an event.
+func (r *ReconcileVitessShard) updateStorageTypeCondition(vts *planetscalev2.VitessShard) {
+	configured := false
+	for i := range vts.Spec.TabletPools {
+		if vts.Spec.TabletPools[i].StorageType == planetscalev2.EphemeralStorageType {
+			configured = true
+			break
+		}
+	}
+
+	if !configured {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardStorageTypeValid)
+		return
+	}
+	if problems := vts.Spec.StorageTypeProblems(); len(problems) > 0 {
+		message := fmt.Sprintf("Ephemeral storage can't be used as written: %v", strings.Join(problems, "; "))
+		if condition, ok := vts.Status.Conditions[planetscalev2.VitessShardStorageTypeValid]; !ok || condition.Status != corev1.ConditionFalse || condition.Message != message {
+			r.recorder.Event(vts, corev1.EventTypeWarning, "InvalidStorageType", message)
+		}
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardStorageTypeValid, corev1.ConditionFalse, "InvalidStorageType", message)
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardStorageTypeValid, corev1.ConditionTrue, "ValidStorageType",
+		"Ephemeral storage is used by every tablet pool that asks for it")
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateStorageTypeCondition(t *testing.T) {
+	recorder := record.NewFakeRecorder(100)
+	r := &ReconcileVitessShard{recorder: recorder}
+	vts := &planetscalev2.VitessShard{}
+	vts.Status = planetscalev2.NewVitessShardStatus()
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, Mysqld: &planetscalev2.MysqldSpec{}},
+	}
+
+	// Without ephemeral storage, there's no condition.
+	r.updateStorageTypeCondition(vts)
+	_, ok := vts.Status.Conditions[planetscalev2.VitessShardStorageTypeValid]
+	assert.False(t, ok)
+
+	// Without a backup location, the pool stays ephemeral, but the problem is
+	// reported in the condition and an event.
+	pool := &vts.Spec.TabletPools[0]
+	pool.StorageType = planetscalev2.EphemeralStorageType
+	r.updateStorageTypeCondition(vts)
+	condition := vts.Status.Conditions[planetscalev2.VitessShardStorageTypeValid]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Contains(t, condition.Message, "needs a backup location")
+	assert.True(t, pool.EphemeralStorage())
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "InvalidStorageType")
+
+	// The event isn't repeated while the problem stays the same.
+	r.updateStorageTypeCondition(vts)
+	assert.Len(t, recorder.Events, 0)
+
+	vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{}}
+	r.updateStorageTypeCondition(vts)
+	assert.Equal(t, corev1.ConditionTrue, vts.Status.Conditions[planetscalev2.VitessShardStorageTypeValid].Status)
+	assert.True(t, pool.EphemeralStorage())
+}
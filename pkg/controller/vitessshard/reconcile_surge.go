@@ -0,0 +1,68 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+// surgingPools returns the indexes of the tablet pools in the shard spec that
+// should have a surge tablet: those whose update strategy calls for surge and
+// whose regular tablets have pending changes.
+//
+// The surge tablet is an ordinary tablet at the next index in its pool, so the
+// rollout already waits for it to be Available before updating any other
+// tablet in the pool. Once it's no longer wanted, it's drained and turned down
+// like any other unwanted tablet.
+func surgingPools(vts *planetscalev2.VitessShard, tabletPods map[string]*corev1.Pod) map[int]bool {
+	surging := map[int]bool{}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if !surgeEnabled(vts, pool) {
+			continue
+		}
+		for _, pod := range tabletPods {
+			if pod.Labels[planetscalev2.CellLabel] != pool.Cell || pod.Labels[planetscalev2.TabletTypeLabel] != string(pool.Type) {
+				continue
+			}
+			index, err := strconv.ParseInt(pod.Labels[planetscalev2.TabletIndexLabel], 10, 32)
+			if err != nil || int32(index) > vts.Status.PoolReplicas(pool) {
+				// This is the surge tablet itself.
+				continue
+			}
+			if rollout.Scheduled(pod) {
+				surging[i] = true
+				break
+			}
+		}
+	}
+	return surging
+}
+
+// surgeEnabled returns whether a tablet pool should get a surge tablet while
+// it has pending changes. The surge tablet needs a backup to restore from.
+func surgeEnabled(vts *planetscalev2.VitessShard, pool *planetscalev2.VitessShardTabletPool) bool {
+	if pool.UpdateStrategy == nil || !pool.UpdateStrategy.Surge {
+		return false
+	}
+	return pool.ExternalDatastore == nil && vts.Status.PoolReplicas(pool) > 0 && vts.Spec.BackupLocation(pool.BackupLocationName) != nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+)
+
+func TestSurgingPools(t *testing.T) {
+	newPod := func(cell, tabletType, index string, scheduled bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			planetscalev2.CellLabel:        cell,
+			planetscalev2.TabletTypeLabel:  tabletType,
+			planetscalev2.TabletIndexLabel: index,
+		}}}
+		if scheduled {
+			rollout.Schedule(pod, "new image")
+		}
+		return pod
+	}
+	newShard := func(surge bool, backup bool) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+			{Cell: "zone1", Type: "replica", Replicas: 2},
+			{Cell: "zone1", Type: "rdonly", Replicas: 2, UpdateStrategy: &planetscalev2.VitessTabletPoolUpdateStrategy{Surge: surge}},
+		}
+		if backup {
+			vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{}}
+		}
+		return vts
+	}
+
+	tests := []struct {
+		name string
+		vts  *planetscalev2.VitessShard
+		pods map[string]*corev1.Pod
+		want map[int]bool
+	}{
+		{
+			name: "pending changes in surge pool",
+			vts:  newShard(true, true),
+			pods: map[string]*corev1.Pod{
+				"zone1-1": newPod("zone1", "replica", "1", true),
+				"zone1-2": newPod("zone1", "rdonly", "1", false),
+				"zone1-3": newPod("zone1", "rdonly", "2", true),
+			},
+			want: map[int]bool{1: true},
+		},
+		{
+			name: "only the surge tablet has pending changes",
+			vts:  newShard(true, true),
+			pods: map[string]*corev1.Pod{
+				"zone1-2": newPod("zone1", "rdonly", "1", false),
+				"zone1-3": newPod("zone1", "rdonly", "2", false),
+				"zone1-4": newPod("zone1", "rdonly", "3", true),
+			},
+			want: map[int]bool{},
+		},
+		{
+			name: "surge disabled",
+			vts:  newShard(false, true),
+			pods: map[string]*corev1.Pod{"zone1-3": newPod("zone1", "rdonly", "2", true)},
+			want: map[int]bool{},
+		},
+		{
+			name: "no backup location",
+			vts:  newShard(true, false),
+			pods: map[string]*corev1.Pod{"zone1-3": newPod("zone1", "rdonly", "2", true)},
+			want: map[int]bool{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, surgingPools(tt.vts, tt.pods))
+		})
+	}
+}
@@ -18,6 +18,7 @@ package vitessshard
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
 	"strconv"
 	"time"
@@ -79,8 +80,22 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		sort.Strings(vts.Status.Cells)
 	}()
 
+	// Pools with a surge update strategy get an extra tablet while they have
+	// pending changes.
+	tabletPods, err := r.tabletPodsFromShard(ctx, vts)
+	if err != nil {
+		return resultBuilder.Error(err)
+	}
+
+	// Pools that are moving to a new StorageClass get one too, until they're
+	// done.
+	surging := surgingPools(vts, tabletPods)
+	for poolIndex := range r.migratingPools(ctx, vts, tabletPods) {
+		surging[poolIndex] = true
+	}
+
 	// Compute the set of all desired tablets based on the config.
-	tablets := vttabletSpecs(vts, labels)
+	tablets := vttabletSpecs(vts, labels, surging)
 
 	// Generate podKeys (object names) for all desired tablet pods and pvcKeys for desired PVCs.
 	//
@@ -92,7 +107,7 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		podName := vttablet.PodName(clusterName, tablet.Alias)
 		key := client.ObjectKey{Namespace: vts.Namespace, Name: podName}
 
-		if tablet.DataVolumePVCSpec != nil {
+		if tablet.DataVolumePVCSpec != nil && !tablet.EphemeralDataVolume {
 			// We use the same name for the Pod and the main data volume PVC.
 			tablet.DataVolumePVCName = podName
 
@@ -111,7 +126,7 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 	}
 
 	// Reconcile vttablet PVCs. Note that we use the same keys as the corresponding Pods.
-	err := r.reconciler.ReconcileObjectSet(ctx, vts, pvcKeys, labels, reconciler.Strategy{
+	err = r.reconciler.ReconcileObjectSet(ctx, vts, pvcKeys, labels, reconciler.Strategy{
 		Kind: &corev1.PersistentVolumeClaim{},
 
 		New: func(key client.ObjectKey) runtime.Object {
@@ -126,7 +141,19 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			curObj := obj.(*corev1.PersistentVolumeClaim)
-			vttablet.UpdatePVCInPlace(curObj, tabletMap[key])
+			tablet := tabletMap[key]
+
+			// Expanding a volume is disruptive, so wait for a maintenance window.
+			// Volumes that can't be expanded, or are moving to a new
+			// StorageClass, are replaced instead, by the replication
+			// controller.
+			curSize := curObj.Spec.Resources.Requests[corev1.ResourceStorage]
+			newSize := tablet.DataVolumePVCSpec.Resources.Requests[corev1.ResourceStorage]
+			if newSize.Cmp(curSize) > 0 && (storageClassChanged(curObj, tablet.DataVolumePVCSpec) || !r.volumeExpansionAllowed(ctx, curObj) || !r.maintenanceWindowOpen(vts, resultBuilder, time.Now())) {
+				vttablet.UpdatePVCLabelsInPlace(curObj, tablet)
+				return
+			}
+			vttablet.UpdatePVCInPlace(curObj, tablet)
 		},
 		Status: func(key client.ObjectKey, obj runtime.Object) {
 			tablet := tabletMap[key]
@@ -134,6 +161,12 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 
 			status := vts.Status.Tablets[tablet.AliasStr]
 			status.DataVolumeBound = k8s.ConditionStatus(curObj.Status.Phase == corev1.ClaimBound)
+			// A volume that's moving to a new StorageClass gets the new size
+			// along the way.
+			status.DataVolumeMigration = r.dataVolumeMigrationStatus(vts, resultBuilder, curObj, tablet)
+			if status.DataVolumeMigration == nil {
+				status.DataVolumeResize = r.dataVolumeResizeStatus(ctx, vts, resultBuilder, curObj, tablet)
+			}
 			vts.Status.Tablets[tablet.AliasStr] = status
 		},
 		PrepareForTurndown: func(key client.ObjectKey, obj runtime.Object) *planetscalev2.OrphanStatus {
@@ -156,7 +189,8 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 
 	// Reconcile vttablet Pods.
 	err = r.reconciler.ReconcileObjectSet(ctx, vts, podKeys, labels, reconciler.Strategy{
-		Kind: &corev1.Pod{},
+		Kind:          &corev1.Pod{},
+		RolloutPaused: rolloutPaused(vts),
 
 		New: func(key client.ObjectKey) runtime.Object {
 			tablet := tabletMap[key]
@@ -196,7 +230,22 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 				tabletStatus.Available = tabletAvailableStatus(resultBuilder, pod)
 			}
 			tabletStatus.PendingChanges = pod.Annotations[rollout.ScheduledAnnotation]
+			tabletStatus.Replicating = corev1.ConditionStatus(pod.Annotations[vttablet.ReplicatingAnnotation])
+			tabletStatus.ReplicationError = pod.Annotations[vttablet.ReplicationErrorAnnotation]
+			if vts.Spec.Replication.ErrantGTIDPolicy != "" {
+				tabletStatus.ErrantGTIDs = pod.Annotations[vttablet.ErrantGTIDsAnnotation]
+			}
+			if vts.Spec.MysqldConfigDriftPolicy != "" {
+				tabletStatus.MysqldConfigDrift = pod.Annotations[vttablet.MysqldConfigDriftAnnotation]
+			}
+			if progress := pod.Annotations[vttablet.RestoreProgressAnnotation]; progress != "" {
+				restore := &planetscalev2.VitessTabletRestoreStatus{}
+				if err := json.Unmarshal([]byte(progress), restore); err == nil {
+					tabletStatus.Restore = restore
+				}
+			}
 			vts.Status.Tablets[tablet.AliasStr] = tabletStatus
+			recordDrainState(vts, tablet.AliasStr, pod)
 
 			observedShardGenerationVal := pod.Annotations[observedShardGenerationAnnotationKey]
 			if observedShardGenerationVal == "" {
@@ -217,6 +266,7 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 			tabletAliasStr := topoproto.TabletAliasString(&tabletAlias)
 
 			vts.Status.OrphanedTablets[tabletAliasStr] = *orphanStatus
+			recordDrainState(vts, tabletAliasStr, curObj)
 
 			// Since we're keeping this tablet, remember that we're still in that cell.
 			deployedCells[tabletAlias.Cell] = struct{}{}
@@ -265,8 +315,9 @@ func (r *ReconcileVitessShard) reconcileTablets(ctx context.Context, vts *planet
 	return resultBuilder.Result()
 }
 
-// vttabletSpecs creates a list of vttablet Specs for a VitessShard.
-func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]string) []*vttablet.Spec {
+// vttabletSpecs creates a list of vttablet Specs for a VitessShard. Pools
+// whose index is in surging get one tablet more than their replicas.
+func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]string, surging map[int]bool) []*vttablet.Spec {
 	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
 
 	var tablets []*vttablet.Spec
@@ -277,8 +328,48 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 		// Find the backup location for this pool.
 		backupLocation := vts.Spec.BackupLocation(pool.BackupLocationName)
 
-		// Within each pool, tablets are assigned a 1-based index.
-		for tabletIndex := int32(1); tabletIndex <= pool.Replicas; tabletIndex++ {
+		// Spread the pool's tablets across zones, if requested.
+		topologySpreadConstraints := pool.TopologySpreadConstraints
+		if pool.ZoneSpread != nil {
+			poolLabels := make(map[string]string, len(parentLabels)+2)
+			for k, v := range parentLabels {
+				poolLabels[k] = v
+			}
+			poolLabels[planetscalev2.CellLabel] = pool.Cell
+			poolLabels[planetscalev2.TabletTypeLabel] = string(pool.Type)
+			topologySpreadConstraints = append(append([]corev1.TopologySpreadConstraint{}, pool.TopologySpreadConstraints...), pool.ZoneSpread.TopologySpreadConstraint(poolLabels))
+		}
+
+		// Until the shard has been restored from the initialRestore backups,
+		// tablets look for backups there instead.
+		backupClusterName := ""
+		if restore := vts.Spec.InitialRestore; restore != nil && !vts.Status.InitialRestoreComplete() {
+			if location := vts.Spec.BackupLocation(restore.BackupLocationName); location != nil {
+				backupLocation = location
+			}
+			backupClusterName = restore.ClusterName
+		}
+
+		// Use any recommended resource requests that vertical autoscaling
+		// applied in place of those in the spec.
+		vttabletResources := pool.Vttablet.Resources
+		mysqld := pool.Mysqld
+		if status := vts.Status.PoolResourceStatus(pool); status != nil {
+			vttabletResources = appliedResources(vttabletResources, status.Container(vttablet.VttabletContainerName))
+			if mysqld != nil {
+				mysqldcpy := *mysqld
+				mysqldcpy.Resources = appliedResources(mysqld.Resources, status.Container(vttablet.MysqldContainerName))
+				mysqld = &mysqldcpy
+			}
+		}
+
+		// Within each pool, tablets are assigned a 1-based index. A surge
+		// tablet comes after the pool's regular tablets.
+		replicas := vts.Status.PoolReplicas(pool)
+		if surging[poolIndex] {
+			replicas++
+		}
+		for tabletIndex := int32(1); tabletIndex <= replicas; tabletIndex++ {
 			tabletAlias := topodatapb.TabletAlias{
 				Cell: pool.Cell,
 				Uid:  vttablet.UID(pool.Cell, keyspaceName, vts.Spec.KeyRange, pool.Type, uint32(tabletIndex)),
@@ -310,6 +401,7 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 			// Make shallow copy of pool.Vttablet to avoid mutating input.
 			vttabletcpy := pool.Vttablet
 			vttabletcpy.ExtraFlags = extraFlags
+			vttabletcpy.Resources = vttabletResources
 
 			annotations := map[string]string{
 				drain.SupportedAnnotation: "ensure that the tablet is not a primary",
@@ -330,17 +422,20 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 				AliasStr:                  topoproto.TabletAliasString(&tabletAlias),
 				Zone:                      vts.Spec.ZoneMap[tabletAlias.Cell],
 				Vttablet:                  &vttabletcpy,
-				Mysqld:                    pool.Mysqld,
+				Mysqld:                    mysqld,
+				AutoTuneMysql:             pool.AutoTuneMysql,
 				MysqldExporter:            pool.MysqldExporter,
 				ExternalDatastore:         pool.ExternalDatastore,
 				Type:                      pool.Type,
 				DataVolumePVCSpec:         pool.DataVolumeClaimTemplate,
+				EphemeralDataVolume:       pool.EphemeralStorage(),
 				KeyspaceName:              keyspaceName,
 				DatabaseName:              vts.Spec.DatabaseName,
 				DatabaseInitScriptSecret:  vts.Spec.DatabaseInitScriptSecret,
 				Annotations:               annotations,
 				BackupLocation:            backupLocation,
 				BackupEngine:              vts.Spec.BackupEngine,
+				BackupClusterName:         backupClusterName,
 				Affinity:                  pool.Affinity,
 				ExtraEnv:                  pool.ExtraEnv,
 				ExtraVolumes:              pool.ExtraVolumes,
@@ -349,7 +444,8 @@ func vttabletSpecs(vts *planetscalev2.VitessShard, parentLabels map[string]strin
 				SidecarContainers:         pool.SidecarContainers,
 				ExtraVolumeMounts:         pool.ExtraVolumeMounts,
 				Tolerations:               pool.Tolerations,
-				TopologySpreadConstraints: pool.TopologySpreadConstraints,
+				TopologySpreadConstraints: topologySpreadConstraints,
+				ReplicationDelay:          pool.ReplicationDelay(),
 			})
 		}
 	}
@@ -0,0 +1,384 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// resourceSampleInterval is how often to sample the resource usage of
+	// tablets in pools with vertical autoscaling enabled.
+	resourceSampleInterval = time.Minute
+	// peakUsageHalfLife is how long it takes for a peak in resource usage to
+	// count for half as much.
+	peakUsageHalfLife = 24 * time.Hour
+	// minResourceSampleAge is how long usage must have been sampled for a
+	// pool before recommendations are applied.
+	minResourceSampleAge = 24 * time.Hour
+	// recommendationMarginPercent is how much room recommendations leave
+	// above peak usage.
+	recommendationMarginPercent = 15
+	// applyThresholdPercent is how far a recommendation must be from the
+	// current requests before it's applied, so tablets aren't restarted for
+	// small changes.
+	applyThresholdPercent = 20
+
+	minRecommendedCPUMillis   = 10
+	cpuMillisStep             = 10
+	minRecommendedMemoryBytes = 32 * 1024 * 1024
+	memoryBytesStep           = 1024 * 1024
+)
+
+// podMetricsListGVK is the kind of object that the Kubernetes resource
+// metrics API serves Pod usage as.
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// verticalAutoscalingContainers are the tablet containers that resources
+// are recommended for.
+var verticalAutoscalingContainers = []string{vttablet.VttabletContainerName, vttablet.MysqldContainerName}
+
+// podMetrics is the part of a PodMetrics object that we use.
+type podMetrics struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Containers        []containerMetrics `json:"containers"`
+}
+
+// containerMetrics is the usage of one container in a PodMetrics object.
+type containerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// reconcileVerticalAutoscaling samples the CPU and memory usage of tablets in
+// pools with vertical autoscaling enabled, and records recommended requests
+// for their vttablet and mysqld containers in the shard status. In Auto mode,
+// it also decides when to apply the recommendations, which vttabletSpecs then
+// uses in place of the requests in the spec. Any resulting Pod changes are
+// rolled out like any other change.
+//
+// NOTE: This must be done before reconcileTablets, which uses the applied
+// recommendations.
+func (r *ReconcileVitessShard) reconcileVerticalAutoscaling(ctx context.Context, vts *planetscalev2.VitessShard, now time.Time) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	// Keep what we knew about pools that still have vertical autoscaling
+	// enabled, and start tracking any new ones.
+	oldResources := vts.Status.TabletPoolResources
+	vts.Status.TabletPoolResources = nil
+	sampleDue := false
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if !verticalAutoscalingEnabled(pool) {
+			continue
+		}
+		// Copy the old status, so the status update sees what changed.
+		status := (&planetscalev2.VitessShardStatus{TabletPoolResources: oldResources}).PoolResourceStatus(pool).DeepCopy()
+		if status == nil {
+			status = &planetscalev2.VitessTabletPoolResourceStatus{
+				Cell:         pool.Cell,
+				Type:         pool.Type,
+				Name:         pool.Name,
+				SampledSince: metav1.Time{Time: now},
+			}
+		}
+		if status.LastSampleTime == nil || now.Sub(status.LastSampleTime.Time) >= resourceSampleInterval {
+			sampleDue = true
+		}
+		vts.Status.TabletPoolResources = append(vts.Status.TabletPoolResources, *status)
+	}
+	if len(vts.Status.TabletPoolResources) == 0 {
+		return resultBuilder.Result()
+	}
+
+	if sampleDue {
+		metrics, err := r.tabletPodMetrics(ctx, vts)
+		switch {
+		case meta.IsNoMatchError(err):
+			r.recorder.Event(vts, corev1.EventTypeWarning, "MetricsUnavailable", "Can't recommend tablet resources because the resource metrics API (metrics.k8s.io) isn't available. Is metrics-server installed?")
+		case err != nil:
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "MetricsFailed", "failed to get tablet resource usage: %v", err)
+			resultBuilder.Error(err)
+		default:
+			for i := range vts.Spec.TabletPools {
+				pool := &vts.Spec.TabletPools[i]
+				if status := vts.Status.PoolResourceStatus(pool); status != nil {
+					samplePoolUsage(vts, pool, status, metrics, now)
+				}
+			}
+		}
+	}
+
+	// Apply recommendations in Auto mode, during maintenance windows.
+	open, _, err := maintenance.Open(vts.Spec.MaintenanceWindows, now)
+	if err != nil {
+		// The rollout reports invalid maintenance windows.
+		open = false
+	}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		status := vts.Status.PoolResourceStatus(pool)
+		if status == nil {
+			continue
+		}
+		if pool.VerticalAutoscaling != planetscalev2.VerticalAutoscalingAuto {
+			for j := range status.Containers {
+				status.Containers[j].Applied = nil
+			}
+			continue
+		}
+		if !open || now.Sub(status.SampledSince.Time) < minResourceSampleAge {
+			continue
+		}
+		var changed []string
+		for j := range status.Containers {
+			container := &status.Containers[j]
+			current := container.Applied
+			if current == nil {
+				current = specRequests(pool, container.Name)
+			}
+			if len(container.Recommended) > 0 && recommendationDiffers(current, container.Recommended) {
+				container.Applied = container.Recommended.DeepCopy()
+				changed = append(changed, container.Name)
+			}
+		}
+		if len(changed) > 0 {
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "ResourcesAdjusted", "Applying recommended resource requests for %v in tablet pool %v/%v.", strings.Join(changed, ", "), pool.Cell, pool.Type)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// verticalAutoscalingEnabled returns whether resources are recommended for a
+// tablet pool.
+func verticalAutoscalingEnabled(pool *planetscalev2.VitessShardTabletPool) bool {
+	return pool.VerticalAutoscaling == planetscalev2.VerticalAutoscalingRecommend || pool.VerticalAutoscaling == planetscalev2.VerticalAutoscalingAuto
+}
+
+// tabletPodMetrics returns the resource usage of the tablet Pods in the shard,
+// as reported by the Kubernetes resource metrics API.
+func (r *ReconcileVitessShard) tabletPodMetrics(ctx context.Context, vts *planetscalev2.VitessShard) ([]podMetrics, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(tabletShardLabels(vts)),
+	}
+	if err := r.client.List(ctx, list, listOpts); err != nil {
+		return nil, err
+	}
+	metrics := make([]podMetrics, 0, len(list.Items))
+	for i := range list.Items {
+		var item podMetrics
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, &item); err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, item)
+	}
+	return metrics, nil
+}
+
+// tabletShardLabels returns the labels that every tablet Pod in the shard has.
+func tabletShardLabels(vts *planetscalev2.VitessShard) map[string]string {
+	return map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+}
+
+// samplePoolUsage updates the peak usage of each container in a tablet pool
+// with the highest usage in any of its tablets right now, and recommends
+// requests to fit.
+func samplePoolUsage(vts *planetscalev2.VitessShard, pool *planetscalev2.VitessShardTabletPool, status *planetscalev2.VitessTabletPoolResourceStatus, metrics []podMetrics, now time.Time) {
+	selector, err := metav1.LabelSelectorAsSelector(tabletPoolSelector(tabletShardLabels(vts), pool))
+	if err != nil {
+		return
+	}
+	usage := map[string]corev1.ResourceList{}
+	for i := range metrics {
+		if !selector.Matches(apilabels.Set(metrics[i].Labels)) {
+			continue
+		}
+		for _, container := range metrics[i].Containers {
+			usage[container.Name] = maxResources(usage[container.Name], container.Usage)
+		}
+	}
+
+	var elapsed time.Duration
+	if status.LastSampleTime != nil {
+		elapsed = now.Sub(status.LastSampleTime.Time)
+	}
+	status.LastSampleTime = &metav1.Time{Time: now}
+
+	for _, name := range verticalAutoscalingContainers {
+		sample, ok := usage[name]
+		if !ok {
+			continue
+		}
+		container := status.Container(name)
+		if container == nil {
+			status.Containers = append(status.Containers, planetscalev2.VitessContainerResourceStatus{Name: name})
+			container = &status.Containers[len(status.Containers)-1]
+		}
+		container.PeakUsage = updatePeakUsage(container.PeakUsage, sample, elapsed)
+		container.Recommended = recommendRequests(container.PeakUsage)
+	}
+}
+
+// maxResources returns the higher of the CPU and memory values in a and b.
+func maxResources(a, b corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		value, ok := a[name]
+		if other, otherOK := b[name]; otherOK && (!ok || other.Cmp(value) > 0) {
+			value, ok = other, true
+		}
+		if ok {
+			result[name] = value.DeepCopy()
+		}
+	}
+	return result
+}
+
+// updatePeakUsage returns the new peak usage, given the last one, a new
+// sample, and how long it's been since the last sample. The last peak decays
+// by half every peakUsageHalfLife, so the peak follows usage down over time.
+func updatePeakUsage(peak, sample corev1.ResourceList, elapsed time.Duration) corev1.ResourceList {
+	decay := math.Pow(0.5, float64(elapsed)/float64(peakUsageHalfLife))
+	result := corev1.ResourceList{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		value := int64(0)
+		if old, ok := peak[name]; ok {
+			value = int64(float64(old.MilliValue()) * decay)
+		}
+		if sampled, ok := sample[name]; ok && sampled.MilliValue() > value {
+			value = sampled.MilliValue()
+		}
+		result[name] = resourceQuantity(name, value)
+	}
+	return result
+}
+
+// recommendRequests returns the requests that fit the given peak usage, with
+// room to spare.
+func recommendRequests(peak corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	if cpu, ok := peak[corev1.ResourceCPU]; ok {
+		millis := roundUp(withMargin(cpu.MilliValue()), cpuMillisStep)
+		if millis < minRecommendedCPUMillis {
+			millis = minRecommendedCPUMillis
+		}
+		result[corev1.ResourceCPU] = *resource.NewMilliQuantity(millis, resource.DecimalSI)
+	}
+	if memory, ok := peak[corev1.ResourceMemory]; ok {
+		bytes := roundUp(withMargin(memory.Value()), memoryBytesStep)
+		if bytes < minRecommendedMemoryBytes {
+			bytes = minRecommendedMemoryBytes
+		}
+		result[corev1.ResourceMemory] = *resource.NewQuantity(bytes, resource.BinarySI)
+	}
+	return result
+}
+
+// recommendationDiffers returns whether recommended requests are far enough
+// from the current ones to be worth restarting tablets for.
+func recommendationDiffers(current, recommended corev1.ResourceList) bool {
+	for name, want := range recommended {
+		have, ok := current[name]
+		if !ok || have.IsZero() {
+			return true
+		}
+		diff := want.MilliValue() - have.MilliValue()
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff*100 > have.MilliValue()*applyThresholdPercent {
+			return true
+		}
+	}
+	return false
+}
+
+// specRequests returns the requests in the spec for a container in a tablet pool.
+func specRequests(pool *planetscalev2.VitessShardTabletPool, containerName string) corev1.ResourceList {
+	switch containerName {
+	case vttablet.VttabletContainerName:
+		return pool.Vttablet.Resources.Requests
+	case vttablet.MysqldContainerName:
+		if pool.Mysqld != nil {
+			return pool.Mysqld.Resources.Requests
+		}
+	}
+	return nil
+}
+
+// appliedResources returns the resources for a container with any
+// recommended requests that were applied in place of those in the spec.
+func appliedResources(resources corev1.ResourceRequirements, status *planetscalev2.VitessContainerResourceStatus) corev1.ResourceRequirements {
+	if status == nil || len(status.Applied) == 0 {
+		return resources
+	}
+	result := *resources.DeepCopy()
+	if result.Requests == nil {
+		result.Requests = corev1.ResourceList{}
+	}
+	for name, value := range status.Applied {
+		result.Requests[name] = value.DeepCopy()
+		// The limit can't be lower than the request.
+		if limit, ok := result.Limits[name]; ok && limit.Cmp(value) < 0 {
+			result.Limits[name] = value.DeepCopy()
+		}
+	}
+	return result
+}
+
+func resourceQuantity(name corev1.ResourceName, millis int64) resource.Quantity {
+	if name == corev1.ResourceMemory {
+		return *resource.NewQuantity(millis/1000, resource.BinarySI)
+	}
+	return *resource.NewMilliQuantity(millis, resource.DecimalSI)
+}
+
+func withMargin(value int64) int64 {
+	return value + (value*recommendationMarginPercent+99)/100
+}
+
+func roundUp(value, step int64) int64 {
+	return (value + step - 1) / step * step
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestUpdatePeakUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		peak    corev1.ResourceList
+		sample  corev1.ResourceList
+		elapsed time.Duration
+		want    corev1.ResourceList
+	}{
+		{
+			name:   "first sample",
+			sample: resources("250m", "1Gi"),
+			want:   resources("250m", "1Gi"),
+		},
+		{
+			name:    "higher sample",
+			peak:    resources("250m", "1Gi"),
+			sample:  resources("500m", "2Gi"),
+			elapsed: time.Minute,
+			want:    resources("500m", "2Gi"),
+		},
+		{
+			name:    "peak decays by half every half-life",
+			peak:    resources("1", "2Gi"),
+			sample:  resources("100m", "256Mi"),
+			elapsed: peakUsageHalfLife,
+			want:    resources("500m", "1Gi"),
+		},
+		{
+			name:    "decayed peak is no lower than the sample",
+			peak:    resources("1", "2Gi"),
+			sample:  resources("800m", "1536Mi"),
+			elapsed: peakUsageHalfLife,
+			want:    resources("800m", "1536Mi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := updatePeakUsage(tt.peak, tt.sample, tt.elapsed)
+			for name, want := range tt.want {
+				got := got[name]
+				assert.Zero(t, want.Cmp(got), "%v: got %v, want %v", name, got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestRecommendRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		peak corev1.ResourceList
+		want corev1.ResourceList
+	}{
+		{
+			name: "margin above peak",
+			peak: resources("1", "1Gi"),
+			want: resources("1150m", "1178Mi"),
+		},
+		{
+			name: "rounded up",
+			peak: resources("101m", "100Mi"),
+			want: resources("120m", "115Mi"),
+		},
+		{
+			name: "floor",
+			peak: resources("1m", "1Mi"),
+			want: resources("10m", "32Mi"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recommendRequests(tt.peak)
+			for name, want := range tt.want {
+				got := got[name]
+				assert.Zero(t, want.Cmp(got), "%v: got %v, want %v", name, got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestRecommendationDiffers(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     corev1.ResourceList
+		recommended corev1.ResourceList
+		want        bool
+	}{
+		{
+			name:        "no requests",
+			recommended: resources("100m", "1Gi"),
+			want:        true,
+		},
+		{
+			name:        "within threshold",
+			current:     resources("100m", "1Gi"),
+			recommended: resources("110m", "900Mi"),
+			want:        false,
+		},
+		{
+			name:        "cpu above threshold",
+			current:     resources("100m", "1Gi"),
+			recommended: resources("130m", "1Gi"),
+			want:        true,
+		},
+		{
+			name:        "memory below threshold",
+			current:     resources("100m", "1Gi"),
+			recommended: resources("100m", "512Mi"),
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, recommendationDiffers(tt.current, tt.recommended))
+		})
+	}
+}
+
+func TestAppliedResources(t *testing.T) {
+	spec := corev1.ResourceRequirements{
+		Requests: resources("100m", "1Gi"),
+		Limits:   resources("200m", "1Gi"),
+	}
+
+	// Without applied recommendations, the spec is used as is.
+	assert.Equal(t, spec, appliedResources(spec, nil))
+	assert.Equal(t, spec, appliedResources(spec, &planetscalev2.VitessContainerResourceStatus{}))
+
+	// Applied requests replace those in the spec, and limits are raised to
+	// match if needed.
+	got := appliedResources(spec, &planetscalev2.VitessContainerResourceStatus{Applied: resources("150m", "2Gi")})
+	assert.Zero(t, got.Requests.Cpu().Cmp(resource.MustParse("150m")))
+	assert.Zero(t, got.Requests.Memory().Cmp(resource.MustParse("2Gi")))
+	assert.Zero(t, got.Limits.Cpu().Cmp(resource.MustParse("200m")))
+	assert.Zero(t, got.Limits.Memory().Cmp(resource.MustParse("2Gi")))
+
+	// The spec isn't modified.
+	assert.Zero(t, spec.Requests.Cpu().Cmp(resource.MustParse("100m")))
+	assert.Zero(t, spec.Limits.Memory().Cmp(resource.MustParse("1Gi")))
+}
+
+func TestReconcileVerticalAutoscalingApply(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+
+	newShard := func(mode planetscalev2.VitessVerticalAutoscalingMode, sampledFor time.Duration) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{{
+			Cell:                "zone1",
+			Type:                planetscalev2.ReplicaPoolType,
+			VerticalAutoscaling: mode,
+			Vttablet:            planetscalev2.VttabletSpec{Resources: corev1.ResourceRequirements{Requests: resources("100m", "1Gi")}},
+		}}
+		// The last sample is recent, so none is taken now.
+		vts.Status.TabletPoolResources = []planetscalev2.VitessTabletPoolResourceStatus{{
+			Cell:           "zone1",
+			Type:           planetscalev2.ReplicaPoolType,
+			SampledSince:   metav1.Time{Time: now.Add(-sampledFor)},
+			LastSampleTime: &metav1.Time{Time: now},
+			Containers: []planetscalev2.VitessContainerResourceStatus{{
+				Name:        vttablet.VttabletContainerName,
+				Recommended: resources("500m", "1Gi"),
+				Applied:     resources("300m", "1Gi"),
+			}},
+		}}
+		return vts
+	}
+
+	tests := []struct {
+		name        string
+		vts         *planetscalev2.VitessShard
+		wantStatus  bool
+		wantApplied corev1.ResourceList
+	}{
+		{
+			name:        "auto applies recommendation",
+			vts:         newShard(planetscalev2.VerticalAutoscalingAuto, 48*time.Hour),
+			wantStatus:  true,
+			wantApplied: resources("500m", "1Gi"),
+		},
+		{
+			name:        "auto waits for enough samples",
+			vts:         newShard(planetscalev2.VerticalAutoscalingAuto, time.Hour),
+			wantStatus:  true,
+			wantApplied: resources("300m", "1Gi"),
+		},
+		{
+			name:       "recommend only clears applied requests",
+			vts:        newShard(planetscalev2.VerticalAutoscalingRecommend, 48*time.Hour),
+			wantStatus: true,
+		},
+		{
+			name: "off forgets the pool",
+			vts:  newShard(planetscalev2.VerticalAutoscalingOff, 48*time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.reconcileVerticalAutoscaling(context.Background(), tt.vts, now)
+			assert.NoError(t, err)
+			status := tt.vts.Status.PoolResourceStatus(&tt.vts.Spec.TabletPools[0])
+			if !tt.wantStatus {
+				assert.Nil(t, status)
+				return
+			}
+			assert.Equal(t, tt.wantApplied, status.Container(vttablet.VttabletContainerName).Applied)
+		})
+	}
+}
@@ -23,12 +23,15 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+	"planetscale.dev/vitess-operator/pkg/operator/pdb"
 	"planetscale.dev/vitess-operator/pkg/operator/reconciler"
 	"planetscale.dev/vitess-operator/pkg/operator/results"
 	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
@@ -63,15 +66,19 @@ func (r *ReconcileVitessShard) reconcileVtorc(ctx context.Context, vts *planetsc
 		specMap[key] = spec
 	}
 
+	// While rollouts are paused, only make changes that don't restart Pods.
+	paused := rolloutPaused(vts)
+
 	err := r.reconciler.ReconcileObjectSet(ctx, vts, keys, labels, reconciler.Strategy{
-		Kind: &appsv1.Deployment{},
+		Kind:          &appsv1.Deployment{},
+		RolloutPaused: paused,
 
 		New: func(key client.ObjectKey) runtime.Object {
 			return vtorc.NewDeployment(key, specMap[key])
 		},
 		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
 			newObj := obj.(*appsv1.Deployment)
-			if *vts.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType {
+			if *vts.Spec.UpdateStrategy.Type == planetscalev2.ImmediateVitessClusterUpdateStrategyType && !paused {
 				vtorc.UpdateDeployment(newObj, specMap[key])
 				return
 			}
@@ -100,6 +107,42 @@ func (r *ReconcileVitessShard) reconcileVtorc(ctx context.Context, vts *planetsc
 		resultBuilder.Error(err)
 	}
 
+	// Find out about recoveries the vtorc Pods ran.
+	if len(specs) != 0 {
+		r.reconcileVtorcRecoveries(ctx, vts, labels)
+	}
+
+	// Reconcile a vtorc PDB for each Deployment.
+	var pdbConfig *planetscalev2.VitessPodDisruptionBudget
+	if vts.Spec.VitessOrchestrator != nil {
+		pdbConfig = vts.Spec.VitessOrchestrator.PodDisruptionBudget
+	}
+	pdbKeys := keys
+	if !pdb.Wanted(pdbConfig) {
+		pdbKeys = nil
+	}
+	pdbSpec := func(key client.ObjectKey) *pdb.Spec {
+		return &pdb.Spec{
+			Labels:   labels,
+			Selector: &metav1.LabelSelector{MatchLabels: specMap[key].Labels},
+			Replicas: specMap[key].Replicas,
+			Config:   pdbConfig,
+		}
+	}
+	err = r.reconciler.ReconcileObjectSet(ctx, vts, pdbKeys, labels, reconciler.Strategy{
+		Kind: &policyv1.PodDisruptionBudget{},
+
+		New: func(key client.ObjectKey) runtime.Object {
+			return pdb.NewPodDisruptionBudget(key, pdbSpec(key))
+		},
+		UpdateInPlace: func(key client.ObjectKey, obj runtime.Object) {
+			pdb.UpdatePodDisruptionBudget(obj.(*policyv1.PodDisruptionBudget), pdbSpec(key))
+		},
+	})
+	if err != nil {
+		resultBuilder.Error(err)
+	}
+
 	return resultBuilder.Result()
 }
 
@@ -107,6 +150,10 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 	if vts.Spec.VitessOrchestrator == nil {
 		return nil
 	}
+	if vts.Spec.VitessOrchestrator.Scope == planetscalev2.VitessOrchestratorScopeKeyspace {
+		// The keyspace deploys a single vtorc for all its shards.
+		return nil
+	}
 
 	specs := make([]*vtorc.Spec, 0, len(vts.Spec.TabletPools))
 
@@ -130,8 +177,8 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 		}
 		labels[planetscalev2.CellLabel] = tabletPool.Cell
 
-		// Merge ExtraVitessFlags and ExtraFlags into a new map.
-		extraFlags := make(map[string]string)
+		// Merge recovery flags, ExtraVitessFlags and ExtraFlags into a new map.
+		extraFlags := vtorc.RecoveryFlags(vts.Spec.VitessOrchestrator.Recovery, vts.Spec.ReparentSettings)
 		update.StringMap(&extraFlags, vts.Spec.ExtraVitessFlags)
 		update.StringMap(&extraFlags, vts.Spec.VitessOrchestrator.ExtraFlags)
 
@@ -145,6 +192,7 @@ func (r *ReconcileVitessShard) vtorcSpecs(vts *planetscalev2.VitessShard, parent
 			Cell:              tabletPool.Cell,
 			Zone:              vts.Spec.ZoneMap[tabletPool.Cell],
 			Labels:            labels,
+			Replicas:          *vts.Spec.VitessOrchestrator.Replicas,
 			Resources:         vts.Spec.VitessOrchestrator.Resources,
 			Affinity:          vts.Spec.VitessOrchestrator.Affinity,
 			ExtraFlags:        extraFlags,
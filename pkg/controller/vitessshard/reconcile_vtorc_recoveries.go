@@ -0,0 +1,120 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
+)
+
+// reconcileVtorcRecoveries checks whether the shard's vtorc Pods ran any
+// recoveries since we last looked, and records them in the status, in
+// metrics, and as events. Failing to check isn't worth failing the
+// reconcile over, so problems are only reported as events.
+func (r *ReconcileVitessShard) reconcileVtorcRecoveries(ctx context.Context, vts *planetscalev2.VitessShard, labels map[string]string) {
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list vtorc Pods: %v", err)
+		return
+	}
+
+	now := metav1.Now()
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		counts, err := vtorc.GetRecoveryCounts(ctx, pod.Status.PodIP)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "VtorcRecoveriesCheckFailed", "%v", err)
+			continue
+		}
+
+		// A Pod we haven't checked before has a fresh vtorc, so everything
+		// it counted is new to us.
+		last := &vtorc.RecoveryCounts{}
+		if value := pod.Annotations[vtorc.RecoveryCountsAnnotation]; value != "" {
+			if err := json.Unmarshal([]byte(value), last); err != nil {
+				last = &vtorc.RecoveryCounts{}
+			}
+		}
+		records := newRecoveryRecords(last, counts, pod.Name, now)
+		if len(records) == 0 {
+			continue
+		}
+		for _, record := range records {
+			vts.Status.VitessOrchestrator.RecordRecovery(record)
+			vtorcRecoveryCount.WithLabelValues(vtorcRecoveryLabels(vts, record)...).Add(float64(record.Count))
+			if record.Succeeded {
+				r.recorder.Eventf(vts, corev1.EventTypeNormal, "VtorcRecovery", "vtorc Pod %v ran %v %v recoveries.", pod.Name, record.Count, record.Type)
+			} else {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "VtorcRecoveryFailed", "vtorc Pod %v failed %v %v recoveries.", pod.Name, record.Count, record.Type)
+			}
+		}
+
+		value, err := json.Marshal(counts)
+		if err != nil {
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vtorc.RecoveryCountsAnnotation] = string(value)
+		if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update annotation on Pod %v: %v", pod.Name, err)
+		}
+	}
+}
+
+// newRecoveryRecords returns records of the recoveries counted in current
+// that weren't counted in last yet, sorted by type.
+func newRecoveryRecords(last, current *vtorc.RecoveryCounts, podName string, now metav1.Time) []planetscalev2.VitessOrchestratorRecoveryRecord {
+	var records []planetscalev2.VitessOrchestratorRecoveryRecord
+	add := func(lastCounts, currentCounts map[string]int64, succeeded bool) {
+		for recoveryType, count := range currentCounts {
+			if delta := count - lastCounts[recoveryType]; delta > 0 {
+				records = append(records, planetscalev2.VitessOrchestratorRecoveryRecord{
+					Time:      now,
+					Type:      recoveryType,
+					Succeeded: succeeded,
+					Count:     int32(delta),
+					Pod:       podName,
+				})
+			}
+		}
+	}
+	add(last.Failed, current.Failed, false)
+	add(last.Succeeded, current.Succeeded, true)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Type < records[j].Type
+	})
+	return records
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
+)
+
+func TestNewRecoveryRecords(t *testing.T) {
+	now := metav1.Now()
+	last := &vtorc.RecoveryCounts{
+		Succeeded: map[string]int64{"FixReplica": 3},
+	}
+	current := &vtorc.RecoveryCounts{
+		Succeeded: map[string]int64{"FixReplica": 3, "RecoverDeadPrimary": 1},
+		Failed:    map[string]int64{"ElectNewPrimary": 2},
+	}
+
+	records := newRecoveryRecords(last, current, "vtorc-0", now)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "ElectNewPrimary", records[0].Type)
+		assert.False(t, records[0].Succeeded)
+		assert.Equal(t, int32(2), records[0].Count)
+		assert.Equal(t, "RecoverDeadPrimary", records[1].Type)
+		assert.True(t, records[1].Succeeded)
+		assert.Equal(t, "vtorc-0", records[1].Pod)
+	}
+
+	// Nothing is new once the counts are recorded.
+	assert.Empty(t, newRecoveryRecords(current, current, "vtorc-0", now))
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshard
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// updateZoneSpreadCondition sets the ZoneSpreadSatisfiable condition based on
+// whether the shard's tablet pools guarantee that its replica-type tablets
+// land in at least minReplicaZones distinct zones. The condition is removed
+// if minReplicaZones isn't set.
+func updateZoneSpreadCondition(vts *planetscalev2.VitessShard) {
+	minZones := vts.Spec.MinReplicaZones
+	if minZones == nil {
+		delete(vts.Status.Conditions, planetscalev2.VitessShardZoneSpreadSatisfiable)
+		return
+	}
+
+	zones := vts.Spec.GuaranteedReplicaZones()
+	if zones < *minZones {
+		vts.Status.SetConditionStatus(planetscalev2.VitessShardZoneSpreadSatisfiable, corev1.ConditionFalse, "NotEnoughZones",
+			fmt.Sprintf("Replica tablets are only guaranteed to land in %d of the required %d zones; add replica pools in cells with other zones, or set zoneSpread.minZones on pools in cells that span several zones", zones, *minZones))
+		return
+	}
+	vts.Status.SetConditionStatus(planetscalev2.VitessShardZoneSpreadSatisfiable, corev1.ConditionTrue, "EnoughZones",
+		fmt.Sprintf("Replica tablets are guaranteed to land in at least %d zones", *minZones))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -58,6 +59,7 @@ var log = logrus.WithField("controller", "VitessShard")
 var watchResources = []client.Object{
 	&corev1.Pod{},
 	&corev1.PersistentVolumeClaim{},
+	&policyv1.PodDisruptionBudget{},
 }
 
 // Add creates a new VitessShard Controller and adds it to the Manager. The Manager will set fields on the Controller
@@ -179,15 +181,55 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	if oldStatus.Conditions != nil {
 		vts.Status.Conditions = oldStatus.DeepCopyConditions()
 	}
+	// The replication controller records reparents, so keep those as well.
+	vts.Status.ReparentHistory = oldStatus.ReparentHistory
+	// Canary rollouts, MySQL upgrades and rollout backoff progress across many
+	// passes, so keep track of them too.
+	vts.Status.Canary = oldStatus.Canary
+	vts.Status.UpgradeStatus = oldStatus.UpgradeStatus
+	vts.Status.RolloutBackoff = oldStatus.RolloutBackoff
+	vts.Status.TabletPoolResources = oldStatus.TabletPoolResources
+	// The replication controller records the external failover signals it has seen.
+	vts.Status.ExternalFailover = oldStatus.ExternalFailover
+	// vtorc only tells us how many recoveries it ran, so we keep the history.
+	vts.Status.VitessOrchestrator.RecentRecoveries = oldStatus.VitessOrchestrator.RecentRecoveries
+
+	// Check whether the shard is done restoring from its initialRestore.
+	// NOTE: This must always be done before reconcileTablets, which uses the
+	// condition to decide where tablets restore from.
+	updateInitialRestoreCondition(vts, oldStatus.HasMaster)
+
+	// Decide which scale profiles are in effect, which sets the number of
+	// tablets in each pool.
+	// NOTE: This must always be done before reconcileTablets and anything
+	// else that sizes tablet pools.
+	r.updateScaleProfiles(vts, oldStatus.TabletPoolScaleProfiles, time.Now())
 
 	// Create/update vtorc.
 	vtorcResult, err := r.reconcileVtorc(ctx, vts)
 	resultBuilder.Merge(vtorcResult, err)
 
+	// Recommend resources for tablet pools, and decide when to apply them.
+	// NOTE: This must always be done before reconcileTablets, which uses the
+	// applied recommendations.
+	verticalAutoscalingResult, err := r.reconcileVerticalAutoscaling(ctx, vts, time.Now())
+	resultBuilder.Merge(verticalAutoscalingResult, err)
+
+	// Create/update the monitoring user Secret and ServiceMonitor for the
+	// MySQL exporters.
+	// NOTE: This must always be done before reconcileTablets, since tablet
+	// Pods can't start their exporter without the Secret.
+	mysqldExporterResult, err := r.reconcileMysqldExporter(ctx, vts)
+	resultBuilder.Merge(mysqldExporterResult, err)
+
 	// Create/update desired tablets.
 	tabletResult, err := r.reconcileTablets(ctx, vts)
 	resultBuilder.Merge(tabletResult, err)
 
+	// Create/update PDBs for tablet pools.
+	pdbResult, err := r.reconcileTabletPodDisruptionBudgets(ctx, vts)
+	resultBuilder.Merge(pdbResult, err)
+
 	// Mark tablet pods for disk size updates if needed.
 	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated
 	diskUpdateResult, err := r.reconcileDisk(ctx, vts)
@@ -203,6 +245,33 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	topoResult, err := r.reconcileTopology(ctx, vts)
 	resultBuilder.Merge(topoResult, err)
 
+	// Summarize drain progress, including whether the primary needs a reparent.
+	// NOTE: This must always be done after reconcileTablets and reconcileTopology.
+	updateDrainStatus(vts)
+
+	// Check whether the tablet pools can satisfy semi-sync acks, if enabled.
+	updateSemiSyncCondition(vts)
+
+	// Check whether replica tablets are guaranteed to span enough zones, if required.
+	updateZoneSpreadCondition(vts)
+
+	// Check that the query serving settings of tablet pools take effect, if any are set.
+	updateQueryServingCondition(vts)
+
+	// Check that the mysqld config settings of tablet pools can all be applied, if any are set.
+	updateMysqldConfigCondition(vts)
+
+	// Check that tablet pools asking for ephemeral storage can use it, if any do.
+	r.updateStorageTypeCondition(vts)
+
+	// Summarize errant GTIDs found on tablets, if checks are enabled.
+	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated.
+	updateErrantGTIDCondition(vts)
+
+	// Summarize MySQL config drift found on tablets, if checks are enabled.
+	// NOTE: This must always be done after reconcileTablets, so Status.Tablets is populated.
+	updateMysqldConfigDriftCondition(vts)
+
 	// Take initial or periodic backups, if appropriate.
 	backupResult, err := r.reconcileBackupJob(ctx, vts)
 	resultBuilder.Merge(backupResult, err)
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// runPreFinishHooks runs the shard's pre-finish drain hooks, in order, against
+// the given tablet. It stops at the first hook that fails.
+func runPreFinishHooks(ctx context.Context, wr *wrangler.Wrangler, vts *planetscalev2.VitessShard, tablet *topo.TabletInfo) error {
+	if vts.Spec.DrainHooks == nil || len(vts.Spec.DrainHooks.PreFinish) == 0 {
+		return nil
+	}
+	if tablet == nil {
+		return errors.New("can't run pre-finish hooks: tablet record not found")
+	}
+
+	tmc := wr.TabletManagerClient()
+	for i := range vts.Spec.DrainHooks.PreFinish {
+		drainHook := &vts.Spec.DrainHooks.PreFinish[i]
+
+		switch {
+		case drainHook.SQL != "" && drainHook.Hook != nil:
+			return fmt.Errorf("pre-finish hook %d is invalid: only one of sql or hook may be specified", i)
+		case drainHook.SQL != "":
+			req := &tabletmanagerdata.ExecuteFetchAsDbaRequest{
+				Query:   []byte(drainHook.SQL),
+				DbName:  "_vt",
+				MaxRows: 0,
+			}
+			if _, err := tmc.ExecuteFetchAsDba(ctx, tablet.Tablet, true /*usePool*/, req); err != nil {
+				return fmt.Errorf("pre-finish hook %d failed on tablet %v: %w", i, tablet.AliasString(), err)
+			}
+		case drainHook.Hook != nil:
+			hk := hook.NewHook(drainHook.Hook.Name, drainHook.Hook.Parameters)
+			result, err := tmc.ExecuteHook(ctx, tablet.Tablet, hk)
+			if err != nil {
+				return fmt.Errorf("pre-finish hook %d (%v) failed on tablet %v: %w", i, drainHook.Hook.Name, tablet.AliasString(), err)
+			}
+			if result.ExitStatus != hook.HOOK_SUCCESS {
+				return fmt.Errorf("pre-finish hook %d (%v) failed on tablet %v with exit status %d: %v", i, drainHook.Hook.Name, tablet.AliasString(), result.ExitStatus, result.Stderr)
+			}
+		default:
+			return fmt.Errorf("pre-finish hook %d is invalid: one of sql or hook must be specified", i)
+		}
+	}
+	return nil
+}
@@ -66,6 +66,10 @@ func (r *ReconcileVitessShard) initRestoredShard(ctx context.Context, vts *plane
 	if !vts.Spec.BackupsEnabled() || vts.Spec.UsingExternalDatastore() {
 		return resultBuilder.Result()
 	}
+	// A standby shard waits to be promoted before electing a primary.
+	if vts.Spec.Standby != nil {
+		return resultBuilder.Result()
+	}
 
 	// Check if the shard has a primary.
 	switch vts.Status.HasMaster {
@@ -81,7 +85,10 @@ func (r *ReconcileVitessShard) initRestoredShard(ctx context.Context, vts *plane
 	// we ran the initial vtbackup job and it found that a backup already
 	// existed (the cold restore case), since vtbackup's "initial backup" mode
 	// is idempotent.
-	if vts.Status.HasInitialBackup != corev1.ConditionTrue {
+	//
+	// Shards bootstrapped from another cluster's backups don't get an initial
+	// backup of their own. Their tablets just wait for the source backups.
+	if vts.Status.HasInitialBackup != corev1.ConditionTrue && vts.Spec.InitialRestore == nil {
 		r.recorder.Eventf(vts, corev1.EventTypeNormal, "InitShardWaiting", "can't initialize shard: waiting for initial backup to complete")
 		return resultBuilder.Result()
 	}
@@ -25,6 +25,23 @@ import (
 
 const (
 	metricsSubsystemName = "shard_replication"
+
+	drainStateLabel    = "state"
+	blockedReasonLabel = "reason"
+	repairActionLabel  = "action"
+)
+
+// Reasons for the drainBlockedCount metric.
+const (
+	drainBlockedShardUnhealthy      = "shard_unhealthy"
+	drainBlockedNoPrimary           = "no_primary"
+	drainBlockedPoolDisruption      = "pool_disruption"
+	drainBlockedHookFailed          = "hook_failed"
+	drainBlockedNoPrimaryCandidate  = "no_primary_candidate"
+	drainBlockedReplicationLag      = "replication_lag"
+	drainBlockedSemiSync            = "semi_sync"
+	drainBlockedExternalReparent    = "external_reparent"
+	drainBlockedReparentConcurrency = "reparent_concurrency"
 )
 
 var (
@@ -49,6 +66,13 @@ var (
 		Help:      "PlannedReparentShard attempts for a VitessShard",
 	}, shardMetricLabels)
 
+	emergencyReparentCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "emergency_reparent_count",
+		Help:      "EmergencyReparentShard attempts for a VitessShard",
+	}, shardMetricLabels)
+
 	recoverRestartedMasterCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metrics.Namespace,
 		Subsystem: metricsSubsystemName,
@@ -62,14 +86,73 @@ var (
 		Name:      "reparent_tablet_count",
 		Help:      "ReparentTablet attempts for a VitessShard",
 	}, shardMetricLabels)
+
+	drainStateCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "drain_state_count",
+		Help:      "Number of tablets in a VitessShard in each state of the drain process",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel, drainStateLabel})
+
+	drainDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "drain_duration_seconds",
+		Help:      "Time from acknowledging a tablet drain to marking it as finished",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 12),
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel})
+
+	drainBlockedCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "drain_blocked_count",
+		Help:      "Times drains in a VitessShard could not make progress, by reason",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel, blockedReasonLabel})
+
+	candidatePrimaryLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "candidate_primary_latency_seconds",
+		Help:      "Time taken to choose a candidate primary for a planned reparent",
+		Buckets:   prometheus.DefBuckets,
+	}, shardMetricLabels)
+
+	replicationRepairCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "replication_repair_count",
+		Help:      "Attempts to repair broken replication on tablets in a VitessShard, by action",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel, repairActionLabel, metrics.ResultLabel})
+
+	errantGTIDTablets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "errant_gtid_tablets",
+		Help:      "Number of tablets in a VitessShard found to have errant GTIDs",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel})
+
+	mysqldConfigDriftTablets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystemName,
+		Name:      "mysqld_config_drift_tablets",
+		Help:      "Number of tablets in a VitessShard found to have MySQL global variables that don't match their mysqld config",
+	}, []string{metrics.ClusterLabel, metrics.KeyspaceLabel, metrics.ShardLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		reconcileCount,
 		plannedReparentCount,
+		emergencyReparentCount,
 		recoverRestartedMasterCount,
 		reparentTabletCount,
+		drainStateCount,
+		drainDuration,
+		drainBlockedCount,
+		candidatePrimaryLatency,
+		replicationRepairCount,
+		errantGTIDTablets,
+		mysqldConfigDriftTablets,
 	)
 }
 
@@ -81,3 +164,11 @@ func metricLabels(vts *planetscalev2.VitessShard, err error) []string {
 		metrics.Result(err),
 	}
 }
+
+func shardLabels(vts *planetscalev2.VitessShard, extra ...string) []string {
+	return append([]string{
+		vts.Labels[planetscalev2.ClusterLabel],
+		vts.Labels[planetscalev2.KeyspaceLabel],
+		vts.Spec.Name,
+	}, extra...)
+}
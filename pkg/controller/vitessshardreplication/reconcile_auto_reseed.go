@@ -0,0 +1,187 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// autoReseedMinRestarts is how many times mysqld must have restarted
+	// before a crash-looping tablet is considered unrecoverable.
+	autoReseedMinRestarts = 5
+	// crashLoopBackOffReason is the reason Kubernetes gives for a container
+	// that's waiting to be restarted after crashing repeatedly.
+	crashLoopBackOffReason = "CrashLoopBackOff"
+)
+
+// reconcileAutoReseed reseeds tablets in pools with autoReseed enabled, or with
+// ephemeral storage, whose MySQL can't recover on its own, by deleting their
+// data volume and Pod so they're recreated and restored from the latest backup.
+//
+// Unlike the other replication checks, this also looks at tablets that aren't
+// Ready, since a tablet whose mysqld keeps crashing never becomes Ready.
+func (r *ReconcileVitessShard) reconcileAutoReseed(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	if !autoReseedEnabled(vts) || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, vts.Spec.ReparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	// A reseeded tablet needs a primary to catch up from.
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	// Check tablets deterministically, so we always pick the same one first.
+	tabletAliases := make([]string, 0, len(pods))
+	for tabletAliasStr := range pods {
+		tabletAliases = append(tabletAliases, tabletAliasStr)
+	}
+	sort.Strings(tabletAliases)
+
+	for _, tabletAliasStr := range tabletAliases {
+		pod := pods[tabletAliasStr]
+		if tabletAliasStr == primaryAliasStr || pod.DeletionTimestamp != nil {
+			continue
+		}
+		pool := tabletPool(vts, pod)
+		if !(pool.AutoReseed || pool.EphemeralStorage()) || pool.Mysqld == nil {
+			continue
+		}
+		reason := unrecoverableReason(pod)
+		if reason == "" {
+			continue
+		}
+
+		// Check on the tablet again soon, whether or not we can reseed it.
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+
+		if err := checkAutoReseed(vts, pods, tabletAliasStr); err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeNormal, "AutoReseedDeferred", "not reseeding unrecoverable tablet (%v): %v", reason, err)
+			continue
+		}
+		reseedErr := r.reseedFromBackup(ctx, pod)
+		replicationRepairCount.WithLabelValues(shardLabels(vts, string(planetscalev2.ReseedFromBackupRepairAction), metrics.Result(reseedErr))...).Inc()
+		if reseedErr != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "AutoReseedFailed", "failed to reseed unrecoverable tablet (%v) from backup: %v", reason, reseedErr)
+		} else {
+			r.recorder.Eventf(pod, corev1.EventTypeNormal, "AutoReseed", "reseeding unrecoverable tablet from backup: %v", reason)
+		}
+		// Only reseed one tablet at a time.
+		break
+	}
+
+	return resultBuilder.Result()
+}
+
+// autoReseedEnabled returns whether any tablet pool in the shard has
+// autoReseed enabled. Pools with ephemeral storage always do.
+func autoReseedEnabled(vts *planetscalev2.VitessShard) bool {
+	for i := range vts.Spec.TabletPools {
+		if vts.Spec.TabletPools[i].AutoReseed || vts.Spec.TabletPools[i].EphemeralStorage() {
+			return true
+		}
+	}
+	return false
+}
+
+// unrecoverableReason returns why a tablet's MySQL can't recover on its own,
+// or an empty string if it might still recover.
+func unrecoverableReason(pod *corev1.Pod) string {
+	for i := range pod.Status.ContainerStatuses {
+		status := &pod.Status.ContainerStatuses[i]
+		if status.Name != vttablet.MysqldContainerName {
+			continue
+		}
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == crashLoopBackOffReason && status.RestartCount >= autoReseedMinRestarts {
+			return fmt.Sprintf("mysqld keeps crashing after %d restarts", status.RestartCount)
+		}
+	}
+	if errant := pod.Annotations[vttablet.ErrantGTIDsAnnotation]; errant != "" {
+		return fmt.Sprintf("tablet has errant GTIDs: %v", errant)
+	}
+	if problem := pod.Annotations[vttablet.ReplicationErrorAnnotation]; strings.Contains(strings.ToLower(problem), sourceFatalErrorReadingBinlog) {
+		return fmt.Sprintf("replication has diverged from the primary: %v", problem)
+	}
+	return ""
+}
+
+// checkAutoReseed returns an error if it's not safe to reseed the given tablet:
+// if the shard has no complete backup to restore from, or if any other desired
+// tablet in the shard is down. Requiring every other tablet to be up means at
+// most one tablet per shard is ever being reseeded, and keeps us from making
+// things worse when something is wrong with the whole shard.
+func checkAutoReseed(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod, tabletAliasStr string) error {
+	if !hasCompleteBackup(vts) {
+		return fmt.Errorf("shard has no complete backup to restore from")
+	}
+	otherAliases := make([]string, 0, len(vts.Status.Tablets))
+	for otherAliasStr := range vts.Status.Tablets {
+		if otherAliasStr != tabletAliasStr {
+			otherAliases = append(otherAliases, otherAliasStr)
+		}
+	}
+	sort.Strings(otherAliases)
+	for _, otherAliasStr := range otherAliases {
+		pod := pods[otherAliasStr]
+		switch {
+		case pod == nil:
+			return fmt.Errorf("tablet %v has no Pod", otherAliasStr)
+		case pod.DeletionTimestamp != nil:
+			return fmt.Errorf("tablet %v is being deleted", otherAliasStr)
+		case !podutils.IsPodReady(pod):
+			return fmt.Errorf("tablet %v is not Ready", otherAliasStr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+func TestUnrecoverableReason(t *testing.T) {
+	mysqldStatus := func(restarts int32, waitingReason string) corev1.PodStatus {
+		status := corev1.ContainerStatus{Name: vttablet.MysqldContainerName, RestartCount: restarts}
+		if waitingReason != "" {
+			status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waitingReason}
+		}
+		return corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}}
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want string
+	}{
+		{
+			name: "healthy",
+			pod:  &corev1.Pod{Status: mysqldStatus(0, "")},
+			want: "",
+		},
+		{
+			name: "crash looping",
+			pod:  &corev1.Pod{Status: mysqldStatus(7, crashLoopBackOffReason)},
+			want: "mysqld keeps crashing after 7 restarts",
+		},
+		{
+			name: "crash looping with few restarts",
+			pod:  &corev1.Pod{Status: mysqldStatus(2, crashLoopBackOffReason)},
+			want: "",
+		},
+		{
+			name: "restarted but running again",
+			pod:  &corev1.Pod{Status: mysqldStatus(7, "")},
+			want: "",
+		},
+		{
+			name: "errant GTIDs",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				vttablet.ErrantGTIDsAnnotation: "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:4",
+			}}},
+			want: "tablet has errant GTIDs: 8bc65c84-3fe4-11ed-a912-257f0fcdd6c9:4",
+		},
+		{
+			name: "diverged replication",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				vttablet.ReplicationErrorAnnotation: "IO thread error: " + purgedBinlogsError,
+			}}},
+			want: "replication has diverged from the primary: IO thread error: " + purgedBinlogsError,
+		},
+		{
+			name: "other replication error",
+			pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				vttablet.ReplicationErrorAnnotation: "IO thread error: access denied",
+			}}},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unrecoverableReason(tt.pod))
+		})
+	}
+}
+
+func TestCheckAutoReseed(t *testing.T) {
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}}
+	}
+	deletingPod := readyPod()
+	deletingPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	newShard := func(completeBackups int32) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{}}
+		vts.Status.BackupLocations = []*planetscalev2.ShardBackupLocationStatus{{CompleteBackups: completeBackups}}
+		vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+			"zone1-1": {PoolType: "replica"},
+			"zone1-2": {PoolType: "replica"},
+			"zone2-1": {PoolType: "rdonly"},
+		}
+		return vts
+	}
+
+	tests := []struct {
+		name    string
+		vts     *planetscalev2.VitessShard
+		pods    map[string]*corev1.Pod
+		wantErr bool
+	}{
+		{
+			name:    "others ready",
+			vts:     newShard(1),
+			pods:    map[string]*corev1.Pod{"zone1-1": {}, "zone1-2": readyPod(), "zone2-1": readyPod()},
+			wantErr: false,
+		},
+		{
+			name:    "no complete backup",
+			vts:     newShard(0),
+			pods:    map[string]*corev1.Pod{"zone1-1": {}, "zone1-2": readyPod(), "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "other pool not ready",
+			vts:     newShard(1),
+			pods:    map[string]*corev1.Pod{"zone1-1": {}, "zone1-2": readyPod(), "zone2-1": {}},
+			wantErr: true,
+		},
+		{
+			name:    "other missing",
+			vts:     newShard(1),
+			pods:    map[string]*corev1.Pod{"zone1-1": {}, "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "other deleting",
+			vts:     newShard(1),
+			pods:    map[string]*corev1.Pod{"zone1-1": {}, "zone1-2": deletingPod, "zone2-1": readyPod()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAutoReseed(tt.vts, tt.pods, "zone1-1")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// dataVolumeReplaceDrainReason is the drain reason given to tablets whose
+// data volume is about to be replaced.
+const dataVolumeReplaceDrainReason = "replacing the data volume"
+
+// reconcileDataVolumeReplace replaces the data volumes of tablets that need to
+// grow but whose StorageClass doesn't allow volume expansion, or that are
+// moving to a new StorageClass, as reported in the shard status by the main
+// VitessShard controller. Each such tablet is drained, so it stops being
+// primary if it was, and then its volume and Pod are deleted. The main
+// controller recreates both from the pool's dataVolumeClaimTemplate, and the
+// new tablet restores from the latest backup and catches up on replication
+// before it becomes Ready.
+//
+// Only one tablet is replaced at a time, and only while every other tablet
+// is Ready and there's a backup to restore from.
+func (r *ReconcileVitessShard) reconcileDataVolumeReplace(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	if vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	// Check tablets deterministically, so we always pick the same one first.
+	var tabletAliases []string
+	for tabletAliasStr, status := range vts.Status.Tablets {
+		resize, migration := status.DataVolumeResize, status.DataVolumeMigration
+		if (resize != nil && resize.Phase == planetscalev2.DataVolumeResizeReplacingPhase) ||
+			(migration != nil && migration.Phase == planetscalev2.DataVolumeMigrationReplacingPhase) {
+			tabletAliases = append(tabletAliases, tabletAliasStr)
+		}
+	}
+	if len(tabletAliases) == 0 {
+		return resultBuilder.Result()
+	}
+	sort.Strings(tabletAliases)
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, vts.Spec.ReparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	// A restored tablet needs a primary to catch up from.
+	if !shard.HasPrimary() {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	// Check on the replacement again soon, whatever happens below.
+	resultBuilder.RequeueAfter(replicationRequeueDelay)
+
+	tabletAliasStr := tabletAliases[0]
+	pod := pods[tabletAliasStr]
+	if pod == nil || pod.DeletionTimestamp != nil {
+		// The replacement is already underway.
+		return resultBuilder.Result()
+	}
+	if err := checkAutoReseed(vts, pods, tabletAliasStr); err != nil {
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "DataVolumeReplaceDeferred", "not replacing data volume yet: %v", err)
+		return resultBuilder.Result()
+	}
+
+	// Drain the tablet first, so it isn't serving when it goes away, and a
+	// primary is moved elsewhere with a planned reparent.
+	if !drain.Started(pod) {
+		drain.Start(pod, dataVolumeReplaceDrainReason)
+		if err := r.client.Update(ctx, pod); err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "UpdateFailed", "failed to start drain: %v", err)
+			return resultBuilder.Error(err)
+		}
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "DrainStarted", "started drain: %v", dataVolumeReplaceDrainReason)
+		return resultBuilder.Result()
+	}
+	if !drain.Finished(pod) || tabletAliasStr == primaryAliasStr {
+		return resultBuilder.Result()
+	}
+
+	if err := r.reseedFromBackup(ctx, pod); err != nil {
+		r.recorder.Eventf(pod, corev1.EventTypeWarning, "DataVolumeReplaceFailed", "failed to replace data volume: %v", err)
+		return resultBuilder.Result()
+	}
+	r.recorder.Eventf(pod, corev1.EventTypeNormal, "DataVolumeReplaced", "deleted data volume and Pod, so the tablet is restored from backup onto a new volume")
+	return resultBuilder.Result()
+}
@@ -18,15 +18,16 @@ package vitessshardreplication
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
 	"slices"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"vitess.io/vitess/go/mysql/replication"
+	vtsets "vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
@@ -35,6 +36,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubectl/pkg/util/podutils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -46,17 +49,10 @@ import (
 )
 
 const (
-	// reconcileDrainTimeout is the overall timeout for a single drain pass.
-	// It should be large enough to include other sub-timeouts below.
-	reconcileDrainTimeout = 60 * time.Second
 	// reconcileDrainReadTimeout is the timeout for reading state before we
 	// decide to do anything. These reads should be fast, so we keep this low to
 	// fail fast if topo is down rather than wait until the overall timeout.
 	reconcileDrainReadTimeout = 10 * time.Second
-	// plannedReparentTimeout is the timeout for executing PlannedReparentShard.
-	plannedReparentTimeout = 30 * time.Second
-	// tolerableReplicationLag is the replication lag that is considered acceptable for running a PRS.
-	tolerableReplicationLag = 15 * time.Second
 	// candidatePrimaryTimeout is the timeout for contacting candidate primarys to decide which one to choose.
 	candidatePrimaryTimeout = 2 * time.Second
 )
@@ -77,10 +73,17 @@ This operates in four phases:
 
 We guarantee this invariant:
 
-  - Only one tablet is marked as finished, and once it is, no other tablet will be
-    marked as finished until this tablet is deleted or the drain is aborted
-    (aborting the drain is considered an emergency situation and our invariant
-    could break here).
+  - At most N tablets are marked as finished, where N is the shard's
+    drainMaxUnavailable setting (1 by default), and once N tablets are marked,
+    no other tablet will be marked as finished until one of these tablets is
+    deleted or the drain is aborted (aborting the drain is considered an
+    emergency situation and our invariant could break here).
+
+We also never mark a tablet as finished while too many other desired tablets in
+the same pool (cell, type and name) are down for any reason, similar to what a
+PodDisruptionBudget would enforce. A pool allows as many tablets to be down or
+finished at once as its updateStrategy.maxUnavailable, if set, or else
+drainMaxUnavailable.
 
 This has implications to these situations:
 
@@ -99,16 +102,28 @@ than our original situation.
 This essentially means that we cannot guarantee that during our planned
 decommissioning we won't be racing with an unplanned incident and have the
 drainer delete something at a bad time.  However, by deleting only one tablet at
-a time we still ensure that for shards with three or more tablets we still have
-redundancy during the decommissioning.  Maybe later we can do better.
+a time (by default) we still ensure that for shards with three or more tablets
+we still have redundancy during the decommissioning.  Maybe later we can do
+better.
+
+Shards with many replicas can raise drainMaxUnavailable to let several tablets
+finish draining concurrently. The primary always finishes draining last: it
+isn't considered while any other tablet is waiting to finish draining, and it
+is never marked as finished until it has been reparented away.
+
+Annotations left behind by an aborted drain are normally cleared in phase 2,
+which only runs while the shard is healthy. If they're still there after the
+shard has reported the DrainAborted condition for longer than
+drainAbortGracePeriod, we clear them before phase 1 regardless of health.
 */
 func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, log *logrus.Entry) (reconcile.Result, error) {
-	clusterName := vts.Labels[planetscalev2.ClusterLabel]
 	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
 	resultBuilder := &results.Builder{}
 
 	// Don't hold our slot in the reconcile work queue for too long.
-	ctx, cancel := context.WithTimeout(ctx, reconcileDrainTimeout)
+	// This should be large enough to include the other sub-timeouts below.
+	reparentSettings := vts.Spec.ReparentSettings
+	ctx, cancel := context.WithTimeout(ctx, reparentSettings.ReconcileDrainTimeout.Duration)
 	defer cancel()
 
 	// Put a tighter limit on the initial read phase so we fail fast.
@@ -116,19 +131,8 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 	defer readCancel()
 
 	// Get a list of all our tablet Pods from the cache.
-	labels := map[string]string{
-		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
-		planetscalev2.ClusterLabel:   clusterName,
-		planetscalev2.KeyspaceLabel:  keyspaceName,
-		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
-	}
-
-	podList := &corev1.PodList{}
-	listOpts := &client.ListOptions{
-		Namespace:     vts.Namespace,
-		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
-	}
-	if err := r.client.List(readCtx, podList, listOpts); err != nil {
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
 		return resultBuilder.Error(err)
 	}
@@ -149,23 +153,56 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 		return resultBuilder.RequeueAfter(replicationRequeueDelay)
 	}
 
-	// Create a tablet alias to pod map
-	pods := make(map[string]*corev1.Pod, len(podList.Items))
-	for i := range podList.Items {
-		pod := &podList.Items[i]
-		tabletAlias := vttablet.AliasFromPod(pod)
-		tabletAliasStr := topoproto.TabletAliasString(&tabletAlias)
-		pods[tabletAliasStr] = pod
+	// If a drain was aborted and the drainer hasn't cleaned up after itself
+	// within the grace period, clear the leftover annotations ourselves. We do
+	// this even if the shard is unhealthy, so half-aborted drains don't linger
+	// forever.
+	if drainAbortExpired(vts, reparentSettings.DrainAbortGracePeriod.Duration, time.Now()) {
+		for _, pod := range pods {
+			if drain.Started(pod) || !(drain.Acknowledged(pod) || drain.Finished(pod)) {
+				continue
+			}
+			if err := r.updateDrainStatus(ctx, pod, drain.NotDrainingState); err != nil {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update drain annotation on Pod %v: %v", pod.Name, err)
+				resultBuilder.Error(err)
+				continue
+			}
+			r.recorder.Eventf(pod, corev1.EventTypeNormal, "DrainAbortCleanedUp",
+				"cleared annotations left by a drain that was aborted more than %v ago", reparentSettings.DrainAbortGracePeriod.Duration)
+		}
 	}
 
+	drainRequests := recordDrainStateMetrics(vts, pods)
+
 	//
 	// 1. Check shard health.  Do not take any action if shard is unhealthy.
 	//
 
+	// If emergency failover is allowed, find draining tablets that have been
+	// unreachable for so long that we should consider them lost. These don't
+	// count against the health of the shard.
+	lostTablets := sets.New[string]()
+	if *reparentSettings.AllowEmergencyFailover && !vts.Spec.UsingExternalDatastore() {
+		lostTablets = lostDrainingTablets(pods, reparentSettings.EmergencyFailoverGracePeriod.Duration, time.Now())
+	}
+
+	// While tablets are draining, keep the shard's vtorc from reparenting at
+	// the same time as we do. If the shard is unhealthy, though, let vtorc
+	// recover it.
+	healthErr := isShardHealthy(vts, lostTablets)
+	suspendRecoveries := drainRequests > 0 && healthErr == nil && shard.HasPrimary()
+	if err := r.reconcileVtorcRecoveries(ctx, vts, suspendRecoveries); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "VtorcRecoveriesUpdateFailed", "failed to update vtorc recoveries: %v", err)
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
 	// If the shard is in any way unhealthy, bail out now and do nothing.
-	if err := isShardHealthy(vts); err != nil {
+	if err := healthErr; err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning,
 			"NotReconcilingDrain", "Shard is in an unhealthy state: %v", err)
+		if drainRequests > 0 {
+			drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedShardUnhealthy)...).Inc()
+		}
 		return resultBuilder.Result()
 	}
 
@@ -173,6 +210,9 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 	if !shard.HasPrimary() {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning,
 			"NotReconcilingDrain", "Shard does not have a primary")
+		if drainRequests > 0 {
+			drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedNoPrimary)...).Inc()
+		}
 		return resultBuilder.Result()
 	}
 
@@ -235,15 +275,28 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 		return resultBuilder.Result()
 	}
 
+	// Find our primary so we don't accidentally mark the primary as finished.
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	// If the primary is draining but has been lost, a planned reparent can't
+	// succeed. Do an emergency reparent instead, and wait for the next pass to
+	// see the new primary before doing anything else. If reparents are managed
+	// externally, we leave this to the external tooling as well.
+	if lostTablets.Has(primaryAliasStr) && !vts.Spec.Replication.ExternalReparents {
+		r.emergencyReparent(ctx, vts, wr, pods, primaryAliasStr, lostTablets)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
 	//
 	// 3. Handle updating annotations.  Do not mark current primary as finished.
 	//
 
-	// Find our primary so we don't accidentally mark the primary as finished.
-	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
-
 	// Update all the new tablet states based on the state machine output.
-	transitions := drain.StateTransitions(drains)
+	maxFinished := r.drainMaxFinished(vts)
+	transitions := drain.StateTransitionsWithOrder(finishCandidates(drains, primaryAliasStr), drainOrder(vts, pods), maxFinished)
+	// Keep track of the tablets we mark as finished in this pass, since their
+	// Pods are still Ready until they're deleted.
+	finishing := sets.New[string]()
 	for tabletAliasStr, state := range transitions {
 		// Do not mark the primary as finished.
 		if state == drain.FinishedState && tabletAliasStr == primaryAliasStr {
@@ -256,16 +309,50 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 		}
 
 		pod := pods[tabletAliasStr]
+
+		// Don't mark a tablet as finished while too many other tablets in its
+		// pool are down for any reason, just like a PodDisruptionBudget would.
+		if state == drain.FinishedState {
+			if err := checkPoolDisruption(vts, pods, tabletAliasStr, finishing, poolDisruptionBudget(vts, pod, maxFinished)); err != nil {
+				r.recorder.Eventf(pod, corev1.EventTypeNormal,
+					"DrainWaitingForPool", "not marking drain as finished: %v", err)
+				drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedPoolDisruption)...).Inc()
+				r.reportStuckDrain(vts, pod, err.Error())
+				resultBuilder.RequeueAfter(replicationRequeueDelay)
+				continue
+			}
+		}
+
+		// Run any pre-finish hooks before marking the tablet as finished.
+		// If they fail, leave the tablet as it is and try again later.
+		if state == drain.FinishedState {
+			if err := runPreFinishHooks(ctx, wr, vts, tablets[tabletAliasStr]); err != nil {
+				r.recorder.Eventf(pod, corev1.EventTypeWarning,
+					"DrainHookFailed", "not marking drain as finished: %v", err)
+				drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedHookFailed)...).Inc()
+				r.reportStuckDrain(vts, pod, err.Error())
+				resultBuilder.RequeueAfter(replicationRequeueDelay)
+				continue
+			}
+		}
+
 		if err := r.updateDrainStatus(ctx, pod, state); err != nil {
 			r.recorder.Eventf(vts, corev1.EventTypeWarning,
 				"UpdateFailed", "failed to update drain annotation on Pod %v: %v", pod.Name, err)
 			resultBuilder.Error(err)
+			continue
+		}
+		if state == drain.FinishedState {
+			finishing.Insert(tabletAliasStr)
+			if since, ok := drain.AcknowledgedSince(pod); ok {
+				drainDuration.WithLabelValues(shardLabels(vts)...).Observe(time.Since(since).Seconds())
+			}
 		}
 	}
 
 	// 4. Check if we need to perform any operations like disabling fast shutdown
 	// for upgrades here.
-	if err := r.disableFastShutdown(ctx, wr, pods, tablets, vts.Spec.Images.Mysqld.Image(), log); err != nil {
+	if err := r.disableFastShutdown(ctx, wr, pods, tablets, vts.Spec.Images.Mysqld, log); err != nil {
 		r.recorder.Eventf(vts, corev1.EventTypeWarning,
 			"MysqldSafeUpgradeFailed", "failed to disable fast shutdown: %v", err)
 		return resultBuilder.Error(err)
@@ -300,22 +387,90 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 		return resultBuilder.Result()
 	}
 
+	// If reparents are managed externally, all we can do is wait for the
+	// external tooling to move the primary elsewhere.
+	if vts.Spec.Replication.ExternalReparents {
+		reason := "waiting for the primary to be reparented by external tooling"
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "WaitingForExternalReparent", "unable to drain primary tablet %v: %v", primaryAliasStr, reason)
+		r.reportStuckDrain(vts, pods[primaryAliasStr], reason)
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedExternalReparent)...).Inc()
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Hold the shard lock from here through the reparent, so vtorc and
+	// anything else that reparents leave the shard alone in the meantime.
+	// The planned reparent sees that we hold the lock and uses it. If someone
+	// else holds it, try again later rather than wait.
+	ctx, unlock, err := wr.TopoServer().TryLockShard(ctx, keyspaceName, vts.Spec.Name, drainLockAction)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "DrainWaitingForShardLock", "not reparenting primary tablet %v yet: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	var reparentErr error
+	defer unlock(&reparentErr)
+
+	// Make sure nobody reparented the shard before we got the lock.
+	shard, err = wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if currentPrimary := topoproto.TabletAliasString(shard.PrimaryAlias); currentPrimary != primaryAliasStr {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "NotReparentingPrimary", "primary changed from %v to %v before we could reparent", primaryAliasStr, currentPrimary)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
 	// See if there's a candidate primary for a planned reparent.
-	newPrimary := candidatePrimary(ctx, wr, shard, tablets, pods, vts.Spec.UsingExternalDatastore())
+	candidateStart := time.Now()
+	newPrimary, rejected := candidatePrimary(ctx, wr, vts, shard, tablets, pods)
+	var candidateErr error
+	if newPrimary == nil {
+		candidateErr = errors.New("no candidate primary")
+	}
+	candidatePrimaryLatency.WithLabelValues(metricLabels(vts, candidateErr)...).Observe(time.Since(candidateStart).Seconds())
 	if newPrimary == nil {
-		r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainBlocked", "unable to drain primary tablet %v: no other tablet is a suitable primary candidate", primaryAliasStr)
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedNoPrimaryCandidate)...).Inc()
+		reason := fmt.Sprintf("no other tablet is a suitable primary candidate [%v]", strings.Join(rejected, "; "))
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainBlocked", "unable to drain primary tablet %v: %v", primaryAliasStr, reason)
+		r.reportStuckDrain(vts, pods[primaryAliasStr], reason)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't move the primary into a cell whose replicas are lagging.
+	if err := r.checkCellReplicationLag(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainBlocked", "unable to drain primary tablet %v: %v", primaryAliasStr, err)
+		r.reportStuckDrain(vts, pods[primaryAliasStr], err.Error())
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedReplicationLag)...).Inc()
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't promote a primary that couldn't get enough semi-sync acks.
+	if err := checkSemiSyncAckers(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainBlocked", "unable to drain primary tablet %v: %v", primaryAliasStr, err)
+		r.reportStuckDrain(vts, pods[primaryAliasStr], err.Error())
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedSemiSync)...).Inc()
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't reparent too many shards in the keyspace at once.
+	release, err := r.reparents.acquire(vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "DrainWaitingForReparentSlot", "not reparenting primary tablet %v yet: %v", primaryAliasStr, err)
+		r.reportStuckDrain(vts, pods[primaryAliasStr], err.Error())
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedReparentConcurrency)...).Inc()
 		return resultBuilder.RequeueAfter(replicationRequeueDelay)
 	}
+	defer release()
 
 	// Perform a planned reparent.
+	plannedReparentTimeout := reparentSettings.PlannedReparentTimeout.Duration
 	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
 	defer reparentCancel()
 
-	var reparentErr error
 	if vts.Spec.UsingExternalDatastore() {
 		reparentErr = r.handleExternalReparent(ctx, vts, wr, newPrimary.Alias, shard.PrimaryAlias)
 	} else {
-		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, plannedReparentTimeout, tolerableReplicationLag)
+		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, plannedReparentTimeout, reparentSettings.TolerableReplicationLag.Duration)
 	}
 
 	if reparentErr != nil {
@@ -325,16 +480,153 @@ func (r *ReconcileVitessShard) reconcileDrain(ctx context.Context, vts *planetsc
 	}
 
 	plannedReparentCount.WithLabelValues(metricLabels(vts, reparentErr)...).Inc()
+	r.recordReparent(ctx, vts, drainReparentReason(pods[primaryAliasStr]), primaryAliasStr, newPrimary.AliasString(), reparentErr)
 
 	return resultBuilder.Result()
 }
 
+// tabletPods returns all the shard's tablet Pods from the cache, keyed by
+// tablet alias.
+func (r *ReconcileVitessShard) tabletPods(ctx context.Context, vts *planetscalev2.VitessShard) (map[string]*corev1.Pod, error) {
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VttabletComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return nil, err
+	}
+
+	// Create a tablet alias to pod map
+	pods := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		tabletAlias := vttablet.AliasFromPod(pod)
+		tabletAliasStr := topoproto.TabletAliasString(&tabletAlias)
+		pods[tabletAliasStr] = pod
+	}
+	return pods, nil
+}
+
+// finishCandidates returns the drain states to base our state transitions on.
+// While any other tablet is still waiting to finish draining, the primary is
+// left out, so it always finishes draining last. Otherwise, once the primary
+// is first in line, it would hold up the other tablets until it has been
+// reparented away.
+//
+// Leaving out a tablet that is only acknowledged can only reduce the number
+// of tablets marked as finished, so the state machine's invariant still holds.
+func finishCandidates(drains map[string]drain.State, primaryAliasStr string) map[string]drain.State {
+	if drains[primaryAliasStr] != drain.AcknowledgedState {
+		return drains
+	}
+	othersAcknowledged := false
+	for tabletAliasStr, state := range drains {
+		if tabletAliasStr != primaryAliasStr && state == drain.AcknowledgedState {
+			othersAcknowledged = true
+			break
+		}
+	}
+	if !othersAcknowledged {
+		return drains
+	}
+	candidates := make(map[string]drain.State, len(drains)-1)
+	for tabletAliasStr, state := range drains {
+		if tabletAliasStr != primaryAliasStr {
+			candidates[tabletAliasStr] = state
+		}
+	}
+	return candidates
+}
+
+// drainOrder returns the order in which draining tablets should be considered
+// for being marked as finished, based on the drainOrder of the pool each
+// tablet belongs to.
+//
+// This must only depend on the shard spec, not on which tablet is currently
+// primary, so the choice stays the same across a reparent. The primary is
+// kept last by finishCandidates instead.
+func drainOrder(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod) map[string]int {
+	order := make(map[string]int, len(pods))
+	for tabletAliasStr, pod := range pods {
+		order[tabletAliasStr] = int(*tabletPool(vts, pod).DrainOrder)
+	}
+	return order
+}
+
+// primaryEligibilityWeights returns the primaryEligibilityWeight of the pool
+// each tablet belongs to.
+func primaryEligibilityWeights(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod) map[string]int32 {
+	weights := make(map[string]int32, len(pods))
+	for tabletAliasStr, pod := range pods {
+		weights[tabletAliasStr] = tabletPool(vts, pod).PrimaryEligibilityWeight
+	}
+	return weights
+}
+
+// tabletPool returns the tablet pool in the shard spec that the given tablet
+// Pod belongs to. Tablets that don't belong to any pool (e.g. ones being
+// turned down) get a defaulted pool with only the cell, type and name set.
+//
+// NOTE: The shard must have been defaulted, and the result must not be modified.
+func tabletPool(vts *planetscalev2.VitessShard, pod *corev1.Pod) *planetscalev2.VitessShardTabletPool {
+	podPool := &planetscalev2.VitessShardTabletPool{
+		Cell: pod.Labels[planetscalev2.CellLabel],
+		Type: planetscalev2.VitessTabletPoolType(pod.Labels[planetscalev2.TabletTypeLabel]),
+		Name: pod.Labels[planetscalev2.TabletPoolNameLabel],
+	}
+	for i := range vts.Spec.TabletPools {
+		pool := &vts.Spec.TabletPools[i]
+		if pool.IsMatch(podPool) {
+			return pool
+		}
+	}
+	planetscalev2.DefaultVitessShardTabletPool(podPool)
+	return podPool
+}
+
+// drainMaxFinished returns the maximum number of tablets in the shard that may
+// be marked as finished draining at the same time.
+func (r *ReconcileVitessShard) drainMaxFinished(vts *planetscalev2.VitessShard) int {
+	maxUnavailable := vts.Spec.Replication.DrainMaxUnavailable
+	if maxUnavailable == nil {
+		return 1
+	}
+	maxFinished, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, desiredTablets(vts), false)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidDrainMaxUnavailable", "invalid drainMaxUnavailable value %q, falling back to 1: %v", maxUnavailable.String(), err)
+		return 1
+	}
+	if maxFinished < 1 {
+		return 1
+	}
+	return maxFinished
+}
+
+// desiredTablets returns the number of tablets that the shard spec asks for,
+// not counting extra tablets that are only there during a surge, or ones that
+// are being turned down.
+func desiredTablets(vts *planetscalev2.VitessShard) int {
+	total := 0
+	for i := range vts.Spec.TabletPools {
+		total += int(vts.Status.PoolReplicas(&vts.Spec.TabletPools[i]))
+	}
+	return total
+}
+
 func (r *ReconcileVitessShard) handleExternalReparent(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, newPrimaryAlias, oldPrimaryAlias *topodatapb.TabletAlias) error {
 	err := wr.TabletExternallyReparented(ctx, newPrimaryAlias)
 
-	if err == nil {
-		// TODO: Remove this after all externalprimary tablets have been updated
-		// to set the -demote_primary_type=SPARE flag.
+	// The old primary still points at the same writer endpoint, unless the
+	// datastore says otherwise, so keep it from serving replica traffic.
+	if datastore := vts.Spec.ExternalMasterDatastore(); err == nil && (datastore == nil || *datastore.DemotePrimaryToSpare) {
 		err = wr.ChangeTabletType(ctx, oldPrimaryAlias, topodatapb.TabletType_SPARE)
 	}
 
@@ -375,8 +667,13 @@ func (r *ReconcileVitessShard) updateDrainStatus(ctx context.Context, pod *corev
 	return r.client.Update(ctx, pod)
 }
 
-func isShardHealthy(vts *planetscalev2.VitessShard) error {
+// isShardHealthy returns an error if any tablet in the shard, other than the
+// ignored ones, is not Available.
+func isShardHealthy(vts *planetscalev2.VitessShard, ignoredTablets sets.Set[string]) error {
 	for name, tablet := range vts.Status.Tablets {
+		if ignoredTablets.Has(name) {
+			continue
+		}
 		if tablet.Available != corev1.ConditionTrue {
 			return fmt.Errorf("tablet %v is not Available", name)
 		}
@@ -384,51 +681,212 @@ func isShardHealthy(vts *planetscalev2.VitessShard) error {
 	return nil
 }
 
-// candidatePrimary chooses a candidate tablet to be the new primary in a planned
-// reparent (when the current primary is still healthy).
-func candidatePrimary(ctx context.Context, wr *wrangler.Wrangler, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo, pods map[string]*corev1.Pod, usingExternal bool) *topo.TabletInfo {
-	candidates := []*topo.TabletInfo{}
-	for tabletAliasStr, tablet := range tablets {
-		// It must not be the current primary.
-		if topoproto.TabletAliasEqual(tablet.Alias, shard.PrimaryAlias) {
+// checkPoolDisruption returns an error if budget or more desired tablets, other
+// than the given one, in the same pool (cell, type and name) as the given tablet
+// are down. A tablet is considered down if its Pod is missing, being deleted, or
+// not Ready, or if its drain has finished (or is in the finishing set), since
+// its Pod may be deleted at any moment. Desired tablets without a Pod don't tell
+// us their pool name, so they count against every pool of the same cell and type.
+func checkPoolDisruption(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod, tabletAliasStr string, finishing sets.Set[string], budget int) error {
+	tablet, ok := vts.Status.Tablets[tabletAliasStr]
+	if !ok {
+		// This is not a desired tablet, so it's not part of any pool.
+		return nil
+	}
+	tabletAlias, err := topoproto.ParseTabletAlias(tabletAliasStr)
+	if err != nil {
+		return err
+	}
+	poolName := ""
+	if pod := pods[tabletAliasStr]; pod != nil {
+		poolName = pod.Labels[planetscalev2.TabletPoolNameLabel]
+	}
+
+	var down []string
+	for otherAliasStr, other := range vts.Status.Tablets {
+		if otherAliasStr == tabletAliasStr || other.PoolType != tablet.PoolType {
+			continue
+		}
+		otherAlias, err := topoproto.ParseTabletAlias(otherAliasStr)
+		if err != nil {
+			return err
+		}
+		if otherAlias.Cell != tabletAlias.Cell {
 			continue
 		}
 
-		// The Pod must be Ready.
-		pod := pods[tabletAliasStr]
-		if pod == nil {
+		pod := pods[otherAliasStr]
+		if pod != nil && pod.Labels[planetscalev2.TabletPoolNameLabel] != poolName {
 			continue
 		}
+		switch {
+		case pod == nil:
+			down = append(down, fmt.Sprintf("tablet %v in the same pool has no Pod", otherAliasStr))
+		case pod.DeletionTimestamp != nil:
+			down = append(down, fmt.Sprintf("tablet %v in the same pool is being deleted", otherAliasStr))
+		case !podutils.IsPodReady(pod):
+			down = append(down, fmt.Sprintf("tablet %v in the same pool is not Ready", otherAliasStr))
+		case drain.Finished(pod) || finishing.Has(otherAliasStr):
+			down = append(down, fmt.Sprintf("tablet %v in the same pool has finished draining", otherAliasStr))
+		}
+	}
+	if len(down) < budget {
+		return nil
+	}
+	sort.Strings(down)
+	return errors.New(strings.Join(down, "; "))
+}
 
-		// It must be a "replica" type for local MySQL, or any type for external primary pools.
-		if usingExternal {
-			if pod.Labels[planetscalev2.TabletTypeLabel] != planetscalev2.ExternalMasterTabletPoolName {
-				continue
-			}
-			// Because we aren't handling MySQL replication, if a tablet thinks it's primary then it should be safe.
-			if tablet.Type != topodatapb.TabletType_SPARE && tablet.Type != topodatapb.TabletType_PRIMARY {
-				continue
-			}
-		} else {
-			if tablet.Type != topodatapb.TabletType_REPLICA {
-				continue
+// poolDisruptionBudget returns how many tablets in the given tablet's pool may
+// be down or finished draining at the same time: the pool's
+// updateStrategy.maxUnavailable if it sets one, or else maxFinished.
+func poolDisruptionBudget(vts *planetscalev2.VitessShard, pod *corev1.Pod, maxFinished int) int {
+	if maxUnavailable, ok := vts.Status.PoolMaxUnavailable(tabletPool(vts, pod)); ok {
+		return maxUnavailable
+	}
+	return maxFinished
+}
+
+// lostDrainingTablets returns the aliases of tablets that have been requested
+// to drain, but whose Pods have not been Ready for longer than gracePeriod.
+func lostDrainingTablets(pods map[string]*corev1.Pod, gracePeriod time.Duration, now time.Time) sets.Set[string] {
+	lost := sets.New[string]()
+	for tabletAliasStr, pod := range pods {
+		if !drain.Started(pod) || podutils.IsPodReady(pod) {
+			continue
+		}
+		// The Ready condition's transition time tells us how long the Pod has
+		// been unready. If it's missing, the Pod has never been Ready since it
+		// was created, so we go by the creation time instead.
+		unreadySince := pod.CreationTimestamp.Time
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady {
+				unreadySince = cond.LastTransitionTime.Time
+				break
 			}
 		}
+		if now.Sub(unreadySince) > gracePeriod {
+			lost.Insert(tabletAliasStr)
+		}
+	}
+	return lost
+}
+
+// reportStuckDrain emits a DrainStuck event with the reason a tablet's drain
+// is blocked, if it has been acknowledged for longer than the drain timeout.
+func (r *ReconcileVitessShard) reportStuckDrain(vts *planetscalev2.VitessShard, pod *corev1.Pod, reason string) {
+	drainTimeout := vts.Spec.ReparentSettings.DrainTimeout.Duration
+	if pod == nil || !drain.Stuck(pod, drainTimeout, time.Now()) {
+		return
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeWarning, "DrainStuck",
+		"drain of Pod %v has been acknowledged for longer than %v: %v", pod.Name, drainTimeout, reason)
+}
+
+// recordDrainStateMetrics updates the number of tablets in each state of the
+// drain process, and returns the number of tablets that have drain requests.
+func recordDrainStateMetrics(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod) int {
+	counts := map[drain.State]int{}
+	for _, pod := range pods {
+		if !drain.Started(pod) {
+			continue
+		}
+		state, _ := drain.GetState(pod)
+		counts[state]++
+	}
+	drainStateCount.WithLabelValues(shardLabels(vts, "draining")...).Set(float64(counts[drain.DrainingState]))
+	drainStateCount.WithLabelValues(shardLabels(vts, "acknowledged")...).Set(float64(counts[drain.AcknowledgedState]))
+	drainStateCount.WithLabelValues(shardLabels(vts, "finished")...).Set(float64(counts[drain.FinishedState]))
+	return counts[drain.DrainingState] + counts[drain.AcknowledgedState] + counts[drain.FinishedState]
+}
+
+// drainAbortExpired returns whether the shard has reported the DrainAborted
+// condition for longer than gracePeriod.
+func drainAbortExpired(vts *planetscalev2.VitessShard, gracePeriod time.Duration, now time.Time) bool {
+	cond, ok := vts.Status.Conditions[planetscalev2.VitessShardDrainAborted]
+	if !ok || cond.Status != corev1.ConditionTrue || cond.LastTransitionTime == nil {
+		return false
+	}
+	return now.Sub(cond.LastTransitionTime.Time) > gracePeriod
+}
+
+// emergencyReparent performs an emergency reparent away from a lost primary.
+func (r *ReconcileVitessShard) emergencyReparent(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, pods map[string]*corev1.Pod, primaryAliasStr string, lostTablets sets.Set[string]) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	waitReplicasTimeout := vts.Spec.ReparentSettings.PlannedReparentTimeout.Duration
+
+	// Even emergency reparents wait their turn, since many at once are what
+	// reparentConcurrency is meant to prevent.
+	release, err := r.reparents.acquire(vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "EmergencyReparentDeferred", "not reparenting away from lost primary tablet %v yet: %v", primaryAliasStr, err)
+		drainBlockedCount.WithLabelValues(shardLabels(vts, drainBlockedReparentConcurrency)...).Inc()
+		return
+	}
+	defer release()
+
+	r.recorder.Eventf(vts, corev1.EventTypeWarning, "EmergencyReparent", "primary tablet %v is draining and has been unreachable for longer than the grace period; attempting emergency reparent", primaryAliasStr)
+
+	// Let Vitess choose the most up-to-date replica as the new primary, but
+	// don't wait on any of the other tablets we already consider lost, or on
+	// delayed replicas, which would hold up the reparent for the whole delay.
+	ignoredTablets := vtsets.New[string](lostTablets.UnsortedList()...)
+	for tabletAliasStr, pod := range pods {
+		if delayedTablet(vts, pod) {
+			ignoredTablets.Insert(tabletAliasStr)
+		}
+	}
+	ignoredTablets.Delete(primaryAliasStr)
+	err = wr.EmergencyReparentShard(ctx, keyspaceName, vts.Spec.Name, nil, waitReplicasTimeout, ignoredTablets, false /* preventCrossCellPromotion */, false /* waitForAllTablets */)
+	newPrimaryAliasStr := ""
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "EmergencyReparentFailed", "emergency reparent away from primary %v failed: %v", primaryAliasStr, err)
+	} else {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "EmergencyReparent", "emergency reparent away from primary %v succeeded", primaryAliasStr)
+		// Vitess chose the new primary, so look it up for the history.
+		if shard, getErr := wr.TopoServer().GetShard(ctx, keyspaceName, vts.Spec.Name); getErr == nil && shard.HasPrimary() {
+			newPrimaryAliasStr = topoproto.TabletAliasString(shard.PrimaryAlias)
+		}
+	}
+
+	emergencyReparentCount.WithLabelValues(metricLabels(vts, err)...).Inc()
+	r.recordReparent(ctx, vts, planetscalev2.FailoverReparentReason, primaryAliasStr, newPrimaryAliasStr, err)
+}
 
-		if !podutils.IsPodReady(pod) {
+// candidatePrimary chooses a candidate tablet to be the new primary in a planned
+// reparent (when the current primary is still healthy).
+//
+// If there is no candidate, it also returns the reason each tablet was
+// rejected, sorted by tablet alias, to help diagnose why the reparent is blocked.
+func candidatePrimary(ctx context.Context, wr *wrangler.Wrangler, vts *planetscalev2.VitessShard, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo, pods map[string]*corev1.Pod) (*topo.TabletInfo, []string) {
+	usingExternal := vts.Spec.UsingExternalDatastore()
+	candidates := []*topo.TabletInfo{}
+	rejected := []string{}
+	for tabletAliasStr, tablet := range tablets {
+		// It must not be the current primary.
+		if topoproto.TabletAliasEqual(tablet.Alias, shard.PrimaryAlias) {
+			continue
+		}
+		if reason := candidateRejection(tablet, pods[tabletAliasStr], usingExternal); reason != "" {
+			rejected = append(rejected, fmt.Sprintf("%v: %v", tabletAliasStr, reason))
 			continue
 		}
-		// The Pod must not have a drain request, or have already entered the
-		// drain state machine.
-		if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+		// It must not be a delayed replica, which is behind on purpose.
+		if delayedTablet(vts, pods[tabletAliasStr]) {
+			rejected = append(rejected, fmt.Sprintf("%v: delayed replica", tabletAliasStr))
 			continue
 		}
-		// TODO(enisoc): Add other criteria, such as perferred primary cells.
 		// For now, this is good enough to be a candidate.
 		candidates = append(candidates, tablet)
 	}
+	// Only consider candidates outside the preferred cells if there are no
+	// eligible candidates inside them.
+	candidates = preferredCandidates(candidates, vts.Spec.PreferredPrimaryCells)
+	// Of those, only consider candidates in the pools with the highest weight.
+	candidates = weightedCandidates(candidates, primaryEligibilityWeights(vts, pods))
 	if len(candidates) == 0 {
-		return nil
+		sort.Strings(rejected)
+		return nil, rejected
 	}
 
 	// The last check we do is to look for the candidate whose replication
@@ -471,7 +929,79 @@ func candidatePrimary(ctx context.Context, wr *wrangler.Wrangler, shard *topo.Sh
 		bestCandidate = candidates[0]
 	}
 
-	return bestCandidate
+	return bestCandidate, nil
+}
+
+// candidateRejection returns the reason a tablet can't be a candidate primary
+// in a planned reparent, or an empty string if it can be.
+func candidateRejection(tablet *topo.TabletInfo, pod *corev1.Pod, usingExternal bool) string {
+	// The Pod must be Ready.
+	if pod == nil {
+		return "Pod not found"
+	}
+
+	// It must be a "replica" type for local MySQL, or any type for external primary pools.
+	if usingExternal {
+		if pod.Labels[planetscalev2.TabletTypeLabel] != planetscalev2.ExternalMasterTabletPoolName {
+			return "not in an externalmaster pool"
+		}
+		// Because we aren't handling MySQL replication, if a tablet thinks it's primary then it should be safe.
+		if tablet.Type != topodatapb.TabletType_SPARE && tablet.Type != topodatapb.TabletType_PRIMARY {
+			return fmt.Sprintf("tablet type is %v", tablet.Type)
+		}
+	} else {
+		if tablet.Type != topodatapb.TabletType_REPLICA {
+			return fmt.Sprintf("tablet type is %v", tablet.Type)
+		}
+	}
+
+	if !podutils.IsPodReady(pod) {
+		return "Pod not Ready"
+	}
+	// The Pod must not have a drain request, or have already entered the
+	// drain state machine.
+	if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+		return "draining"
+	}
+	return ""
+}
+
+// preferredCandidates returns the subset of candidates that live in one of the
+// preferred cells. If there are no preferred cells, or none of the candidates
+// live in a preferred cell, it returns all the candidates.
+func preferredCandidates(candidates []*topo.TabletInfo, preferredCells []string) []*topo.TabletInfo {
+	if len(preferredCells) == 0 {
+		return candidates
+	}
+	var result []*topo.TabletInfo
+	for _, tablet := range candidates {
+		if slices.Contains(preferredCells, tablet.Alias.GetCell()) {
+			result = append(result, tablet)
+		}
+	}
+	if len(result) == 0 {
+		return candidates
+	}
+	return result
+}
+
+// weightedCandidates returns the subset of candidates with the highest weight,
+// keyed by tablet alias. Candidates with no weight have a weight of 0.
+func weightedCandidates(candidates []*topo.TabletInfo, weights map[string]int32) []*topo.TabletInfo {
+	var result []*topo.TabletInfo
+	var highestWeight int32
+	for _, tablet := range candidates {
+		weight := weights[tablet.AliasString()]
+		if len(result) > 0 && weight < highestWeight {
+			continue
+		}
+		if len(result) == 0 || weight > highestWeight {
+			result = nil
+			highestWeight = weight
+		}
+		result = append(result, tablet)
+	}
+	return result
 }
 
 func (r *ReconcileVitessShard) disableFastShutdown(
@@ -479,7 +1009,7 @@ func (r *ReconcileVitessShard) disableFastShutdown(
 	wr *wrangler.Wrangler,
 	pods map[string]*corev1.Pod,
 	tablets map[string]*topo.TabletInfo,
-	desiredImage string,
+	desired *planetscalev2.MysqldImage,
 	log *logrus.Entry,
 ) error {
 	const disableFastShutdown = "set @@global.innodb_fast_shutdown = 0"
@@ -508,7 +1038,7 @@ func (r *ReconcileVitessShard) disableFastShutdown(
 			}
 		}
 
-		needsSafe, err := safeMysqldUpgrade(current, desiredImage)
+		needsSafe, err := safeMysqldUpgrade(current, desired)
 		if err != nil {
 			return err
 		}
@@ -527,65 +1057,53 @@ func (r *ReconcileVitessShard) disableFastShutdown(
 	return nil
 }
 
-var mysqlImageVersion = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)`)
-
-func safeMysqldUpgrade(currentImage, desiredImage string) (bool, error) {
+// safeMysqldUpgrade returns whether moving a tablet from its current mysqld
+// image to the desired one needs a safe upgrade, with fast shutdown disabled,
+// or an error if it's a downgrade that MySQL doesn't support.
+//
+// Versions come from the image tags, which can be MySQL or Percona Server
+// versions. If the desired tag doesn't say, the series implied by its flavor
+// field is used instead.
+func safeMysqldUpgrade(currentImage string, desired *planetscalev2.MysqldImage) (bool, error) {
+	desiredImage := ""
+	if desired != nil {
+		desiredImage = desired.Image()
+	}
 	if currentImage == "" || desiredImage == "" {
 		// No action if we have unknown versions.
 		return false, nil
 	}
 
-	// Quick check so no regexp matching is needed for the most common
+	// Quick check so no version parsing is needed for the most common
 	// case where nothing changes.
 	if desiredImage == currentImage {
 		return false, nil
 	}
 
-	currentParts := strings.SplitN(currentImage, ":", 2)
-	if len(currentParts) != 2 {
-		return false, nil
-	}
-
-	desiredParts := strings.SplitN(desiredImage, ":", 2)
-	if len(desiredParts) != 2 {
-		return false, nil
-	}
-
-	current := currentParts[1]
-	desired := desiredParts[1]
-
-	curStrParts := mysqlImageVersion.FindStringSubmatch(current)
-	if len(curStrParts) != 4 {
-		// Invalid version, assume that we need to do a safe upgrade.
+	cur, ok := vttablet.ParseMysqldVersion(currentImage)
+	if !ok {
+		// We can't tell what the tablet runs, so assume we need a safe upgrade.
 		return true, nil
 	}
-	dstStrParts := mysqlImageVersion.FindStringSubmatch(desired)
-	if len(dstStrParts) != 4 {
+	dst, ok := vttablet.DesiredMysqldVersion(desired)
+	if !ok {
 		// Invalid version, assume that we need to do a safe upgrade.
 		return true, nil
 	}
-	if slices.Equal(curStrParts, dstStrParts) {
+	if cur == dst {
 		return false, nil
 	}
-	dstParts := make([]int, len(dstStrParts)-1)
-	curParts := make([]int, len(curStrParts)-1)
-	for i, part := range dstStrParts[1:] {
-		// We already matched with `\d_` so there's no
-		// way this can trigger an error.
-		dstParts[i], _ = strconv.Atoi(part)
-	}
 
-	for i, part := range curStrParts[1:] {
-		// We already matched with `\d_` so there's no
-		// way this can trigger an error.
-		curParts[i], _ = strconv.Atoi(part)
+	if dst.Major < cur.Major {
+		return false, fmt.Errorf("cannot downgrade major version from %s to %s", cur, dst)
 	}
-
-	if dstParts[0] < curParts[0] {
-		return false, fmt.Errorf("cannot downgrade major version from %s to %s", current, desired)
+	if dst.Major == cur.Major && dst.Minor < cur.Minor {
+		return false, fmt.Errorf("cannot downgrade minor version from %s to %s", cur, dst)
 	}
-	if dstParts[0] == curParts[1] && dstParts[1] < curParts[1] {
-		return false, fmt.Errorf("cannot downgrade minor version from %s to %s", current, desired)
+
+	// For any major or minor version change we always need safe upgrade.
+	if dst.Series() != cur.Series() {
+		return true, nil
 	}
 
 	// Alright, here it gets more tricky. MySQL has had a complicated release history. For the 8.0 series,
@@ -601,22 +1119,29 @@ func safeMysqldUpgrade(currentImage, desiredImage string) (bool, error) {
 	// fast shutdown enabled.
 	// Specifically, it calls out that "MySQL 8.0.34+ will become bugfix only release (red)". This means
 	// that we use that version as a cut-off point here for when we need to disable fast shutdown or not.
-	if dstParts[0] == 8 && dstParts[1] == 0 && curParts[0] == 8 && curParts[1] == 0 {
+	// The same goes for Percona Server, whose versions follow the MySQL release they're based on.
+	if dst.Major == 8 && dst.Minor == 0 {
 		// Our upgrade process stays within the 8.0.x version range.
-		if dstParts[2] >= 34 && curParts[2] >= 34 {
+		if !dst.HasPatch || !cur.HasPatch {
+			// We can't tell if both sides are bugfix only releases.
+			return true, nil
+		}
+		if dst.Patch >= 34 && cur.Patch >= 34 {
 			// No need for safe upgrade if both versions are 8.0.34 or higher.
 			return false, nil
 		}
 		// We can't downgrade within the 8.0.x series before 8.0.34.
-		if dstParts[2] < curParts[2] {
-			return false, fmt.Errorf("cannot downgrade patch version from %s to %s", current, desired)
+		if dst.Patch < cur.Patch {
+			return false, fmt.Errorf("cannot downgrade patch version from %s to %s", cur, dst)
 		}
 		// Always need safe upgrade if we change the patch release for 8.0.x before 8.0.34.
-		return dstParts[2] != curParts[2], nil
+		return true, nil
 	}
 
-	// For any major or minor version change we always need safe upgrade.
-	return dstParts[0] != curParts[0] || dstParts[1] != curParts[1], nil
+	// Patch releases of other series, including the 8.4 LTS series, don't
+	// change the on-disk format, so they can be upgraded or downgraded in
+	// place with fast shutdown enabled.
+	return false, nil
 }
 
 type candidateInfo struct {
@@ -18,8 +18,20 @@ package vitessshardreplication
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
 )
 
 func TestSafeMysqldUpgrade(t *testing.T) {
@@ -27,6 +39,7 @@ func TestSafeMysqldUpgrade(t *testing.T) {
 		name      string
 		current   string
 		desired   string
+		flavor    *planetscalev2.MysqldImage
 		needsSafe bool
 		err       string
 	}{
@@ -128,11 +141,87 @@ func TestSafeMysqldUpgrade(t *testing.T) {
 			desired:   "docker.io/vitess/mysql:8.4.12",
 			needsSafe: true,
 		},
+		{
+			name:    "minor downgrade",
+			current: "docker.io/vitess/mysql:8.4.0",
+			desired: "docker.io/vitess/mysql:8.0.36",
+			err:     "cannot downgrade minor version from 8.4.0 to 8.0.36",
+		},
+		{
+			name:      "patch upgrade within 8.4 LTS",
+			current:   "docker.io/library/mysql:8.4.0",
+			desired:   "docker.io/library/mysql:8.4.2",
+			needsSafe: false,
+		},
+		{
+			name:      "patch downgrade within 8.4 LTS",
+			current:   "docker.io/library/mysql:8.4.2",
+			desired:   "docker.io/library/mysql:8.4.0",
+			needsSafe: false,
+		},
+		{
+			name:      "series tag within 8.4 LTS",
+			current:   "docker.io/library/mysql:8.4.2",
+			desired:   "docker.io/library/mysql:8.4",
+			needsSafe: false,
+		},
+		{
+			name:      "series tag within 8.0",
+			current:   "docker.io/library/mysql:8.0.35",
+			desired:   "docker.io/library/mysql:8.0",
+			needsSafe: true,
+		},
+		{
+			name:      "percona patch upgrade after 8.0.34",
+			current:   "docker.io/percona/percona-server:8.0.34-26",
+			desired:   "docker.io/percona/percona-server:8.0.36-28",
+			needsSafe: false,
+		},
+		{
+			name:      "percona patch upgrade before 8.0.34",
+			current:   "docker.io/percona/percona-server:ps-8.0.32-24",
+			desired:   "docker.io/percona/percona-server:ps-8.0.33-25",
+			needsSafe: true,
+		},
+		{
+			name:    "percona patch downgrade before 8.0.34",
+			current: "docker.io/percona/percona-server:8.0.33-25",
+			desired: "docker.io/percona/percona-server:8.0.32-24",
+			err:     "cannot downgrade patch version from 8.0.33 to 8.0.32",
+		},
+		{
+			name:      "percona 8.0 to 8.4",
+			current:   "docker.io/percona/percona-server:8.0.36-28",
+			desired:   "docker.io/percona/percona-server:8.4.0-1",
+			needsSafe: true,
+		},
+		{
+			name:      "registry with port",
+			current:   "registry:5000/vitess/mysql:8.0.35",
+			desired:   "registry:5000/vitess/mysql:8.0.36",
+			needsSafe: false,
+		},
+		{
+			name:      "desired version from 8.4 flavor",
+			current:   "docker.io/library/mysql:8.0.36",
+			flavor:    &planetscalev2.MysqldImage{Mysql84Compatible: "docker.io/library/mysql:lts"},
+			needsSafe: true,
+		},
+		{
+			name:      "desired series from 8.4 flavor",
+			current:   "docker.io/library/mysql:8.4.1",
+			flavor:    &planetscalev2.MysqldImage{Mysql84Compatible: "docker.io/library/mysql:lts"},
+			needsSafe: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			needsSafe, err := safeMysqldUpgrade(tt.current, tt.desired)
+			desired := tt.flavor
+			if desired == nil {
+				desired = &planetscalev2.MysqldImage{Mysql80Compatible: tt.desired}
+			}
+			needsSafe, err := safeMysqldUpgrade(tt.current, desired)
 			if tt.err != "" {
 				assert.EqualError(t, err, tt.err)
 			} else {
@@ -142,3 +231,430 @@ func TestSafeMysqldUpgrade(t *testing.T) {
 		})
 	}
 }
+
+func TestPreferredCandidates(t *testing.T) {
+	tablet := func(cell string, uid uint32) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: cell, Uid: uid}}}
+	}
+	zone1a := tablet("zone1", 1)
+	zone1b := tablet("zone1", 2)
+	zone2 := tablet("zone2", 3)
+	zone3 := tablet("zone3", 4)
+	all := []*topo.TabletInfo{zone1a, zone2, zone1b, zone3}
+
+	tests := []struct {
+		name           string
+		candidates     []*topo.TabletInfo
+		preferredCells []string
+		want           []*topo.TabletInfo
+	}{
+		{
+			name:       "no preferred cells",
+			candidates: all,
+			want:       all,
+		},
+		{
+			name:           "one preferred cell",
+			candidates:     all,
+			preferredCells: []string{"zone1"},
+			want:           []*topo.TabletInfo{zone1a, zone1b},
+		},
+		{
+			name:           "multiple preferred cells",
+			candidates:     all,
+			preferredCells: []string{"zone3", "zone2"},
+			want:           []*topo.TabletInfo{zone2, zone3},
+		},
+		{
+			name:           "no candidates in preferred cells",
+			candidates:     []*topo.TabletInfo{zone2, zone3},
+			preferredCells: []string{"zone1"},
+			want:           []*topo.TabletInfo{zone2, zone3},
+		},
+		{
+			name:           "no candidates",
+			preferredCells: []string{"zone1"},
+			want:           nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, preferredCandidates(tt.candidates, tt.preferredCells))
+		})
+	}
+}
+
+func TestWeightedCandidates(t *testing.T) {
+	tablet := func(uid uint32) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: uid}}}
+	}
+	t1 := tablet(1)
+	t2 := tablet(2)
+	t3 := tablet(3)
+	all := []*topo.TabletInfo{t1, t2, t3}
+
+	tests := []struct {
+		name    string
+		weights map[string]int32
+		want    []*topo.TabletInfo
+	}{
+		{
+			name: "no weights",
+			want: all,
+		},
+		{
+			name:    "one highest weight",
+			weights: map[string]int32{"zone1-0000000002": 10, "zone1-0000000003": 5},
+			want:    []*topo.TabletInfo{t2},
+		},
+		{
+			name:    "tied highest weight",
+			weights: map[string]int32{"zone1-0000000001": 10, "zone1-0000000003": 10},
+			want:    []*topo.TabletInfo{t1, t3},
+		},
+		{
+			name:    "negative weights",
+			weights: map[string]int32{"zone1-0000000001": -1, "zone1-0000000003": -1},
+			want:    []*topo.TabletInfo{t2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, weightedCandidates(all, tt.weights))
+		})
+	}
+	assert.Empty(t, weightedCandidates(nil, nil))
+}
+
+func TestPrimaryEligibilityWeights(t *testing.T) {
+	pod := func(cell, poolType, name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			planetscalev2.CellLabel:           cell,
+			planetscalev2.TabletTypeLabel:     poolType,
+			planetscalev2.TabletPoolNameLabel: name,
+		}}}
+	}
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: "replica", PrimaryEligibilityWeight: 10},
+		{Cell: "zone1", Type: "replica", Name: "slow"},
+	}
+	planetscalev2.DefaultVitessShard(vts)
+
+	pods := map[string]*corev1.Pod{
+		"zone1-1": pod("zone1", "replica", ""),
+		"zone1-2": pod("zone1", "replica", "slow"),
+		"zone2-1": pod("zone2", "replica", ""),
+	}
+	assert.Equal(t, map[string]int32{"zone1-1": 10, "zone1-2": 0, "zone2-1": 0}, primaryEligibilityWeights(vts, pods))
+}
+
+func TestRecordDrainStateMetrics(t *testing.T) {
+	pod := func(annotations ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+		for _, key := range annotations {
+			p.Annotations[key] = "test"
+		}
+		return p
+	}
+	vts := &planetscalev2.VitessShard{}
+	vts.Labels = map[string]string{planetscalev2.ClusterLabel: "cluster", planetscalev2.KeyspaceLabel: "keyspace"}
+	vts.Spec.Name = "metrics-test"
+
+	pods := map[string]*corev1.Pod{
+		"zone1-1": pod(),
+		"zone1-2": pod(drain.StartedAnnotation),
+		"zone1-3": pod(drain.StartedAnnotation),
+		"zone1-4": pod(drain.StartedAnnotation, drain.AcknowledgedAnnotation),
+		"zone1-5": pod(drain.AcknowledgedAnnotation),
+	}
+
+	assert.Equal(t, 3, recordDrainStateMetrics(vts, pods))
+	assert.Equal(t, 2.0, testutil.ToFloat64(drainStateCount.WithLabelValues(shardLabels(vts, "draining")...)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(drainStateCount.WithLabelValues(shardLabels(vts, "acknowledged")...)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(drainStateCount.WithLabelValues(shardLabels(vts, "finished")...)))
+}
+
+func TestLostDrainingTablets(t *testing.T) {
+	now := time.Now()
+	gracePeriod := 5 * time.Minute
+
+	pod := func(draining bool, ready corev1.ConditionStatus, since time.Duration) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodReady,
+					Status:             ready,
+					LastTransitionTime: metav1.NewTime(now.Add(-since)),
+				}},
+			},
+		}
+		if draining {
+			drain.Start(p, "test")
+		}
+		return p
+	}
+
+	pods := map[string]*corev1.Pod{
+		"zone1-1": pod(true, corev1.ConditionFalse, 10*time.Minute),
+		"zone1-2": pod(true, corev1.ConditionFalse, time.Minute),
+		"zone1-3": pod(true, corev1.ConditionTrue, 10*time.Minute),
+		"zone1-4": pod(false, corev1.ConditionFalse, 10*time.Minute),
+	}
+
+	assert.Equal(t, sets.New[string]("zone1-1"), lostDrainingTablets(pods, gracePeriod, now))
+}
+
+func TestCandidateRejection(t *testing.T) {
+	readyPod := func(poolType string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{planetscalev2.TabletTypeLabel: poolType}},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+	drainingPod := readyPod("replica")
+	drain.Start(drainingPod, "test")
+	tablet := func(tabletType topodatapb.TabletType) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{Type: tabletType}}
+	}
+
+	tests := []struct {
+		name          string
+		tablet        *topo.TabletInfo
+		pod           *corev1.Pod
+		usingExternal bool
+		want          string
+	}{
+		{name: "eligible", tablet: tablet(topodatapb.TabletType_REPLICA), pod: readyPod("replica"), want: ""},
+		{name: "no pod", tablet: tablet(topodatapb.TabletType_REPLICA), want: "Pod not found"},
+		{name: "rdonly", tablet: tablet(topodatapb.TabletType_RDONLY), pod: readyPod("rdonly"), want: "tablet type is RDONLY"},
+		{name: "not ready", tablet: tablet(topodatapb.TabletType_REPLICA), pod: &corev1.Pod{}, want: "Pod not Ready"},
+		{name: "draining", tablet: tablet(topodatapb.TabletType_REPLICA), pod: drainingPod, want: "draining"},
+		{name: "external eligible", tablet: tablet(topodatapb.TabletType_SPARE), pod: readyPod(planetscalev2.ExternalMasterTabletPoolName), usingExternal: true, want: ""},
+		{name: "external wrong pool", tablet: tablet(topodatapb.TabletType_SPARE), pod: readyPod("replica"), usingExternal: true, want: "not in an externalmaster pool"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, candidateRejection(tt.tablet, tt.pod, tt.usingExternal))
+		})
+	}
+}
+
+func TestDrainAbortExpired(t *testing.T) {
+	now := time.Now()
+	gracePeriod := 10 * time.Minute
+
+	tests := []struct {
+		name   string
+		status corev1.ConditionStatus
+		since  time.Duration
+		want   bool
+	}{
+		{name: "no condition"},
+		{name: "not aborted", status: corev1.ConditionFalse, since: time.Hour, want: false},
+		{name: "aborted recently", status: corev1.ConditionTrue, since: time.Minute, want: false},
+		{name: "aborted long ago", status: corev1.ConditionTrue, since: time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vts := &planetscalev2.VitessShard{}
+			if tt.status != "" {
+				transitionTime := metav1.NewTime(now.Add(-tt.since))
+				vts.Status.Conditions = map[planetscalev2.VitessShardConditionType]planetscalev2.VitessShardCondition{
+					planetscalev2.VitessShardDrainAborted: {Status: tt.status, LastTransitionTime: &transitionTime},
+				}
+			}
+			assert.Equal(t, tt.want, drainAbortExpired(vts, gracePeriod, now))
+		})
+	}
+}
+
+func TestCheckPoolDisruption(t *testing.T) {
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}}
+	}
+	deletingPod := readyPod()
+	deletingPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	finishedPod := readyPod()
+	finishedPod.Annotations = map[string]string{drain.FinishedAnnotation: "true"}
+	otherPoolPod := &corev1.Pod{}
+	otherPoolPod.Labels = map[string]string{planetscalev2.TabletPoolNameLabel: "other"}
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {PoolType: "replica"},
+		"zone1-2": {PoolType: "replica"},
+		"zone1-3": {PoolType: "rdonly"},
+		"zone2-1": {PoolType: "replica"},
+	}
+
+	tests := []struct {
+		name    string
+		pods    map[string]*corev1.Pod
+		budget  int
+		wantErr bool
+	}{
+		{
+			name:    "all ready",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": readyPod(), "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: false,
+		},
+		{
+			name:    "other pools down",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": readyPod(), "zone1-3": &corev1.Pod{}},
+			wantErr: false,
+		},
+		{
+			name:    "same pool missing",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "same pool not ready",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": &corev1.Pod{}, "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "same pool deleting",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": deletingPod, "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "same pool finished draining",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": finishedPod, "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: true,
+		},
+		{
+			name:    "same pool finished draining within budget",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": finishedPod, "zone1-3": readyPod(), "zone2-1": readyPod()},
+			budget:  2,
+			wantErr: false,
+		},
+		{
+			name:    "separately named pool of the same type down",
+			pods:    map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": otherPoolPod, "zone1-3": readyPod(), "zone2-1": readyPod()},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := tt.budget
+			if budget == 0 {
+				budget = 1
+			}
+			err := checkPoolDisruption(vts, tt.pods, "zone1-1", nil, budget)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPoolDisruptionSamePass(t *testing.T) {
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}}
+	}
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {PoolType: "replica"},
+		"zone1-2": {PoolType: "replica"},
+	}
+	pods := map[string]*corev1.Pod{"zone1-1": readyPod(), "zone1-2": readyPod()}
+
+	// With a limit of 2, both Ready pool-mates may be picked in the same pass,
+	// but only as many as the pool's budget can be marked as finished.
+	drains := map[string]drain.State{"zone1-1": drain.AcknowledgedState, "zone1-2": drain.AcknowledgedState}
+	transitions := drain.StateTransitionsWithLimit(drains, 2)
+	assert.Len(t, transitions, 2)
+
+	for _, budget := range []int{1, 2} {
+		finishing := sets.New[string]()
+		for tabletAliasStr, state := range transitions {
+			assert.Equal(t, drain.FinishedState, state)
+			if checkPoolDisruption(vts, pods, tabletAliasStr, finishing, budget) == nil {
+				finishing.Insert(tabletAliasStr)
+			}
+		}
+		assert.Equal(t, budget, finishing.Len(), "budget %v", budget)
+	}
+}
+
+func TestDrainMaxFinished(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 3},
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, Replicas: 1},
+	}
+	// Two extra surge tablets are observed, but they don't count toward the base.
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-1": {}, "zone1-2": {}, "zone1-3": {}, "zone1-4": {}, "zone1-5": {}, "zone1-6": {},
+	}
+
+	assert.Equal(t, 1, r.drainMaxFinished(vts), "default")
+
+	maxUnavailable := intstr.FromString("50%")
+	vts.Spec.Replication.DrainMaxUnavailable = &maxUnavailable
+	assert.Equal(t, 2, r.drainMaxFinished(vts), "50% of 4 desired tablets")
+
+	maxUnavailable = intstr.FromString("10%")
+	assert.Equal(t, 1, r.drainMaxFinished(vts), "rounds down to at least 1")
+}
+
+func TestPoolDisruptionBudget(t *testing.T) {
+	maxUnavailable := intstr.FromInt(2)
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 4, UpdateStrategy: &planetscalev2.VitessTabletPoolUpdateStrategy{MaxUnavailable: &maxUnavailable}},
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, Replicas: 4},
+	}
+	planetscalev2.DefaultVitessShard(vts)
+
+	podInPool := func(poolType planetscalev2.VitessTabletPoolType) *corev1.Pod {
+		pod := &corev1.Pod{}
+		pod.Labels = map[string]string{
+			planetscalev2.CellLabel:       "zone1",
+			planetscalev2.TabletTypeLabel: string(poolType),
+		}
+		return pod
+	}
+
+	assert.Equal(t, 2, poolDisruptionBudget(vts, podInPool(planetscalev2.ReplicaPoolType), 1), "pool maxUnavailable")
+	assert.Equal(t, 3, poolDisruptionBudget(vts, podInPool(planetscalev2.RdonlyPoolType), 3), "shard drainMaxUnavailable")
+}
+
+func TestFinishCandidatesPrimaryLast(t *testing.T) {
+	// The primary comes first in drain order, and the limit would allow it
+	// alone to finish.
+	order := map[string]int{"zone1-1": 0, "zone1-2": 10, "zone1-3": 10}
+
+	drains := map[string]drain.State{
+		"zone1-1": drain.AcknowledgedState,
+		"zone1-2": drain.AcknowledgedState,
+		"zone1-3": drain.AcknowledgedState,
+	}
+	transitions := drain.StateTransitionsWithOrder(finishCandidates(drains, "zone1-1"), order, 1)
+	assert.Equal(t, map[string]drain.State{"zone1-2": drain.FinishedState}, transitions, "primary waits for others")
+
+	drains = map[string]drain.State{
+		"zone1-1": drain.AcknowledgedState,
+		"zone1-2": drain.FinishedState,
+	}
+	transitions = drain.StateTransitionsWithOrder(finishCandidates(drains, "zone1-1"), order, 2)
+	assert.Equal(t, map[string]drain.State{"zone1-1": drain.FinishedState}, transitions, "primary is last")
+
+	drains = map[string]drain.State{
+		"zone1-1": drain.AcknowledgedState,
+		"zone1-2": drain.DrainingState,
+	}
+	transitions = drain.StateTransitionsWithOrder(finishCandidates(drains, "zone1-1"), order, 2)
+	assert.Equal(t, map[string]drain.State{"zone1-2": drain.AcknowledgedState}, transitions, "nothing finishes while acknowledging")
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/mysql/replication"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileErrantGTIDs compares the GTID set of each Ready replica-type tablet
+// against the primary's, records any errant GTIDs in annotations on the tablet
+// Pods, and reseeds affected replicas from backup if the policy says to.
+//
+// The main VitessShard controller copies the annotations into the tablet
+// status, since it owns the rest of the status.
+func (r *ReconcileVitessShard) reconcileErrantGTIDs(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// We don't manage replication for external datastores.
+	policy := vts.Spec.Replication.ErrantGTIDPolicy
+	if policy == "" || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, vts.Spec.ReparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+	primary := tablets[primaryAliasStr]
+	if primary == nil {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// The primary's GTID set is the reference, so it has none by definition.
+	if pod := pods[primaryAliasStr]; pod != nil {
+		if err := r.setErrantGTIDsAnnotation(ctx, pod, ""); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	replicas := make(map[string]*topo.TabletInfo, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		if tabletAliasStr == primaryAliasStr {
+			continue
+		}
+		if tablet.Type != topodatapb.TabletType_REPLICA && tablet.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		pod := pods[tabletAliasStr]
+		if pod == nil || pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		replicas[tabletAliasStr] = tablet
+	}
+
+	// Read the replica positions before the primary's, so any transaction a
+	// replica has legitimately received is already in the primary's GTID set.
+	statuses := replicationStatuses(ctx, wr, replicas)
+	rpcCtx, rpcCancel := context.WithTimeout(ctx, candidatePrimaryTimeout)
+	defer rpcCancel()
+	primaryPosition, err := wr.TabletManagerClient().PrimaryPosition(rpcCtx, primary.Tablet)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ErrantGTIDCheckFailed", "failed to get primary position from %v: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].aliasStr < statuses[j].aliasStr
+	})
+
+	affected := 0
+	reseeded := false
+	for _, result := range statuses {
+		pod := pods[result.aliasStr]
+		if result.err != nil {
+			// Leave the last known result in place until we can check again.
+			continue
+		}
+		errant, err := errantGTIDs(result.status.GetPosition(), primaryPosition)
+		if err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ErrantGTIDCheckFailed", "failed to compare GTID set with primary %v: %v", primaryAliasStr, err)
+			continue
+		}
+		if err := r.setErrantGTIDsAnnotation(ctx, pod, errant); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update errant GTIDs annotation on Pod %v: %v", pod.Name, err)
+			resultBuilder.Error(err)
+		}
+		if errant == "" {
+			continue
+		}
+		affected++
+		r.recorder.Eventf(pod, corev1.EventTypeWarning, "ErrantGTIDs", "tablet has transactions that primary %v doesn't have: %v", primaryAliasStr, errant)
+
+		if policy != planetscalev2.ReseedErrantGTIDPolicy {
+			continue
+		}
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+		if reseeded || !hasCompleteBackup(vts) {
+			continue
+		}
+		if err := checkPoolDisruption(vts, pods, result.aliasStr, nil, 1); err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeNormal, "ErrantGTIDReseedDeferred", "not reseeding tablet with errant GTIDs: %v", err)
+			continue
+		}
+		reseedErr := r.reseedFromBackup(ctx, pod)
+		reseeded = true
+		replicationRepairCount.WithLabelValues(shardLabels(vts, string(planetscalev2.ReseedFromBackupRepairAction), metrics.Result(reseedErr))...).Inc()
+		if reseedErr != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ErrantGTIDReseedFailed", "failed to reseed tablet with errant GTIDs from backup: %v", reseedErr)
+			continue
+		}
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "ErrantGTIDReseed", "reseeding tablet with errant GTIDs from backup")
+	}
+	errantGTIDTablets.WithLabelValues(shardLabels(vts)...).Set(float64(affected))
+
+	return resultBuilder.Result()
+}
+
+// errantGTIDs returns the GTIDs in a replica's position that are not in the
+// primary's position, or an empty string if there are none.
+func errantGTIDs(replicaPosition, primaryPosition string) (string, error) {
+	replicaPos, err := replication.DecodePosition(replicaPosition)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode replica position: %v", err)
+	}
+	primaryPos, err := replication.DecodePosition(primaryPosition)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode primary position: %v", err)
+	}
+	replicaSet, ok := replicaPos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return "", fmt.Errorf("unsupported replica position %q", replicaPosition)
+	}
+	primarySet, ok := primaryPos.GTIDSet.(replication.Mysql56GTIDSet)
+	if !ok {
+		return "", fmt.Errorf("unsupported primary position %q", primaryPosition)
+	}
+	errant := replicaSet.Difference(primarySet)
+	if len(errant) == 0 {
+		return "", nil
+	}
+	return errant.String(), nil
+}
+
+// setErrantGTIDsAnnotation records the errant GTIDs found on a tablet Pod, if
+// they changed. An empty value clears the annotation.
+func (r *ReconcileVitessShard) setErrantGTIDsAnnotation(ctx context.Context, pod *corev1.Pod, errant string) error {
+	if pod.Annotations[vttablet.ErrantGTIDsAnnotation] == errant {
+		return nil
+	}
+
+	if errant == "" {
+		delete(pod.Annotations, vttablet.ErrantGTIDsAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vttablet.ErrantGTIDsAnnotation] = errant
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
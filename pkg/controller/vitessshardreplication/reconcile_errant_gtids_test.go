@@ -0,0 +1,74 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrantGTIDs(t *testing.T) {
+	const (
+		primaryUUID = "8bc65c84-3fe4-11ed-a912-257f0fcdd6c9"
+		replicaUUID = "8bc65cca-3fe4-11ed-bbfb-091034d48b3e"
+	)
+
+	tests := []struct {
+		name    string
+		replica string
+		primary string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "replica behind",
+			replica: "MySQL56/" + primaryUUID + ":1-5",
+			primary: "MySQL56/" + primaryUUID + ":1-10",
+			want:    "",
+		},
+		{
+			name:    "replica caught up",
+			replica: "MySQL56/" + primaryUUID + ":1-10",
+			primary: "MySQL56/" + primaryUUID + ":1-10",
+			want:    "",
+		},
+		{
+			name:    "errant transactions",
+			replica: "MySQL56/" + primaryUUID + ":1-10," + replicaUUID + ":1-2",
+			primary: "MySQL56/" + primaryUUID + ":1-10",
+			want:    replicaUUID + ":1-2",
+		},
+		{
+			name:    "bad position",
+			replica: "garbage",
+			primary: "MySQL56/" + primaryUUID + ":1-10",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := errantGTIDs(test.replica, test.primary)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
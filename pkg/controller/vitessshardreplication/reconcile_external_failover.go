@@ -0,0 +1,289 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// lookupHost resolves a host name. It's a variable so tests can replace it.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// reconcileExternalFailover follows failovers of an externally managed MySQL
+// that are signaled outside of Vitess. When a signal changes, it restarts the
+// tablets in "externalmaster" pools so they connect to the new primary, and
+// then reports one of them to Vitess with TabletExternallyReparented.
+func (r *ReconcileVitessShard) reconcileExternalFailover(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	datastore := vts.Spec.ExternalFailover()
+	if datastore == nil {
+		return resultBuilder.Result()
+	}
+	failover := datastore.Failover
+
+	// Check the signals again soon, even if nothing else happens.
+	resultBuilder.RequeueAfter(failover.CheckInterval.Duration)
+
+	status := vts.Status.ExternalFailover.DeepCopy()
+	if status == nil {
+		status = &planetscalev2.VitessShardExternalFailoverStatus{}
+	}
+	host, addresses, checkErr := r.externalFailoverSignals(ctx, vts.Namespace, datastore)
+	changed, failedOver := observeExternalFailover(status, host, addresses, checkErr, time.Now())
+	if failedOver {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "ExternalFailoverDetected", "externally managed MySQL failed over to %v %v", status.PrimaryHost, status.Addresses)
+	}
+	if changed {
+		vts.Status.ExternalFailover = status
+		if err := r.client.Status().Update(ctx, vts); err != nil {
+			if !apierrors.IsConflict(err) {
+				r.recorder.Eventf(vts, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to record external failover: %v", err)
+			}
+			// We'll see the same signals again on the next pass.
+			return resultBuilder.Error(err)
+		}
+	}
+
+	if !externalFailoverPending(status) {
+		return resultBuilder.Result()
+	}
+
+	// Keep checking on the shard until it has caught up with the failover.
+	resultBuilder.RequeueAfter(replicationRequeueDelay)
+
+	pods, err := r.tabletPods(ctx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	// Restart any externalmaster tablets that started before the failover,
+	// so they drop their connections to the old primary.
+	stale, waiting := staleExternalMasterTablets(vts, pods, status.LastFailoverTime.Time)
+	for _, tabletAliasStr := range stale {
+		pod := pods[tabletAliasStr]
+		if err := r.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ExternalFailoverRewireFailed", "failed to restart tablet to connect to the new primary: %v", err)
+			return resultBuilder.Error(err)
+		}
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "ExternalFailoverRewire", "restarting tablet to connect to the new primary %v", status.PrimaryHost)
+	}
+	if len(stale) > 0 || waiting {
+		return resultBuilder.Result()
+	}
+
+	tabletAliasStr := runningExternalMasterTablet(vts)
+	if tabletAliasStr == "" {
+		// Wait for a rewired tablet to come up.
+		return resultBuilder.Result()
+	}
+	tabletAlias, err := topoproto.ParseTabletAlias(tabletAliasStr)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InternalError", "can't parse tablet alias %q: %v", tabletAliasStr, err)
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	terCtx, cancel := context.WithTimeout(ctx, externallyReparentTimeout)
+	defer cancel()
+	if err := wr.TabletExternallyReparented(terCtx, tabletAlias); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TabletExternallyReparentedFailed", "failed to externally reparent shard after failover: %v", err)
+		return resultBuilder.Result()
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "TabletExternallyReparented", "Externally reparented tablet %v after failover to %v", tabletAliasStr, status.PrimaryHost)
+
+	now := metav1.Now()
+	status.RewiredTime = &now
+	vts.Status.ExternalFailover = status
+	r.recordReparent(ctx, vts, planetscalev2.ExternalFailoverReparentReason, vts.Status.MasterAlias, tabletAliasStr, nil)
+
+	return resultBuilder.Result()
+}
+
+// externalFailoverSignals returns the host of the current primary of an
+// externally managed MySQL, and the addresses it resolves to if DNS is
+// watched. Addresses are sorted so they can be compared.
+func (r *ReconcileVitessShard) externalFailoverSignals(ctx context.Context, namespace string, datastore *planetscalev2.ExternalDatastore) (string, []string, error) {
+	host, err := r.externalPrimaryHost(ctx, namespace, datastore)
+	if err != nil {
+		return "", nil, err
+	}
+	if !datastore.Failover.WatchDNS {
+		return host, nil, nil
+	}
+	addresses, err := lookupHost(ctx, host)
+	if err != nil {
+		return host, nil, fmt.Errorf("failed to resolve %v: %v", host, err)
+	}
+	sort.Strings(addresses)
+	return host, addresses, nil
+}
+
+// externalPrimaryHost reads the host of the current primary from where the
+// failover tooling publishes it, or else returns the datastore's host.
+func (r *ReconcileVitessShard) externalPrimaryHost(ctx context.Context, namespace string, datastore *planetscalev2.ExternalDatastore) (string, error) {
+	source := datastore.Failover.PrimaryHostFrom
+	var value string
+	switch {
+	case source == nil:
+		return datastore.Host, nil
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		configMap := &corev1.ConfigMap{}
+		if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			return "", fmt.Errorf("failed to get ConfigMap %v: %v", ref.Name, err)
+		}
+		var ok bool
+		if value, ok = configMap.Data[ref.Key]; !ok {
+			return "", fmt.Errorf("ConfigMap %v has no key %q", ref.Name, ref.Key)
+		}
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		secret := &corev1.Secret{}
+		if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			return "", fmt.Errorf("failed to get Secret %v: %v", ref.Name, err)
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("Secret %v has no key %q", ref.Name, ref.Key)
+		}
+		value = string(data)
+	default:
+		return "", fmt.Errorf("primaryHostFrom must set either configMapKeyRef or secretKeyRef")
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("the published primary host is empty")
+	}
+	return value, nil
+}
+
+// observeExternalFailover records the failover signals that were just read
+// in the status. It returns whether the status changed, and whether the
+// signals show a failover since they were last recorded.
+//
+// The first signals seen are only recorded, since there's nothing to compare
+// them to. Addresses are only compared when both lookups succeeded.
+func observeExternalFailover(status *planetscalev2.VitessShardExternalFailoverStatus, host string, addresses []string, checkErr error, now time.Time) (changed, failedOver bool) {
+	message := ""
+	if checkErr != nil {
+		message = checkErr.Error()
+	}
+	if status.Message != message {
+		status.Message = message
+		changed = true
+	}
+	if host == "" {
+		// We couldn't read the signals at all.
+		return changed, false
+	}
+
+	switch {
+	case status.PrimaryHost == "":
+		// This is the first time we've seen the signals.
+	case host != status.PrimaryHost:
+		failedOver = true
+	case len(addresses) > 0 && len(status.Addresses) > 0 && !slices.Equal(addresses, status.Addresses):
+		failedOver = true
+	}
+
+	if host != status.PrimaryHost {
+		status.PrimaryHost = host
+		changed = true
+	}
+	if len(addresses) > 0 && !slices.Equal(addresses, status.Addresses) {
+		status.Addresses = addresses
+		changed = true
+	}
+	if failedOver {
+		failoverTime := metav1.NewTime(now)
+		status.LastFailoverTime = &failoverTime
+		changed = true
+	}
+	return changed, failedOver
+}
+
+// externalFailoverPending returns whether a failover has been seen that the
+// shard hasn't caught up with yet.
+func externalFailoverPending(status *planetscalev2.VitessShardExternalFailoverStatus) bool {
+	if status == nil || status.LastFailoverTime == nil {
+		return false
+	}
+	return status.RewiredTime == nil || status.RewiredTime.Before(status.LastFailoverTime)
+}
+
+// staleExternalMasterTablets returns the externalmaster tablets whose Pods
+// were created before the given failover time, sorted by alias. It also
+// returns whether any other externalmaster tablet is still waiting for its
+// Pod to be deleted or recreated.
+func staleExternalMasterTablets(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod, failoverTime time.Time) ([]string, bool) {
+	var stale []string
+	waiting := false
+	for _, tabletAliasStr := range sortedTabletAliases(vts) {
+		tablet := vts.Status.Tablets[tabletAliasStr]
+		if !tablet.IsExternalMaster() {
+			continue
+		}
+		pod := pods[tabletAliasStr]
+		switch {
+		case pod == nil || pod.DeletionTimestamp != nil:
+			waiting = true
+		case pod.CreationTimestamp.Time.Before(failoverTime):
+			stale = append(stale, tabletAliasStr)
+		}
+	}
+	return stale, waiting
+}
+
+// runningExternalMasterTablet returns the first externalmaster tablet by
+// alias that's running, or "" if there are none.
+func runningExternalMasterTablet(vts *planetscalev2.VitessShard) string {
+	for _, tabletAliasStr := range sortedTabletAliases(vts) {
+		tablet := vts.Status.Tablets[tabletAliasStr]
+		if tablet.IsExternalMaster() && tablet.IsRunning() {
+			return tabletAliasStr
+		}
+	}
+	return ""
+}
+
+func sortedTabletAliases(vts *planetscalev2.VitessShard) []string {
+	tabletAliases := make([]string, 0, len(vts.Status.Tablets))
+	for tabletAliasStr := range vts.Status.Tablets {
+		tabletAliases = append(tabletAliases, tabletAliasStr)
+	}
+	sort.Strings(tabletAliases)
+	return tabletAliases
+}
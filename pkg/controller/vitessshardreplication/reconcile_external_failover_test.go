@@ -0,0 +1,102 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestObserveExternalFailover(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	status := &planetscalev2.VitessShardExternalFailoverStatus{}
+
+	// The first signals are only recorded.
+	changed, failedOver := observeExternalFailover(status, "db-a", []string{"10.0.0.1"}, nil, now)
+	assert.True(t, changed)
+	assert.False(t, failedOver)
+	assert.Equal(t, "db-a", status.PrimaryHost)
+	assert.False(t, externalFailoverPending(status))
+
+	// Nothing changes if the signals are the same.
+	changed, failedOver = observeExternalFailover(status, "db-a", []string{"10.0.0.1"}, nil, now)
+	assert.False(t, changed)
+	assert.False(t, failedOver)
+
+	// A failed lookup is reported, but isn't a failover.
+	changed, failedOver = observeExternalFailover(status, "db-a", nil, errors.New("no such host"), now)
+	assert.True(t, changed)
+	assert.False(t, failedOver)
+	assert.Equal(t, "no such host", status.Message)
+	assert.Equal(t, []string{"10.0.0.1"}, status.Addresses)
+
+	// The host resolving somewhere else is a failover.
+	changed, failedOver = observeExternalFailover(status, "db-a", []string{"10.0.0.2"}, nil, now)
+	assert.True(t, changed)
+	assert.True(t, failedOver)
+	assert.Empty(t, status.Message)
+	assert.True(t, externalFailoverPending(status))
+
+	// Once the shard is rewired, a new host is another failover.
+	rewired := metav1.NewTime(now.Add(time.Minute))
+	status.RewiredTime = &rewired
+	assert.False(t, externalFailoverPending(status))
+	_, failedOver = observeExternalFailover(status, "db-b", nil, nil, now.Add(time.Hour))
+	assert.True(t, failedOver)
+	assert.Equal(t, "db-b", status.PrimaryHost)
+	assert.True(t, externalFailoverPending(status))
+}
+
+func TestStaleExternalMasterTablets(t *testing.T) {
+	failoverTime := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-0000000101": {PoolType: planetscalev2.ExternalMasterTabletPoolName, Running: corev1.ConditionTrue},
+		"zone2-0000000201": {PoolType: planetscalev2.ExternalMasterTabletPoolName, Running: corev1.ConditionTrue},
+		"zone1-0000000301": {PoolType: "externalreplica", Running: corev1.ConditionTrue},
+	}
+	pod := func(created time.Time) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	}
+	pods := map[string]*corev1.Pod{
+		"zone1-0000000101": pod(failoverTime.Add(-time.Hour)),
+		"zone2-0000000201": pod(failoverTime.Add(-time.Hour)),
+		"zone1-0000000301": pod(failoverTime.Add(-time.Hour)),
+	}
+
+	// Only externalmaster tablets get restarted.
+	stale, waiting := staleExternalMasterTablets(vts, pods, failoverTime)
+	assert.Equal(t, []string{"zone1-0000000101", "zone2-0000000201"}, stale)
+	assert.False(t, waiting)
+
+	// Wait for deleted Pods to come back.
+	delete(pods, "zone1-0000000101")
+	pods["zone2-0000000201"] = pod(failoverTime.Add(time.Minute))
+	stale, waiting = staleExternalMasterTablets(vts, pods, failoverTime)
+	assert.Empty(t, stale)
+	assert.True(t, waiting)
+
+	assert.Equal(t, "zone1-0000000101", runningExternalMasterTablet(vts))
+}
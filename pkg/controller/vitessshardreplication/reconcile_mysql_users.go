@@ -0,0 +1,236 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/contenthash"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// mysqlUsersTimeout is how long to wait for the primary to update the MySQL
+// users.
+const mysqlUsersTimeout = 30 * time.Second
+
+// reconcileMysqlUsers creates and updates the MySQL users listed in the
+// tablet pools, and drops the ones that were removed. Users are changed on
+// the primary, and replicate from there to the other tablets.
+//
+// A hash of the users and the accounts they have are recorded in annotations
+// on the primary tablet Pod, so the users are only changed again if the spec
+// or a password Secret changes, or a different tablet becomes primary.
+func (r *ReconcileVitessShard) reconcileMysqlUsers(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// We don't manage the users of external datastores.
+	if vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	users, problems := vts.Spec.MysqlUsers()
+	if len(problems) > 0 {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqlUsersInvalid", "Some MySQL users can't be managed: %v", strings.Join(problems, "; "))
+	}
+
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	// Accounts are recorded on the primary, but a tablet that used to be
+	// primary can still have some that the current primary doesn't.
+	managed := map[vttablet.MysqlAccount]bool{}
+	for _, pod := range pods {
+		for _, account := range vttablet.ParseMysqlAccounts(pod.Annotations[vttablet.MysqlUserAccountsAnnotation]) {
+			managed[account] = true
+		}
+	}
+	if len(users) == 0 && len(managed) == 0 {
+		return resultBuilder.Result()
+	}
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primaryPod := pods[topoproto.TabletAliasString(shard.PrimaryAlias)]
+	if primaryPod == nil || primaryPod.DeletionTimestamp != nil || !podutils.IsPodReady(primaryPod) {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	var queries, hashed, passwords []string
+	for i := range users {
+		user := &users[i]
+		password, version, err := r.mysqlUserPassword(ctx, vts.Namespace, user)
+		if err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqlUsersFailed", "failed to get the password of MySQL user %q: %v", user.Username, err)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+		queries = append(queries, vttablet.MysqlUserQueries(user, password)...)
+		passwords = append(passwords, password)
+		// The hash follows password changes through the Secret's version,
+		// so it doesn't reveal anything about the password.
+		hashed = append(hashed, vttablet.MysqlUserQueries(user, version)...)
+	}
+	hash := contenthash.StringList(hashed)
+
+	accounts := vttablet.MysqlUserAccounts(users)
+	wanted := make(map[vttablet.MysqlAccount]bool, len(accounts))
+	for _, account := range accounts {
+		wanted[account] = true
+	}
+	var stale []vttablet.MysqlAccount
+	for account := range managed {
+		if !wanted[account] {
+			stale = append(stale, account)
+		}
+	}
+	if len(problems) > 0 {
+		// Users that can't be managed right now are left alone, rather than
+		// dropped, until they're fixed or removed from the spec.
+		accounts = append(accounts, stale...)
+		vttablet.SortMysqlAccounts(accounts)
+		stale = nil
+	}
+	accountsValue := vttablet.FormatMysqlAccounts(accounts)
+
+	recorded := primaryPod.Annotations[vttablet.MysqlUsersAnnotation] == hash && primaryPod.Annotations[vttablet.MysqlUserAccountsAnnotation] == accountsValue
+	if recorded && len(stale) == 0 {
+		return resultBuilder.Result()
+	}
+	if recorded {
+		// Only drop accounts that other tablets still list.
+		queries = nil
+	}
+	for _, account := range stale {
+		queries = append(queries, vttablet.MysqlUserDropQuery(account))
+	}
+
+	primary, err := wr.TopoServer().GetTablet(readCtx, shard.PrimaryAlias)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get primary tablet record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	fetchCtx, fetchCancel := context.WithTimeout(ctx, mysqlUsersTimeout)
+	defer fetchCancel()
+	for _, query := range queries {
+		_, err := wr.TabletManagerClient().ExecuteFetchAsDba(fetchCtx, primary.Tablet, false /*usePool*/, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query: []byte(query),
+		})
+		if err != nil {
+			// MySQL errors can quote the query, which can have a password in it.
+			message := err.Error()
+			for _, password := range passwords {
+				if password != "" {
+					message = strings.ReplaceAll(message, password, "<redacted>")
+				}
+			}
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqlUsersFailed", "failed to update MySQL users on the primary: %v", message)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+	}
+	if len(accounts) == 0 {
+		hash = ""
+	}
+	if err := r.setMysqlUsersAnnotations(ctx, primaryPod, hash, accountsValue); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record MySQL users on Pod %v: %v", primaryPod.Name, err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqlUsersUpdated", "Updated MySQL users on the primary, and dropped %d accounts that were removed", len(stale))
+
+	// The primary's record now covers everything other tablets listed.
+	for _, pod := range pods {
+		if pod == primaryPod {
+			continue
+		}
+		if err := r.setMysqlUsersAnnotations(ctx, pod, "", ""); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// mysqlUserPassword reads the password of a MySQL user from its Secret, and
+// returns it along with the version of the Secret.
+func (r *ReconcileVitessShard) mysqlUserPassword(ctx context.Context, namespace string, user *planetscalev2.MysqlUser) (string, string, error) {
+	ref := user.SecretRef
+	secret := &corev1.Secret{}
+	if err := r.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get Secret %v: %v", ref.Name, err)
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", "", fmt.Errorf("Secret %v has no key %q", ref.Name, ref.Key)
+	}
+	return string(password), secret.ResourceVersion, nil
+}
+
+// setMysqlUsersAnnotations records the MySQL users that were last updated on
+// a tablet Pod, if they changed. Empty values clear the annotations.
+func (r *ReconcileVitessShard) setMysqlUsersAnnotations(ctx context.Context, pod *corev1.Pod, hash, accounts string) error {
+	values := map[string]string{
+		vttablet.MysqlUsersAnnotation:        hash,
+		vttablet.MysqlUserAccountsAnnotation: accounts,
+	}
+	changed := false
+	for key, value := range values {
+		if pod.Annotations[key] == value {
+			continue
+		}
+		changed = true
+		if value == "" {
+			delete(pod.Annotations, key)
+			continue
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[key] = value
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// mysqldConfigDriftTimeout is how long to wait for a tablet to read or
+	// set its global variables.
+	mysqldConfigDriftTimeout = 5 * time.Second
+
+	// The buffer pool size that MySQL reports is rounded up to a multiple of
+	// these, so they're always read along with the configured variables.
+	bufferPoolSizeVariable      = "innodb_buffer_pool_size"
+	bufferPoolChunkSizeVariable = "innodb_buffer_pool_chunk_size"
+	bufferPoolInstancesVariable = "innodb_buffer_pool_instances"
+)
+
+// mysqldVariableDrift is a global variable whose value doesn't match the
+// tablet's mysqld config.
+type mysqldVariableDrift struct {
+	name, want, have string
+}
+
+// reconcileMysqldConfigDrift compares the global variables of each running
+// MySQL against the mysqld config its tablet was started with, records any
+// drift in annotations on the tablet Pods, and sets drifted variables back if
+// the policy says to.
+//
+// The main VitessShard controller copies the annotations into the tablet
+// status, since it owns the rest of the status.
+func (r *ReconcileVitessShard) reconcileMysqldConfigDrift(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// We don't manage the config of external datastores.
+	policy := vts.Spec.MysqldConfigDriftPolicy
+	if policy == "" || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	tabletAliases := make([]string, 0, len(pods))
+	for tabletAliasStr := range pods {
+		tabletAliases = append(tabletAliases, tabletAliasStr)
+	}
+	sort.Strings(tabletAliases)
+
+	affected := 0
+	for _, tabletAliasStr := range tabletAliases {
+		pod := pods[tabletAliasStr]
+		tablet := tablets[tabletAliasStr]
+		if tablet == nil || pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		config := vttablet.PodMysqldConfig(pod)
+		if len(config) == 0 {
+			if err := r.setMysqldConfigDriftAnnotation(ctx, pod, ""); err != nil {
+				resultBuilder.Error(err)
+			}
+			continue
+		}
+
+		variables, err := readMysqldVariables(ctx, wr, tablet, config)
+		if err != nil {
+			// Leave the last known result in place until we can check again.
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "MysqldConfigDriftCheckFailed", "failed to read MySQL global variables: %v", err)
+			continue
+		}
+		drift := mysqldConfigDrift(config, variables)
+
+		var descriptions []string
+		for _, d := range drift {
+			description := fmt.Sprintf("%v is %v, want %v", d.name, d.have, d.want)
+			if policy == planetscalev2.ReapplyMysqldConfigDriftPolicy {
+				if err := setMysqldVariable(ctx, wr, tablet, d.name, d.want); err != nil {
+					r.recorder.Eventf(pod, corev1.EventTypeWarning, "MysqldConfigReapplyFailed", "failed to set %v back to %v: %v", d.name, d.want, err)
+					descriptions = append(descriptions, description+" (can't be set back without a restart)")
+					continue
+				}
+				r.recorder.Eventf(pod, corev1.EventTypeNormal, "MysqldConfigReapplied", "set %v back from %v to %v", d.name, d.have, d.want)
+				continue
+			}
+			descriptions = append(descriptions, description)
+		}
+		if err := r.setMysqldConfigDriftAnnotation(ctx, pod, strings.Join(descriptions, "; ")); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update mysqld config drift annotation on Pod %v: %v", pod.Name, err)
+			resultBuilder.Error(err)
+		}
+		if len(descriptions) > 0 {
+			affected++
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "MysqldConfigDrift", "MySQL global variables don't match the tablet's mysqld config: %v", strings.Join(descriptions, "; "))
+		}
+	}
+	mysqldConfigDriftTablets.WithLabelValues(shardLabels(vts)...).Set(float64(affected))
+
+	return resultBuilder.Result()
+}
+
+// readMysqldVariables returns the current values of the global variables that
+// the given mysqld config sets, by lowercase name. Options that aren't global
+// variables are left out.
+func readMysqldVariables(ctx context.Context, wr *wrangler.Wrangler, tablet *topo.TabletInfo, config map[string]string) (map[string]string, error) {
+	names := []string{
+		sqltypes.EncodeStringSQL(bufferPoolChunkSizeVariable),
+		sqltypes.EncodeStringSQL(bufferPoolInstancesVariable),
+	}
+	for option := range config {
+		names = append(names, sqltypes.EncodeStringSQL(vttablet.MysqldVariableName(option)))
+	}
+	sort.Strings(names)
+	query := fmt.Sprintf("SHOW GLOBAL VARIABLES WHERE Variable_name IN (%v)", strings.Join(names, ", "))
+
+	ctx, cancel := context.WithTimeout(ctx, mysqldConfigDriftTimeout)
+	defer cancel()
+	qrproto, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, tablet.Tablet, true /*usePool*/, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:   []byte(query),
+		MaxRows: uint64(len(names)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	qr := sqltypes.Proto3ToResult(qrproto)
+	variables := make(map[string]string, len(qr.Rows))
+	for _, row := range qr.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		variables[strings.ToLower(row[0].ToString())] = row[1].ToString()
+	}
+	return variables, nil
+}
+
+// setMysqldVariable sets a global variable back to its configured value.
+// SET GLOBAL doesn't accept size suffixes, so sizes are sent in bytes.
+func setMysqldVariable(ctx context.Context, wr *wrangler.Wrangler, tablet *topo.TabletInfo, name, value string) error {
+	if n, ok := parseMysqldInt(value); ok {
+		value = strconv.FormatInt(n, 10)
+	} else {
+		value = sqltypes.EncodeStringSQL(value)
+	}
+	ctx, cancel := context.WithTimeout(ctx, mysqldConfigDriftTimeout)
+	defer cancel()
+	_, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, tablet.Tablet, true /*usePool*/, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query: []byte(fmt.Sprintf("SET GLOBAL %v = %v", sqlescape.EscapeID(name), value)),
+	})
+	return err
+}
+
+// mysqldConfigDrift returns the global variables whose current values don't
+// match the mysqld config, sorted by name.
+func mysqldConfigDrift(config, variables map[string]string) []mysqldVariableDrift {
+	var drift []mysqldVariableDrift
+	for option, want := range config {
+		name := vttablet.MysqldVariableName(option)
+		have, ok := variables[name]
+		if !ok {
+			// This option isn't a global variable in this MySQL version.
+			continue
+		}
+		if name == bufferPoolSizeVariable {
+			want = roundBufferPoolSize(want, variables)
+		}
+		if !mysqldValuesEqual(want, have) {
+			drift = append(drift, mysqldVariableDrift{name: name, want: want, have: have})
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].name < drift[j].name
+	})
+	return drift
+}
+
+// roundBufferPoolSize rounds a configured buffer pool size up the same way
+// MySQL does, so it can be compared with the size MySQL reports.
+func roundBufferPoolSize(want string, variables map[string]string) string {
+	size, ok := parseMysqldInt(want)
+	if !ok {
+		return want
+	}
+	chunkSize, ok := parseMysqldInt(variables[bufferPoolChunkSizeVariable])
+	if !ok {
+		return want
+	}
+	instances, ok := parseMysqldInt(variables[bufferPoolInstancesVariable])
+	if !ok {
+		return want
+	}
+	multiple := chunkSize * instances
+	if multiple <= 0 {
+		return want
+	}
+	return strconv.FormatInt((size+multiple-1)/multiple*multiple, 10)
+}
+
+// mysqldValuesEqual returns whether a configured value and the value MySQL
+// reports for a variable mean the same thing. MySQL reports booleans as ON or
+// OFF, sizes in bytes, and fractional seconds with six decimal places, while
+// my.cnf also accepts 1 or 0, size suffixes, and shorter decimals.
+func mysqldValuesEqual(want, have string) bool {
+	if strings.EqualFold(want, have) {
+		return true
+	}
+	if wantBool, ok := parseMysqldBool(want); ok {
+		if haveBool, ok := parseMysqldBool(have); ok {
+			return wantBool == haveBool
+		}
+	}
+	if wantInt, ok := parseMysqldInt(want); ok {
+		if haveInt, ok := parseMysqldInt(have); ok {
+			return wantInt == haveInt
+		}
+	}
+	if wantFloat, err := strconv.ParseFloat(want, 64); err == nil {
+		if haveFloat, err := strconv.ParseFloat(have, 64); err == nil {
+			return wantFloat == haveFloat
+		}
+	}
+	return false
+}
+
+func parseMysqldBool(value string) (bool, bool) {
+	switch strings.ToUpper(value) {
+	case "ON", "TRUE", "1":
+		return true, true
+	case "OFF", "FALSE", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// parseMysqldInt parses an integer with an optional K, M, G, or T suffix, the
+// way my.cnf does.
+func parseMysqldInt(value string) (int64, bool) {
+	multiplier := int64(1)
+	if value != "" {
+		switch value[len(value)-1] {
+		case 'K', 'k':
+			multiplier = 1 << 10
+		case 'M', 'm':
+			multiplier = 1 << 20
+		case 'G', 'g':
+			multiplier = 1 << 30
+		case 'T', 't':
+			multiplier = 1 << 40
+		}
+		if multiplier != 1 {
+			value = value[:len(value)-1]
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * multiplier, true
+}
+
+// setMysqldConfigDriftAnnotation records the config drift found on a tablet
+// Pod, if it changed. An empty value clears the annotation.
+func (r *ReconcileVitessShard) setMysqldConfigDriftAnnotation(ctx context.Context, pod *corev1.Pod, drift string) error {
+	if pod.Annotations[vttablet.MysqldConfigDriftAnnotation] == drift {
+		return nil
+	}
+
+	if drift == "" {
+		delete(pod.Annotations, vttablet.MysqldConfigDriftAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vttablet.MysqldConfigDriftAnnotation] = drift
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMysqldConfigDrift(t *testing.T) {
+	config := map[string]string{
+		"innodb_buffer_pool_size":        "1000M",
+		"loose-innodb_redo_log_capacity": "268435456",
+		"max_connections":                "1024",
+		"slow-query-log":                 "1",
+		"sql_mode":                       "STRICT_TRANS_TABLES",
+		"skip-name-resolve":              "ON",
+	}
+	variables := map[string]string{
+		// 1000M rounds up to 1024M with 2 instances of 128M chunks.
+		"innodb_buffer_pool_size":       "1073741824",
+		"innodb_buffer_pool_chunk_size": "134217728",
+		"innodb_buffer_pool_instances":  "2",
+		"max_connections":               "151",
+		"slow_query_log":                "ON",
+		"sql_mode":                      "ONLY_FULL_GROUP_BY",
+	}
+
+	// Options that aren't global variables, like the redo log capacity on
+	// older versions, are skipped.
+	assert.Equal(t, []mysqldVariableDrift{
+		{name: "max_connections", want: "1024", have: "151"},
+		{name: "sql_mode", want: "STRICT_TRANS_TABLES", have: "ONLY_FULL_GROUP_BY"},
+	}, mysqldConfigDrift(config, variables))
+
+	// A buffer pool that was resized by hand is drift, even though the
+	// configured size isn't a multiple of the chunk size.
+	variables["innodb_buffer_pool_size"] = "2147483648"
+	variables["max_connections"] = "1024"
+	variables["sql_mode"] = "strict_trans_tables"
+	assert.Equal(t, []mysqldVariableDrift{
+		{name: "innodb_buffer_pool_size", want: "1073741824", have: "2147483648"},
+	}, mysqldConfigDrift(config, variables))
+}
+
+func TestMysqldValuesEqual(t *testing.T) {
+	assert.True(t, mysqldValuesEqual("1", "ON"))
+	assert.True(t, mysqldValuesEqual("off", "OFF"))
+	assert.True(t, mysqldValuesEqual("2G", "2147483648"))
+	assert.True(t, mysqldValuesEqual("64k", "65536"))
+	assert.True(t, mysqldValuesEqual("0.5", "0.500000"))
+	assert.True(t, mysqldValuesEqual("1", "1.000000"))
+	assert.False(t, mysqldValuesEqual("2", "ON"))
+	assert.False(t, mysqldValuesEqual("ROW", "STATEMENT"))
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// mysqldExporterUserTimeout is how long to wait for the primary to create the
+// monitoring user.
+const mysqldExporterUserTimeout = 10 * time.Second
+
+// reconcileMysqldExporterUser creates the monitoring user that the MySQL
+// exporters of the shard connect as, with the password in the Secret that the
+// main VitessShard controller generated. The user is created on the primary
+// and replicates to the other tablets.
+//
+// Once that succeeds, a hash of the Secret is recorded in an annotation on
+// it, so the user is only created again if the Secret changes.
+func (r *ReconcileVitessShard) reconcileMysqldExporterUser(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// External datastores don't run an exporter.
+	if !vts.Spec.MysqldExporterMonitoringUser() {
+		return resultBuilder.Result()
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: vts.Namespace, Name: vttablet.MysqldExporterName(clusterName, keyspaceName, vts.Spec.KeyRange)}
+	if err := r.client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Wait for the VitessShard controller to create it.
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+		return resultBuilder.Error(err)
+	}
+	hash := secrets.ContentHash(secret)
+	if secret.Annotations[vttablet.MysqldExporterUserAnnotation] == hash {
+		return resultBuilder.Result()
+	}
+
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	primary, err := wr.TopoServer().GetTablet(readCtx, shard.PrimaryAlias)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get primary tablet record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	fetchCtx, fetchCancel := context.WithTimeout(ctx, mysqldExporterUserTimeout)
+	defer fetchCancel()
+	password := string(secret.Data[vttablet.MysqldExporterPasswordKey])
+	for _, query := range vttablet.MysqldExporterUserQueries(password) {
+		_, err := wr.TabletManagerClient().ExecuteFetchAsDba(fetchCtx, primary.Tablet, false /*usePool*/, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query: []byte(query),
+		})
+		if err != nil {
+			// MySQL errors can quote the query, which has the password in it.
+			message := strings.ReplaceAll(err.Error(), password, "<redacted>")
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "MysqldExporterUserFailed", "failed to create the MySQL monitoring user on the primary: %v", message)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[vttablet.MysqldExporterUserAnnotation] = hash
+	if err := r.client.Update(ctx, secret); err != nil {
+		if !apierrors.IsConflict(err) {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to record the MySQL monitoring user on Secret %v: %v", secret.Name, err)
+		}
+		// Creating the user again next time does no harm.
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(vts, corev1.EventTypeNormal, "MysqldExporterUserCreated", "Created the MySQL monitoring user for mysqld-exporter on the primary")
+
+	return resultBuilder.Result()
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// reconcilePrimaryAffinity performs a planned reparent back to a tablet that
+// matches the shard's primaryAffinity, if a drain or failure moved the primary
+// somewhere else.
+//
+// To avoid flapping, we only move the primary once both the current primary's
+// term and the matching tablet's readiness have lasted for the affinity's
+// stableFor period.
+func (r *ReconcileVitessShard) reconcilePrimaryAffinity(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	affinity := vts.Spec.PrimaryAffinity
+	if affinity == nil || vts.Spec.Replication.ExternalReparents {
+		return resultBuilder.Result()
+	}
+	stableFor := affinity.StableFor.Duration
+	reparentSettings := vts.Spec.ReparentSettings
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, reparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+	if affinity.Matches(primaryAliasStr, shard.PrimaryAlias.GetCell()) {
+		return resultBuilder.Result()
+	}
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Only consider tablets that match the affinity and have been Ready long
+	// enough. If none have, wait for the first one that will.
+	now := time.Now()
+	matching := make(map[string]*topo.TabletInfo, len(tablets))
+	var wait time.Duration
+	for tabletAliasStr, tablet := range tablets {
+		if !affinity.Matches(tabletAliasStr, tablet.Alias.GetCell()) {
+			continue
+		}
+		readySince, ready := podReadySince(pods[tabletAliasStr])
+		if !ready {
+			continue
+		}
+		if stable, remaining := primaryAffinityStable(readySince, stableFor, now); !stable {
+			if wait == 0 || remaining < wait {
+				wait = remaining
+			}
+			continue
+		}
+		matching[tabletAliasStr] = tablet
+	}
+	if len(matching) == 0 {
+		if wait > 0 {
+			return resultBuilder.RequeueAfter(wait)
+		}
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Give the current primary a chance to settle in before moving it again.
+	if stable, remaining := primaryAffinityStable(shard.GetPrimaryTermStartTime(), stableFor, now); !stable {
+		return resultBuilder.RequeueAfter(remaining)
+	}
+
+	// Leave the primary alone while any drains are in progress, since those
+	// may need to reparent it themselves.
+	for _, pod := range pods {
+		if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryAffinityDeferred", "not moving primary tablet %v: tablet Pod %v is draining", primaryAliasStr, pod.Name)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+	}
+	if err := isShardHealthy(vts, nil); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryAffinityDeferred", "not moving primary tablet %v: shard is in an unhealthy state: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	newPrimary, rejected := candidatePrimary(ctx, wr, vts, shard, matching, pods)
+	if newPrimary == nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryAffinityBlocked", "unable to move primary tablet %v: no preferred tablet is a suitable primary candidate [%v]", primaryAliasStr, strings.Join(rejected, "; "))
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Only move the primary to a tablet that's caught up.
+	statuses := replicationStatuses(ctx, wr, map[string]*topo.TabletInfo{newPrimary.AliasString(): newPrimary})
+	if problems := replicaLagProblems(statuses, reparentSettings.TolerableReplicationLag.Duration); len(problems) > 0 {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryAffinityDeferred", "not moving primary tablet %v yet: %v", primaryAliasStr, strings.Join(problems, "; "))
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't move the primary into a cell whose replicas are lagging.
+	if err := r.checkCellReplicationLag(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryAffinityBlocked", "unable to move primary tablet %v: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't promote a primary that couldn't get enough semi-sync acks.
+	if err := checkSemiSyncAckers(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryAffinityBlocked", "unable to move primary tablet %v: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't reparent too many shards in the keyspace at once.
+	release, err := r.reparents.acquire(vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryAffinityDeferred", "not moving primary tablet %v yet: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	defer release()
+
+	// Perform a planned reparent.
+	plannedReparentTimeout := reparentSettings.PlannedReparentTimeout.Duration
+	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
+	defer reparentCancel()
+
+	var reparentErr error
+	if vts.Spec.UsingExternalDatastore() {
+		reparentErr = r.handleExternalReparent(ctx, vts, wr, newPrimary.Alias, shard.PrimaryAlias)
+	} else {
+		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, plannedReparentTimeout, reparentSettings.TolerableReplicationLag.Duration)
+	}
+
+	if reparentErr != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryAffinityFailed", "planned reparent from current primary %v to preferred primary %v failed: %v", primaryAliasStr, newPrimary.AliasString(), reparentErr)
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+	} else {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryAffinity", "planned reparent from old primary %v to preferred primary %v succeeded", primaryAliasStr, newPrimary.AliasString())
+	}
+
+	plannedReparentCount.WithLabelValues(metricLabels(vts, reparentErr)...).Inc()
+	r.recordReparent(ctx, vts, planetscalev2.AffinityReparentReason, primaryAliasStr, newPrimary.AliasString(), reparentErr)
+
+	return resultBuilder.Result()
+}
+
+// primaryAffinityStable returns whether something that started at since has
+// lasted for stableFor. If not, it also returns how long to wait until it has.
+//
+// If we don't know when it started, it's considered stable.
+func primaryAffinityStable(since time.Time, stableFor time.Duration, now time.Time) (bool, time.Duration) {
+	if since.IsZero() {
+		return true, 0
+	}
+	if elapsed := now.Sub(since); elapsed < stableFor {
+		return false, stableFor - elapsed
+	}
+	return true, 0
+}
+
+// podReadySince returns when a tablet Pod last became Ready, and false if it
+// is missing, terminating, or not Ready.
+func podReadySince(pod *corev1.Pod) (time.Time, bool) {
+	if pod == nil || pod.DeletionTimestamp != nil {
+		return time.Time{}, false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.LastTransitionTime.Time, cond.Status == corev1.ConditionTrue
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrimaryAffinityStable(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	stableFor := 5 * time.Minute
+
+	tests := []struct {
+		name       string
+		since      time.Time
+		wantStable bool
+		wantWait   time.Duration
+	}{
+		{name: "unknown start", since: time.Time{}, wantStable: true},
+		{name: "recent", since: now.Add(-2 * time.Minute), wantStable: false, wantWait: 3 * time.Minute},
+		{name: "just now", since: now, wantStable: false, wantWait: 5 * time.Minute},
+		{name: "long enough", since: now.Add(-5 * time.Minute), wantStable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stable, wait := primaryAffinityStable(tt.since, stableFor, now)
+			assert.Equal(t, tt.wantStable, stable)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
+
+func TestPodReadySince(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	readyPod := func(status corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(since)},
+				},
+			},
+		}
+	}
+	terminating := readyPod(corev1.ConditionTrue)
+	terminating.DeletionTimestamp = &metav1.Time{Time: since}
+
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		wantSince time.Time
+		wantReady bool
+	}{
+		{name: "missing", pod: nil},
+		{name: "no conditions", pod: &corev1.Pod{}},
+		{name: "not ready", pod: readyPod(corev1.ConditionFalse), wantSince: since},
+		{name: "terminating", pod: terminating},
+		{name: "ready", pod: readyPod(corev1.ConditionTrue), wantSince: since, wantReady: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ready := podReadySince(tt.pod)
+			assert.Equal(t, tt.wantReady, ready)
+			assert.True(t, tt.wantSince.Equal(got), "got %v, want %v", got, tt.wantSince)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/maintenance"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// reconcilePrimaryRotation performs a planned reparent away from the current
+// primary once the shard's primaryRotationSchedule says it's due.
+//
+// The schedule is evaluated relative to the start of the current primary's
+// term, as recorded in the shard record, so we don't need to keep any state of
+// our own about when we last rotated the primary.
+func (r *ReconcileVitessShard) reconcilePrimaryRotation(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	reparentSettings := vts.Spec.ReparentSettings
+	// Rotation would fight with primaryAffinity over where the primary goes.
+	if reparentSettings.PrimaryRotationSchedule == "" || vts.Spec.Replication.ExternalReparents || vts.Spec.PrimaryAffinity != nil {
+		return resultBuilder.Result()
+	}
+	schedule, err := cron.ParseStandard(reparentSettings.PrimaryRotationSchedule)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidPrimaryRotationSchedule", "failed to parse primaryRotationSchedule %q: %v", reparentSettings.PrimaryRotationSchedule, err)
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, reparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+
+	due, wait := primaryRotationDue(schedule, shard.GetPrimaryTermStartTime(), time.Now())
+	if !due {
+		// Make sure we come back when it's time, even if nothing else changes.
+		return resultBuilder.RequeueAfter(wait)
+	}
+	// Rotation is disruptive, so it waits for a maintenance window.
+	open, wait, err := maintenance.Open(vts.Spec.MaintenanceWindows, time.Now())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "InvalidMaintenanceWindow", "not rotating primary: %v", err)
+		return resultBuilder.Result()
+	}
+	if !open {
+		return resultBuilder.RequeueAfter(wait)
+	}
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Leave the primary alone while any drains are in progress, since those
+	// may need to reparent it themselves.
+	for _, pod := range pods {
+		if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryRotationDeferred", "not rotating primary tablet %v: tablet Pod %v is draining", primaryAliasStr, pod.Name)
+			return resultBuilder.RequeueAfter(replicationRequeueDelay)
+		}
+	}
+	if err := isShardHealthy(vts, nil); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryRotationDeferred", "not rotating primary tablet %v: shard is in an unhealthy state: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	newPrimary, rejected := candidatePrimary(ctx, wr, vts, shard, tablets, pods)
+	if newPrimary == nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryRotationBlocked", "unable to rotate primary tablet %v: no other tablet is a suitable primary candidate [%v]", primaryAliasStr, strings.Join(rejected, "; "))
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't move the primary into a cell whose replicas are lagging.
+	if err := r.checkCellReplicationLag(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryRotationBlocked", "unable to rotate primary tablet %v: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't promote a primary that couldn't get enough semi-sync acks.
+	if err := checkSemiSyncAckers(ctx, vts, wr, shard, tablets, pods, newPrimary); err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryRotationBlocked", "unable to rotate primary tablet %v: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// Don't reparent too many shards in the keyspace at once.
+	release, err := r.reparents.acquire(vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryRotationDeferred", "not rotating primary tablet %v yet: %v", primaryAliasStr, err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	defer release()
+
+	// Perform a planned reparent.
+	plannedReparentTimeout := reparentSettings.PlannedReparentTimeout.Duration
+	reparentCtx, reparentCancel := context.WithTimeout(ctx, plannedReparentTimeout)
+	defer reparentCancel()
+
+	var reparentErr error
+	if vts.Spec.UsingExternalDatastore() {
+		reparentErr = r.handleExternalReparent(ctx, vts, wr, newPrimary.Alias, shard.PrimaryAlias)
+	} else {
+		reparentErr = wr.PlannedReparentShard(reparentCtx, keyspaceName, vts.Spec.Name, newPrimary.Alias, nil, plannedReparentTimeout, reparentSettings.TolerableReplicationLag.Duration)
+	}
+
+	if reparentErr != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "PrimaryRotationFailed", "scheduled planned reparent from current primary %v to candidate primary %v failed: %v", primaryAliasStr, newPrimary.AliasString(), reparentErr)
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+	} else {
+		r.recorder.Eventf(vts, corev1.EventTypeNormal, "PrimaryRotation", "scheduled planned reparent from old primary %v to new primary %v succeeded", primaryAliasStr, newPrimary.AliasString())
+	}
+
+	plannedReparentCount.WithLabelValues(metricLabels(vts, reparentErr)...).Inc()
+	r.recordReparent(ctx, vts, planetscalev2.RotationReparentReason, primaryAliasStr, newPrimary.AliasString(), reparentErr)
+
+	return resultBuilder.Result()
+}
+
+// primaryRotationDue returns whether the first scheduled rotation after the
+// primary's term started has passed. If not, it also returns how long to wait
+// until it does.
+//
+// If we don't know when the term started, the primary is considered due.
+func primaryRotationDue(schedule cron.Schedule, primaryTermStart, now time.Time) (bool, time.Duration) {
+	if primaryTermStart.IsZero() {
+		return true, 0
+	}
+	next := schedule.Next(primaryTermStart)
+	if next.After(now) {
+		return false, next.Sub(now)
+	}
+	return true, 0
+}
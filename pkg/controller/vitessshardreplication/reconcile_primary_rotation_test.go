@@ -0,0 +1,66 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimaryRotationDue(t *testing.T) {
+	// Every Sunday at 03:00.
+	schedule, err := cron.ParseStandard("0 3 * * 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Wednesday.
+	now := time.Date(2024, time.January, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		termStart time.Time
+		wantDue   bool
+		wantWait  time.Duration
+	}{
+		{
+			name:      "elected this week",
+			termStart: now.Add(-time.Hour),
+			wantDue:   false,
+			wantWait:  3*24*time.Hour + 15*time.Hour,
+		},
+		{
+			name:      "elected before last rotation time",
+			termStart: now.Add(-5 * 24 * time.Hour),
+			wantDue:   true,
+		},
+		{
+			name:    "unknown term start",
+			wantDue: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, wait := primaryRotationDue(schedule, tt.termStart, now)
+			assert.Equal(t, tt.wantDue, due)
+			assert.Equal(t, tt.wantWait, wait)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileReplicationDelay configures MySQL's replication delay on tablets
+// in pools with delayedReplication, and removes it from tablets we previously
+// delayed whose pool no longer asks for it.
+//
+// Tablets in delayed pools are checked on every pass, since a restart or a
+// reparent may have reset the delay. For the others, we only check the ones
+// we've annotated as having been delayed.
+func (r *ReconcileVitessShard) reconcileReplicationDelay(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// We don't manage replication for external datastores.
+	if vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	// Put a tight limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	delayPods := make(map[string]*corev1.Pod, len(pods))
+	for tabletAliasStr, pod := range pods {
+		if delayedTablet(vts, pod) || pod.Annotations[vttablet.ReplicationDelayAnnotation] != "" {
+			delayPods[tabletAliasStr] = pod
+		}
+	}
+	if len(delayPods) == 0 {
+		return resultBuilder.Result()
+	}
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	// Replication is set up when the shard gets its first primary.
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	replicas := make(map[string]*topo.TabletInfo, len(delayPods))
+	for tabletAliasStr, pod := range delayPods {
+		tablet := tablets[tabletAliasStr]
+		if tablet == nil || topoproto.TabletAliasEqual(tablet.Alias, shard.PrimaryAlias) {
+			continue
+		}
+		if tablet.Type != topodatapb.TabletType_REPLICA && tablet.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		if pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		replicas[tabletAliasStr] = tablet
+	}
+
+	for _, result := range replicationStatuses(ctx, wr, replicas) {
+		pod := delayPods[result.aliasStr]
+		if result.err != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ReplicationDelayFailed", "failed to get replication status: %v", result.err)
+			resultBuilder.RequeueAfter(replicationRequeueDelay)
+			continue
+		}
+
+		delay := tabletPool(vts, pod).ReplicationDelay()
+		delaySeconds := int32(delay / time.Second)
+		if result.status.GetSqlDelay() != uint32(delaySeconds) {
+			if err := setReplicationDelay(ctx, wr, replicas[result.aliasStr], delaySeconds); err != nil {
+				r.recorder.Eventf(pod, corev1.EventTypeWarning, "ReplicationDelayFailed", "failed to set replication delay to %v: %v", delay, err)
+				resultBuilder.RequeueAfter(replicationRequeueDelay)
+				continue
+			}
+			r.recorder.Eventf(pod, corev1.EventTypeNormal, "ReplicationDelaySet", "set replication delay to %v", delay)
+		}
+
+		if err := r.setReplicationDelayAnnotation(ctx, pod, delay); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update replication delay annotation on Pod %v: %v", pod.Name, err)
+			resultBuilder.Error(err)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// delayedTablet returns whether a tablet Pod belongs to a pool with delayed
+// replication.
+func delayedTablet(vts *planetscalev2.VitessShard, pod *corev1.Pod) bool {
+	return pod != nil && tabletPool(vts, pod).ReplicationDelay() > 0
+}
+
+// replicationDelayQueries returns the queries that change the replication
+// delay on a replica. Only the SQL thread needs to be stopped, so the replica
+// keeps receiving (and acking) changes from the primary in the meantime.
+func replicationDelayQueries(delaySeconds int32) []string {
+	return []string{
+		"STOP SLAVE SQL_THREAD",
+		fmt.Sprintf("CHANGE MASTER TO MASTER_DELAY = %d", delaySeconds),
+		"START SLAVE SQL_THREAD",
+	}
+}
+
+// setReplicationDelay changes the replication delay on a replica.
+func setReplicationDelay(ctx context.Context, wr *wrangler.Wrangler, tablet *topo.TabletInfo, delaySeconds int32) error {
+	for _, query := range replicationDelayQueries(delaySeconds) {
+		req := &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+			Query: []byte(query),
+		}
+		if _, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, tablet.Tablet, false /*usePool*/, req); err != nil {
+			return fmt.Errorf("query %q failed: %v", query, err)
+		}
+	}
+	return nil
+}
+
+// setReplicationDelayAnnotation records the replication delay configured on a
+// tablet Pod, if it changed. A zero delay clears the annotation.
+func (r *ReconcileVitessShard) setReplicationDelayAnnotation(ctx context.Context, pod *corev1.Pod, delay time.Duration) error {
+	value := ""
+	if delay > 0 {
+		value = delay.String()
+	}
+	if pod.Annotations[vttablet.ReplicationDelayAnnotation] == value {
+		return nil
+	}
+
+	if value == "" {
+		delete(pod.Annotations, vttablet.ReplicationDelayAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vttablet.ReplicationDelayAnnotation] = value
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestDelayedTablet(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{
+		{Cell: "zone1", Type: planetscalev2.ReplicaPoolType},
+		{Cell: "zone1", Type: planetscalev2.RdonlyPoolType, DelayedReplication: &metav1.Duration{Duration: time.Hour}},
+	}
+	pod := func(cell string, poolType planetscalev2.VitessTabletPoolType) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			planetscalev2.CellLabel:       cell,
+			planetscalev2.TabletTypeLabel: string(poolType),
+		}}}
+	}
+
+	assert.False(t, delayedTablet(vts, nil), "no Pod")
+	assert.False(t, delayedTablet(vts, pod("zone1", planetscalev2.ReplicaPoolType)), "replica pool")
+	assert.True(t, delayedTablet(vts, pod("zone1", planetscalev2.RdonlyPoolType)), "delayed rdonly pool")
+	assert.False(t, delayedTablet(vts, pod("zone2", planetscalev2.RdonlyPoolType)), "rdonly Pod outside any pool")
+}
+
+func TestReplicationDelayQueries(t *testing.T) {
+	assert.Equal(t, []string{
+		"STOP SLAVE SQL_THREAD",
+		"CHANGE MASTER TO MASTER_DELAY = 3600",
+		"START SLAVE SQL_THREAD",
+	}, replicationDelayQueries(3600))
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/sqltypes"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+const (
+	// restoreProgressRequeueDelay is how often to check on tablets that are
+	// restoring from backup.
+	restoreProgressRequeueDelay = 30 * time.Second
+	// restoreProgressTimeout is how long to wait for a tablet to report its
+	// restore progress.
+	restoreProgressTimeout = 5 * time.Second
+
+	// primaryDataSizeQuery estimates how much data a restore writes, from the
+	// size of the tables on the primary.
+	primaryDataSizeQuery = "SELECT IFNULL(SUM(data_length + index_length), 0) FROM information_schema.tables"
+)
+
+// reconcileRestoreProgress records the progress of tablets that are restoring
+// from backup in annotations on their Pods.
+//
+// The main VitessShard controller copies the annotations into the tablet
+// status, since it owns the rest of the status.
+func (r *ReconcileVitessShard) reconcileRestoreProgress(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// Tablets for external datastores never restore from backup.
+	if vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	now := time.Now()
+	var estimatedTotalBytes *int64
+	for tabletAliasStr, pod := range pods {
+		tablet := tablets[tabletAliasStr]
+		if tablet == nil || tablet.Type != topodatapb.TabletType_RESTORE || pod.Status.PodIP == "" {
+			// Clear the progress of a restore that's over.
+			if err := r.setRestoreProgressAnnotation(ctx, pod, nil); err != nil {
+				resultBuilder.Error(err)
+			}
+			continue
+		}
+		resultBuilder.RequeueAfter(restoreProgressRequeueDelay)
+
+		progress := &planetscalev2.VitessTabletRestoreStatus{}
+		if last := pod.Annotations[vttablet.RestoreProgressAnnotation]; last == "" || json.Unmarshal([]byte(last), progress) != nil {
+			progress = &planetscalev2.VitessTabletRestoreStatus{StartTime: metav1.Time{Time: now}}
+		}
+
+		restoredBytes, err := tabletRestoredBytes(ctx, pod.Status.PodIP)
+		if err != nil {
+			// Leave the last known progress in place until we can check again.
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "RestoreProgressFailed", "failed to get restore progress: %v", err)
+			continue
+		}
+		if estimatedTotalBytes == nil {
+			size := primaryDataSize(ctx, wr, tablets)
+			estimatedTotalBytes = &size
+		}
+		updateRestoreProgress(progress, restoredBytes, *estimatedTotalBytes, now)
+
+		if err := r.setRestoreProgressAnnotation(ctx, pod, progress); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update restore progress annotation on Pod %v: %v", pod.Name, err)
+			resultBuilder.Error(err)
+		}
+	}
+
+	return resultBuilder.Result()
+}
+
+// updateRestoreProgress fills in the progress of a restore that has written
+// restoredBytes so far, out of an estimated total (or 0 if unknown).
+func updateRestoreProgress(progress *planetscalev2.VitessTabletRestoreStatus, restoredBytes, estimatedTotalBytes int64, now time.Time) {
+	progress.RestoredBytes = restoredBytes
+	progress.EstimatedTotalBytes = estimatedTotalBytes
+	progress.EstimatedCompletionTime = nil
+
+	if restoredBytes == 0 {
+		progress.Phase = planetscalev2.RestoreStartingPhase
+		return
+	}
+	progress.Phase = planetscalev2.RestoreCopyingPhase
+
+	// Assume the rest goes at the same rate as what we've seen so far. If
+	// we're already past the estimate, we have no idea how much is left.
+	elapsed := now.Sub(progress.StartTime.Time)
+	if estimatedTotalBytes <= restoredBytes || elapsed <= 0 {
+		return
+	}
+	remaining := time.Duration(float64(elapsed) * float64(estimatedTotalBytes-restoredBytes) / float64(restoredBytes))
+	progress.EstimatedCompletionTime = &metav1.Time{Time: now.Add(remaining).Truncate(time.Second)}
+}
+
+// tabletRestoredBytes asks vttablet how many bytes it has written while
+// restoring from backup.
+func tabletRestoredBytes(ctx context.Context, podIP string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, restoreProgressTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("http://%s/debug/vars", net.JoinHostPort(podIP, strconv.Itoa(planetscalev2.DefaultWebPort)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected HTTP status from %v: %v", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	return restoredBytes(body)
+}
+
+// restoredBytes parses the bytes written by the backup engine out of the
+// RestoreBytes counters in vttablet's /debug/vars.
+func restoredBytes(vars []byte) (int64, error) {
+	var parsed struct {
+		RestoreBytes map[string]int64
+	}
+	if err := json.Unmarshal(vars, &parsed); err != nil {
+		return 0, fmt.Errorf("failed to parse /debug/vars: %v", err)
+	}
+
+	// The counters are keyed by "component.implementation.operation". Only
+	// count the data written to disk, so we don't count the same bytes again
+	// as they're read from storage and decompressed.
+	var total int64
+	for key, value := range parsed.RestoreBytes {
+		if strings.HasPrefix(key, "BackupEngine.") && strings.HasSuffix(key, ".Destination:Write") {
+			total += value
+		}
+	}
+	return total, nil
+}
+
+// primaryDataSize returns the size of the tables on the shard's primary, or 0
+// if it can't be determined.
+func primaryDataSize(ctx context.Context, wr *wrangler.Wrangler, tablets map[string]*topo.TabletInfo) int64 {
+	var primary *topo.TabletInfo
+	for _, tablet := range tablets {
+		if tablet.Type == topodatapb.TabletType_PRIMARY {
+			primary = tablet
+			break
+		}
+	}
+	if primary == nil {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, restoreProgressTimeout)
+	defer cancel()
+	qrproto, err := wr.TabletManagerClient().ExecuteFetchAsDba(ctx, primary.Tablet, true /*usePool*/, &tabletmanagerdatapb.ExecuteFetchAsDbaRequest{
+		Query:   []byte(primaryDataSizeQuery),
+		MaxRows: 1,
+	})
+	if err != nil {
+		return 0
+	}
+	qr := sqltypes.Proto3ToResult(qrproto)
+	if len(qr.Rows) == 0 || len(qr.Rows[0]) == 0 {
+		return 0
+	}
+	size, err := qr.Rows[0][0].ToInt64()
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// setRestoreProgressAnnotation records the restore progress of a tablet Pod,
+// if it changed. A nil value clears the annotation.
+func (r *ReconcileVitessShard) setRestoreProgressAnnotation(ctx context.Context, pod *corev1.Pod, progress *planetscalev2.VitessTabletRestoreStatus) error {
+	value := ""
+	if progress != nil {
+		encoded, err := json.Marshal(progress)
+		if err != nil {
+			return err
+		}
+		value = string(encoded)
+	}
+	if pod.Annotations[vttablet.RestoreProgressAnnotation] == value {
+		return nil
+	}
+
+	if value == "" {
+		delete(pod.Annotations, vttablet.RestoreProgressAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vttablet.RestoreProgressAnnotation] = value
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestRestoredBytes(t *testing.T) {
+	vars := []byte(`{
+		"RestoreBytes": {
+			"BackupEngine.Builtin.Destination:Write": 1000,
+			"BackupEngine.Builtin.Source:Read": 400,
+			"BackupStorage.S3.File:Read": 400
+		},
+		"RestorePosition": ""
+	}`)
+	got, err := restoredBytes(vars)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), got)
+
+	got, err = restoredBytes([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Zero(t, got)
+
+	_, err = restoredBytes([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestUpdateRestoreProgress(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Minute)
+
+	tests := []struct {
+		name          string
+		restoredBytes int64
+		totalBytes    int64
+		wantPhase     planetscalev2.VitessTabletRestorePhase
+		wantETA       *metav1.Time
+	}{
+		{
+			name:      "nothing written yet",
+			wantPhase: planetscalev2.RestoreStartingPhase,
+		},
+		{
+			name:          "a quarter done",
+			restoredBytes: 250,
+			totalBytes:    1000,
+			wantPhase:     planetscalev2.RestoreCopyingPhase,
+			wantETA:       &metav1.Time{Time: now.Add(30 * time.Minute)},
+		},
+		{
+			name:          "unknown total",
+			restoredBytes: 250,
+			wantPhase:     planetscalev2.RestoreCopyingPhase,
+		},
+		{
+			name:          "past the estimate",
+			restoredBytes: 2000,
+			totalBytes:    1000,
+			wantPhase:     planetscalev2.RestoreCopyingPhase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := &planetscalev2.VitessTabletRestoreStatus{StartTime: metav1.Time{Time: start}}
+			updateRestoreProgress(progress, tt.restoredBytes, tt.totalBytes, now)
+			assert.Equal(t, tt.wantPhase, progress.Phase)
+			assert.Equal(t, tt.restoredBytes, progress.RestoredBytes)
+			assert.Equal(t, tt.wantETA, progress.EstimatedCompletionTime)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+)
+
+// reconcileStandbyRefresh keeps the tablets of a standby shard close to the
+// cluster it follows. Since a standby has no primary to replicate from, each
+// tablet is periodically reseeded, which restores it from the latest backup.
+func (r *ReconcileVitessShard) reconcileStandbyRefresh(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	resultBuilder := &results.Builder{}
+
+	standby := vts.Spec.Standby
+	if standby == nil || standby.RefreshInterval == nil || standby.RefreshInterval.Duration <= 0 || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	pods, err := r.tabletPods(ctx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+
+	tabletAliasStr, wait := nextStandbyRefresh(vts, pods, standby.RefreshInterval.Duration, time.Now())
+	if tabletAliasStr == "" {
+		if wait > 0 {
+			resultBuilder.RequeueAfter(wait)
+		}
+		return resultBuilder.Result()
+	}
+
+	// Check on the shard again soon, whether or not we can refresh it now.
+	resultBuilder.RequeueAfter(replicationRequeueDelay)
+
+	pod := pods[tabletAliasStr]
+	if err := checkStandbyRefresh(vts, pods, tabletAliasStr); err != nil {
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "StandbyRefreshDeferred", "not refreshing standby tablet from the latest backup: %v", err)
+		return resultBuilder.Result()
+	}
+	if err := r.reseedFromBackup(ctx, pod); err != nil {
+		r.recorder.Eventf(pod, corev1.EventTypeWarning, "StandbyRefreshFailed", "failed to refresh standby tablet from the latest backup: %v", err)
+		return resultBuilder.Error(err)
+	}
+	r.recorder.Eventf(pod, corev1.EventTypeNormal, "StandbyRefresh", "refreshing standby tablet from the latest backup after %v", standby.RefreshInterval.Duration)
+
+	return resultBuilder.Result()
+}
+
+// nextStandbyRefresh returns the desired tablet whose Pod has gone the longest
+// without a refresh, if that's longer than the refresh interval. Otherwise,
+// it returns how long until the next tablet is due, or 0 if there are none.
+func nextStandbyRefresh(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod, interval time.Duration, now time.Time) (string, time.Duration) {
+	tabletAliases := make([]string, 0, len(vts.Status.Tablets))
+	for tabletAliasStr := range vts.Status.Tablets {
+		tabletAliases = append(tabletAliases, tabletAliasStr)
+	}
+	sort.Strings(tabletAliases)
+
+	oldest := ""
+	var oldestTime time.Time
+	for _, tabletAliasStr := range tabletAliases {
+		pod := pods[tabletAliasStr]
+		if pod == nil || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if oldest == "" || pod.CreationTimestamp.Time.Before(oldestTime) {
+			oldest = tabletAliasStr
+			oldestTime = pod.CreationTimestamp.Time
+		}
+	}
+	if oldest == "" {
+		return "", 0
+	}
+	if due := oldestTime.Add(interval); due.After(now) {
+		return "", due.Sub(now)
+	}
+	return oldest, 0
+}
+
+// checkStandbyRefresh returns an error if it's not safe to refresh the given
+// tablet, because another desired tablet in the shard is down or is still
+// restoring. That keeps at most one tablet per shard refreshing at a time.
+//
+// Unlike checkAutoReseed, this doesn't require the other tablets to be Ready,
+// since tablets without a primary to replicate from might never be.
+func checkStandbyRefresh(vts *planetscalev2.VitessShard, pods map[string]*corev1.Pod, tabletAliasStr string) error {
+	otherAliases := make([]string, 0, len(vts.Status.Tablets))
+	for otherAliasStr := range vts.Status.Tablets {
+		if otherAliasStr != tabletAliasStr {
+			otherAliases = append(otherAliases, otherAliasStr)
+		}
+	}
+	sort.Strings(otherAliases)
+	for _, otherAliasStr := range otherAliases {
+		pod := pods[otherAliasStr]
+		tablet := vts.Status.Tablets[otherAliasStr]
+		switch {
+		case pod == nil:
+			return fmt.Errorf("tablet %v has no Pod", otherAliasStr)
+		case pod.DeletionTimestamp != nil:
+			return fmt.Errorf("tablet %v is being deleted", otherAliasStr)
+		case tablet.Running != corev1.ConditionTrue:
+			return fmt.Errorf("tablet %v is not running", otherAliasStr)
+		case tablet.Type == "" || tablet.Type == "restore":
+			return fmt.Errorf("tablet %v hasn't finished restoring", otherAliasStr)
+		}
+	}
+	return nil
+}
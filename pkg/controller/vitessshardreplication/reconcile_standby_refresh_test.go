@@ -0,0 +1,72 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestStandbyRefresh(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	interval := 24 * time.Hour
+
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+		"zone1-0000000101": {Running: corev1.ConditionTrue, Type: "replica"},
+		"zone1-0000000102": {Running: corev1.ConditionTrue, Type: "replica"},
+	}
+	pod := func(age time.Duration) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+
+	// Nothing is due yet, so wait for the oldest tablet.
+	pods := map[string]*corev1.Pod{
+		"zone1-0000000101": pod(20 * time.Hour),
+		"zone1-0000000102": pod(2 * time.Hour),
+	}
+	tablet, wait := nextStandbyRefresh(vts, pods, interval, now)
+	assert.Equal(t, "", tablet)
+	assert.Equal(t, 4*time.Hour, wait)
+
+	// The oldest tablet is refreshed first once it's due.
+	pods["zone1-0000000102"] = pod(30 * time.Hour)
+	pods["zone1-0000000101"] = pod(25 * time.Hour)
+	tablet, _ = nextStandbyRefresh(vts, pods, interval, now)
+	assert.Equal(t, "zone1-0000000102", tablet)
+	assert.NoError(t, checkStandbyRefresh(vts, pods, tablet))
+
+	// Only one tablet refreshes at a time.
+	restoring := vts.Status.Tablets["zone1-0000000101"]
+	restoring.Type = "restore"
+	vts.Status.Tablets["zone1-0000000101"] = restoring
+	assert.Error(t, checkStandbyRefresh(vts, pods, tablet))
+
+	delete(pods, "zone1-0000000101")
+	assert.Error(t, checkStandbyRefresh(vts, pods, tablet))
+
+	// There's nothing to do without tablets.
+	tablet, wait = nextStandbyRefresh(&planetscalev2.VitessShard{}, pods, interval, now)
+	assert.Equal(t, "", tablet)
+	assert.Zero(t, wait)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/rollout"
+	"planetscale.dev/vitess-operator/pkg/operator/vitesskeyspace"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// reconcileUpgradeChecks runs the safety checks that the update strategy calls
+// for before a new vttablet or mysqld image is rolled out to the shard, and
+// records the outcome in the shard's UpgradeBlocked condition. The main
+// VitessShard controller only starts releasing tablets onto the new images
+// once the condition is False.
+//
+// Once the checks have passed, they aren't run again for the same upgrade.
+// The main VitessShard controller resets the condition when no upgrade is
+// pending anymore.
+func (r *ReconcileVitessShard) reconcileUpgradeChecks(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	clusterName := vts.Labels[planetscalev2.ClusterLabel]
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	if !vts.Spec.UpgradeChecksEnabled() || !rollout.Cascading(vts) {
+		return resultBuilder.Result()
+	}
+	if vts.Status.UpgradeChecksPassed() {
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, vts.Spec.ReparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	if !vttablet.ImageUpgradePending(pods, &vts.Spec.Images) {
+		return resultBuilder.Result()
+	}
+
+	vtk := &planetscalev2.VitessKeyspace{}
+	vtkKey := client.ObjectKey{Namespace: vts.Namespace, Name: vitesskeyspace.Name(clusterName, keyspaceName)}
+	if err := r.client.Get(readCtx, vtkKey, vtk); err != nil && !apierrors.IsNotFound(err) {
+		return resultBuilder.Error(err)
+	}
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	var primaryAliasStr string
+	if shard.HasPrimary() {
+		primaryAliasStr = topoproto.TabletAliasString(shard.PrimaryAlias)
+	}
+
+	problems := upgradeCheckProblems(vts, &vtk.Status, primaryAliasStr, time.Now())
+
+	// Ask each replica that isn't delayed on purpose how far behind it is.
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoListFailed", "failed to list tablets: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	replicas := make(map[string]*topo.TabletInfo, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		if tabletAliasStr == primaryAliasStr || delayedTablet(vts, pods[tabletAliasStr]) {
+			continue
+		}
+		if tablet.Type != topodatapb.TabletType_REPLICA && tablet.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		replicas[tabletAliasStr] = tablet
+	}
+	maxLag := vts.Spec.UpdateStrategy.UpgradeChecks.MaxReplicationLag.Duration
+	problems = append(problems, replicaLagProblems(replicationStatuses(ctx, wr, replicas), maxLag)...)
+
+	if len(problems) > 0 {
+		message := fmt.Sprintf("Not upgrading tablets until the pre-upgrade checks pass: %v", strings.Join(problems, "; "))
+		r.setCondition(ctx, vts, planetscalev2.VitessShardUpgradeBlocked, corev1.ConditionTrue, "PreflightChecksFailed", message)
+		r.recorder.Event(vts, corev1.EventTypeWarning, "UpgradeBlocked", message)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	r.setCondition(ctx, vts, planetscalev2.VitessShardUpgradeBlocked, corev1.ConditionFalse, "PreflightChecksPassed", "All pre-upgrade checks passed.")
+	return resultBuilder.Result()
+}
+
+// upgradeCheckProblems returns a description of each pre-upgrade check that
+// can be answered from the shard and keyspace status and fails: the latest
+// complete backup must be recent enough, the keyspace must not be resharding,
+// and enough replicas must be healthy. Replication lag is checked separately,
+// since it needs to ask the tablets.
+func upgradeCheckProblems(vts *planetscalev2.VitessShard, keyspaceStatus *planetscalev2.VitessKeyspaceStatus, primaryAliasStr string, now time.Time) []string {
+	checks := vts.Spec.UpdateStrategy.UpgradeChecks
+	var problems []string
+
+	if maxAge := checks.MaxBackupAge.Duration; maxAge > 0 {
+		var latest time.Time
+		for _, location := range vts.Status.BackupLocations {
+			if location.LatestCompleteBackupTime != nil && location.LatestCompleteBackupTime.After(latest) {
+				latest = location.LatestCompleteBackupTime.Time
+			}
+		}
+		switch {
+		case latest.IsZero():
+			problems = append(problems, "shard has no complete backup")
+		case now.Sub(latest) > maxAge:
+			problems = append(problems, fmt.Sprintf("latest complete backup is older than %v", maxAge))
+		}
+	}
+
+	if cond, ok := keyspaceStatus.GetCondition(planetscalev2.VitessKeyspaceReshardingActive); ok && cond.Status == corev1.ConditionTrue {
+		problems = append(problems, "keyspace has a resharding operation in progress")
+	}
+
+	healthy := 0
+	var unhealthy []string
+	for tabletAliasStr, tablet := range vts.Status.Tablets {
+		if tabletAliasStr == primaryAliasStr || tablet.PoolType != string(planetscalev2.ReplicaPoolType) {
+			continue
+		}
+		if tablet.Available == corev1.ConditionTrue && tablet.Replicating != corev1.ConditionFalse {
+			healthy++
+		} else {
+			unhealthy = append(unhealthy, tabletAliasStr)
+		}
+	}
+	if minHealthy := *checks.MinHealthyReplicas; int32(healthy) < minHealthy {
+		sort.Strings(unhealthy)
+		problems = append(problems, fmt.Sprintf("only %d of the required %d replicas are healthy (unhealthy: %v)", healthy, minHealthy, strings.Join(unhealthy, ", ")))
+	}
+
+	return problems
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpgradeCheckProblems(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newShard := func(backupAge time.Duration) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Spec.UpdateStrategy = &planetscalev2.VitessClusterUpdateStrategy{
+			UpgradeChecks: &planetscalev2.VitessUpgradeChecks{
+				MaxBackupAge:       &metav1.Duration{Duration: 24 * time.Hour},
+				MaxReplicationLag:  &metav1.Duration{Duration: 30 * time.Second},
+				MinHealthyReplicas: pointer.Int32Ptr(1),
+			},
+		}
+		vts.Status.BackupLocations = []*planetscalev2.ShardBackupLocationStatus{
+			{LatestCompleteBackupTime: &metav1.Time{Time: now.Add(-backupAge)}},
+		}
+		vts.Status.Tablets = map[string]planetscalev2.VitessTabletStatus{
+			"zone1-1": {PoolType: "replica", Available: corev1.ConditionTrue, Replicating: corev1.ConditionTrue},
+			"zone1-2": {PoolType: "replica", Available: corev1.ConditionTrue, Replicating: corev1.ConditionTrue},
+			"zone1-3": {PoolType: "rdonly", Available: corev1.ConditionTrue, Replicating: corev1.ConditionTrue},
+		}
+		return vts
+	}
+
+	noBackup := newShard(time.Hour)
+	noBackup.Status.BackupLocations = nil
+
+	unhealthyReplica := newShard(time.Hour)
+	unhealthyReplica.Status.Tablets["zone1-2"] = planetscalev2.VitessTabletStatus{PoolType: "replica", Available: corev1.ConditionTrue, Replicating: corev1.ConditionFalse}
+
+	backupCheckOff := newShard(48 * time.Hour)
+	backupCheckOff.Spec.UpdateStrategy.UpgradeChecks.MaxBackupAge.Duration = 0
+
+	resharding := &planetscalev2.VitessKeyspaceStatus{}
+	resharding.SetConditionStatus(planetscalev2.VitessKeyspaceReshardingActive, corev1.ConditionTrue, "ActiveReshardingWorkflow", "")
+
+	tests := []struct {
+		name           string
+		vts            *planetscalev2.VitessShard
+		keyspaceStatus *planetscalev2.VitessKeyspaceStatus
+		want           []string
+	}{
+		{
+			name: "all checks pass",
+			vts:  newShard(time.Hour),
+			want: nil,
+		},
+		{
+			name: "no backup",
+			vts:  noBackup,
+			want: []string{"shard has no complete backup"},
+		},
+		{
+			name: "old backup",
+			vts:  newShard(48 * time.Hour),
+			want: []string{"latest complete backup is older than 24h0m0s"},
+		},
+		{
+			name: "backup check disabled",
+			vts:  backupCheckOff,
+			want: nil,
+		},
+		{
+			name:           "resharding",
+			vts:            newShard(time.Hour),
+			keyspaceStatus: resharding,
+			want:           []string{"keyspace has a resharding operation in progress"},
+		},
+		{
+			name: "only replica besides primary is not replicating",
+			vts:  unhealthyReplica,
+			want: []string{"only 0 of the required 1 replicas are healthy (unhealthy: zone1-2)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyspaceStatus := tt.keyspaceStatus
+			if keyspaceStatus == nil {
+				keyspaceStatus = &planetscalev2.VitessKeyspaceStatus{}
+			}
+			assert.Equal(t, tt.want, upgradeCheckProblems(tt.vts, keyspaceStatus, "zone1-1", now))
+		})
+	}
+}
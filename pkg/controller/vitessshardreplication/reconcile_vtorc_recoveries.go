@@ -0,0 +1,100 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
+)
+
+// drainLockAction is what we tell others we're doing while we hold the
+// shard lock to reparent away from a draining primary.
+const drainLockAction = "vitess-operator: reparent draining primary"
+
+// reconcileVtorcRecoveries suspends recoveries in the shard's own vtorc while
+// the operator is draining tablets in the shard, and resumes them afterward.
+// Otherwise vtorc might see a tablet going down for the drain and reparent
+// at the same time as we do.
+//
+// A keyspace-wide vtorc is left alone, since suspending it would affect every
+// shard in the keyspace. Reparents are still safe from it, because we hold the
+// shard lock while reparenting, and vtorc skips shards that are locked.
+func (r *ReconcileVitessShard) reconcileVtorcRecoveries(ctx context.Context, vts *planetscalev2.VitessShard, suspend bool) error {
+	labels := map[string]string{
+		planetscalev2.ComponentLabel: planetscalev2.VtorcComponentName,
+		planetscalev2.ClusterLabel:   vts.Labels[planetscalev2.ClusterLabel],
+		planetscalev2.KeyspaceLabel:  vts.Labels[planetscalev2.KeyspaceLabel],
+		planetscalev2.ShardLabel:     vts.Spec.KeyRange.SafeName(),
+	}
+	podList := &corev1.PodList{}
+	listOpts := &client.ListOptions{
+		Namespace:     vts.Namespace,
+		LabelSelector: apilabels.SelectorFromSet(apilabels.Set(labels)),
+	}
+	if err := r.client.List(ctx, podList, listOpts); err != nil {
+		return fmt.Errorf("failed to list vtorc Pods: %v", err)
+	}
+
+	var firstErr error
+	for _, pod := range vtorcPodsToUpdate(podList.Items, suspend) {
+		if err := vtorc.SetRecoveriesEnabled(ctx, pod.Status.PodIP, !suspend); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if suspend {
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[vtorc.RecoveriesSuspendedAnnotation] = "true"
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "VtorcRecoveriesSuspended", "Suspended vtorc recoveries in Pod %v while tablets are draining.", pod.Name)
+		} else {
+			delete(pod.Annotations, vtorc.RecoveriesSuspendedAnnotation)
+			r.recorder.Eventf(vts, corev1.EventTypeNormal, "VtorcRecoveriesResumed", "Resumed vtorc recoveries in Pod %v.", pod.Name)
+		}
+		if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// vtorcPodsToUpdate returns the running vtorc Pods whose recoveries aren't
+// yet suspended or resumed as requested.
+func vtorcPodsToUpdate(pods []corev1.Pod, suspend bool) []*corev1.Pod {
+	var update []*corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		_, suspended := pod.Annotations[vtorc.RecoveriesSuspendedAnnotation]
+		if suspended != suspend {
+			update = append(update, pod)
+		}
+	}
+	return update
+}
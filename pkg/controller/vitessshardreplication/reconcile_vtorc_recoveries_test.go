@@ -0,0 +1,55 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"planetscale.dev/vitess-operator/pkg/operator/vtorc"
+)
+
+func TestVtorcPodsToUpdate(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, suspended bool) corev1.Pod {
+		p := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+		if suspended {
+			p.Annotations = map[string]string{vtorc.RecoveriesSuspendedAnnotation: "true"}
+		}
+		return p
+	}
+	names := func(pods []*corev1.Pod) []string {
+		var out []string
+		for _, p := range pods {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	pods := []corev1.Pod{
+		pod("running", corev1.PodRunning, false),
+		pod("suspended", corev1.PodRunning, true),
+		pod("pending", corev1.PodPending, false),
+	}
+
+	assert.Equal(t, []string{"running"}, names(vtorcPodsToUpdate(pods, true)))
+	assert.Equal(t, []string{"suspended"}, names(vtorcPodsToUpdate(pods, false)))
+}
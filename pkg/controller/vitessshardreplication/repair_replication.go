@@ -0,0 +1,318 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"vitess.io/vitess/go/mysql/replication"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+	"planetscale.dev/vitess-operator/pkg/operator/metrics"
+	"planetscale.dev/vitess-operator/pkg/operator/results"
+	"planetscale.dev/vitess-operator/pkg/operator/vttablet"
+)
+
+// sourceFatalErrorReadingBinlog is the MySQL error (ER_SOURCE_FATAL_ERROR_READING_BINLOG)
+// that the replica IO thread reports when the source can't serve the replica's
+// GTID set, either because it purged binary logs the replica still needs, or
+// because the replica has transactions the source doesn't know about.
+const sourceFatalErrorReadingBinlog = "fatal error 1236"
+
+// repairReplication checks replication on each Ready replica-type tablet,
+// records the result in annotations on the tablet Pods, and tries to repair
+// any replica whose replication is broken with the shard's repair actions.
+//
+// The main VitessShard controller copies the annotations into the tablet
+// status, since it owns the rest of the status.
+func (r *ReconcileVitessShard) repairReplication(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (reconcile.Result, error) {
+	keyspaceName := vts.Labels[planetscalev2.KeyspaceLabel]
+	resultBuilder := &results.Builder{}
+
+	// We don't manage replication for external datastores.
+	repair := vts.Spec.Replication.Repair
+	if repair == nil || vts.Spec.UsingExternalDatastore() {
+		return resultBuilder.Result()
+	}
+
+	// Don't hold our slot in the reconcile work queue for too long.
+	ctx, cancel := context.WithTimeout(ctx, vts.Spec.ReparentSettings.ReconcileDrainTimeout.Duration)
+	defer cancel()
+
+	// Put a tighter limit on the initial read phase so we fail fast.
+	readCtx, readCancel := context.WithTimeout(ctx, reconcileDrainReadTimeout)
+	defer readCancel()
+
+	shard, err := wr.TopoServer().GetShard(readCtx, keyspaceName, vts.Spec.Name)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get shard record: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+	// Replication is set up when the shard gets its first primary.
+	if !shard.HasPrimary() {
+		return resultBuilder.Result()
+	}
+
+	pods, err := r.tabletPods(readCtx, vts)
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "ListFailed", "failed to list Pods: %v", err)
+		return resultBuilder.Error(err)
+	}
+	tablets, err := wr.TopoServer().GetTabletMapForShardByCell(readCtx, keyspaceName, vts.Spec.Name, vts.Spec.GetCells().UnsortedList())
+	if err != nil {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "TopoGetFailed", "failed to get tablet records: %v", err)
+		return resultBuilder.RequeueAfter(replicationRequeueDelay)
+	}
+
+	// The primary doesn't replicate, so make sure it doesn't report a stale
+	// status from before it was promoted.
+	primaryAliasStr := topoproto.TabletAliasString(shard.PrimaryAlias)
+	if pod := pods[primaryAliasStr]; pod != nil {
+		if err := r.setReplicationAnnotations(ctx, pod, "", ""); err != nil {
+			resultBuilder.Error(err)
+		}
+	}
+
+	replicas := make(map[string]*topo.TabletInfo, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		if tabletAliasStr == primaryAliasStr {
+			continue
+		}
+		if tablet.Type != topodatapb.TabletType_REPLICA && tablet.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		// Leave tablets that are starting up, shutting down, or draining alone.
+		pod := pods[tabletAliasStr]
+		if pod == nil || pod.DeletionTimestamp != nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+			continue
+		}
+		replicas[tabletAliasStr] = tablet
+	}
+	statuses := replicationStatuses(ctx, wr, replicas)
+	// Repair deterministically, so reseeds always pick the same tablet first.
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].aliasStr < statuses[j].aliasStr
+	})
+
+	reseeded := false
+	for _, result := range statuses {
+		pod := pods[result.aliasStr]
+
+		var replicating corev1.ConditionStatus
+		var problem string
+		switch {
+		case result.err != nil:
+			replicating = corev1.ConditionUnknown
+			problem = fmt.Sprintf("failed to get replication status: %v", result.err)
+		default:
+			problem = replicationProblem(result.status)
+			if problem == "" {
+				replicating = corev1.ConditionTrue
+			} else {
+				replicating = corev1.ConditionFalse
+			}
+		}
+		if err := r.setReplicationAnnotations(ctx, pod, replicating, problem); err != nil {
+			r.recorder.Eventf(vts, corev1.EventTypeWarning, "UpdateFailed", "failed to update replication annotations on Pod %v: %v", pod.Name, err)
+			resultBuilder.Error(err)
+		}
+		if replicating != corev1.ConditionFalse {
+			continue
+		}
+
+		// Check on the replica again soon, whether or not we can fix it.
+		resultBuilder.RequeueAfter(replicationRequeueDelay)
+
+		canReseed := !reseeded && hasCompleteBackup(vts) && checkPoolDisruption(vts, pods, result.aliasStr, nil, 1) == nil
+		action := repairAction(repair.Actions, result.status, canReseed)
+		if action == "" {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ReplicationBroken", "replication is broken and no repair action applies: %v", problem)
+			continue
+		}
+
+		var repairErr error
+		switch action {
+		case planetscalev2.StartReplicationRepairAction:
+			repairErr = startReplication(ctx, vts, wr, tablets[primaryAliasStr], replicas[result.aliasStr])
+		case planetscalev2.ReseedFromBackupRepairAction:
+			repairErr = r.reseedFromBackup(ctx, pod)
+			reseeded = true
+		}
+		replicationRepairCount.WithLabelValues(shardLabels(vts, string(action), metrics.Result(repairErr))...).Inc()
+		if repairErr != nil {
+			r.recorder.Eventf(pod, corev1.EventTypeWarning, "ReplicationRepairFailed", "%v failed for broken replication (%v): %v", action, problem, repairErr)
+			continue
+		}
+		r.recorder.Eventf(pod, corev1.EventTypeNormal, "ReplicationRepair", "performed %v for broken replication: %v", action, problem)
+	}
+
+	return resultBuilder.Result()
+}
+
+// replicationProblem returns a description of what's wrong with replication,
+// or an empty string if it's healthy.
+//
+// This follows the same rules as ReplicationStatus.Healthy() in Vitess, which
+// we can't use directly because converting the status proto panics if any of
+// its positions fail to parse.
+func replicationProblem(status *replicationdatapb.Status) string {
+	var problems []string
+
+	ioState := replication.ReplicationState(status.GetIoState())
+	ioHealthy := ioState == replication.ReplicationStateRunning ||
+		(ioState == replication.ReplicationStateConnecting && status.GetLastIoError() == "")
+	if !ioHealthy {
+		if status.GetLastIoError() != "" {
+			problems = append(problems, fmt.Sprintf("IO thread error: %v", status.GetLastIoError()))
+		} else {
+			problems = append(problems, "IO thread is not running")
+		}
+	}
+
+	if replication.ReplicationState(status.GetSqlState()) != replication.ReplicationStateRunning {
+		if status.GetLastSqlError() != "" {
+			problems = append(problems, fmt.Sprintf("SQL thread error: %v", status.GetLastSqlError()))
+		} else {
+			problems = append(problems, "SQL thread is not running")
+		}
+	}
+
+	return strings.Join(problems, "; ")
+}
+
+// gtidDiverged returns whether a replica's replication broke because its GTID
+// set can no longer be served by the source. Restarting replication won't help
+// in that case.
+func gtidDiverged(status *replicationdatapb.Status) bool {
+	return strings.Contains(strings.ToLower(status.GetLastIoError()), sourceFatalErrorReadingBinlog)
+}
+
+// repairAction returns the first of the configured actions that applies to a
+// replica with the given broken replication status, or an empty string if none
+// of them do.
+func repairAction(actions []planetscalev2.VitessReplicationRepairAction, status *replicationdatapb.Status, canReseed bool) planetscalev2.VitessReplicationRepairAction {
+	diverged := gtidDiverged(status)
+	for _, action := range actions {
+		switch action {
+		case planetscalev2.StartReplicationRepairAction:
+			if !diverged {
+				return action
+			}
+		case planetscalev2.ReseedFromBackupRepairAction:
+			if diverged && canReseed {
+				return action
+			}
+		}
+	}
+	return ""
+}
+
+// hasCompleteBackup returns whether the shard has a complete backup to restore
+// from in any backup location.
+func hasCompleteBackup(vts *planetscalev2.VitessShard) bool {
+	if len(vts.Spec.BackupLocations) == 0 {
+		return false
+	}
+	for _, location := range vts.Status.BackupLocations {
+		if location.CompleteBackups > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// startReplication restarts replication on a replica, with semi-sync enabled
+// if the keyspace's durability policy says the replica should ack the primary.
+func startReplication(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, primary, replica *topo.TabletInfo) error {
+	if primary == nil {
+		return fmt.Errorf("primary tablet record not found")
+	}
+	_, durability, err := keyspaceDurability(ctx, vts, wr)
+	if err != nil {
+		return err
+	}
+	semiSync := reparentutil.IsReplicaSemiSync(durability, primary.Tablet, replica.Tablet)
+	return wr.TabletManagerClient().StartReplication(ctx, replica.Tablet, semiSync)
+}
+
+// reseedFromBackup deletes a tablet's data volume and Pod. The main VitessShard
+// controller then recreates both, and the new tablet restores from the latest
+// backup before it starts replicating.
+func (r *ReconcileVitessShard) reseedFromBackup(ctx context.Context, pod *corev1.Pod) error {
+	// The data volume has the same name as the Pod. Deleting it first means
+	// it goes away as soon as the Pod does, before the Pod can be recreated.
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: pod.Name}, pvc)
+	switch {
+	case err == nil:
+		if err := r.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PersistentVolumeClaim %v: %v", pvc.Name, err)
+		}
+	case !apierrors.IsNotFound(err):
+		return fmt.Errorf("failed to get PersistentVolumeClaim %v: %v", pod.Name, err)
+	}
+
+	if err := r.client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Pod %v: %v", pod.Name, err)
+	}
+	return nil
+}
+
+// setReplicationAnnotations records the outcome of a replication check on a
+// tablet Pod, if it changed. An empty status clears the annotations.
+func (r *ReconcileVitessShard) setReplicationAnnotations(ctx context.Context, pod *corev1.Pod, replicating corev1.ConditionStatus, problem string) error {
+	if pod.Annotations[vttablet.ReplicatingAnnotation] == string(replicating) && pod.Annotations[vttablet.ReplicationErrorAnnotation] == problem {
+		return nil
+	}
+
+	if replicating == "" {
+		delete(pod.Annotations, vttablet.ReplicatingAnnotation)
+	} else {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[vttablet.ReplicatingAnnotation] = string(replicating)
+	}
+	if problem == "" {
+		delete(pod.Annotations, vttablet.ReplicationErrorAnnotation)
+	} else {
+		pod.Annotations[vttablet.ReplicationErrorAnnotation] = problem
+	}
+
+	if err := r.client.Update(ctx, pod); err != nil && !apierrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/mysql/replication"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const purgedBinlogsError = "Got fatal error 1236 from master when reading data from binary log: 'The slave is connecting using CHANGE MASTER TO MASTER_AUTO_POSITION = 1, but the master has purged binary logs containing GTIDs that the slave requires.'"
+
+func TestReplicationProblem(t *testing.T) {
+	running := int32(replication.ReplicationStateRunning)
+	stopped := int32(replication.ReplicationStateStopped)
+	connecting := int32(replication.ReplicationStateConnecting)
+
+	tests := []struct {
+		name   string
+		status *replicationdatapb.Status
+		want   string
+	}{
+		{
+			name:   "healthy",
+			status: &replicationdatapb.Status{IoState: running, SqlState: running},
+			want:   "",
+		},
+		{
+			name:   "connecting without error",
+			status: &replicationdatapb.Status{IoState: connecting, SqlState: running},
+			want:   "",
+		},
+		{
+			name:   "connecting with error",
+			status: &replicationdatapb.Status{IoState: connecting, SqlState: running, LastIoError: "access denied"},
+			want:   "IO thread error: access denied",
+		},
+		{
+			name:   "stopped",
+			status: &replicationdatapb.Status{IoState: stopped, SqlState: stopped},
+			want:   "IO thread is not running; SQL thread is not running",
+		},
+		{
+			name:   "SQL error",
+			status: &replicationdatapb.Status{IoState: running, SqlState: stopped, LastSqlError: "duplicate key"},
+			want:   "SQL thread error: duplicate key",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, replicationProblem(test.status))
+		})
+	}
+}
+
+func TestRepairAction(t *testing.T) {
+	stopped := &replicationdatapb.Status{}
+	diverged := &replicationdatapb.Status{LastIoError: purgedBinlogsError}
+	both := []planetscalev2.VitessReplicationRepairAction{
+		planetscalev2.StartReplicationRepairAction,
+		planetscalev2.ReseedFromBackupRepairAction,
+	}
+
+	tests := []struct {
+		name      string
+		actions   []planetscalev2.VitessReplicationRepairAction
+		status    *replicationdatapb.Status
+		canReseed bool
+		want      planetscalev2.VitessReplicationRepairAction
+	}{
+		{
+			name:   "no actions",
+			status: stopped,
+			want:   "",
+		},
+		{
+			name:      "stopped restarts replication",
+			actions:   both,
+			status:    stopped,
+			canReseed: true,
+			want:      planetscalev2.StartReplicationRepairAction,
+		},
+		{
+			name:      "diverged reseeds",
+			actions:   both,
+			status:    diverged,
+			canReseed: true,
+			want:      planetscalev2.ReseedFromBackupRepairAction,
+		},
+		{
+			name:      "diverged without reseed allowed",
+			actions:   both,
+			status:    diverged,
+			canReseed: false,
+			want:      "",
+		},
+		{
+			name:      "stopped with only reseed configured",
+			actions:   both[1:],
+			status:    stopped,
+			canReseed: true,
+			want:      "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, repairAction(test.actions, test.status, test.canReseed))
+		})
+	}
+}
+
+func TestHasCompleteBackup(t *testing.T) {
+	vts := &planetscalev2.VitessShard{}
+	vts.Status.BackupLocations = []*planetscalev2.ShardBackupLocationStatus{{Name: "default", CompleteBackups: 1}}
+	assert.False(t, hasCompleteBackup(vts), "no backup locations configured")
+
+	vts.Spec.BackupLocations = []planetscalev2.VitessBackupLocation{{Name: "default"}}
+	assert.True(t, hasCompleteBackup(vts))
+
+	vts.Status.BackupLocations[0].CompleteBackups = 0
+	assert.False(t, hasCompleteBackup(vts), "no complete backups")
+}
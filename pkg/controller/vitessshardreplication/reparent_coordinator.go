@@ -0,0 +1,85 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// reparentCoordinator limits how many shards in each keyspace may be in the
+// middle of a reparent at the same time.
+//
+// Reparents run synchronously within a shard's reconcile pass, and only the
+// elected leader runs reconcile passes, so it's enough to track them in
+// memory. Shards in the same keyspace may be reconciled concurrently, though,
+// so all access must be synchronized.
+type reparentCoordinator struct {
+	mu       sync.Mutex
+	inFlight map[string]sets.Set[string]
+}
+
+func newReparentCoordinator() *reparentCoordinator {
+	return &reparentCoordinator{
+		inFlight: make(map[string]sets.Set[string]),
+	}
+}
+
+// acquire reserves a reparent slot for the shard in its keyspace. If the
+// keyspace's reparentConcurrency is already used up by other shards, it
+// returns an error listing them. Otherwise, the caller must call the returned
+// release func when the reparent is done.
+func (c *reparentCoordinator) acquire(vts *planetscalev2.VitessShard) (func(), error) {
+	key := keyspaceKey(vts)
+	shard := vts.Spec.Name
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shards := c.inFlight[key]
+	if shards == nil {
+		shards = sets.New[string]()
+		c.inFlight[key] = shards
+	}
+	if limit := vts.Spec.ReparentConcurrency; limit != nil && !shards.Has(shard) && shards.Len() >= int(*limit) {
+		others := shards.UnsortedList()
+		sort.Strings(others)
+		return nil, fmt.Errorf("keyspace reparentConcurrency of %d is in use by shards %v", *limit, strings.Join(others, ", "))
+	}
+	shards.Insert(shard)
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.inFlight[key].Delete(shard)
+		if c.inFlight[key].Len() == 0 {
+			delete(c.inFlight, key)
+		}
+	}, nil
+}
+
+// keyspaceKey identifies the keyspace a shard belongs to.
+func keyspaceKey(vts *planetscalev2.VitessShard) string {
+	return fmt.Sprintf("%v/%v/%v", vts.Namespace, vts.Labels[planetscalev2.ClusterLabel], vts.Labels[planetscalev2.KeyspaceLabel])
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestReparentCoordinator(t *testing.T) {
+	shard := func(keyspace, name string, concurrency *int32) *planetscalev2.VitessShard {
+		vts := &planetscalev2.VitessShard{}
+		vts.Namespace = "default"
+		vts.Labels = map[string]string{
+			planetscalev2.ClusterLabel:  "example",
+			planetscalev2.KeyspaceLabel: keyspace,
+		}
+		vts.Spec.Name = name
+		vts.Spec.ReparentConcurrency = concurrency
+		return vts
+	}
+	c := newReparentCoordinator()
+
+	// The first shard in the keyspace gets the only slot.
+	release1, err := c.acquire(shard("commerce", "-80", pointer.Int32(1)))
+	assert.NoError(t, err)
+
+	// Another shard in the same keyspace has to wait.
+	_, err = c.acquire(shard("commerce", "80-", pointer.Int32(1)))
+	assert.EqualError(t, err, "keyspace reparentConcurrency of 1 is in use by shards -80")
+
+	// Shards in other keyspaces, or without a limit, don't.
+	release2, err := c.acquire(shard("customer", "-80", pointer.Int32(1)))
+	assert.NoError(t, err)
+	release3, err := c.acquire(shard("commerce", "80-", nil))
+	assert.NoError(t, err)
+	release2()
+	release3()
+
+	// Releasing the slot lets the next shard through.
+	release1()
+	release4, err := c.acquire(shard("commerce", "80-", pointer.Int32(1)))
+	assert.NoError(t, err)
+	release4()
+	assert.Empty(t, c.inFlight)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+// recordReparent adds a reparent attempt to the shard's ReparentHistory and
+// writes the status.
+//
+// The main VitessShard controller owns the rest of the status, but it carries
+// over the reparent history.
+func (r *ReconcileVitessShard) recordReparent(ctx context.Context, vts *planetscalev2.VitessShard, reason planetscalev2.VitessShardReparentReason, oldPrimary, newPrimary string, reparentErr error) {
+	record := planetscalev2.VitessShardReparentRecord{
+		Time:       metav1.Now(),
+		OldPrimary: oldPrimary,
+		NewPrimary: newPrimary,
+		Reason:     reason,
+		Succeeded:  reparentErr == nil,
+	}
+	if reparentErr != nil {
+		record.Message = reparentErr.Error()
+	}
+	vts.Status.RecordReparent(record)
+
+	if err := r.client.Status().Update(ctx, vts); err != nil && !apierrors.IsConflict(err) {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to record reparent history: %v", err)
+	}
+}
+
+// drainReparentReason returns why a draining primary is being reparented,
+// based on the message its drain was requested with.
+func drainReparentReason(pod *corev1.Pod) planetscalev2.VitessShardReparentReason {
+	if pod != nil && pod.Annotations[drain.StartedAnnotation] == drain.RollingUpdateMessage {
+		return planetscalev2.UpdateReparentReason
+	}
+	return planetscalev2.DrainReparentReason
+}
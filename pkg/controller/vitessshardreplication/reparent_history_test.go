@@ -0,0 +1,39 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+func TestDrainReparentReason(t *testing.T) {
+	pod := func(message string) *corev1.Pod {
+		p := &corev1.Pod{}
+		drain.Start(p, message)
+		return p
+	}
+
+	assert.Equal(t, planetscalev2.UpdateReparentReason, drainReparentReason(pod(drain.RollingUpdateMessage)))
+	assert.Equal(t, planetscalev2.DrainReparentReason, drainReparentReason(pod("node is cordoned")))
+	assert.Equal(t, planetscalev2.DrainReparentReason, drainReparentReason(nil))
+}
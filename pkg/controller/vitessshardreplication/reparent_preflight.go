@@ -0,0 +1,207 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubectl/pkg/util/podutils"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+// replicaLagResult is the outcome of asking a replica for its replication status.
+type replicaLagResult struct {
+	aliasStr string
+	status   *replicationdatapb.Status
+	err      error
+}
+
+// checkCellReplicationLag checks that all replicas in the cell of the candidate
+// primary are within that cell's replication lag threshold, if it has one.
+// Delayed replicas are left out, since they lag on purpose.
+// It records the outcome in the shard's ReparentLagCheckPassed condition, and
+// returns an error describing the lagging replicas if the reparent should be
+// blocked.
+func (r *ReconcileVitessShard) checkCellReplicationLag(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo, pods map[string]*corev1.Pod, candidate *topo.TabletInfo) error {
+	cell := candidate.Alias.GetCell()
+	threshold, ok := vts.Spec.ReparentSettings.CellReplicationLagThresholds[cell]
+	// We don't manage replication for external datastores.
+	if !ok || vts.Spec.UsingExternalDatastore() {
+		return nil
+	}
+
+	// Ask every replica in the destination cell for its replication status.
+	replicas := make(map[string]*topo.TabletInfo, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		if tablet.Alias.GetCell() != cell || topoproto.TabletAliasEqual(tablet.Alias, shard.PrimaryAlias) {
+			continue
+		}
+		if tablet.Type != topodatapb.TabletType_REPLICA && tablet.Type != topodatapb.TabletType_RDONLY {
+			continue
+		}
+		if delayedTablet(vts, pods[tabletAliasStr]) {
+			continue
+		}
+		replicas[tabletAliasStr] = tablet
+	}
+	lagResults := replicationStatuses(ctx, wr, replicas)
+
+	problems := replicaLagProblems(lagResults, threshold.Duration)
+	var err error
+	if len(problems) > 0 {
+		err = fmt.Errorf("replicas in destination cell %v are not within the replication lag threshold of %v: %v", cell, threshold.Duration, strings.Join(problems, "; "))
+		r.setCondition(ctx, vts, planetscalev2.VitessShardReparentLagCheckPassed, corev1.ConditionFalse, "ReplicasLagging", err.Error())
+	} else {
+		r.setCondition(ctx, vts, planetscalev2.VitessShardReparentLagCheckPassed, corev1.ConditionTrue, "ReplicasCaughtUp",
+			fmt.Sprintf("replicas in destination cell %v are within the replication lag threshold of %v", cell, threshold.Duration))
+	}
+	return err
+}
+
+// checkSemiSyncAckers returns an error if the shard is in semiSync mode and
+// the candidate primary wouldn't have enough replicas to ack its writes once
+// it's promoted. Only Ready replicas that aren't draining count, since the
+// others may go away at any time.
+func checkSemiSyncAckers(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo, pods map[string]*corev1.Pod, candidate *topo.TabletInfo) error {
+	if vts.Spec.Replication.Mode != planetscalev2.SemiSyncReplicationMode || vts.Spec.UsingExternalDatastore() {
+		return nil
+	}
+
+	durabilityName, durability, err := keyspaceDurability(ctx, vts, wr)
+	if err != nil {
+		return err
+	}
+
+	required := reparentutil.SemiSyncAckers(durability, candidate.Tablet)
+	if required == 0 {
+		return nil
+	}
+	ackers := semiSyncAckers(durability, shard, tablets, pods, candidate)
+	if ackers < required {
+		return fmt.Errorf("candidate primary %v needs %d semi-sync acks under the %v durability policy, but only %d eligible replicas would remain", candidate.AliasString(), required, durabilityName, ackers)
+	}
+	return nil
+}
+
+// keyspaceDurability returns the name of the durability policy that's
+// actually in effect for the shard's keyspace, along with the policy itself.
+func keyspaceDurability(ctx context.Context, vts *planetscalev2.VitessShard, wr *wrangler.Wrangler) (string, reparentutil.Durabler, error) {
+	durabilityName, err := wr.TopoServer().GetKeyspaceDurability(ctx, vts.Labels[planetscalev2.KeyspaceLabel])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get keyspace durability policy: %v", err)
+	}
+	durability, err := reparentutil.GetDurabilityPolicy(durabilityName)
+	if err != nil {
+		return "", nil, err
+	}
+	return durabilityName, durability, nil
+}
+
+// replicationStatuses asks each of the given tablets for its replication
+// status in parallel.
+func replicationStatuses(ctx context.Context, wr *wrangler.Wrangler, tablets map[string]*topo.TabletInfo) []replicaLagResult {
+	rpcCtx, rpcCancel := context.WithTimeout(ctx, candidatePrimaryTimeout)
+	defer rpcCancel()
+
+	results := make(chan replicaLagResult, len(tablets))
+	for tabletAliasStr, tablet := range tablets {
+		go func(tabletAliasStr string, tablet *topo.TabletInfo) {
+			status, err := wr.TabletManagerClient().ReplicationStatus(rpcCtx, tablet.Tablet)
+			results <- replicaLagResult{aliasStr: tabletAliasStr, status: status, err: err}
+		}(tabletAliasStr, tablet)
+	}
+	statuses := make([]replicaLagResult, 0, len(tablets))
+	for range tablets {
+		statuses = append(statuses, <-results)
+	}
+	return statuses
+}
+
+// semiSyncAckers returns the number of tablets that could ack semi-sync writes
+// for the candidate primary.
+func semiSyncAckers(durability reparentutil.Durabler, shard *topo.ShardInfo, tablets map[string]*topo.TabletInfo, pods map[string]*corev1.Pod, candidate *topo.TabletInfo) int {
+	ackers := 0
+	for tabletAliasStr, tablet := range tablets {
+		if topoproto.TabletAliasEqual(tablet.Alias, candidate.Alias) || topoproto.TabletAliasEqual(tablet.Alias, shard.PrimaryAlias) {
+			continue
+		}
+		pod := pods[tabletAliasStr]
+		if pod == nil || !podutils.IsPodReady(pod) {
+			continue
+		}
+		if drain.Started(pod) || drain.Acknowledged(pod) || drain.Finished(pod) {
+			continue
+		}
+		// This checks the tablet type as well as the cell.
+		if reparentutil.IsReplicaSemiSync(durability, candidate.Tablet, tablet.Tablet) {
+			ackers++
+		}
+	}
+	return ackers
+}
+
+// replicaLagProblems returns a description of each replica that is lagging
+// more than threshold, or whose lag is unknown, sorted by tablet alias.
+func replicaLagProblems(results []replicaLagResult, threshold time.Duration) []string {
+	var problems []string
+	for _, result := range results {
+		switch {
+		case result.err != nil:
+			problems = append(problems, fmt.Sprintf("%v: failed to get replication status: %v", result.aliasStr, result.err))
+		case result.status.GetReplicationLagUnknown():
+			problems = append(problems, fmt.Sprintf("%v: replication lag is unknown", result.aliasStr))
+		default:
+			lag := time.Duration(result.status.GetReplicationLagSeconds()) * time.Second
+			if lag > threshold {
+				problems = append(problems, fmt.Sprintf("%v: replication lag is %v", result.aliasStr, lag))
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// setCondition sets a condition on the shard's status, and writes the status
+// if the condition changed.
+//
+// The main VitessShard controller owns the rest of the status, but it carries
+// over conditions set by other controllers.
+func (r *ReconcileVitessShard) setCondition(ctx context.Context, vts *planetscalev2.VitessShard, condType planetscalev2.VitessShardConditionType, status corev1.ConditionStatus, reason, message string) {
+	oldCondition, hadCondition := vts.Status.Conditions[condType]
+	vts.Status.SetConditionStatus(condType, status, reason, message)
+	if hadCondition && apiequality.Semantic.DeepEqual(oldCondition, vts.Status.Conditions[condType]) {
+		return
+	}
+	if err := r.client.Status().Update(ctx, vts); err != nil && !apierrors.IsConflict(err) {
+		r.recorder.Eventf(vts, corev1.EventTypeWarning, "StatusUpdateFailed", "failed to update %v condition: %v", condType, err)
+	}
+}
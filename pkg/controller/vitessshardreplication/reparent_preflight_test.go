@@ -0,0 +1,91 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+func TestReplicaLagProblems(t *testing.T) {
+	results := []replicaLagResult{
+		{aliasStr: "zone1-4", status: &replicationdatapb.Status{ReplicationLagSeconds: 30}},
+		{aliasStr: "zone1-1", status: &replicationdatapb.Status{ReplicationLagSeconds: 5}},
+		{aliasStr: "zone1-2", status: &replicationdatapb.Status{ReplicationLagUnknown: true}},
+		{aliasStr: "zone1-3", err: errors.New("timed out")},
+		{aliasStr: "zone1-5", status: &replicationdatapb.Status{ReplicationLagSeconds: 10}},
+	}
+
+	assert.Equal(t, []string{
+		"zone1-2: replication lag is unknown",
+		"zone1-3: failed to get replication status: timed out",
+		"zone1-4: replication lag is 30s",
+	}, replicaLagProblems(results, 10*time.Second))
+
+	assert.Empty(t, replicaLagProblems(results[1:2], 10*time.Second))
+}
+
+func TestSemiSyncAckers(t *testing.T) {
+	readyPod := func() *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}}}
+	}
+	drainingPod := readyPod()
+	drain.Start(drainingPod, "test")
+	tablet := func(cell string, uid uint32, tabletType topodatapb.TabletType) *topo.TabletInfo {
+		return &topo.TabletInfo{Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: cell, Uid: uid}, Type: tabletType}}
+	}
+
+	shard := &topo.ShardInfo{}
+	shard.Shard = &topodatapb.Shard{PrimaryAlias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 1}}
+	candidate := tablet("zone1", 2, topodatapb.TabletType_REPLICA)
+	tablets := map[string]*topo.TabletInfo{
+		"zone1-1": tablet("zone1", 1, topodatapb.TabletType_PRIMARY),
+		"zone1-2": candidate,
+		"zone1-3": tablet("zone1", 3, topodatapb.TabletType_REPLICA),
+		"zone1-4": tablet("zone1", 4, topodatapb.TabletType_RDONLY),
+		"zone1-5": tablet("zone1", 5, topodatapb.TabletType_REPLICA),
+		"zone2-1": tablet("zone2", 1, topodatapb.TabletType_REPLICA),
+		"zone2-2": tablet("zone2", 2, topodatapb.TabletType_REPLICA),
+	}
+	pods := map[string]*corev1.Pod{
+		"zone1-1": readyPod(),
+		"zone1-2": readyPod(),
+		"zone1-3": readyPod(),
+		"zone1-4": readyPod(),
+		"zone1-5": drainingPod,
+		"zone2-1": readyPod(),
+		"zone2-2": &corev1.Pod{},
+	}
+
+	semiSync, err := reparentutil.GetDurabilityPolicy("semi_sync")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, semiSyncAckers(semiSync, shard, tablets, pods, candidate))
+
+	crossCell, err := reparentutil.GetDurabilityPolicy("cross_cell")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, semiSyncAckers(crossCell, shard, tablets, pods, candidate))
+}
@@ -88,6 +88,7 @@ func newReconciler(mgr manager.Manager) *ReconcileVitessShard {
 		resync:     resync.NewPeriodic(controllerName, *resyncPeriod),
 		recorder:   recorder,
 		reconciler: reconciler.New(c, scheme, recorder),
+		reparents:  newReparentCoordinator(),
 	}
 }
 
@@ -139,6 +140,7 @@ type ReconcileVitessShard struct {
 	resync     *resync.Periodic
 	recorder   record.EventRecorder
 	reconciler *reconciler.Reconciler
+	reparents  *reparentCoordinator
 }
 
 // Reconcile reads that state of the cluster for a VitessShard object and makes changes based on the state read
@@ -208,10 +210,66 @@ func (r *ReconcileVitessShard) Reconcile(cctx context.Context, request reconcile
 	initReplicationResult, err := r.initReplication(ctx, vts, wr)
 	resultBuilder.Merge(initReplicationResult, err)
 
+	// Follow failovers of externally managed MySQL that are signaled outside of Vitess.
+	externalFailoverResult, err := r.reconcileExternalFailover(ctx, vts, wr)
+	resultBuilder.Merge(externalFailoverResult, err)
+
 	// Check if we've been asked to do a planned reparent.
 	drainResult, err := r.reconcileDrain(ctx, vts, wr, log)
 	resultBuilder.Merge(drainResult, err)
 
+	// Check if it's time to rotate the primary on a schedule.
+	rotationResult, err := r.reconcilePrimaryRotation(ctx, vts, wr)
+	resultBuilder.Merge(rotationResult, err)
+
+	// Check if the primary should be moved back to where its affinity says.
+	affinityResult, err := r.reconcilePrimaryAffinity(ctx, vts, wr)
+	resultBuilder.Merge(affinityResult, err)
+
+	// Make sure delayed replicas are delayed by the right amount.
+	delayResult, err := r.reconcileReplicationDelay(ctx, vts, wr)
+	resultBuilder.Merge(delayResult, err)
+
+	// Check for replicas with errant GTIDs.
+	errantResult, err := r.reconcileErrantGTIDs(ctx, vts, wr)
+	resultBuilder.Merge(errantResult, err)
+
+	// Check for MySQL global variables that drifted from the mysqld config.
+	driftResult, err := r.reconcileMysqldConfigDrift(ctx, vts, wr)
+	resultBuilder.Merge(driftResult, err)
+
+	// Create the monitoring user for the MySQL exporters, if they use one.
+	exporterUserResult, err := r.reconcileMysqldExporterUser(ctx, vts, wr)
+	resultBuilder.Merge(exporterUserResult, err)
+
+	// Create, update, and drop the MySQL users listed in the tablet pools.
+	mysqlUsersResult, err := r.reconcileMysqlUsers(ctx, vts, wr)
+	resultBuilder.Merge(mysqlUsersResult, err)
+
+	// Record the progress of tablets that are restoring from backup.
+	restoreResult, err := r.reconcileRestoreProgress(ctx, vts, wr)
+	resultBuilder.Merge(restoreResult, err)
+
+	// Check for replicas with broken replication, and try to repair them.
+	repairResult, err := r.repairReplication(ctx, vts, wr)
+	resultBuilder.Merge(repairResult, err)
+
+	// Reseed tablets whose MySQL can't recover on its own, if configured.
+	reseedResult, err := r.reconcileAutoReseed(ctx, vts, wr)
+	resultBuilder.Merge(reseedResult, err)
+
+	// Replace data volumes that need to grow but can't be expanded in place.
+	replaceResult, err := r.reconcileDataVolumeReplace(ctx, vts, wr)
+	resultBuilder.Merge(replaceResult, err)
+
+	// Refresh standby tablets from the latest backup, if it's time.
+	standbyResult, err := r.reconcileStandbyRefresh(ctx, vts, wr)
+	resultBuilder.Merge(standbyResult, err)
+
+	// Check whether it's safe to roll out new tablet images, if configured.
+	upgradeChecksResult, err := r.reconcileUpgradeChecks(ctx, vts, wr)
+	resultBuilder.Merge(upgradeChecksResult, err)
+
 	// Request a periodic resync for the shard so we can recheck replication
 	// even if no Kubernetes events have occurred.
 	r.resync.Enqueue(request.NamespacedName)
@@ -228,12 +286,15 @@ func (r *ReconcileVitessShard) initReplication(ctx context.Context, vts *planets
 	if !*vts.Spec.Replication.InitializeMaster {
 		return resultBuilder.Result()
 	}
-
 	// Check if we need to initialize the shard.
 	// If it's already initialized, this will be a no-op.
 	// If we are using external MySQL we will bail out early.
-	ismResult, err := r.initShardPrimary(ctx, vts, wr)
-	resultBuilder.Merge(ismResult, err)
+	// Choosing the first primary of an empty shard is a reparent too, so we
+	// leave it to the external tooling if reparents are managed externally.
+	if !vts.Spec.Replication.ExternalReparents {
+		ismResult, err := r.initShardPrimary(ctx, vts, wr)
+		resultBuilder.Merge(ismResult, err)
+	}
 
 	// Check if we need to externally reparent
 	// in the case of external MySQL.
@@ -0,0 +1,44 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessshardreplication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestInitReplicationExternalReparents(t *testing.T) {
+	r := &ReconcileVitessShard{recorder: record.NewFakeRecorder(100)}
+	vts := &planetscalev2.VitessShard{}
+	vts.Spec.Replication.InitializeMaster = pointer.Bool(true)
+	vts.Spec.Replication.ExternalReparents = true
+	vts.Spec.TabletPools = []planetscalev2.VitessShardTabletPool{{BackupLocationName: "default"}}
+	// The restored shard's topo status isn't known yet, so initRestoredShard
+	// asks to be requeued rather than giving up.
+	vts.Status.HasMaster = corev1.ConditionUnknown
+
+	result, err := r.initReplication(context.Background(), vts, nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.RequeueAfter, "restored shard should still be initialized")
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package backupreplicator copies a Vitess backup from one storage location to
+another.
+
+Vitess backup storage is configured with process-wide flags, so a single
+process can only talk to one location. The copy is therefore split in two: an
+init container downloads the backup from the source location into a scratch
+volume, and then the main container uploads it to the destination location.
+Both run as a forked code path of the operator binary in a throwaway Pod. The
+outcome is reported through the exit status and termination message of the
+Pod's containers.
+
+See cmd/manager/main.go for details.
+*/
+package backupreplicator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	_ "vitess.io/vitess/go/vt/mysqlctl/azblobbackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/cephbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/gcsbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/s3backupstorage"
+
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+const (
+	// ForkPath is the fork path for copying a backup.
+	// See cmd/manager/main.go for details.
+	ForkPath = "backup-replicator"
+
+	ModeEnvVar       = "PS_OPERATOR_REPLICATE_MODE"
+	BackupDirEnvVar  = "PS_OPERATOR_REPLICATE_BACKUP_DIR"
+	BackupNameEnvVar = "PS_OPERATOR_REPLICATE_BACKUP_NAME"
+	ScratchDirEnvVar = "PS_OPERATOR_REPLICATE_SCRATCH_DIR"
+
+	// ModeDownload copies the backup from storage into the scratch dir.
+	ModeDownload = "download"
+	// ModeUpload copies the backup from the scratch dir into storage.
+	ModeUpload = "upload"
+
+	// manifestFileName is the name of the file that describes a backup.
+	// Vitess writes it last, so a backup without it is incomplete.
+	manifestFileName = "MANIFEST"
+
+	// These are the names of the Vitess backup engines whose files we know
+	// how to find.
+	builtinBackupEngineName    = "builtin"
+	xtrabackupBackupEngineName = "xtrabackup"
+)
+
+var log = logrus.WithField("component", "backup-replicator")
+
+// Run copies the backup described by the environment to or from the scratch
+// dir, and writes the outcome to the termination message. It returns an error
+// if the copy failed.
+func Run(ctx context.Context) error {
+	message, err := replicate(ctx)
+	if err != nil {
+		message = err.Error()
+	}
+	if writeErr := k8s.WriteTerminationMessage(message); writeErr != nil {
+		log.Warningf("Can't write termination message: %v", writeErr)
+	}
+	return err
+}
+
+func replicate(ctx context.Context) (string, error) {
+	mode := os.Getenv(ModeEnvVar)
+	dir := os.Getenv(BackupDirEnvVar)
+	name := os.Getenv(BackupNameEnvVar)
+	scratchDir := os.Getenv(ScratchDirEnvVar)
+	if dir == "" || name == "" || scratchDir == "" {
+		return "", fmt.Errorf("backup replicator requires %v, %v and %v env vars to be set", BackupDirEnvVar, BackupNameEnvVar, ScratchDirEnvVar)
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return "", fmt.Errorf("can't get backup storage: %v", err)
+	}
+	defer bs.Close()
+
+	switch mode {
+	case ModeDownload:
+		return download(ctx, bs, dir, name, scratchDir)
+	case ModeUpload:
+		return upload(ctx, bs, dir, name, scratchDir)
+	}
+	return "", fmt.Errorf("invalid %v: %q", ModeEnvVar, mode)
+}
+
+// download copies every file of a backup from storage into the scratch dir.
+// The MANIFEST is copied last, so its presence means the download finished.
+func download(ctx context.Context, bs backupstorage.BackupStorage, dir, name, scratchDir string) (string, error) {
+	bh, err := findBackup(ctx, bs, dir, name)
+	if err != nil {
+		return "", err
+	}
+	if bh == nil {
+		return "", fmt.Errorf("backup %v/%v not found in source location", dir, name)
+	}
+
+	manifest, err := readFile(ctx, bh, manifestFileName)
+	if err != nil {
+		return "", err
+	}
+	files, err := backupFiles(manifest)
+	if err != nil {
+		return "", fmt.Errorf("can't list files of backup %v/%v: %v", dir, name, err)
+	}
+
+	// Start from an empty scratch dir, in case the volume was used before.
+	if err := os.RemoveAll(scratchDir); err != nil {
+		return "", fmt.Errorf("can't clean up scratch dir: %v", err)
+	}
+	if err := os.MkdirAll(scratchDir, 0755); err != nil {
+		return "", fmt.Errorf("can't create scratch dir: %v", err)
+	}
+	for _, file := range files {
+		log.Infof("Downloading file %v of backup %v/%v", file, dir, name)
+		if err := downloadFile(ctx, bh, file, filepath.Join(scratchDir, file)); err != nil {
+			return "", err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(scratchDir, manifestFileName), manifest, 0644); err != nil {
+		return "", fmt.Errorf("can't write %v: %v", manifestFileName, err)
+	}
+	return fmt.Sprintf("Downloaded backup %v/%v with %d files.", dir, name, len(files)), nil
+}
+
+// upload copies every file of a backup from the scratch dir into storage.
+// The MANIFEST is copied last, so the backup only appears complete once every
+// other file is in place. If the backup is already complete in storage, we
+// leave it alone, so retrying a copy that succeeded is harmless.
+func upload(ctx context.Context, bs backupstorage.BackupStorage, dir, name, scratchDir string) (string, error) {
+	existing, err := findBackup(ctx, bs, dir, name)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		if _, err := readFile(ctx, existing, manifestFileName); err == nil {
+			return fmt.Sprintf("Backup %v/%v already exists in destination location.", dir, name), nil
+		}
+		// Clean up what's left of a previous attempt that didn't finish.
+		log.Infof("Removing incomplete backup %v/%v from destination location", dir, name)
+		if err := bs.RemoveBackup(ctx, dir, name); err != nil {
+			return "", fmt.Errorf("can't remove incomplete backup %v/%v: %v", dir, name, err)
+		}
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(scratchDir, manifestFileName))
+	if err != nil {
+		return "", fmt.Errorf("can't read downloaded %v: %v", manifestFileName, err)
+	}
+	files, err := backupFiles(manifest)
+	if err != nil {
+		return "", fmt.Errorf("can't list files of backup %v/%v: %v", dir, name, err)
+	}
+
+	bh, err := bs.StartBackup(ctx, dir, name)
+	if err != nil {
+		return "", fmt.Errorf("can't start backup %v/%v: %v", dir, name, err)
+	}
+	for _, file := range append(files, manifestFileName) {
+		log.Infof("Uploading file %v of backup %v/%v", file, dir, name)
+		if err := uploadFile(ctx, bh, filepath.Join(scratchDir, file), file); err != nil {
+			if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+				log.Warningf("Can't abort backup %v/%v: %v", dir, name, abortErr)
+			}
+			return "", err
+		}
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return "", fmt.Errorf("can't finish backup %v/%v: %v", dir, name, err)
+	}
+	return fmt.Sprintf("Uploaded backup %v/%v with %d files.", dir, name, len(files)), nil
+}
+
+// findBackup returns the handle for the named backup in a directory of backup
+// storage, or nil if there is no such backup.
+func findBackup(ctx context.Context, bs backupstorage.BackupStorage, dir, name string) (backupstorage.BackupHandle, error) {
+	backups, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't list backups in %v: %v", dir, err)
+	}
+	for _, bh := range backups {
+		if bh.Name() == name {
+			return bh, nil
+		}
+	}
+	return nil, nil
+}
+
+func readFile(ctx context.Context, bh backupstorage.BackupHandle, name string) ([]byte, error) {
+	reader, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("can't open %v: %v", name, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't read %v: %v", name, err)
+	}
+	return data, nil
+}
+
+func downloadFile(ctx context.Context, bh backupstorage.BackupHandle, name, path string) error {
+	reader, err := bh.ReadFile(ctx, name)
+	if err != nil {
+		return fmt.Errorf("can't open %v: %v", name, err)
+	}
+	defer reader.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("can't create %v: %v", path, err)
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return fmt.Errorf("can't download %v: %v", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("can't write %v: %v", path, err)
+	}
+	return nil
+}
+
+func uploadFile(ctx context.Context, bh backupstorage.BackupHandle, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("can't open %v: %v", path, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("can't stat %v: %v", path, err)
+	}
+
+	writer, err := bh.AddFile(ctx, name, info.Size())
+	if err != nil {
+		return fmt.Errorf("can't add %v: %v", name, err)
+	}
+	if _, err := io.Copy(writer, file); err != nil {
+		writer.Close()
+		return fmt.Errorf("can't upload %v: %v", name, err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("can't upload %v: %v", name, err)
+	}
+	return nil
+}
+
+// backupFiles returns the names of the files that make up a backup, other
+// than the MANIFEST, according to the MANIFEST.
+func backupFiles(manifest []byte) ([]string, error) {
+	var m struct {
+		BackupMethod string
+		// FileEntries is set by the builtin engine.
+		FileEntries []json.RawMessage
+		// FileName and NumStripes are set by the xtrabackup engine.
+		FileName   string
+		NumStripes int32
+	}
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return nil, fmt.Errorf("can't parse %v: %v", manifestFileName, err)
+	}
+
+	switch m.BackupMethod {
+	case "", builtinBackupEngineName:
+		// The builtin engine names each file after its index in FileEntries.
+		files := make([]string, 0, len(m.FileEntries))
+		for i := range m.FileEntries {
+			files = append(files, strconv.Itoa(i))
+		}
+		return files, nil
+	case xtrabackupBackupEngineName:
+		if m.FileName == "" {
+			return nil, fmt.Errorf("%v doesn't record the backup file name", manifestFileName)
+		}
+		if m.NumStripes == 0 {
+			return []string{m.FileName}, nil
+		}
+		files := make([]string, 0, m.NumStripes)
+		for i := 0; i < int(m.NumStripes); i++ {
+			files = append(files, fmt.Sprintf("%s-%03d", m.FileName, i))
+		}
+		return files, nil
+	}
+	return nil, fmt.Errorf("copying backups taken with the %q engine is not supported", m.BackupMethod)
+}
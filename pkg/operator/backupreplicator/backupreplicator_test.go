@@ -0,0 +1,84 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupreplicator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackupFiles(t *testing.T) {
+	table := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "builtin",
+			manifest: `{"BackupMethod": "builtin", "FileEntries": [{"Base": "Data", "Name": "ibdata1"}, {"Base": "Data", "Name": "mysql/user.ibd"}]}`,
+			want:     []string{"0", "1"},
+		},
+		{
+			name:     "builtin without method",
+			manifest: `{"FileEntries": [{"Base": "Data", "Name": "ibdata1"}]}`,
+			want:     []string{"0"},
+		},
+		{
+			name:     "xtrabackup",
+			manifest: `{"BackupMethod": "xtrabackup", "FileName": "backup.xbstream.gz"}`,
+			want:     []string{"backup.xbstream.gz"},
+		},
+		{
+			name:     "xtrabackup with stripes",
+			manifest: `{"BackupMethod": "xtrabackup", "FileName": "backup.xbstream.gz", "NumStripes": 2}`,
+			want:     []string{"backup.xbstream.gz-000", "backup.xbstream.gz-001"},
+		},
+		{
+			name:     "xtrabackup without file name",
+			manifest: `{"BackupMethod": "xtrabackup"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported engine",
+			manifest: `{"BackupMethod": "mysqlshell"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid manifest",
+			manifest: `{`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range table {
+		got, err := backupFiles([]byte(tc.manifest))
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%v: backupFiles() = %v; expected error", tc.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: backupFiles() error: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: backupFiles() = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
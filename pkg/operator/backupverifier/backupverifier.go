@@ -0,0 +1,205 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package backupverifier checks that a Vitess backup can actually be restored.
+
+It restores a backup into a scratch data directory, starts mysqld on the result,
+and optionally runs CHECKSUM TABLE on some tables. It runs as a forked code path
+of the operator binary, which is copied into a throwaway Pod that uses the
+mysqld image, since restoring needs mysqld. The outcome is reported through the
+exit status and termination message of the Pod's container.
+
+See cmd/manager/main.go for details.
+*/
+package backupverifier
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	_ "vitess.io/vitess/go/vt/mysqlctl/azblobbackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	_ "vitess.io/vitess/go/vt/mysqlctl/cephbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/gcsbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/s3backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+const (
+	// ForkPath is the fork path for verifying a backup.
+	// See cmd/manager/main.go for details.
+	ForkPath = "backup-verifier"
+
+	KeyspaceEnvVar       = "PS_OPERATOR_VERIFY_KEYSPACE"
+	ShardEnvVar          = "PS_OPERATOR_VERIFY_SHARD"
+	DatabaseNameEnvVar   = "PS_OPERATOR_VERIFY_DATABASE_NAME"
+	MysqlSocketEnvVar    = "PS_OPERATOR_VERIFY_MYSQL_SOCKET"
+	BackupTimeEnvVar     = "PS_OPERATOR_VERIFY_BACKUP_TIME"
+	ChecksumTablesEnvVar = "PS_OPERATOR_VERIFY_CHECKSUM_TABLES"
+
+	// BinaryPath is where the operator binary is installed in the operator image.
+	BinaryPath = "/usr/local/bin/vitess-operator"
+
+	// tabletUID is the UID of the imaginary tablet whose data dir we restore
+	// into. It doesn't matter, since the data dir is thrown away afterwards.
+	tabletUID = 1
+	// mysqlPort is the port for the restored mysqld. It doesn't matter either,
+	// since nothing else in the Pod listens on it.
+	mysqlPort = 3306
+)
+
+var (
+	restoreConcurrency = flag.Int("backup_verifier_restore_concurrency", 4, "how many files the backup verifier restores at once")
+	mysqlTimeout       = flag.Duration("backup_verifier_mysql_timeout", 5*time.Minute, "how long the backup verifier waits for mysqld to start up or shut down")
+)
+
+var log = logrus.WithField("component", "backup-verifier")
+
+// Run verifies the backup described by the environment, and writes the outcome
+// to the termination message. It returns an error if verification failed.
+func Run(ctx context.Context) error {
+	message, err := verify(ctx)
+	if err != nil {
+		message = err.Error()
+	}
+	if writeErr := k8s.WriteTerminationMessage(message); writeErr != nil {
+		log.Warningf("Can't write termination message: %v", writeErr)
+	}
+	return err
+}
+
+func verify(ctx context.Context) (string, error) {
+	keyspace := os.Getenv(KeyspaceEnvVar)
+	shard := os.Getenv(ShardEnvVar)
+	dbName := os.Getenv(DatabaseNameEnvVar)
+	if keyspace == "" || shard == "" || dbName == "" {
+		return "", fmt.Errorf("backup verifier requires %v, %v and %v env vars to be set", KeyspaceEnvVar, ShardEnvVar, DatabaseNameEnvVar)
+	}
+	backupTime, err := time.Parse(time.RFC3339, os.Getenv(BackupTimeEnvVar))
+	if err != nil {
+		return "", fmt.Errorf("invalid %v: %v", BackupTimeEnvVar, err)
+	}
+	var tables []string
+	for _, table := range strings.Split(os.Getenv(ChecksumTablesEnvVar), ",") {
+		if table != "" {
+			tables = append(tables, table)
+		}
+	}
+
+	// Start up mysqld on an empty data dir, as vtbackup does. Restoring will
+	// replace the data dir and restart mysqld.
+	collationEnv := collations.NewEnvironment(servenv.MySQLServerVersion())
+	mysqld, mycnf, err := mysqlctl.CreateMysqldAndMycnf(tabletUID, os.Getenv(MysqlSocketEnvVar), mysqlPort, collationEnv)
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize mysql config: %v", err)
+	}
+	initCtx, initCancel := context.WithTimeout(ctx, *mysqlTimeout)
+	defer initCancel()
+	if err := mysqld.Init(initCtx, mycnf, ""); err != nil {
+		return "", fmt.Errorf("failed to initialize mysql data dir and start mysqld: %v", err)
+	}
+	defer func() {
+		// Don't use the original context, since we still want to shut down
+		// mysqld cleanly if we timed out.
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), *mysqlTimeout+10*time.Second)
+		defer shutdownCancel()
+		if err := mysqld.Shutdown(shutdownCtx, mycnf, false, *mysqlTimeout); err != nil {
+			log.Errorf("Failed to shut down mysqld: %v", err)
+		}
+	}()
+
+	// Restore the latest backup taken at or before the one we were asked to
+	// verify, which should be that backup.
+	manifest, err := mysqlctl.Restore(ctx, mysqlctl.RestoreParams{
+		Cnf:                  mycnf,
+		Mysqld:               mysqld,
+		Logger:               logutil.NewConsoleLogger(),
+		Concurrency:          *restoreConcurrency,
+		DeleteBeforeRestore:  true,
+		DbName:               dbName,
+		Keyspace:             keyspace,
+		Shard:                shard,
+		StartTime:            backupTime,
+		Stats:                backupstats.RestoreStats(),
+		MysqlShutdownTimeout: *mysqlTimeout,
+	})
+	if errors.Is(err, mysqlctl.ErrNoBackup) {
+		return "", fmt.Errorf("no backup found for %v/%v", keyspace, shard)
+	}
+	if err != nil {
+		return "", fmt.Errorf("can't restore from backup: %v", err)
+	}
+	message := fmt.Sprintf("Restored backup %v at position %v.", manifest.BackupTime, manifest.Position)
+
+	if len(tables) == 0 {
+		return message, nil
+	}
+	qr, err := mysqld.FetchSuperQuery(ctx, checksumQuery(dbName, tables))
+	if err != nil {
+		return "", fmt.Errorf("%v CHECKSUM TABLE failed: %v", message, err)
+	}
+	checksums, err := checksumResults(qr)
+	if err != nil {
+		return "", fmt.Errorf("%v %v", message, err)
+	}
+	return fmt.Sprintf("%v Checksums: %v", message, checksums), nil
+}
+
+// checksumQuery returns a CHECKSUM TABLE statement for the given tables in
+// the given database.
+func checksumQuery(dbName string, tables []string) string {
+	names := make([]string, 0, len(tables))
+	for _, table := range tables {
+		names = append(names, sqlescape.EscapeID(dbName)+"."+sqlescape.EscapeID(table))
+	}
+	return "CHECKSUM TABLE " + strings.Join(names, ", ")
+}
+
+// checksumResults summarizes the result of CHECKSUM TABLE. It returns an error
+// if any of the tables doesn't exist, for which MySQL returns a NULL checksum.
+func checksumResults(qr *sqltypes.Result) (string, error) {
+	var checksums, missing []string
+	for _, row := range qr.Rows {
+		if len(row) < 2 {
+			continue
+		}
+		if row[1].IsNull() {
+			missing = append(missing, row[0].ToString())
+			continue
+		}
+		checksums = append(checksums, fmt.Sprintf("%v=%v", row[0].ToString(), row[1].ToString()))
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("tables not found in restored backup: %v", strings.Join(missing, ", "))
+	}
+	return strings.Join(checksums, ", "), nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupverifier
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+func TestChecksumQuery(t *testing.T) {
+	got := checksumQuery("vt_commerce", []string{"customer", "order"})
+	want := "CHECKSUM TABLE `vt_commerce`.`customer`, `vt_commerce`.`order`"
+	if got != want {
+		t.Errorf("checksumQuery() = %q; want %q", got, want)
+	}
+}
+
+func TestChecksumResults(t *testing.T) {
+	fields := sqltypes.MakeTestFields("Table|Checksum", "varchar|int64")
+
+	qr := sqltypes.MakeTestResult(fields, "vt_commerce.customer|123", "vt_commerce.order|456")
+	got, err := checksumResults(qr)
+	if err != nil {
+		t.Fatalf("checksumResults() error: %v", err)
+	}
+	if want := "vt_commerce.customer=123, vt_commerce.order=456"; got != want {
+		t.Errorf("checksumResults() = %q; want %q", got, want)
+	}
+
+	qr = sqltypes.MakeTestResult(fields, "vt_commerce.customer|123", "vt_commerce.missing|null")
+	if _, err := checksumResults(qr); err == nil {
+		t.Errorf("checksumResults() with missing table: expected error")
+	}
+}
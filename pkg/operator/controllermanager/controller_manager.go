@@ -12,6 +12,8 @@ import (
 
 	"planetscale.dev/vitess-operator/pkg/controller"
 	vbssubcontroller "planetscale.dev/vitess-operator/pkg/controller/vitessbackupstorage/subcontroller"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+	"planetscale.dev/vitess-operator/pkg/operator/evictionwebhook"
 )
 
 var log = logf.Log.WithName("controller-manager")
@@ -41,6 +43,17 @@ func New(forkPath string, cfg *rest.Config, opts manager.Options) (manager.Manag
 		if err := controller.AddToManager(mgr); err != nil {
 			return nil, err
 		}
+		// The eviction webhook is opt-in, since it needs extra setup in the
+		// cluster (certificates and a ValidatingWebhookConfiguration).
+		if environment.EvictionWebhookEnabled() {
+			var namespaces []string
+			for ns := range opts.Cache.DefaultNamespaces {
+				namespaces = append(namespaces, ns)
+			}
+			if err := evictionwebhook.Add(mgr, namespaces); err != nil {
+				return nil, err
+			}
+		}
 	case vbssubcontroller.ForkPath:
 		// Run only the vitessbackupstorage subcontroller.
 		if err := vbssubcontroller.Add(mgr); err != nil {
@@ -79,6 +79,10 @@ const (
 	// unfinished, for example if some unplanned disruption caused the object
 	// to be reassigned its formerly-drained duties to avoid downtime.
 	FinishedAnnotation = AnnotationPrefix + "/" + "finished"
+
+	// timestampLayout is the format of the timestamps we write as annotation
+	// values, which matches what time.Time.String() returns.
+	timestampLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
 )
 
 // Supported returns whether the object's controller supports drains.
@@ -118,6 +122,10 @@ func Finished(obj metav1.Object) bool {
 	return present
 }
 
+// RollingUpdateMessage is the message that drains started for rolling updates
+// are requested with.
+const RollingUpdateMessage = "rolling update"
+
 /*
 Start annotates an object to request a drain.
 
@@ -165,6 +173,39 @@ func Acknowledge(obj metav1.Object) {
 	obj.SetAnnotations(ann)
 }
 
+/*
+AcknowledgedSince returns the time at which the drain of an object was
+acknowledged by the controller.
+
+It returns false if the drain hasn't been acknowledged, or if the value of the
+annotation isn't a timestamp written by Acknowledge.
+*/
+func AcknowledgedSince(obj metav1.Object) (time.Time, bool) {
+	value, present := obj.GetAnnotations()[AcknowledgedAnnotation]
+	if !present {
+		return time.Time{}, false
+	}
+	since, err := time.Parse(timestampLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+/*
+Stuck returns whether the drain of an object has been acknowledged for longer
+than timeout at the given time, without being marked as finished.
+
+A timeout of 0 means a drain is never considered stuck.
+*/
+func Stuck(obj metav1.Object, timeout time.Duration, now time.Time) bool {
+	if timeout <= 0 || Finished(obj) {
+		return false
+	}
+	since, ok := AcknowledgedSince(obj)
+	return ok && now.Sub(since) > timeout
+}
+
 /*
 Unacknowledge removes the "acknowledged" annotation.
 
@@ -258,6 +299,7 @@ The return value returns a map populated with only the elements that should
 change to a new State, and is keyed by the object identifiers you passed
 in.
 
+This is equivalent to calling StateTransitionsWithLimit with a limit of 1.
 See below for why this function exists, and a proof of correctness.
 
 # GOALS:
@@ -313,11 +355,56 @@ Step 2 is a contradiction to the fact that t1 is already marked as "finished"
 because t1="draining" has not yet been observed.
 */
 func StateTransitions(drainStates map[string]State) map[string]State {
+	return StateTransitionsWithLimit(drainStates, 1)
+}
+
+/*
+StateTransitionsWithLimit is like StateTransitions, except that it allows up
+to maxFinished objects to be marked as "DrainingFinished" at the same time.
+
+The algorithm is the same as the one described for StateTransitions, except
+that instead of refusing to mark anything as "DrainingFinished" whenever any
+object is already "DrainingFinished", we count the objects that are already
+"DrainingFinished" and only mark as many of the first (sorted) objects in the
+"DrainingAcknowledged" state as it takes to reach the limit.
+
+The proof of correctness carries over because the choice of which objects to
+mark is still deterministic for a given observed state. Marking a different
+set of objects requires observing a new object in the "DrainingAcknowledged"
+state, which in turn requires a previous pass that observed that object as
+"Draining" (and therefore marked nothing as "DrainingFinished"). Any pass that
+observes our acknowledgement of that object will also observe every object we
+marked as "DrainingFinished" before that, so the count of finished objects we
+base our decisions on can never be stale.
+
+A maxFinished value less than 1 is treated as 1.
+*/
+func StateTransitionsWithLimit(drainStates map[string]State, maxFinished int) map[string]State {
+	return StateTransitionsWithOrder(drainStates, nil, maxFinished)
+}
+
+/*
+StateTransitionsWithOrder is like StateTransitionsWithLimit, except that the
+objects in the "DrainingAcknowledged" state are considered for being marked as
+"DrainingFinished" in ascending order of the values in the order map, and only
+then by name. Objects missing from the order map are treated as having order 0.
+
+The proof of correctness for StateTransitions relies on the choice of which
+objects to mark being deterministic for a given observed state. To keep that
+property, the order map must be computed from information that rarely
+changes, such as configuration, rather than from rapidly changing state.
+*/
+func StateTransitionsWithOrder(drainStates map[string]State, order map[string]int, maxFinished int) map[string]State {
+	if maxFinished < 1 {
+		maxFinished = 1
+	}
+
 	transitions := map[string]State{}
 
 	// First do the initial scan, acknowledging drains and detecting cases where
 	// it is unsafe to mark anything as finished.
 	canMarkFinished := true
+	finished := 0
 	for name, state := range drainStates {
 		switch state {
 		case NotDrainingState:
@@ -328,28 +415,36 @@ func StateTransitions(drainStates map[string]State) map[string]State {
 		case AcknowledgedState:
 			continue
 		case FinishedState:
-			canMarkFinished = false
+			finished++
 		default:
 			panic("Invalid state, should not be possible.")
 		}
 	}
 
-	if !canMarkFinished {
+	if !canMarkFinished || finished >= maxFinished {
 		return transitions
 	}
 
-	// Now iterate in sorted order, and mark the first element in the
-	// "DrainingAcknowledged" state as "DrainingFinished".
+	// Now iterate in sorted order, and mark the first elements in the
+	// "DrainingAcknowledged" state as "DrainingFinished" until we reach the limit.
 	var names []string
 	for name := range drainStates {
 		names = append(names, name)
 	}
-	sort.Strings(names)
+	sort.Slice(names, func(i, j int) bool {
+		if order[names[i]] != order[names[j]] {
+			return order[names[i]] < order[names[j]]
+		}
+		return names[i] < names[j]
+	})
 
 	for _, name := range names {
+		if finished >= maxFinished {
+			break
+		}
 		if drainStates[name] == AcknowledgedState {
 			transitions[name] = FinishedState
-			return transitions
+			finished++
 		}
 	}
 	return transitions
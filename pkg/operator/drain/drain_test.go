@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -27,15 +28,15 @@ import (
 )
 
 // checkInvariants will check to see if we broke our one invariant, that at most
-// one element is ever marked as "Finished".
-func checkInvariants(drainStates map[string]State) error {
-	foundFinished := false
+// maxFinished elements are ever marked as "Finished".
+func checkInvariants(drainStates map[string]State, maxFinished int) error {
+	foundFinished := 0
 	for name, state := range drainStates {
 		if state == FinishedState {
-			if foundFinished {
+			if foundFinished >= maxFinished {
 				return fmt.Errorf("Found another element marked as Finished: %s", name)
 			}
-			foundFinished = true
+			foundFinished++
 		}
 	}
 	return nil
@@ -116,11 +117,64 @@ func applyRandomDrains(realDrainStates map[string]State, cachedDrainStates map[s
 }
 
 func TestStateTransitions(t *testing.T) {
+	testStateTransitions(t, 1, StateTransitions)
+}
+
+func TestStateTransitionsWithLimit(t *testing.T) {
+	for _, maxFinished := range []int{1, 2, 3, 5} {
+		t.Run(fmt.Sprintf("maxFinished=%d", maxFinished), func(t *testing.T) {
+			testStateTransitions(t, maxFinished, func(drainStates map[string]State) map[string]State {
+				return StateTransitionsWithLimit(drainStates, maxFinished)
+			})
+		})
+	}
+}
+
+func TestStateTransitionsWithLimitMarksUpToLimit(t *testing.T) {
+	drainStates := map[string]State{
+		"a": AcknowledgedState,
+		"b": AcknowledgedState,
+		"c": AcknowledgedState,
+		"d": NotDrainingState,
+	}
+	assert.Equal(t, map[string]State{"a": FinishedState, "b": FinishedState}, StateTransitionsWithLimit(drainStates, 2))
+
+	drainStates["a"] = FinishedState
+	assert.Equal(t, map[string]State{"b": FinishedState}, StateTransitionsWithLimit(drainStates, 2))
+
+	drainStates["b"] = FinishedState
+	assert.Empty(t, StateTransitionsWithLimit(drainStates, 2))
+
+	drainStates["d"] = DrainingState
+	assert.Equal(t, map[string]State{"d": AcknowledgedState}, StateTransitionsWithLimit(drainStates, 5))
+}
+
+func TestStateTransitionsWithOrder(t *testing.T) {
+	// Randomized tests with a fixed order.
+	randomOrder := map[string]int{"0": 5, "3": 2, "7": -1, "9": 2}
+	testStateTransitions(t, 1, func(drainStates map[string]State) map[string]State {
+		return StateTransitionsWithOrder(drainStates, randomOrder, 1)
+	})
+
+	order := map[string]int{"a": 2, "b": 1, "c": 1}
+
+	drainStates := map[string]State{
+		"a": AcknowledgedState,
+		"b": AcknowledgedState,
+		"c": AcknowledgedState,
+		"d": AcknowledgedState,
+	}
+	// Lower order goes first, then names break ties. Unlisted names are 0.
+	assert.Equal(t, map[string]State{"d": FinishedState}, StateTransitionsWithOrder(drainStates, order, 1))
+	assert.Equal(t, map[string]State{"d": FinishedState, "b": FinishedState, "c": FinishedState}, StateTransitionsWithOrder(drainStates, order, 3))
+}
+
+func testStateTransitions(t *testing.T, maxFinished int, stateTransitions func(map[string]State) map[string]State) {
 	for i := 0; i <= 10000; i++ {
 
 		// 1. Run state transition on initial random valid set of states.
 		initial := generateRandomDrainStates()
-		trans1 := StateTransitions(initial)
+		trans1 := stateTransitions(initial)
 
 		// 2. Sanity check that we aren't touching anything that isn't draining.
 		err := checkNoSpontaneousDrains(initial, trans1)
@@ -133,7 +187,7 @@ func TestStateTransitions(t *testing.T) {
 		cached := applyTransitions(initial, trans1, false)
 
 		// 4. Check that we haven't broken any invariants on our "real" state.
-		err = checkInvariants(current)
+		err = checkInvariants(current, maxFinished)
 		if err != nil {
 			t.Errorf("initial: %v+, current: %v+, trans1: %v+, Error: %v",
 				initial, current, trans1, err)
@@ -143,7 +197,7 @@ func TestStateTransitions(t *testing.T) {
 		current, cached = applyRandomDrains(current, cached)
 
 		// 6. Run another iteration based on "cached" state.
-		trans2 := StateTransitions(cached)
+		trans2 := stateTransitions(cached)
 
 		// 7. Sanity check that we aren't touching anything that isn't draining.
 		err = checkNoSpontaneousDrains(cached, trans2)
@@ -155,7 +209,7 @@ func TestStateTransitions(t *testing.T) {
 		current = applyTransitions(current, trans2, true)
 
 		// 9. Check whether the final "real" state broke our invariants.
-		err = checkInvariants(current)
+		err = checkInvariants(current, maxFinished)
 		if err != nil {
 			t.Errorf(
 				"initial: %v+, current: %v+, cached: %v+, trans1: %v+, trans2: %v+, err: %v",
@@ -203,3 +257,33 @@ func TestGetDrainState(t *testing.T) {
 	_, err = GetState(&badState)
 	assert.Error(t, err, "Should have failed because this is an invalid state")
 }
+
+func TestAcknowledgedSince(t *testing.T) {
+	pod := corev1.Pod{}
+	_, ok := AcknowledgedSince(&pod)
+	assert.False(t, ok, "Should not have an acknowledged time before Acknowledge")
+
+	before := time.Now()
+	Acknowledge(&pod)
+	since, ok := AcknowledgedSince(&pod)
+	assert.True(t, ok, "Should have an acknowledged time after Acknowledge")
+	assert.WithinDuration(t, before, since, time.Minute)
+
+	pod.Annotations[AcknowledgedAnnotation] = "not a timestamp"
+	_, ok = AcknowledgedSince(&pod)
+	assert.False(t, ok, "Should not parse an invalid timestamp")
+}
+
+func TestStuck(t *testing.T) {
+	now := time.Now()
+	pod := corev1.Pod{}
+	pod.Annotations = map[string]string{
+		AcknowledgedAnnotation: now.Add(-2 * time.Hour).UTC().String(),
+	}
+	assert.True(t, Stuck(&pod, time.Hour, now))
+	assert.False(t, Stuck(&pod, 3*time.Hour, now))
+	assert.False(t, Stuck(&pod, 0, now), "A timeout of 0 should disable the check")
+
+	Finish(&pod)
+	assert.False(t, Stuck(&pod, time.Hour, now), "A finished drain should never be stuck")
+}
@@ -33,6 +33,7 @@ import (
 
 var (
 	reconcileTimeout   time.Duration
+	evictionWebhook    bool
 	mySQLServerVersion = "8.0.30-Vitess"
 	// truncateUILen truncate queries in debug UIs to the given length. 0 means unlimited.
 	truncateUILen = 512
@@ -45,6 +46,7 @@ func FlagSet() *pflag.FlagSet {
 	operatorFlagSet := pflag.NewFlagSet("operator", pflag.ExitOnError)
 
 	operatorFlagSet.DurationVar(&reconcileTimeout, "reconcile_timeout", 10*time.Minute, "Maximum time that any controller will spend trying to reconcile a single object before giving up.")
+	operatorFlagSet.BoolVar(&evictionWebhook, "eviction_webhook", false, "Serve an admission webhook that turns Pod evictions into drain requests. Requires a ValidatingWebhookConfiguration and serving certificates to be installed.")
 
 	operatorFlagSet.StringVar(&planetscalev2.DefaultVitessPriorityClass, "default_vitess_priority_class", planetscalev2.DefaultVitessPriorityClass, "Default PriorityClass to use for Pods that run Vitess components. An empty value means don't use any PriorityClass.")
 	operatorFlagSet.StringVar(&planetscalev2.DefaultVitessServiceAccount, "default_vitess_service_account", planetscalev2.DefaultVitessServiceAccount, "Default ServiceAccount to use for Pods that run Vitess components. An empty value means let Kubernetes fill in a default.")
@@ -66,6 +68,11 @@ func ReconcileTimeout() time.Duration {
 	return reconcileTimeout
 }
 
+// EvictionWebhookEnabled returns whether the eviction admission webhook should be served.
+func EvictionWebhookEnabled() bool {
+	return evictionWebhook
+}
+
 // CollationEnvAndParser gets the collation environment and parser to be used in the operator.
 func CollationEnvAndParser() (*collations.Environment, *sqlparser.Parser, error) {
 	collationEnv := collations.NewEnvironment(mySQLServerVersion)
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/backupverifier"
+	"planetscale.dev/vitess-operator/pkg/operator/etcdbackup"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
+)
+
+const (
+	// SnapshotLabel is the label that identifies which lockserver cluster a
+	// snapshot Pod belongs to. Snapshot Pods don't get LockserverLabel, since
+	// they aren't members.
+	SnapshotLabel = "etcd.planetscale.com/snapshot"
+
+	// RestoreAnnotation is set on the data volume PVC of a member that was
+	// created to be seeded from a snapshot. Its value is the snapshot.
+	RestoreAnnotation = "etcd.planetscale.com/restore-snapshot"
+	// SnapshotNameAnnotation is set on a snapshot Pod to record the name of
+	// the snapshot it takes.
+	SnapshotNameAnnotation = "etcd.planetscale.com/snapshot-name"
+	// SnapshotScheduleTimeAnnotation is set on a snapshot Pod to record the
+	// scheduled time of the snapshot it takes, in RFC 3339 format.
+	SnapshotScheduleTimeAnnotation = "etcd.planetscale.com/snapshot-schedule-time"
+
+	snapshotContainerName = "etcd-snapshot"
+	restoreContainerName  = "etcd-restore"
+	initRestoreBinName    = "init-etcd-restore"
+
+	// The operator binary is copied into the etcd container for restores,
+	// since the restore needs etcdutl from the etcd image.
+	restoreBinVolumeName = "etcd-restore-bin"
+	restoreBinMountPath  = "/mnt/etcd-restore-bin"
+	restoreCommand       = restoreBinMountPath + "/vitess-operator"
+	restoreBinInitScript = `set -ex
+cp --no-clobber ` + backupverifier.BinaryPath + ` ` + restoreBinMountPath + `/
+`
+)
+
+// RestoreSpec specifies a snapshot to seed a new etcd member from.
+type RestoreSpec struct {
+	Location      *planetscalev2.VitessBackupLocation
+	ClusterName   string
+	Directory     string
+	Name          string
+	OperatorImage string
+}
+
+// SnapshotSpec specifies all the internal parameters needed to take a
+// snapshot of an etcd cluster.
+type SnapshotSpec struct {
+	LockserverName  string
+	ClusterName     string
+	Location        *planetscalev2.VitessBackupLocation
+	SnapshotsToKeep int32
+	ScheduleTime    time.Time
+	OperatorImage   string
+	Labels          map[string]string
+}
+
+// SnapshotPodName returns the name of the Pod that takes the snapshot of an
+// etcd cluster scheduled at a given time.
+func SnapshotPodName(lockserverName string, scheduleTime time.Time) string {
+	return fmt.Sprintf("%s-snapshot-%d", lockserverName, scheduleTime.Unix())
+}
+
+// RestoreAnnotationValue returns the value of RestoreAnnotation for a snapshot.
+func RestoreAnnotationValue(directory, name string) string {
+	return directory + "/" + name
+}
+
+// NewSnapshotPod creates a Pod that takes one snapshot of an etcd cluster.
+// The snapshot tool is part of the operator binary, so the Pod runs the
+// operator image and talks to etcd as a client.
+func NewSnapshotPod(key client.ObjectKey, spec *SnapshotSpec) *corev1.Pod {
+	name := etcdbackup.SnapshotName(spec.ScheduleTime)
+
+	env := fork.EnvVars(etcdbackup.SnapshotForkPath)
+	env = append(env,
+		corev1.EnvVar{Name: etcdbackup.EndpointEnvVar, Value: fmt.Sprintf("%s:%d", ClientServiceName(spec.LockserverName), ClientPortNumber)},
+		corev1.EnvVar{Name: etcdbackup.SnapshotDirEnvVar, Value: etcdbackup.SnapshotDir(spec.LockserverName)},
+		corev1.EnvVar{Name: etcdbackup.SnapshotNameEnvVar, Value: name},
+		corev1.EnvVar{Name: etcdbackup.SnapshotsToKeepEnvVar, Value: strconv.Itoa(int(spec.SnapshotsToKeep))},
+	)
+	update.Env(&env, vitessbackup.StorageEnvVars(spec.Location))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    spec.Labels,
+			Annotations: map[string]string{
+				SnapshotNameAnnotation:         name,
+				SnapshotScheduleTimeAnnotation: spec.ScheduleTime.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: corev1.PodSpec{
+			// The outcome is reported through the Pod phase, so don't retry.
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:         snapshotContainerName,
+					Image:        spec.OperatorImage,
+					Command:      []string{backupverifier.BinaryPath},
+					Args:         vitessbackup.StorageFlags(spec.Location, spec.ClusterName).FormatArgs(),
+					Env:          env,
+					VolumeMounts: vitessbackup.StorageVolumeMounts(spec.Location),
+				},
+			},
+			Volumes: vitessbackup.StorageVolumes(spec.Location),
+		},
+	}
+	if planetscalev2.DefaultVitessServiceAccount != "" {
+		pod.Spec.ServiceAccountName = planetscalev2.DefaultVitessServiceAccount
+	}
+	return pod
+}
+
+// restoreInitContainers returns the init containers that seed the data dir of
+// a new member from a snapshot. They do nothing if the member already has data,
+// so they're harmless when the Pod is recreated later.
+func restoreInitContainers(spec *Spec, securityContext *corev1.SecurityContext) []corev1.Container {
+	restore := spec.Restore
+	memberName, initialCluster, initialClusterToken, initialAdvertisePeerURLs := spec.bootstrapParams()
+
+	env := fork.EnvVars(etcdbackup.RestoreForkPath)
+	env = append(env,
+		corev1.EnvVar{Name: etcdbackup.SnapshotDirEnvVar, Value: restore.Directory},
+		corev1.EnvVar{Name: etcdbackup.SnapshotNameEnvVar, Value: restore.Name},
+		corev1.EnvVar{Name: etcdbackup.DataDirEnvVar, Value: dataVolumeMountPath},
+		corev1.EnvVar{Name: etcdbackup.MemberNameEnvVar, Value: memberName},
+		corev1.EnvVar{Name: etcdbackup.InitialClusterEnvVar, Value: initialCluster},
+		corev1.EnvVar{Name: etcdbackup.InitialClusterTokenEnvVar, Value: initialClusterToken},
+		corev1.EnvVar{Name: etcdbackup.InitialAdvertisePeerURLsEnvVar, Value: initialAdvertisePeerURLs},
+	)
+	update.Env(&env, vitessbackup.StorageEnvVars(restore.Location))
+
+	binVolumeMount := corev1.VolumeMount{
+		Name:      restoreBinVolumeName,
+		MountPath: restoreBinMountPath,
+	}
+	volumeMounts := []corev1.VolumeMount{
+		binVolumeMount,
+		{
+			Name:      dataVolumeName,
+			MountPath: dataVolumeMountPath,
+			SubPath:   dataVolumeSubPath,
+		},
+	}
+	volumeMounts = append(volumeMounts, vitessbackup.StorageVolumeMounts(restore.Location)...)
+
+	return []corev1.Container{
+		{
+			Name:            initRestoreBinName,
+			Image:           restore.OperatorImage,
+			Command:         []string{"bash", "-c"},
+			Args:            []string{restoreBinInitScript},
+			SecurityContext: securityContext,
+			VolumeMounts:    []corev1.VolumeMount{binVolumeMount},
+		},
+		{
+			Name:            restoreContainerName,
+			Image:           spec.Image,
+			ImagePullPolicy: spec.ImagePullPolicy,
+			Command:         []string{restoreCommand},
+			Args:            vitessbackup.StorageFlags(restore.Location, restore.ClusterName).FormatArgs(),
+			SecurityContext: securityContext,
+			Env:             env,
+			VolumeMounts:    volumeMounts,
+		},
+	}
+}
+
+// restoreVolumes returns the Volumes needed by restoreInitContainers.
+func restoreVolumes(spec *Spec) []corev1.Volume {
+	volumes := []corev1.Volume{
+		{
+			Name: restoreBinVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	return append(volumes, vitessbackup.StorageVolumes(spec.Restore.Location)...)
+}
@@ -87,6 +87,8 @@ type Spec struct {
 	ExtraLabels       map[string]string
 	AdvertisePeerURLs []string
 	Tolerations       []corev1.Toleration
+	// Restore is set if the member should be seeded from a snapshot.
+	Restore *RestoreSpec
 }
 
 // NewPod creates a new etcd Pod.
@@ -225,6 +227,9 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 		},
 	})
 	update.Volumes(&obj.Spec.Volumes, spec.ExtraVolumes)
+	if spec.Restore != nil {
+		update.Volumes(&obj.Spec.Volumes, restoreVolumes(spec))
+	}
 
 	obj.Spec.Hostname = PodName(spec.LockserverName, spec.Index)
 	obj.Spec.Subdomain = PeerServiceName(spec.LockserverName)
@@ -308,7 +313,12 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	}
 
 	// Make a final list of desired containers and init containers before merging.
-	initContainers := spec.InitContainers
+	var initContainers []corev1.Container
+	if spec.Restore != nil {
+		// Restore before anything else touches the data dir.
+		initContainers = append(initContainers, restoreInitContainers(spec, securityContext)...)
+	}
+	initContainers = append(initContainers, spec.InitContainers...)
 	containers := []corev1.Container{
 		*etcdContainer,
 	}
@@ -335,7 +345,7 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	})
 
 	// Inject init containers from spec.
-	update.PodContainers(&obj.Spec.InitContainers, spec.InitContainers)
+	update.PodContainers(&obj.Spec.InitContainers, initContainers)
 
 	// Update sidecar containers we care about in the Pod template,
 	// ignoring other containers that may have been injected.
@@ -348,38 +358,13 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 
 // Args returns the etcd args.
 func (spec *Spec) Args() []string {
-	hostname := PodName(spec.LockserverName, spec.Index)
+	hostname, initialCluster, initialClusterToken, initialAdvertisePeerURLs := spec.bootstrapParams()
 	subdomain := PeerServiceName(spec.LockserverName)
 
 	listenPeerURLs := fmt.Sprintf("http://0.0.0.0:%d", PeerPortNumber)
 	listenClientURLs := fmt.Sprintf("http://0.0.0.0:%d", ClientPortNumber)
 	advertiseClientURLs := fmt.Sprintf("http://%s.%s:%d", hostname, subdomain, ClientPortNumber)
 
-	// Use static bootstrapping.
-	initialClusterToken := spec.LockserverName
-	advertisePeerURLs := spec.AdvertisePeerURLs
-
-	// If peer URLs were not explicitly specified, generate them.
-	if len(advertisePeerURLs) != NumReplicas {
-		advertisePeerURLs = make([]string, 0, NumReplicas)
-		for i := 0; i < NumReplicas; i++ {
-			peerIndex := i + 1
-			peerName := PodName(spec.LockserverName, peerIndex)
-			advertisePeerURLs = append(advertisePeerURLs, fmt.Sprintf("http://%s.%s:%d", peerName, subdomain, PeerPortNumber))
-		}
-	}
-
-	// Set the address that this peer will advertise for itself.
-	initialAdvertisePeerURLs := advertisePeerURLs[spec.Index-1]
-
-	// Create list of peer addresses.
-	initialCluster := make([]string, 0, NumReplicas)
-	for i := 0; i < NumReplicas; i++ {
-		peerIndex := i + 1
-		peerName := PodName(spec.LockserverName, peerIndex)
-		initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", peerName, advertisePeerURLs[i]))
-	}
-
 	flags := vitess.Flags{
 		"data-dir":              dataVolumeMountPath,
 		"name":                  hostname,
@@ -391,7 +376,7 @@ func (spec *Spec) Args() []string {
 		"initial-cluster-state":       "new",
 		"initial-cluster-token":       initialClusterToken,
 		"initial-advertise-peer-urls": initialAdvertisePeerURLs,
-		"initial-cluster":             strings.Join(initialCluster, ","),
+		"initial-cluster":             initialCluster,
 	}
 
 	// Apply user-supplied extra flags last so they take precedence.
@@ -404,3 +389,39 @@ func (spec *Spec) Args() []string {
 
 	return flags.FormatArgs()
 }
+
+// bootstrapParams returns the member name and the "initial-*" settings etcd
+// uses to bootstrap this member. Restoring a snapshot into the member must use
+// the same ones, so it comes up as part of the same cluster.
+func (spec *Spec) bootstrapParams() (name, initialCluster, initialClusterToken, initialAdvertisePeerURLs string) {
+	name = PodName(spec.LockserverName, spec.Index)
+	subdomain := PeerServiceName(spec.LockserverName)
+
+	// Use static bootstrapping.
+	initialClusterToken = spec.LockserverName
+	advertisePeerURLs := spec.AdvertisePeerURLs
+
+	// If peer URLs were not explicitly specified, generate them.
+	if len(advertisePeerURLs) != NumReplicas {
+		advertisePeerURLs = make([]string, 0, NumReplicas)
+		for i := 0; i < NumReplicas; i++ {
+			peerIndex := i + 1
+			peerName := PodName(spec.LockserverName, peerIndex)
+			advertisePeerURLs = append(advertisePeerURLs, fmt.Sprintf("http://%s.%s:%d", peerName, subdomain, PeerPortNumber))
+		}
+	}
+
+	// Set the address that this peer will advertise for itself.
+	initialAdvertisePeerURLs = advertisePeerURLs[spec.Index-1]
+
+	// Create list of peer addresses.
+	peers := make([]string, 0, NumReplicas)
+	for i := 0; i < NumReplicas; i++ {
+		peerIndex := i + 1
+		peerName := PodName(spec.LockserverName, peerIndex)
+		peers = append(peers, fmt.Sprintf("%s=%s", peerName, advertisePeerURLs[i]))
+	}
+	initialCluster = strings.Join(peers, ",")
+
+	return name, initialCluster, initialClusterToken, initialAdvertisePeerURLs
+}
@@ -31,7 +31,7 @@ func NewPVC(key client.ObjectKey, spec *Spec) *corev1.PersistentVolumeClaim {
 	update.Labels(&labels, spec.Labels)
 	update.Labels(&labels, spec.ExtraLabels)
 
-	return &corev1.PersistentVolumeClaim{
+	obj := &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: key.Namespace,
 			Name:      key.Name,
@@ -39,6 +39,14 @@ func NewPVC(key client.ObjectKey, spec *Spec) *corev1.PersistentVolumeClaim {
 		},
 		Spec: *spec.DataVolumePVCSpec,
 	}
+	// Remember that this volume is meant to be seeded from a snapshot, so
+	// the member keeps trying if its Pod is recreated before it's done.
+	if spec.Restore != nil {
+		obj.Annotations = map[string]string{
+			RestoreAnnotation: RestoreAnnotationValue(spec.Restore.Directory, spec.Restore.Name),
+		}
+	}
+	return obj
 }
 
 // UpdatePVCInPlace updates an existing PVC in-place.
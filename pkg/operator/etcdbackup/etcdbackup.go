@@ -0,0 +1,321 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package etcdbackup takes snapshots of an EtcdLockserver and restores them.
+
+Both run as forked code paths of the operator binary, and store snapshots with
+the same backup storage plugins Vitess uses, so any Vitess backup location can
+hold them. A snapshot is taken in a throwaway Pod that uses the operator image
+and connects to etcd as a client. A restore runs in an init container of each
+new etcd member, which uses the etcd image with the operator binary copied in,
+since turning a snapshot into a data dir needs etcdutl. The outcome is reported
+through the exit status and termination message of the container.
+
+See cmd/manager/main.go for details.
+*/
+package etcdbackup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	_ "vitess.io/vitess/go/vt/mysqlctl/azblobbackupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/cephbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/filebackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/gcsbackupstorage"
+	_ "vitess.io/vitess/go/vt/mysqlctl/s3backupstorage"
+
+	"planetscale.dev/vitess-operator/pkg/operator/k8s"
+)
+
+const (
+	// SnapshotForkPath is the fork path for taking a snapshot.
+	// See cmd/manager/main.go for details.
+	SnapshotForkPath = "etcd-snapshot"
+	// RestoreForkPath is the fork path for restoring a snapshot.
+	// See cmd/manager/main.go for details.
+	RestoreForkPath = "etcd-restore"
+
+	EndpointEnvVar        = "PS_OPERATOR_ETCD_ENDPOINT"
+	SnapshotDirEnvVar     = "PS_OPERATOR_ETCD_SNAPSHOT_DIR"
+	SnapshotNameEnvVar    = "PS_OPERATOR_ETCD_SNAPSHOT_NAME"
+	SnapshotsToKeepEnvVar = "PS_OPERATOR_ETCD_SNAPSHOTS_TO_KEEP"
+
+	DataDirEnvVar                  = "PS_OPERATOR_ETCD_DATA_DIR"
+	MemberNameEnvVar               = "PS_OPERATOR_ETCD_MEMBER_NAME"
+	InitialClusterEnvVar           = "PS_OPERATOR_ETCD_INITIAL_CLUSTER"
+	InitialClusterTokenEnvVar      = "PS_OPERATOR_ETCD_INITIAL_CLUSTER_TOKEN"
+	InitialAdvertisePeerURLsEnvVar = "PS_OPERATOR_ETCD_INITIAL_ADVERTISE_PEER_URLS"
+
+	// snapshotFileName is the name of the file that holds the snapshot
+	// within a backup.
+	snapshotFileName = "snapshot.db"
+	// snapshotNameTimeFormat is the layout of snapshot names, which makes
+	// them sort by the time they were scheduled.
+	snapshotNameTimeFormat = "2006-01-02.150405"
+
+	// etcdutlPath is where etcdutl is installed in the etcd image.
+	etcdutlPath = "/usr/local/bin/etcdutl"
+	// memberDirName is the subdirectory of the etcd data dir that holds the
+	// data of a member. If it exists, the member has already bootstrapped.
+	memberDirName = "member"
+	// restoreDirName is the subdirectory of the etcd data dir that we use as
+	// scratch space while restoring.
+	restoreDirName = "restore"
+
+	dialTimeout = 10 * time.Second
+)
+
+var log = logrus.WithField("component", "etcd-backup")
+
+// SnapshotDir returns the directory in backup storage that holds the
+// snapshots of a given EtcdLockserver.
+func SnapshotDir(lockserverName string) string {
+	return "etcd-snapshots/" + lockserverName
+}
+
+// SnapshotName returns the name of the snapshot scheduled at a given time.
+func SnapshotName(scheduledTime time.Time) string {
+	return scheduledTime.UTC().Format(snapshotNameTimeFormat)
+}
+
+// RunSnapshot takes the snapshot described by the environment, uploads it,
+// and deletes snapshots beyond the number to keep. It writes the outcome to
+// the termination message, and returns an error if the snapshot failed.
+func RunSnapshot(ctx context.Context) error {
+	return run(ctx, snapshot)
+}
+
+// RunRestore restores the snapshot described by the environment into the
+// etcd data dir, unless the data dir already has a member in it. It writes
+// the outcome to the termination message, and returns an error if the
+// restore failed.
+func RunRestore(ctx context.Context) error {
+	return run(ctx, restore)
+}
+
+func run(ctx context.Context, do func(ctx context.Context, bs backupstorage.BackupStorage) (string, error)) error {
+	message, err := withBackupStorage(ctx, do)
+	if err != nil {
+		message = err.Error()
+	}
+	if writeErr := k8s.WriteTerminationMessage(message); writeErr != nil {
+		log.Warningf("Can't write termination message: %v", writeErr)
+	}
+	return err
+}
+
+func withBackupStorage(ctx context.Context, do func(ctx context.Context, bs backupstorage.BackupStorage) (string, error)) (string, error) {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return "", fmt.Errorf("can't get backup storage: %v", err)
+	}
+	defer bs.Close()
+	return do(ctx, bs)
+}
+
+func snapshot(ctx context.Context, bs backupstorage.BackupStorage) (string, error) {
+	endpoint := os.Getenv(EndpointEnvVar)
+	dir := os.Getenv(SnapshotDirEnvVar)
+	name := os.Getenv(SnapshotNameEnvVar)
+	if endpoint == "" || dir == "" || name == "" {
+		return "", fmt.Errorf("etcd snapshot requires %v, %v and %v env vars to be set", EndpointEnvVar, SnapshotDirEnvVar, SnapshotNameEnvVar)
+	}
+	keep, err := strconv.Atoi(os.Getenv(SnapshotsToKeepEnvVar))
+	if err != nil || keep < 1 {
+		return "", fmt.Errorf("invalid %v: %q", SnapshotsToKeepEnvVar, os.Getenv(SnapshotsToKeepEnvVar))
+	}
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't connect to etcd at %v: %v", endpoint, err)
+	}
+	defer cli.Close()
+
+	log.Infof("Taking snapshot %v/%v of etcd at %v", dir, name, endpoint)
+	rc, err := cli.Snapshot(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't start snapshot: %v", err)
+	}
+	defer rc.Close()
+
+	bh, err := bs.StartBackup(ctx, dir, name)
+	if err != nil {
+		return "", fmt.Errorf("can't start backup %v/%v: %v", dir, name, err)
+	}
+	size, err := uploadFile(ctx, bh, rc)
+	if err != nil {
+		if abortErr := bh.AbortBackup(ctx); abortErr != nil {
+			log.Warningf("Can't abort backup %v/%v: %v", dir, name, abortErr)
+		}
+		return "", err
+	}
+	if err := bh.EndBackup(ctx); err != nil {
+		return "", fmt.Errorf("can't finish backup %v/%v: %v", dir, name, err)
+	}
+
+	// Only prune once the new snapshot is safely stored.
+	removed, err := pruneSnapshots(ctx, bs, dir, keep)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Took snapshot %v/%v of %d bytes and removed %d old snapshots.", dir, name, size, removed), nil
+}
+
+func uploadFile(ctx context.Context, bh backupstorage.BackupHandle, src io.Reader) (int64, error) {
+	dst, err := bh.AddFile(ctx, snapshotFileName, 0)
+	if err != nil {
+		return 0, fmt.Errorf("can't add %v to backup: %v", snapshotFileName, err)
+	}
+	size, err := io.Copy(dst, src)
+	if err != nil {
+		dst.Close()
+		return 0, fmt.Errorf("can't upload %v: %v", snapshotFileName, err)
+	}
+	if err := dst.Close(); err != nil {
+		return 0, fmt.Errorf("can't upload %v: %v", snapshotFileName, err)
+	}
+	return size, nil
+}
+
+// pruneSnapshots removes the oldest snapshots in a directory until only the
+// given number are left. It returns how many it removed.
+func pruneSnapshots(ctx context.Context, bs backupstorage.BackupStorage, dir string, keep int) (int, error) {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return 0, fmt.Errorf("can't list snapshots in %v: %v", dir, err)
+	}
+	names := make([]string, 0, len(bhs))
+	for _, bh := range bhs {
+		names = append(names, bh.Name())
+	}
+	sort.Strings(names)
+
+	removed := 0
+	for len(names)-removed > keep {
+		name := names[removed]
+		log.Infof("Removing old snapshot %v/%v", dir, name)
+		if err := bs.RemoveBackup(ctx, dir, name); err != nil {
+			return removed, fmt.Errorf("can't remove old snapshot %v/%v: %v", dir, name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func restore(ctx context.Context, bs backupstorage.BackupStorage) (string, error) {
+	dir := os.Getenv(SnapshotDirEnvVar)
+	name := os.Getenv(SnapshotNameEnvVar)
+	dataDir := os.Getenv(DataDirEnvVar)
+	if dir == "" || name == "" || dataDir == "" {
+		return "", fmt.Errorf("etcd restore requires %v, %v and %v env vars to be set", SnapshotDirEnvVar, SnapshotNameEnvVar, DataDirEnvVar)
+	}
+
+	memberDir := filepath.Join(dataDir, memberDirName)
+	if _, err := os.Stat(memberDir); err == nil {
+		return fmt.Sprintf("Skipped restore of snapshot %v/%v because the member already has data.", dir, name), nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("can't check for existing data: %v", err)
+	}
+
+	// Start from an empty scratch dir, in case a previous attempt failed.
+	scratchDir := filepath.Join(dataDir, restoreDirName)
+	if err := os.RemoveAll(scratchDir); err != nil {
+		return "", fmt.Errorf("can't clean up scratch dir: %v", err)
+	}
+	if err := os.MkdirAll(scratchDir, 0755); err != nil {
+		return "", fmt.Errorf("can't create scratch dir: %v", err)
+	}
+
+	snapshotPath := filepath.Join(scratchDir, snapshotFileName)
+	if err := downloadSnapshot(ctx, bs, dir, name, snapshotPath); err != nil {
+		return "", err
+	}
+
+	// etcdutl refuses to restore into a data dir that already exists, so
+	// restore next to it and then move the member data into place.
+	restoredDataDir := filepath.Join(scratchDir, "data")
+	cmd := exec.CommandContext(ctx, etcdutlPath, "snapshot", "restore", snapshotPath,
+		"--data-dir", restoredDataDir,
+		"--name", os.Getenv(MemberNameEnvVar),
+		"--initial-cluster", os.Getenv(InitialClusterEnvVar),
+		"--initial-cluster-token", os.Getenv(InitialClusterTokenEnvVar),
+		"--initial-advertise-peer-urls", os.Getenv(InitialAdvertisePeerURLsEnvVar),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("etcdutl snapshot restore failed: %v", err)
+	}
+	if err := os.Rename(filepath.Join(restoredDataDir, memberDirName), memberDir); err != nil {
+		return "", fmt.Errorf("can't move restored data into place: %v", err)
+	}
+	if err := os.RemoveAll(scratchDir); err != nil {
+		log.Warningf("Can't clean up scratch dir: %v", err)
+	}
+	return fmt.Sprintf("Restored snapshot %v/%v.", dir, name), nil
+}
+
+func downloadSnapshot(ctx context.Context, bs backupstorage.BackupStorage, dir, name, path string) error {
+	bhs, err := bs.ListBackups(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("can't list snapshots in %v: %v", dir, err)
+	}
+	var bh backupstorage.BackupHandle
+	for _, candidate := range bhs {
+		if candidate.Name() == name {
+			bh = candidate
+			break
+		}
+	}
+	if bh == nil {
+		return fmt.Errorf("snapshot %v/%v not found", dir, name)
+	}
+
+	log.Infof("Downloading snapshot %v/%v", dir, name)
+	src, err := bh.ReadFile(ctx, snapshotFileName)
+	if err != nil {
+		return fmt.Errorf("can't read snapshot %v/%v: %v", dir, name, err)
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("can't create %v: %v", path, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("can't download snapshot %v/%v: %v", dir, name, err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("can't download snapshot %v/%v: %v", dir, name, err)
+	}
+	return nil
+}
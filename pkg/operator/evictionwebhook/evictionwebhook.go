@@ -0,0 +1,157 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package evictionwebhook implements a validating admission webhook that maps
+requests to the Kubernetes Eviction API onto the drain protocol defined in the
+"drain" package.
+
+Tools like "kubectl drain", the cluster autoscaler, and managed node upgrades
+evict Pods through the Eviction API, and retry evictions that are rejected with
+"429 Too Many Requests". When such a tool tries to evict a Pod that supports
+drains, this webhook starts a drain on the Pod (if one isn't already in
+progress) and rejects the eviction with a 429 until the Pod's controller marks
+the drain as finished, at which point the eviction is allowed.
+
+This only takes effect if the operator is started with --eviction_webhook and
+a ValidatingWebhookConfiguration that sends CREATE requests for the
+"pods/eviction" subresource to Path has been installed in the cluster.
+*/
+package evictionwebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+// Path is the URL path on the webhook server at which the eviction webhook
+// is served.
+const Path = "/validate-v1-pod-eviction"
+
+var log = logf.Log.WithName("eviction-webhook")
+
+// Add registers the eviction webhook with the manager's webhook server.
+//
+// If namespaces is not empty, it should list the namespaces the manager's
+// cache watches. Evictions of Pods in any other namespace are always allowed,
+// since we can't look those Pods up.
+func Add(mgr manager.Manager, namespaces []string) error {
+	mgr.GetWebhookServer().Register(Path, &webhook.Admission{
+		Handler: newEvictionHandler(mgr.GetClient(), namespaces),
+	})
+	return nil
+}
+
+type evictionHandler struct {
+	client client.Client
+	// namespaces is the set of namespaces we can look up Pods in.
+	// If it's empty, we can look up Pods in any namespace.
+	namespaces map[string]bool
+}
+
+func newEvictionHandler(c client.Client, namespaces []string) *evictionHandler {
+	h := &evictionHandler{client: c, namespaces: map[string]bool{}}
+	for _, ns := range namespaces {
+		h.namespaces[ns] = true
+	}
+	return h
+}
+
+// Handle implements admission.Handler.
+func (h *evictionHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create || req.SubResource != "eviction" {
+		return admission.Allowed("not an eviction")
+	}
+	if len(h.namespaces) > 0 && !h.namespaces[req.Namespace] {
+		// None of our Pods live here, and the cache can't see this namespace.
+		return admission.Allowed("namespace not watched")
+	}
+
+	pod := &corev1.Pod{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: req.Name}, pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Let the API server report that the Pod doesn't exist.
+			return admission.Allowed("pod not found")
+		}
+		// Any error response from a webhook denies the request, regardless of
+		// the failurePolicy, so fail open here too rather than block evictions
+		// of Pods we may not even manage.
+		log.Info("failed to get Pod for eviction; allowing it", "namespace", req.Namespace, "name", req.Name, "error", err.Error())
+		return admission.Allowed("pod lookup failed")
+	}
+
+	allowed, startDrain := decide(pod)
+	if allowed {
+		return admission.Allowed("drain finished or not supported")
+	}
+
+	dryRun := req.DryRun != nil && *req.DryRun
+	if startDrain && !dryRun {
+		drain.Start(pod, "eviction requested")
+		if err := h.client.Update(ctx, pod); err != nil {
+			// The evicting agent will retry, so we'll get another chance.
+			log.Info("failed to start drain for eviction", "namespace", pod.Namespace, "name", pod.Name, "error", err.Error())
+		}
+	}
+
+	return tooManyRequests(fmt.Sprintf("pod %v/%v is being drained; retry the eviction later", pod.Namespace, pod.Name))
+}
+
+// decide returns whether an eviction of the given Pod should be allowed now,
+// and if not, whether a drain needs to be started on it.
+func decide(pod *corev1.Pod) (allowed, startDrain bool) {
+	// We only intervene for Pods whose controller implements drains.
+	if !drain.Supported(pod) {
+		return true, false
+	}
+	// Once the drain has finished, the Pod is safe to delete.
+	if drain.Finished(pod) {
+		return true, false
+	}
+	// A Pod that's already going away doesn't need to be drained.
+	if pod.DeletionTimestamp != nil {
+		return true, false
+	}
+	return false, !drain.Started(pod)
+}
+
+// tooManyRequests returns a response that denies the eviction in a way that
+// tells the evicting agent to retry later, just like a PodDisruptionBudget.
+func tooManyRequests(message string) admission.Response {
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Code:    http.StatusTooManyRequests,
+				Reason:  metav1.StatusReasonTooManyRequests,
+				Message: message,
+			},
+		},
+	}
+}
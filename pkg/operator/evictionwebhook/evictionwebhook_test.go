@@ -0,0 +1,120 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictionwebhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"planetscale.dev/vitess-operator/pkg/operator/drain"
+)
+
+func TestDecide(t *testing.T) {
+	newPod := func(annotations ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+		for _, key := range annotations {
+			pod.Annotations[key] = "test"
+		}
+		return pod
+	}
+	deleting := newPod(drain.SupportedAnnotation)
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	tests := []struct {
+		name           string
+		pod            *corev1.Pod
+		wantAllowed    bool
+		wantStartDrain bool
+	}{
+		{
+			name:        "drain not supported",
+			pod:         newPod(),
+			wantAllowed: true,
+		},
+		{
+			name:           "not draining",
+			pod:            newPod(drain.SupportedAnnotation),
+			wantAllowed:    false,
+			wantStartDrain: true,
+		},
+		{
+			name:        "draining",
+			pod:         newPod(drain.SupportedAnnotation, drain.StartedAnnotation),
+			wantAllowed: false,
+		},
+		{
+			name:        "drain finished",
+			pod:         newPod(drain.SupportedAnnotation, drain.StartedAnnotation, drain.AcknowledgedAnnotation, drain.FinishedAnnotation),
+			wantAllowed: true,
+		},
+		{
+			name:        "being deleted",
+			pod:         deleting,
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, startDrain := decide(tt.pod)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Equal(t, tt.wantStartDrain, startDrain)
+		})
+	}
+}
+
+// failingClient fails every Get, like a cache that doesn't cover the namespace.
+type failingClient struct {
+	client.Client
+	gets int
+}
+
+func (c *failingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets++
+	return errors.New("unable to get: pods is forbidden")
+}
+
+func TestHandleUnwatchedNamespace(t *testing.T) {
+	eviction := func(namespace string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation:   admissionv1.Create,
+			SubResource: "eviction",
+			Namespace:   namespace,
+			Name:        "pod",
+		}}
+	}
+
+	c := &failingClient{}
+	h := newEvictionHandler(c, []string{"vitess"})
+	resp := h.Handle(context.Background(), eviction("kube-system"))
+	assert.True(t, resp.Allowed, "eviction in an unwatched namespace")
+	assert.Equal(t, 0, c.gets, "Pod lookups in an unwatched namespace")
+
+	// Lookup errors shouldn't block evictions either.
+	resp = h.Handle(context.Background(), eviction("vitess"))
+	assert.True(t, resp.Allowed, "eviction after a failed lookup")
+	assert.Equal(t, 1, c.gets)
+}
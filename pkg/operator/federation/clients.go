@@ -0,0 +1,130 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Clients hands out clients for the Kubernetes clusters that members live in.
+// Clients for other clusters are built from kubeconfig Secrets and reused for
+// as long as the kubeconfig doesn't change. When it changes, or the Secret
+// goes away, the old client is dropped and its idle connections are closed.
+type Clients struct {
+	local  client.Client
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	remotes map[kubeconfigKey]*remoteClient
+}
+
+// kubeconfigKey identifies a kubeconfig stored in a Secret.
+type kubeconfigKey struct {
+	namespace, name, key string
+}
+
+type remoteClient struct {
+	sum        [sha256.Size]byte
+	client     client.Client
+	httpClient *http.Client
+}
+
+// NewClients creates a Clients that uses the given client for the local
+// Kubernetes cluster, including to read kubeconfig Secrets.
+func NewClients(local client.Client, scheme *runtime.Scheme) *Clients {
+	return &Clients{
+		local:   local,
+		scheme:  scheme,
+		remotes: make(map[kubeconfigKey]*remoteClient),
+	}
+}
+
+// For returns a client for the Kubernetes cluster of a target. The kubeconfig
+// Secret, if any, is read from the given namespace.
+func (c *Clients) For(ctx context.Context, namespace string, target Target) (client.Client, error) {
+	if target.KubeconfigSecret == nil {
+		return c.local, nil
+	}
+	remoteKey := kubeconfigKey{namespace: namespace, name: target.KubeconfigSecret.Name, key: target.KubeconfigSecret.Key}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: target.KubeconfigSecret.Name}
+	if err := c.local.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.evict(remoteKey)
+		}
+		return nil, fmt.Errorf("failed to get kubeconfig Secret %s: %w", key.Name, err)
+	}
+	kubeconfig, ok := secret.Data[target.KubeconfigSecret.Key]
+	if !ok {
+		c.evict(remoteKey)
+		return nil, fmt.Errorf("kubeconfig Secret %s has no key %q", key.Name, target.KubeconfigSecret.Key)
+	}
+
+	sum := sha256.Sum256(kubeconfig)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if remote := c.remotes[remoteKey]; remote != nil {
+		if remote.sum == sum {
+			return remote.client, nil
+		}
+		// The kubeconfig has changed, so the old client is no longer needed.
+		c.evictLocked(remoteKey)
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in Secret %s: %w", key.Name, err)
+	}
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client from kubeconfig Secret %s: %w", key.Name, err)
+	}
+	remote, err := client.New(config, client.Options{Scheme: c.scheme, HTTPClient: httpClient})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client from kubeconfig Secret %s: %w", key.Name, err)
+	}
+	c.remotes[remoteKey] = &remoteClient{sum: sum, client: remote, httpClient: httpClient}
+	return remote, nil
+}
+
+// evict drops the client built from a kubeconfig Secret, if any.
+func (c *Clients) evict(key kubeconfigKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictLocked(key)
+}
+
+func (c *Clients) evictLocked(key kubeconfigKey) {
+	remote := c.remotes[key]
+	if remote == nil {
+		return
+	}
+	remote.httpClient.CloseIdleConnections()
+	delete(c.remotes, key)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// secretClient serves a single kubeconfig Secret, or none if data is nil.
+type secretClient struct {
+	client.Client
+	data map[string][]byte
+}
+
+func (c *secretClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if c.data == nil {
+		return apierrors.NewNotFound(corev1.Resource("secrets"), key.Name)
+	}
+	obj.(*corev1.Secret).Data = c.data
+	return nil
+}
+
+func kubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: %s
+contexts:
+- name: remote
+  context:
+    cluster: remote
+current-context: remote
+`, server))
+}
+
+func TestClientsEviction(t *testing.T) {
+	ctx := context.Background()
+	local := &secretClient{data: map[string][]byte{"kubeconfig": kubeconfig("https://east.example.com")}}
+	clients := NewClients(local, runtime.NewScheme())
+	target := Target{Namespace: "away", KubeconfigSecret: &planetscalev2.SecretSource{Name: "east", Key: "kubeconfig"}}
+
+	first, err := clients.For(ctx, "home", target)
+	assert.NoError(t, err)
+	again, err := clients.For(ctx, "home", target)
+	assert.NoError(t, err)
+	assert.Same(t, first, again, "client for an unchanged kubeconfig")
+
+	// A rotated kubeconfig replaces the old client rather than adding one.
+	local.data = map[string][]byte{"kubeconfig": kubeconfig("https://east2.example.com")}
+	rotated, err := clients.For(ctx, "home", target)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, rotated, "client for a rotated kubeconfig")
+	assert.Len(t, clients.remotes, 1)
+
+	// A deleted Secret drops the client.
+	local.data = nil
+	_, err = clients.For(ctx, "home", target)
+	assert.Error(t, err)
+	assert.Empty(t, clients.remotes)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package federation deploys the cells of a VitessCluster that are placed in
+other namespaces or Kubernetes clusters.
+
+Each distinct place gets a member VitessCluster with the same name as its
+parent. A member deploys only the cells placed there, along with tablets of
+every keyspace in those cells, and connects to the parent's global lockserver.
+Since all members share the global topology, Vitess itself coordinates
+serving, backups and reparents across them. Anything that acts on a whole
+keyspace or on the set of cells, such as pruning or scheduled backups, is
+only done by the parent.
+*/
+package federation
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// ParentAnnotation is set on a member VitessCluster to record which
+	// VitessCluster it belongs to, as "<namespace>/<name>".
+	ParentAnnotation = "planetscale.com/federation-parent"
+)
+
+// Target identifies the place where a member VitessCluster lives.
+type Target struct {
+	// Namespace is the namespace of the member.
+	Namespace string
+	// KubeconfigSecret is the kubeconfig for the Kubernetes cluster of the
+	// member, or nil if it's the same cluster as the parent.
+	KubeconfigSecret *planetscalev2.SecretSource
+}
+
+// TargetFor returns the target for a cell placement, filling in the parent's
+// namespace if none is specified.
+func TargetFor(namespace string, placement *planetscalev2.VitessCellPlacement) Target {
+	target := Target{Namespace: placement.Namespace}
+	if target.Namespace == "" {
+		target.Namespace = namespace
+	}
+	if placement.KubeconfigSecret != nil {
+		target.KubeconfigSecret = &planetscalev2.SecretSource{
+			Name: placement.KubeconfigSecret.Name,
+			Key:  placement.KubeconfigSecret.Key,
+		}
+	}
+	return target
+}
+
+// Key returns a string that's the same for any two equivalent targets.
+func (t Target) Key() string {
+	if t.KubeconfigSecret == nil {
+		return t.Namespace
+	}
+	return fmt.Sprintf("%s@%s/%s", t.Namespace, t.KubeconfigSecret.Name, t.KubeconfigSecret.Key)
+}
+
+// String returns a description of the target for events and status messages.
+func (t Target) String() string {
+	if t.KubeconfigSecret == nil {
+		return fmt.Sprintf("namespace %s", t.Namespace)
+	}
+	return fmt.Sprintf("namespace %s of the cluster in kubeconfig Secret %s", t.Namespace, t.KubeconfigSecret.Name)
+}
+
+// ParentValue returns the value of ParentAnnotation for members of a
+// VitessCluster.
+func ParentValue(vt *planetscalev2.VitessCluster) string {
+	return vt.Namespace + "/" + vt.Name
+}
+
+// NewMember builds the member VitessCluster that deploys the given cells of
+// a VitessCluster, connected to the given global lockserver.
+func NewMember(key client.ObjectKey, vt *planetscalev2.VitessCluster, cells sets.String, globalLockserver *planetscalev2.VitessLockserverParams) *planetscalev2.VitessCluster {
+	spec := vt.Spec.DeepCopy()
+
+	spec.GlobalLockserver = planetscalev2.LockserverSpec{External: globalLockserver.DeepCopy()}
+
+	spec.Cells = nil
+	for i := range vt.Spec.Cells {
+		if !cells.Has(vt.Spec.Cells[i].Name) {
+			continue
+		}
+		cell := vt.Spec.Cells[i].DeepCopy()
+		cell.Placement = nil
+		spec.Cells = append(spec.Cells, *cell)
+	}
+
+	for i := range spec.Keyspaces {
+		keyspace := &spec.Keyspaces[i]
+
+		// Only deploy tablets in the member's own cells.
+		keyspace.RemoveTabletPoolsInCells(keyspaceCells(keyspace).Difference(cells))
+
+		// These act on the whole keyspace, so only the parent does them.
+		keyspace.BackupSchedule = nil
+		keyspace.Materializations = nil
+		keyspace.AutoReshard = nil
+		keyspace.Schema = nil
+
+		// The parent decides how to turn down a keyspace. The member only
+		// lets go of it once the keyspace is idle.
+		keyspace.TurndownPolicy = planetscalev2.VitessKeyspaceTurndownPolicyRequireIdle
+		keyspace.Decommission = nil
+	}
+
+	if spec.Backup != nil {
+		spec.Backup.Schedule = nil
+	}
+	if spec.ReparentSettings != nil {
+		spec.ReparentSettings.PrimaryRotationSchedule = ""
+	}
+
+	// The parent has already resolved any rollback into the images and flags.
+	spec.RollbackTo = nil
+
+	// The parent owns the set of cells and keyspaces in topology. Members
+	// only register their own cells.
+	if spec.TopologyReconciliation == nil {
+		spec.TopologyReconciliation = &planetscalev2.TopoReconcileConfig{}
+	}
+	spec.TopologyReconciliation.RegisterCellsAliases = pointer.Bool(false)
+	spec.TopologyReconciliation.PruneCells = pointer.Bool(false)
+	spec.TopologyReconciliation.PruneKeyspaces = pointer.Bool(false)
+
+	return &planetscalev2.VitessCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Annotations: map[string]string{
+				ParentAnnotation: ParentValue(vt),
+			},
+		},
+		Spec: *spec,
+	}
+}
+
+// MemberCells returns the sorted names of the cells that a member VitessCluster
+// deploys, including cells that it's still in the process of removing.
+func MemberCells(member *planetscalev2.VitessCluster) []string {
+	cells := sets.NewString()
+	for i := range member.Spec.Cells {
+		cells.Insert(member.Spec.Cells[i].Name)
+	}
+	for name := range member.Status.Cells {
+		cells.Insert(name)
+	}
+	for name := range member.Status.OrphanedCells {
+		cells.Insert(name)
+	}
+	return cells.List()
+}
+
+// StatusTarget returns the target of a member status.
+func StatusTarget(status *planetscalev2.VitessClusterMemberStatus) Target {
+	return Target{
+		Namespace:        status.Namespace,
+		KubeconfigSecret: status.KubeconfigSecret,
+	}
+}
+
+// keyspaceCells returns the set of cells in which a keyspace has tablet pools.
+func keyspaceCells(keyspace *planetscalev2.VitessKeyspaceTemplate) sets.String {
+	cells := sets.NewString()
+	for i := range keyspace.Partitionings {
+		for _, pool := range keyspace.Partitionings[i].TabletPools() {
+			cells.Insert(pool.Cell)
+		}
+	}
+	return cells
+}
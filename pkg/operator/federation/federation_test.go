@@ -0,0 +1,99 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestTargetKey(t *testing.T) {
+	local := TargetFor("home", &planetscalev2.VitessCellPlacement{})
+	assert.Equal(t, "home", local.Key())
+
+	sameNamespace := TargetFor("home", &planetscalev2.VitessCellPlacement{Namespace: "home"})
+	assert.Equal(t, local.Key(), sameNamespace.Key())
+
+	remote := TargetFor("home", &planetscalev2.VitessCellPlacement{
+		KubeconfigSecret: &planetscalev2.SecretSource{Name: "east", Key: "kubeconfig"},
+	})
+	assert.NotEqual(t, local.Key(), remote.Key())
+}
+
+func TestNewMember(t *testing.T) {
+	vt := &planetscalev2.VitessCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "home", Name: "example"},
+		Spec: planetscalev2.VitessClusterSpec{
+			Cells: []planetscalev2.VitessCellTemplate{
+				{Name: "zone1"},
+				{Name: "zone2", Placement: &planetscalev2.VitessCellPlacement{Namespace: "away"}},
+			},
+			Keyspaces: []planetscalev2.VitessKeyspaceTemplate{
+				{
+					Name:           "commerce",
+					BackupSchedule: &planetscalev2.VitessBackupScheduleSpec{},
+					Partitionings: []planetscalev2.VitessKeyspacePartitioning{
+						{Equal: &planetscalev2.VitessKeyspaceEqualPartitioning{
+							Parts: 1,
+							ShardTemplate: planetscalev2.VitessShardTemplate{
+								TabletPools: []planetscalev2.VitessShardTabletPool{
+									{Cell: "zone1", Type: planetscalev2.ReplicaPoolType, Replicas: 2},
+									{Cell: "zone2", Type: planetscalev2.ReplicaPoolType, Replicas: 1},
+								},
+							},
+						}},
+					},
+				},
+			},
+			TopologyReconciliation: &planetscalev2.TopoReconcileConfig{
+				RegisterCells: pointer.Bool(true),
+				PruneCells:    pointer.Bool(true),
+			},
+		},
+	}
+	global := &planetscalev2.VitessLockserverParams{Implementation: "etcd2", Address: "global:2379", RootPath: "/vitess/example/global"}
+
+	member := NewMember(client.ObjectKey{Namespace: "away", Name: "example"}, vt, sets.NewString("zone2"), global)
+
+	assert.Equal(t, "home/example", member.Annotations[ParentAnnotation])
+	assert.Equal(t, global, member.Spec.GlobalLockserver.External)
+	if assert.Len(t, member.Spec.Cells, 1) {
+		assert.Equal(t, "zone2", member.Spec.Cells[0].Name)
+		assert.Nil(t, member.Spec.Cells[0].Placement)
+	}
+	if assert.Len(t, member.Spec.Keyspaces, 1) {
+		keyspace := &member.Spec.Keyspaces[0]
+		assert.Nil(t, keyspace.BackupSchedule)
+		pools := keyspace.Partitionings[0].TabletPools()
+		if assert.Len(t, pools, 1) {
+			assert.Equal(t, "zone2", pools[0].Cell)
+		}
+	}
+	assert.True(t, *member.Spec.TopologyReconciliation.RegisterCells)
+	assert.False(t, *member.Spec.TopologyReconciliation.PruneCells)
+
+	// The parent must not be modified.
+	assert.Len(t, vt.Spec.Keyspaces[0].Partitionings[0].TabletPools(), 2)
+	assert.NotNil(t, vt.Spec.Keyspaces[0].BackupSchedule)
+}
@@ -51,6 +51,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -62,6 +63,10 @@ const (
 	envForkPath     = "PS_OPERATOR_FORK_PATH"
 	envPodName      = "PS_OPERATOR_POD_NAME"
 	envPodNamespace = "PS_OPERATOR_POD_NAMESPACE"
+
+	// OperatorContainerNameSubstring needs to be contained within the name of
+	// the main container in deploy/operator.yaml.
+	OperatorContainerNameSubstring = "-operator"
 )
 
 // Path returns the name of the forked code path that this process should take.
@@ -84,12 +89,7 @@ func NewPodSpec(ctx context.Context, c client.Client, forkPath string) (*corev1.
 	spec.NodeName = ""
 
 	// Set the fork path env var on all containers.
-	childEnv := []corev1.EnvVar{
-		{
-			Name:  envForkPath,
-			Value: forkPath,
-		},
-	}
+	childEnv := EnvVars(forkPath)
 	for i := range spec.Containers {
 		container := &spec.Containers[i]
 		update.Env(&container.Env, childEnv)
@@ -98,6 +98,35 @@ func NewPodSpec(ctx context.Context, c client.Client, forkPath string) (*corev1.
 	return &spec, nil
 }
 
+// EnvVars returns the environment variables that tell the operator binary to
+// take the given forked code path. This can be used to run the operator binary
+// in a Pod that isn't based on the parent Pod, such as one that copies the
+// binary into a different container image.
+func EnvVars(forkPath string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  envForkPath,
+			Value: forkPath,
+		},
+	}
+}
+
+// OperatorImage returns the container image of the operator container in the
+// Pod you're currently running in.
+func OperatorImage(ctx context.Context, c client.Client) (string, error) {
+	parentPod, err := getParentPod(ctx, c)
+	if err != nil {
+		return "", fmt.Errorf("can't get parent Pod: %v", err)
+	}
+	for i := range parentPod.Spec.Containers {
+		container := &parentPod.Spec.Containers[i]
+		if strings.Contains(container.Name, OperatorContainerNameSubstring) {
+			return container.Image, nil
+		}
+	}
+	return "", fmt.Errorf("can't find operator container (name containing %q) in my own Pod", OperatorContainerNameSubstring)
+}
+
 func getParentPod(ctx context.Context, c client.Client) (*corev1.Pod, error) {
 	var key client.ObjectKey
 	key.Namespace = os.Getenv(envPodNamespace)
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TerminationMessagePath is where a container writes the outcome of its
+	// work, so the operator can read it from the Pod status.
+	TerminationMessagePath = "/dev/termination-log"
+	// MaxTerminationMessageLength is the most that Kubernetes will keep.
+	MaxTerminationMessageLength = 4096
+)
+
+// WriteTerminationMessage writes a message to TerminationMessagePath,
+// truncated to the length that Kubernetes will keep.
+func WriteTerminationMessage(message string) error {
+	if len(message) > MaxTerminationMessageLength {
+		message = message[:MaxTerminationMessageLength]
+	}
+	return os.WriteFile(TerminationMessagePath, []byte(message), 0644)
+}
+
+// PodTermination returns when the last container (or init container) of a
+// finished Pod to terminate did so, along with its termination message. If no
+// container has terminated, it returns when the Pod was created.
+func PodTermination(pod *corev1.Pod) (time.Time, string) {
+	finishedTime := pod.CreationTimestamp.Time
+	var message string
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		terminated := statuses[i].State.Terminated
+		if terminated == nil || terminated.FinishedAt.Time.Before(finishedTime) {
+			continue
+		}
+		finishedTime = terminated.FinishedAt.Time
+		message = terminated.Message
+	}
+	return finishedTime, message
+}
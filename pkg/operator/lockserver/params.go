@@ -52,6 +52,21 @@ func GlobalConnectionParams(lockSpec *planetscalev2.LockserverSpec, namespace, c
 	}
 }
 
+// ActiveGlobalSpec returns the global lockserver spec that Vitess components
+// should use right now. During a migration, that's the migration target once
+// the data has been copied there and a switch over was requested.
+func ActiveGlobalSpec(lockSpec *planetscalev2.LockserverSpec, status *planetscalev2.LockserverStatus) *planetscalev2.LockserverSpec {
+	migration := lockSpec.Migration
+	if migration == nil || !migration.SwitchOver {
+		return lockSpec
+	}
+	if status.Migration == nil || status.Migration.Phase != planetscalev2.LockserverMigrationSwitchedOver || status.Migration.Target != migration.Target {
+		return lockSpec
+	}
+	target := migration.Target
+	return &planetscalev2.LockserverSpec{External: &target}
+}
+
 // LocalConnectionParams returns the Vitess connection parameters for a
 // VitessCluster cell's local lockserver.
 func LocalConnectionParams(globalLockserverSpec, cellLockserverSpec *planetscalev2.LockserverSpec, namespace, clusterName, cellName string) *planetscalev2.VitessLockserverParams {
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lockserver
+
+import (
+	"testing"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestActiveGlobalSpec(t *testing.T) {
+	target := planetscalev2.VitessLockserverParams{
+		Implementation: "consul",
+		Address:        "consul.example.com:8500",
+		RootPath:       "/vitess/example/global",
+	}
+	spec := &planetscalev2.LockserverSpec{
+		Etcd: &planetscalev2.EtcdLockserverTemplate{},
+		Migration: &planetscalev2.LockserverMigration{
+			Target:     target,
+			SwitchOver: true,
+		},
+	}
+
+	table := []struct {
+		name       string
+		status     *planetscalev2.LockserverMigrationStatus
+		wantTarget bool
+	}{
+		{
+			name:   "not copied yet",
+			status: &planetscalev2.LockserverMigrationStatus{Target: target, Phase: planetscalev2.LockserverMigrationCopying},
+		},
+		{
+			name:   "copied to another target",
+			status: &planetscalev2.LockserverMigrationStatus{Phase: planetscalev2.LockserverMigrationSwitchedOver},
+		},
+		{
+			name:       "switched over",
+			status:     &planetscalev2.LockserverMigrationStatus{Target: target, Phase: planetscalev2.LockserverMigrationSwitchedOver},
+			wantTarget: true,
+		},
+	}
+	for _, test := range table {
+		params := GlobalConnectionParams(ActiveGlobalSpec(spec, &planetscalev2.LockserverStatus{Migration: test.status}), "ns", "example")
+		if got := *params == target; got != test.wantTarget {
+			t.Errorf("%v: GlobalConnectionParams() = %+v; want target: %v", test.name, params, test.wantTarget)
+		}
+	}
+}
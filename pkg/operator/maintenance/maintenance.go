@@ -0,0 +1,82 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package maintenance decides when the operator may make disruptive changes,
+according to the maintenance windows configured for a VitessCluster.
+*/
+package maintenance
+
+import (
+	"fmt"
+	"time"
+	// Embed the time zone database, since the operator image might not have
+	// one.
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// Open returns whether any of the given maintenance windows is open at the
+// given time. If none is, it also returns how long until the next one opens.
+// If there are no maintenance windows, disruptive changes may be made at any
+// time.
+func Open(windows []planetscalev2.MaintenanceWindow, now time.Time) (bool, time.Duration, error) {
+	if len(windows) == 0 {
+		return true, 0, nil
+	}
+	var wait time.Duration
+	for i := range windows {
+		open, windowWait, err := windowOpen(&windows[i], now)
+		if err != nil {
+			return false, 0, err
+		}
+		if open {
+			return true, 0, nil
+		}
+		if wait == 0 || windowWait < wait {
+			wait = windowWait
+		}
+	}
+	return false, wait, nil
+}
+
+func windowOpen(window *planetscalev2.MaintenanceWindow, now time.Time) (bool, time.Duration, error) {
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return false, 0, fmt.Errorf("invalid maintenance window schedule %q: %v", window.Schedule, err)
+	}
+	if window.Duration.Duration <= 0 {
+		return false, 0, fmt.Errorf("invalid duration %v for maintenance window %q: must be positive", window.Duration.Duration, window.Schedule)
+	}
+	location := time.UTC
+	if window.TimeZone != "" {
+		location, err = time.LoadLocation(window.TimeZone)
+		if err != nil {
+			return false, 0, fmt.Errorf("invalid time zone for maintenance window %q: %v", window.Schedule, err)
+		}
+	}
+
+	// The window is open if it opened less than its duration ago, so look for
+	// the first time it opens after that.
+	start := schedule.Next(now.In(location).Add(-window.Duration.Duration))
+	if !start.After(now) {
+		return true, 0, nil
+	}
+	return false, start.Sub(now), nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestOpen(t *testing.T) {
+	// Saturdays from 02:00 to 04:00 in New York, and Wednesdays from 12:00
+	// to 13:00 UTC.
+	windows := []planetscalev2.MaintenanceWindow{
+		{
+			Schedule: "0 2 * * 6",
+			Duration: metav1.Duration{Duration: 2 * time.Hour},
+			TimeZone: "America/New_York",
+		},
+		{
+			Schedule: "0 12 * * 3",
+			Duration: metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	table := []struct {
+		name     string
+		windows  []planetscalev2.MaintenanceWindow
+		now      string
+		wantOpen bool
+		wantWait time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "no windows",
+			now:      "2024-03-04T10:00:00Z",
+			wantOpen: true,
+		},
+		{
+			name:     "in time zone window",
+			windows:  windows,
+			now:      "2024-03-09T08:30:00Z", // 03:30 in New York
+			wantOpen: true,
+		},
+		{
+			name:     "time zone window closed",
+			windows:  windows,
+			now:      "2024-03-09T09:00:00Z", // 04:00 in New York
+			wantOpen: false,
+			wantWait: 4*24*time.Hour + 3*time.Hour, // Saturday 09:00 to Wednesday 12:00.
+		},
+		{
+			name:     "in UTC window",
+			windows:  windows,
+			now:      "2024-03-06T12:59:00Z",
+			wantOpen: true,
+		},
+		{
+			name:     "next window",
+			windows:  windows,
+			now:      "2024-03-08T07:00:00Z", // Friday 02:00 in New York
+			wantOpen: false,
+			wantWait: 24 * time.Hour,
+		},
+		{
+			name:    "invalid schedule",
+			windows: []planetscalev2.MaintenanceWindow{{Schedule: "sometimes", Duration: metav1.Duration{Duration: time.Hour}}},
+			now:     "2024-03-04T10:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "invalid time zone",
+			windows: []planetscalev2.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}, TimeZone: "Mars/Olympus_Mons"}},
+			now:     "2024-03-04T10:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "no duration",
+			windows: []planetscalev2.MaintenanceWindow{{Schedule: "0 2 * * *"}},
+			now:     "2024-03-04T10:00:00Z",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			open, wait, err := Open(test.windows, now)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("Open() error = %v, want error: %v", err, test.wantErr)
+			}
+			if open != test.wantOpen || wait != test.wantWait {
+				t.Errorf("Open() = %v, %v; want %v, %v", open, wait, test.wantOpen, test.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package pdb builds the PodDisruptionBudgets (PDBs) that the operator manages
+for Vitess components.
+*/
+package pdb
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// Spec specifies all the internal parameters needed to create a PDB.
+type Spec struct {
+	// Labels are the labels to put on the PDB itself.
+	Labels map[string]string
+	// Selector selects the Pods that the PDB covers.
+	Selector *metav1.LabelSelector
+	// Replicas is the desired number of Pods.
+	Replicas int32
+	// Config holds the user's settings, if any.
+	Config *planetscalev2.VitessPodDisruptionBudget
+}
+
+// Wanted returns whether a PDB should exist, given the user's settings.
+func Wanted(config *planetscalev2.VitessPodDisruptionBudget) bool {
+	return config == nil || !config.Disabled
+}
+
+// NewPodDisruptionBudget creates a new PDB.
+func NewPodDisruptionBudget(key client.ObjectKey, spec *Spec) *policyv1.PodDisruptionBudget {
+	obj := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	UpdatePodDisruptionBudget(obj, spec)
+	return obj
+}
+
+// UpdatePodDisruptionBudget updates an existing PDB in-place.
+func UpdatePodDisruptionBudget(obj *policyv1.PodDisruptionBudget, spec *Spec) {
+	// Update labels, but ignore existing ones we don't set.
+	update.Labels(&obj.Labels, spec.Labels)
+
+	obj.Spec.Selector = spec.Selector
+	obj.Spec.MinAvailable = nil
+	obj.Spec.MaxUnavailable = nil
+	switch config := spec.Config; {
+	case config != nil && config.MaxUnavailable != nil:
+		maxUnavailable := *config.MaxUnavailable
+		obj.Spec.MaxUnavailable = &maxUnavailable
+	case config != nil && config.MinAvailable != nil:
+		minAvailable := *config.MinAvailable
+		obj.Spec.MinAvailable = &minAvailable
+	default:
+		// Let only one Pod be evicted at a time.
+		minAvailable := intstr.FromInt(int(defaultMinAvailable(spec.Replicas)))
+		obj.Spec.MinAvailable = &minAvailable
+	}
+}
+
+// defaultMinAvailable returns one less than the desired replicas.
+func defaultMinAvailable(replicas int32) int32 {
+	if replicas < 1 {
+		return 0
+	}
+	return replicas - 1
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdb
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromString("50%")
+	maxUnavailable := intstr.FromInt(2)
+
+	tests := []struct {
+		name               string
+		replicas           int32
+		config             *planetscalev2.VitessPodDisruptionBudget
+		wantMinAvailable   *intstr.IntOrString
+		wantMaxUnavailable *intstr.IntOrString
+	}{
+		{
+			name:             "default",
+			replicas:         3,
+			wantMinAvailable: intOrStringPtr(intstr.FromInt(2)),
+		},
+		{
+			name:             "default with no replicas",
+			replicas:         0,
+			wantMinAvailable: intOrStringPtr(intstr.FromInt(0)),
+		},
+		{
+			name:             "minAvailable",
+			replicas:         3,
+			config:           &planetscalev2.VitessPodDisruptionBudget{MinAvailable: &minAvailable},
+			wantMinAvailable: &minAvailable,
+		},
+		{
+			name:               "maxUnavailable wins",
+			replicas:           3,
+			config:             &planetscalev2.VitessPodDisruptionBudget{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable},
+			wantMaxUnavailable: &maxUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &Spec{
+				Labels:   map[string]string{"app": "test"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				Replicas: tt.replicas,
+				Config:   tt.config,
+			}
+			obj := NewPodDisruptionBudget(client.ObjectKey{Namespace: "ns", Name: "name"}, spec)
+			if got, want := obj.Spec.MinAvailable, tt.wantMinAvailable; !equalIntOrString(got, want) {
+				t.Errorf("minAvailable = %v, want %v", got, want)
+			}
+			if got, want := obj.Spec.MaxUnavailable, tt.wantMaxUnavailable; !equalIntOrString(got, want) {
+				t.Errorf("maxUnavailable = %v, want %v", got, want)
+			}
+			if obj.Labels["app"] != "test" {
+				t.Errorf("labels = %v, want app=test", obj.Labels)
+			}
+		})
+	}
+}
+
+func intOrStringPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
+
+func equalIntOrString(a, b *intstr.IntOrString) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
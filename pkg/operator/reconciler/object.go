@@ -227,7 +227,7 @@ func (r *Reconciler) ReconcileObject(ctx context.Context, owner runtime.Object,
 	}
 
 	// If the object is ready to be rolled out, also apply rolling updates.
-	if rollout.Released(curObjMeta) {
+	if rollout.Released(curObjMeta) && !s.RolloutPaused {
 		if s.UpdateRollingInPlace != nil {
 			s.UpdateRollingInPlace(key, updatedObjInPlace)
 		}
@@ -334,7 +334,7 @@ func (r *Reconciler) drainAndDelete(ctx context.Context, owner runtime.Object, k
 
 	// If the object supports drain, we need to drain first.
 	if drain.Supported(curObjMeta) && !drain.Finished(curObjMeta) {
-		drain.Start(newObjMeta, "rolling update")
+		drain.Start(newObjMeta, drain.RollingUpdateMessage)
 		// We still have changes pending from UpdateRollingRecreate
 		// since we didn't get to delete yet.
 		rollout.Schedule(newObjMeta, describeDiff(updatedObjInPlace, updatedObjRecreate, s.Kind))
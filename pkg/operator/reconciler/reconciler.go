@@ -171,6 +171,13 @@ type Strategy struct {
 	*/
 	UpdateRollingRecreate func(key client.ObjectKey, newObj runtime.Object)
 
+	/*
+		RolloutPaused holds back rolling updates, even for objects that have
+		already been released. Pending changes are still scheduled as usual, and
+		they're applied once rollouts are no longer paused.
+	*/
+	RolloutPaused bool
+
 	/*
 		Status is called when the object exists and is desired.
 
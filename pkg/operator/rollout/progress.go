@@ -0,0 +1,64 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/conditions"
+)
+
+const (
+	// progressDeadlineExceededReason is the reason the Deployment controller
+	// gives when a rollout stops making progress.
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// DeploymentProgress returns how far along the rollout of the latest spec of a
+// Deployment is. Updated Pods that are still unavailable once the Deployment
+// has stopped making progress are counted as failed.
+func DeploymentProgress(obj *appsv1.Deployment) planetscalev2.VitessRolloutCounts {
+	counts := planetscalev2.VitessRolloutCounts{Desired: 1}
+	if obj.Spec.Replicas != nil {
+		counts.Desired = *obj.Spec.Replicas
+	}
+	if Scheduled(obj) {
+		// None of the Pods have the changes yet.
+		counts.Pending = counts.Desired
+		return counts
+	}
+	if obj.Status.ObservedGeneration == obj.Generation {
+		// The status only reflects the latest spec once it's been observed.
+		counts.Updated = obj.Status.UpdatedReplicas
+	}
+	if progressing := conditions.Deployment(obj.Status.Conditions, appsv1.DeploymentProgressing); progressing != nil && progressing.Reason == progressDeadlineExceededReason {
+		counts.Failed = obj.Status.UnavailableReplicas
+		if counts.Failed > counts.Updated {
+			counts.Failed = counts.Updated
+		}
+		counts.Updated -= counts.Failed
+	}
+	if counts.Updated > counts.Desired {
+		counts.Updated = counts.Desired
+	}
+	counts.Pending = counts.Desired - counts.Updated - counts.Failed
+	if counts.Pending < 0 {
+		counts.Pending = 0
+	}
+	return counts
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollout
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestDeploymentProgress(t *testing.T) {
+	newDeployment := func(updated, unavailable int32, progressReason string) *appsv1.Deployment {
+		obj := &appsv1.Deployment{}
+		obj.Spec.Replicas = pointer.Int32Ptr(3)
+		obj.Status.UpdatedReplicas = updated
+		obj.Status.UnavailableReplicas = unavailable
+		obj.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Reason: progressReason}}
+		return obj
+	}
+	scheduled := newDeployment(3, 0, "NewReplicaSetAvailable")
+	Schedule(scheduled, "new image")
+
+	tests := []struct {
+		name string
+		obj  *appsv1.Deployment
+		want planetscalev2.VitessRolloutCounts
+	}{
+		{
+			name: "complete",
+			obj:  newDeployment(3, 0, "NewReplicaSetAvailable"),
+			want: planetscalev2.VitessRolloutCounts{Desired: 3, Updated: 3},
+		},
+		{
+			name: "in progress",
+			obj:  newDeployment(1, 1, "ReplicaSetUpdated"),
+			want: planetscalev2.VitessRolloutCounts{Desired: 3, Updated: 1, Pending: 2},
+		},
+		{
+			name: "stopped making progress",
+			obj:  newDeployment(1, 1, progressDeadlineExceededReason),
+			want: planetscalev2.VitessRolloutCounts{Desired: 3, Pending: 2, Failed: 1},
+		},
+		{
+			name: "changes scheduled",
+			obj:  scheduled,
+			want: planetscalev2.VitessRolloutCounts{Desired: 3, Pending: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeploymentProgress(tt.obj); got != tt.want {
+				t.Errorf("DeploymentProgress() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -58,6 +58,11 @@ const (
 	// object's controller should now release any scheduled changes to its children.
 	// The controller will remove the annotation when all children are updated.
 	CascadeAnnotation = AnnotationPrefix + "/" + "cascade"
+
+	// PausedAnnotation is the annotation whose presence on a VitessCluster,
+	// VitessKeyspace, or VitessShard freezes the rollout of any changes that
+	// require restarting Pods below it, regardless of its value.
+	PausedAnnotation = "planetscale.com/rollout-paused"
 )
 
 // Scheduled returns whether the object has pending changes.
@@ -69,6 +74,14 @@ func Scheduled(obj metav1.Object) bool {
 	return present
 }
 
+// Paused returns whether the rollout of changes below the object is paused.
+func Paused(obj metav1.Object) bool {
+	ann := obj.GetAnnotations()
+	// We only care that the annotation key is present.
+	_, present := ann[PausedAnnotation]
+	return present
+}
+
 // Released returns whether it's ok to apply changes to the object.
 func Released(obj metav1.Object) bool {
 	ann := obj.GetAnnotations()
@@ -0,0 +1,68 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package scaleprofile decides which scheduled scale profile, if any, is in
+effect for a tablet pool or vtgate.
+*/
+package scaleprofile
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// Active returns the first of the given scale profiles that's in effect at
+// the given time, or nil if none is.
+func Active(profiles []planetscalev2.VitessScaleProfile, now time.Time) (*planetscalev2.VitessScaleProfile, error) {
+	for i := range profiles {
+		active, err := inEffect(&profiles[i], now)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			return &profiles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func inEffect(profile *planetscalev2.VitessScaleProfile, now time.Time) (bool, error) {
+	schedule, err := cron.ParseStandard(profile.Schedule)
+	if err != nil {
+		return false, fmt.Errorf("invalid schedule %q for scale profile %q: %v", profile.Schedule, profile.Name, err)
+	}
+	if profile.Duration.Duration <= 0 {
+		return false, fmt.Errorf("invalid duration %v for scale profile %q: must be positive", profile.Duration.Duration, profile.Name)
+	}
+	location := time.UTC
+	if profile.TimeZone != "" {
+		location, err = time.LoadLocation(profile.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("invalid time zone for scale profile %q: %v", profile.Name, err)
+		}
+	}
+
+	// The profile is in effect if it last started no longer than its
+	// duration ago.
+	start := schedule.Next(now.In(location).Add(-profile.Duration.Duration))
+	return !start.After(now), nil
+}
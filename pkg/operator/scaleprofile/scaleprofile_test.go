@@ -0,0 +1,114 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaleprofile
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestActive(t *testing.T) {
+	profiles := []planetscalev2.VitessScaleProfile{
+		{
+			Name:     "launch",
+			Schedule: "0 0 4 3 *",
+			Duration: metav1.Duration{Duration: 24 * time.Hour},
+			Replicas: 10,
+		},
+		{
+			Name:     "business-hours",
+			Schedule: "0 9 * * 1-5",
+			Duration: metav1.Duration{Duration: 12 * time.Hour},
+			TimeZone: "America/New_York",
+			Replicas: 6,
+		},
+	}
+
+	table := []struct {
+		name     string
+		profiles []planetscalev2.VitessScaleProfile
+		now      string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "no profiles",
+			now:  "2024-03-05T15:00:00Z",
+		},
+		{
+			name:     "business hours",
+			profiles: profiles,
+			now:      "2024-03-05T15:00:00Z", // Tuesday 10:00 in New York
+			want:     "business-hours",
+		},
+		{
+			name:     "overnight",
+			profiles: profiles,
+			now:      "2024-03-06T03:00:00Z", // Tuesday 22:00 in New York
+		},
+		{
+			name:     "weekend",
+			profiles: profiles,
+			now:      "2024-03-09T15:00:00Z", // Saturday 10:00 in New York
+		},
+		{
+			name:     "first profile wins",
+			profiles: profiles,
+			now:      "2024-03-04T15:00:00Z", // Monday 10:00 in New York
+			want:     "launch",
+		},
+		{
+			name: "invalid schedule",
+			profiles: []planetscalev2.VitessScaleProfile{
+				{Name: "bad", Schedule: "whenever", Duration: metav1.Duration{Duration: time.Hour}},
+			},
+			now:     "2024-03-05T15:00:00Z",
+			wantErr: true,
+		},
+		{
+			name: "invalid duration",
+			profiles: []planetscalev2.VitessScaleProfile{
+				{Name: "bad", Schedule: "0 9 * * *"},
+			},
+			now:     "2024-03-05T15:00:00Z",
+			wantErr: true,
+		},
+	}
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			now, err := time.Parse(time.RFC3339, test.now)
+			if err != nil {
+				t.Fatalf("can't parse time: %v", err)
+			}
+			got, err := Active(test.profiles, now)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("Active() error = %v, wantErr = %v", err, test.wantErr)
+			}
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != test.want {
+				t.Errorf("Active() = %q, want %q", gotName, test.want)
+			}
+		})
+	}
+}
@@ -58,6 +58,9 @@ func updateDiskSize(dst, src *planetscalev2.VitessKeyspaceTemplate) {
 
 				updateTabletPoolDiskSize(dstShard.TabletPools, srcShard.TabletPools)
 			}
+			if dstPartitioning.Custom.ShardTemplate != nil && srcPartitioning.Custom.ShardTemplate != nil {
+				updateTabletPoolDiskSize(dstPartitioning.Custom.ShardTemplate.TabletPools, srcPartitioning.Custom.ShardTemplate.TabletPools)
+			}
 		}
 	}
 }
@@ -22,6 +22,7 @@ const (
 
 	s3BackupStorageImplementationName = "s3"
 	s3AuthDirName                     = "s3-backup-auth"
+	s3CADirName                       = "s3-backup-ca"
 
 	azblobBackupStorageImplementationName = "azblob"
 	azblobAuthDirName                     = "azblob-backup-auth"
@@ -32,4 +33,6 @@ const (
 	fileBackupStorageImplementationName = "file"
 	fileBackupStorageVolumeName         = "vitess-backups"
 	fileBackupStorageMountPath          = "/vt/backups"
+
+	mysqlShellDirName = "mysqlshell"
 )
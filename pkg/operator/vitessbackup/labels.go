@@ -19,6 +19,9 @@ package vitessbackup
 const (
 	// LocationLabel is the label key for the backup storage location name.
 	LocationLabel = "backup.planetscale.com/location"
+	// StorageLabel is the label key for the name of the VitessBackupStorage
+	// object that a VitessBackup object belongs to.
+	StorageLabel = "backup.planetscale.com/storage"
 	// TypeLabel is the label key for the type of a backup.
 	TypeLabel = "backup.planetscale.com/type"
 
@@ -26,4 +29,21 @@ const (
 	TypeInit = "init"
 	// TypeUpdate is a backup taken to update the latest backup for a shard.
 	TypeUpdate = "update"
+	// TypeVerify is a restore of an existing backup to check that it's usable.
+	TypeVerify = "verify"
+	// TypeRequest is a backup requested on demand with RequestAnnotation.
+	TypeRequest = "request"
+	// TypeReplicate is a copy of an existing backup to another location.
+	TypeReplicate = "replicate"
+
+	// RequestAnnotation is the annotation key on a VitessShard or
+	// VitessKeyspace to request an on-demand backup of the shard, or of every
+	// shard in the keyspace. The value is an arbitrary ID chosen by the
+	// requester. Setting a new ID requests another backup, and removing the
+	// annotation cleans up the vtbackup Pods of the last request.
+	RequestAnnotation = "backup.planetscale.com/request"
+
+	// VerifiedBackupAnnotation is the annotation key on a backup verification
+	// Pod for the name of the VitessBackup object it's verifying.
+	VerifiedBackupAnnotation = "backup.planetscale.com/verified-backup"
 )
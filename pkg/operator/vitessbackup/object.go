@@ -18,6 +18,7 @@ package vitessbackup
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -64,6 +65,15 @@ func StorageObjectName(clusterName, backupLocationName string) string {
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, backupLocationName)
 }
 
+// KeyspaceStorageObjectName returns the name for a VitessBackupStorage object
+// for a backup location that's overridden by a keyspace.
+func KeyspaceStorageObjectName(clusterName, keyspaceName, backupLocationName string) string {
+	if backupLocationName == "" {
+		return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, "backup")
+	}
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, backupLocationName, "backup")
+}
+
 // ParseBackupName parses the name given by Vitess to each backup.
 func ParseBackupName(name string) (time.Time, *topodatapb.TabletAlias, error) {
 	// Backup names are formatted as "date.time.tablet-alias".
@@ -109,3 +119,45 @@ func CompleteBackups(backups []planetscalev2.VitessBackup) []*planetscalev2.Vite
 	}
 	return completeBackups
 }
+
+// ExpiredBackups returns the backups from the input, which must all belong to
+// the same shard and storage location, that fall outside the retention limits.
+//
+// The latest complete backup is never expired, and incomplete backups are only
+// expired by age, since they might still be in progress.
+func ExpiredBackups(backups []*planetscalev2.VitessBackup, retention *planetscalev2.VitessBackupRetentionSpec, now time.Time) []*planetscalev2.VitessBackup {
+	if retention == nil {
+		return nil
+	}
+
+	// Sort newest first.
+	sorted := make([]*planetscalev2.VitessBackup, len(backups))
+	copy(sorted, backups)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Status.StartTime.After(sorted[j].Status.StartTime.Time)
+	})
+
+	var expired []*planetscalev2.VitessBackup
+	completeCount := 0
+	for _, backup := range sorted {
+		tooOld := retention.MaxAge != nil && now.Sub(backup.Status.StartTime.Time) > retention.MaxAge.Duration
+
+		if !backup.Status.Complete {
+			if tooOld {
+				expired = append(expired, backup)
+			}
+			continue
+		}
+
+		completeCount++
+		if completeCount == 1 {
+			// Always keep the latest complete backup.
+			continue
+		}
+		tooMany := retention.MaxCount != nil && completeCount > int(*retention.MaxCount)
+		if tooOld || tooMany {
+			expired = append(expired, backup)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessbackup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestExpiredBackups(t *testing.T) {
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	backup := func(name string, daysAgo int, complete bool) *planetscalev2.VitessBackup {
+		return &planetscalev2.VitessBackup{
+			Status: planetscalev2.VitessBackupStatus{
+				StartTime:   metav1.NewTime(now.Add(-time.Duration(daysAgo) * 24 * time.Hour)),
+				Complete:    complete,
+				StorageName: name,
+			},
+		}
+	}
+	maxCount := func(n int32) *int32 { return &n }
+	maxAge := func(days int) *metav1.Duration {
+		return &metav1.Duration{Duration: time.Duration(days) * 24 * time.Hour}
+	}
+
+	backups := []*planetscalev2.VitessBackup{
+		backup("d8", 8, true),
+		backup("d1", 1, true),
+		backup("d0", 0, false),
+		backup("d5", 5, false),
+		backup("d3", 3, true),
+	}
+
+	table := []struct {
+		name      string
+		backups   []*planetscalev2.VitessBackup
+		retention *planetscalev2.VitessBackupRetentionSpec
+		want      []string
+	}{
+		{
+			name:      "no retention",
+			backups:   backups,
+			retention: nil,
+			want:      nil,
+		},
+		{
+			name:      "max count",
+			backups:   backups,
+			retention: &planetscalev2.VitessBackupRetentionSpec{MaxCount: maxCount(2)},
+			want:      []string{"d8"},
+		},
+		{
+			name:      "max age",
+			backups:   backups,
+			retention: &planetscalev2.VitessBackupRetentionSpec{MaxAge: maxAge(4)},
+			want:      []string{"d5", "d8"},
+		},
+		{
+			name:      "max count and age",
+			backups:   backups,
+			retention: &planetscalev2.VitessBackupRetentionSpec{MaxCount: maxCount(1), MaxAge: maxAge(6)},
+			want:      []string{"d3", "d8"},
+		},
+		{
+			name:      "latest complete backup is kept",
+			backups:   []*planetscalev2.VitessBackup{backup("d8", 8, true), backup("d0", 0, false)},
+			retention: &planetscalev2.VitessBackupRetentionSpec{MaxAge: maxAge(1)},
+			want:      nil,
+		},
+	}
+
+	for _, test := range table {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, backup := range ExpiredBackups(test.backups, test.retention, now) {
+				got = append(got, backup.Status.StorageName)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ExpiredBackups() = %v; want %v", got, test.want)
+			}
+		})
+	}
+}
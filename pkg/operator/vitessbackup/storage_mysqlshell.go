@@ -0,0 +1,60 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitessbackup
+
+import (
+	"strings"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+)
+
+// MySQLShellLocation returns where the mysqlshell backup engine should write
+// its dumps for the given backup storage location, along with any options
+// mysqlsh needs to reach that storage. The dumps are kept under their own
+// prefix, next to the Vitess backup manifests.
+//
+// It returns false if mysqlsh can't write to the storage type of the location.
+func MySQLShellLocation(backupLocation *planetscalev2.VitessBackupLocation, clusterName string) (string, map[string]interface{}, bool) {
+	switch {
+	case backupLocation.S3 != nil:
+		s3 := backupLocation.S3
+		options := map[string]interface{}{
+			"s3BucketName": s3.Bucket,
+			"s3Region":     s3.Region,
+		}
+		if len(s3.Endpoint) > 0 {
+			options["s3EndpointOverride"] = s3.Endpoint
+		}
+		if s3.AuthSecret != nil {
+			options["s3CredentialsFile"] = secrets.Mount(s3.AuthSecret, s3AuthDirName).FilePath()
+		}
+		return rootKeyPrefix(mysqlShellPrefix(s3.KeyPrefix), clusterName), options, true
+	case backupLocation.Volume != nil:
+		return rootKeyPrefix(mysqlShellPrefix(fileBackupStorageMountPath), clusterName), nil, true
+	}
+	return "", nil, false
+}
+
+// mysqlShellPrefix returns the prefix for mysqlsh dumps within a user prefix.
+func mysqlShellPrefix(userPrefix string) string {
+	userPrefix = strings.TrimRight(userPrefix, "/")
+	if userPrefix == "" {
+		return mysqlShellDirName
+	}
+	return userPrefix + "/" + mysqlShellDirName
+}
@@ -39,29 +39,44 @@ func s3BackupFlags(s3 *planetscalev2.S3BackupLocation, clusterName string) vites
 }
 
 func s3BackupVolumes(s3 *planetscalev2.S3BackupLocation) []corev1.Volume {
-	if s3.AuthSecret == nil {
-		return nil
+	var volumes []corev1.Volume
+	if s3.AuthSecret != nil {
+		volumes = append(volumes, secrets.Mount(s3.AuthSecret, s3AuthDirName).PodVolumes()...)
 	}
-	return secrets.Mount(s3.AuthSecret, s3AuthDirName).PodVolumes()
+	if s3.CASecret != nil {
+		volumes = append(volumes, secrets.Mount(s3.CASecret, s3CADirName).PodVolumes()...)
+	}
+	return volumes
 }
 
 func s3BackupVolumeMounts(s3 *planetscalev2.S3BackupLocation) []corev1.VolumeMount {
-	if s3.AuthSecret == nil {
-		return nil
+	var mounts []corev1.VolumeMount
+	if s3.AuthSecret != nil {
+		mounts = append(mounts, secrets.Mount(s3.AuthSecret, s3AuthDirName).ContainerVolumeMount())
 	}
-	return []corev1.VolumeMount{
-		secrets.Mount(s3.AuthSecret, s3AuthDirName).ContainerVolumeMount(),
+	if s3.CASecret != nil {
+		mounts = append(mounts, secrets.Mount(s3.CASecret, s3CADirName).ContainerVolumeMount())
 	}
+	return mounts
 }
 
 func s3BackupEnvVars(s3 *planetscalev2.S3BackupLocation) []corev1.EnvVar {
-	if s3.AuthSecret == nil {
-		return nil
-	}
-	return []corev1.EnvVar{
-		{
+	var env []corev1.EnvVar
+	if s3.AuthSecret != nil {
+		env = append(env, corev1.EnvVar{
 			Name:  "AWS_SHARED_CREDENTIALS_FILE",
 			Value: secrets.Mount(s3.AuthSecret, s3AuthDirName).FilePath(),
-		},
+		})
+	}
+	if s3.CASecret != nil {
+		// Vitess gives the S3 client its own TLS config, which ignores
+		// AWS_CA_BUNDLE. Instead, we add the custom CA to the directories
+		// that Go loads system roots from. The system's default CA bundle
+		// file is still loaded, since SSL_CERT_FILE is left unset.
+		env = append(env, corev1.EnvVar{
+			Name:  "SSL_CERT_DIR",
+			Value: secrets.Mount(s3.CASecret, s3CADirName).DirPath(),
+		})
 	}
+	return env
 }
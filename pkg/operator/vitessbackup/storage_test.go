@@ -18,6 +18,8 @@ package vitessbackup
 
 import (
 	"testing"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 )
 
 func TestRootKeyPrefix(t *testing.T) {
@@ -31,3 +33,25 @@ func TestRootKeyPrefix(t *testing.T) {
 		t.Errorf("rootKeyPrefix() = %v; want %v", got, want)
 	}
 }
+
+func TestS3BackupCASecret(t *testing.T) {
+	s3 := &planetscalev2.S3BackupLocation{
+		Region:   "us-east-1",
+		Bucket:   "backups",
+		Endpoint: "minio.example.com:9000",
+		CASecret: &planetscalev2.SecretSource{Name: "minio-ca", Key: "ca.crt"},
+	}
+
+	volumes := s3BackupVolumes(s3)
+	if len(volumes) != 1 || volumes[0].Secret == nil || volumes[0].Secret.SecretName != "minio-ca" {
+		t.Errorf("s3BackupVolumes() = %v; want one volume for Secret minio-ca", volumes)
+	}
+	mounts := s3BackupVolumeMounts(s3)
+	if len(mounts) != 1 || mounts[0].MountPath != "/vt/secrets/s3-backup-ca" {
+		t.Errorf("s3BackupVolumeMounts() = %v; want one mount at /vt/secrets/s3-backup-ca", mounts)
+	}
+	env := s3BackupEnvVars(s3)
+	if len(env) != 1 || env[0].Name != "SSL_CERT_DIR" || env[0].Value != "/vt/secrets/s3-backup-ca" {
+		t.Errorf("s3BackupEnvVars() = %v; want SSL_CERT_DIR=/vt/secrets/s3-backup-ca", env)
+	}
+}
@@ -20,6 +20,20 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/names"
 )
 
+const (
+	// AutoReshardApprovalAnnotation is the annotation key on a
+	// VitessKeyspace that approves the shard split proposed by its
+	// autoReshard policy, when the policy requires approval. The value must
+	// be the name of the proposal.
+	AutoReshardApprovalAnnotation = "planetscale.com/approve-auto-reshard"
+
+	// DecommissionAnnotation is the annotation key that the VitessCluster
+	// controller sets on a VitessKeyspace that was removed from the cluster
+	// spec with the Decommission turndown policy. The value is the Unix time
+	// at which the decommission was requested.
+	DecommissionAnnotation = "planetscale.com/decommission"
+)
+
 // Name returns the VitessKeyspace metadata.name for a given keyspace.
 func Name(clusterName, keyspaceName string) string {
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName)
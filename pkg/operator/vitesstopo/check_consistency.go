@@ -0,0 +1,222 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesstopo
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/topotools"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+)
+
+type CheckConsistencyParams struct {
+	// EventObj holds the object type that the recorder will use when writing events.
+	EventObj   runtime.Object
+	TopoServer *topo.Server
+	Recorder   record.EventRecorder
+	// Cells is the set of cells the cluster deploys to.
+	Cells sets.String
+	// Keyspaces is the set of keyspaces the cluster deploys.
+	Keyspaces sets.String
+	// Shards is the set of shards that are managed by a VitessShard,
+	// in "keyspace/shard" form.
+	Shards sets.String
+	// PruneTablets is whether to delete tablet records that belong to shards
+	// no VitessShard manages anymore, rather than just reporting them.
+	PruneTablets bool
+	// RepairServingGraph is whether to rebuild missing SrvKeyspace and
+	// SrvVSchema records, rather than just reporting them.
+	RepairServingGraph bool
+}
+
+// ConsistencyReport is the outcome of CheckConsistency.
+type ConsistencyReport struct {
+	// Repaired is the number of records that were pruned or rebuilt.
+	Repaired int32
+	// Drift lists the records that are out of line and weren't repaired.
+	Drift []planetscalev2.TopologyDrift
+}
+
+func (r *ConsistencyReport) addDrift(kind planetscalev2.TopologyRecordKind, name, format string, args ...interface{}) {
+	r.Drift = append(r.Drift, planetscalev2.TopologyDrift{
+		Kind:    kind,
+		Name:    name,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// CheckConsistency compares topo records against the cells, keyspaces and
+// shards that the operator manages.
+//
+// Tablet records in managed cells and keyspaces that belong to a shard with no
+// VitessShard are left over from tablet Pods that were deleted along with
+// their shard, so they're pruned if allowed. Tablets of managed shards are
+// left to the VitessShard controller, which knows which ones are desired.
+// SrvKeyspace and SrvVSchema records that are missing in a cell where a
+// managed shard has tablets are rebuilt if allowed. Anything else that's out of
+// line is only reported, since it may be managed by something else.
+//
+// An error is returned only if the check itself couldn't be completed.
+func CheckConsistency(ctx context.Context, p CheckConsistencyParams) (*ConsistencyReport, error) {
+	report := &ConsistencyReport{}
+	ts := p.TopoServer
+
+	collationEnv, parser, err := environment.CollationEnvAndParser()
+	if err != nil {
+		return nil, err
+	}
+	// We use the Vitess wrangler (multi-step command executor) to delete tablets.
+	// This is equivalent to `vtctl DeleteTablet`.
+	wr := wrangler.New(logutil.NewConsoleLogger(), ts, nil, collationEnv, parser)
+
+	// Check that all desired cells are registered.
+	cellNames, err := ts.GetCellInfoNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cells: %w", err)
+	}
+	registeredCells := sets.NewString(cellNames...)
+	for _, cell := range p.Cells.List() {
+		if !registeredCells.Has(cell) {
+			report.addDrift(planetscalev2.TopologyCellRecord, cell, "cell is not registered in topology")
+		}
+	}
+
+	// Check that every shard record of a managed keyspace has a VitessShard.
+	for _, keyspace := range p.Keyspaces.List() {
+		shardNames, err := ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			if topo.IsErrType(err, topo.NoNode) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list shards in keyspace %v: %w", keyspace, err)
+		}
+		for _, shard := range shardNames {
+			name := keyspace + "/" + shard
+			if !p.Shards.Has(name) {
+				report.addDrift(planetscalev2.TopologyShardRecord, name, "no VitessShard manages this shard")
+			}
+		}
+	}
+
+	for _, cell := range p.Cells.List() {
+		if !registeredCells.Has(cell) {
+			continue
+		}
+		tablets, err := ts.GetTabletsByCell(ctx, cell, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tablets in cell %v: %w", cell, err)
+		}
+
+		// Keep track of which keyspaces have tablets of managed shards in
+		// this cell, since those need serving graph records here.
+		servingKeyspaces := sets.NewString()
+		for _, tablet := range tablets {
+			if !p.Keyspaces.Has(tablet.Keyspace) {
+				continue
+			}
+			shardName := tablet.Keyspace + "/" + tablet.Shard
+			if p.Shards.Has(shardName) {
+				servingKeyspaces.Insert(tablet.Keyspace)
+				continue
+			}
+
+			alias := topoproto.TabletAliasString(tablet.Alias)
+			if !p.PruneTablets {
+				report.addDrift(planetscalev2.TopologyTabletRecord, alias, "tablet belongs to shard %v, which no VitessShard manages", shardName)
+				continue
+			}
+			if err := wr.DeleteTablet(ctx, tablet.Alias, false /* allowPrimary */); err != nil && !topo.IsErrType(err, topo.NoNode) {
+				p.Recorder.Eventf(p.EventObj, corev1.EventTypeWarning, "TopoCleanupFailed", "unable to remove orphaned tablet %s from topology: %v", alias, err)
+				report.addDrift(planetscalev2.TopologyTabletRecord, alias, "tablet belongs to shard %v, which no VitessShard manages, and pruning it failed: %v", shardName, err)
+				continue
+			}
+			p.Recorder.Eventf(p.EventObj, corev1.EventTypeNormal, "TopoCleanup", "removed orphaned tablet %s from topology", alias)
+			report.Repaired++
+		}
+
+		if err := checkServingGraph(ctx, p, cell, servingKeyspaces, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// checkServingGraph checks that a cell has the SrvKeyspace records for the
+// given keyspaces, as well as a SrvVSchema record if there are any.
+func checkServingGraph(ctx context.Context, p CheckConsistencyParams, cell string, keyspaces sets.String, report *ConsistencyReport) error {
+	ts := p.TopoServer
+
+	for _, keyspace := range keyspaces.List() {
+		_, err := ts.GetSrvKeyspace(ctx, cell, keyspace)
+		if err == nil {
+			continue
+		}
+		if !topo.IsErrType(err, topo.NoNode) {
+			return fmt.Errorf("failed to get SrvKeyspace for keyspace %v in cell %v: %w", keyspace, cell, err)
+		}
+
+		name := cell + "/" + keyspace
+		if !p.RepairServingGraph {
+			report.addDrift(planetscalev2.TopologySrvKeyspaceRecord, name, "SrvKeyspace is missing")
+			continue
+		}
+		// This is equivalent to `vtctl RebuildKeyspaceGraph -cells=<cell>`.
+		if err := topotools.RebuildKeyspace(ctx, logutil.NewConsoleLogger(), ts, keyspace, []string{cell}, false /* allowPartial */); err != nil {
+			p.Recorder.Eventf(p.EventObj, corev1.EventTypeWarning, "TopoRepairFailed", "unable to rebuild SrvKeyspace for keyspace %s in cell %s: %v", keyspace, cell, err)
+			report.addDrift(planetscalev2.TopologySrvKeyspaceRecord, name, "SrvKeyspace is missing, and rebuilding it failed: %v", err)
+			continue
+		}
+		p.Recorder.Eventf(p.EventObj, corev1.EventTypeNormal, "TopoRepair", "rebuilt missing SrvKeyspace for keyspace %s in cell %s", keyspace, cell)
+		report.Repaired++
+	}
+
+	if keyspaces.Len() == 0 {
+		return nil
+	}
+	_, err := ts.GetSrvVSchema(ctx, cell)
+	if err == nil {
+		return nil
+	}
+	if !topo.IsErrType(err, topo.NoNode) {
+		return fmt.Errorf("failed to get SrvVSchema in cell %v: %w", cell, err)
+	}
+	if !p.RepairServingGraph {
+		report.addDrift(planetscalev2.TopologySrvVSchemaRecord, cell, "SrvVSchema is missing")
+		return nil
+	}
+	// This is equivalent to `vtctl RebuildVSchemaGraph -cells=<cell>`.
+	if err := ts.RebuildSrvVSchema(ctx, []string{cell}); err != nil {
+		p.Recorder.Eventf(p.EventObj, corev1.EventTypeWarning, "TopoRepairFailed", "unable to rebuild SrvVSchema in cell %s: %v", cell, err)
+		report.addDrift(planetscalev2.TopologySrvVSchemaRecord, cell, "SrvVSchema is missing, and rebuilding it failed: %v", err)
+		return nil
+	}
+	p.Recorder.Eventf(p.EventObj, corev1.EventTypeNormal, "TopoRepair", "rebuilt missing SrvVSchema in cell %s", cell)
+	report.Repaired++
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vitesstopo
+
+import (
+	"context"
+	"fmt"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/helpers"
+
+	"planetscale.dev/vitess-operator/pkg/operator/environment"
+)
+
+// CopyTopology copies the records Vitess components need to serve from one
+// topo server to another. This is equivalent to running topo2topo for
+// keyspaces, shards, shard replications, tablets and routing rules, followed
+// by a copy of the serving graph (SrvKeyspace and SrvVSchema) of each cell.
+//
+// The cells must already be registered in the destination, since that's
+// where it looks up the local topo server of each cell. Cells that have their
+// own local topo server share it between source and destination, in which
+// case their records are simply rewritten in place.
+//
+// Existing records in the destination are updated, except for keyspace
+// records, which Vitess only creates if they're missing.
+func CopyTopology(ctx context.Context, fromTS, toTS *topo.Server) error {
+	_, parser, err := environment.CollationEnvAndParser()
+	if err != nil {
+		return err
+	}
+
+	if err := helpers.CopyKeyspaces(ctx, fromTS, toTS, parser); err != nil {
+		return err
+	}
+	if err := helpers.CopyShards(ctx, fromTS, toTS); err != nil {
+		return err
+	}
+	if err := helpers.CopyShardReplications(ctx, fromTS, toTS); err != nil {
+		return err
+	}
+	if err := helpers.CopyTablets(ctx, fromTS, toTS); err != nil {
+		return err
+	}
+	if err := helpers.CopyRoutingRules(ctx, fromTS, toTS); err != nil {
+		return err
+	}
+	return copyServingGraph(ctx, fromTS, toTS)
+}
+
+// copyServingGraph copies the SrvKeyspace and SrvVSchema records of each cell,
+// so vtgates that start against the destination can route right away instead
+// of waiting for the next rebuild of the serving graph.
+func copyServingGraph(ctx context.Context, fromTS, toTS *topo.Server) error {
+	cells, err := fromTS.GetCellInfoNames(ctx)
+	if err != nil {
+		return fmt.Errorf("GetCellInfoNames: %w", err)
+	}
+
+	for _, cell := range cells {
+		keyspaces, err := fromTS.GetSrvKeyspaceNames(ctx, cell)
+		if err != nil {
+			return fmt.Errorf("GetSrvKeyspaceNames(%v): %w", cell, err)
+		}
+		for _, keyspace := range keyspaces {
+			srvKeyspace, err := fromTS.GetSrvKeyspace(ctx, cell, keyspace)
+			if err != nil {
+				return fmt.Errorf("GetSrvKeyspace(%v, %v): %w", cell, keyspace, err)
+			}
+			if err := toTS.UpdateSrvKeyspace(ctx, cell, keyspace, srvKeyspace); err != nil {
+				return fmt.Errorf("UpdateSrvKeyspace(%v, %v): %w", cell, keyspace, err)
+			}
+		}
+
+		srvVSchema, err := fromTS.GetSrvVSchema(ctx, cell)
+		switch {
+		case err == nil:
+			if err := toTS.UpdateSrvVSchema(ctx, cell, srvVSchema); err != nil {
+				return fmt.Errorf("UpdateSrvVSchema(%v): %w", cell, err)
+			}
+		case topo.IsErrType(err, topo.NoNode):
+			// Nothing to do.
+		default:
+			return fmt.Errorf("GetSrvVSchema(%v): %w", cell, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"fmt"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// SwitchTrafficApprovalAnnotation is the annotation key on a
+	// VitessReshard or VitessMoveTables that approves switching traffic when
+	// the spec requires approval. The value must be "true".
+	SwitchTrafficApprovalAnnotation = "planetscale.com/approve-switch-traffic"
+)
+
+// SwitchTrafficWaitReason returns what a workflow that's ready to switch
+// traffic is still waiting for, or an empty string if traffic may be switched.
+func SwitchTrafficWaitReason(mode planetscalev2.VitessSwitchTrafficMode, approvalRequired, approved bool, annotations map[string]string) string {
+	if mode != planetscalev2.SwitchTrafficAuto {
+		return "Waiting for switchTraffic to be set to Auto."
+	}
+	if approvalRequired && !approved && annotations[SwitchTrafficApprovalAnnotation] != "true" {
+		return fmt.Sprintf("Waiting for approval to switch traffic. Set approved to true, or set the annotation %v=true to approve.", SwitchTrafficApprovalAnnotation)
+	}
+	return ""
+}
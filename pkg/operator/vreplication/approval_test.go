@@ -0,0 +1,39 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestSwitchTrafficWaitReason(t *testing.T) {
+	approve := map[string]string{SwitchTrafficApprovalAnnotation: "true"}
+
+	assert.Equal(t, "", SwitchTrafficWaitReason(planetscalev2.SwitchTrafficAuto, false, false, nil))
+	assert.Contains(t, SwitchTrafficWaitReason(planetscalev2.SwitchTrafficManual, false, false, nil), "switchTraffic")
+	// Approval doesn't override the Manual mode.
+	assert.Contains(t, SwitchTrafficWaitReason(planetscalev2.SwitchTrafficManual, true, true, approve), "switchTraffic")
+
+	assert.Contains(t, SwitchTrafficWaitReason(planetscalev2.SwitchTrafficAuto, true, false, nil), "Waiting for approval")
+	assert.Contains(t, SwitchTrafficWaitReason(planetscalev2.SwitchTrafficAuto, true, false, map[string]string{SwitchTrafficApprovalAnnotation: "yes"}), "Waiting for approval")
+	assert.Equal(t, "", SwitchTrafficWaitReason(planetscalev2.SwitchTrafficAuto, true, true, nil))
+	assert.Equal(t, "", SwitchTrafficWaitReason(planetscalev2.SwitchTrafficAuto, true, false, approve))
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package vreplication summarizes the state of VReplication workflows and
+VDiffs, as reported by vtctld.
+*/
+package vreplication
+
+import (
+	"encoding/json"
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+const (
+	// VReplication stream states, as reported by WorkflowStatus.
+	streamCopying = "Copying"
+	streamRunning = "Running"
+	streamError   = "Error"
+
+	// vdiffCompleted is the state of a VDiff, and of each of its tables,
+	// once it's done comparing.
+	vdiffCompleted = "completed"
+)
+
+// CopyProgress summarizes the streams of a VReplication workflow.
+type CopyProgress struct {
+	// Percent is how much of the existing rows were copied, from 0 to 100.
+	Percent int
+	// Copying is whether any stream is still copying existing rows.
+	Copying bool
+	// Running is whether every stream is replicating changes.
+	Running bool
+	// Errors are the messages of streams that failed, sorted by shard.
+	Errors []string
+}
+
+// WorkflowCopyProgress summarizes a WorkflowStatus response.
+func WorkflowCopyProgress(resp *vtctldatapb.WorkflowStatusResponse) CopyProgress {
+	progress := CopyProgress{Percent: 100}
+
+	var copied, total int64
+	for _, state := range resp.TableCopyState {
+		copied += state.RowsCopied
+		total += state.RowsTotal
+	}
+	if len(resp.TableCopyState) > 0 {
+		progress.Copying = true
+		if total > 0 {
+			progress.Percent = int(copied * 100 / total)
+			if progress.Percent > 99 {
+				// Don't claim we're done while tables are still copying.
+				progress.Percent = 99
+			}
+		} else {
+			progress.Percent = 0
+		}
+	}
+
+	shards := make([]string, 0, len(resp.ShardStreams))
+	for shard := range resp.ShardStreams {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	streams := 0
+	running := 0
+	for _, shard := range shards {
+		for _, stream := range resp.ShardStreams[shard].GetStreams() {
+			streams++
+			switch stream.Status {
+			case streamCopying:
+				progress.Copying = true
+			case streamRunning:
+				running++
+			case streamError:
+				progress.Errors = append(progress.Errors, shard+": "+stream.Info)
+			}
+		}
+	}
+	progress.Running = streams > 0 && running == streams
+	return progress
+}
+
+// VDiffOutcome summarizes the results of a VDiff.
+type VDiffOutcome struct {
+	// Completed is whether every shard finished comparing every table.
+	Completed bool
+	// Mismatch is whether any table differs between source and target.
+	Mismatch bool
+	// LastError is the last error that any shard reported, if any.
+	LastError string
+	// Tables is the number of tables being compared.
+	Tables int
+	// RowsCompared is how many rows were compared so far, in all tables.
+	RowsCompared int64
+	// TableDiffs are the differences found in each table that has any,
+	// sorted by table name.
+	TableDiffs []TableDiff
+}
+
+// TableDiff counts the differences that a VDiff found in a table, across
+// all shards.
+type TableDiff struct {
+	Table           string
+	MismatchedRows  int64
+	ExtraSourceRows int64
+	ExtraTargetRows int64
+}
+
+// vdiffTableReport is the part of the JSON report of each table in a VDiff
+// that counts differences.
+type vdiffTableReport struct {
+	MismatchedRows  int64
+	ExtraRowsSource int64
+	ExtraRowsTarget int64
+}
+
+// VDiffResult summarizes a VDiffShow response for a single VDiff.
+func VDiffResult(resp *vtctldatapb.VDiffShowResponse) VDiffOutcome {
+	outcome := VDiffOutcome{Completed: len(resp.TabletResponses) > 0}
+	tables := map[string]struct{}{}
+	diffs := map[string]*TableDiff{}
+
+	shards := make([]string, 0, len(resp.TabletResponses))
+	for shard := range resp.TabletResponses {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	for _, shard := range shards {
+		tabletResp := resp.TabletResponses[shard]
+		if tabletResp == nil || tabletResp.Output == nil {
+			outcome.Completed = false
+			continue
+		}
+		rows := sqltypes.Proto3ToResult(tabletResp.Output).Named().Rows
+		if len(rows) == 0 {
+			outcome.Completed = false
+		}
+		for _, row := range rows {
+			if row.AsString("vdiff_state", "") != vdiffCompleted {
+				outcome.Completed = false
+			}
+			if lastError := row.AsString("last_error", ""); lastError != "" {
+				outcome.LastError = shard + ": " + lastError
+			}
+			table := row.AsString("table_name", "")
+			if table == "" {
+				// The VDiff hasn't started on any table yet.
+				outcome.Completed = false
+				continue
+			}
+			tables[table] = struct{}{}
+			if row.AsString("table_state", "") != vdiffCompleted {
+				outcome.Completed = false
+			}
+			if row.AsInt64("has_mismatch", 0) == 1 {
+				outcome.Mismatch = true
+			}
+			outcome.RowsCompared += row.AsInt64("rows_compared", 0)
+
+			var report vdiffTableReport
+			if err := json.Unmarshal([]byte(row.AsString("report", "")), &report); err != nil {
+				// There's no report until the table has been compared.
+				continue
+			}
+			if report.MismatchedRows == 0 && report.ExtraRowsSource == 0 && report.ExtraRowsTarget == 0 {
+				continue
+			}
+			diff := diffs[table]
+			if diff == nil {
+				diff = &TableDiff{Table: table}
+				diffs[table] = diff
+			}
+			diff.MismatchedRows += report.MismatchedRows
+			diff.ExtraSourceRows += report.ExtraRowsSource
+			diff.ExtraTargetRows += report.ExtraRowsTarget
+		}
+	}
+	outcome.Tables = len(tables)
+	for _, diff := range diffs {
+		outcome.TableDiffs = append(outcome.TableDiffs, *diff)
+	}
+	sort.Slice(outcome.TableDiffs, func(i, j int) bool {
+		return outcome.TableDiffs[i].Table < outcome.TableDiffs[j].Table
+	})
+	return outcome
+}
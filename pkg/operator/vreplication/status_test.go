@@ -0,0 +1,182 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"vitess.io/vitess/go/sqltypes"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestWorkflowCopyProgress(t *testing.T) {
+	streams := func(statuses ...string) map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams {
+		shardStreams := map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams{}
+		for i, status := range statuses {
+			shard := []string{"commerce/-40", "commerce/40-80"}[i]
+			shardStreams[shard] = &vtctldatapb.WorkflowStatusResponse_ShardStreams{
+				Streams: []*vtctldatapb.WorkflowStatusResponse_ShardStreamState{{Status: status, Info: "boom"}},
+			}
+		}
+		return shardStreams
+	}
+
+	tests := []struct {
+		name string
+		resp *vtctldatapb.WorkflowStatusResponse
+		want CopyProgress
+	}{
+		{
+			name: "copying",
+			resp: &vtctldatapb.WorkflowStatusResponse{
+				TableCopyState: map[string]*vtctldatapb.WorkflowStatusResponse_TableCopyState{
+					"customer": {RowsCopied: 30, RowsTotal: 100},
+					"corder":   {RowsCopied: 20, RowsTotal: 100},
+				},
+				ShardStreams: streams(streamCopying, streamCopying),
+			},
+			want: CopyProgress{Percent: 25, Copying: true},
+		},
+		{
+			name: "row estimates are low",
+			resp: &vtctldatapb.WorkflowStatusResponse{
+				TableCopyState: map[string]*vtctldatapb.WorkflowStatusResponse_TableCopyState{
+					"customer": {RowsCopied: 120, RowsTotal: 100},
+				},
+				ShardStreams: streams(streamCopying, streamRunning),
+			},
+			want: CopyProgress{Percent: 99, Copying: true},
+		},
+		{
+			name: "running",
+			resp: &vtctldatapb.WorkflowStatusResponse{ShardStreams: streams(streamRunning, streamRunning)},
+			want: CopyProgress{Percent: 100, Running: true},
+		},
+		{
+			name: "error",
+			resp: &vtctldatapb.WorkflowStatusResponse{ShardStreams: streams(streamRunning, streamError)},
+			want: CopyProgress{Percent: 100, Errors: []string{"commerce/40-80: boom"}},
+		},
+		{
+			name: "no streams",
+			resp: &vtctldatapb.WorkflowStatusResponse{},
+			want: CopyProgress{Percent: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WorkflowCopyProgress(tt.resp))
+		})
+	}
+}
+
+func TestVDiffResult(t *testing.T) {
+	output := func(rows ...string) *tabletmanagerdatapb.VDiffResponse {
+		result := sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("vdiff_state|last_error|table_name|table_state|has_mismatch", "varchar|varchar|varchar|varchar|int64"),
+			rows...,
+		)
+		return &tabletmanagerdatapb.VDiffResponse{Output: sqltypes.ResultToProto3(result)}
+	}
+
+	tests := []struct {
+		name string
+		resp *vtctldatapb.VDiffShowResponse
+		want VDiffOutcome
+	}{
+		{
+			name: "running",
+			resp: &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+				"commerce/-40":   output("started||customer|started|0", "started||corder|completed|0"),
+				"commerce/40-80": output("completed||customer|completed|0", "completed||corder|completed|0"),
+			}},
+			want: VDiffOutcome{Tables: 2},
+		},
+		{
+			name: "not started",
+			resp: &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+				"commerce/-40": output("pending||||0"),
+			}},
+			want: VDiffOutcome{},
+		},
+		{
+			name: "match",
+			resp: &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+				"commerce/-40":   output("completed||customer|completed|0"),
+				"commerce/40-80": output("completed||customer|completed|0"),
+			}},
+			want: VDiffOutcome{Completed: true, Tables: 1},
+		},
+		{
+			name: "mismatch",
+			resp: &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+				"commerce/-40": output("completed||customer|completed|1"),
+			}},
+			want: VDiffOutcome{Completed: true, Mismatch: true, Tables: 1},
+		},
+		{
+			name: "error",
+			resp: &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+				"commerce/-40": output("error|lost connection|customer|started|0"),
+			}},
+			want: VDiffOutcome{LastError: "commerce/-40: lost connection", Tables: 1},
+		},
+		{
+			name: "no responses",
+			resp: &vtctldatapb.VDiffShowResponse{},
+			want: VDiffOutcome{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, VDiffResult(tt.resp))
+		})
+	}
+}
+
+func TestVDiffResultTableDiffs(t *testing.T) {
+	output := func(rows ...string) *tabletmanagerdatapb.VDiffResponse {
+		result := sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("vdiff_state|last_error|table_name|table_state|has_mismatch|rows_compared|report", "varchar|varchar|varchar|varchar|int64|int64|varchar"),
+			rows...,
+		)
+		return &tabletmanagerdatapb.VDiffResponse{Output: sqltypes.ResultToProto3(result)}
+	}
+
+	resp := &vtctldatapb.VDiffShowResponse{TabletResponses: map[string]*tabletmanagerdatapb.VDiffResponse{
+		"commerce/-40": output(
+			`completed||customer|completed|1|100|{"TableName":"customer","MismatchedRows":2,"ExtraRowsSource":1}`,
+			`completed||corder|completed|0|50|{"TableName":"corder"}`,
+		),
+		"commerce/40-80": output(
+			`completed||customer|completed|1|80|{"TableName":"customer","MismatchedRows":1,"ExtraRowsTarget":3}`,
+			`completed||corder|completed|0|40|`,
+		),
+	}}
+	want := VDiffOutcome{
+		Completed:    true,
+		Mismatch:     true,
+		Tables:       2,
+		RowsCompared: 270,
+		TableDiffs: []TableDiff{
+			{Table: "customer", MismatchedRows: 3, ExtraSourceRows: 1, ExtraTargetRows: 3},
+		},
+	}
+	assert.Equal(t, want, VDiffResult(resp))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"vitess.io/vitess/go/protoutil"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/wrangler"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// vdiffFilteredReplicationWaitTime is how long VDiff waits for the target
+	// to catch up to the source before comparing them.
+	vdiffFilteredReplicationWaitTime = 30 * time.Second
+)
+
+// CreateVDiff starts a VDiff of the given workflow, and returns its UUID.
+func CreateVDiff(ctx context.Context, wr *wrangler.Wrangler, targetKeyspace, workflow string, tabletTypes []topodatapb.TabletType) (string, error) {
+	vdiffUUID := uuid.New().String()
+	_, err := wr.VtctldServer().VDiffCreate(ctx, &vtctldatapb.VDiffCreateRequest{
+		Workflow:                    workflow,
+		TargetKeyspace:              targetKeyspace,
+		Uuid:                        vdiffUUID,
+		TabletTypes:                 tabletTypes,
+		TabletSelectionPreference:   tabletmanagerdatapb.TabletSelectionPreference_INORDER,
+		FilteredReplicationWaitTime: protoutil.DurationToProto(vdiffFilteredReplicationWaitTime),
+		MaxDiffDuration:             protoutil.DurationToProto(0),
+		AutoRetry:                   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	return vdiffUUID, nil
+}
+
+// ShowVDiff gets the results of a VDiff of the given workflow so far.
+func ShowVDiff(ctx context.Context, wr *wrangler.Wrangler, targetKeyspace, workflow, vdiffUUID string) (VDiffOutcome, error) {
+	resp, err := wr.VtctldServer().VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+		Workflow:       workflow,
+		TargetKeyspace: targetKeyspace,
+		Arg:            vdiffUUID,
+	})
+	if err != nil {
+		return VDiffOutcome{}, err
+	}
+	return VDiffResult(resp), nil
+}
+
+// NextScheduledVDiff returns when the next VDiff is due according to a
+// schedule in standard cron format, given when the last one started.
+func NextScheduledVDiff(schedule string, last time.Time) (time.Time, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid VDiff schedule %q: %v", schedule, err)
+	}
+	return sched.Next(last), nil
+}
+
+// UpdateVDiffReport records the results of a VDiff so far in a report.
+// The report is marked complete once the VDiff has finished comparing
+// every table.
+func UpdateVDiffReport(report *planetscalev2.VitessVDiffReport, outcome VDiffOutcome, now time.Time) {
+	report.Mismatch = outcome.Mismatch
+	report.RowsCompared = outcome.RowsCompared
+	report.Message = outcome.LastError
+
+	report.Tables = nil
+	for _, diff := range outcome.TableDiffs {
+		report.Tables = append(report.Tables, planetscalev2.VitessVDiffTableReport{
+			Name:            diff.Table,
+			MismatchedRows:  diff.MismatchedRows,
+			ExtraSourceRows: diff.ExtraSourceRows,
+			ExtraTargetRows: diff.ExtraTargetRows,
+		})
+	}
+
+	if (outcome.Completed || outcome.Mismatch) && report.CompletionTime == nil {
+		report.CompletionTime = &metav1.Time{Time: now}
+	}
+}
+
+// VDiffTablesSummary lists the tables in which a VDiff found differences,
+// for use at the end of a sentence, or returns "" if it found none.
+func VDiffTablesSummary(report *planetscalev2.VitessVDiffReport) string {
+	if len(report.Tables) == 0 {
+		return ""
+	}
+	tables := make([]string, 0, len(report.Tables))
+	for _, table := range report.Tables {
+		tables = append(tables, table.Name)
+	}
+	return " in tables " + strings.Join(tables, ", ")
+}
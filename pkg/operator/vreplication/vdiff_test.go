@@ -0,0 +1,60 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNextScheduledVDiff(t *testing.T) {
+	last := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	next, err := NextScheduledVDiff("0 */6 * * *", last)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), next)
+	}
+
+	_, err = NextScheduledVDiff("every day", last)
+	assert.Error(t, err)
+}
+
+func TestUpdateVDiffReport(t *testing.T) {
+	now := time.Now()
+	report := &planetscalev2.VitessVDiffReport{UUID: "abc"}
+
+	// A running VDiff isn't complete yet.
+	UpdateVDiffReport(report, VDiffOutcome{RowsCompared: 10}, now)
+	assert.Nil(t, report.CompletionTime)
+	assert.Equal(t, int64(10), report.RowsCompared)
+
+	// Differences are reported as soon as they're found.
+	UpdateVDiffReport(report, VDiffOutcome{
+		Mismatch:     true,
+		RowsCompared: 20,
+		TableDiffs:   []TableDiff{{Table: "customer", MismatchedRows: 2}},
+	}, now)
+	if assert.NotNil(t, report.CompletionTime) {
+		assert.Equal(t, now, report.CompletionTime.Time)
+	}
+	assert.True(t, report.Mismatch)
+	assert.Equal(t, []planetscalev2.VitessVDiffTableReport{{Name: "customer", MismatchedRows: 2}}, report.Tables)
+	assert.Equal(t, " in tables customer", VDiffTablesSummary(report))
+}
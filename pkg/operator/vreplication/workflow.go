@@ -0,0 +1,39 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vreplication
+
+import (
+	"context"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/wrangler"
+)
+
+// WorkflowExists returns whether the named VReplication workflow exists in
+// the given target keyspace.
+func WorkflowExists(ctx context.Context, wr *wrangler.Wrangler, keyspace, workflow string) (bool, error) {
+	resp, err := wr.VtctldServer().GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: keyspace})
+	if err != nil {
+		return false, err
+	}
+	for _, wf := range resp.Workflows {
+		if wf.Name == workflow {
+			return true, nil
+		}
+	}
+	return false, nil
+}
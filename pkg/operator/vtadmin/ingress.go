@@ -0,0 +1,106 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtadmin
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// apiPathPrefix is the path under which vtadmin-api serves its API.
+const apiPathPrefix = "/api"
+
+// IngressName returns the name of the vtadmin Ingress for a cluster.
+func IngressName(clusterName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VtadminComponentName)
+}
+
+// IngressAddress returns the address at which clients outside the
+// Kubernetes cluster reach vtadmin through the Ingress.
+func IngressAddress(ingress *planetscalev2.VtAdminIngress) string {
+	if ingress.TLSSecretName != "" {
+		return "https://" + ingress.Host
+	}
+	return "http://" + ingress.Host
+}
+
+// NewIngress creates a new Ingress object for vtadmin.
+func NewIngress(key client.ObjectKey, labels map[string]string, serviceName string, ingress *planetscalev2.VtAdminIngress) *networkingv1.Ingress {
+	// Fill in the immutable parts.
+	obj := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	// Set everything else.
+	UpdateIngress(obj, labels, serviceName, ingress)
+	return obj
+}
+
+// UpdateIngress updates the mutable parts of the vtadmin Ingress.
+func UpdateIngress(obj *networkingv1.Ingress, labels map[string]string, serviceName string, ingress *planetscalev2.VtAdminIngress) {
+	update.Labels(&obj.Labels, labels)
+	update.Annotations(&obj.Annotations, ingress.Annotations)
+
+	pathType := networkingv1.PathTypePrefix
+	backend := func(portName string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: serviceName,
+				Port: networkingv1.ServiceBackendPort{Name: portName},
+			},
+		}
+	}
+
+	obj.Spec.IngressClassName = ingress.ClassName
+	obj.Spec.Rules = []networkingv1.IngressRule{
+		{
+			Host: ingress.Host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     apiPathPrefix,
+							PathType: &pathType,
+							Backend:  backend(planetscalev2.DefaultAPIPortName),
+						},
+						{
+							Path:     "/",
+							PathType: &pathType,
+							Backend:  backend(planetscalev2.DefaultWebPortName),
+						},
+					},
+				},
+			},
+		},
+	}
+	obj.Spec.TLS = nil
+	if ingress.TLSSecretName != "" {
+		obj.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{ingress.Host},
+				SecretName: ingress.TLSSecretName,
+			},
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtadmin
+
+import (
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNewIngress(t *testing.T) {
+	ingress := &planetscalev2.VtAdminIngress{
+		Host:          "vtadmin.example.com",
+		TLSSecretName: "vtadmin-tls",
+	}
+	obj := NewIngress(client.ObjectKey{Namespace: "ns", Name: "example-vtadmin"}, nil, "example-vtadmin", ingress)
+
+	paths := obj.Spec.Rules[0].HTTP.Paths
+	if len(paths) != 2 {
+		t.Fatalf("len(Paths) = %v; want 2", len(paths))
+	}
+	if paths[0].Path != "/api" || paths[0].Backend.Service.Port.Name != planetscalev2.DefaultAPIPortName {
+		t.Errorf("Paths[0] = %v -> %v; want /api -> %v", paths[0].Path, paths[0].Backend.Service.Port.Name, planetscalev2.DefaultAPIPortName)
+	}
+	if paths[1].Path != "/" || paths[1].Backend.Service.Port.Name != planetscalev2.DefaultWebPortName {
+		t.Errorf("Paths[1] = %v -> %v; want / -> %v", paths[1].Path, paths[1].Backend.Service.Port.Name, planetscalev2.DefaultWebPortName)
+	}
+	if len(obj.Spec.TLS) != 1 || obj.Spec.TLS[0].SecretName != "vtadmin-tls" {
+		t.Errorf("TLS = %v; want secret vtadmin-tls", obj.Spec.TLS)
+	}
+	if got, want := IngressAddress(ingress), "https://vtadmin.example.com"; got != want {
+		t.Errorf("IngressAddress() = %v; want %v", got, want)
+	}
+
+	// Removing the TLS secret serves plain HTTP.
+	ingress.TLSSecretName = ""
+	UpdateIngress(obj, nil, "example-vtadmin", ingress)
+	if obj.Spec.TLS != nil {
+		t.Errorf("TLS = %v; want none", obj.Spec.TLS)
+	}
+	if got, want := IngressAddress(ingress), "http://vtadmin.example.com"; got != want {
+		t.Errorf("IngressAddress() = %v; want %v", got, want)
+	}
+}
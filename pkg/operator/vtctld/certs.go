@@ -0,0 +1,285 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	// CACertKey is the key of the certificate authority in certificate
+	// Secrets, as in the Secrets that cert-manager issues.
+	CACertKey = "ca.crt"
+	// caKeyKey is the key of the certificate authority's private key in the
+	// Secret that holds the certificate authority the operator minted.
+	caKeyKey = "ca.key"
+
+	caLifetime = 10 * 365 * 24 * time.Hour
+	// CertLifetime is how long the certificates the operator mints are
+	// valid for.
+	CertLifetime = 365 * 24 * time.Hour
+	// CertRenewBefore is how long before they expire the certificates the
+	// operator minted are replaced.
+	CertRenewBefore = 30 * 24 * time.Hour
+
+	// ClientCommonName is the common name of the client certificate that the
+	// operator mints.
+	ClientCommonName = "vtctld-client"
+)
+
+// CASecretName returns the name of the Secret that holds the certificate
+// authority the operator minted for the vtctld gRPC API of a cluster.
+func CASecretName(clusterName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VtctldComponentName, "grpc-ca")
+}
+
+// ServerCertSecretName returns the name of the Secret that holds the vtctld
+// server certificate the operator minted for a cluster.
+func ServerCertSecretName(clusterName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VtctldComponentName, "grpc-server")
+}
+
+// ClientCertSecretName returns the name of the Secret that holds the vtctld
+// client certificate the operator minted for a cluster.
+func ClientCertSecretName(clusterName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VtctldComponentName, "grpc-client")
+}
+
+// ServerDNSNames returns the names to include in the vtctld server
+// certificate: the in-cluster names of the vtctld Service, followed by any
+// extra names.
+func ServerDNSNames(namespace, clusterName string, extra []string) []string {
+	svc := ServiceName(clusterName)
+	dnsNames := []string{
+		svc,
+		fmt.Sprintf("%s.%s", svc, namespace),
+		fmt.Sprintf("%s.%s.svc", svc, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svc, namespace),
+	}
+	return append(dnsNames, extra...)
+}
+
+// CA is a certificate authority that signs vtctld certificates.
+type CA struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	Key     crypto.Signer
+	KeyPEM  []byte
+}
+
+// NewCA mints a self-signed certificate authority for a cluster.
+func NewCA(clusterName string, now time.Time) (*CA, error) {
+	key, keyPEM, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s-%s-ca", clusterName, planetscalev2.VtctldComponentName)},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{
+		Cert:    cert,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:     key,
+		KeyPEM:  keyPEM,
+	}, nil
+}
+
+// ParseCA loads the certificate authority from a Secret made by NewCASecret.
+func ParseCA(secret *corev1.Secret) (*CA, error) {
+	cert, err := parseCert(secret.Data[CACertKey])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate in Secret %v: %v", secret.Name, err)
+	}
+	block, _ := pem.Decode(secret.Data[caKeyKey])
+	if block == nil {
+		return nil, fmt.Errorf("no CA key in Secret %v", secret.Name)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key in Secret %v: %v", secret.Name, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("CA key in Secret %v can't sign", secret.Name)
+	}
+	return &CA{
+		Cert:    cert,
+		CertPEM: secret.Data[CACertKey],
+		Key:     signer,
+		KeyPEM:  secret.Data[caKeyKey],
+	}, nil
+}
+
+// NewCASecret creates a new Secret object that holds a certificate authority.
+func NewCASecret(key client.ObjectKey, labels map[string]string, ca *CA) *corev1.Secret {
+	obj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			CACertKey: ca.CertPEM,
+			caKeyKey:  ca.KeyPEM,
+		},
+	}
+	update.Labels(&obj.Labels, labels)
+	return obj
+}
+
+// NewCertSecret mints a certificate signed by the given certificate
+// authority, and creates a new Secret object that holds it. Certificates with
+// DNS names are server certificates, and certificates without them are client
+// certificates.
+func NewCertSecret(key client.ObjectKey, labels map[string]string, ca *CA, commonName string, dnsNames []string, now time.Time) (*corev1.Secret, error) {
+	certKey, keyPEM, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(CertLifetime),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if len(dnsNames) != 0 {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, certKey.Public(), ca.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %v", err)
+	}
+
+	obj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:               ca.CertPEM,
+		},
+	}
+	update.Labels(&obj.Labels, labels)
+	return obj, nil
+}
+
+// CertUpToDate returns whether the certificate in a Secret made by
+// NewCertSecret is still signed by the given certificate authority, has the
+// given DNS names, and doesn't need to be renewed yet.
+func CertUpToDate(secret *corev1.Secret, ca *CA, dnsNames []string, now time.Time) bool {
+	cert, err := parseCert(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return false
+	}
+	if err := cert.CheckSignatureFrom(ca.Cert); err != nil {
+		return false
+	}
+	if now.Add(CertRenewBefore).After(cert.NotAfter) {
+		return false
+	}
+	return equalNames(cert.DNSNames, dnsNames)
+}
+
+func newKey() (crypto.Signer, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode key: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+	return serial, nil
+}
+
+func parseCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func equalNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
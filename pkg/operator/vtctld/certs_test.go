@@ -0,0 +1,66 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCertUpToDate(t *testing.T) {
+	now := time.Now()
+	ca, err := NewCA("example", now)
+	if err != nil {
+		t.Fatalf("NewCA() error: %v", err)
+	}
+	// The CA survives a round trip through its Secret.
+	ca, err = ParseCA(NewCASecret(client.ObjectKey{Namespace: "ns", Name: "ca"}, nil, ca))
+	if err != nil {
+		t.Fatalf("ParseCA() error: %v", err)
+	}
+
+	dnsNames := ServerDNSNames("ns", "example", []string{"vtctld.example.com"})
+	secret, err := NewCertSecret(client.ObjectKey{Namespace: "ns", Name: "server"}, nil, ca, "vtctld", dnsNames, now)
+	if err != nil {
+		t.Fatalf("NewCertSecret() error: %v", err)
+	}
+	if _, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]); err != nil {
+		t.Errorf("tls.X509KeyPair() error: %v", err)
+	}
+
+	if !CertUpToDate(secret, ca, dnsNames, now) {
+		t.Errorf("CertUpToDate() = false; want true for a new certificate")
+	}
+	if CertUpToDate(secret, ca, dnsNames, now.Add(CertLifetime-CertRenewBefore+time.Hour)) {
+		t.Errorf("CertUpToDate() = true; want false once it's time to renew")
+	}
+	if CertUpToDate(secret, ca, dnsNames[:len(dnsNames)-1], now) {
+		t.Errorf("CertUpToDate() = true; want false when the DNS names changed")
+	}
+
+	otherCA, err := NewCA("example", now)
+	if err != nil {
+		t.Fatalf("NewCA() error: %v", err)
+	}
+	if CertUpToDate(secret, otherCA, dnsNames, now) {
+		t.Errorf("CertUpToDate() = true; want false for a different CA")
+	}
+}
@@ -29,6 +29,7 @@ import (
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/k8s"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 	"planetscale.dev/vitess-operator/pkg/operator/vitess"
 	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
@@ -41,6 +42,11 @@ const (
 	webDir     = "/vt/src/vitess.io/vitess/web/vtctld"
 	webDir2    = "/vt/src/vitess.io/vitess/web/vtctld2/app"
 	serviceMap = "grpc-vtctl,grpc-vtctld"
+
+	staticAuthDirName      = "vtctld-static-auth"
+	tlsCertDirName         = "vtctld-tls-cert"
+	tlsKeyDirName          = "vtctld-tls-key"
+	tlsClientCACertDirName = "vtctld-tls-ca-cert"
 )
 
 // DeploymentName returns the name of the vtctld Deployment for a given cell.
@@ -71,6 +77,12 @@ type Spec struct {
 	Tolerations       []corev1.Toleration
 	BackupLocation    *planetscalev2.VitessBackupLocation
 	BackupEngine      planetscalev2.VitessBackupEngine
+	// GRPCTLSSecretName is the name of a Secret with the gRPC server
+	// certificate in tls.crt, its key in tls.key, and the CA for client
+	// certificates in ca.crt. If it's empty, gRPC is served in plaintext.
+	GRPCTLSSecretName string
+	// GRPCStaticAuth is the static auth file for gRPC clients, if any.
+	GRPCStaticAuth *planetscalev2.SecretSource
 }
 
 // NewDeployment creates a new Deployment object for vtctld.
@@ -125,6 +137,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 
 	// Apply user-provided flag overrides after generating base flags.
 	flags := spec.flags()
+	grpcVolumes, grpcVolumeMounts := updateGRPC(spec, flags)
 	for key, value := range spec.ExtraFlags {
 		// We told users in the CRD API field doc not to put any leading '-',
 		// but people may not read that so we are liberal in what we accept.
@@ -147,6 +160,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 		volumeMounts = append(volumeMounts, vitessbackup.StorageVolumeMounts(spec.BackupLocation)...)
 		env = append(env, vitessbackup.StorageEnvVars(spec.BackupLocation)...)
 	}
+	update.Volumes(&obj.Spec.Template.Spec.Volumes, grpcVolumes)
 	update.Volumes(&obj.Spec.Template.Spec.Volumes, volumes)
 
 	securityContext := &corev1.SecurityContext{}
@@ -198,7 +212,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 				InitialDelaySeconds: 300,
 				FailureThreshold:    30,
 			},
-			VolumeMounts: volumeMounts,
+			VolumeMounts: append(grpcVolumeMounts, volumeMounts...),
 			Env:          env,
 		},
 	})
@@ -259,3 +273,40 @@ func (spec *Spec) flags() vitess.Flags {
 	flags = flags.Merge(storageLocationFlags)
 	return flags
 }
+
+// updateGRPC sets the flags for TLS and authentication on the gRPC API, and
+// returns the volumes and volume mounts they need.
+func updateGRPC(spec *Spec, flags vitess.Flags) ([]corev1.Volume, []corev1.VolumeMount) {
+	var mounts []*secrets.VolumeMount
+
+	if spec.GRPCTLSSecretName != "" {
+		tlsCertFile := secrets.Mount(&planetscalev2.SecretSource{Name: spec.GRPCTLSSecretName, Key: corev1.TLSCertKey}, tlsCertDirName)
+		tlsKeyFile := secrets.Mount(&planetscalev2.SecretSource{Name: spec.GRPCTLSSecretName, Key: corev1.TLSPrivateKeyKey}, tlsKeyDirName)
+		clientCACertFile := secrets.Mount(&planetscalev2.SecretSource{Name: spec.GRPCTLSSecretName, Key: CACertKey}, tlsClientCACertDirName)
+
+		// Setting grpc_ca makes vtctld require client certificates signed
+		// by that CA.
+		flags["grpc_cert"] = tlsCertFile.FilePath()
+		flags["grpc_key"] = tlsKeyFile.FilePath()
+		flags["grpc_ca"] = clientCACertFile.FilePath()
+
+		mounts = append(mounts, tlsCertFile, tlsKeyFile, clientCACertFile)
+	}
+
+	if spec.GRPCStaticAuth != nil {
+		staticAuthFile := secrets.Mount(spec.GRPCStaticAuth, staticAuthDirName)
+
+		flags["grpc_auth_mode"] = "static"
+		flags["grpc_auth_static_password_file"] = staticAuthFile.FilePath()
+
+		mounts = append(mounts, staticAuthFile)
+	}
+
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	for _, mount := range mounts {
+		volumes = append(volumes, mount.PodVolumes()...)
+		volumeMounts = append(volumeMounts, mount.ContainerVolumeMount())
+	}
+	return volumes, volumeMounts
+}
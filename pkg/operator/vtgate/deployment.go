@@ -17,7 +17,13 @@ limitations under the License.
 package vtgate
 
 import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -53,6 +59,19 @@ const (
 	tlsCertDirName         = "vtgate-tls-cert"
 	tlsKeyDirName          = "vtgate-tls-key"
 	tlsClientCACertDirName = "vtgate-tls-ca-cert"
+
+	// drainGracePeriodBuffer is how much longer than the drain timeout vtgate
+	// gets to shut down after connections are drained.
+	drainGracePeriodBuffer = 30 * time.Second
+)
+
+const (
+	// ColorLabel is the label that tells apart the Pods of the alternate vtgate
+	// Deployment that's used for blue/green rollouts. Pods of the original
+	// vtgate Deployment don't have it, since its selector can't be changed.
+	ColorLabel = "planetscale.com/vtgate-color"
+	// GreenColor is the value of ColorLabel for the alternate Deployment.
+	GreenColor = "green"
 )
 
 // DeploymentName returns the name of the vtgate Deployment for a given cell.
@@ -60,6 +79,12 @@ func DeploymentName(clusterName, cellName string) string {
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, cellName, planetscalev2.VtgateComponentName)
 }
 
+// AlternateDeploymentName returns the name of the second vtgate Deployment for
+// a given cell, which is used for blue/green rollouts.
+func AlternateDeploymentName(clusterName, cellName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, cellName, planetscalev2.VtgateComponentName, GreenColor)
+}
+
 // Spec specifies all the internal parameters needed to deploy vtgate,
 // as opposed to the API type planetscalev2.VitessCellGatewaySpec, which is the public API.
 type Spec struct {
@@ -83,6 +108,13 @@ type Spec struct {
 	TopologySpreadConstraints     []corev1.TopologySpreadConstraint
 	Lifecycle                     corev1.Lifecycle
 	TerminationGracePeriodSeconds *int64
+	DrainTimeout                  time.Duration
+	LameduckPeriod                time.Duration
+	TerminationDrainDuration      time.Duration
+	ServingGate                   bool
+	// LDAPConfigSecretName is the name of the Secret that holds the LDAP
+	// config file rendered for this cell, if LDAP authentication is enabled.
+	LDAPConfigSecretName string
 }
 
 // NewDeployment creates a new Deployment object for vtgate.
@@ -104,6 +136,19 @@ func NewDeployment(key client.ObjectKey, spec *Spec) *appsv1.Deployment {
 	return obj
 }
 
+// TemplateHash returns a hash of the Pod template that the vtgate Deployment
+// would get for the given spec, ignoring labels and the number of replicas.
+// It changes whenever the spec calls for new vtgate Pods.
+func TemplateHash(spec *Spec) string {
+	obj := &appsv1.Deployment{}
+	UpdateDeployment(obj, spec)
+	obj.Spec.Template.Labels = nil
+	// Marshaling a Pod template can't fail.
+	data, _ := json.Marshal(&obj.Spec.Template)
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // UpdateDeployment updates the mutable parts of the vtgate Deployment.
 func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	// Set labels on the Deployment object.
@@ -133,8 +178,19 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	obj.Spec.Template.Spec.Tolerations = spec.Tolerations
 	obj.Spec.Template.Spec.TopologySpreadConstraints = spec.TopologySpreadConstraints
 
+	obj.Spec.Template.Spec.ReadinessGates = nil
+	if spec.ServingGate {
+		// Hold new Pods back until the operator sees enough healthy shards.
+		obj.Spec.Template.Spec.ReadinessGates = []corev1.PodReadinessGate{
+			{ConditionType: ServingConditionType},
+		}
+	}
+
 	if spec.TerminationGracePeriodSeconds != nil {
 		obj.Spec.Template.Spec.TerminationGracePeriodSeconds = spec.TerminationGracePeriodSeconds
+	} else if shutdown := spec.shutdownDuration(); shutdown > 0 {
+		// Leave enough time to drain client connections before the Pod is killed.
+		obj.Spec.Template.Spec.TerminationGracePeriodSeconds = pointer.Int64Ptr(int64((shutdown + drainGracePeriodBuffer) / time.Second))
 	}
 
 	if spec.Affinity != nil {
@@ -221,6 +277,13 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 
 	// Get all the flags that don't need any logic.
 	flags := spec.baseFlags()
+	if spec.DrainTimeout > 0 {
+		// Wait this long for client connections to finish on shutdown.
+		flags["onterm_timeout"] = spec.DrainTimeout.String()
+	}
+	if spec.LameduckPeriod > 0 {
+		flags["lameduck-period"] = spec.LameduckPeriod.String()
+	}
 
 	// Update the Pod template, container, and flags for various optional things.
 	updateAuth(spec, flags, vtgateContainer, &obj.Spec.Template.Spec)
@@ -239,8 +302,18 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 
 	// Set the container lifecycle configuration if provided. Otherwise, skip
 	// to avoid restarting existing pods due to an empty 'lifecycle' field.
-	if spec.Lifecycle != (corev1.Lifecycle{}) {
-		vtgateContainer.Lifecycle = &spec.Lifecycle
+	lifecycle := spec.Lifecycle
+	if spec.TerminationDrainDuration > 0 && lifecycle.PreStop == nil {
+		// Keep serving until load balancers stop sending new connections,
+		// before vtgate gets SIGTERM.
+		lifecycle.PreStop = &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sleep", strconv.FormatInt(int64(math.Ceil(spec.TerminationDrainDuration.Seconds())), 10)},
+			},
+		}
+	}
+	if lifecycle != (corev1.Lifecycle{}) {
+		vtgateContainer.Lifecycle = &lifecycle
 	}
 
 	update.PodTemplateContainers(&obj.Spec.Template.Spec.InitContainers, spec.InitContainers)
@@ -286,20 +359,65 @@ func (spec *Spec) baseFlags() vitess.Flags {
 	}
 }
 
+// shutdownDuration returns how long a vtgate Pod may take to shut down once
+// it starts terminating, not counting the grace period buffer.
+func (spec *Spec) shutdownDuration() time.Duration {
+	drain := spec.DrainTimeout
+	if spec.LameduckPeriod > drain {
+		drain = spec.LameduckPeriod
+	}
+	return spec.TerminationDrainDuration + drain
+}
+
 func updateAuth(spec *Spec, flags vitess.Flags, container *corev1.Container, podSpec *corev1.PodSpec) {
-	if spec.Authentication.Static != nil && spec.Authentication.Static.Secret != nil {
+	switch {
+	case spec.Authentication.Static != nil:
+		if spec.Authentication.Static.Secret == nil {
+			return
+		}
 		staticAuthFile := secrets.Mount(spec.Authentication.Static.Secret, staticAuthDirName)
 
 		// Get usernames and passwords from a static file, mounted from a Secret.
 		flags["mysql_auth_server_impl"] = "static"
 		flags["mysql_auth_server_static_file"] = staticAuthFile.FilePath()
-		flags["mysql_auth_static_reload_interval"] = "30s"
+		flags["mysql_auth_static_reload_interval"] = StaticAuthReloadInterval.String()
 
 		// Add the volume to the Pod, if needed.
 		update.Volumes(&podSpec.Volumes, staticAuthFile.PodVolumes())
 
 		// Mount the volume in the Container.
 		container.VolumeMounts = append(container.VolumeMounts, staticAuthFile.ContainerVolumeMount())
+	case spec.Authentication.LDAP != nil:
+		// The config file is rendered by the controller, since it needs
+		// the bind password.
+		if spec.LDAPConfigSecretName == "" {
+			return
+		}
+		ldap := spec.Authentication.LDAP
+		ldapConfigFile := secrets.Mount(&planetscalev2.SecretSource{Name: spec.LDAPConfigSecretName, Key: LDAPConfigKey}, ldapConfigDirName)
+
+		flags["mysql_auth_server_impl"] = "ldap"
+		flags["mysql_ldap_auth_config_file"] = ldapConfigFile.FilePath()
+		flags["mysql_ldap_auth_method"] = string(ldap.AuthMethod)
+
+		update.Volumes(&podSpec.Volumes, ldapConfigFile.PodVolumes())
+		container.VolumeMounts = append(container.VolumeMounts, ldapConfigFile.ContainerVolumeMount())
+
+		if ldap.CACertSecret != nil {
+			// The path to this file is in the config file.
+			ldapCACertFile := secrets.Mount(ldap.CACertSecret, ldapCACertDirName)
+			update.Volumes(&podSpec.Volumes, ldapCACertFile.PodVolumes())
+			container.VolumeMounts = append(container.VolumeMounts, ldapCACertFile.ContainerVolumeMount())
+		}
+	case spec.Authentication.ClientCert != nil:
+		// vtgate only registers the clientcert auth server if it checks
+		// client certificates, and fails to start if it's asked to use an
+		// auth server that isn't registered.
+		if spec.SecureTransport == nil || spec.SecureTransport.TLS == nil || spec.SecureTransport.TLS.ClientCACertSecret == nil {
+			return
+		}
+		flags["mysql_auth_server_impl"] = "clientcert"
+		flags["mysql_clientcert_auth_method"] = string(spec.Authentication.ClientCert.AuthMethod)
 	}
 }
 
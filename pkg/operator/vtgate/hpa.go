@@ -0,0 +1,138 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	// maxScalingPolicyPeriod is the longest period that an HPA scaling policy
+	// may have.
+	maxScalingPolicyPeriod = 30 * time.Minute
+)
+
+// AutoscalerSpec specifies all the internal parameters needed to create the
+// HorizontalPodAutoscaler (HPA) for vtgate in a cell.
+type AutoscalerSpec struct {
+	// Labels are the labels to put on the HPA itself.
+	Labels map[string]string
+	// CellObjectName is the name of the VitessCell object whose scale
+	// subresource the HPA adjusts.
+	CellObjectName string
+	// Config holds the user's settings.
+	Config *planetscalev2.VitessGatewayAutoscaler
+}
+
+// NewHorizontalPodAutoscaler creates a new HPA for vtgate.
+func NewHorizontalPodAutoscaler(key client.ObjectKey, spec *AutoscalerSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	obj := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+	}
+	UpdateHorizontalPodAutoscaler(obj, spec)
+	return obj
+}
+
+// UpdateHorizontalPodAutoscaler updates the mutable parts of the vtgate HPA.
+func UpdateHorizontalPodAutoscaler(obj *autoscalingv2.HorizontalPodAutoscaler, spec *AutoscalerSpec) {
+	// Update labels, but ignore existing ones we don't set.
+	update.Labels(&obj.Labels, spec.Labels)
+
+	config := spec.Config
+
+	// Scale the VitessCell rather than the vtgate Deployment, so the number
+	// of replicas carries over to both Deployments during blue/green rollouts.
+	obj.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		APIVersion: planetscalev2.SchemeGroupVersion.String(),
+		Kind:       "VitessCell",
+		Name:       spec.CellObjectName,
+	}
+	obj.Spec.MinReplicas = config.MinReplicas
+	obj.Spec.MaxReplicas = config.MaxReplicas
+
+	obj.Spec.Metrics = nil
+	if config.TargetCPUUtilization != nil {
+		obj.Spec.Metrics = append(obj.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: config.TargetCPUUtilization,
+				},
+			},
+		})
+	}
+	for _, metric := range []*planetscalev2.VitessGatewayAutoscalerMetric{config.QueriesPerSecond, config.Connections} {
+		if metric == nil {
+			continue
+		}
+		averageValue := metric.AverageValue.DeepCopy()
+		obj.Spec.Metrics = append(obj.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{Name: metric.MetricName},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &averageValue,
+				},
+			},
+		})
+	}
+	for i := range config.Metrics {
+		obj.Spec.Metrics = append(obj.Spec.Metrics, *config.Metrics[i].DeepCopy())
+	}
+
+	// Remove at most one vtgate per drain timeout, so only one of them is
+	// draining client connections at a time.
+	drainPeriod := config.DrainTimeout.Duration
+	if drainPeriod > maxScalingPolicyPeriod {
+		drainPeriod = maxScalingPolicyPeriod
+	}
+	if drainPeriod < time.Second {
+		drainPeriod = time.Second
+	}
+	obj.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2.HPAScalingRules{
+			StabilizationWindowSeconds: durationSeconds(config.ScaleDownStabilizationWindow.Duration),
+			Policies: []autoscalingv2.HPAScalingPolicy{
+				{
+					Type:          autoscalingv2.PodsScalingPolicy,
+					Value:         1,
+					PeriodSeconds: *durationSeconds(drainPeriod),
+				},
+			},
+		},
+	}
+}
+
+// durationSeconds returns a duration in whole seconds.
+func durationSeconds(d time.Duration) *int32 {
+	seconds := int32(d / time.Second)
+	return &seconds
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestNewHorizontalPodAutoscaler(t *testing.T) {
+	config := &planetscalev2.VitessGatewayAutoscaler{
+		MaxReplicas: 10,
+		QueriesPerSecond: &planetscalev2.VitessGatewayAutoscalerMetric{
+			AverageValue: resource.MustParse("500"),
+		},
+		DrainTimeout: &metav1.Duration{Duration: 2 * time.Minute},
+	}
+	planetscalev2.DefaultVitessGatewayAutoscaler(config)
+
+	obj := NewHorizontalPodAutoscaler(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, &AutoscalerSpec{
+		CellObjectName: "example-zone1",
+		Config:         config,
+	})
+
+	if got := obj.Spec.ScaleTargetRef.Kind; got != "VitessCell" {
+		t.Errorf("ScaleTargetRef.Kind = %q; want VitessCell", got)
+	}
+	if got := *obj.Spec.MinReplicas; got != 2 {
+		t.Errorf("MinReplicas = %v; want 2", got)
+	}
+	// Setting a custom metric means CPU isn't used by default.
+	if got := len(obj.Spec.Metrics); got != 1 {
+		t.Fatalf("len(Metrics) = %v; want 1", got)
+	}
+	metric := obj.Spec.Metrics[0]
+	if metric.Type != autoscalingv2.PodsMetricSourceType || metric.Pods.Metric.Name != "vtgate_queries_per_second" {
+		t.Errorf("Metrics[0] = %+v; want Pods metric vtgate_queries_per_second", metric)
+	}
+	scaleDown := obj.Spec.Behavior.ScaleDown
+	if got := *scaleDown.StabilizationWindowSeconds; got != 300 {
+		t.Errorf("StabilizationWindowSeconds = %v; want 300", got)
+	}
+	if got := scaleDown.Policies; len(got) != 1 || got[0].Value != 1 || got[0].PeriodSeconds != 120 {
+		t.Errorf("Policies = %+v; want one Pod per 120s", got)
+	}
+}
+
+func TestDeploymentDrainTimeout(t *testing.T) {
+	spec := &Spec{
+		Cell:           &planetscalev2.VitessCellSpec{},
+		Authentication: &planetscalev2.VitessGatewayAuthentication{},
+		DrainTimeout:   time.Minute,
+	}
+	obj := NewDeployment(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, spec)
+
+	if got := *obj.Spec.Template.Spec.TerminationGracePeriodSeconds; got != 90 {
+		t.Errorf("TerminationGracePeriodSeconds = %v; want 90", got)
+	}
+	found := false
+	for _, arg := range obj.Spec.Template.Spec.Containers[0].Args {
+		if arg == "--onterm_timeout=1m0s" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Args = %v; want --onterm_timeout=1m0s", obj.Spec.Template.Spec.Containers[0].Args)
+	}
+}
+
+func TestDeploymentTerminationDrain(t *testing.T) {
+	spec := &Spec{
+		Cell:                     &planetscalev2.VitessCellSpec{},
+		Authentication:           &planetscalev2.VitessGatewayAuthentication{},
+		DrainTimeout:             time.Minute,
+		LameduckPeriod:           90 * time.Second,
+		TerminationDrainDuration: 15 * time.Second,
+	}
+	obj := NewDeployment(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, spec)
+	container := obj.Spec.Template.Spec.Containers[0]
+
+	if got := *obj.Spec.Template.Spec.TerminationGracePeriodSeconds; got != 135 {
+		t.Errorf("TerminationGracePeriodSeconds = %v; want 135", got)
+	}
+	if container.Lifecycle == nil || container.Lifecycle.PreStop == nil || container.Lifecycle.PreStop.Exec == nil {
+		t.Fatalf("Lifecycle = %+v; want preStop exec hook", container.Lifecycle)
+	}
+	if got, want := strings.Join(container.Lifecycle.PreStop.Exec.Command, " "), "sleep 15"; got != want {
+		t.Errorf("preStop command = %q; want %q", got, want)
+	}
+	found := false
+	for _, arg := range container.Args {
+		if arg == "--lameduck-period=1m30s" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Args = %v; want --lameduck-period=1m30s", container.Args)
+	}
+
+	// A preStop hook from the spec takes precedence.
+	spec.Lifecycle.PreStop = &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}}
+	obj = NewDeployment(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, spec)
+	if got := obj.Spec.Template.Spec.Containers[0].Lifecycle.PreStop.Exec.Command; len(got) != 1 || got[0] != "true" {
+		t.Errorf("preStop command = %v; want [true]", got)
+	}
+}
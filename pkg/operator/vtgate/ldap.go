@@ -0,0 +1,89 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/secrets"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	// LDAPConfigKey is the key of the vtgate LDAP config file in the Secret
+	// that the operator renders it into.
+	LDAPConfigKey = "ldap-config.json"
+
+	ldapConfigDirName = "vtgate-ldap-config"
+	ldapCACertDirName = "vtgate-ldap-ca-cert"
+)
+
+// LDAPConfigSecretName returns the name of the Secret that holds the LDAP
+// config file for vtgate in a given cell.
+func LDAPConfigSecretName(clusterName, cellName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, cellName, planetscalev2.VtgateComponentName, "ldap")
+}
+
+// ldapConfig is the format of the vtgate LDAP config file.
+type ldapConfig struct {
+	LdapServer     string
+	LdapCA         string `json:",omitempty"`
+	User           string
+	Password       string
+	GroupQuery     string
+	UserDnPattern  string
+	RefreshSeconds int64
+}
+
+// NewLDAPConfigSecret creates a new Secret object that holds the vtgate LDAP
+// config file, which includes the bind password.
+func NewLDAPConfigSecret(key client.ObjectKey, labels map[string]string, ldap *planetscalev2.VitessGatewayLDAPAuthentication, bindPassword string) (*corev1.Secret, error) {
+	config := ldapConfig{
+		LdapServer:     ldap.Server,
+		User:           ldap.BindDN,
+		Password:       bindPassword,
+		GroupQuery:     ldap.GroupBaseDN,
+		UserDnPattern:  ldap.UserDNPattern,
+		RefreshSeconds: int64(ldap.RefreshInterval.Seconds()),
+	}
+	if ldap.CACertSecret != nil {
+		config.LdapCA = secrets.Mount(ldap.CACertSecret, ldapCACertDirName).FilePath()
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			LDAPConfigKey: data,
+		},
+	}
+	update.Labels(&obj.Labels, labels)
+	return obj, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+func TestLDAPAuth(t *testing.T) {
+	auth := planetscalev2.VitessGatewayAuthentication{
+		LDAP: &planetscalev2.VitessGatewayLDAPAuthentication{
+			Server:        "ldap.example.com:636",
+			UserDNPattern: "uid=%s,ou=users,dc=example,dc=com",
+			GroupBaseDN:   "ou=groups,dc=example,dc=com",
+			BindDN:        "cn=vtgate,dc=example,dc=com",
+			CACertSecret:  &planetscalev2.SecretSource{Name: "ldap-ca", Key: "ca.crt"},
+		},
+	}
+	planetscalev2.DefaultVitessGatewayAuthentication(&auth)
+
+	secret, err := NewLDAPConfigSecret(client.ObjectKey{Namespace: "ns", Name: "ldap"}, nil, auth.LDAP, "hunter2")
+	if err != nil {
+		t.Fatalf("NewLDAPConfigSecret() error: %v", err)
+	}
+	config := ldapConfig{}
+	if err := json.Unmarshal(secret.Data[LDAPConfigKey], &config); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if config.Password != "hunter2" || config.RefreshSeconds != 60 {
+		t.Errorf("config = %+v; want Password hunter2 and RefreshSeconds 60", config)
+	}
+	if want := "/vt/secrets/vtgate-ldap-ca-cert/ca.crt"; config.LdapCA != want {
+		t.Errorf("LdapCA = %q; want %q", config.LdapCA, want)
+	}
+
+	spec := &Spec{Authentication: &auth, LDAPConfigSecretName: "ldap"}
+	flags := vitess.Flags{}
+	container := &corev1.Container{}
+	podSpec := &corev1.PodSpec{}
+	updateAuth(spec, flags, container, podSpec)
+	if flags["mysql_auth_server_impl"] != "ldap" || flags["mysql_ldap_auth_method"] != "mysql_clear_password" {
+		t.Errorf("flags = %v; want ldap auth with mysql_clear_password", flags)
+	}
+	if got := len(podSpec.Volumes); got != 2 {
+		t.Errorf("len(Volumes) = %v; want 2 for the config and the CA", got)
+	}
+}
+
+func TestClientCertAuth(t *testing.T) {
+	auth := planetscalev2.VitessGatewayAuthentication{
+		ClientCert: &planetscalev2.VitessGatewayClientCertAuthentication{},
+	}
+	planetscalev2.DefaultVitessGatewayAuthentication(&auth)
+	spec := &Spec{Authentication: &auth}
+
+	// Without a CA to check client certificates, vtgate wouldn't start.
+	flags := vitess.Flags{}
+	updateAuth(spec, flags, &corev1.Container{}, &corev1.PodSpec{})
+	if _, ok := flags["mysql_auth_server_impl"]; ok {
+		t.Errorf("flags = %v; want no auth server without a client CA", flags)
+	}
+
+	spec.SecureTransport = &planetscalev2.VitessGatewaySecureTransport{
+		TLS: &planetscalev2.VitessGatewayTLSSecureTransport{
+			ClientCACertSecret: &planetscalev2.SecretSource{Name: "vtgate-ca", Key: "ca.crt"},
+		},
+	}
+	updateAuth(spec, flags, &corev1.Container{}, &corev1.PodSpec{})
+	if flags["mysql_auth_server_impl"] != "clientcert" {
+		t.Errorf("mysql_auth_server_impl = %v; want clientcert", flags["mysql_auth_server_impl"])
+	}
+}
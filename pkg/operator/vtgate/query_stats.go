@@ -0,0 +1,157 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// queryStatsTimeout is how long to wait for a vtgate to report its stats.
+	queryStatsTimeout = 5 * time.Second
+)
+
+// QueryStats is how many queries a vtgate has served since it started, and
+// how many of them failed.
+type QueryStats struct {
+	Queries int64
+	Errors  int64
+}
+
+// Add adds up the stats of two vtgates.
+func (s QueryStats) Add(other QueryStats) QueryStats {
+	return QueryStats{Queries: s.Queries + other.Queries, Errors: s.Errors + other.Errors}
+}
+
+// ErrorPercent returns the percentage of queries that failed.
+func (s QueryStats) ErrorPercent() float64 {
+	if s.Queries == 0 {
+		return 0
+	}
+	return float64(s.Errors) * 100 / float64(s.Queries)
+}
+
+var queryStatsClient = &http.Client{Timeout: queryStatsTimeout}
+
+// GetQueryStats asks a vtgate Pod for its query stats.
+func GetQueryStats(ctx context.Context, pod *corev1.Pod) (QueryStats, error) {
+	body, err := getVars(ctx, pod)
+	if err != nil {
+		return QueryStats{}, err
+	}
+	stats, err := parseQueryStats(body)
+	if err != nil {
+		return QueryStats{}, fmt.Errorf("failed to parse stats of vtgate Pod %v: %v", pod.Name, err)
+	}
+	return stats, nil
+}
+
+// GetKeyspaceQueries asks a vtgate Pod how many queries it has served for a
+// given keyspace since it started.
+func GetKeyspaceQueries(ctx context.Context, pod *corev1.Pod, keyspace string) (int64, error) {
+	body, err := getVars(ctx, pod)
+	if err != nil {
+		return 0, err
+	}
+	queries, err := parseKeyspaceQueries(body, keyspace)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse stats of vtgate Pod %v: %v", pod.Name, err)
+	}
+	return queries, nil
+}
+
+// getVars fetches the expvars that a vtgate Pod exports at /debug/vars.
+func getVars(ctx context.Context, pod *corev1.Pod) ([]byte, error) {
+	if pod.Status.PodIP == "" {
+		return nil, fmt.Errorf("vtgate Pod %v has no IP", pod.Name)
+	}
+	url := fmt.Sprintf("http://%v/debug/vars", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(planetscalev2.DefaultWebPort)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := queryStatsClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats of vtgate Pod %v: %v", pod.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get stats of vtgate Pod %v: %v", pod.Name, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats of vtgate Pod %v: %v", pod.Name, err)
+	}
+	return body, nil
+}
+
+// parseQueryStats reads query stats out of the expvars that vtgate exports at
+// /debug/vars. Every query is counted in VtgateApi, and every failed one is
+// also counted in VtgateApiErrorCounts, by operation, keyspace, tablet type,
+// and error code.
+func parseQueryStats(data []byte) (QueryStats, error) {
+	var vars struct {
+		VtgateApi struct {
+			TotalCount int64
+		}
+		VtgateApiErrorCounts map[string]int64
+	}
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return QueryStats{}, err
+	}
+	stats := QueryStats{Queries: vars.VtgateApi.TotalCount}
+	for _, count := range vars.VtgateApiErrorCounts {
+		stats.Errors += count
+	}
+	return stats, nil
+}
+
+// parseKeyspaceQueries adds up the queries that VtgateApi counted for a given
+// keyspace. VtgateApi keeps a histogram for each operation, keyspace, and
+// tablet type, named by joining them with dots.
+func parseKeyspaceQueries(data []byte, keyspace string) (int64, error) {
+	var vars struct {
+		VtgateApi struct {
+			Histograms map[string]struct {
+				Count int64
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return 0, err
+	}
+	var queries int64
+	for name, histogram := range vars.VtgateApi.Histograms {
+		parts := strings.Split(name, ".")
+		if len(parts) == 3 && parts[1] == keyspace {
+			queries += histogram.Count
+		}
+	}
+	return queries, nil
+}
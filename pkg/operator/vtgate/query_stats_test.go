@@ -0,0 +1,78 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import "testing"
+
+func TestParseQueryStats(t *testing.T) {
+	data := []byte(`{
+		"Uptime": 120,
+		"VtgateApi": {"TotalCount": 200, "TotalTime": 123456, "Histograms": {}},
+		"VtgateApiErrorCounts": {
+			"Execute.commerce.primary.INVALID_ARGUMENT": 3,
+			"StreamExecute.commerce.replica.UNAVAILABLE": 2
+		}
+	}`)
+	stats, err := parseQueryStats(data)
+	if err != nil {
+		t.Fatalf("parseQueryStats() error: %v", err)
+	}
+	if want := (QueryStats{Queries: 200, Errors: 5}); stats != want {
+		t.Errorf("parseQueryStats() = %+v; want %+v", stats, want)
+	}
+	if got, want := stats.ErrorPercent(), 2.5; got != want {
+		t.Errorf("ErrorPercent() = %v; want %v", got, want)
+	}
+
+	// A vtgate that hasn't served anything yet doesn't export the counters.
+	stats, err = parseQueryStats([]byte(`{"Uptime": 1}`))
+	if err != nil {
+		t.Fatalf("parseQueryStats() error: %v", err)
+	}
+	if stats != (QueryStats{}) || stats.ErrorPercent() != 0 {
+		t.Errorf("parseQueryStats() = %+v; want no queries", stats)
+	}
+}
+
+func TestParseKeyspaceQueries(t *testing.T) {
+	data := []byte(`{
+		"VtgateApi": {
+			"TotalCount": 60,
+			"TotalTime": 123456,
+			"Histograms": {
+				"Execute.commerce.primary": {"500000": 10, "inf": 0, "Count": 20, "Time": 1000},
+				"StreamExecute.commerce.replica": {"500000": 5, "inf": 0, "Count": 5, "Time": 100},
+				"Execute.customer.primary": {"500000": 35, "inf": 0, "Count": 35, "Time": 2000}
+			}
+		}
+	}`)
+	queries, err := parseKeyspaceQueries(data, "commerce")
+	if err != nil {
+		t.Fatalf("parseKeyspaceQueries() error: %v", err)
+	}
+	if want := int64(25); queries != want {
+		t.Errorf("parseKeyspaceQueries() = %v; want %v", queries, want)
+	}
+
+	queries, err = parseKeyspaceQueries(data, "unsharded")
+	if err != nil {
+		t.Fatalf("parseKeyspaceQueries() error: %v", err)
+	}
+	if queries != 0 {
+		t.Errorf("parseKeyspaceQueries() = %v; want 0", queries)
+	}
+}
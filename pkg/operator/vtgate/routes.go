@@ -0,0 +1,127 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+// The Gateway API isn't vendored, since its CRDs are optional, so routes are
+// built as unstructured objects.
+var (
+	// HTTPRouteGVK is the kind of the route to the vtgate web UI.
+	HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	// TCPRouteGVK is the kind of the route to the vtgate MySQL port.
+	TCPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+)
+
+// NewRoute creates a new, empty route object of the given kind.
+func NewRoute(key client.ObjectKey, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	return obj
+}
+
+// UpdateHTTPRoute updates the mutable parts of the vtgate HTTPRoute.
+func UpdateHTTPRoute(obj *unstructured.Unstructured, labels map[string]string, serviceName string, route *planetscalev2.VitessGatewayHTTPRoute) {
+	updateRouteLabels(obj, labels)
+
+	// Spell out the fields that the Gateway API defaults, so the route
+	// doesn't look changed on every pass.
+	spec := map[string]interface{}{
+		"parentRefs": routeParentRefs(route.ParentRefs),
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": "/",
+						},
+					},
+				},
+				"backendRefs": routeBackendRefs(serviceName, planetscalev2.DefaultWebPort),
+			},
+		},
+	}
+	if len(route.Hostnames) != 0 {
+		hostnames := make([]interface{}, 0, len(route.Hostnames))
+		for _, hostname := range route.Hostnames {
+			hostnames = append(hostnames, hostname)
+		}
+		spec["hostnames"] = hostnames
+	}
+	obj.Object["spec"] = spec
+}
+
+// UpdateTCPRoute updates the mutable parts of the vtgate TCPRoute.
+func UpdateTCPRoute(obj *unstructured.Unstructured, labels map[string]string, serviceName string, route *planetscalev2.VitessGatewayTCPRoute) {
+	updateRouteLabels(obj, labels)
+
+	obj.Object["spec"] = map[string]interface{}{
+		"parentRefs": routeParentRefs(route.ParentRefs),
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": routeBackendRefs(serviceName, planetscalev2.DefaultMysqlPort),
+			},
+		},
+	}
+}
+
+func updateRouteLabels(obj *unstructured.Unstructured, labels map[string]string) {
+	objLabels := obj.GetLabels()
+	update.Labels(&objLabels, labels)
+	obj.SetLabels(objLabels)
+}
+
+func routeParentRefs(parentRefs []planetscalev2.GatewayAPIParentRef) []interface{} {
+	result := make([]interface{}, 0, len(parentRefs))
+	for _, parentRef := range parentRefs {
+		ref := map[string]interface{}{
+			"group": HTTPRouteGVK.Group,
+			"kind":  "Gateway",
+			"name":  parentRef.Name,
+		}
+		if parentRef.Namespace != "" {
+			ref["namespace"] = parentRef.Namespace
+		}
+		if parentRef.SectionName != "" {
+			ref["sectionName"] = parentRef.SectionName
+		}
+		result = append(result, ref)
+	}
+	return result
+}
+
+func routeBackendRefs(serviceName string, port int32) []interface{} {
+	return []interface{}{
+		map[string]interface{}{
+			"group":  "",
+			"kind":   "Service",
+			"name":   serviceName,
+			"port":   int64(port),
+			"weight": int64(1),
+		},
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package vtgate
 
 import (
+	"sort"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -27,6 +30,13 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/update"
 )
 
+const (
+	// ExposureAnnotationsAnnotation lists the annotations on the vtgate Service
+	// that were set from the gateway's exposure settings, so they can be
+	// removed once they're no longer wanted.
+	ExposureAnnotationsAnnotation = "planetscale.com/exposure-annotations"
+)
+
 // ServiceName returns the name of the vtgate Service for a cell.
 func ServiceName(clusterName, cellName string) string {
 	return names.JoinWithConstraints(names.ServiceConstraints, clusterName, cellName, planetscalev2.VtgateComponentName)
@@ -57,6 +67,12 @@ func UpdateService(obj *corev1.Service, labels map[string]string) {
 
 	obj.Spec.Selector = labels
 
+	// Keep any node ports that Kubernetes picked.
+	nodePorts := make(map[string]int32, len(obj.Spec.Ports))
+	for _, port := range obj.Spec.Ports {
+		nodePorts[port.Name] = port.NodePort
+	}
+
 	// Using named TargetPorts instead of hard-coded port numbers means that
 	// each Pod can decide what port numbers to use.
 	// The Pod just needs to assign the proper name to those ports so we
@@ -81,4 +97,107 @@ func UpdateService(obj *corev1.Service, labels map[string]string) {
 			TargetPort: intstr.FromString(planetscalev2.DefaultMysqlPortName),
 		},
 	}
+	for i := range obj.Spec.Ports {
+		obj.Spec.Ports[i].NodePort = nodePorts[obj.Spec.Ports[i].Name]
+	}
+}
+
+// UpdateServiceExposure updates the parts of the vtgate Service that expose
+// it outside of the Kubernetes cluster.
+func UpdateServiceExposure(obj *corev1.Service, exposure *planetscalev2.VitessGatewayExposure) {
+	serviceType := corev1.ServiceTypeClusterIP
+	if exposure != nil && exposure.ServiceType != "" {
+		serviceType = exposure.ServiceType
+	}
+	obj.Spec.Type = serviceType
+
+	if serviceType == corev1.ServiceTypeClusterIP {
+		// Clear the fields that only external Services may have.
+		for i := range obj.Spec.Ports {
+			obj.Spec.Ports[i].NodePort = 0
+		}
+		obj.Spec.ExternalTrafficPolicy = ""
+	} else {
+		obj.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+		if exposure.ExternalTrafficPolicy != "" {
+			obj.Spec.ExternalTrafficPolicy = exposure.ExternalTrafficPolicy
+		}
+		if nodePorts := exposure.NodePorts; nodePorts != nil {
+			for i := range obj.Spec.Ports {
+				port := &obj.Spec.Ports[i]
+				var nodePort int32
+				switch port.Name {
+				case planetscalev2.DefaultMysqlPortName:
+					nodePort = nodePorts.MySQL
+				case planetscalev2.DefaultGrpcPortName:
+					nodePort = nodePorts.GRPC
+				case planetscalev2.DefaultWebPortName:
+					nodePort = nodePorts.Web
+				}
+				if nodePort != 0 {
+					port.NodePort = nodePort
+				}
+			}
+		}
+	}
+
+	if serviceType != corev1.ServiceTypeLoadBalancer {
+		obj.Spec.LoadBalancerClass = nil
+		obj.Spec.LoadBalancerSourceRanges = nil
+		obj.Spec.AllocateLoadBalancerNodePorts = nil
+	}
+	if serviceType != corev1.ServiceTypeLoadBalancer || obj.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyLocal {
+		obj.Spec.HealthCheckNodePort = 0
+	}
+	if serviceType != corev1.ServiceTypeLoadBalancer || exposure.LoadBalancer == nil {
+		updateExposureAnnotations(obj, nil)
+		return
+	}
+
+	lb := exposure.LoadBalancer
+	if obj.Spec.LoadBalancerClass == nil {
+		// This can only be set when the Service becomes a LoadBalancer.
+		obj.Spec.LoadBalancerClass = lb.Class
+	}
+	obj.Spec.LoadBalancerSourceRanges = lb.SourceRanges
+	annotations := make(map[string]string, len(lb.Annotations)+len(internalLoadBalancerAnnotations))
+	update.Annotations(&annotations, lb.Annotations)
+	if lb.Internal {
+		update.Annotations(&annotations, internalLoadBalancerAnnotations)
+	}
+	updateExposureAnnotations(obj, annotations)
+}
+
+// updateExposureAnnotations sets the given annotations on the Service, and
+// removes any that we set before but are no longer wanted. Annotations set by
+// anyone else are left alone.
+func updateExposureAnnotations(obj *corev1.Service, annotations map[string]string) {
+	if previous := obj.Annotations[ExposureAnnotationsAnnotation]; previous != "" {
+		for _, key := range strings.Split(previous, ",") {
+			if _, ok := annotations[key]; !ok {
+				delete(obj.Annotations, key)
+			}
+		}
+	}
+	delete(obj.Annotations, ExposureAnnotationsAnnotation)
+	if len(annotations) == 0 {
+		return
+	}
+
+	update.Annotations(&obj.Annotations, annotations)
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	obj.Annotations[ExposureAnnotationsAnnotation] = strings.Join(keys, ",")
+}
+
+// internalLoadBalancerAnnotations are the annotations that cloud provider
+// load balancer controllers look for to make a load balancer internal.
+var internalLoadBalancerAnnotations = map[string]string{
+	"service.beta.kubernetes.io/aws-load-balancer-internal":   "true",
+	"service.beta.kubernetes.io/aws-load-balancer-scheme":     "internal",
+	"networking.gke.io/load-balancer-type":                    "Internal",
+	"service.beta.kubernetes.io/azure-load-balancer-internal": "true",
 }
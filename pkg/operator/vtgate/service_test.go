@@ -0,0 +1,130 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestUpdateServiceExposure(t *testing.T) {
+	labels := map[string]string{"component": "vtgate"}
+	svc := NewService(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, labels)
+	exposure := &planetscalev2.VitessGatewayExposure{
+		ServiceType: corev1.ServiceTypeLoadBalancer,
+		LoadBalancer: &planetscalev2.VitessGatewayLoadBalancer{
+			Internal:     true,
+			SourceRanges: []string{"10.0.0.0/8"},
+		},
+		NodePorts: &planetscalev2.VitessGatewayNodePorts{MySQL: 30306},
+	}
+	UpdateServiceExposure(svc, exposure)
+
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		t.Errorf("Type = %v; want LoadBalancer", svc.Spec.Type)
+	}
+	if got := svc.Annotations["networking.gke.io/load-balancer-type"]; got != "Internal" {
+		t.Errorf("GKE load balancer type annotation = %q; want Internal", got)
+	}
+	if got := svc.Spec.LoadBalancerSourceRanges; len(got) != 1 || got[0] != "10.0.0.0/8" {
+		t.Errorf("LoadBalancerSourceRanges = %v; want [10.0.0.0/8]", got)
+	}
+
+	// Node ports that Kubernetes picked are kept on update.
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Name == planetscalev2.DefaultWebPortName {
+			svc.Spec.Ports[i].NodePort = 31000
+		}
+	}
+	UpdateService(svc, labels)
+	UpdateServiceExposure(svc, exposure)
+	for _, port := range svc.Spec.Ports {
+		var want int32
+		switch port.Name {
+		case planetscalev2.DefaultMysqlPortName:
+			want = 30306
+		case planetscalev2.DefaultWebPortName:
+			want = 31000
+		}
+		if port.NodePort != want {
+			t.Errorf("NodePort for %v = %v; want %v", port.Name, port.NodePort, want)
+		}
+	}
+
+	// Going back to ClusterIP clears the fields external Services have.
+	UpdateServiceExposure(svc, nil)
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP || svc.Spec.ExternalTrafficPolicy != "" || svc.Spec.LoadBalancerSourceRanges != nil {
+		t.Errorf("Spec = %+v; want plain ClusterIP Service", svc.Spec)
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.NodePort != 0 {
+			t.Errorf("NodePort for %v = %v; want 0", port.Name, port.NodePort)
+		}
+	}
+}
+
+func TestUpdateServiceExposureAnnotations(t *testing.T) {
+	labels := map[string]string{"component": "vtgate"}
+	svc := NewService(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, labels)
+	svc.Annotations = map[string]string{"example.com/owner": "someone-else"}
+	exposure := &planetscalev2.VitessGatewayExposure{
+		ServiceType: corev1.ServiceTypeLoadBalancer,
+		LoadBalancer: &planetscalev2.VitessGatewayLoadBalancer{
+			Internal:    true,
+			Annotations: map[string]string{"example.com/lb-tier": "gold"},
+		},
+	}
+	UpdateServiceExposure(svc, exposure)
+	for key := range internalLoadBalancerAnnotations {
+		if _, ok := svc.Annotations[key]; !ok {
+			t.Errorf("internal load balancer annotation %v is missing", key)
+		}
+	}
+
+	// Going from internal to external removes the internal annotations, but
+	// keeps the ones still asked for and the ones set by someone else.
+	exposure.LoadBalancer.Internal = false
+	UpdateServiceExposure(svc, exposure)
+	for key := range internalLoadBalancerAnnotations {
+		if _, ok := svc.Annotations[key]; ok {
+			t.Errorf("internal load balancer annotation %v is still set after switching to external", key)
+		}
+	}
+	if got := svc.Annotations["example.com/lb-tier"]; got != "gold" {
+		t.Errorf("load balancer annotation = %q; want gold", got)
+	}
+	if got := svc.Annotations["example.com/owner"]; got != "someone-else" {
+		t.Errorf("unrelated annotation = %q; want someone-else", got)
+	}
+
+	// Annotations that are no longer asked for are removed too.
+	exposure.LoadBalancer.Annotations = nil
+	UpdateServiceExposure(svc, exposure)
+	if _, ok := svc.Annotations["example.com/lb-tier"]; ok {
+		t.Errorf("load balancer annotation is still set after being removed from the spec")
+	}
+	if _, ok := svc.Annotations[ExposureAnnotationsAnnotation]; ok {
+		t.Errorf("%v is still set with no exposure annotations", ExposureAnnotationsAnnotation)
+	}
+	if got := svc.Annotations["example.com/owner"]; got != "someone-else" {
+		t.Errorf("unrelated annotation = %q; want someone-else", got)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// ServingConditionType is the Pod condition that the vtgate readiness
+	// gate waits for. The operator sets it once enough shards are healthy.
+	ServingConditionType corev1.PodConditionType = "planetscale.com/vtgate-serving"
+)
+
+// IsServing returns whether a vtgate Pod has been let through the readiness
+// gate.
+func IsServing(pod *corev1.Pod) bool {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == ServingConditionType {
+			return pod.Status.Conditions[i].Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// SetServing lets a vtgate Pod through the readiness gate.
+func SetServing(pod *corev1.Pod, reason, message string, now time.Time) {
+	condition := corev1.PodCondition{
+		Type:               ServingConditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Time{Time: now},
+		Reason:             reason,
+		Message:            message,
+	}
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == ServingConditionType {
+			pod.Status.Conditions[i] = condition
+			return
+		}
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, condition)
+}
+
+// ShardHealthy returns whether a shard has a primary and a Ready tablet, and
+// whether it counts towards the readiness gate at all. Shards that don't
+// serve their key range yet aren't counted.
+func ShardHealthy(shard *planetscalev2.VitessKeyspaceShardStatus) (healthy, counted bool) {
+	if shard.ServingWrites == corev1.ConditionFalse {
+		return false, false
+	}
+	return shard.HasMaster == corev1.ConditionTrue && shard.ReadyTablets > 0, true
+}
+
+// ServingGateOpen returns whether enough shards are healthy to let vtgate Pods
+// through the readiness gate. With no shards to count, it's always open.
+func ServingGateOpen(healthyShards, shards, minHealthyShardPercent int32) bool {
+	return shards == 0 || healthyShards*100 >= shards*minHealthyShardPercent
+}
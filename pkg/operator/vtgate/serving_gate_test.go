@@ -0,0 +1,82 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestShardHealthy(t *testing.T) {
+	table := []struct {
+		shard            planetscalev2.VitessKeyspaceShardStatus
+		healthy, counted bool
+	}{
+		{
+			shard:   planetscalev2.VitessKeyspaceShardStatus{HasMaster: corev1.ConditionTrue, ServingWrites: corev1.ConditionTrue, ReadyTablets: 1},
+			healthy: true, counted: true,
+		},
+		{
+			shard:   planetscalev2.VitessKeyspaceShardStatus{HasMaster: corev1.ConditionTrue, ServingWrites: corev1.ConditionUnknown},
+			healthy: false, counted: true,
+		},
+		{
+			shard:   planetscalev2.VitessKeyspaceShardStatus{HasMaster: corev1.ConditionTrue, ServingWrites: corev1.ConditionFalse, ReadyTablets: 1},
+			healthy: false, counted: false,
+		},
+	}
+	for _, test := range table {
+		healthy, counted := ShardHealthy(&test.shard)
+		if healthy != test.healthy || counted != test.counted {
+			t.Errorf("ShardHealthy(%+v) = %v, %v; want %v, %v", test.shard, healthy, counted, test.healthy, test.counted)
+		}
+	}
+
+	if !ServingGateOpen(0, 0, 100) {
+		t.Errorf("ServingGateOpen() with no shards = false; want true")
+	}
+	if ServingGateOpen(3, 4, 100) || !ServingGateOpen(3, 4, 75) {
+		t.Errorf("ServingGateOpen(3, 4) doesn't match 75%% threshold")
+	}
+}
+
+func TestServingGate(t *testing.T) {
+	spec := &Spec{
+		Cell:           &planetscalev2.VitessCellSpec{},
+		Authentication: &planetscalev2.VitessGatewayAuthentication{},
+		ServingGate:    true,
+	}
+	obj := NewDeployment(client.ObjectKey{Namespace: "ns", Name: "vtgate"}, spec)
+	if got := obj.Spec.Template.Spec.ReadinessGates; len(got) != 1 || got[0].ConditionType != ServingConditionType {
+		t.Errorf("ReadinessGates = %v; want %v", got, ServingConditionType)
+	}
+
+	pod := &corev1.Pod{}
+	if IsServing(pod) {
+		t.Errorf("IsServing() = true; want false")
+	}
+	SetServing(pod, "ShardsHealthy", "", time.Now())
+	SetServing(pod, "ShardsHealthy", "", time.Now())
+	if !IsServing(pod) || len(pod.Status.Conditions) != 1 {
+		t.Errorf("Conditions = %v; want one serving condition", pod.Status.Conditions)
+	}
+}
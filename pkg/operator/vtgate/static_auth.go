@@ -0,0 +1,84 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// StaticAuthReloadInterval is how often vtgate checks the static auth
+	// file for changes.
+	StaticAuthReloadInterval = 30 * time.Second
+	// StaticAuthPickupDelay is how long after a vtgate Pod is signaled that
+	// it's counted as having picked up new static auth credentials. The
+	// kubelet refreshes the mounted file when the Pod is updated, and then
+	// vtgate reloads it within one reload interval; the rest is slack.
+	StaticAuthPickupDelay = 2 * StaticAuthReloadInterval
+
+	// StaticAuthHashAnnotation is the annotation on vtgate Pods that records
+	// the hash of the static auth Secret contents the Pod was last signaled
+	// about.
+	StaticAuthHashAnnotation = "planetscale.com/static-auth-hash"
+	// StaticAuthSignaledAnnotation is the annotation on vtgate Pods that
+	// records when the Pod was last signaled about new static auth
+	// credentials, in RFC 3339 format.
+	StaticAuthSignaledAnnotation = "planetscale.com/static-auth-signaled"
+)
+
+// SignalStaticAuth marks a vtgate Pod as signaled about the static auth
+// Secret contents with the given hash. Updating the Pod makes the kubelet
+// refresh its Secret volumes right away, rather than at its next periodic
+// sync. It returns false if the Pod was already signaled about them.
+func SignalStaticAuth(pod *corev1.Pod, hash string, now time.Time) bool {
+	if pod.Annotations[StaticAuthHashAnnotation] == hash {
+		return false
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[StaticAuthHashAnnotation] = hash
+	pod.Annotations[StaticAuthSignaledAnnotation] = now.UTC().Format(time.RFC3339)
+	return true
+}
+
+// StaticAuthPickupRemaining returns how much longer to wait until a vtgate Pod
+// can be counted as having picked up the static auth Secret contents with the
+// given hash, or zero if it already has.
+//
+// A Pod that was created after the contents changed started with them. A Pod
+// that was signaled about them has picked them up once StaticAuthPickupDelay
+// has passed. Pods that weren't signaled about them yet have to wait the full
+// delay.
+func StaticAuthPickupRemaining(pod *corev1.Pod, hash string, changed, now time.Time) time.Duration {
+	if !pod.CreationTimestamp.Time.Before(changed) {
+		return 0
+	}
+	if pod.Annotations[StaticAuthHashAnnotation] != hash {
+		return StaticAuthPickupDelay
+	}
+	signaled, err := time.Parse(time.RFC3339, pod.Annotations[StaticAuthSignaledAnnotation])
+	if err != nil {
+		return StaticAuthPickupDelay
+	}
+	if remaining := signaled.Add(StaticAuthPickupDelay).Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStaticAuthPickup(t *testing.T) {
+	changed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.Time{Time: changed.Add(-time.Hour)},
+		},
+	}
+
+	if got := StaticAuthPickupRemaining(pod, "new", changed, changed); got != StaticAuthPickupDelay {
+		t.Errorf("StaticAuthPickupRemaining() before signal = %v; want %v", got, StaticAuthPickupDelay)
+	}
+
+	now := changed.Add(10 * time.Second)
+	if !SignalStaticAuth(pod, "new", now) {
+		t.Errorf("SignalStaticAuth() = false; want true")
+	}
+	if SignalStaticAuth(pod, "new", now.Add(time.Minute)) {
+		t.Errorf("SignalStaticAuth() again = true; want false")
+	}
+	if got, want := StaticAuthPickupRemaining(pod, "new", changed, now.Add(20*time.Second)), StaticAuthPickupDelay-20*time.Second; got != want {
+		t.Errorf("StaticAuthPickupRemaining() after signal = %v; want %v", got, want)
+	}
+	if got := StaticAuthPickupRemaining(pod, "new", changed, now.Add(StaticAuthPickupDelay)); got != 0 {
+		t.Errorf("StaticAuthPickupRemaining() after delay = %v; want 0", got)
+	}
+
+	newPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.Time{Time: changed.Add(time.Second)},
+		},
+	}
+	if got := StaticAuthPickupRemaining(newPod, "new", changed, changed.Add(time.Second)); got != 0 {
+		t.Errorf("StaticAuthPickupRemaining() for new Pod = %v; want 0", got)
+	}
+}
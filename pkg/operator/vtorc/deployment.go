@@ -17,6 +17,7 @@ limitations under the License.
 package vtorc
 
 import (
+	"strconv"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -48,8 +49,15 @@ func DeploymentName(clusterName, keyspace string, shardKeyRange planetscalev2.Vi
 	return deploymentName(clusterName, keyspace, shardKeyRange.SafeName(), cellName)
 }
 
+// KeyspaceDeploymentName returns the name of the VTOrc Deployment that
+// watches every shard of a keyspace.
+func KeyspaceDeploymentName(clusterName, keyspace string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspace, planetscalev2.VtorcComponentName)
+}
+
 // Spec specifies all the internal parameters needed to deploy VTOrc,
 // as opposed to the API type planetscalev2.VitessDashboardSpec, which is the public API.
+// An empty Shard means VTOrc watches every shard of the Keyspace.
 type Spec struct {
 	GlobalLockserver  planetscalev2.VitessLockserverParams
 	Keyspace          string
@@ -60,6 +68,7 @@ type Spec struct {
 	ImagePullPolicy   corev1.PullPolicy
 	ImagePullSecrets  []corev1.LocalObjectReference
 	Labels            map[string]string
+	Replicas          int32
 	Resources         corev1.ResourceRequirements
 	Affinity          *corev1.Affinity
 	ExtraFlags        map[string]string
@@ -115,6 +124,7 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 	obj.Spec.Template.Annotations = spec.Annotations
 
 	// Deployment options.
+	obj.Spec.Replicas = pointer.Int32Ptr(spec.Replicas)
 	obj.Spec.RevisionHistoryLimit = pointer.Int32Ptr(0)
 
 	// Reset the list of volumes in the template so we remove old ones.
@@ -210,14 +220,52 @@ func UpdateDeployment(obj *appsv1.Deployment, spec *Spec) {
 }
 
 func (spec *Spec) flags() vitess.Flags {
+	clustersToWatch := spec.Keyspace
+	if spec.Shard != "" {
+		clustersToWatch += "/" + spec.Shard
+	}
 	return vitess.Flags{
 		"topo_implementation":        spec.GlobalLockserver.Implementation,
 		"topo_global_server_address": spec.GlobalLockserver.Address,
 		"topo_global_root":           spec.GlobalLockserver.RootPath,
 		"port":                       planetscalev2.DefaultWebPort,
 
-		"clusters_to_watch": spec.Keyspace + "/" + spec.Shard,
+		"clusters_to_watch": clustersToWatch,
 
 		"logtostderr": true,
 	}
 }
+
+// RecoveryFlags returns the vtorc flags for the given recovery settings.
+// Settings that aren't set follow the reparent settings the operator uses
+// itself, so vtorc and the operator make the same choices when reparenting.
+func RecoveryFlags(recovery *planetscalev2.VitessOrchestratorRecovery, reparent *planetscalev2.ReparentSettings) map[string]string {
+	flags := make(map[string]string)
+	if reparent != nil {
+		if reparent.PlannedReparentTimeout != nil {
+			flags["wait-replicas-timeout"] = reparent.PlannedReparentTimeout.Duration.String()
+		}
+		if reparent.TolerableReplicationLag != nil {
+			flags["tolerable-replication-lag"] = reparent.TolerableReplicationLag.Duration.String()
+		}
+	}
+	if recovery == nil {
+		return flags
+	}
+	if recovery.AllowEmergencyReparent != nil {
+		flags["allow-emergency-reparent"] = strconv.FormatBool(*recovery.AllowEmergencyReparent)
+	}
+	if recovery.PreventCrossCellFailover {
+		flags["prevent-cross-cell-failover"] = "true"
+	}
+	if recovery.WaitReplicasTimeout != nil {
+		flags["wait-replicas-timeout"] = recovery.WaitReplicasTimeout.Duration.String()
+	}
+	if recovery.TolerableReplicationLag != nil {
+		flags["tolerable-replication-lag"] = recovery.TolerableReplicationLag.Duration.String()
+	}
+	if recovery.RecoveryPeriodBlockDuration != nil {
+		flags["recovery-period-block-duration"] = recovery.RecoveryPeriodBlockDuration.Duration.String()
+	}
+	return flags
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtorc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestRecoveryFlags(t *testing.T) {
+	reparent := &planetscalev2.ReparentSettings{
+		PlannedReparentTimeout:  &metav1.Duration{Duration: 30 * time.Second},
+		TolerableReplicationLag: &metav1.Duration{Duration: 15 * time.Second},
+	}
+
+	tests := []struct {
+		name     string
+		recovery *planetscalev2.VitessOrchestratorRecovery
+		want     map[string]string
+	}{
+		{
+			name: "follows reparent settings",
+			want: map[string]string{
+				"wait-replicas-timeout":     "30s",
+				"tolerable-replication-lag": "15s",
+			},
+		},
+		{
+			name: "recovery settings win",
+			recovery: &planetscalev2.VitessOrchestratorRecovery{
+				AllowEmergencyReparent:      pointer.BoolPtr(false),
+				PreventCrossCellFailover:    true,
+				TolerableReplicationLag:     &metav1.Duration{Duration: time.Minute},
+				RecoveryPeriodBlockDuration: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+			want: map[string]string{
+				"allow-emergency-reparent":       "false",
+				"prevent-cross-cell-failover":    "true",
+				"wait-replicas-timeout":          "30s",
+				"tolerable-replication-lag":      "1m0s",
+				"recovery-period-block-duration": "5m0s",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecoveryFlags(tt.recovery, reparent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RecoveryFlags() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyspaceDeployment(t *testing.T) {
+	obj := NewDeployment(client.ObjectKey{Namespace: "ns", Name: "vtorc"}, &Spec{
+		Keyspace: "commerce",
+		Replicas: 2,
+	})
+
+	if got := *obj.Spec.Replicas; got != 2 {
+		t.Errorf("Replicas = %v; want 2", got)
+	}
+	want := "--clusters_to_watch=commerce"
+	for _, arg := range obj.Spec.Template.Spec.Containers[0].Args {
+		if arg == want {
+			return
+		}
+	}
+	t.Errorf("Args = %v; want %v", obj.Spec.Template.Spec.Containers[0].Args, want)
+}
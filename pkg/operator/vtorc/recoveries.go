@@ -0,0 +1,128 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtorc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// RecoveriesSuspendedAnnotation is the Pod annotation in which the
+	// operator records that it told the vtorc in that Pod to stop running
+	// recoveries. vtorc doesn't remember this across restarts, and neither
+	// does the annotation, since it goes away with the Pod.
+	RecoveriesSuspendedAnnotation = "planetscale.com/recoveries-suspended"
+	// RecoveryCountsAnnotation is the Pod annotation in which the operator
+	// records how many recoveries the vtorc in that Pod had run the last
+	// time it checked, as a JSON-encoded RecoveryCounts.
+	RecoveryCountsAnnotation = "planetscale.com/recovery-counts"
+
+	// recoveriesTimeout is how long to wait for vtorc to answer.
+	recoveriesTimeout = 5 * time.Second
+)
+
+var recoveriesClient = &http.Client{Timeout: recoveriesTimeout}
+
+// SetRecoveriesEnabled tells the vtorc at the given host whether it may run
+// recoveries. Each vtorc Deployment watches one shard unless its scope is
+// Keyspace, so this only affects the shard it watches.
+func SetRecoveriesEnabled(ctx context.Context, host string, enabled bool) error {
+	if host == "" {
+		return fmt.Errorf("vtorc has no host")
+	}
+	path := "/api/disable-global-recoveries"
+	if enabled {
+		path = "/api/enable-global-recoveries"
+	}
+	url := fmt.Sprintf("http://%v%v", net.JoinHostPort(host, strconv.Itoa(planetscalev2.DefaultWebPort)), path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := recoveriesClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call vtorc %v: %v", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to call vtorc %v: %v", host, resp.Status)
+	}
+	return nil
+}
+
+// RecoveryCounts are the numbers of recoveries a vtorc has run since it
+// started, by recovery type.
+type RecoveryCounts struct {
+	Succeeded map[string]int64 `json:"succeeded,omitempty"`
+	Failed    map[string]int64 `json:"failed,omitempty"`
+}
+
+// GetRecoveryCounts asks the vtorc at the given host how many recoveries it
+// has run. vtorc doesn't keep a list of recoveries we can ask for, but it
+// exports counters of them.
+func GetRecoveryCounts(ctx context.Context, host string) (*RecoveryCounts, error) {
+	if host == "" {
+		return nil, fmt.Errorf("vtorc has no host")
+	}
+	url := fmt.Sprintf("http://%v/debug/vars", net.JoinHostPort(host, strconv.Itoa(planetscalev2.DefaultWebPort)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := recoveriesClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats of vtorc %v: %v", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get stats of vtorc %v: %v", host, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats of vtorc %v: %v", host, err)
+	}
+	counts, err := parseRecoveryCounts(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse stats of vtorc %v: %v", host, err)
+	}
+	return counts, nil
+}
+
+// parseRecoveryCounts reads the recovery counters out of the expvars that
+// vtorc exports at /debug/vars.
+func parseRecoveryCounts(data []byte) (*RecoveryCounts, error) {
+	var vars struct {
+		SuccessfulRecoveries map[string]int64
+		FailedRecoveries     map[string]int64
+	}
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return nil, err
+	}
+	return &RecoveryCounts{
+		Succeeded: vars.SuccessfulRecoveries,
+		Failed:    vars.FailedRecoveries,
+	}, nil
+}
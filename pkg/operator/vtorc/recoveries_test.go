@@ -0,0 +1,38 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtorc
+
+import "testing"
+
+func TestParseRecoveryCounts(t *testing.T) {
+	data := []byte(`{
+		"PendingRecoveries": 0,
+		"RecoveriesCount": {"RecoverDeadPrimary": 2, "FixReplica": 1},
+		"SuccessfulRecoveries": {"RecoverDeadPrimary": 1, "FixReplica": 1},
+		"FailedRecoveries": {"RecoverDeadPrimary": 1}
+	}`)
+	counts, err := parseRecoveryCounts(data)
+	if err != nil {
+		t.Fatalf("parseRecoveryCounts() error: %v", err)
+	}
+	if got := counts.Succeeded["FixReplica"]; got != 1 {
+		t.Errorf("Succeeded[FixReplica] = %v; want 1", got)
+	}
+	if got := counts.Failed["RecoverDeadPrimary"]; got != 1 {
+		t.Errorf("Failed[RecoverDeadPrimary] = %v; want 1", got)
+	}
+}
@@ -20,6 +20,30 @@ import (
 	"planetscale.dev/vitess-operator/pkg/operator/lazy"
 )
 
+const (
+	// ReplicatingAnnotation is the Pod annotation in which the operator
+	// records whether replication was healthy the last time it was checked.
+	// The value is a ConditionStatus.
+	ReplicatingAnnotation = "planetscale.com/replicating"
+	// ReplicationErrorAnnotation is the Pod annotation in which the operator
+	// records why replication was not healthy the last time it was checked.
+	ReplicationErrorAnnotation = "planetscale.com/replication-error"
+	// ReplicationDelayAnnotation is the Pod annotation in which the operator
+	// records the replication delay it has configured on a delayed replica.
+	ReplicationDelayAnnotation = "planetscale.com/replication-delay"
+	// ErrantGTIDsAnnotation is the Pod annotation in which the operator
+	// records any errant GTIDs it found on the tablet the last time it checked.
+	ErrantGTIDsAnnotation = "planetscale.com/errant-gtids"
+	// MysqldConfigDriftAnnotation is the Pod annotation in which the operator
+	// records any MySQL global variables it found didn't match the tablet's
+	// mysqld config the last time it checked.
+	MysqldConfigDriftAnnotation = "planetscale.com/mysqld-config-drift"
+	// RestoreProgressAnnotation is the Pod annotation in which the operator
+	// records the progress of a tablet that's restoring from backup, as a
+	// JSON-encoded VitessTabletRestoreStatus.
+	RestoreProgressAnnotation = "planetscale.com/restore-progress"
+)
+
 func init() {
 	tabletAnnotations.Add(func(s lazy.Spec) map[string]string {
 		spec := s.(*Spec)
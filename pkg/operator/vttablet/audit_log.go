@@ -0,0 +1,106 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"strconv"
+	"strings"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// Both plugins are built as the same library.
+	auditLogPluginLibrary = "audit_log.so"
+
+	// Like the query logs, the audit log lives at the root of the data
+	// volume, since MySQL won't create a missing directory for it.
+	auditLogPath       = vtDataRootPath + "/audit.log"
+	auditLogStderrPath = "/dev/stderr"
+)
+
+// MysqldAuditLogConfig returns the mysqld options that load and configure the
+// audit log plugin of a MySQL instance, by normalized option name. These take
+// precedence over the same options in the structured mysqld config.
+//
+// Settings that the plugin wouldn't accept, as reported by the audit log's
+// Problems, are left out.
+func MysqldAuditLogConfig(mysqld *planetscalev2.MysqldSpec) map[string]string {
+	if mysqld == nil || mysqld.AuditLog == nil {
+		return nil
+	}
+	auditLog := mysqld.AuditLog
+	percona := auditLog.Plugin == planetscalev2.PerconaMysqldAuditLogPlugin
+
+	// FORCE_PLUS_PERMANENT keeps MySQL from starting, or the plugin from
+	// being uninstalled, so there's never a time when events aren't logged.
+	config := map[string]string{
+		"plugin_load_add": auditLogPluginLibrary,
+		"audit_log":       "FORCE_PLUS_PERMANENT",
+	}
+	if percona {
+		config["audit_log_handler"] = "FILE"
+	}
+	if auditLog.Format != "" && (percona || auditLog.Format != "CSV") {
+		config["audit_log_format"] = auditLog.Format
+	}
+	if auditLog.Policy != "" {
+		config["audit_log_policy"] = auditLog.Policy
+	}
+
+	switch auditLog.Destination {
+	case planetscalev2.StderrMysqldAuditLogDestination:
+		// The plugin can't rotate a stream.
+		config["audit_log_file"] = auditLogStderrPath
+		config["audit_log_rotate_on_size"] = "0"
+	default:
+		config["audit_log_file"] = auditLogPath
+		if auditLog.RotateOnSize != nil {
+			rotateOnSize := auditLog.RotateOnSize.Value()
+			config["audit_log_rotate_on_size"] = strconv.FormatInt(rotateOnSize, 10)
+			if auditLog.Rotations != nil {
+				if percona {
+					config["audit_log_rotations"] = strconv.Itoa(int(*auditLog.Rotations))
+				} else {
+					// MySQL Enterprise Audit limits the total size of rotated
+					// files instead of how many there are.
+					config["audit_log_max_size"] = strconv.FormatInt(rotateOnSize*int64(*auditLog.Rotations), 10)
+				}
+			}
+		}
+	}
+
+	if filter := auditLog.Filter; filter != nil {
+		setAuditLogFilter(config, "accounts", filter.IncludeAccounts, filter.ExcludeAccounts)
+		if percona {
+			setAuditLogFilter(config, "commands", filter.IncludeCommands, filter.ExcludeCommands)
+			setAuditLogFilter(config, "databases", filter.IncludeDatabases, filter.ExcludeDatabases)
+		}
+	}
+	return config
+}
+
+// setAuditLogFilter sets the include or the exclude option for a kind of
+// audit log filter. The plugins don't allow both, so include wins.
+func setAuditLogFilter(config map[string]string, kind string, include, exclude []string) {
+	switch {
+	case len(include) > 0:
+		config["audit_log_include_"+kind] = strings.Join(include, ",")
+	case len(exclude) > 0:
+		config["audit_log_exclude_"+kind] = strings.Join(exclude, ",")
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMysqldAuditLogConfig(t *testing.T) {
+	rotateOnSize := resource.MustParse("1Mi")
+	auditLog := &planetscalev2.MysqldAuditLog{
+		Plugin:       planetscalev2.PerconaMysqldAuditLogPlugin,
+		Destination:  planetscalev2.FileMysqldAuditLogDestination,
+		Format:       "CSV",
+		Policy:       "QUERIES",
+		RotateOnSize: &rotateOnSize,
+		Rotations:    pointer.Int32Ptr(3),
+		Filter: &planetscalev2.MysqldAuditLogFilter{
+			IncludeAccounts:  []string{"app@%", "dba@localhost"},
+			ExcludeAccounts:  []string{"ignored@%"},
+			ExcludeDatabases: []string{"_vt"},
+		},
+	}
+	mysqld := &planetscalev2.MysqldSpec{AuditLog: auditLog}
+
+	want := map[string]string{
+		"plugin_load_add":             "audit_log.so",
+		"audit_log":                   "FORCE_PLUS_PERMANENT",
+		"audit_log_handler":           "FILE",
+		"audit_log_format":            "CSV",
+		"audit_log_policy":            "QUERIES",
+		"audit_log_file":              "/vt/vtdataroot/audit.log",
+		"audit_log_rotate_on_size":    "1048576",
+		"audit_log_rotations":         "3",
+		"audit_log_include_accounts":  "app@%,dba@localhost",
+		"audit_log_exclude_databases": "_vt",
+	}
+	if got := MysqldAuditLogConfig(mysqld); !reflect.DeepEqual(got, want) {
+		t.Errorf("MysqldAuditLogConfig() = %v; want %v", got, want)
+	}
+
+	// MySQL Enterprise Audit has no CSV format, and only filters accounts.
+	auditLog.Plugin = planetscalev2.MysqlEnterpriseMysqldAuditLogPlugin
+	want = map[string]string{
+		"plugin_load_add":            "audit_log.so",
+		"audit_log":                  "FORCE_PLUS_PERMANENT",
+		"audit_log_policy":           "QUERIES",
+		"audit_log_file":             "/vt/vtdataroot/audit.log",
+		"audit_log_rotate_on_size":   "1048576",
+		"audit_log_max_size":         "3145728",
+		"audit_log_include_accounts": "app@%,dba@localhost",
+	}
+	if got := MysqldAuditLogConfig(mysqld); !reflect.DeepEqual(got, want) {
+		t.Errorf("MysqldAuditLogConfig() for MySQL Enterprise = %v; want %v", got, want)
+	}
+
+	auditLog.Destination = planetscalev2.StderrMysqldAuditLogDestination
+	got := MysqldAuditLogConfig(mysqld)
+	if got["audit_log_file"] != "/dev/stderr" || got["audit_log_rotate_on_size"] != "0" || got["audit_log_max_size"] != "" {
+		t.Errorf("MysqldAuditLogConfig() to stderr = %v; want no rotation", got)
+	}
+}
+
+func TestRenderMysqldConfigAuditLog(t *testing.T) {
+	mysqld := &planetscalev2.MysqldSpec{
+		Config: map[string]string{
+			"plugin-load-add": "semisync_source.so",
+		},
+		AuditLog: &planetscalev2.MysqldAuditLog{Plugin: planetscalev2.PerconaMysqldAuditLogPlugin},
+	}
+	config, problems := RenderMysqldConfig(mysqld, nil)
+	wantConfig := "audit_log = FORCE_PLUS_PERMANENT\n" +
+		"audit_log_file = /vt/vtdataroot/audit.log\n" +
+		"audit_log_handler = FILE\n" +
+		"plugin_load_add = audit_log.so\n"
+	if config != wantConfig {
+		t.Errorf("RenderMysqldConfig() config = %q; want %q", config, wantConfig)
+	}
+	wantProblems := []string{"plugin-load-add: can't be set because auditLog manages it"}
+	if !reflect.DeepEqual(problems, wantProblems) {
+		t.Errorf("RenderMysqldConfig() problems = %v; want %v", problems, wantProblems)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package vttablet
 
 import (
+	"encoding/json"
 	"fmt"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
@@ -39,6 +40,52 @@ func xtrabackupFlags(spec *Spec, backupThreads, restoreThreads int) vitess.Flags
 	return flags
 }
 
+// backupClusterName returns the name of the cluster under which to look for
+// backups. Backups are stored under the name of the cluster that took them,
+// which is only different when restoring another cluster's backups.
+func (spec *Spec) backupClusterName() string {
+	if spec.BackupClusterName != "" {
+		return spec.BackupClusterName
+	}
+	return spec.Labels[planetscalev2.ClusterLabel]
+}
+
+// mysqlShellFlags returns the flags for the mysqlshell backup engine, which
+// runs mysqlsh against the local mysqld to dump and load the database.
+func mysqlShellFlags(spec *Spec, backupThreads, restoreThreads int) vitess.Flags {
+	location, storageOptions, ok := vitessbackup.MySQLShellLocation(spec.BackupLocation, spec.backupClusterName())
+	if !ok {
+		// Leave the location unset, so vttablet reports the misconfiguration.
+		return nil
+	}
+
+	dumpOptions := map[string]interface{}{
+		"threads": backupThreads,
+	}
+	// These are the defaults Vitess uses for loads, which we need to keep.
+	loadOptions := map[string]interface{}{
+		"threads":       restoreThreads,
+		"loadUsers":     true,
+		"updateGtidSet": "replace",
+		"skipBinlog":    true,
+		"progressFile":  "",
+	}
+	for k, v := range storageOptions {
+		dumpOptions[k] = v
+		loadOptions[k] = v
+	}
+	// Maps are marshaled with sorted keys, so the flags are deterministic.
+	dumpFlags, _ := json.Marshal(dumpOptions)
+	loadFlags, _ := json.Marshal(loadOptions)
+
+	return vitess.Flags{
+		"mysql-shell-backup-location": location,
+		"mysql-shell-flags":           fmt.Sprintf("--defaults-file=/dev/null --js --socket=%s --user=%s", mysqlSocketPath, mysqlShellUser),
+		"mysql-shell-dump-flags":      string(dumpFlags),
+		"mysql-shell-load-flags":      string(loadFlags),
+	}
+}
+
 func init() {
 	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
 		spec := s.(*Spec)
@@ -51,26 +98,28 @@ func init() {
 			"wait_for_backup_interval":     waitForBackupInterval,
 			"backup_engine_implementation": string(spec.BackupEngine),
 		}
-		if spec.BackupEngine == planetscalev2.VitessBackupEngineXtraBackup {
-			// When vttablets take backups, we let them keep serving, so we
-			// limit to single-threaded to reduce the impact.
-			backupThreads := 1
-			// When vttablets are restoring, they can't serve at the same time
-			// anyway, so let the restore use all available CPUs for this Pod.
-			// This is cheating a bit, since xtrabackup technically counts
-			// against only the vttablet container, but we allow CPU bursting,
-			// and we happen to know that our mysqld container is not using its
-			// CPU request (reservation) during restore since it's stopped.
-			mysqlCPU := spec.Mysqld.Resources.Requests[corev1.ResourceCPU]
-			vttabletCPU := spec.Vttablet.Resources.Requests[corev1.ResourceCPU]
-			restoreThreads := int(mysqlCPU.Value() + vttabletCPU.Value())
-			if restoreThreads < 1 {
-				restoreThreads = 1
-			}
+		// When vttablets take backups, we let them keep serving, so we
+		// limit to single-threaded to reduce the impact.
+		backupThreads := 1
+		// When vttablets are restoring, they can't serve at the same time
+		// anyway, so let the restore use all available CPUs for this Pod.
+		// This is cheating a bit, since the backup engine technically counts
+		// against only the vttablet container, but we allow CPU bursting,
+		// and we happen to know that our mysqld container is not using its
+		// CPU request (reservation) during restore since it's stopped.
+		mysqlCPU := spec.Mysqld.Resources.Requests[corev1.ResourceCPU]
+		vttabletCPU := spec.Vttablet.Resources.Requests[corev1.ResourceCPU]
+		restoreThreads := int(mysqlCPU.Value() + vttabletCPU.Value())
+		if restoreThreads < 1 {
+			restoreThreads = 1
+		}
+		switch spec.BackupEngine {
+		case planetscalev2.VitessBackupEngineXtraBackup:
 			flags.Merge(xtrabackupFlags(spec, backupThreads, restoreThreads))
+		case planetscalev2.VitessBackupEngineMySQLShell:
+			flags.Merge(mysqlShellFlags(spec, backupThreads, restoreThreads))
 		}
-		clusterName := spec.Labels[planetscalev2.ClusterLabel]
-		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, clusterName)
+		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, spec.backupClusterName())
 		return flags.Merge(storageLocationFlags)
 	})
 
@@ -83,17 +132,20 @@ func init() {
 		flags := vitess.Flags{
 			"backup_engine_implementation": string(spec.BackupEngine),
 		}
-		if spec.BackupEngine == planetscalev2.VitessBackupEngineXtraBackup {
-			// A vtbackup Pod is given the same resources as the mysqld
-			// container for a vttablet in the shard would be given.
-			// We let vtbackup use all available CPUs during both backup and
-			// restore, since it is not serving queries anyway.
-			vtbackupCPU := spec.Mysqld.Resources.Requests[corev1.ResourceCPU]
-			threads := int(vtbackupCPU.Value())
-			if threads < 1 {
-				threads = 1
-			}
+		// A vtbackup Pod is given the same resources as the mysqld
+		// container for a vttablet in the shard would be given.
+		// We let vtbackup use all available CPUs during both backup and
+		// restore, since it is not serving queries anyway.
+		vtbackupCPU := spec.Mysqld.Resources.Requests[corev1.ResourceCPU]
+		threads := int(vtbackupCPU.Value())
+		if threads < 1 {
+			threads = 1
+		}
+		switch spec.BackupEngine {
+		case planetscalev2.VitessBackupEngineXtraBackup:
 			flags.Merge(xtrabackupFlags(spec, threads, threads))
+		case planetscalev2.VitessBackupEngineMySQLShell:
+			flags.Merge(mysqlShellFlags(spec, threads, threads))
 		}
 		clusterName := spec.Labels[planetscalev2.ClusterLabel]
 		storageLocationFlags := vitessbackup.StorageFlags(spec.BackupLocation, clusterName)
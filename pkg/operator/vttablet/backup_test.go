@@ -0,0 +1,71 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMySQLShellFlags(t *testing.T) {
+	spec := &Spec{
+		Labels: map[string]string{planetscalev2.ClusterLabel: "example"},
+		BackupLocation: &planetscalev2.VitessBackupLocation{
+			S3: &planetscalev2.S3BackupLocation{
+				Region:    "us-east-1",
+				Bucket:    "backups",
+				KeyPrefix: "prod/",
+			},
+		},
+	}
+
+	flags := mysqlShellFlags(spec, 1, 4)
+	if got, want := flags["mysql-shell-backup-location"], "prod/mysqlshell/example"; got != want {
+		t.Errorf("mysql-shell-backup-location = %v; want %v", got, want)
+	}
+	if got, want := flags["mysql-shell-dump-flags"], `{"s3BucketName":"backups","s3Region":"us-east-1","threads":1}`; got != want {
+		t.Errorf("mysql-shell-dump-flags = %v; want %v", got, want)
+	}
+	if got, want := flags["mysql-shell-load-flags"], `{"loadUsers":true,"progressFile":"","s3BucketName":"backups","s3Region":"us-east-1","skipBinlog":true,"threads":4,"updateGtidSet":"replace"}`; got != want {
+		t.Errorf("mysql-shell-load-flags = %v; want %v", got, want)
+	}
+
+	// Restoring another cluster's backups reads its dumps too.
+	spec.BackupClusterName = "source"
+	if got, want := mysqlShellFlags(spec, 1, 4)["mysql-shell-backup-location"], "prod/mysqlshell/source"; got != want {
+		t.Errorf("mysql-shell-backup-location = %v; want %v", got, want)
+	}
+
+	// Storage that mysqlsh can't write to gets no location.
+	spec.BackupLocation = &planetscalev2.VitessBackupLocation{
+		GCS: &planetscalev2.GCSBackupLocation{Bucket: "backups"},
+	}
+	if flags := mysqlShellFlags(spec, 1, 4); flags != nil {
+		t.Errorf("mysqlShellFlags() = %v; want nil", flags)
+	}
+
+	// Volume locations keep dumps on the same volume.
+	spec.BackupLocation = &planetscalev2.VitessBackupLocation{
+		Volume: &corev1.VolumeSource{},
+	}
+	if got, want := mysqlShellFlags(spec, 1, 4)["mysql-shell-backup-location"], "/vt/backups/mysqlshell/source"; got != want {
+		t.Errorf("mysql-shell-backup-location = %v; want %v", got, want)
+	}
+}
@@ -21,12 +21,25 @@ import (
 )
 
 const (
-	vttabletContainerName = "vttablet"
+	VttabletContainerName = "vttablet"
 	vttabletCommand       = "/vt/bin/vttablet"
 
 	vtbackupContainerName = "vtbackup"
 	vtbackupCommand       = "/vt/bin/vtbackup"
 
+	backupVerifierContainerName = "backup-verifier"
+	backupVerifierCommand       = "/vt/bin/vitess-operator"
+
+	backupDownloaderContainerName = "backup-downloader"
+	backupUploaderContainerName   = "backup-uploader"
+	// backupReplicationScratchDir is where a backup replication Pod keeps
+	// the files it's copying, on the same volume vtbackup would restore into.
+	backupReplicationScratchDir = vtDataRootPath + "/backup-replicator"
+	// backupReplicationVolumePrefix is prepended to the names of volumes for
+	// the destination location of a backup replication Pod, so they don't
+	// collide with those for the source location.
+	backupReplicationVolumePrefix = "dest-"
+
 	MysqldContainerName = "mysqld"
 	mysqldCommand       = "/vt/bin/mysqlctld"
 
@@ -42,6 +55,9 @@ const (
 
 	serviceMap          = "grpc-queryservice,grpc-tabletmanager,grpc-updatestream"
 	healthCheckInterval = 5 * time.Second
+	// unhealthyThreshold is the default replication lag after which vttablet
+	// considers itself unhealthy.
+	unhealthyThreshold = 2 * time.Hour
 
 	// defaultTerminationGracePeriodSeconds is how long Kubernetes will wait for the
 	// tablet processes (vttablet, mysqlctld) to terminate gracefully after
@@ -88,10 +104,20 @@ const (
 	externalDatastoreCredentialsDirName = "external-datastore-credentials"
 	externalDatastoreCACertDirName      = "external-datastore-ca-cert"
 
+	// systemCABundlePath is where the Vitess images keep the system's
+	// trusted CA certificates.
+	systemCABundlePath = "/etc/ssl/certs/ca-certificates.crt"
+
+	// externalPrimaryHostEnvVar holds the host of the current primary of an
+	// externally managed MySQL, as published by external failover tooling.
+	externalPrimaryHostEnvVar = "EXTERNAL_PRIMARY_HOST"
+
 	enableSSLBitflag = 2048
 
 	mysqldConfigOverridesAnnotationName      = "planetscale.com/mysqld-config-overrides"
 	mysqldConfigOverridesAnnotationFieldPath = "metadata.annotations['" + mysqldConfigOverridesAnnotationName + "']"
+	mysqldConfigAnnotationName               = "planetscale.com/mysqld-config"
+	mysqldConfigAnnotationFieldPath          = "metadata.annotations['" + mysqldConfigAnnotationName + "']"
 
 	vtbackupTimeout            = 2 * time.Hour
 	vtbackupReplicationTimeout = 1 * time.Hour
@@ -111,6 +137,8 @@ const (
 	xtrabackupStripeCount = 8
 	xtrabackupUser        = "vt_dba"
 
+	mysqlShellUser = "vt_dba"
+
 	// mysqlctlWaitTime is how long mysqlctld will wait for mysqld to start up
 	// before assuming it's stuck and trying to restart it. We set this fairly
 	// high because it can take a while to do crash recovery and it's rarely
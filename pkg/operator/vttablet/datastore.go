@@ -19,6 +19,7 @@ package vttablet
 import (
 	corev1 "k8s.io/api/core/v1"
 
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
 	"planetscale.dev/vitess-operator/pkg/operator/lazy"
 	"planetscale.dev/vitess-operator/pkg/operator/secrets"
 	"planetscale.dev/vitess-operator/pkg/operator/vitess"
@@ -56,6 +57,26 @@ func init() {
 		return mounts
 	})
 
+	// Inject the primary host that external failover tooling publishes, if
+	// any, so we can use it in the flags below. The value is read when the
+	// container starts, so the operator restarts the tablets to rewire them.
+	vttabletEnvVars.Add(func(s lazy.Spec) []corev1.EnvVar {
+		spec := s.(*Spec)
+		source := spec.externalPrimaryHostSource()
+		if source == nil {
+			return nil
+		}
+		return []corev1.EnvVar{
+			{
+				Name: externalPrimaryHostEnvVar,
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: source.ConfigMapKeyRef,
+					SecretKeyRef:    source.SecretKeyRef,
+				},
+			},
+		}
+	})
+
 	// sets datastore specific vttablet flags.
 	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
 		spec := s.(*Spec)
@@ -129,5 +150,25 @@ func externalDatastoreFlags(spec *Spec) vitess.Flags {
 		"vreplication_tablet_type":    vreplicationTabletType,
 	}
 
+	if spec.ExternalDatastore.SSLMode != "" {
+		flags["db_ssl_mode"] = spec.ExternalDatastore.SSLMode
+	}
+	if spec.ExternalDatastore.UsesSystemCAs() {
+		flags["db_ssl_ca"] = systemCABundlePath
+	}
+	if spec.externalPrimaryHostSource() != nil {
+		flags["db_host"] = "$(" + externalPrimaryHostEnvVar + ")"
+	}
+
 	return flags
 }
+
+// externalPrimaryHostSource returns where to read the host of the current
+// primary from, if this is an externalmaster tablet whose datastore has
+// failover tooling that publishes it.
+func (spec *Spec) externalPrimaryHostSource() *planetscalev2.ExternalHostSource {
+	if spec.Type != planetscalev2.ExternalMasterPoolType || spec.ExternalDatastore == nil || spec.ExternalDatastore.Failover == nil {
+		return nil
+	}
+	return spec.ExternalDatastore.Failover.PrimaryHostFrom
+}
@@ -17,8 +17,6 @@ limitations under the License.
 package vttablet
 
 import (
-	"fmt"
-
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	corev1 "k8s.io/api/core/v1"
@@ -70,12 +68,24 @@ func init() {
 			"init_tablet_type": spec.Type.InitTabletType(),
 
 			"health_check_interval": healthCheckInterval,
+		}
+	})
 
-			"queryserver-config-max-result-size":  queryserverConfigMaxResultSize,
-			"queryserver-config-query-timeout":    fmt.Sprintf("%ds", queryserverConfigQueryTimeout),
-			"queryserver-config-pool-size":        queryserverConfigPoolSize,
-			"queryserver-config-stream-pool-size": queryserverConfigStreamPoolSize,
-			"queryserver-config-transaction-cap":  queryserverConfigTransactionCap,
+	// Query serving flags, from the pool's settings if it has any.
+	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
+		spec := s.(*Spec)
+		return QueryServingFlags(spec.Vttablet.QueryServing)
+	})
+
+	// Delayed replicas always lag by at least the delay, so only count lag
+	// beyond that against the tablet's health.
+	vttabletFlags.Add(func(s lazy.Spec) vitess.Flags {
+		spec := s.(*Spec)
+		if spec.ReplicationDelay <= 0 {
+			return nil
+		}
+		return vitess.Flags{
+			"unhealthy_threshold": unhealthyThreshold + spec.ReplicationDelay,
 		}
 	})
 
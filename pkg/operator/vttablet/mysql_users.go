@@ -0,0 +1,142 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// MysqlUsersAnnotation is the annotation on the primary tablet Pod in
+	// which the operator records a hash of the MySQL users it last created
+	// there.
+	MysqlUsersAnnotation = "planetscale.com/mysql-users"
+	// MysqlUserAccountsAnnotation is the annotation on the primary tablet Pod
+	// that lists the MySQL accounts the operator manages, so accounts that
+	// are removed from the spec can be dropped.
+	MysqlUserAccountsAnnotation = "planetscale.com/mysql-user-accounts"
+)
+
+// MysqlAccount is a MySQL user at a host pattern.
+type MysqlAccount struct {
+	User string `json:"user"`
+	Host string `json:"host"`
+}
+
+// String returns the account as it's written in SQL statements.
+func (a MysqlAccount) String() string {
+	return fmt.Sprintf("%v@%v", sqltypes.EncodeStringSQL(a.User), sqltypes.EncodeStringSQL(a.Host))
+}
+
+// MysqlUserAccounts returns the MySQL accounts of the given users, sorted.
+func MysqlUserAccounts(users []planetscalev2.MysqlUser) []MysqlAccount {
+	var accounts []MysqlAccount
+	for i := range users {
+		for _, host := range users[i].Hosts {
+			accounts = append(accounts, MysqlAccount{User: users[i].Username, Host: host})
+		}
+	}
+	SortMysqlAccounts(accounts)
+	return accounts
+}
+
+// FormatMysqlAccounts returns the value of the annotation that lists the
+// given MySQL accounts.
+func FormatMysqlAccounts(accounts []MysqlAccount) string {
+	if len(accounts) == 0 {
+		return ""
+	}
+	// Marshaling plain strings can't fail.
+	data, _ := json.Marshal(accounts)
+	return string(data)
+}
+
+// ParseMysqlAccounts parses the annotation that lists MySQL accounts. An
+// empty or invalid value yields no accounts.
+func ParseMysqlAccounts(value string) []MysqlAccount {
+	if value == "" {
+		return nil
+	}
+	var accounts []MysqlAccount
+	if err := json.Unmarshal([]byte(value), &accounts); err != nil {
+		return nil
+	}
+	return accounts
+}
+
+// SortMysqlAccounts sorts MySQL accounts by user and then host.
+func SortMysqlAccounts(accounts []MysqlAccount) {
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].User != accounts[j].User {
+			return accounts[i].User < accounts[j].User
+		}
+		return accounts[i].Host < accounts[j].Host
+	})
+}
+
+// MysqlUserQueries returns the statements that create or update each account
+// of a MySQL user with the given password, and make its privileges match the
+// grants. Privileges are revoked before they're granted again, so a change
+// in grants briefly leaves the user without them.
+func MysqlUserQueries(user *planetscalev2.MysqlUser, password string) []string {
+	identified := "IDENTIFIED BY " + sqltypes.EncodeStringSQL(password)
+	var queries []string
+	for _, host := range user.Hosts {
+		account := MysqlAccount{User: user.Username, Host: host}.String()
+		queries = append(queries,
+			fmt.Sprintf("CREATE USER IF NOT EXISTS %v %v", account, identified),
+			fmt.Sprintf("ALTER USER %v %v", account, identified),
+			fmt.Sprintf("REVOKE ALL PRIVILEGES, GRANT OPTION FROM %v", account),
+		)
+		for i := range user.Grants {
+			queries = append(queries, mysqlGrantQuery(&user.Grants[i], account))
+		}
+	}
+	return queries
+}
+
+// MysqlUserDropQuery returns the statement that drops an account.
+func MysqlUserDropQuery(account MysqlAccount) string {
+	return "DROP USER IF EXISTS " + account.String()
+}
+
+func mysqlGrantQuery(grant *planetscalev2.MysqlGrant, account string) string {
+	privileges := make([]string, 0, len(grant.Privileges))
+	for _, privilege := range grant.Privileges {
+		privileges = append(privileges, strings.ToUpper(strings.Join(strings.Fields(privilege), " ")))
+	}
+	query := fmt.Sprintf("GRANT %v ON %v.%v TO %v", strings.Join(privileges, ", "), mysqlGrantTarget(grant.Database), mysqlGrantTarget(grant.Table), account)
+	if grant.WithGrantOption {
+		query += " WITH GRANT OPTION"
+	}
+	return query
+}
+
+func mysqlGrantTarget(name string) string {
+	if name == "*" {
+		return name
+	}
+	return sqlescape.EscapeID(name)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMysqlUserQueries(t *testing.T) {
+	user := &planetscalev2.MysqlUser{
+		Username:  "app",
+		Hosts:     []string{"10.0.%"},
+		SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-user"}, Key: "password"},
+		Grants: []planetscalev2.MysqlGrant{
+			{Privileges: []string{"select", "insert"}, Database: "commerce", Table: "*"},
+			{Privileges: []string{"all  privileges"}, Database: "scratch`db", Table: "notes", WithGrantOption: true},
+		},
+	}
+
+	want := []string{
+		`CREATE USER IF NOT EXISTS 'app'@'10.0.%' IDENTIFIED BY 'pa\'ss'`,
+		`ALTER USER 'app'@'10.0.%' IDENTIFIED BY 'pa\'ss'`,
+		`REVOKE ALL PRIVILEGES, GRANT OPTION FROM 'app'@'10.0.%'`,
+		"GRANT SELECT, INSERT ON `commerce`.* TO 'app'@'10.0.%'",
+		"GRANT ALL PRIVILEGES ON `scratch``db`.`notes` TO 'app'@'10.0.%' WITH GRANT OPTION",
+	}
+	if got := MysqlUserQueries(user, `pa'ss`); !reflect.DeepEqual(got, want) {
+		t.Errorf("MysqlUserQueries() = %q; want %q", got, want)
+	}
+	if got, want := MysqlUserDropQuery(MysqlAccount{User: "old", Host: "%"}), "DROP USER IF EXISTS 'old'@'%'"; got != want {
+		t.Errorf("MysqlUserDropQuery() = %q; want %q", got, want)
+	}
+}
+
+func TestMysqlAccountsAnnotation(t *testing.T) {
+	users := []planetscalev2.MysqlUser{
+		{Username: "dba", Hosts: []string{"localhost", "%"}},
+		{Username: "app", Hosts: []string{"%"}},
+	}
+	accounts := MysqlUserAccounts(users)
+	want := []MysqlAccount{
+		{User: "app", Host: "%"},
+		{User: "dba", Host: "%"},
+		{User: "dba", Host: "localhost"},
+	}
+	if !reflect.DeepEqual(accounts, want) {
+		t.Errorf("MysqlUserAccounts() = %v; want %v", accounts, want)
+	}
+	if got := ParseMysqlAccounts(FormatMysqlAccounts(accounts)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMysqlAccounts(FormatMysqlAccounts()) = %v; want %v", got, want)
+	}
+
+	if got := FormatMysqlAccounts(nil); got != "" {
+		t.Errorf("FormatMysqlAccounts(nil) = %q; want empty", got)
+	}
+	if got := ParseMysqlAccounts("not json"); got != nil {
+		t.Errorf("ParseMysqlAccounts() of an invalid value = %v; want nil", got)
+	}
+}
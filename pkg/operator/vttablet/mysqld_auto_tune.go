@@ -0,0 +1,123 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	mebibyte = 1 << 20
+	gibibyte = 1 << 30
+
+	// autoTuneBufferPoolChunk is the default innodb_buffer_pool_chunk_size.
+	// MySQL rounds the buffer pool up to a multiple of it anyway.
+	autoTuneBufferPoolChunk = 128 * mebibyte
+	// autoTuneLargeMemory is the memory above which the buffer pool gets a
+	// larger share, since the rest of mysqld needs a roughly fixed amount.
+	autoTuneLargeMemory = 4 * gibibyte
+	// autoTuneMinRedoLogCapacity and autoTuneMaxRedoLogCapacity bound the
+	// redo log capacity, which is a quarter of the buffer pool.
+	autoTuneMinRedoLogCapacity = 256 * mebibyte
+	autoTuneMaxRedoLogCapacity = 16 * gibibyte
+	// autoTuneConnectionMemory is the memory set aside per connection, out of
+	// what the buffer pool doesn't use.
+	autoTuneConnectionMemory = 2 * mebibyte
+	// autoTuneExtraConnections is how many connections to allow beyond the
+	// vttablet query serving pools, for the dba and app pools, replication,
+	// and people.
+	autoTuneExtraConnections = 100
+)
+
+// AutoTuneMysqld returns my.cnf settings sized from the resources of a local
+// MySQL, using requests where they're set and limits otherwise. The
+// connection limit leaves room for the given vttablet query serving pools.
+func AutoTuneMysqld(mysqld *planetscalev2.MysqldSpec, queryServing *planetscalev2.VttabletQueryServingSpec) (map[string]string, error) {
+	if mysqld == nil {
+		return nil, nil
+	}
+	memory, ok := autoTuneResource(&mysqld.Resources, corev1.ResourceMemory)
+	if !ok {
+		return nil, fmt.Errorf("autoTuneMysql needs a memory request or limit for mysqld")
+	}
+	memoryBytes := memory.Value()
+
+	bufferPool := memoryBytes / 2
+	if memoryBytes >= autoTuneLargeMemory {
+		bufferPool = memoryBytes * 3 / 4
+	}
+	bufferPool = max(bufferPool/autoTuneBufferPoolChunk*autoTuneBufferPoolChunk, autoTuneBufferPoolChunk)
+
+	redoLogCapacity := min(max(bufferPool/4/mebibyte*mebibyte, autoTuneMinRedoLogCapacity), autoTuneMaxRedoLogCapacity)
+
+	connections := max((memoryBytes-bufferPool)/autoTuneConnectionMemory, vttabletConnections(queryServing)+autoTuneExtraConnections)
+
+	config := map[string]string{
+		"innodb_buffer_pool_size": strconv.FormatInt(bufferPool, 10),
+		// This is only known to MySQL 8.0.30 and later, so older versions
+		// ignore it and keep their default redo log size.
+		"loose-innodb_redo_log_capacity": strconv.FormatInt(redoLogCapacity, 10),
+		"max_connections":                strconv.FormatInt(connections, 10),
+	}
+
+	if cpu, ok := autoTuneResource(&mysqld.Resources, corev1.ResourceCPU); ok {
+		cpus := (cpu.MilliValue() + 999) / 1000
+		ioThreads := strconv.FormatInt(min(max(cpus, 4), 16), 10)
+		config["innodb_read_io_threads"] = ioThreads
+		config["innodb_write_io_threads"] = ioThreads
+		// MySQL ignores multiple instances for buffer pools under 1GiB.
+		if bufferPool >= gibibyte {
+			config["innodb_buffer_pool_instances"] = strconv.FormatInt(min(max(cpus, 1), 8), 10)
+		}
+	}
+	return config, nil
+}
+
+// autoTuneResource returns the request for a resource, or else its limit.
+func autoTuneResource(resources *corev1.ResourceRequirements, name corev1.ResourceName) (*resource.Quantity, bool) {
+	if quantity, ok := resources.Requests[name]; ok && !quantity.IsZero() {
+		return &quantity, true
+	}
+	if quantity, ok := resources.Limits[name]; ok && !quantity.IsZero() {
+		return &quantity, true
+	}
+	return nil, false
+}
+
+// vttabletConnections returns how many MySQL connections the vttablet query
+// serving pools can open at once.
+func vttabletConnections(queryServing *planetscalev2.VttabletQueryServingSpec) int64 {
+	poolSize, streamPoolSize, transactionCap := int64(queryserverConfigPoolSize), int64(queryserverConfigStreamPoolSize), int64(queryserverConfigTransactionCap)
+	if queryServing != nil {
+		if queryServing.PoolSize != nil {
+			poolSize = int64(*queryServing.PoolSize)
+		}
+		if queryServing.StreamPoolSize != nil {
+			streamPoolSize = int64(*queryServing.StreamPoolSize)
+		}
+		if queryServing.TransactionCap != nil {
+			transactionCap = int64(*queryServing.TransactionCap)
+		}
+	}
+	return poolSize + streamPoolSize + transactionCap
+}
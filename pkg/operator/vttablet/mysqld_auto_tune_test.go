@@ -0,0 +1,104 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestAutoTuneMysqld(t *testing.T) {
+	table := []struct {
+		name         string
+		resources    corev1.ResourceRequirements
+		queryServing *planetscalev2.VttabletQueryServingSpec
+		want         map[string]string
+	}{
+		{
+			name: "large with CPUs",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+					corev1.ResourceCPU:    resource.MustParse("1500m"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+			},
+			want: map[string]string{
+				"innodb_buffer_pool_size":        "6442450944",
+				"innodb_buffer_pool_instances":   "2",
+				"innodb_read_io_threads":         "4",
+				"innodb_write_io_threads":        "4",
+				"loose-innodb_redo_log_capacity": "1610612736",
+				"max_connections":                "1024",
+			},
+		},
+		{
+			name: "small from limits",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			queryServing: &planetscalev2.VttabletQueryServingSpec{
+				PoolSize:       pointer.Int32Ptr(16),
+				StreamPoolSize: pointer.Int32Ptr(16),
+				TransactionCap: pointer.Int32Ptr(50),
+			},
+			want: map[string]string{
+				"innodb_buffer_pool_size":        "536870912",
+				"loose-innodb_redo_log_capacity": "268435456",
+				"max_connections":                "256",
+			},
+		},
+		{
+			name: "vttablet pools need more connections",
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			want: map[string]string{
+				"innodb_buffer_pool_size":        "536870912",
+				"loose-innodb_redo_log_capacity": "268435456",
+				"max_connections":                "592",
+			},
+		},
+	}
+
+	for _, test := range table {
+		got, err := AutoTuneMysqld(&planetscalev2.MysqldSpec{Resources: test.resources}, test.queryServing)
+		if err != nil {
+			t.Errorf("%v: AutoTuneMysqld() error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: AutoTuneMysqld() = %v; want %v", test.name, got, test.want)
+		}
+	}
+
+	if _, err := AutoTuneMysqld(&planetscalev2.MysqldSpec{}, nil); err == nil {
+		t.Errorf("AutoTuneMysqld() without memory resources: want error")
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// unsafeMysqldConfig are the my.cnf options that can't be set through the
+// structured mysqld config, along with why. Names are normalized with
+// normalizeMysqldOption.
+var unsafeMysqldConfig = map[string]string{
+	"bind_address":              "mysqlctld manages how MySQL listens",
+	"binlog_format":             "Vitess requires row-based replication",
+	"datadir":                   "the operator manages the data volume",
+	"enforce_gtid_consistency":  "Vitess requires GTID-based replication",
+	"gtid_mode":                 "Vitess requires GTID-based replication",
+	"innodb_data_home_dir":      "the operator manages the data volume",
+	"innodb_log_group_home_dir": "the operator manages the data volume",
+	"log_bin":                   "Vitess requires binary logs",
+	"log_error":                 "the operator collects MySQL logs from stderr",
+	"log_replica_updates":       "Vitess requires replicas to write binary logs",
+	"log_slave_updates":         "Vitess requires replicas to write binary logs",
+	"port":                      "mysqlctld manages how MySQL listens",
+	"read_only":                 "Vitess manages which tablets are writable",
+	"relay_log":                 "the operator manages the data volume",
+	"server_id":                 "Vitess assigns server IDs to tablets",
+	"skip_grant_tables":         "it disables authentication",
+	"skip_networking":           "mysqlctld manages how MySQL listens",
+	"socket":                    "mysqlctld manages how MySQL listens",
+	"super_read_only":           "Vitess manages which tablets are writable",
+}
+
+var mysqldOptionPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// mysqldConfigFuncs are the functions available to mysqld config templates.
+var mysqldConfigFuncs = template.FuncMap{
+	"percent": func(percent, value int64) int64 {
+		return value * percent / 100
+	},
+	"min": func(a, b int64) int64 {
+		if a < b {
+			return a
+		}
+		return b
+	},
+	"max": func(a, b int64) int64 {
+		if a > b {
+			return a
+		}
+		return b
+	},
+}
+
+// RenderMysqldConfig returns a my.cnf snippet with the structured config
+// settings of a MySQL instance, with any templated values filled in from its
+// resources. Tuned settings, if any, are included unless the config sets the
+// same option. Query log and audit log settings always win over the config.
+// It also returns the problems with any settings it left out.
+func RenderMysqldConfig(mysqld *planetscalev2.MysqldSpec, tuned map[string]string) (string, []string) {
+	managed := map[string]string{}
+	managedConfig := map[string]string{}
+	for _, m := range []struct {
+		field  string
+		config map[string]string
+	}{
+		{field: "queryLogs", config: MysqldQueryLogConfig(mysqld)},
+		{field: "auditLog", config: MysqldAuditLogConfig(mysqld)},
+	} {
+		for option, value := range m.config {
+			managed[option] = m.field
+			managedConfig[option] = value
+		}
+	}
+	if mysqld == nil || (len(mysqld.Config) == 0 && len(tuned) == 0 && len(managedConfig) == 0) {
+		return "", nil
+	}
+
+	config := make(map[string]string, len(mysqld.Config)+len(tuned)+len(managedConfig))
+	explicit := make(map[string]bool, len(mysqld.Config)+len(managedConfig))
+	var problems []string
+	for option, value := range managedConfig {
+		config[option] = value
+		explicit[option] = true
+	}
+	for option, value := range mysqld.Config {
+		if field, ok := managed[normalizeMysqldOption(option)]; ok {
+			problems = append(problems, fmt.Sprintf("%v: can't be set because %v manages it", option, field))
+			continue
+		}
+		config[option] = value
+		explicit[normalizeMysqldOption(option)] = true
+	}
+	for option, value := range tuned {
+		if !explicit[normalizeMysqldOption(option)] {
+			config[option] = value
+		}
+	}
+	options := make([]string, 0, len(config))
+	for option := range config {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+
+	data := mysqldConfigTemplateData(&mysqld.Resources)
+	var b strings.Builder
+	for _, option := range options {
+		value, err := renderMysqldOption(option, config[option], data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", option, err))
+			continue
+		}
+		fmt.Fprintf(&b, "%v = %v\n", option, value)
+	}
+	sort.Strings(problems)
+	return b.String(), problems
+}
+
+// renderMysqldOption checks that a mysqld option is safe to set, and returns
+// its value with any template filled in.
+func renderMysqldOption(option, value string, data map[string]int64) (string, error) {
+	if !mysqldOptionPattern.MatchString(option) {
+		return "", fmt.Errorf("not a valid option name")
+	}
+	if reason, unsafe := unsafeMysqldConfig[normalizeMysqldOption(option)]; unsafe {
+		return "", fmt.Errorf("can't be set because %v", reason)
+	}
+
+	if strings.Contains(value, "{{") {
+		tmpl, err := template.New(option).Funcs(mysqldConfigFuncs).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid template: %v", err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("failed to fill in template: %v", err)
+		}
+		value = b.String()
+	}
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, "\r\n") {
+		return "", fmt.Errorf("value must be a single non-empty line")
+	}
+	return value, nil
+}
+
+// mysqldConfigTemplateData returns the values that mysqld config templates
+// can use. Values are left out if the resources don't set them, so templates
+// that need them fail instead of using zero.
+func mysqldConfigTemplateData(resources *corev1.ResourceRequirements) map[string]int64 {
+	data := map[string]int64{}
+	if memory, ok := resources.Limits[corev1.ResourceMemory]; ok {
+		data["Memory"] = memory.Value()
+	} else if memory, ok := resources.Requests[corev1.ResourceMemory]; ok {
+		data["Memory"] = memory.Value()
+	}
+	cpu, ok := resources.Limits[corev1.ResourceCPU]
+	if !ok {
+		cpu, ok = resources.Requests[corev1.ResourceCPU]
+	}
+	if ok {
+		data["CPUs"] = (cpu.MilliValue() + 999) / 1000
+	}
+	return data
+}
+
+// normalizeMysqldOption returns the name MySQL uses for an option, which
+// treats dashes and underscores the same, and ignores a "loose" prefix.
+func normalizeMysqldOption(option string) string {
+	option = strings.ToLower(strings.ReplaceAll(option, "-", "_"))
+	return strings.TrimPrefix(option, "loose_")
+}
+
+// mysqldConfig returns the my.cnf snippet for the structured mysqld config
+// and auto-tuning of a tablet, leaving out any settings with problems.
+func (spec *Spec) mysqldConfig() string {
+	if spec.Mysqld == nil {
+		return ""
+	}
+	var tuned map[string]string
+	if spec.AutoTuneMysql {
+		var queryServing *planetscalev2.VttabletQueryServingSpec
+		if spec.Vttablet != nil {
+			queryServing = spec.Vttablet.QueryServing
+		}
+		// If there's nothing to tune from, the shard reports it.
+		tuned, _ = AutoTuneMysqld(spec.Mysqld, queryServing)
+	}
+	config, _ := RenderMysqldConfig(spec.Mysqld, tuned)
+	return config
+}
+
+// PodMysqldConfig returns the structured mysqld config and auto-tuned
+// settings that a tablet Pod was started with, as a map from option name to
+// value.
+func PodMysqldConfig(pod *corev1.Pod) map[string]string {
+	config := map[string]string{}
+	for _, line := range strings.Split(pod.Annotations[mysqldConfigAnnotationName], "\n") {
+		option, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		config[strings.TrimSpace(option)] = strings.TrimSpace(value)
+	}
+	return config
+}
+
+// MysqldVariableName returns the name of the global variable that a my.cnf
+// option sets.
+func MysqldVariableName(option string) string {
+	return normalizeMysqldOption(option)
+}
@@ -23,34 +23,40 @@ import (
 )
 
 func init() {
-	// Mount tablet-pool-specific my.cnf overrides.
+	// Mount tablet-pool-specific my.cnf settings and overrides.
 	// Since these ought to be small, and updates should roll out slowly like
-	// other Pod spec changes, we put it in an annotation that *doesn't* get
-	// updated in-place, and then we mount it as a file in the Container.
+	// other Pod spec changes, we put them in annotations that *don't* get
+	// updated in-place, and then we mount them as files in the Container.
 	tabletAnnotations.Add(func(s lazy.Spec) map[string]string {
 		spec := s.(*Spec)
-		if spec.Mysqld == nil || len(spec.Mysqld.ConfigOverrides) == 0 {
-			return nil
+		annotations := map[string]string{}
+		if config := spec.mysqldConfig(); config != "" {
+			annotations[mysqldConfigAnnotationName] = config
 		}
-		return map[string]string{
-			mysqldConfigOverridesAnnotationName: spec.Mysqld.ConfigOverrides,
+		if spec.Mysqld != nil && len(spec.Mysqld.ConfigOverrides) != 0 {
+			annotations[mysqldConfigOverridesAnnotationName] = spec.Mysqld.ConfigOverrides
 		}
+		return annotations
 	})
 	extraMyCnf.Add(func(s lazy.Spec) []string {
 		spec := s.(*Spec)
-		if spec.Mysqld == nil || len(spec.Mysqld.ConfigOverrides) == 0 {
+		files := podConfigFiles(spec)
+		if len(files) == 0 {
 			return nil
 		}
+		// The overrides come after the structured config, so they win.
+		paths := make([]string, 0, len(files)+1)
+		for _, file := range files {
+			paths = append(paths, "/pod-config/"+file.Path)
+		}
 		// Append an extra config file for vtbackup at the end to override any
 		// settings from the custom ones; will be empty for normal vttablet
-		return []string{
-			"/pod-config/mysqld-config-overrides",
-			vtbackupExtraMyCnfFile,
-		}
+		return append(paths, vtbackupExtraMyCnfFile)
 	})
 	tabletVolumes.Add(func(s lazy.Spec) []corev1.Volume {
 		spec := s.(*Spec)
-		if spec.Mysqld == nil || len(spec.Mysqld.ConfigOverrides) == 0 {
+		files := podConfigFiles(spec)
+		if len(files) == 0 {
 			return nil
 		}
 		return []corev1.Volume{
@@ -58,9 +64,7 @@ func init() {
 				Name: "pod-config",
 				VolumeSource: corev1.VolumeSource{
 					DownwardAPI: &corev1.DownwardAPIVolumeSource{
-						Items: []corev1.DownwardAPIVolumeFile{
-							{Path: "mysqld-config-overrides", FieldRef: &corev1.ObjectFieldSelector{FieldPath: mysqldConfigOverridesAnnotationFieldPath}},
-						},
+						Items: files,
 					},
 				},
 			},
@@ -68,7 +72,7 @@ func init() {
 	})
 	tabletVolumeMounts.Add(func(s lazy.Spec) []corev1.VolumeMount {
 		spec := s.(*Spec)
-		if spec.Mysqld == nil || len(spec.Mysqld.ConfigOverrides) == 0 {
+		if len(podConfigFiles(spec)) == 0 {
 			return nil
 		}
 		return []corev1.VolumeMount{
@@ -80,3 +84,16 @@ func init() {
 		}
 	})
 }
+
+// podConfigFiles returns the my.cnf files to mount from the tablet's
+// annotations, in the order they should be applied.
+func podConfigFiles(spec *Spec) []corev1.DownwardAPIVolumeFile {
+	var files []corev1.DownwardAPIVolumeFile
+	if spec.mysqldConfig() != "" {
+		files = append(files, corev1.DownwardAPIVolumeFile{Path: "mysqld-config", FieldRef: &corev1.ObjectFieldSelector{FieldPath: mysqldConfigAnnotationFieldPath}})
+	}
+	if spec.Mysqld != nil && len(spec.Mysqld.ConfigOverrides) != 0 {
+		files = append(files, corev1.DownwardAPIVolumeFile{Path: "mysqld-config-overrides", FieldRef: &corev1.ObjectFieldSelector{FieldPath: mysqldConfigOverridesAnnotationFieldPath}})
+	}
+	return files
+}
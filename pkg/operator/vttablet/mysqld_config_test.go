@@ -0,0 +1,69 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestRenderMysqldConfig(t *testing.T) {
+	mysqld := &planetscalev2.MysqldSpec{
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("10Gi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1500m"),
+			},
+		},
+		Config: map[string]string{
+			"innodb_buffer_pool_size":      "{{ percent 70 .Memory }}",
+			"innodb_buffer_pool_instances": "{{ max 1 (min 8 .CPUs) }}",
+			"max_connections":              " 500 ",
+			"loose-gtid-mode":              "OFF",
+			"innodb_log_file_size":         "{{ .Disk }}",
+			"sql_mode":                     "STRICT_TRANS_TABLES\nskip-grant-tables",
+		},
+	}
+
+	config, problems := RenderMysqldConfig(mysqld, nil)
+	wantConfig := "innodb_buffer_pool_instances = 2\n" +
+		"innodb_buffer_pool_size = 7516192768\n" +
+		"max_connections = 500\n"
+	if config != wantConfig {
+		t.Errorf("RenderMysqldConfig() config = %q; want %q", config, wantConfig)
+	}
+	var options []string
+	for _, problem := range problems {
+		option, _, _ := strings.Cut(problem, ":")
+		options = append(options, option)
+	}
+	if want := []string{"innodb_log_file_size", "loose-gtid-mode", "sql_mode"}; !reflect.DeepEqual(options, want) {
+		t.Errorf("RenderMysqldConfig() problems = %v; want problems for %v", problems, want)
+	}
+
+	if config, problems := RenderMysqldConfig(&planetscalev2.MysqldSpec{}, nil); config != "" || problems != nil {
+		t.Errorf("RenderMysqldConfig() = %q, %v; want nothing without config", config, problems)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"vitess.io/vitess/go/sqltypes"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/lazy"
+	"planetscale.dev/vitess-operator/pkg/operator/names"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	// MysqldExporterPasswordKey is the key in the mysqld-exporter Secret of a
+	// shard that holds the password of the monitoring user.
+	MysqldExporterPasswordKey = "password"
+	// MysqldExporterUserAnnotation is the annotation on the mysqld-exporter
+	// Secret in which the operator records a hash of the Secret contents it
+	// last created the monitoring user with.
+	MysqldExporterUserAnnotation = "planetscale.com/mysqld-exporter-user"
+
+	mysqldExporterMonitoringUser   = "vt_monitoring"
+	mysqldExporterPasswordEnvVar   = "MYSQLD_EXPORTER_PASSWORD"
+	mysqldExporterPasswordBytes    = 24
+	mysqldExporterMaxConnections   = 3
+	mysqldExporterMetricsPath      = "/metrics"
+	mysqldExporterScrapeAnnotation = "prometheus.io/scrape"
+	mysqldExporterPortAnnotation   = "prometheus.io/port"
+	mysqldExporterPathAnnotation   = "prometheus.io/path"
+)
+
+// The Prometheus Operator API isn't vendored, since its CRDs are optional, so
+// ServiceMonitors are built as unstructured objects.
+var ServiceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// prometheusLabelNameInvalidChars matches the characters that Prometheus
+// replaces with underscores when it turns Kubernetes labels into meta labels.
+var prometheusLabelNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func init() {
+	tabletAnnotations.Add(func(s lazy.Spec) map[string]string {
+		spec := s.(*Spec)
+		return spec.mysqldExporterScrapeAnnotations()
+	})
+}
+
+// MysqldExporterName returns the name of the Secret and the ServiceMonitor
+// for the MySQL exporters of a shard.
+func MysqldExporterName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), "mysqld-exporter")
+}
+
+// NewMysqldExporterPassword generates a password for the monitoring user of
+// a shard.
+func NewMysqldExporterPassword() (string, error) {
+	password := make([]byte, mysqldExporterPasswordBytes)
+	if _, err := rand.Read(password); err != nil {
+		return "", fmt.Errorf("failed to generate monitoring user password: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(password), nil
+}
+
+// NewMysqldExporterSecret creates a new Secret that holds the password of the
+// monitoring user of a shard. The password is never changed once the Secret
+// exists, so only the labels need to be updated.
+func NewMysqldExporterSecret(key client.ObjectKey, labels map[string]string, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			MysqldExporterPasswordKey: []byte(password),
+		},
+	}
+}
+
+// MysqldExporterUserQueries returns the statements that create or update the
+// monitoring user with the given password, and grant it only the privileges
+// that mysqld_exporter needs.
+func MysqldExporterUserQueries(password string) []string {
+	user := fmt.Sprintf("%v@'localhost'", sqltypes.EncodeStringSQL(mysqldExporterMonitoringUser))
+	identified := "IDENTIFIED BY " + sqltypes.EncodeStringSQL(password)
+	return []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %v %v WITH MAX_USER_CONNECTIONS %d", user, identified, mysqldExporterMaxConnections),
+		fmt.Sprintf("ALTER USER %v %v WITH MAX_USER_CONNECTIONS %d", user, identified, mysqldExporterMaxConnections),
+		fmt.Sprintf("GRANT PROCESS, REPLICATION CLIENT, SELECT ON *.* TO %v", user),
+	}
+}
+
+// mysqldExporterEnv returns the environment of the mysqld-exporter container.
+func (spec *Spec) mysqldExporterEnv() []corev1.EnvVar {
+	if spec.MysqldExporter == nil || !spec.MysqldExporter.MonitoringUser {
+		return []corev1.EnvVar{
+			{
+				Name:  "DATA_SOURCE_NAME",
+				Value: fmt.Sprintf("%s@unix(%s)/", mysqldExporterUser, mysqlSocketPath),
+			},
+		}
+	}
+
+	// Kubernetes expands the password into the data source name, so it never
+	// appears in the Pod spec.
+	return []corev1.EnvVar{
+		{
+			Name: mysqldExporterPasswordEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: MysqldExporterName(spec.Labels[planetscalev2.ClusterLabel], spec.KeyspaceName, spec.KeyRange),
+					},
+					Key: MysqldExporterPasswordKey,
+				},
+			},
+		},
+		{
+			Name:  "DATA_SOURCE_NAME",
+			Value: fmt.Sprintf("%s:$(%s)@unix(%s)/", mysqldExporterMonitoringUser, mysqldExporterPasswordEnvVar, mysqlSocketPath),
+		},
+	}
+}
+
+// mysqldExporterScrapeAnnotations returns the Prometheus scrape annotations
+// for the tablet Pod, if they're wanted and the Pod runs an exporter.
+func (spec *Spec) mysqldExporterScrapeAnnotations() map[string]string {
+	if spec.Mysqld == nil || spec.Images.MysqldExporter == "" || spec.MysqldExporter == nil || !spec.MysqldExporter.ScrapeAnnotations {
+		return nil
+	}
+	return map[string]string{
+		mysqldExporterScrapeAnnotation: "true",
+		mysqldExporterPortAnnotation:   strconv.Itoa(mysqldExporterPort),
+		mysqldExporterPathAnnotation:   mysqldExporterMetricsPath,
+	}
+}
+
+// ServiceMonitorEndpoint is the part of a ServiceMonitor that scrapes the
+// MySQL exporters of one tablet pool.
+type ServiceMonitorEndpoint struct {
+	// PodLabels are the labels that a tablet Pod must have to be scraped by
+	// this endpoint. A label with an empty value must be missing.
+	PodLabels map[string]string
+	// Interval is the scrape interval, or "" for the Prometheus default.
+	Interval string
+}
+
+// NewServiceMonitor creates a new, empty ServiceMonitor object.
+func NewServiceMonitor(key client.ObjectKey) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(ServiceMonitorGVK)
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+	return obj
+}
+
+// UpdateServiceMonitor updates the mutable parts of the ServiceMonitor for the
+// MySQL exporters of a shard. It scrapes the metrics port of the cluster's
+// vttablet Service, and each endpoint keeps only the tablets of one pool.
+func UpdateServiceMonitor(obj *unstructured.Unstructured, labels, serviceLabels map[string]string, endpoints []ServiceMonitorEndpoint) {
+	objLabels := obj.GetLabels()
+	update.Labels(&objLabels, labels)
+	obj.SetLabels(objLabels)
+
+	matchLabels := make(map[string]interface{}, len(serviceLabels))
+	for k, v := range serviceLabels {
+		matchLabels[k] = v
+	}
+	items := make([]interface{}, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		items = append(items, serviceMonitorEndpoint(endpoint))
+	}
+	obj.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+		"endpoints": items,
+	}
+}
+
+func serviceMonitorEndpoint(endpoint ServiceMonitorEndpoint) map[string]interface{} {
+	keys := make([]string, 0, len(endpoint.PodLabels))
+	for key := range endpoint.PodLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Prometheus sees a missing label as an empty one, so a single regex over
+	// all the labels can require that some are missing.
+	sourceLabels := make([]interface{}, 0, len(keys))
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		sourceLabels = append(sourceLabels, "__meta_kubernetes_pod_label_"+prometheusLabelNameInvalidChars.ReplaceAllString(key, "_"))
+		values = append(values, regexp.QuoteMeta(endpoint.PodLabels[key]))
+	}
+
+	result := map[string]interface{}{
+		"port": mysqldExporterPortName,
+		"path": mysqldExporterMetricsPath,
+		"relabelings": []interface{}{
+			map[string]interface{}{
+				"action":       "keep",
+				"sourceLabels": sourceLabels,
+				"separator":    ";",
+				"regex":        strings.Join(values, ";"),
+			},
+		},
+	}
+	if endpoint.Interval != "" {
+		result["interval"] = endpoint.Interval
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestMysqldExporterUserQueries(t *testing.T) {
+	queries := MysqldExporterUserQueries(`pa'ss\word`)
+	if len(queries) != 3 {
+		t.Fatalf("MysqldExporterUserQueries() returned %d queries; want 3", len(queries))
+	}
+	for _, query := range queries[:2] {
+		if !strings.Contains(query, `IDENTIFIED BY 'pa\'ss\\word'`) {
+			t.Errorf("query %q doesn't escape the password", query)
+		}
+	}
+	if want := "GRANT PROCESS, REPLICATION CLIENT, SELECT ON *.* TO 'vt_monitoring'@'localhost'"; queries[2] != want {
+		t.Errorf("grant = %q; want %q", queries[2], want)
+	}
+}
+
+func TestMysqldExporterEnv(t *testing.T) {
+	spec := &Spec{
+		Labels:         map[string]string{planetscalev2.ClusterLabel: "example"},
+		KeyspaceName:   "commerce",
+		KeyRange:       planetscalev2.VitessKeyRange{Start: "", End: "80"},
+		MysqldExporter: &planetscalev2.MysqldExporterSpec{},
+	}
+	env := spec.mysqldExporterEnv()
+	if len(env) != 1 || env[0].Value != "vt_dba@unix(/vt/socket/mysql.sock)/" {
+		t.Errorf("mysqldExporterEnv() without monitoring user = %v; want vt_dba data source", env)
+	}
+
+	spec.MysqldExporter.MonitoringUser = true
+	env = spec.mysqldExporterEnv()
+	if len(env) != 2 {
+		t.Fatalf("mysqldExporterEnv() with monitoring user = %v; want 2 variables", env)
+	}
+	ref := env[0].ValueFrom.SecretKeyRef
+	if ref == nil || ref.Name != MysqldExporterName("example", "commerce", spec.KeyRange) || ref.Key != MysqldExporterPasswordKey {
+		t.Errorf("password = %v; want a reference to the mysqld-exporter Secret", env[0])
+	}
+	if want := "vt_monitoring:$(MYSQLD_EXPORTER_PASSWORD)@unix(/vt/socket/mysql.sock)/"; env[1].Value != want {
+		t.Errorf("DATA_SOURCE_NAME = %q; want %q", env[1].Value, want)
+	}
+}
+
+func TestMysqldExporterScrapeAnnotations(t *testing.T) {
+	spec := &Spec{
+		Mysqld:         &planetscalev2.MysqldSpec{},
+		Images:         planetscalev2.VitessKeyspaceImages{MysqldExporter: "prom/mysqld-exporter"},
+		MysqldExporter: &planetscalev2.MysqldExporterSpec{ScrapeAnnotations: true},
+	}
+	want := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "9104",
+		"prometheus.io/path":   "/metrics",
+	}
+	if got := spec.mysqldExporterScrapeAnnotations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("mysqldExporterScrapeAnnotations() = %v; want %v", got, want)
+	}
+
+	// There's nothing to scrape without an exporter.
+	spec.Images.MysqldExporter = ""
+	if got := spec.mysqldExporterScrapeAnnotations(); got != nil {
+		t.Errorf("mysqldExporterScrapeAnnotations() without exporter image = %v; want nil", got)
+	}
+}
+
+func TestUpdateServiceMonitor(t *testing.T) {
+	obj := NewServiceMonitor(client.ObjectKey{Namespace: "default", Name: "example"})
+	UpdateServiceMonitor(obj, map[string]string{"release": "prometheus"}, map[string]string{"app": "vttablet"}, []ServiceMonitorEndpoint{
+		{
+			PodLabels: map[string]string{
+				planetscalev2.ShardLabel:          "x-80",
+				planetscalev2.TabletPoolNameLabel: "",
+			},
+			Interval: "30s",
+		},
+	})
+
+	if got := obj.GetLabels(); !reflect.DeepEqual(got, map[string]string{"release": "prometheus"}) {
+		t.Errorf("labels = %v", got)
+	}
+	endpoints := obj.Object["spec"].(map[string]interface{})["endpoints"].([]interface{})
+	if len(endpoints) != 1 {
+		t.Fatalf("endpoints = %v; want 1", endpoints)
+	}
+	endpoint := endpoints[0].(map[string]interface{})
+	if endpoint["port"] != "metrics" || endpoint["interval"] != "30s" {
+		t.Errorf("endpoint = %v; want port metrics every 30s", endpoint)
+	}
+	relabeling := endpoint["relabelings"].([]interface{})[0].(map[string]interface{})
+	wantSourceLabels := []interface{}{
+		"__meta_kubernetes_pod_label_planetscale_com_pool_name",
+		"__meta_kubernetes_pod_label_planetscale_com_shard",
+	}
+	if !reflect.DeepEqual(relabeling["sourceLabels"], wantSourceLabels) {
+		t.Errorf("sourceLabels = %v; want %v", relabeling["sourceLabels"], wantSourceLabels)
+	}
+
+	// Pods without a pool name match, but named pools don't.
+	regex := regexp.MustCompile("^(?:" + relabeling["regex"].(string) + ")$")
+	if !regex.MatchString(";x-80") {
+		t.Errorf("regex %q doesn't match an unnamed pool", regex)
+	}
+	if regex.MatchString("big;x-80") {
+		t.Errorf("regex %q matches a named pool", regex)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+// mysqldVersionPattern matches the version at the start of a mysqld image tag,
+// such as "8.0.34", "8.4", or Percona Server's "8.0.36-28" and "ps-8.0.36-28".
+var mysqldVersionPattern = regexp.MustCompile(`^(?:[A-Za-z]+-)*(\d+)\.(\d+)(?:\.(\d+))?(?:$|[-_.+])`)
+
+// MysqldVersion is a MySQL version according to a mysqld image tag.
+type MysqldVersion struct {
+	Major, Minor, Patch int
+	// HasPatch is false if the tag only names a version series, like "8.4".
+	HasPatch bool
+}
+
+// Series returns the version series, such as "8.0".
+func (v MysqldVersion) Series() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// String returns the version, or just the series if the patch is unknown.
+func (v MysqldVersion) String() string {
+	if !v.HasPatch {
+		return v.Series()
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ParseMysqldVersion returns the MySQL version that a mysqld image runs
+// according to its tag, and whether the tag says.
+func ParseMysqldVersion(image string) (MysqldVersion, bool) {
+	// Drop the digest, if any, so we're left with the tag.
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	// A colon before the last slash is a registry port, not a tag.
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return MysqldVersion{}, false
+	}
+	return parseMysqldVersionTag(image[i+1:])
+}
+
+// DesiredMysqldVersion returns the MySQL version of a mysqld image according
+// to its tag or, if the tag doesn't say, the version series that its flavor
+// field implies.
+func DesiredMysqldVersion(image *planetscalev2.MysqldImage) (MysqldVersion, bool) {
+	if image == nil {
+		return MysqldVersion{}, false
+	}
+	if version, ok := ParseMysqldVersion(image.Image()); ok {
+		return version, true
+	}
+	return parseMysqldVersionTag(image.VersionSeries())
+}
+
+func parseMysqldVersionTag(tag string) (MysqldVersion, bool) {
+	match := mysqldVersionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return MysqldVersion{}, false
+	}
+	// The pattern only matches digits, so these can't fail.
+	var version MysqldVersion
+	version.Major, _ = strconv.Atoi(match[1])
+	version.Minor, _ = strconv.Atoi(match[2])
+	if match[3] != "" {
+		version.Patch, _ = strconv.Atoi(match[3])
+		version.HasPatch = true
+	}
+	return version, true
+}
+
+// MysqldVersionSeries returns the MySQL version series, such as "8.0", that a
+// mysqld image runs according to its tag, or an empty string if the tag
+// doesn't say.
+func MysqldVersionSeries(image string) string {
+	version, ok := ParseMysqldVersion(image)
+	if !ok {
+		return ""
+	}
+	return version.Series()
+}
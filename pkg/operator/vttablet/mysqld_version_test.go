@@ -0,0 +1,62 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import "testing"
+
+func TestMysqldVersionSeries(t *testing.T) {
+	table := map[string]string{
+		"":                                     "",
+		"mysql":                                "",
+		"mysql:latest":                         "",
+		"mysql:5.7":                            "5.7",
+		"docker.io/vitess/mysql:8.0.23":        "8.0",
+		"percona/percona-server:8.4.0-1":       "8.4",
+		"registry:5000/vitess/mysql:8.0.34":    "8.0",
+		"registry:5000/vitess/mysql":           "",
+		"mysql:8.0.30@sha256:0123456789abcdef": "8.0",
+		"mysql:80":                             "",
+	}
+	for image, want := range table {
+		if got := MysqldVersionSeries(image); got != want {
+			t.Errorf("MysqldVersionSeries(%q) = %q; want %q", image, got, want)
+		}
+	}
+}
+
+func TestParseMysqldVersion(t *testing.T) {
+	table := map[string]MysqldVersion{
+		"mysql:8.0.34":                             {Major: 8, Minor: 0, Patch: 34, HasPatch: true},
+		"mysql:8.4":                                {Major: 8, Minor: 4},
+		"percona/percona-server:8.0.36-28":         {Major: 8, Minor: 0, Patch: 36, HasPatch: true},
+		"percona/percona-server:ps-8.0.36-28":      {Major: 8, Minor: 0, Patch: 36, HasPatch: true},
+		"percona/percona-server:8.4.0-1.1":         {Major: 8, Minor: 4, Patch: 0, HasPatch: true},
+		"registry:5000/vitess/mysql:8.0.30@sha256": {Major: 8, Minor: 0, Patch: 30, HasPatch: true},
+	}
+	for image, want := range table {
+		got, ok := ParseMysqldVersion(image)
+		if !ok || got != want {
+			t.Errorf("ParseMysqldVersion(%q) = %v, %v; want %v, true", image, got, ok, want)
+		}
+	}
+
+	for _, image := range []string{"mysql", "mysql:latest", "mysql:80", "registry:5000/vitess/mysql"} {
+		if got, ok := ParseMysqldVersion(image); ok {
+			t.Errorf("ParseMysqldVersion(%q) = %v, true; want false", image, got)
+		}
+	}
+}
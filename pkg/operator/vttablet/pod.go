@@ -17,7 +17,6 @@ limitations under the License.
 package vttablet
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 
@@ -42,6 +41,11 @@ func PodName(clusterName string, tabletAlias topodatapb.TabletAlias) string {
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, planetscalev2.VttabletComponentName, topoproto.TabletAliasString(&tabletAlias))
 }
 
+// PDBName returns the name of the PodDisruptionBudget for a given tablet pool.
+func PDBName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange, cell string, poolType planetscalev2.VitessTabletPoolType, poolName string) string {
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), cell, string(poolType), poolName, planetscalev2.VttabletComponentName)
+}
+
 // NewPod creates a new vttablet Pod from a Spec.
 func NewPod(key client.ObjectKey, spec *Spec) *corev1.Pod {
 	obj := &corev1.Pod{
@@ -112,7 +116,7 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 
 	// Build the containers.
 	vttabletContainer := &corev1.Container{
-		Name:            vttabletContainerName,
+		Name:            VttabletContainerName,
 		Image:           spec.Images.Vttablet,
 		ImagePullPolicy: spec.ImagePullPolicies.Vttablet,
 		Command:         []string{vttabletCommand},
@@ -163,6 +167,7 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 
 	var mysqldContainer *corev1.Container
 	var mysqldExporterContainer *corev1.Container
+	var queryLogsContainer *corev1.Container
 
 	if spec.Mysqld != nil {
 		mysqldContainer = &corev1.Container{
@@ -208,12 +213,7 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 				// memory usage.
 				"--collect.info_schema.tables.databases=sys,_vt",
 			},
-			Env: []corev1.EnvVar{
-				{
-					Name:  "DATA_SOURCE_NAME",
-					Value: fmt.Sprintf("%s@unix(%s)/", mysqldExporterUser, mysqlSocketPath),
-				},
-			},
+			Env: spec.mysqldExporterEnv(),
 			Ports: []corev1.ContainerPort{
 				{
 					Name:          mysqldExporterPortName,
@@ -242,6 +242,8 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 		if spec.MysqldExporter != nil {
 			update.ResourceRequirements(&mysqldExporterContainer.Resources, &spec.MysqldExporter.Resources)
 		}
+
+		queryLogsContainer = spec.queryLogsContainer(securityContext, mysqldMounts)
 	}
 
 	// Set the resource requirements on each of the default vttablet init
@@ -272,6 +274,10 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 		if mysqldExporterContainer.Image != "" {
 			containers = append(containers, *mysqldExporterContainer)
 		}
+
+		if queryLogsContainer != nil {
+			containers = append(containers, *queryLogsContainer)
+		}
 	}
 
 	// Record hashes of desired label and annotation keys to force the Pod
@@ -279,6 +285,7 @@ func UpdatePod(obj *corev1.Pod, spec *Spec) {
 	desiredStateHash := desiredstatehash.NewBuilder()
 	desiredStateHash.AddStringMapKeys("labels-keys", spec.ExtraLabels)
 	desiredStateHash.AddStringMapKeys("annotations-keys", spec.Annotations)
+	desiredStateHash.AddStringMapKeys("scrape-annotations-keys", spec.mysqldExporterScrapeAnnotations())
 
 	// Record a hash of desired containers to force the Pod to be recreated if
 	// something is removed from our desired state that we otherwise might
@@ -394,3 +401,46 @@ func AliasFromPod(pod *corev1.Pod) topodatapb.TabletAlias {
 		Uid:  uint32(uid),
 	}
 }
+
+// VttabletImage returns the image of the vttablet container in a vttablet Pod,
+// or an empty string if there's no such container.
+func VttabletImage(pod *corev1.Pod) string {
+	return containerImage(pod, VttabletContainerName)
+}
+
+// MysqldImage returns the image of the mysqld container in a vttablet Pod, or
+// an empty string if there's no such container.
+func MysqldImage(pod *corev1.Pod) string {
+	return containerImage(pod, MysqldContainerName)
+}
+
+// ImagesOutdated returns whether a vttablet Pod runs a different vttablet or
+// mysqld image than the given ones. The mysqld image is only compared for
+// Pods that run mysqld.
+func ImagesOutdated(pod *corev1.Pod, images *planetscalev2.VitessKeyspaceImages) bool {
+	if VttabletImage(pod) != images.Vttablet {
+		return true
+	}
+	mysqldImage := MysqldImage(pod)
+	return mysqldImage != "" && images.Mysqld != nil && mysqldImage != images.Mysqld.Image()
+}
+
+// ImageUpgradePending returns whether any of the given vttablet Pods runs a
+// different vttablet or mysqld image than the given ones.
+func ImageUpgradePending(pods map[string]*corev1.Pod, images *planetscalev2.VitessKeyspaceImages) bool {
+	for _, pod := range pods {
+		if ImagesOutdated(pod, images) {
+			return true
+		}
+	}
+	return false
+}
+
+func containerImage(pod *corev1.Pod, containerName string) string {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return pod.Spec.Containers[i].Image
+		}
+	}
+	return ""
+}
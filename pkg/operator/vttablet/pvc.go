@@ -42,11 +42,7 @@ func NewPVC(key client.ObjectKey, spec *Spec) *corev1.PersistentVolumeClaim {
 
 // UpdatePVCInPlace updates an existing vttablet PVC in-place.
 func UpdatePVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec) {
-	// Update labels, but ignore existing ones we don't set.
-	update.Labels(&obj.Labels, spec.Labels)
-	// update extra labels
-	// TODO: Handle the case when labels are removed from ExtraLabels
-	update.Labels(&obj.Labels, spec.ExtraLabels)
+	UpdatePVCLabelsInPlace(obj, spec)
 
 	// The only in-place spec update that's possible is volume expansion.
 	curSize := obj.Spec.Resources.Requests[corev1.ResourceStorage]
@@ -55,3 +51,13 @@ func UpdatePVCInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec) {
 		obj.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
 	}
 }
+
+// UpdatePVCLabelsInPlace updates only the labels of an existing PVC, leaving
+// its size alone.
+func UpdatePVCLabelsInPlace(obj *corev1.PersistentVolumeClaim, spec *Spec) {
+	// Update labels, but ignore existing ones we don't set.
+	update.Labels(&obj.Labels, spec.Labels)
+	// update extra labels
+	// TODO: Handle the case when labels are removed from ExtraLabels
+	update.Labels(&obj.Labels, spec.ExtraLabels)
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/update"
+)
+
+const (
+	queryLogsContainerName = "query-logs"
+
+	// The query logs live at the root of the data volume, rather than in the
+	// tablet dir, since MySQL won't create a missing directory for them.
+	slowQueryLogPath = vtDataRootPath + "/slow-query.log"
+	generalLogPath   = vtDataRootPath + "/general-query.log"
+
+	queryLogsRotateIntervalSeconds = 30
+	queryLogsReconnectSeconds      = 5
+	queryLogsCPURequestMillis      = 10
+	queryLogsMemoryRequestBytes    = 32 * (1 << 20) // 32 MiB
+)
+
+// queryLogsScript rotates the query logs when they get too big, and ships
+// each new line to the sink.
+//
+// MySQL opens its logs for appending, so it keeps writing to the same file
+// after the file is copied and truncated, without needing to be told to
+// reopen it. tail -F notices the truncation and starts over from the top.
+const queryLogsScript = `set -u
+trap 'exit 0' TERM INT
+
+rotate() {
+  local file=$1 size i
+  size=$(stat -c %s "$file" 2>/dev/null) || return 0
+  [ "$size" -gt "$QUERY_LOG_MAX_SIZE" ] || return 0
+  i=$QUERY_LOG_MAX_FILES
+  rm -f "$file.$i"
+  while [ "$i" -gt 1 ]; do
+    if [ -e "$file.$((i-1))" ]; then mv -f "$file.$((i-1))" "$file.$i"; fi
+    i=$((i-1))
+  done
+  cp "$file" "$file.1" && : > "$file"
+}
+
+ship() {
+  local name=$1 file=$2
+  case "$QUERY_LOG_SINK" in
+  stdout)
+    tail -n 0 -F "$file" 2>/dev/null | sed -u "s/^/[$name] /" &
+    ;;
+  fluentBit)
+    (
+      while true; do
+        tail -n 0 -F "$file" 2>/dev/null | sed -u "s/^/[$name] /" > "/dev/tcp/$FLUENT_BIT_HOST/$FLUENT_BIT_PORT"
+        sleep "$QUERY_LOG_RECONNECT_SECONDS"
+      done
+    ) &
+    ;;
+  esac
+}
+
+for entry in $QUERY_LOG_FILES; do
+  ship "${entry%%=*}" "${entry#*=}"
+done
+while true; do
+  for entry in $QUERY_LOG_FILES; do
+    rotate "${entry#*=}"
+  done
+  sleep "$QUERY_LOG_ROTATE_INTERVAL_SECONDS" &
+  wait $!
+done
+`
+
+// MysqldQueryLogConfig returns the mysqld options that turn on the query logs
+// of a MySQL instance, by normalized option name. These take precedence over
+// the same options in the structured mysqld config.
+func MysqldQueryLogConfig(mysqld *planetscalev2.MysqldSpec) map[string]string {
+	if mysqld == nil || mysqld.QueryLogs == nil {
+		return nil
+	}
+	queryLogs := mysqld.QueryLogs
+	if queryLogs.SlowQueryLog == nil && !queryLogs.GeneralLog {
+		return nil
+	}
+
+	// The sidecar can only find the logs if they're written to files.
+	config := map[string]string{
+		"log_output": "FILE",
+	}
+	if slowLog := queryLogs.SlowQueryLog; slowLog != nil {
+		config["slow_query_log"] = "ON"
+		config["slow_query_log_file"] = slowQueryLogPath
+		if slowLog.LongQueryTime != nil {
+			config["long_query_time"] = strconv.FormatFloat(slowLog.LongQueryTime.Duration.Seconds(), 'f', -1, 64)
+		}
+		if slowLog.LogQueriesNotUsingIndexes {
+			config["log_queries_not_using_indexes"] = "ON"
+		}
+	}
+	if queryLogs.GeneralLog {
+		config["general_log"] = "ON"
+		config["general_log_file"] = generalLogPath
+	}
+	return config
+}
+
+// queryLogsContainer returns the sidecar that rotates and ships the query logs
+// of the tablet's MySQL, or nil if there are no query logs.
+func (spec *Spec) queryLogsContainer(securityContext *corev1.SecurityContext, volumeMounts []corev1.VolumeMount) *corev1.Container {
+	if spec.Mysqld == nil || MysqldQueryLogConfig(spec.Mysqld) == nil {
+		return nil
+	}
+	queryLogs := spec.Mysqld.QueryLogs
+
+	var files []string
+	if queryLogs.SlowQueryLog != nil {
+		files = append(files, "slow="+slowQueryLogPath)
+	}
+	if queryLogs.GeneralLog {
+		files = append(files, "general="+generalLogPath)
+	}
+	// Defaults are filled in by the time we get here, but don't count on it.
+	maxSize := resource.MustParse("100Mi")
+	if queryLogs.MaxSize != nil {
+		maxSize = *queryLogs.MaxSize
+	}
+	maxFiles := int32(5)
+	if queryLogs.MaxFiles != nil {
+		maxFiles = *queryLogs.MaxFiles
+	}
+	sink := queryLogs.Sink
+	if sink == planetscalev2.FluentBitMysqldQueryLogSink && queryLogs.FluentBit == nil {
+		// There's nowhere to send the logs, and the shard reports it.
+		sink = planetscalev2.FileMysqldQueryLogSink
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "QUERY_LOG_FILES", Value: strings.Join(files, " ")},
+		{Name: "QUERY_LOG_MAX_SIZE", Value: strconv.FormatInt(maxSize.Value(), 10)},
+		{Name: "QUERY_LOG_MAX_FILES", Value: strconv.Itoa(int(maxFiles))},
+		{Name: "QUERY_LOG_SINK", Value: string(sink)},
+		{Name: "QUERY_LOG_ROTATE_INTERVAL_SECONDS", Value: strconv.Itoa(queryLogsRotateIntervalSeconds)},
+		{Name: "QUERY_LOG_RECONNECT_SECONDS", Value: strconv.Itoa(queryLogsReconnectSeconds)},
+	}
+	if sink == planetscalev2.FluentBitMysqldQueryLogSink {
+		env = append(env,
+			corev1.EnvVar{Name: "FLUENT_BIT_HOST", Value: queryLogs.FluentBit.Host},
+			corev1.EnvVar{Name: "FLUENT_BIT_PORT", Value: strconv.Itoa(int(queryLogs.FluentBit.Port))},
+		)
+	}
+
+	container := &corev1.Container{
+		Name:            queryLogsContainerName,
+		Image:           spec.Images.Vttablet,
+		ImagePullPolicy: spec.ImagePullPolicies.Vttablet,
+		Command:         []string{"bash", "-c"},
+		Args:            []string{queryLogsScript},
+		Env:             env,
+		SecurityContext: securityContext,
+		VolumeMounts:    volumeMounts,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(queryLogsCPURequestMillis, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(queryLogsMemoryRequestBytes, resource.BinarySI),
+			},
+		},
+	}
+	update.ResourceRequirements(&container.Resources, &queryLogs.Resources)
+	return container
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+func TestRenderMysqldConfigQueryLogs(t *testing.T) {
+	mysqld := &planetscalev2.MysqldSpec{
+		Config: map[string]string{
+			"long-query-time": "10",
+			"max_connections": "500",
+		},
+		QueryLogs: &planetscalev2.MysqldQueryLogs{
+			SlowQueryLog: &planetscalev2.MysqldSlowQueryLog{
+				LongQueryTime: &metav1.Duration{Duration: 500 * time.Millisecond},
+			},
+		},
+	}
+
+	config, problems := RenderMysqldConfig(mysqld, nil)
+	wantConfig := "log_output = FILE\n" +
+		"long_query_time = 0.5\n" +
+		"max_connections = 500\n" +
+		"slow_query_log = ON\n" +
+		"slow_query_log_file = /vt/vtdataroot/slow-query.log\n"
+	if config != wantConfig {
+		t.Errorf("RenderMysqldConfig() config = %q; want %q", config, wantConfig)
+	}
+	wantProblems := []string{"long-query-time: can't be set because queryLogs manages it"}
+	if !reflect.DeepEqual(problems, wantProblems) {
+		t.Errorf("RenderMysqldConfig() problems = %v; want %v", problems, wantProblems)
+	}
+
+	// Query logs that are all turned off don't touch the config.
+	mysqld = &planetscalev2.MysqldSpec{QueryLogs: &planetscalev2.MysqldQueryLogs{}}
+	if config, problems := RenderMysqldConfig(mysqld, nil); config != "" || problems != nil {
+		t.Errorf("RenderMysqldConfig() = %q, %v; want nothing without query logs", config, problems)
+	}
+}
+
+func TestQueryLogsContainer(t *testing.T) {
+	maxSize := resource.MustParse("1Mi")
+	spec := &Spec{
+		Images: planetscalev2.VitessKeyspaceImages{Vttablet: "vitess/lite"},
+		Mysqld: &planetscalev2.MysqldSpec{
+			QueryLogs: &planetscalev2.MysqldQueryLogs{
+				SlowQueryLog: &planetscalev2.MysqldSlowQueryLog{},
+				GeneralLog:   true,
+				MaxSize:      &maxSize,
+				Sink:         planetscalev2.FluentBitMysqldQueryLogSink,
+				FluentBit: &planetscalev2.MysqldQueryLogFluentBit{
+					Host: "fluent-bit.logging",
+					Port: 5170,
+				},
+			},
+		},
+	}
+
+	container := spec.queryLogsContainer(nil, nil)
+	if container == nil {
+		t.Fatalf("queryLogsContainer() = nil; want a container")
+	}
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	for name, want := range map[string]string{
+		"QUERY_LOG_FILES":     "slow=/vt/vtdataroot/slow-query.log general=/vt/vtdataroot/general-query.log",
+		"QUERY_LOG_MAX_SIZE":  "1048576",
+		"QUERY_LOG_MAX_FILES": "5",
+		"QUERY_LOG_SINK":      "fluentBit",
+		"FLUENT_BIT_HOST":     "fluent-bit.logging",
+		"FLUENT_BIT_PORT":     "5170",
+	} {
+		if env[name] != want {
+			t.Errorf("%v = %q; want %q", name, env[name], want)
+		}
+	}
+	if got := container.Resources.Requests[corev1.ResourceMemory]; got.Value() != queryLogsMemoryRequestBytes {
+		t.Errorf("memory request = %v; want default", got.String())
+	}
+
+	// Without a Fluent Bit address, the logs stay in files.
+	spec.Mysqld.QueryLogs.FluentBit = nil
+	container = spec.queryLogsContainer(nil, nil)
+	for _, envVar := range container.Env {
+		if envVar.Name == "QUERY_LOG_SINK" && envVar.Value != "file" {
+			t.Errorf("QUERY_LOG_SINK = %q; want file", envVar.Value)
+		}
+	}
+
+	spec.Mysqld.QueryLogs = &planetscalev2.MysqldQueryLogs{}
+	if container := spec.queryLogsContainer(nil, nil); container != nil {
+		t.Errorf("queryLogsContainer() = %v; want nil without query logs", container)
+	}
+}
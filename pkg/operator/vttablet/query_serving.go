@@ -0,0 +1,95 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"fmt"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/vitess"
+)
+
+// QueryServingFlags returns the vttablet flags for the given query serving
+// settings, which should already have defaults filled in. If there are no
+// settings, it returns the operator's fixed defaults.
+func QueryServingFlags(queryServing *planetscalev2.VttabletQueryServingSpec) vitess.Flags {
+	if queryServing == nil {
+		return vitess.Flags{
+			"queryserver-config-max-result-size":  queryserverConfigMaxResultSize,
+			"queryserver-config-query-timeout":    fmt.Sprintf("%ds", queryserverConfigQueryTimeout),
+			"queryserver-config-pool-size":        queryserverConfigPoolSize,
+			"queryserver-config-stream-pool-size": queryserverConfigStreamPoolSize,
+			"queryserver-config-transaction-cap":  queryserverConfigTransactionCap,
+		}
+	}
+
+	flags := vitess.Flags{}
+	if queryServing.MaxResultSize != nil {
+		flags["queryserver-config-max-result-size"] = *queryServing.MaxResultSize
+	}
+	if queryServing.QueryTimeout != nil {
+		flags["queryserver-config-query-timeout"] = queryServing.QueryTimeout.Duration.String()
+	}
+	if queryServing.TransactionTimeout != nil {
+		flags["queryserver-config-transaction-timeout"] = queryServing.TransactionTimeout.Duration.String()
+	}
+	if queryServing.PoolSize != nil {
+		flags["queryserver-config-pool-size"] = *queryServing.PoolSize
+	}
+	if queryServing.StreamPoolSize != nil {
+		flags["queryserver-config-stream-pool-size"] = *queryServing.StreamPoolSize
+	}
+	if queryServing.TransactionCap != nil {
+		flags["queryserver-config-transaction-cap"] = *queryServing.TransactionCap
+	}
+
+	// vttablet picks the consolidator mode from a pair of boolean flags.
+	switch queryServing.Consolidator {
+	case "enable":
+		flags["enable_consolidator"] = true
+		flags["enable_consolidator_replicas"] = false
+	case "disable":
+		flags["enable_consolidator"] = false
+		flags["enable_consolidator_replicas"] = false
+	case "notOnPrimary":
+		flags["enable_consolidator"] = false
+		flags["enable_consolidator_replicas"] = true
+	}
+
+	if hotRow := queryServing.HotRowProtection; hotRow != nil {
+		switch hotRow.Mode {
+		case "enable":
+			flags["enable_hot_row_protection"] = true
+		case "dryRun":
+			flags["enable_hot_row_protection"] = true
+			flags["enable_hot_row_protection_dry_run"] = true
+		case "disable":
+			flags["enable_hot_row_protection"] = false
+		}
+		if hotRow.MaxQueueSize != nil {
+			flags["hot_row_protection_max_queue_size"] = *hotRow.MaxQueueSize
+		}
+		if hotRow.MaxGlobalQueueSize != nil {
+			flags["hot_row_protection_max_global_queue_size"] = *hotRow.MaxGlobalQueueSize
+		}
+		if hotRow.MaxConcurrency != nil {
+			flags["hot_row_protection_concurrent_transactions"] = *hotRow.MaxConcurrency
+		}
+	}
+
+	return flags
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+)
+
+const (
+	// queryStatsTimeout is how long to wait for a vttablet to report its stats.
+	queryStatsTimeout = 5 * time.Second
+)
+
+var queryStatsClient = &http.Client{Timeout: queryStatsTimeout}
+
+// GetQPS asks the vttablet at the given host for how many queries per second
+// it's serving.
+func GetQPS(ctx context.Context, host string) (float64, error) {
+	if host == "" {
+		return 0, fmt.Errorf("vttablet has no host")
+	}
+	url := fmt.Sprintf("http://%v/debug/vars", net.JoinHostPort(host, strconv.Itoa(planetscalev2.DefaultWebPort)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := queryStatsClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stats of vttablet %v: %v", host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to get stats of vttablet %v: %v", host, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read stats of vttablet %v: %v", host, err)
+	}
+	qps, err := parseQPS(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse stats of vttablet %v: %v", host, err)
+	}
+	return qps, nil
+}
+
+// parseQPS reads the query rate out of the expvars that vttablet exports at
+// /debug/vars. QPS holds a series of recent rates for each kind of query,
+// oldest first, and the "All" series covers every kind.
+func parseQPS(data []byte) (float64, error) {
+	var vars struct {
+		QPS map[string][]float64
+	}
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return 0, err
+	}
+	rates := vars.QPS["All"]
+	if len(rates) == 0 {
+		return 0, nil
+	}
+	return rates[len(rates)-1], nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import "testing"
+
+func TestParseQPS(t *testing.T) {
+	data := []byte(`{
+		"Uptime": 120,
+		"QPS": {
+			"All": [10.5, 20, 42.25],
+			"Select": [10, 18, 40]
+		}
+	}`)
+	qps, err := parseQPS(data)
+	if err != nil {
+		t.Fatalf("parseQPS() error: %v", err)
+	}
+	if want := 42.25; qps != want {
+		t.Errorf("parseQPS() = %v; want %v", qps, want)
+	}
+
+	// A vttablet that hasn't served anything yet has no rates.
+	qps, err = parseQPS([]byte(`{"Uptime": 1}`))
+	if err != nil {
+		t.Fatalf("parseQPS() error: %v", err)
+	}
+	if qps != 0 {
+		t.Errorf("parseQPS() = %v; want 0", qps)
+	}
+}
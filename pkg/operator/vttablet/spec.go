@@ -18,6 +18,7 @@ package vttablet
 
 import (
 	"fmt"
+	"time"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 
@@ -42,16 +43,19 @@ type Spec struct {
 	DatabaseName              string
 	Vttablet                  *planetscalev2.VttabletSpec
 	Mysqld                    *planetscalev2.MysqldSpec
+	AutoTuneMysql             bool
 	MysqldExporter            *planetscalev2.MysqldExporterSpec
 	ExternalDatastore         *planetscalev2.ExternalDatastore
 	DataVolumePVCSpec         *corev1.PersistentVolumeClaimSpec
 	DataVolumePVCName         string
+	EphemeralDataVolume       bool
 	GlobalLockserver          planetscalev2.VitessLockserverParams
 	DatabaseInitScriptSecret  planetscalev2.SecretSource
 	Annotations               map[string]string
 	ExtraLabels               map[string]string
 	BackupLocation            *planetscalev2.VitessBackupLocation
 	BackupEngine              planetscalev2.VitessBackupEngine
+	BackupClusterName         string
 	Affinity                  *corev1.Affinity
 	ExtraEnv                  []corev1.EnvVar
 	ExtraVolumes              []corev1.Volume
@@ -60,6 +64,7 @@ type Spec struct {
 	SidecarContainers         []corev1.Container
 	Tolerations               []corev1.Toleration
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	ReplicationDelay          time.Duration
 }
 
 // localDatabaseName returns the MySQL database name for a tablet Spec in the case of locally managed MySQL.
@@ -104,7 +109,7 @@ func (spec *Spec) myCnfFilePath() string {
 func (spec *Spec) dbConfigCharset() string {
 	// For flavors that we know are 8.0-compatible, use the new default charset
 	// that Vitess switched to for 8.0+.
-	if spec.Images.Mysqld != nil && spec.Images.Mysqld.Mysql80Compatible != "" {
+	if spec.Images.Mysqld != nil && (spec.Images.Mysqld.Mysql80Compatible != "" || spec.Images.Mysqld.Mysql84Compatible != "") {
 		return defaultMySQL80Charset
 	}
 
@@ -30,6 +30,22 @@ func init() {
 		if spec.DataVolumePVCSpec == nil {
 			return nil
 		}
+		if spec.EphemeralDataVolume {
+			// The PVC is created and deleted along with the Pod, so it isn't
+			// labeled like the tablet PVCs we manage ourselves.
+			return []corev1.Volume{
+				{
+					Name: pvcVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Ephemeral: &corev1.EphemeralVolumeSource{
+							VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+								Spec: *spec.DataVolumePVCSpec,
+							},
+						},
+					},
+				},
+			}
+		}
 		return []corev1.Volume{
 			{
 				Name: pvcVolumeName,
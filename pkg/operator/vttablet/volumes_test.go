@@ -0,0 +1,51 @@
+/*
+Copyright 2024 PlanetScale Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vttablet
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestDataVolume(t *testing.T) {
+	dataVolume := func(spec *Spec) *corev1.Volume {
+		for _, volume := range tabletVolumes.Get(spec) {
+			if volume.Name == pvcVolumeName {
+				return &volume
+			}
+		}
+		return nil
+	}
+	pvcSpec := &corev1.PersistentVolumeClaimSpec{StorageClassName: pointer.String("local-nvme")}
+
+	volume := dataVolume(&Spec{DataVolumePVCSpec: pvcSpec, DataVolumePVCName: "tablet"})
+	if volume == nil || volume.PersistentVolumeClaim == nil || volume.PersistentVolumeClaim.ClaimName != "tablet" {
+		t.Errorf("data volume = %v; want PVC tablet", volume)
+	}
+
+	volume = dataVolume(&Spec{DataVolumePVCSpec: pvcSpec, EphemeralDataVolume: true})
+	if volume == nil || volume.Ephemeral == nil || *volume.Ephemeral.VolumeClaimTemplate.Spec.StorageClassName != "local-nvme" {
+		t.Errorf("data volume = %v; want an ephemeral volume on local-nvme", volume)
+	}
+
+	// Without a claim template, the data lives in the vt root emptyDir.
+	if volume := dataVolume(&Spec{EphemeralDataVolume: true}); volume != nil {
+		t.Errorf("data volume = %v; want none", volume)
+	}
+}
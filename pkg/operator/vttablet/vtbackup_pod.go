@@ -18,6 +18,7 @@ package vttablet
 
 import (
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,8 +27,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	planetscalev2 "planetscale.dev/vitess-operator/pkg/apis/planetscale/v2"
+	"planetscale.dev/vitess-operator/pkg/operator/backupreplicator"
+	"planetscale.dev/vitess-operator/pkg/operator/backupverifier"
+	"planetscale.dev/vitess-operator/pkg/operator/fork"
 	"planetscale.dev/vitess-operator/pkg/operator/names"
 	"planetscale.dev/vitess-operator/pkg/operator/update"
+	"planetscale.dev/vitess-operator/pkg/operator/vitessbackup"
 )
 
 const (
@@ -47,6 +52,10 @@ echo -e "sync_binlog=0\ninnodb_flush_log_at_trx_commit=0" > /mnt/vt/config/mycnf
 mkdir -p /mnt/vt/certs
 cp --no-clobber /etc/ssl/certs/ca-certificates.crt /mnt/vt/certs/
 echo "socket = ` + mysqlSocketPath + `" > /mnt/vt/config/mycnf/socket.cnf
+`
+
+	backupVerifierInitScript = `set -ex
+cp --no-clobber ` + backupverifier.BinaryPath + ` /mnt/vt/bin/
 `
 )
 
@@ -72,6 +81,50 @@ type BackupSpec struct {
 	// Even if a backup is past the MinRetentionTime, it will not be deleted if
 	// doing so would take the total number of backups below MinRetentionCount.
 	MinRetentionCount int
+
+	// Resources, if set, override the mysqld resources in TabletSpec for the
+	// vtbackup container.
+	Resources *corev1.ResourceRequirements
+	// NodeSelector restricts the backup Pod to nodes with matching labels.
+	NodeSelector map[string]string
+	// PriorityClassName is the PriorityClass of the backup Pod.
+	PriorityClassName string
+
+	// Verification, if set, means don't take a backup. Instead, restore an
+	// existing one to check that it's usable.
+	Verification *BackupVerification
+	// Replication, if set, means don't take a backup. Instead, copy an
+	// existing one to another location.
+	Replication *BackupReplication
+}
+
+// BackupVerification is the part of a BackupSpec for a Pod that verifies an
+// existing backup instead of taking a new one.
+type BackupVerification struct {
+	// OperatorImage is the operator's own image, from which the Pod copies the
+	// operator binary to run the backup verifier.
+	OperatorImage string
+	// BackupName is the name of the VitessBackup object to verify.
+	BackupName string
+	// BackupTime is the start time of the backup to verify.
+	BackupTime time.Time
+	// ChecksumTables are the tables to run CHECKSUM TABLE on after restoring.
+	ChecksumTables []string
+}
+
+// BackupReplication is the part of a BackupSpec for a Pod that copies an
+// existing backup to another location instead of taking a new one.
+type BackupReplication struct {
+	// OperatorImage is the operator's own image, which runs the backup
+	// replicator.
+	OperatorImage string
+	// StorageDirectory is the directory of the backup in storage.
+	StorageDirectory string
+	// StorageName is the name of the backup in storage.
+	StorageName string
+	// DestinationLocation is the location to copy the backup to. The source
+	// location is the BackupLocation of the TabletSpec.
+	DestinationLocation *planetscalev2.VitessBackupLocation
 }
 
 // BackupPodName returns the name of the Pod for a periodic vtbackup job.
@@ -85,6 +138,39 @@ func BackupPodName(clusterName, keyspaceName string, keyRange planetscalev2.Vite
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), planetscalev2.VtbackupComponentName, backupLocationName, timestamp)
 }
 
+// RequestedBackupPodName returns the name of the vtbackup Pod that takes an
+// on-demand backup. The request ID is chosen by the user, so it's only
+// included as a hash, which is always valid in an object name.
+func RequestedBackupPodName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange, backupLocationName, requestID string) string {
+	requestHash := names.Hash([]string{requestID})
+	if backupLocationName == "" {
+		return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), planetscalev2.VtbackupComponentName, vitessbackup.TypeRequest, requestHash)
+	}
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), planetscalev2.VtbackupComponentName, backupLocationName, vitessbackup.TypeRequest, requestHash)
+}
+
+// BackupVerificationPodName returns the name of the Pod that verifies a backup.
+// The Pod name incorporates the time since which the backup has been due for
+// verification, so a new Pod is created each time the backup is verified.
+func BackupVerificationPodName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange, backupLocationName string, dueSince time.Time) string {
+	timestamp := strconv.FormatInt(dueSince.Unix(), 16)
+	if backupLocationName == "" {
+		return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), vitessbackup.TypeVerify, timestamp)
+	}
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), vitessbackup.TypeVerify, backupLocationName, timestamp)
+}
+
+// BackupReplicationPodName returns the name of the Pod that copies a backup to
+// another location. The Pod name incorporates the time of the backup, so a new
+// Pod is created for each backup that needs to be copied.
+func BackupReplicationPodName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange, destinationLocationName string, backupTime time.Time) string {
+	timestamp := strconv.FormatInt(backupTime.Unix(), 16)
+	if destinationLocationName == "" {
+		return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), vitessbackup.TypeReplicate, timestamp)
+	}
+	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), vitessbackup.TypeReplicate, destinationLocationName, timestamp)
+}
+
 // InitialBackupPodName returns the name of the Pod for an initial vtbackup job.
 func InitialBackupPodName(clusterName, keyspaceName string, keyRange planetscalev2.VitessKeyRange) string {
 	return names.JoinWithConstraints(names.DefaultConstraints, clusterName, keyspaceName, keyRange.SafeName(), planetscalev2.VtbackupComponentName, "init")
@@ -130,7 +216,11 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 
 	var containerResources corev1.ResourceRequirements
 	// Make a copy of Resources since it contains pointers.
-	update.ResourceRequirements(&containerResources, &tabletSpec.Mysqld.Resources)
+	if backupSpec.Resources != nil {
+		update.ResourceRequirements(&containerResources, backupSpec.Resources)
+	} else {
+		update.ResourceRequirements(&containerResources, &tabletSpec.Mysqld.Resources)
+	}
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -140,12 +230,14 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 			Annotations: tabletAnnotations.Get(tabletSpec),
 		},
 		Spec: corev1.PodSpec{
-			ImagePullSecrets: tabletSpec.ImagePullSecrets,
-			RestartPolicy:    corev1.RestartPolicyOnFailure,
-			Volumes:          tabletVolumes.Get(tabletSpec),
-			SecurityContext:  podSecurityContext,
-			Affinity:         tabletSpec.Affinity,
-			Tolerations:      tabletSpec.Tolerations,
+			ImagePullSecrets:  tabletSpec.ImagePullSecrets,
+			RestartPolicy:     corev1.RestartPolicyOnFailure,
+			Volumes:           tabletVolumes.Get(tabletSpec),
+			SecurityContext:   podSecurityContext,
+			Affinity:          tabletSpec.Affinity,
+			Tolerations:       tabletSpec.Tolerations,
+			NodeSelector:      backupSpec.NodeSelector,
+			PriorityClassName: backupSpec.PriorityClassName,
 			InitContainers: []corev1.Container{
 				{
 					Name:            "init-vt-root",
@@ -185,7 +277,122 @@ func NewBackupPod(key client.ObjectKey, backupSpec *BackupSpec) *corev1.Pod {
 		pod.Spec.ServiceAccountName = planetscalev2.DefaultVitessServiceAccount
 	}
 
+	if backupSpec.Verification != nil {
+		updateBackupVerificationPod(pod, backupSpec)
+	}
+	if backupSpec.Replication != nil {
+		updateBackupReplicationPod(pod, backupSpec)
+	}
+
 	update.PodContainers(&pod.Spec.InitContainers, backupSpec.TabletSpec.InitContainers)
 	update.PodContainers(&pod.Spec.Containers, backupSpec.TabletSpec.SidecarContainers)
 	return pod
 }
+
+// updateBackupVerificationPod turns a vtbackup Pod into one that runs the
+// backup verifier instead. The verifier is part of the operator binary, which
+// we copy into the mysqld container the same way we do for vtbackup.
+func updateBackupVerificationPod(pod *corev1.Pod, backupSpec *BackupSpec) {
+	tabletSpec := backupSpec.TabletSpec
+	verification := backupSpec.Verification
+
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[vitessbackup.VerifiedBackupAnnotation] = verification.BackupName
+
+	// The outcome is reported through the Pod phase, so don't retry.
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	initVtRoot := &pod.Spec.InitContainers[0]
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+		Name:            "init-backup-verifier",
+		SecurityContext: initVtRoot.SecurityContext,
+		Image:           verification.OperatorImage,
+		VolumeMounts:    initVtRoot.VolumeMounts,
+		Command:         []string{"bash", "-c"},
+		Args:            []string{backupVerifierInitScript},
+	})
+
+	container := &pod.Spec.Containers[0]
+	container.Name = backupVerifierContainerName
+	container.Command = []string{backupVerifierCommand}
+	container.Args = vitessbackup.StorageFlags(tabletSpec.BackupLocation, tabletSpec.backupClusterName()).FormatArgs()
+	env := fork.EnvVars(backupverifier.ForkPath)
+	env = append(env,
+		corev1.EnvVar{Name: backupverifier.KeyspaceEnvVar, Value: tabletSpec.KeyspaceName},
+		corev1.EnvVar{Name: backupverifier.ShardEnvVar, Value: tabletSpec.KeyRange.String()},
+		corev1.EnvVar{Name: backupverifier.DatabaseNameEnvVar, Value: tabletSpec.localDatabaseName()},
+		corev1.EnvVar{Name: backupverifier.MysqlSocketEnvVar, Value: mysqlSocketPath},
+		corev1.EnvVar{Name: backupverifier.BackupTimeEnvVar, Value: verification.BackupTime.UTC().Format(time.RFC3339)},
+		corev1.EnvVar{Name: backupverifier.ChecksumTablesEnvVar, Value: strings.Join(verification.ChecksumTables, ",")},
+	)
+	update.Env(&container.Env, env)
+}
+
+// updateBackupReplicationPod turns a vtbackup Pod into one that copies a backup
+// to another location instead. Vitess can only talk to one backup location per
+// process, so an init container downloads the backup into the data volume, and
+// then the main container uploads it to the destination. Both run the backup
+// replicator in the operator image, since neither needs mysqld.
+func updateBackupReplicationPod(pod *corev1.Pod, backupSpec *BackupSpec) {
+	tabletSpec := backupSpec.TabletSpec
+	replication := backupSpec.Replication
+	clusterName := tabletSpec.backupClusterName()
+
+	// The outcome is reported through the Pod phase, so don't retry.
+	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	// Rename the volumes for the destination location, in case it uses the
+	// same kind of storage as the source.
+	destVolumes := vitessbackup.StorageVolumes(replication.DestinationLocation)
+	for i := range destVolumes {
+		destVolumes[i].Name = backupReplicationVolumePrefix + destVolumes[i].Name
+	}
+	destVolumeMounts := vitessbackup.StorageVolumeMounts(replication.DestinationLocation)
+	for i := range destVolumeMounts {
+		destVolumeMounts[i].Name = backupReplicationVolumePrefix + destVolumeMounts[i].Name
+	}
+	update.Volumes(&pod.Spec.Volumes, destVolumes)
+
+	env := fork.EnvVars(backupreplicator.ForkPath)
+	env = append(env,
+		corev1.EnvVar{Name: backupreplicator.BackupDirEnvVar, Value: replication.StorageDirectory},
+		corev1.EnvVar{Name: backupreplicator.BackupNameEnvVar, Value: replication.StorageName},
+		corev1.EnvVar{Name: backupreplicator.ScratchDirEnvVar, Value: backupReplicationScratchDir},
+	)
+	downloadEnv := append([]corev1.EnvVar{{Name: backupreplicator.ModeEnvVar, Value: backupreplicator.ModeDownload}}, env...)
+	update.Env(&downloadEnv, vitessbackup.StorageEnvVars(tabletSpec.BackupLocation))
+	uploadEnv := append([]corev1.EnvVar{{Name: backupreplicator.ModeEnvVar, Value: backupreplicator.ModeUpload}}, env...)
+	update.Env(&uploadEnv, vitessbackup.StorageEnvVars(replication.DestinationLocation))
+
+	// The data volume is mounted in both containers to hold the files.
+	downloadVolumeMounts := append(tabletVolumeMounts.Get(tabletSpec), vitessbackup.StorageVolumeMounts(tabletSpec.BackupLocation)...)
+	uploadVolumeMounts := append(tabletVolumeMounts.Get(tabletSpec), destVolumeMounts...)
+
+	vtbackup := &pod.Spec.Containers[0]
+	pod.Spec.InitContainers = []corev1.Container{
+		{
+			Name:            backupDownloaderContainerName,
+			Image:           replication.OperatorImage,
+			Command:         []string{backupverifier.BinaryPath},
+			Args:            vitessbackup.StorageFlags(tabletSpec.BackupLocation, clusterName).FormatArgs(),
+			Resources:       vtbackup.Resources,
+			SecurityContext: vtbackup.SecurityContext,
+			Env:             downloadEnv,
+			VolumeMounts:    downloadVolumeMounts,
+		},
+	}
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name:            backupUploaderContainerName,
+			Image:           replication.OperatorImage,
+			Command:         []string{backupverifier.BinaryPath},
+			Args:            vitessbackup.StorageFlags(replication.DestinationLocation, clusterName).FormatArgs(),
+			Resources:       vtbackup.Resources,
+			SecurityContext: vtbackup.SecurityContext,
+			Env:             uploadEnv,
+			VolumeMounts:    uploadVolumeMounts,
+		},
+	}
+}